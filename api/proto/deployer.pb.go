@@ -0,0 +1,1074 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: deployer.proto
+
+package deployerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Deployment represents a deployment
+type Deployment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	AppName       string                 `protobuf:"bytes,3,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Cloud         string                 `protobuf:"bytes,6,opt,name=cloud,proto3" json:"cloud,omitempty"`
+	Region        string                 `protobuf:"bytes,7,opt,name=region,proto3" json:"region,omitempty"`
+	ExternalIp    string                 `protobuf:"bytes,8,opt,name=external_ip,json=externalIp,proto3" json:"external_ip,omitempty"`
+	ExternalUrl   string                 `protobuf:"bytes,9,opt,name=external_url,json=externalUrl,proto3" json:"external_url,omitempty"`
+	Error         string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeployedAt    *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=deployed_at,json=deployedAt,proto3" json:"deployed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Deployment) Reset() {
+	*x = Deployment{}
+	mi := &file_deployer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Deployment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deployment) ProtoMessage() {}
+
+func (x *Deployment) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deployment.ProtoReflect.Descriptor instead.
+func (*Deployment) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Deployment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Deployment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Deployment) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *Deployment) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Deployment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Deployment) GetCloud() string {
+	if x != nil {
+		return x.Cloud
+	}
+	return ""
+}
+
+func (x *Deployment) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Deployment) GetExternalIp() string {
+	if x != nil {
+		return x.ExternalIp
+	}
+	return ""
+}
+
+func (x *Deployment) GetExternalUrl() string {
+	if x != nil {
+		return x.ExternalUrl
+	}
+	return ""
+}
+
+func (x *Deployment) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Deployment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Deployment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Deployment) GetDeployedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeployedAt
+	}
+	return nil
+}
+
+// Addon is an optional managed service provisioned with the cluster
+type Addon struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // cloudsql, memorystore
+	Config        *structpb.Struct       `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Addon) Reset() {
+	*x = Addon{}
+	mi := &file_deployer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Addon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Addon) ProtoMessage() {}
+
+func (x *Addon) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Addon.ProtoReflect.Descriptor instead.
+func (*Addon) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Addon) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Addon) GetConfig() *structpb.Struct {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type CreateDeploymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AppName       string                 `protobuf:"bytes,2,opt,name=app_name,json=appName,proto3" json:"app_name,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Cloud         string                 `protobuf:"bytes,4,opt,name=cloud,proto3" json:"cloud,omitempty"`                       // Default: gcp
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`                     // Default: us-central1
+	ImageTag      string                 `protobuf:"bytes,6,opt,name=image_tag,json=imageTag,proto3" json:"image_tag,omitempty"` // Optional: if provided, triggers immediate provisioning
+	Port          int32                  `protobuf:"varint,7,opt,name=port,proto3" json:"port,omitempty"`                        // Default: 8080
+	Addons        []*Addon               `protobuf:"bytes,8,rep,name=addons,proto3" json:"addons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDeploymentRequest) Reset() {
+	*x = CreateDeploymentRequest{}
+	mi := &file_deployer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDeploymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDeploymentRequest) ProtoMessage() {}
+
+func (x *CreateDeploymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDeploymentRequest.ProtoReflect.Descriptor instead.
+func (*CreateDeploymentRequest) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateDeploymentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDeploymentRequest) GetAppName() string {
+	if x != nil {
+		return x.AppName
+	}
+	return ""
+}
+
+func (x *CreateDeploymentRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *CreateDeploymentRequest) GetCloud() string {
+	if x != nil {
+		return x.Cloud
+	}
+	return ""
+}
+
+func (x *CreateDeploymentRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CreateDeploymentRequest) GetImageTag() string {
+	if x != nil {
+		return x.ImageTag
+	}
+	return ""
+}
+
+func (x *CreateDeploymentRequest) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *CreateDeploymentRequest) GetAddons() []*Addon {
+	if x != nil {
+		return x.Addons
+	}
+	return nil
+}
+
+type GetDeploymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeploymentRequest) Reset() {
+	*x = GetDeploymentRequest{}
+	mi := &file_deployer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeploymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeploymentRequest) ProtoMessage() {}
+
+func (x *GetDeploymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeploymentRequest.ProtoReflect.Descriptor instead.
+func (*GetDeploymentRequest) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetDeploymentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListDeploymentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // Default: 20
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Cloud         string                 `protobuf:"bytes,4,opt,name=cloud,proto3" json:"cloud,omitempty"`
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeploymentsRequest) Reset() {
+	*x = ListDeploymentsRequest{}
+	mi := &file_deployer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeploymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeploymentsRequest) ProtoMessage() {}
+
+func (x *ListDeploymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeploymentsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeploymentsRequest) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListDeploymentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDeploymentsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListDeploymentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListDeploymentsRequest) GetCloud() string {
+	if x != nil {
+		return x.Cloud
+	}
+	return ""
+}
+
+func (x *ListDeploymentsRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type ListDeploymentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deployments   []*Deployment          `protobuf:"bytes,1,rep,name=deployments,proto3" json:"deployments,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeploymentsResponse) Reset() {
+	*x = ListDeploymentsResponse{}
+	mi := &file_deployer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeploymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeploymentsResponse) ProtoMessage() {}
+
+func (x *ListDeploymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeploymentsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeploymentsResponse) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListDeploymentsResponse) GetDeployments() []*Deployment {
+	if x != nil {
+		return x.Deployments
+	}
+	return nil
+}
+
+func (x *ListDeploymentsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListDeploymentsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDeploymentsResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type DeleteDeploymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeploymentRequest) Reset() {
+	*x = DeleteDeploymentRequest{}
+	mi := &file_deployer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeploymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeploymentRequest) ProtoMessage() {}
+
+func (x *DeleteDeploymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeploymentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDeploymentRequest) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteDeploymentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteDeploymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeploymentResponse) Reset() {
+	*x = DeleteDeploymentResponse{}
+	mi := &file_deployer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeploymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeploymentResponse) ProtoMessage() {}
+
+func (x *DeleteDeploymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeploymentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDeploymentResponse) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteDeploymentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type StartDeploymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ImageTag      string                 `protobuf:"bytes,2,opt,name=image_tag,json=imageTag,proto3" json:"image_tag,omitempty"` // Required: container image to deploy
+	Port          int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`                        // Default: 8080
+	Replicas      int32                  `protobuf:"varint,4,opt,name=replicas,proto3" json:"replicas,omitempty"`                // Default: 2
+	Addons        []*Addon               `protobuf:"bytes,5,rep,name=addons,proto3" json:"addons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartDeploymentRequest) Reset() {
+	*x = StartDeploymentRequest{}
+	mi := &file_deployer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartDeploymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartDeploymentRequest) ProtoMessage() {}
+
+func (x *StartDeploymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartDeploymentRequest.ProtoReflect.Descriptor instead.
+func (*StartDeploymentRequest) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartDeploymentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StartDeploymentRequest) GetImageTag() string {
+	if x != nil {
+		return x.ImageTag
+	}
+	return ""
+}
+
+func (x *StartDeploymentRequest) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *StartDeploymentRequest) GetReplicas() int32 {
+	if x != nil {
+		return x.Replicas
+	}
+	return 0
+}
+
+func (x *StartDeploymentRequest) GetAddons() []*Addon {
+	if x != nil {
+		return x.Addons
+	}
+	return nil
+}
+
+type TriggerRollbackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TargetVersion string                 `protobuf:"bytes,2,opt,name=target_version,json=targetVersion,proto3" json:"target_version,omitempty"` // Required: version to rollback to
+	TargetTag     string                 `protobuf:"bytes,3,opt,name=target_tag,json=targetTag,proto3" json:"target_tag,omitempty"`             // Optional: specific image tag
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerRollbackRequest) Reset() {
+	*x = TriggerRollbackRequest{}
+	mi := &file_deployer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerRollbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerRollbackRequest) ProtoMessage() {}
+
+func (x *TriggerRollbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerRollbackRequest.ProtoReflect.Descriptor instead.
+func (*TriggerRollbackRequest) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TriggerRollbackRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TriggerRollbackRequest) GetTargetVersion() string {
+	if x != nil {
+		return x.TargetVersion
+	}
+	return ""
+}
+
+func (x *TriggerRollbackRequest) GetTargetTag() string {
+	if x != nil {
+		return x.TargetTag
+	}
+	return ""
+}
+
+// OrchestrationResponse is returned by asynchronous operations
+type OrchestrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeploymentId  string                 `protobuf:"bytes,1,opt,name=deployment_id,json=deploymentId,proto3" json:"deployment_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrchestrationResponse) Reset() {
+	*x = OrchestrationResponse{}
+	mi := &file_deployer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrchestrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrchestrationResponse) ProtoMessage() {}
+
+func (x *OrchestrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrchestrationResponse.ProtoReflect.Descriptor instead.
+func (*OrchestrationResponse) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OrchestrationResponse) GetDeploymentId() string {
+	if x != nil {
+		return x.DeploymentId
+	}
+	return ""
+}
+
+func (x *OrchestrationResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrchestrationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type StreamLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Follow        bool                   `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"` // Keep streaming until the deployment settles
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamLogsRequest) Reset() {
+	*x = StreamLogsRequest{}
+	mi := &file_deployer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLogsRequest) ProtoMessage() {}
+
+func (x *StreamLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLogsRequest.ProtoReflect.Descriptor instead.
+func (*StreamLogsRequest) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StreamLogsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StreamLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+// DeploymentLogEntry is a single line of deployment output
+type DeploymentLogEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // status, provision, build
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeploymentLogEntry) Reset() {
+	*x = DeploymentLogEntry{}
+	mi := &file_deployer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeploymentLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeploymentLogEntry) ProtoMessage() {}
+
+func (x *DeploymentLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_deployer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeploymentLogEntry.ProtoReflect.Descriptor instead.
+func (*DeploymentLogEntry) Descriptor() ([]byte, []int) {
+	return file_deployer_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeploymentLogEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *DeploymentLogEntry) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *DeploymentLogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_deployer_proto protoreflect.FileDescriptor
+
+const file_deployer_proto_rawDesc = "" +
+	"\n" +
+	"\x0edeployer.proto\x12\vdeployer.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb8\x03\n" +
+	"\n" +
+	"Deployment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bapp_name\x18\x03 \x01(\tR\aappName\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x14\n" +
+	"\x05cloud\x18\x06 \x01(\tR\x05cloud\x12\x16\n" +
+	"\x06region\x18\a \x01(\tR\x06region\x12\x1f\n" +
+	"\vexternal_ip\x18\b \x01(\tR\n" +
+	"externalIp\x12!\n" +
+	"\fexternal_url\x18\t \x01(\tR\vexternalUrl\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\vdeployed_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"deployedAt\"L\n" +
+	"\x05Addon\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12/\n" +
+	"\x06config\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x06config\"\xed\x01\n" +
+	"\x17CreateDeploymentRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\bapp_name\x18\x02 \x01(\tR\aappName\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x14\n" +
+	"\x05cloud\x18\x04 \x01(\tR\x05cloud\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1b\n" +
+	"\timage_tag\x18\x06 \x01(\tR\bimageTag\x12\x12\n" +
+	"\x04port\x18\a \x01(\x05R\x04port\x12*\n" +
+	"\x06addons\x18\b \x03(\v2\x12.deployer.v1.AddonR\x06addons\"&\n" +
+	"\x14GetDeploymentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8c\x01\n" +
+	"\x16ListDeploymentsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05cloud\x18\x04 \x01(\tR\x05cloud\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\"\x98\x01\n" +
+	"\x17ListDeploymentsResponse\x129\n" +
+	"\vdeployments\x18\x01 \x03(\v2\x17.deployer.v1.DeploymentR\vdeployments\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\")\n" +
+	"\x17DeleteDeploymentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
+	"\x18DeleteDeploymentResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xa1\x01\n" +
+	"\x16StartDeploymentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\timage_tag\x18\x02 \x01(\tR\bimageTag\x12\x12\n" +
+	"\x04port\x18\x03 \x01(\x05R\x04port\x12\x1a\n" +
+	"\breplicas\x18\x04 \x01(\x05R\breplicas\x12*\n" +
+	"\x06addons\x18\x05 \x03(\v2\x12.deployer.v1.AddonR\x06addons\"n\n" +
+	"\x16TriggerRollbackRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0etarget_version\x18\x02 \x01(\tR\rtargetVersion\x12\x1d\n" +
+	"\n" +
+	"target_tag\x18\x03 \x01(\tR\ttargetTag\"n\n" +
+	"\x15OrchestrationResponse\x12#\n" +
+	"\rdeployment_id\x18\x01 \x01(\tR\fdeploymentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\";\n" +
+	"\x11StreamLogsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06follow\x18\x02 \x01(\bR\x06follow\"\x80\x01\n" +
+	"\x12DeploymentLogEntry\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage2\xf9\x04\n" +
+	"\x0fDeployerService\x12Q\n" +
+	"\x10CreateDeployment\x12$.deployer.v1.CreateDeploymentRequest\x1a\x17.deployer.v1.Deployment\x12K\n" +
+	"\rGetDeployment\x12!.deployer.v1.GetDeploymentRequest\x1a\x17.deployer.v1.Deployment\x12\\\n" +
+	"\x0fListDeployments\x12#.deployer.v1.ListDeploymentsRequest\x1a$.deployer.v1.ListDeploymentsResponse\x12_\n" +
+	"\x10DeleteDeployment\x12$.deployer.v1.DeleteDeploymentRequest\x1a%.deployer.v1.DeleteDeploymentResponse\x12Z\n" +
+	"\x0fStartDeployment\x12#.deployer.v1.StartDeploymentRequest\x1a\".deployer.v1.OrchestrationResponse\x12Z\n" +
+	"\x0fTriggerRollback\x12#.deployer.v1.TriggerRollbackRequest\x1a\".deployer.v1.OrchestrationResponse\x12O\n" +
+	"\n" +
+	"StreamLogs\x12\x1e.deployer.v1.StreamLogsRequest\x1a\x1f.deployer.v1.DeploymentLogEntry0\x01B;Z9github.com/alvesdmateus/app-deployer/api/proto;deployerpbb\x06proto3"
+
+var (
+	file_deployer_proto_rawDescOnce sync.Once
+	file_deployer_proto_rawDescData []byte
+)
+
+func file_deployer_proto_rawDescGZIP() []byte {
+	file_deployer_proto_rawDescOnce.Do(func() {
+		file_deployer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_deployer_proto_rawDesc), len(file_deployer_proto_rawDesc)))
+	})
+	return file_deployer_proto_rawDescData
+}
+
+var file_deployer_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_deployer_proto_goTypes = []any{
+	(*Deployment)(nil),               // 0: deployer.v1.Deployment
+	(*Addon)(nil),                    // 1: deployer.v1.Addon
+	(*CreateDeploymentRequest)(nil),  // 2: deployer.v1.CreateDeploymentRequest
+	(*GetDeploymentRequest)(nil),     // 3: deployer.v1.GetDeploymentRequest
+	(*ListDeploymentsRequest)(nil),   // 4: deployer.v1.ListDeploymentsRequest
+	(*ListDeploymentsResponse)(nil),  // 5: deployer.v1.ListDeploymentsResponse
+	(*DeleteDeploymentRequest)(nil),  // 6: deployer.v1.DeleteDeploymentRequest
+	(*DeleteDeploymentResponse)(nil), // 7: deployer.v1.DeleteDeploymentResponse
+	(*StartDeploymentRequest)(nil),   // 8: deployer.v1.StartDeploymentRequest
+	(*TriggerRollbackRequest)(nil),   // 9: deployer.v1.TriggerRollbackRequest
+	(*OrchestrationResponse)(nil),    // 10: deployer.v1.OrchestrationResponse
+	(*StreamLogsRequest)(nil),        // 11: deployer.v1.StreamLogsRequest
+	(*DeploymentLogEntry)(nil),       // 12: deployer.v1.DeploymentLogEntry
+	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),          // 14: google.protobuf.Struct
+}
+var file_deployer_proto_depIdxs = []int32{
+	13, // 0: deployer.v1.Deployment.created_at:type_name -> google.protobuf.Timestamp
+	13, // 1: deployer.v1.Deployment.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 2: deployer.v1.Deployment.deployed_at:type_name -> google.protobuf.Timestamp
+	14, // 3: deployer.v1.Addon.config:type_name -> google.protobuf.Struct
+	1,  // 4: deployer.v1.CreateDeploymentRequest.addons:type_name -> deployer.v1.Addon
+	0,  // 5: deployer.v1.ListDeploymentsResponse.deployments:type_name -> deployer.v1.Deployment
+	1,  // 6: deployer.v1.StartDeploymentRequest.addons:type_name -> deployer.v1.Addon
+	13, // 7: deployer.v1.DeploymentLogEntry.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 8: deployer.v1.DeployerService.CreateDeployment:input_type -> deployer.v1.CreateDeploymentRequest
+	3,  // 9: deployer.v1.DeployerService.GetDeployment:input_type -> deployer.v1.GetDeploymentRequest
+	4,  // 10: deployer.v1.DeployerService.ListDeployments:input_type -> deployer.v1.ListDeploymentsRequest
+	6,  // 11: deployer.v1.DeployerService.DeleteDeployment:input_type -> deployer.v1.DeleteDeploymentRequest
+	8,  // 12: deployer.v1.DeployerService.StartDeployment:input_type -> deployer.v1.StartDeploymentRequest
+	9,  // 13: deployer.v1.DeployerService.TriggerRollback:input_type -> deployer.v1.TriggerRollbackRequest
+	11, // 14: deployer.v1.DeployerService.StreamLogs:input_type -> deployer.v1.StreamLogsRequest
+	0,  // 15: deployer.v1.DeployerService.CreateDeployment:output_type -> deployer.v1.Deployment
+	0,  // 16: deployer.v1.DeployerService.GetDeployment:output_type -> deployer.v1.Deployment
+	5,  // 17: deployer.v1.DeployerService.ListDeployments:output_type -> deployer.v1.ListDeploymentsResponse
+	7,  // 18: deployer.v1.DeployerService.DeleteDeployment:output_type -> deployer.v1.DeleteDeploymentResponse
+	10, // 19: deployer.v1.DeployerService.StartDeployment:output_type -> deployer.v1.OrchestrationResponse
+	10, // 20: deployer.v1.DeployerService.TriggerRollback:output_type -> deployer.v1.OrchestrationResponse
+	12, // 21: deployer.v1.DeployerService.StreamLogs:output_type -> deployer.v1.DeploymentLogEntry
+	15, // [15:22] is the sub-list for method output_type
+	8,  // [8:15] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_deployer_proto_init() }
+func file_deployer_proto_init() {
+	if File_deployer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_deployer_proto_rawDesc), len(file_deployer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_deployer_proto_goTypes,
+		DependencyIndexes: file_deployer_proto_depIdxs,
+		MessageInfos:      file_deployer_proto_msgTypes,
+	}.Build()
+	File_deployer_proto = out.File
+	file_deployer_proto_goTypes = nil
+	file_deployer_proto_depIdxs = nil
+}
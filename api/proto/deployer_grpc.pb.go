@@ -0,0 +1,371 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: deployer.proto
+
+package deployerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DeployerService_CreateDeployment_FullMethodName = "/deployer.v1.DeployerService/CreateDeployment"
+	DeployerService_GetDeployment_FullMethodName    = "/deployer.v1.DeployerService/GetDeployment"
+	DeployerService_ListDeployments_FullMethodName  = "/deployer.v1.DeployerService/ListDeployments"
+	DeployerService_DeleteDeployment_FullMethodName = "/deployer.v1.DeployerService/DeleteDeployment"
+	DeployerService_StartDeployment_FullMethodName  = "/deployer.v1.DeployerService/StartDeployment"
+	DeployerService_TriggerRollback_FullMethodName  = "/deployer.v1.DeployerService/TriggerRollback"
+	DeployerService_StreamLogs_FullMethodName       = "/deployer.v1.DeployerService/StreamLogs"
+)
+
+// DeployerServiceClient is the client API for DeployerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DeployerService mirrors the deployment endpoints of the REST API
+type DeployerServiceClient interface {
+	// CreateDeployment creates a deployment, provisioning immediately when image_tag is set
+	CreateDeployment(ctx context.Context, in *CreateDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	// GetDeployment returns a single deployment
+	GetDeployment(ctx context.Context, in *GetDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error)
+	// ListDeployments returns a page of deployments matching the optional filters
+	ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error)
+	// DeleteDeployment destroys a deployment's infrastructure, or deletes it outright if it has none
+	DeleteDeployment(ctx context.Context, in *DeleteDeploymentRequest, opts ...grpc.CallOption) (*DeleteDeploymentResponse, error)
+	// StartDeployment provisions infrastructure and deploys a built image
+	StartDeployment(ctx context.Context, in *StartDeploymentRequest, opts ...grpc.CallOption) (*OrchestrationResponse, error)
+	// TriggerRollback rolls a deployment back to a previous version
+	TriggerRollback(ctx context.Context, in *TriggerRollbackRequest, opts ...grpc.CallOption) (*OrchestrationResponse, error)
+	// StreamLogs streams status changes, provision logs and build logs for a deployment
+	StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeploymentLogEntry], error)
+}
+
+type deployerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeployerServiceClient(cc grpc.ClientConnInterface) DeployerServiceClient {
+	return &deployerServiceClient{cc}
+}
+
+func (c *deployerServiceClient) CreateDeployment(ctx context.Context, in *CreateDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, DeployerService_CreateDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployerServiceClient) GetDeployment(ctx context.Context, in *GetDeploymentRequest, opts ...grpc.CallOption) (*Deployment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Deployment)
+	err := c.cc.Invoke(ctx, DeployerService_GetDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployerServiceClient) ListDeployments(ctx context.Context, in *ListDeploymentsRequest, opts ...grpc.CallOption) (*ListDeploymentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeploymentsResponse)
+	err := c.cc.Invoke(ctx, DeployerService_ListDeployments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployerServiceClient) DeleteDeployment(ctx context.Context, in *DeleteDeploymentRequest, opts ...grpc.CallOption) (*DeleteDeploymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDeploymentResponse)
+	err := c.cc.Invoke(ctx, DeployerService_DeleteDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployerServiceClient) StartDeployment(ctx context.Context, in *StartDeploymentRequest, opts ...grpc.CallOption) (*OrchestrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrchestrationResponse)
+	err := c.cc.Invoke(ctx, DeployerService_StartDeployment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployerServiceClient) TriggerRollback(ctx context.Context, in *TriggerRollbackRequest, opts ...grpc.CallOption) (*OrchestrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrchestrationResponse)
+	err := c.cc.Invoke(ctx, DeployerService_TriggerRollback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deployerServiceClient) StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeploymentLogEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DeployerService_ServiceDesc.Streams[0], DeployerService_StreamLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamLogsRequest, DeploymentLogEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeployerService_StreamLogsClient = grpc.ServerStreamingClient[DeploymentLogEntry]
+
+// DeployerServiceServer is the server API for DeployerService service.
+// All implementations must embed UnimplementedDeployerServiceServer
+// for forward compatibility.
+//
+// DeployerService mirrors the deployment endpoints of the REST API
+type DeployerServiceServer interface {
+	// CreateDeployment creates a deployment, provisioning immediately when image_tag is set
+	CreateDeployment(context.Context, *CreateDeploymentRequest) (*Deployment, error)
+	// GetDeployment returns a single deployment
+	GetDeployment(context.Context, *GetDeploymentRequest) (*Deployment, error)
+	// ListDeployments returns a page of deployments matching the optional filters
+	ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsResponse, error)
+	// DeleteDeployment destroys a deployment's infrastructure, or deletes it outright if it has none
+	DeleteDeployment(context.Context, *DeleteDeploymentRequest) (*DeleteDeploymentResponse, error)
+	// StartDeployment provisions infrastructure and deploys a built image
+	StartDeployment(context.Context, *StartDeploymentRequest) (*OrchestrationResponse, error)
+	// TriggerRollback rolls a deployment back to a previous version
+	TriggerRollback(context.Context, *TriggerRollbackRequest) (*OrchestrationResponse, error)
+	// StreamLogs streams status changes, provision logs and build logs for a deployment
+	StreamLogs(*StreamLogsRequest, grpc.ServerStreamingServer[DeploymentLogEntry]) error
+	mustEmbedUnimplementedDeployerServiceServer()
+}
+
+// UnimplementedDeployerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDeployerServiceServer struct{}
+
+func (UnimplementedDeployerServiceServer) CreateDeployment(context.Context, *CreateDeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDeployment not implemented")
+}
+func (UnimplementedDeployerServiceServer) GetDeployment(context.Context, *GetDeploymentRequest) (*Deployment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeployment not implemented")
+}
+func (UnimplementedDeployerServiceServer) ListDeployments(context.Context, *ListDeploymentsRequest) (*ListDeploymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeployments not implemented")
+}
+func (UnimplementedDeployerServiceServer) DeleteDeployment(context.Context, *DeleteDeploymentRequest) (*DeleteDeploymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeployment not implemented")
+}
+func (UnimplementedDeployerServiceServer) StartDeployment(context.Context, *StartDeploymentRequest) (*OrchestrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartDeployment not implemented")
+}
+func (UnimplementedDeployerServiceServer) TriggerRollback(context.Context, *TriggerRollbackRequest) (*OrchestrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerRollback not implemented")
+}
+func (UnimplementedDeployerServiceServer) StreamLogs(*StreamLogsRequest, grpc.ServerStreamingServer[DeploymentLogEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLogs not implemented")
+}
+func (UnimplementedDeployerServiceServer) mustEmbedUnimplementedDeployerServiceServer() {}
+func (UnimplementedDeployerServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeDeployerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeployerServiceServer will
+// result in compilation errors.
+type UnsafeDeployerServiceServer interface {
+	mustEmbedUnimplementedDeployerServiceServer()
+}
+
+func RegisterDeployerServiceServer(s grpc.ServiceRegistrar, srv DeployerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDeployerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DeployerService_ServiceDesc, srv)
+}
+
+func _DeployerService_CreateDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployerServiceServer).CreateDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeployerService_CreateDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployerServiceServer).CreateDeployment(ctx, req.(*CreateDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployerService_GetDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployerServiceServer).GetDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeployerService_GetDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployerServiceServer).GetDeployment(ctx, req.(*GetDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployerService_ListDeployments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeploymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployerServiceServer).ListDeployments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeployerService_ListDeployments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployerServiceServer).ListDeployments(ctx, req.(*ListDeploymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployerService_DeleteDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployerServiceServer).DeleteDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeployerService_DeleteDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployerServiceServer).DeleteDeployment(ctx, req.(*DeleteDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployerService_StartDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartDeploymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployerServiceServer).StartDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeployerService_StartDeployment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployerServiceServer).StartDeployment(ctx, req.(*StartDeploymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployerService_TriggerRollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerRollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeployerServiceServer).TriggerRollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeployerService_TriggerRollback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeployerServiceServer).TriggerRollback(ctx, req.(*TriggerRollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeployerService_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeployerServiceServer).StreamLogs(m, &grpc.GenericServerStream[StreamLogsRequest, DeploymentLogEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DeployerService_StreamLogsServer = grpc.ServerStreamingServer[DeploymentLogEntry]
+
+// DeployerService_ServiceDesc is the grpc.ServiceDesc for DeployerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeployerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "deployer.v1.DeployerService",
+	HandlerType: (*DeployerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDeployment",
+			Handler:    _DeployerService_CreateDeployment_Handler,
+		},
+		{
+			MethodName: "GetDeployment",
+			Handler:    _DeployerService_GetDeployment_Handler,
+		},
+		{
+			MethodName: "ListDeployments",
+			Handler:    _DeployerService_ListDeployments_Handler,
+		},
+		{
+			MethodName: "DeleteDeployment",
+			Handler:    _DeployerService_DeleteDeployment_Handler,
+		},
+		{
+			MethodName: "StartDeployment",
+			Handler:    _DeployerService_StartDeployment_Handler,
+		},
+		{
+			MethodName: "TriggerRollback",
+			Handler:    _DeployerService_TriggerRollback_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _DeployerService_StreamLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "deployer.proto",
+}
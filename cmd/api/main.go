@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -9,10 +10,14 @@ import (
 	"time"
 
 	"github.com/alvesdmateus/app-deployer/internal/api"
+	"github.com/alvesdmateus/app-deployer/internal/events"
+	grpcapi "github.com/alvesdmateus/app-deployer/internal/grpc"
+	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/alvesdmateus/app-deployer/pkg/config"
 	"github.com/alvesdmateus/app-deployer/pkg/database"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -54,8 +59,33 @@ func main() {
 	}
 	defer database.Close(db)
 
+	// Run migrations
+	if err := database.MigrateUp(db, cfg.Database.MigrationsPath); err != nil {
+		log.Fatal().Err(err).Msg("Failed to run migrations")
+	}
+
+	// Connect to the read replica when one is configured
+	var readDB *gorm.DB
+	if cfg.Database.ReplicaHost != "" {
+		readDB, err = database.NewReadReplica(database.ReplicaConfig{
+			Host:            cfg.Database.ReplicaHost,
+			Port:            cfg.Database.ReplicaPort,
+			User:            cfg.Database.User,
+			Password:        cfg.Database.Password,
+			DBName:          cfg.Database.DBName,
+			SSLMode:         cfg.Database.SSLMode,
+			MaxOpenConns:    cfg.Database.MaxOpenConns,
+			MaxIdleConns:    cfg.Database.MaxIdleConns,
+			ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to read replica")
+		}
+		defer database.Close(readDB)
+	}
+
 	// Create API server
-	server := api.NewServer(db)
+	server := api.NewServer(db, readDB)
 
 	// Configure HTTP server
 	httpServer := &http.Server{
@@ -66,6 +96,26 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Create gRPC server sharing the HTTP server's orchestrator client. Followed log streams
+	// hear of deployment and build changes through Postgres notifications.
+	grpcServer := grpcapi.NewServer(state.NewRepository(db, readDB, nil), server.OrchestratorClient(),
+		events.NewPGListener(cfg.GetDatabaseDSN()))
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+	if err != nil {
+		log.Fatal().Err(err).Str("port", cfg.Server.GRPCPort).Msg("Failed to listen for gRPC")
+	}
+
+	go func() {
+		log.Info().
+			Str("port", cfg.Server.GRPCPort).
+			Msg("gRPC server listening")
+
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatal().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
 	// Start server in goroutine
 	go func() {
 		log.Info().
@@ -93,5 +143,7 @@ func main() {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
+	grpcServer.GracefulStop()
+
 	log.Info().Msg("Server exited gracefully")
 }
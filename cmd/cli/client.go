@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultAPIURL is used when no API URL is configured
+const defaultAPIURL = "http://localhost:3000"
+
+// errNotFound is returned when the API responds with 404
+var errNotFound = errors.New("not found")
+
+// apiClient is a minimal client for the app-deployer REST API
+type apiClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// deployment mirrors the API deployment response
+type deployment struct {
+	ID          string     `json:"id" yaml:"id"`
+	Name        string     `json:"name" yaml:"name"`
+	AppName     string     `json:"app_name" yaml:"app_name"`
+	Version     string     `json:"version" yaml:"version"`
+	Status      string     `json:"status" yaml:"status"`
+	Cloud       string     `json:"cloud" yaml:"cloud"`
+	Region      string     `json:"region" yaml:"region"`
+	ExternalIP  string     `json:"external_ip,omitempty" yaml:"external_ip,omitempty"`
+	ExternalURL string     `json:"external_url,omitempty" yaml:"external_url,omitempty"`
+	Error       string     `json:"error,omitempty" yaml:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" yaml:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" yaml:"updated_at"`
+	DeployedAt  *time.Time `json:"deployed_at,omitempty" yaml:"deployed_at,omitempty"`
+}
+
+// deploymentList mirrors the API list deployments response
+type deploymentList struct {
+	Deployments []deployment `json:"deployments"`
+	Total       int          `json:"total"`
+	Limit       int          `json:"limit"`
+	Offset      int          `json:"offset"`
+}
+
+// rollbackOption mirrors a revision in the rollback-options response
+type rollbackOption struct {
+	Revision   int       `json:"revision"`
+	Status     string    `json:"status"`
+	ImageTag   string    `json:"image_tag"`
+	DeployedAt time.Time `json:"deployed_at"`
+}
+
+// rollbackOptions mirrors the rollback-options response
+type rollbackOptions struct {
+	DeploymentID string           `json:"deployment_id"`
+	ReleaseName  string           `json:"release_name"`
+	Options      []rollbackOption `json:"options"`
+}
+
+// rollbackRequest mirrors the rollback request body
+type rollbackRequest struct {
+	TargetVersion string `json:"target_version,omitempty"`
+	TargetTag     string `json:"target_tag,omitempty"`
+	Revision      int    `json:"revision,omitempty"`
+}
+
+// apiError mirrors the API error response
+type apiError struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// newAPIClient creates an API client from the config file and environment
+func newAPIClient() (*apiClient, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiClient{
+		baseURL:    strings.TrimSuffix(cfg.APIURL, "/"),
+		apiKey:     cfg.APIKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// ListDeployments lists deployments, passing query as URL parameters
+func (c *apiClient) ListDeployments(ctx context.Context, query url.Values) (*deploymentList, error) {
+	path := "/api/v1/deployments"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var list deploymentList
+	if err := c.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetDeployment fetches a deployment by ID
+func (c *apiClient) GetDeployment(ctx context.Context, id string) (*deployment, error) {
+	var d deployment
+	if err := c.do(ctx, http.MethodGet, "/api/v1/deployments/"+id, nil, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// DeleteDeployment starts destruction of a deployment, optionally deleting its persistent volumes
+func (c *apiClient) DeleteDeployment(ctx context.Context, id string, deleteVolumes bool) error {
+	path := "/api/v1/deployments/" + id
+	if deleteVolumes {
+		path += "?delete_volumes=true"
+	}
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// Rollback triggers a rollback of a deployment
+func (c *apiClient) Rollback(ctx context.Context, id string, req *rollbackRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/deployments/"+id+"/rollback", req, nil)
+}
+
+// GetRollbackOptions fetches the Helm revisions a deployment can be rolled back to
+func (c *apiClient) GetRollbackOptions(ctx context.Context, id string) (*rollbackOptions, error) {
+	var o rollbackOptions
+	if err := c.do(ctx, http.MethodGet, "/api/v1/deployments/"+id+"/rollback-options", nil, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// ExportDeployment fetches a deployment's deployer.yaml manifest
+func (c *apiClient) ExportDeployment(ctx context.Context, id string) ([]byte, error) {
+	resp, err := c.send(ctx, http.MethodGet, "/api/v1/deployments/"+id+"/export?format=yaml", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	manifest, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// ImportDeployment creates a deployment from a deployer.yaml manifest
+func (c *apiClient) ImportDeployment(ctx context.Context, manifest []byte) (*deployment, error) {
+	resp, err := c.send(ctx, http.MethodPost, "/api/v1/deployments/import", "application/yaml", bytes.NewReader(manifest))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var d deployment
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &d, nil
+}
+
+// do sends a request and decodes the JSON response into out (if non-nil)
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var (
+		reader      io.Reader
+		contentType string
+	)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	resp, err := c.send(ctx, method, path, contentType, reader)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// send sends a request, returning the response of successful ones; error statuses are
+// returned as errors with the API's message
+func (c *apiClient) send(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, errNotFound
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+
+		var apiErr apiError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("%s %s: %s", method, path, apiErr.Message)
+		}
+		return nil, fmt.Errorf("%s %s: unexpected status %d", method, path, resp.StatusCode)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cliConfig holds CLI settings from ~/.deployer/config.yaml, overridden by the environment
+type cliConfig struct {
+	APIURL string `yaml:"api_url"`
+	APIKey string `yaml:"api_key"`
+}
+
+// loadConfig reads the CLI config file (if present) and applies DEPLOYER_API_URL / DEPLOYER_API_KEY
+func loadConfig() (*cliConfig, error) {
+	cfg := &cliConfig{}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		path := filepath.Join(home, ".deployer", "config.yaml")
+
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// No config file, rely on environment and defaults
+		case err != nil:
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		default:
+			if err := yaml.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("parse %s: %w", path, err)
+			}
+		}
+	}
+
+	if url := os.Getenv("DEPLOYER_API_URL"); url != "" {
+		cfg.APIURL = url
+	}
+	if key := os.Getenv("DEPLOYER_API_KEY"); key != "" {
+		cfg.APIKey = key
+	}
+
+	if cfg.APIURL == "" {
+		cfg.APIURL = defaultAPIURL
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newDestroyCmd creates the destroy command
+func newDestroyCmd() *cobra.Command {
+	var (
+		timeout       time.Duration
+		deleteVolumes bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "destroy <id>",
+		Short: "Destroy a deployment",
+		Long:  "Destroy a deployment and its infrastructure, waiting until it reaches DESTROYED.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+			stderr := cmd.ErrOrStderr()
+
+			if err := client.DeleteDeployment(cmd.Context(), id, deleteVolumes); err != nil {
+				return fmt.Errorf("destroy deployment: %w", err)
+			}
+
+			fmt.Fprintf(stderr, "Destruction of deployment %s initiated\n", id)
+
+			if _, err := waitForStatus(cmd.Context(), client, id, waitOptions{
+				Target:       "DESTROYED",
+				GoneIsTarget: true,
+				Timeout:      timeout,
+			}, stderr); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(stderr, "Deployment %s destroyed\n", id)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "Maximum time to wait for destruction")
+	cmd.Flags().BoolVar(&deleteVolumes, "delete-volumes", false, "Also delete persistent volumes (statefulset data)")
+
+	return cmd
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// watchInterval is how often --watch re-renders the listing
+const watchInterval = 5 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// listOptions holds flags for the list command
+type listOptions struct {
+	status string
+	cloud  string
+	region string
+	output string
+	limit  int
+	watch  bool
+}
+
+// newListCmd creates the list command
+func newListCmd() *cobra.Command {
+	opts := &listOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all deployments",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch opts.output {
+			case "table", "json", "yaml":
+			default:
+				return fmt.Errorf("unsupported output format %q (use table, json or yaml)", opts.output)
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			if !opts.watch {
+				return runList(cmd.Context(), client, opts, cmd.OutOrStdout())
+			}
+
+			ticker := time.NewTicker(watchInterval)
+			defer ticker.Stop()
+
+			for {
+				fmt.Fprint(cmd.OutOrStdout(), clearScreen)
+				if err := runList(cmd.Context(), client, opts, cmd.OutOrStdout()); err != nil {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Error:", err)
+				}
+
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.status, "status", "", "Only show deployments with this status")
+	cmd.Flags().StringVar(&opts.cloud, "cloud", "", "Only show deployments on this cloud")
+	cmd.Flags().StringVar(&opts.region, "region", "", "Only show deployments in this region")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "table", "Output format: table, json or yaml")
+	cmd.Flags().IntVar(&opts.limit, "limit", 0, "Maximum number of deployments to show (server default if 0)")
+	cmd.Flags().BoolVarP(&opts.watch, "watch", "w", false, "Re-render the list every 5 seconds")
+
+	return cmd
+}
+
+// runList fetches deployments once and renders them in the requested format
+func runList(ctx context.Context, client *apiClient, opts *listOptions, out io.Writer) error {
+	query := url.Values{}
+	if opts.status != "" {
+		query.Set("status", opts.status)
+	}
+	if opts.cloud != "" {
+		query.Set("cloud", opts.cloud)
+	}
+	if opts.region != "" {
+		query.Set("region", opts.region)
+	}
+	if opts.limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.limit))
+	}
+
+	list, err := client.ListDeployments(ctx, query)
+	if err != nil {
+		return fmt.Errorf("list deployments: %w", err)
+	}
+
+	switch opts.output {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list.Deployments)
+	case "yaml":
+		enc := yaml.NewEncoder(out)
+		defer enc.Close()
+		return enc.Encode(list.Deployments)
+	default:
+		renderDeploymentTable(out, list.Deployments)
+		return nil
+	}
+}
+
+// renderDeploymentTable writes deployments as a table
+func renderDeploymentTable(out io.Writer, deployments []deployment) {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"ID", "Name", "App Name", "Status", "Cloud", "Region", "External URL", "Age"})
+	table.SetAutoWrapText(false)
+	table.SetBorder(false)
+	table.SetHeaderLine(false)
+	table.SetColumnSeparator("")
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, d := range deployments {
+		table.Append([]string{
+			shortID(d.ID),
+			d.Name,
+			d.AppName,
+			d.Status,
+			d.Cloud,
+			d.Region,
+			d.ExternalURL,
+			formatAge(time.Since(d.CreatedAt)),
+		})
+	}
+
+	table.Render()
+}
+
+// shortID returns the first 8 characters of a deployment ID
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// formatAge renders a duration in the compact kubectl style (e.g. 45s, 12m, 3h, 5d)
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
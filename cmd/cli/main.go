@@ -1,19 +1,74 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
 )
 
+const version = "0.1.0"
+
 func main() {
-	fmt.Println("app-deployer CLI v0.1.0")
-	fmt.Println("Usage:")
-	fmt.Println("  deployer deploy <repo-url>    Deploy an application from repository")
-	fmt.Println("  deployer list                 List all deployments")
-	fmt.Println("  deployer logs <id>            Stream deployment logs")
-	fmt.Println("  deployer destroy <id>         Destroy a deployment")
-	fmt.Println("  deployer rollback <id>        Rollback a deployment")
-	fmt.Println()
-	fmt.Println("Not implemented yet. Coming in Phase 3.")
-	os.Exit(0)
+	// Cancel in-flight requests and wait loops on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	err := newRootCmd().ExecuteContext(ctx)
+	stop()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// newRootCmd builds the deployer command tree
+func newRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:           "deployer",
+		Short:         "app-deployer CLI",
+		Long:          "Deploy, inspect and manage applications on app-deployer.",
+		Version:       version,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	rootCmd.AddCommand(
+		newDeployCmd(),
+		newListCmd(),
+		newLogsCmd(),
+		newDestroyCmd(),
+		newRollbackCmd(),
+		newExportCmd(),
+		newApplyCmd(),
+	)
+
+	return rootCmd
+}
+
+// newDeployCmd creates the deploy command
+func newDeployCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deploy <repo-url>",
+		Short: "Deploy an application from repository",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("deploy is not implemented yet")
+		},
+	}
+}
+
+// newLogsCmd creates the logs command
+func newLogsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs <id>",
+		Short: "Stream deployment logs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("logs is not implemented yet")
+		},
+	}
 }
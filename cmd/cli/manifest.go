@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newExportCmd creates the export command
+func newExportCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export <id>",
+		Short: "Export a deployment as a deployer.yaml manifest",
+		Long: "Print a deployment's configuration as a deployer.yaml manifest, to commit to Git and\n" +
+			"recreate the deployment with apply. Secret environment variables are listed without values.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			manifest, err := client.ExportDeployment(cmd.Context(), id)
+			if errors.Is(err, errNotFound) {
+				return fmt.Errorf("deployment %s not found", id)
+			}
+			if err != nil {
+				return fmt.Errorf("export deployment: %w", err)
+			}
+
+			if output == "" {
+				_, err := cmd.OutOrStdout().Write(manifest)
+				return err
+			}
+
+			if err := os.WriteFile(output, manifest, 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", output, err)
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Deployment %s exported to %s\n", id, output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the manifest to (stdout if empty)")
+
+	return cmd
+}
+
+// newApplyCmd creates the apply command
+func newApplyCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <file>",
+		Short: "Create a deployment from a deployer.yaml manifest",
+		Long: "Create a deployment with the configuration of a deployer.yaml manifest. Secret environment\n" +
+			"variables listed in the manifest must be set on the new deployment before it is started.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				manifest []byte
+				err      error
+			)
+			if file == "-" {
+				manifest, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				manifest, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return fmt.Errorf("read manifest: %w", err)
+			}
+
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			d, err := client.ImportDeployment(cmd.Context(), manifest)
+			if err != nil {
+				return fmt.Errorf("apply manifest: %w", err)
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Deployment %s created as %s (%s)\n", d.Name, d.ID, d.Status)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Manifest to apply, - for stdin")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
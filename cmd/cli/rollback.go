@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newRollbackCmd creates the rollback command
+func newRollbackCmd() *cobra.Command {
+	var (
+		targetVersion string
+		targetTag     string
+		revision      int
+		timeout       time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rollback <id>",
+		Short: "Rollback a deployment",
+		Long: "Roll a deployment back to a previous version, waiting until it is EXPOSED again.\n" +
+			"Without --version or --revision, the revisions of the Helm release are listed and one is selected.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			client, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+			stderr := cmd.ErrOrStderr()
+
+			if targetVersion == "" && revision == 0 {
+				options, err := client.GetRollbackOptions(cmd.Context(), id)
+				if errors.Is(err, errNotFound) {
+					return fmt.Errorf("deployment %s has no Helm release to roll back", id)
+				}
+				if err != nil {
+					return fmt.Errorf("get rollback options: %w", err)
+				}
+
+				selected, err := selectRollbackOption(cmd.InOrStdin(), stderr, options.Options)
+				if err != nil {
+					return err
+				}
+				revision = selected.Revision
+				if targetTag == "" {
+					targetTag = selected.ImageTag
+				}
+			}
+
+			if err := client.Rollback(cmd.Context(), id, &rollbackRequest{
+				TargetVersion: targetVersion,
+				TargetTag:     targetTag,
+				Revision:      revision,
+			}); err != nil {
+				return fmt.Errorf("rollback deployment: %w", err)
+			}
+
+			target := rollbackTarget(targetVersion, revision)
+			fmt.Fprintf(stderr, "Rollback of deployment %s to %s initiated\n", id, target)
+
+			if _, err := waitForStatus(cmd.Context(), client, id, waitOptions{
+				Target:  "EXPOSED",
+				Timeout: timeout,
+			}, stderr); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(stderr, "Deployment %s rolled back to %s\n", id, target)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&targetVersion, "version", "", "Version to roll back to")
+	cmd.Flags().StringVar(&targetTag, "tag", "", "Specific image tag to roll back to")
+	cmd.Flags().IntVar(&revision, "revision", 0, "Helm revision to roll back to (selected from a list if neither it nor --version is set)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 15*time.Minute, "Maximum time to wait for the rollback")
+
+	return cmd
+}
+
+// rollbackTarget describes what a rollback returns to for progress messages
+func rollbackTarget(version string, revision int) string {
+	if version != "" {
+		return "version " + version
+	}
+	return fmt.Sprintf("revision %d", revision)
+}
+
+// selectRollbackOption lists the superseded revisions, newest first, and prompts for one by
+// its number in the list. An empty answer selects the newest.
+func selectRollbackOption(in io.Reader, out io.Writer, options []rollbackOption) (*rollbackOption, error) {
+	// The deployed revision is the one running now
+	candidates := make([]rollbackOption, 0, len(options))
+	for _, opt := range options {
+		if opt.Status == "superseded" {
+			candidates = append(candidates, opt)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no previous revision to roll back to")
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tREVISION\tIMAGE TAG\tDEPLOYED")
+	for i, opt := range candidates {
+		tag := opt.ImageTag
+		if tag == "" {
+			tag = "-"
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\n", i+1, opt.Revision, tag, opt.DeployedAt.Local().Format(time.DateTime))
+	}
+	tw.Flush()
+
+	answer, err := promptLine(in, out, fmt.Sprintf("Select a revision [1-%d] (default 1): ", len(candidates)))
+	if err != nil {
+		return nil, err
+	}
+	if answer == "" {
+		return &candidates[0], nil
+	}
+
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(candidates) {
+		return nil, fmt.Errorf("invalid selection %q", answer)
+	}
+
+	return &candidates[n-1], nil
+}
+
+// promptLine prints a prompt and reads a single trimmed line of input
+func promptLine(in io.Reader, out io.Writer, prompt string) (string, error) {
+	fmt.Fprint(out, prompt)
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read input: %w", err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// defaultPollInterval is how often deployment status is polled while waiting
+const defaultPollInterval = 5 * time.Second
+
+// waitOptions controls how waitForStatus polls a deployment
+type waitOptions struct {
+	Target       string        // Status that ends the wait successfully
+	GoneIsTarget bool          // Treat a deleted deployment (404) as success
+	Interval     time.Duration // Poll interval
+	Timeout      time.Duration // Overall timeout, 0 for none
+}
+
+// waitForStatus polls a deployment until it reaches the target status or FAILED,
+// writing every status transition to progress
+func waitForStatus(ctx context.Context, client *apiClient, id string, opts waitOptions, progress io.Writer) (*deployment, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultPollInterval
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	lastStatus := ""
+	for {
+		d, err := client.GetDeployment(ctx, id)
+		switch {
+		case errors.Is(err, errNotFound) && opts.GoneIsTarget:
+			fmt.Fprintf(progress, "[%s] Deployment %s removed\n", time.Now().Format(time.TimeOnly), id)
+			return nil, nil
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("timed out waiting for %s (last status: %s)", opts.Target, lastStatus)
+			}
+			return nil, fmt.Errorf("get deployment: %w", err)
+		}
+
+		if d.Status != lastStatus {
+			fmt.Fprintf(progress, "[%s] Status: %s\n", time.Now().Format(time.TimeOnly), d.Status)
+			lastStatus = d.Status
+		}
+
+		switch d.Status {
+		case opts.Target:
+			return d, nil
+		case "FAILED":
+			if d.Error != "" {
+				return d, fmt.Errorf("deployment failed: %s", d.Error)
+			}
+			return d, fmt.Errorf("deployment failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return d, fmt.Errorf("timed out waiting for %s (last status: %s)", opts.Target, lastStatus)
+		case <-ticker.C:
+		}
+	}
+}
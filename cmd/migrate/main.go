@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/alvesdmateus/app-deployer/pkg/config"
+	"github.com/alvesdmateus/app-deployer/pkg/database"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+func main() {
+	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// newRootCmd builds the migrate command tree
+func newRootCmd() *cobra.Command {
+	var migrationsPath string
+
+	rootCmd := &cobra.Command{
+		Use:           "migrate",
+		Short:         "Apply and roll back app-deployer database migrations",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	rootCmd.PersistentFlags().StringVar(&migrationsPath, "path", "",
+		"Migrations directory (defaults to database.migrations_path)")
+
+	rootCmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withDatabase(migrationsPath, func(db *gorm.DB, path string) error {
+					return database.MigrateUp(db, path)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "down [steps]",
+			Short: "Roll back the last applied migrations (1 by default)",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				steps := 1
+				if len(args) == 1 {
+					n, err := strconv.Atoi(args[0])
+					if err != nil || n < 1 {
+						return fmt.Errorf("steps must be a positive number, got %q", args[0])
+					}
+					steps = n
+				}
+
+				return withDatabase(migrationsPath, func(db *gorm.DB, path string) error {
+					return database.MigrateDown(db, path, steps)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "List migrations and whether they are applied",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withDatabase(migrationsPath, func(db *gorm.DB, path string) error {
+					statuses, err := database.GetMigrationStatus(db, path)
+					if err != nil {
+						return err
+					}
+
+					w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+					for _, s := range statuses {
+						appliedAt := "pending"
+						if s.Applied {
+							appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+						}
+						fmt.Fprintf(w, "%06d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+					}
+					return w.Flush()
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "version",
+			Short: "Print the version of the newest applied migration",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withDatabase(migrationsPath, func(db *gorm.DB, path string) error {
+					version, err := database.MigrationVersion(db)
+					if err != nil {
+						return err
+					}
+
+					fmt.Fprintln(cmd.OutOrStdout(), version)
+					return nil
+				})
+			},
+		},
+	)
+
+	return rootCmd
+}
+
+// withDatabase connects to the configured database and runs fn with it and the migrations
+// directory, which the --path flag overrides
+func withDatabase(migrationsPath string, fn func(db *gorm.DB, path string) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if migrationsPath == "" {
+		migrationsPath = cfg.Database.MigrationsPath
+	}
+
+	// Migrations run one at a time in a single transaction, so one connection is enough
+	db, err := database.New(database.Config{
+		Host:            cfg.Database.Host,
+		Port:            cfg.Database.Port,
+		User:            cfg.Database.User,
+		Password:        cfg.Database.Password,
+		DBName:          cfg.Database.DBName,
+		SSLMode:         cfg.Database.SSLMode,
+		MaxOpenConns:    1,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+	})
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	return fn(db, migrationsPath)
+}
@@ -9,12 +9,12 @@ import (
 	"time"
 
 	"github.com/alvesdmateus/app-deployer/internal/api"
-	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/alvesdmateus/app-deployer/pkg/config"
 	"github.com/alvesdmateus/app-deployer/pkg/database"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -59,13 +59,7 @@ func main() {
 	}()
 
 	// Run migrations
-	models := []interface{}{
-		&state.Deployment{},
-		&state.Infrastructure{},
-		&state.Build{},
-	}
-
-	if err := database.Migrate(db, models...); err != nil {
+	if err := database.MigrateUp(db, cfg.Database.MigrationsPath); err != nil {
 		log.Fatal().Err(err).Msg("Failed to run migrations")
 	}
 
@@ -76,8 +70,32 @@ func main() {
 
 	log.Info().Msg("Database is healthy")
 
+	// Connect to the read replica when one is configured
+	var readDB *gorm.DB
+	if cfg.Database.ReplicaHost != "" {
+		readDB, err = database.NewReadReplica(database.ReplicaConfig{
+			Host:            cfg.Database.ReplicaHost,
+			Port:            cfg.Database.ReplicaPort,
+			User:            cfg.Database.User,
+			Password:        cfg.Database.Password,
+			DBName:          cfg.Database.DBName,
+			SSLMode:         cfg.Database.SSLMode,
+			MaxOpenConns:    cfg.Database.MaxOpenConns,
+			MaxIdleConns:    cfg.Database.MaxIdleConns,
+			ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to read replica")
+		}
+		defer func() {
+			if err := database.Close(readDB); err != nil {
+				log.Error().Err(err).Msg("Failed to close read replica")
+			}
+		}()
+	}
+
 	// Initialize HTTP server
-	apiServer := api.NewServer(db)
+	apiServer := api.NewServer(db, readDB)
 	httpServer := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
 		Handler:      apiServer.Handler(),
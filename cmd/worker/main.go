@@ -2,23 +2,37 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 
+	"github.com/alvesdmateus/app-deployer/internal/builder"
+	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
+	"github.com/alvesdmateus/app-deployer/internal/builder/strategies"
+	"github.com/alvesdmateus/app-deployer/internal/costs"
 	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/maintenance"
 	"github.com/alvesdmateus/app-deployer/internal/orchestrator"
 	"github.com/alvesdmateus/app-deployer/internal/provisioner"
 	"github.com/alvesdmateus/app-deployer/internal/provisioner/gcp"
 	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/alvesdmateus/app-deployer/pkg/config"
 	"github.com/alvesdmateus/app-deployer/pkg/database"
 )
 
+// version is reported in worker heartbeats, set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
 	// Initialize logger
 	zlog := zerolog.New(os.Stdout).With().Timestamp().Logger()
@@ -61,32 +75,63 @@ func main() {
 
 	// Run migrations
 	zlog.Info().Msg("Running database migrations...")
-	if err := database.Migrate(db, &state.Deployment{}, &state.Infrastructure{}, &state.Build{}); err != nil {
+	if err := database.MigrateUp(db, cfg.Database.MigrationsPath); err != nil {
 		zlog.Fatal().Err(err).Msg("Failed to run database migrations")
 	}
 	zlog.Info().Msg("Database migrations completed")
 
-	// Create repository
-	repo := state.NewRepository(db)
-
 	// Connect to Redis queue
 	zlog.Info().
 		Str("redis_url", cfg.Redis.URL).
 		Int("redis_db", cfg.Redis.DB).
 		Msg("Connecting to Redis...")
 
+	// With the Pub/Sub job queue Redis is optional and only backs caching and progress streams
+	var redisClient *redis.Client
 	redisQueue, err := queue.NewRedisQueue(cfg.Redis.URL, cfg.Redis.Password, cfg.Redis.DB)
 	if err != nil {
-		zlog.Fatal().Err(err).Msg("Failed to connect to Redis")
+		if cfg.Queue.Backend != "pubsub" {
+			zlog.Fatal().Err(err).Msg("Failed to connect to Redis")
+		}
+		zlog.Warn().Err(err).Msg("Failed to connect to Redis, deployment cache and provisioning progress disabled")
+	} else {
+		defer redisQueue.Close()
+		redisClient = redisQueue.Client()
+		zlog.Info().Msg("Redis connected successfully")
 	}
-	defer redisQueue.Close()
 
-	zlog.Info().Msg("Redis connected successfully")
+	// Create repository; it shares the API server's deployment cache so the worker's writes
+	// invalidate what the API has cached
+	repo := state.NewRepository(db, nil, redisClient)
 
-	// Verify Redis connection
+	// Connect to the job queue
 	ctx := context.Background()
-	if err := redisQueue.Ping(ctx); err != nil {
-		zlog.Fatal().Err(err).Msg("Redis ping failed")
+	var jobQueue queue.Queue
+	switch cfg.Queue.Backend {
+	case "redis":
+		jobQueue = redisQueue
+	case "pubsub":
+		project := cfg.Queue.PubSubProject
+		if project == "" {
+			project = cfg.Provisioner.GCPProject
+		}
+
+		pubsubQueue, err := queue.NewPubSubQueue(ctx, queue.PubSubConfig{
+			Project:     project,
+			TopicPrefix: cfg.Queue.PubSubTopicPrefix,
+		})
+		if err != nil {
+			zlog.Fatal().Err(err).Msg("Failed to connect to Pub/Sub")
+		}
+		defer pubsubQueue.Close()
+		jobQueue = pubsubQueue
+	default:
+		zlog.Fatal().Str("backend", cfg.Queue.Backend).Msg("queue.backend must be redis or pubsub")
+	}
+
+	// Verify queue connection
+	if err := jobQueue.Ping(ctx); err != nil {
+		zlog.Fatal().Err(err).Str("backend", cfg.Queue.Backend).Msg("Queue ping failed")
 	}
 
 	// Initialize GCP provisioner
@@ -105,9 +150,12 @@ func main() {
 		PulumiBackend:   cfg.Provisioner.PulumiBackend,
 		DefaultNodeType: cfg.Provisioner.DefaultNodeType,
 		DefaultNodes:    cfg.Provisioner.DefaultNodes,
+		CostAllocation: gcp.CostAllocationConfig{
+			RequiredLabels: cfg.Provisioner.RequiredCostLabels,
+		},
 	}
 
-	provisionerTracker := provisioner.NewTracker(repo)
+	provisionerTracker := provisioner.NewTracker(repo, redisClient)
 	gcpProv, err := gcp.NewGCPProvisioner(gcpConfig, provisionerTracker)
 	if err != nil {
 		zlog.Fatal().Err(err).Msg("Failed to create GCP provisioner")
@@ -127,6 +175,17 @@ func main() {
 		ChartPath:       "templates/helm/base-app",
 		DefaultReplicas: cfg.Deployer.DefaultReplicas,
 		DefaultPort:     cfg.Deployer.DefaultPort,
+
+		EnableNetworkPolicies: cfg.Deployer.EnableNetworkPolicies,
+
+		ResourcePolicy: deployer.ResourceLimitPolicy{
+			MaxCPULimit:    cfg.Deployer.MaxCPULimit,
+			MaxMemoryLimit: cfg.Deployer.MaxMemoryLimit,
+			MaxReplicas:    cfg.Deployer.MaxReplicas,
+		},
+
+		AddonChartVersions: cfg.Deployer.AddonChartVersions,
+		HelmChartRegistry:  deployer.HelmChartRegistry{RegistryURL: cfg.Deployer.ChartRegistryURL},
 	}
 
 	deployerTracker := deployer.NewTracker(repo)
@@ -139,39 +198,281 @@ func main() {
 
 	// Create orchestrator engine
 	zlog.Info().Msg("Creating orchestrator engine...")
-	engine := orchestrator.NewEngine(redisQueue, repo, gcpProv, helmDeployer, zlog)
+	engine := orchestrator.NewEngine(jobQueue, repo, gcpProv, helmDeployer, zlog)
+
+	// Cloud Run deploys skip cluster provisioning and are only available on GCP
+	if cfg.Provisioner.Provider == "gcp" {
+		cloudRunDeployer, err := deployer.NewCloudRunDeployer(ctx, deployer.CloudRunConfig{
+			Project: cfg.Provisioner.GCPProject,
+			Region:  cfg.Provisioner.GCPRegion,
+		}, deployerTracker)
+		if err != nil {
+			zlog.Warn().Err(err).Msg("Failed to create Cloud Run deployer, Cloud Run deployments disabled")
+		} else {
+			engine.SetCloudRunDeployer(cloudRunDeployer)
+			zlog.Info().Msg("Cloud Run deployer initialized successfully")
+		}
+	}
+
+	// Kustomize deploys are opt-in per deployment and need the kustomize and kubectl CLIs
+	kustomizeDeployer, err := deployer.NewKustomizeDeployer(deployerTracker, repo)
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Failed to create Kustomize deployer, Kustomize deployments disabled")
+	} else {
+		engine.SetKustomizeDeployer(kustomizeDeployer)
+		zlog.Info().Msg("Kustomize deployer initialized successfully")
+	}
+
+	// Verify image signatures before deploying
+	if cfg.Security.EnforceSignedImages && cfg.Security.CosignKeyRef == "" {
+		zlog.Fatal().Msg("security.enforce_signed_images requires security.cosign_key_ref")
+	}
+	engine.SetImageVerification(cfg.Security.CosignKeyRef, cfg.Security.EnforceSignedImages)
+
+	if cfg.Security.BinaryAuthAttestor != "" && !strings.HasPrefix(cfg.Security.CosignKeyRef, "gcpkms://") {
+		zlog.Fatal().Msg("security.binauthz_attestor requires a gcpkms:// security.cosign_key_ref")
+	}
+
+	// Decrypt secret environment variables at deploy time, and stored registry credentials
+	var cipher *secrets.Cipher
+	if cfg.Secrets.EncryptionKey != "" {
+		cipher, err = secrets.NewCipher(cfg.Secrets.EncryptionKey)
+		if err != nil {
+			zlog.Fatal().Err(err).Msg("Invalid secrets.encryption_key")
+		}
+		engine.SetSecretCipher(cipher)
+	}
+
+	// Git hook pushes are built on the worker, which needs Docker and registry credentials
+	// unless builds run in Kaniko pods
+	var kanikoConfig *strategies.KanikoConfig
+	if cfg.Builder.UseKaniko {
+		kanikoConfig = &strategies.KanikoConfig{
+			Namespace:      cfg.Builder.KanikoNamespace,
+			ServiceAccount: cfg.Builder.KanikoAccount,
+		}
+	}
+
+	buildService, err := builder.NewService(builder.ServiceConfig{
+		RegistryConfig: registry.Config{
+			Type:     cfg.Registry.Type,
+			Project:  cfg.Registry.Project,
+			Location: cfg.Registry.Location,
+
+			Credentials: registry.NewCredentialProvider(repo, cipher),
+		},
+		StrategyType: strategies.StrategyTypeDocker,
+		CacheConfig: builder.BuildCacheConfig{
+			BucketName: cfg.Builder.CacheBucket,
+			MaxAgeDays: cfg.Builder.CacheMaxAgeDays,
+		},
+		RegistryCache: cfg.Builder.RegistryCache,
+		SBOMConfig: builder.SBOMConfig{
+			BucketName:  cfg.Builder.SBOMBucket,
+			Format:      cfg.Builder.SBOMFormat,
+			SignerEmail: cfg.Builder.SBOMSigner,
+		},
+		ScanConfig: builder.ScanConfig{
+			Enabled: cfg.Security.ScanImages,
+			FailOn:  cfg.Security.ScanFailOn,
+		},
+		SigningKeyRef: cfg.Security.CosignKeyRef,
+		Attestor:      cfg.Security.BinaryAuthAttestor,
+		Kaniko:        kanikoConfig,
+	}, builder.NewTracker(repo))
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Failed to create build service, git hook builds disabled")
+	} else {
+		engine.SetBuildService(buildService, cfg.Builder.UseKaniko)
+		zlog.Info().Msg("Build service initialized successfully")
+	}
+
+	// Deliver platform events, e.g. approval requests, to the notification webhook
+	engine.SetNotificationWebhook(cfg.Notifications.WebhookURL, cfg.Notifications.WebhookSecret)
+
+	// Attach Cloud Armor policies to the load balancers of deployments that ask for a WAF
+	engine.SetCloudArmor(cfg.Provisioner.GCPProject, cfg.Security.CloudArmorDefaultPolicy)
+
+	// Provision clusters that only run images admitted by the Binary Authorization policy
+	if cfg.Security.BinaryAuthorization {
+		var policy []byte
+		if cfg.Security.BinaryAuthPolicyFile != "" {
+			policy, err = os.ReadFile(cfg.Security.BinaryAuthPolicyFile)
+			if err != nil {
+				zlog.Fatal().Err(err).Msg("Failed to read Binary Authorization policy")
+			}
+		}
+		engine.SetBinaryAuthorization(string(policy))
+	}
+
+	// Put provisioned clusters inside the VPC Service Controls perimeter
+	if cfg.Security.ServicePerimeter != "" {
+		engine.SetServicePerimeter(cfg.Security.ServicePerimeter)
+	}
+
+	// Run Vertical Pod Autoscaling on provisioned clusters in recommendation mode
+	if cfg.Provisioner.EnableVPA {
+		engine.SetVerticalPodAutoscaling()
+	}
 
 	// Create and start worker
-	worker := orchestrator.NewWorker(engine, cfg.Worker.Concurrency, zlog)
+	worker := orchestrator.NewWorker(engine, cfg.Worker.DrainTimeout, zlog)
+
+	// Scale the number of jobs run at once with queue depth
+	pool := orchestrator.NewDynamicConcurrencyManager(jobQueue, orchestrator.ConcurrencyConfig{
+		Initial:       cfg.Worker.Concurrency,
+		Min:           cfg.Worker.MinConcurrency,
+		Max:           max(cfg.Worker.MaxConcurrency, cfg.Worker.Concurrency),
+		HighWaterMark: cfg.Worker.HighWaterMark,
+		LowWaterMark:  cfg.Worker.LowWaterMark,
+	}, zlog)
+
+	cleaner := maintenance.NewCleaner(repo, cfg.LogRetention.RetentionDays, zlog)
 
 	// Create context that listens for interrupt signals
 	workerCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	zlog.Info().
-		Int("concurrency", cfg.Worker.Concurrency).
+		Int("concurrency", pool.Concurrency()).
+		Int("max_concurrency", pool.MaxConcurrency()).
 		Dur("poll_interval", cfg.Worker.PollInterval).
 		Msg("Starting orchestrator worker...")
 
-	// Start worker in goroutine
-	workerErrChan := make(chan error, 1)
+	// Start worker in goroutine; it returns once in-flight jobs have drained
+	workerDone := make(chan error, 1)
 	go func() {
-		if err := worker.Start(workerCtx); err != nil {
-			workerErrChan <- err
-		}
+		workerDone <- worker.Start(workerCtx, pool)
 	}()
 
+	// Register the worker in Redis so the API can list live workers
+	if redisClient != nil {
+		heartbeat := orchestrator.NewWorkerHeartbeat(redisQueue, worker, pool, version, zlog)
+		go heartbeat.Start(workerCtx)
+	}
+
+	// Report draining so the pod can be taken out of rotation while jobs finish
+	var healthServer *http.Server
+	if cfg.Worker.HealthPort != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /healthz/drain", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]bool{"draining": worker.Draining()})
+		})
+		mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			fmt.Fprintf(w, "# HELP deployer_worker_concurrency Jobs the worker may run at once.\n")
+			fmt.Fprintf(w, "# TYPE deployer_worker_concurrency gauge\n")
+			fmt.Fprintf(w, "deployer_worker_concurrency %d\n", pool.Concurrency())
+			fmt.Fprintf(w, "# HELP deployer_worker_max_concurrency Most jobs the worker may be tuned up to.\n")
+			fmt.Fprintf(w, "# TYPE deployer_worker_max_concurrency gauge\n")
+			fmt.Fprintf(w, "deployer_worker_max_concurrency %d\n", pool.MaxConcurrency())
+			fmt.Fprintf(w, "# HELP deployer_worker_queue_depth Queued jobs at the last concurrency check.\n")
+			fmt.Fprintf(w, "# TYPE deployer_worker_queue_depth gauge\n")
+			fmt.Fprintf(w, "deployer_worker_queue_depth %d\n", pool.QueueDepth())
+			fmt.Fprintf(w, "# HELP app_deployer_logs_deleted_total Deployment log entries deleted by retention cleanup.\n")
+			fmt.Fprintf(w, "# TYPE app_deployer_logs_deleted_total counter\n")
+			fmt.Fprintf(w, "app_deployer_logs_deleted_total %d\n", cleaner.LogsDeleted())
+		})
+
+		healthServer = &http.Server{
+			Addr:              ":" + cfg.Worker.HealthPort,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				zlog.Error().Err(err).Str("port", cfg.Worker.HealthPort).Msg("Worker health server failed")
+			}
+		}()
+	}
+
+	// Start periodic drift detection
+	reconciler := orchestrator.NewReconciler(engine, cfg.Worker.ReconcileInterval, zlog)
+	go reconciler.Start(workerCtx)
+
+	// Start stuck deployment recovery
+	watchdog := orchestrator.NewWatchdog(engine, orchestrator.WatchdogConfig{
+		Interval:         cfg.Worker.WatchdogInterval,
+		ProvisionTimeout: cfg.Provisioner.ProvisionTimeout,
+		DeployTimeout:    cfg.Deployer.HelmTimeout + cfg.Deployer.PodTimeout,
+	}, zlog)
+	go watchdog.Start(workerCtx)
+
+	// Start scheduled deployments once they are due
+	scheduler := orchestrator.NewScheduler(engine, zlog)
+	go scheduler.Start(workerCtx)
+
+	// Remind admins weekly of CVE suppressions about to expire
+	suppressionReminder := orchestrator.NewSuppressionReminder(engine, zlog)
+	go suppressionReminder.Start(workerCtx)
+
+	// Rotate or warn of registry credentials that expire within a day
+	credentialRotator := orchestrator.NewCredentialRotator(engine, zlog)
+	go credentialRotator.Start(workerCtx)
+
+	// Notify weekly of platform addon upgrades available to the clusters
+	addonChecker := orchestrator.NewAddonChecker(engine, helmDeployer, zlog)
+	go addonChecker.Start(workerCtx)
+
+	// Delete deployment logs past their retention period daily
+	go cleaner.Start(workerCtx)
+
+	// Record the resources recommended for running deployments nightly
+	if cfg.Provisioner.EnableVPA {
+		vpaRecommender := orchestrator.NewVPARecommender(engine, zlog)
+		go vpaRecommender.Start(workerCtx)
+	}
+
+	// Record incurred infrastructure costs and check them against deployment budgets daily
+	// when a billing export is configured
+	if cfg.Billing.BigQueryDataset != "" {
+		tracker, err := costs.NewTracker(ctx, costs.TrackerConfig{
+			Project: cfg.Billing.BigQueryProject,
+			Dataset: cfg.Billing.BigQueryDataset,
+			Table:   cfg.Billing.BigQueryTable,
+		}, redisQueue)
+		if err != nil {
+			zlog.Warn().Err(err).Msg("Failed to create cost tracker, cost collection disabled")
+		} else {
+			collector := orchestrator.NewCostCollector(engine, tracker, zlog)
+			go collector.Start(workerCtx)
+
+			alerter := costs.NewBudgetAlerter(repo, tracker, orchestrator.NewClient(jobQueue, zlog), cfg.Billing.PauseOverBudget, zlog)
+			go alerter.Start(workerCtx)
+		}
+	}
+
+	// Record cluster egress and alert on deployments over their egress threshold nightly
+	networkMonitor, err := costs.NewNetworkMonitor(ctx, cfg.Provisioner.GCPProject)
+	if err != nil {
+		zlog.Warn().Err(err).Msg("Failed to create network monitor, egress alerts disabled")
+	} else {
+		egressAlerter := costs.NewEgressAlerter(repo, networkMonitor, orchestrator.NewClient(jobQueue, zlog), cfg.Billing.EgressAlertGB, zlog)
+		go egressAlerter.Start(workerCtx)
+	}
+
 	zlog.Info().Msg("Orchestrator worker started successfully, processing jobs...")
 
 	// Wait for interrupt signal or worker error
 	select {
 	case <-workerCtx.Done():
-		zlog.Info().Msg("Received shutdown signal, stopping worker gracefully...")
-	case err := <-workerErrChan:
+		zlog.Info().Dur("drain_timeout", cfg.Worker.DrainTimeout).Msg("Received shutdown signal, draining worker...")
+		if err := <-workerDone; err != nil {
+			zlog.Error().Err(err).Msg("Worker encountered an error")
+		}
+	case err := <-workerDone:
 		zlog.Error().Err(err).Msg("Worker encountered an error")
 	}
 
 	// Graceful shutdown
+	if healthServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := healthServer.Shutdown(shutdownCtx); err != nil {
+			zlog.Error().Err(err).Msg("Worker health server shutdown failed")
+		}
+		cancel()
+	}
 	zlog.Info().Msg("Worker stopped")
 	zlog.Info().Msg("Orchestrator worker shutdown complete")
 }
@@ -0,0 +1,17 @@
+-- Drops every table created by the initial schema, dependents first
+
+DROP TABLE IF EXISTS "cve_suppressions";
+DROP TABLE IF EXISTS "vulnerability_scans";
+DROP TABLE IF EXISTS "git_hooks";
+DROP TABLE IF EXISTS "deployment_approvals";
+DROP TABLE IF EXISTS "resource_policies";
+DROP TABLE IF EXISTS "deployment_events";
+DROP TABLE IF EXISTS "audit_logs";
+DROP TABLE IF EXISTS "deployment_config_maps";
+DROP TABLE IF EXISTS "deployment_env_vars";
+DROP TABLE IF EXISTS "deployment_dependencies";
+DROP TABLE IF EXISTS "federated_deployments";
+DROP TABLE IF EXISTS "deployment_logs";
+DROP TABLE IF EXISTS "builds";
+DROP TABLE IF EXISTS "infrastructures";
+DROP TABLE IF EXISTS "deployments";
@@ -0,0 +1,328 @@
+-- Initial schema, matching what GORM AutoMigrate created before SQL migrations were
+-- introduced. IF NOT EXISTS lets databases created by AutoMigrate adopt it as is.
+
+CREATE TABLE IF NOT EXISTS "deployments" (
+    "id" uuid,
+    "name" text NOT NULL,
+    "app_name" text NOT NULL,
+    "version" text NOT NULL,
+    "status" text NOT NULL,
+    "cloud" text NOT NULL,
+    "region" text NOT NULL,
+    "port" bigint DEFAULT 8080,
+    "infrastructure_id" uuid,
+    "external_ip" text,
+    "external_url" text,
+    "error" text,
+    "image_tag" text,
+    "last_deploy_fingerprint" text,
+    "deployer_type" text DEFAULT 'helm',
+    "repo_url" text,
+    "kustomize_path" text,
+    "cpu_limit" text,
+    "memory_limit" text,
+    "deployment_type" text DEFAULT 'service',
+    "tags" jsonb,
+    "schedule" text,
+    "concurrency_policy" text,
+    "starting_deadline_seconds" bigint,
+    "storage_class" text,
+    "storage_size" text,
+    "storage_mount_path" text,
+    "hooks" text,
+    "smoke_tests" text,
+    "smoke_test_result" jsonb,
+    "workload_identity" boolean,
+    "gcp_service_account_email" text,
+    "cloud_armor_enabled" boolean,
+    "cloud_armor_policy" text,
+    "paused" boolean DEFAULT false,
+    "paused_at" timestamptz,
+    "reconciliation_mode" boolean DEFAULT false,
+    "scheduled_at" timestamptz,
+    "scheduled_job" text,
+    "requires_approval" boolean DEFAULT false,
+    "approvers" jsonb,
+    "cloned_from_id" uuid,
+    "last_progress_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deployed_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_deployments_deleted_at" ON "deployments" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_deployments_last_progress_at" ON "deployments" ("last_progress_at");
+CREATE INDEX IF NOT EXISTS "idx_deployments_cloned_from_id" ON "deployments" ("cloned_from_id");
+CREATE INDEX IF NOT EXISTS "idx_deployments_scheduled_at" ON "deployments" ("scheduled_at");
+CREATE INDEX IF NOT EXISTS "idx_deployments_tags" ON "deployments" USING gin("tags");
+CREATE INDEX IF NOT EXISTS "idx_deployments_infrastructure_id" ON "deployments" ("infrastructure_id");
+CREATE INDEX IF NOT EXISTS "idx_deployments_status" ON "deployments" ("status");
+CREATE INDEX IF NOT EXISTS "idx_deployments_name" ON "deployments" ("name");
+
+CREATE TABLE IF NOT EXISTS "infrastructures" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "cluster_name" text NOT NULL,
+    "namespace" text NOT NULL,
+    "service_name" text,
+    "status" text NOT NULL,
+    "config" jsonb,
+    "pulumi_stack_name" text,
+    "pulumi_project_name" text,
+    "pulumi_backend_url" text,
+    "vpc_name" text,
+    "vpc_network" text,
+    "subnet_name" text,
+    "subnet_c_id_r" text,
+    "router_name" text,
+    "nat_name" text,
+    "cluster_endpoint" text,
+    "cluster_ca_cert" text,
+    "cluster_location" text,
+    "node_pool_name" text,
+    "node_count" bigint DEFAULT 2,
+    "service_account_email" text,
+    "app_service_account_email" text,
+    "vpc_service_perimeter" text,
+    "imported_externally" boolean DEFAULT false,
+    "gcp_project" text,
+    "kube_namespace" text,
+    "helm_release_name" text,
+    "external_ip" text,
+    "waf_policy_name" text,
+    "waf_backend_service" text,
+    "waf_error" text,
+    "adopted_externally" boolean DEFAULT false,
+    "kustomize_manifest" text,
+    "last_kustomize_manifest" text,
+    "pvc_names" jsonb,
+    "hpa_config" jsonb,
+    "database_connection_name" text,
+    "database_host" text,
+    "database_port" bigint,
+    "database_name" text,
+    "database_user" text,
+    "redis_host" text,
+    "redis_port" bigint,
+    "drift_detected" boolean,
+    "drift_details" jsonb,
+    "last_reconciled_at" timestamptz,
+    "helm_values" text,
+    "last_reconcile_status" text,
+    "estimated_monthly_cost_usd" decimal,
+    "cost_updated_at" timestamptz,
+    "last_error" text,
+    "provision_log" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_deployments_infrastructure" FOREIGN KEY ("deployment_id") REFERENCES "deployments"("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_infrastructures_deleted_at" ON "infrastructures" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_infrastructures_pulumi_stack_name" ON "infrastructures" ("pulumi_stack_name");
+CREATE INDEX IF NOT EXISTS "idx_infrastructures_deployment_id" ON "infrastructures" ("deployment_id");
+
+CREATE TABLE IF NOT EXISTS "builds" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "image_tag" text NOT NULL,
+    "status" text NOT NULL,
+    "build_log" text,
+    "cache_key" text,
+    "sbom_path" text,
+    "sbom_format" text,
+    "signature_ref" text,
+    "scan_status" text,
+    "started_at" timestamptz,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_deployments_builds" FOREIGN KEY ("deployment_id") REFERENCES "deployments"("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_builds_deleted_at" ON "builds" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_builds_deployment_id" ON "builds" ("deployment_id");
+
+CREATE TABLE IF NOT EXISTS "deployment_logs" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "phase" text NOT NULL,
+    "level" text NOT NULL,
+    "source" text,
+    "message" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_logs_phase" ON "deployment_logs" ("phase");
+CREATE INDEX IF NOT EXISTS "idx_deployment_logs_deployment_id" ON "deployment_logs" ("deployment_id");
+
+CREATE TABLE IF NOT EXISTS "federated_deployments" (
+    "id" uuid,
+    "name" text NOT NULL,
+    "app_name" text NOT NULL,
+    "version" text NOT NULL,
+    "deployment_ids" jsonb,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_federated_deployments_deleted_at" ON "federated_deployments" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_federated_deployments_name" ON "federated_deployments" ("name");
+
+CREATE TABLE IF NOT EXISTS "deployment_dependencies" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "depends_on_id" uuid NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_dependencies_depends_on_id" ON "deployment_dependencies" ("depends_on_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_deployment_dependency" ON "deployment_dependencies" ("deployment_id","depends_on_id");
+
+CREATE TABLE IF NOT EXISTS "deployment_env_vars" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "key" text NOT NULL,
+    "value" text,
+    "is_secret" boolean DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_deployment_env_var" ON "deployment_env_vars" ("deployment_id","key");
+
+CREATE TABLE IF NOT EXISTS "deployment_config_maps" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "mount_path" text,
+    "data" jsonb,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_deployment_config_map" ON "deployment_config_maps" ("deployment_id","name");
+
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" uuid,
+    "action" text NOT NULL,
+    "deployment_id" uuid,
+    "actor" text,
+    "details" jsonb,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_deployment_id" ON "audit_logs" ("deployment_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+
+CREATE TABLE IF NOT EXISTS "deployment_events" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "type" text NOT NULL,
+    "message" text,
+    "metadata" jsonb,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_events_created_at" ON "deployment_events" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_deployment_events_type" ON "deployment_events" ("type");
+CREATE INDEX IF NOT EXISTS "idx_deployment_events_deployment_id" ON "deployment_events" ("deployment_id");
+
+CREATE TABLE IF NOT EXISTS "resource_policies" (
+    "id" bigserial,
+    "max_cpu_limit" text,
+    "max_memory_limit" text,
+    "max_replicas" bigint,
+    "updated_by" text,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "deployment_approvals" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "requested_by" text,
+    "approvers" jsonb,
+    "status" text NOT NULL,
+    "rollout" text,
+    "decided_by" text,
+    "decided_at" timestamptz,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_approvals_expires_at" ON "deployment_approvals" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_deployment_approvals_status" ON "deployment_approvals" ("status");
+CREATE INDEX IF NOT EXISTS "idx_deployment_approvals_deployment_id" ON "deployment_approvals" ("deployment_id");
+
+CREATE TABLE IF NOT EXISTS "git_hooks" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "provider" text NOT NULL,
+    "repo_url" text NOT NULL,
+    "branch" text NOT NULL DEFAULT 'main',
+    "sub_path" text,
+    "secret" text,
+    "last_commit_sha" text,
+    "last_triggered_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_git_hook_repo" ON "git_hooks" ("provider","repo_url");
+CREATE INDEX IF NOT EXISTS "idx_git_hooks_deployment_id" ON "git_hooks" ("deployment_id");
+
+CREATE TABLE IF NOT EXISTS "vulnerability_scans" (
+    "id" uuid,
+    "build_id" uuid NOT NULL,
+    "deployment_id" uuid NOT NULL,
+    "image_tag" text,
+    "scan_time" timestamptz,
+    "passed" boolean,
+    "critical_count" bigint,
+    "high_count" bigint,
+    "medium_count" bigint,
+    "low_count" bigint,
+    "raw_result" jsonb,
+    "suppressed_count" bigint,
+    "suppressed_vulns" jsonb,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_vulnerability_scans_scan_time" ON "vulnerability_scans" ("scan_time");
+CREATE INDEX IF NOT EXISTS "idx_vulnerability_scans_deployment_id" ON "vulnerability_scans" ("deployment_id");
+CREATE INDEX IF NOT EXISTS "idx_vulnerability_scans_build_id" ON "vulnerability_scans" ("build_id");
+
+CREATE TABLE IF NOT EXISTS "cve_suppressions" (
+    "id" uuid,
+    "cve_id" text NOT NULL,
+    "user_id" text,
+    "reason" text NOT NULL,
+    "deployment_id" uuid,
+    "expires_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_cve_suppressions_expires_at" ON "cve_suppressions" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_cve_suppressions_deployment_id" ON "cve_suppressions" ("deployment_id");
+CREATE INDEX IF NOT EXISTS "idx_cve_suppressions_cve_id" ON "cve_suppressions" ("cve_id");
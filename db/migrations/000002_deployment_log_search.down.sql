@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_deployment_logs_message_tsv";
+
+ALTER TABLE "deployment_logs" DROP COLUMN IF EXISTS "message_tsv";
@@ -0,0 +1,7 @@
+-- Full-text search over deployment log messages
+
+ALTER TABLE "deployment_logs"
+    ADD COLUMN IF NOT EXISTS "message_tsv" tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', coalesce("message", ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_logs_message_tsv" ON "deployment_logs" USING GIN ("message_tsv");
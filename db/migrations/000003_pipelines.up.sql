@@ -0,0 +1,13 @@
+-- Explicit deployment pipelines, one row per deployment holding its latest run
+
+CREATE TABLE IF NOT EXISTS "pipelines" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "stages" jsonb,
+    "current_stage_index" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_pipelines_deployment_id" ON "pipelines" ("deployment_id");
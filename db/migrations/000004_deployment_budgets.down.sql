@@ -0,0 +1,3 @@
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "budget_alert_sent_at";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "budget_alert_percent";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "monthly_budget_usd";
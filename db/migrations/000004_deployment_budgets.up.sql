@@ -0,0 +1,5 @@
+-- Monthly deployment budgets and the alerts sent as spend approaches them
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "monthly_budget_usd" decimal;
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "budget_alert_percent" bigint;
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "budget_alert_sent_at" timestamptz;
@@ -0,0 +1,3 @@
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "vpa_last_recommendation";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "memory_request";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "cpu_request";
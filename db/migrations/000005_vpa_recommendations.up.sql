@@ -0,0 +1,5 @@
+-- Container resource requests and the Vertical Pod Autoscaler recommendations they can be set from
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "cpu_request" text;
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "memory_request" text;
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "vpa_last_recommendation" jsonb;
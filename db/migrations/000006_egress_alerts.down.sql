@@ -0,0 +1,3 @@
+ALTER TABLE "infrastructures" DROP COLUMN IF EXISTS "last_egress_check_at";
+ALTER TABLE "infrastructures" DROP COLUMN IF EXISTS "last_egress_gb";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "egress_alert_gb";
@@ -0,0 +1,5 @@
+-- Per-deployment egress alert thresholds and the egress last measured on each cluster
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "egress_alert_gb" decimal;
+ALTER TABLE "infrastructures" ADD COLUMN IF NOT EXISTS "last_egress_gb" decimal;
+ALTER TABLE "infrastructures" ADD COLUMN IF NOT EXISTS "last_egress_check_at" timestamptz;
@@ -0,0 +1,3 @@
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "pdb_max_unavailable";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "pdb_min_available";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "pdb_enabled";
@@ -0,0 +1,5 @@
+-- Per-deployment PodDisruptionBudget settings
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "pdb_enabled" boolean DEFAULT false;
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "pdb_min_available" bigint;
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "pdb_max_unavailable" bigint;
@@ -0,0 +1,2 @@
+ALTER TABLE "infrastructures" DROP COLUMN IF EXISTS "dedicated_workload";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "node_affinity";
@@ -0,0 +1,4 @@
+-- Node placement of deployment pods and the dedicated node pool of each cluster
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "node_affinity" text;
+ALTER TABLE "infrastructures" ADD COLUMN IF NOT EXISTS "dedicated_workload" text;
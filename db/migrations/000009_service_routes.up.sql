@@ -0,0 +1,14 @@
+-- Istio service routes sending a share of one deployment's in-mesh traffic to another
+
+CREATE TABLE IF NOT EXISTS "service_routes" (
+    "id" uuid,
+    "source_deployment_id" uuid NOT NULL,
+    "target_deployment_id" uuid NOT NULL,
+    "prefix" text NOT NULL,
+    "weight_percent" bigint NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_service_route" ON "service_routes" ("source_deployment_id", "target_deployment_id", "prefix");
+CREATE INDEX IF NOT EXISTS "idx_service_routes_target_deployment_id" ON "service_routes" ("target_deployment_id");
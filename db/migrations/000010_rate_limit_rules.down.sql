@@ -0,0 +1,3 @@
+ALTER TABLE "infrastructures" DROP COLUMN IF EXISTS "rate_limit_rule_name";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "rate_limit_paths";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "rate_limit_requests_per_minute";
@@ -0,0 +1,5 @@
+-- Cloud Armor rate limiting of deployment load balancers
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "rate_limit_requests_per_minute" bigint;
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "rate_limit_paths" jsonb;
+ALTER TABLE "infrastructures" ADD COLUMN IF NOT EXISTS "rate_limit_rule_name" text;
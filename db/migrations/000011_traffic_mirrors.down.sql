@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_deployments_mirror_from";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "mirror_from";
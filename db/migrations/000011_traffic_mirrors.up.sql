@@ -0,0 +1,5 @@
+-- Shadow deployments mirroring another deployment's in-mesh traffic
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "mirror_from" uuid;
+
+CREATE INDEX IF NOT EXISTS "idx_deployments_mirror_from" ON "deployments" ("mirror_from");
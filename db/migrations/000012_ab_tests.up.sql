@@ -0,0 +1,23 @@
+-- Header-based A/B tests between a baseline deployment and a variant on the same cluster
+
+CREATE TABLE IF NOT EXISTS "ab_tests" (
+    "id" uuid,
+    "baseline_deployment_id" uuid NOT NULL,
+    "variant_deployment_id" uuid NOT NULL,
+    "header_name" text NOT NULL,
+    "header_value" text NOT NULL,
+    "routing_weight" bigint NOT NULL,
+    "status" text NOT NULL,
+    "winner_deployment_id" uuid,
+    "baseline_requests" bigint,
+    "baseline_errors" bigint,
+    "variant_requests" bigint,
+    "variant_errors" bigint,
+    "created_at" timestamptz,
+    "concluded_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_ab_tests_baseline_deployment_id" ON "ab_tests" ("baseline_deployment_id");
+CREATE INDEX IF NOT EXISTS "idx_ab_tests_variant_deployment_id" ON "ab_tests" ("variant_deployment_id");
+CREATE INDEX IF NOT EXISTS "idx_ab_tests_status" ON "ab_tests" ("status");
@@ -0,0 +1 @@
+ALTER TABLE "infrastructures" DROP COLUMN IF EXISTS "addon_versions";
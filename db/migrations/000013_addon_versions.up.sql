@@ -0,0 +1,3 @@
+-- Chart versions of the platform addons, such as Istio, last seen running on each cluster
+
+ALTER TABLE "infrastructures" ADD COLUMN IF NOT EXISTS "addon_versions" jsonb;
@@ -0,0 +1,14 @@
+-- Versions of the deployer's Helm chart published to the OCI chart registry
+
+CREATE TABLE IF NOT EXISTS "helm_charts" (
+    "id" uuid,
+    "name" text NOT NULL,
+    "version" text NOT NULL,
+    "registry_url" text NOT NULL,
+    "digest" text,
+    "published_by" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_helm_charts_registry_name_version" ON "helm_charts" ("registry_url", "name", "version");
@@ -0,0 +1,17 @@
+-- Logins to private container registries, with their secrets encrypted
+
+CREATE TABLE IF NOT EXISTS "registry_credentials" (
+    "id" uuid,
+    "registry_url" text NOT NULL,
+    "username" text NOT NULL,
+    "secret" text NOT NULL,
+    "expires_at" timestamptz,
+    "user_id" text,
+    "rotated_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_registry_credentials_registry_url" ON "registry_credentials" ("registry_url");
+CREATE INDEX IF NOT EXISTS "idx_registry_credentials_expires_at" ON "registry_credentials" ("expires_at");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "deployment_ownership_histories";
+DROP INDEX IF EXISTS "idx_deployments_user_id";
+ALTER TABLE "deployments" DROP COLUMN IF EXISTS "user_id";
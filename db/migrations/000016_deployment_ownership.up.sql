@@ -0,0 +1,18 @@
+-- Deployment owners and the history of ownership transfers
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "user_id" text;
+
+CREATE INDEX IF NOT EXISTS "idx_deployments_user_id" ON "deployments" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "deployment_ownership_histories" (
+    "id" uuid,
+    "deployment_id" uuid NOT NULL,
+    "previous_owner" text,
+    "new_owner" text NOT NULL,
+    "transferred_by" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_deployment_ownership_histories_deployment_id" ON "deployment_ownership_histories" ("deployment_id");
+CREATE INDEX IF NOT EXISTS "idx_deployment_ownership_histories_new_owner" ON "deployment_ownership_histories" ("new_owner");
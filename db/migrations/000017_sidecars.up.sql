@@ -0,0 +1,3 @@
+-- Sidecar containers run in each deployment's pods
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "sidecars" text;
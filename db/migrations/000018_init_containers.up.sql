@@ -0,0 +1,3 @@
+-- Init containers run before the app starts in each deployment's pods
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "init_containers" text;
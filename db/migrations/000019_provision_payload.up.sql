@@ -0,0 +1,3 @@
+-- Provision payload of the last provision job, kept to resume stuck pipelines
+
+ALTER TABLE "deployments" ADD COLUMN IF NOT EXISTS "provision_payload" text;
@@ -0,0 +1,2 @@
+-- The cleared token prefixes cannot be restored
+SELECT 1;
@@ -0,0 +1,9 @@
+-- Owners were recorded as bearer token prefixes, which anyone reading a deployment could
+-- present as their own token. Owners are now user IDs assigned by admins, so the prefixes
+-- are cleared, along with those recorded in the ownership history.
+
+UPDATE "deployments" SET "user_id" = '' WHERE "user_id" LIKE 'key:%' OR "user_id" LIKE 'ip:%';
+
+UPDATE "deployment_ownership_histories" SET "previous_owner" = '' WHERE "previous_owner" LIKE 'key:%' OR "previous_owner" LIKE 'ip:%';
+UPDATE "deployment_ownership_histories" SET "new_owner" = '' WHERE "new_owner" LIKE 'key:%' OR "new_owner" LIKE 'ip:%';
+UPDATE "deployment_ownership_histories" SET "transferred_by" = '' WHERE "transferred_by" LIKE 'key:%';
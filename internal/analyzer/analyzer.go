@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/rs/zerolog/log"
 )
 
+// monorepoServicesDir is the root directory whose subdirectories are a monorepo's services
+const monorepoServicesDir = "services"
+
 // Analyzer analyzes source code to detect language, framework, and dependencies
 type Analyzer struct {
 	languageDetector  *LanguageDetector
@@ -27,6 +31,18 @@ func New() *Analyzer {
 
 // Analyze analyzes a directory of source code
 func (a *Analyzer) Analyze(path string) (*AnalysisResult, error) {
+	return a.AnalyzeWithOptions(path, AnalyzeOptions{})
+}
+
+// AnalyzeWithOptions analyzes a directory of source code, or only its SubPath when set
+func (a *Analyzer) AnalyzeWithOptions(path string, opts AnalyzeOptions) (*AnalysisResult, error) {
+	if opts.SubPath != "" {
+		if err := ValidateSubPath(opts.SubPath); err != nil {
+			return nil, err
+		}
+		path = filepath.Join(path, opts.SubPath)
+	}
+
 	log.Info().Str("path", path).Msg("Starting source code analysis")
 
 	// Check if path exists
@@ -81,19 +97,88 @@ func (a *Analyzer) Analyze(path string) (*AnalysisResult, error) {
 		result.Port = buildInfo.Port
 	}
 
+	switch language {
+	case LanguagePython:
+		a.applyPythonServer(path, files, result)
+	case LanguageDotNet:
+		result.Framework = a.frameworkDetector.DotNetFramework(path, files)
+	case LanguageElixir:
+		result.Framework = a.frameworkDetector.ElixirFramework(path)
+	}
+
 	// Check for Dockerfile
 	result.HasDockerfile = a.hasDockerfile(files)
 
+	// A repository root may hold several services, each built from its own directory
+	if opts.SubPath == "" {
+		result.Services = a.detectServices(files)
+		result.IsMonorepo = len(result.Services) > 0
+	}
+
 	log.Info().
 		Str("language", string(result.Language)).
 		Str("framework", string(result.Framework)).
 		Str("build_tool", string(result.BuildTool)).
 		Bool("has_dockerfile", result.HasDockerfile).
+		Strs("services", result.Services).
 		Msg("Analysis complete")
 
 	return result, nil
 }
 
+// applyPythonServer serves Django apps with gunicorn and FastAPI apps with uvicorn, replacing
+// the start command and port found from the project's dependency files
+func (a *Analyzer) applyPythonServer(path string, files []FileInfo, result *AnalysisResult) {
+	if project := a.frameworkDetector.DjangoProject(files); project != "" {
+		result.Framework = FrameworkDjango
+		result.StartCommand = fmt.Sprintf("gunicorn %s.wsgi:application --bind 0.0.0.0:8000", project)
+		result.Port = 8000
+		return
+	}
+
+	if app := a.frameworkDetector.FastAPIApp(path, files); app != "" {
+		result.Framework = FrameworkFastAPI
+		result.StartCommand = fmt.Sprintf("uvicorn %s --host 0.0.0.0 --port 8000", app)
+		result.Port = 8000
+	}
+}
+
+// ValidateSubPath checks that a service directory stays within the source it is part of
+func ValidateSubPath(subPath string) error {
+	if !filepath.IsLocal(subPath) {
+		return fmt.Errorf("sub path must be a relative path within the source: %s", subPath)
+	}
+	return nil
+}
+
+// detectServices lists the directories under services/ that hold a project of their own, found
+// by a language key file or a Dockerfile at their root
+func (a *Analyzer) detectServices(files []FileInfo) []string {
+	seen := make(map[string]bool)
+	for _, file := range files {
+		parts := strings.Split(filepath.ToSlash(file.Path), "/")
+		if len(parts) != 3 || parts[0] != monorepoServicesDir || file.IsDirectory {
+			continue
+		}
+
+		if a.languageDetector.IsKeyFile(file.Name) || a.hasDockerfile([]FileInfo{file}) {
+			seen[parts[0]+"/"+parts[1]] = true
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil
+	}
+
+	services := make([]string, 0, len(seen))
+	for service := range seen {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	return services
+}
+
 // scanDirectory scans a directory and returns file information
 func (a *Analyzer) scanDirectory(path string) ([]FileInfo, error) {
 	var files []FileInfo
@@ -113,7 +198,7 @@ func (a *Analyzer) scanDirectory(path string) ([]FileInfo, error) {
 
 		// Skip common directories to ignore
 		if info.IsDir() {
-			ignored := []string{"node_modules", "vendor", "venv", ".git", "dist", "build", "target", "__pycache__"}
+			ignored := []string{"node_modules", "vendor", "venv", ".git", "dist", "build", "target", "__pycache__", "deps", "_build"}
 			for _, dir := range ignored {
 				if info.Name() == dir {
 					return filepath.SkipDir
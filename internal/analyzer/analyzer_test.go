@@ -212,3 +212,601 @@ func TestDependencyParser_ParseNodeJS(t *testing.T) {
 		t.Error("Expected express in dependencies")
 	}
 }
+
+func TestDependencyParser_ParseRust(t *testing.T) {
+	// Create a temporary directory for testing
+	tempDir, err := os.MkdirTemp("", "test-rust-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Create a Cargo.toml for a binary crate
+	cargoTOML := `[package]
+name = "hello-api"
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+axum = "0.7"
+tokio = { version = "1.36", features = ["full"] }
+shared = { path = "../shared" }
+`
+
+	if err := os.WriteFile(filepath.Join(tempDir, "Cargo.toml"), []byte(cargoTOML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(tempDir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "src", "main.rs"), []byte("fn main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := New().Analyze(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if analysis.Language != LanguageRust {
+		t.Errorf("Expected language Rust, got %s", analysis.Language)
+	}
+
+	if analysis.BuildTool != BuildToolCargo {
+		t.Errorf("Expected build tool cargo, got %s", analysis.BuildTool)
+	}
+
+	if analysis.Runtime != "1.75" {
+		t.Errorf("Expected runtime 1.75, got %s", analysis.Runtime)
+	}
+
+	if analysis.Port != 8080 {
+		t.Errorf("Expected port 8080, got %d", analysis.Port)
+	}
+
+	if analysis.StartCommand != "./hello-api" {
+		t.Errorf("Expected start command ./hello-api, got %s", analysis.StartCommand)
+	}
+
+	expected := map[string]string{"axum": "0.7", "tokio": "1.36", "shared": "*"}
+	for name, version := range expected {
+		if analysis.Dependencies[name] != version {
+			t.Errorf("Expected dependency %s %s, got %q", name, version, analysis.Dependencies[name])
+		}
+	}
+}
+
+func TestDependencyParser_ParseRustToolchain(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		runtime  string
+		start    string
+	}{
+		{
+			name:     "2024 edition",
+			manifest: "[package]\nname = \"app\"\nedition = \"2024\"\n\n[[bin]]\nname = \"server\"\npath = \"src/server.rs\"\n",
+			runtime:  "1.85",
+			start:    "./server",
+		},
+		{
+			name:     "rust-version above edition minimum",
+			manifest: "[package]\nname = \"app\"\nedition = \"2021\"\nrust-version = \"1.80.1\"\n",
+			runtime:  "1.80",
+		},
+		{
+			name:     "library crate",
+			manifest: "[package]\nname = \"parser\"\nedition = \"2018\"\n\n[lib]\npath = \"src/lib.rs\"\n",
+			runtime:  "1.75",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tempDir, "Cargo.toml"), []byte(tt.manifest), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			buildInfo, err := NewDependencyParser().parseRust(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if buildInfo.Runtime != tt.runtime {
+				t.Errorf("Expected runtime %s, got %s", tt.runtime, buildInfo.Runtime)
+			}
+
+			if buildInfo.StartCommand != tt.start {
+				t.Errorf("Expected start command %q, got %q", tt.start, buildInfo.StartCommand)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_DetectPHPFramework(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		framework Framework
+		start     string
+	}{
+		{
+			name: "laravel",
+			files: map[string]string{
+				"composer.json":     `{"require": {"php": "^8.2", "laravel/framework": "^11.0"}}`,
+				"artisan":           "#!/usr/bin/env php\n",
+				"bootstrap/app.php": "<?php\n",
+				"public/index.php":  "<?php\n",
+			},
+			framework: FrameworkLaravel,
+			start:     "php -S 0.0.0.0:8080 -t public",
+		},
+		{
+			name: "symfony",
+			files: map[string]string{
+				"composer.json":    `{"require": {"php": ">=8.2", "symfony/framework-bundle": "7.1.*"}}`,
+				"bin/console":      "#!/usr/bin/env php\n",
+				"public/index.php": "<?php\n",
+			},
+			framework: FrameworkSymfony,
+			start:     "php -S 0.0.0.0:8080 -t public",
+		},
+		{
+			name: "plain cli",
+			files: map[string]string{
+				"composer.json": `{"require": {"php": "^7.4|^8.2"}}`,
+				"app.php":       "<?php\n",
+			},
+			framework: FrameworkUnknown,
+			start:     "php app.php",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(tempDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			analysis, err := New().Analyze(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if analysis.Language != LanguagePHP {
+				t.Errorf("Expected language PHP, got %s", analysis.Language)
+			}
+
+			if analysis.Framework != tt.framework {
+				t.Errorf("Expected framework %s, got %s", tt.framework, analysis.Framework)
+			}
+
+			if analysis.Runtime != "8.2" {
+				t.Errorf("Expected runtime 8.2, got %s", analysis.Runtime)
+			}
+
+			if analysis.StartCommand != tt.start {
+				t.Errorf("Expected start command %q, got %q", tt.start, analysis.StartCommand)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_DetectPythonServer(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		framework Framework
+		start     string
+	}{
+		{
+			name: "django",
+			files: map[string]string{
+				"requirements.txt":   "Django==5.0.4\ngunicorn==22.0.0\n",
+				"manage.py":          "#!/usr/bin/env python\n",
+				"mysite/__init__.py": "",
+				"mysite/settings.py": "DEBUG = False\n",
+				"mysite/wsgi.py":     "application = get_wsgi_application()\n",
+				"blog/views.py":      "from django.http import HttpResponse\n",
+			},
+			framework: FrameworkDjango,
+			start:     "gunicorn mysite.wsgi:application --bind 0.0.0.0:8000",
+		},
+		{
+			name: "fastapi",
+			files: map[string]string{
+				"requirements.txt": "fastapi==0.110.0\nuvicorn[standard]\n",
+				"app/__init__.py":  "",
+				"app/routes.py":    "from fastapi import APIRouter\n",
+				"app/main.py":      "from fastapi import FastAPI\n\napi = FastAPI()\n",
+			},
+			framework: FrameworkFastAPI,
+			start:     "uvicorn app.main:api --host 0.0.0.0 --port 8000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(tempDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			analysis, err := New().Analyze(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if analysis.Framework != tt.framework {
+				t.Errorf("Expected framework %s, got %s", tt.framework, analysis.Framework)
+			}
+
+			if analysis.StartCommand != tt.start {
+				t.Errorf("Expected start command %q, got %q", tt.start, analysis.StartCommand)
+			}
+
+			if analysis.Port != 8000 {
+				t.Errorf("Expected port 8000, got %d", analysis.Port)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_DetectMonorepo(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"README.md":                      "# platform\n",
+		"services/auth/go.mod":           "module example.com/auth\n\ngo 1.22\n",
+		"services/auth/main.go":          "package main\n",
+		"services/payments/package.json": `{"name": "payments", "dependencies": {"express": "^4.18.0"}}`,
+		"services/payments/index.js":     "require('express')\n",
+		"services/docs/README.md":        "# docs\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analyzer := New()
+
+	root, err := analyzer.Analyze(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !root.IsMonorepo {
+		t.Error("Expected root to be detected as a monorepo")
+	}
+
+	expected := []string{"services/auth", "services/payments"}
+	if len(root.Services) != len(expected) {
+		t.Fatalf("Expected services %v, got %v", expected, root.Services)
+	}
+	for i, service := range expected {
+		if root.Services[i] != service {
+			t.Errorf("Expected service %s, got %s", service, root.Services[i])
+		}
+	}
+
+	service, err := analyzer.AnalyzeWithOptions(tempDir, AnalyzeOptions{SubPath: "services/payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if service.Language != LanguageNodeJS {
+		t.Errorf("Expected language NodeJS, got %s", service.Language)
+	}
+
+	if service.IsMonorepo {
+		t.Error("Expected a service not to be reported as a monorepo")
+	}
+
+	if _, err := analyzer.AnalyzeWithOptions(tempDir, AnalyzeOptions{SubPath: "../outside"}); err == nil {
+		t.Error("Expected an error for a sub path outside the source")
+	}
+}
+
+func TestAnalyzer_DetectDotNetFramework(t *testing.T) {
+	tests := []struct {
+		name      string
+		csproj    string
+		dirs      []string
+		framework Framework
+		runtime   string
+		port      int
+	}{
+		{
+			name: "aspnetcore",
+			csproj: `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Swashbuckle.AspNetCore" Version="6.5.0" />
+  </ItemGroup>
+</Project>`,
+			framework: FrameworkASPNetCore,
+			runtime:   "8.0",
+			port:      8080,
+		},
+		{
+			name: "console",
+			csproj: `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <OutputType>Exe</OutputType>
+    <TargetFrameworks>net6.0;net9.0</TargetFrameworks>
+  </PropertyGroup>
+</Project>`,
+			framework: FrameworkConsole,
+			runtime:   "9.0",
+			port:      0,
+		},
+		{
+			name: "blazor from published output",
+			csproj: `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+</Project>`,
+			dirs:      []string{"bin/Release/net8.0/publish/wwwroot/_framework"},
+			framework: FrameworkBlazor,
+			runtime:   "8.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tempDir, "Api.csproj"), []byte(tt.csproj), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(tempDir, "Program.cs"), []byte("Console.WriteLine(\"hi\");\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			for _, dir := range tt.dirs {
+				if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			analysis, err := New().Analyze(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if analysis.Language != LanguageDotNet {
+				t.Errorf("Expected language .NET, got %s", analysis.Language)
+			}
+
+			if analysis.Framework != tt.framework {
+				t.Errorf("Expected framework %s, got %s", tt.framework, analysis.Framework)
+			}
+
+			if analysis.Runtime != tt.runtime {
+				t.Errorf("Expected runtime %s, got %s", tt.runtime, analysis.Runtime)
+			}
+
+			if analysis.StartCommand != "dotnet Api.dll" {
+				t.Errorf("Expected start command dotnet Api.dll, got %s", analysis.StartCommand)
+			}
+
+			if tt.framework != FrameworkBlazor && analysis.Port != tt.port {
+				t.Errorf("Expected port %d, got %d", tt.port, analysis.Port)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_DetectDeno(t *testing.T) {
+	server := `Deno.serve({ port: 8000 }, (_req) => new Response("Hello from Deno"));
+`
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		start string
+		deps  map[string]string
+	}{
+		{
+			name: "deno.json start task",
+			files: map[string]string{
+				"deno.json": `{
+  "tasks": { "start": "deno run --allow-net --allow-env server.ts" },
+  "imports": { "@std/http": "jsr:@std/http@^1.0.0" }
+}`,
+				"server.ts": server,
+			},
+			start: "deno run --allow-net --allow-env server.ts",
+			deps:  map[string]string{"@std/http": "jsr:@std/http@^1.0.0"},
+		},
+		{
+			name: "deno.jsonc with comments and a shell task",
+			files: map[string]string{
+				"deno.jsonc": `{
+  // Started through deno task, which runs the command in its own shell
+  "tasks": {
+    "start": "deno run -A main.ts && echo done", /* trailing comma */
+  },
+}`,
+				"main.ts": server,
+			},
+			start: "deno task start",
+		},
+		{
+			name: "deps.ts without configuration",
+			files: map[string]string{
+				"deps.ts": `export { serve } from "https://deno.land/std@0.200.0/http/server.ts";
+`,
+				"main.ts": server,
+			},
+			start: "deno run --allow-net main.ts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			analysis, err := New().Analyze(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if analysis.Language != LanguageDeno {
+				t.Errorf("Expected language Deno, got %s", analysis.Language)
+			}
+
+			if analysis.BuildTool != BuildToolDeno {
+				t.Errorf("Expected build tool deno, got %s", analysis.BuildTool)
+			}
+
+			if analysis.Port != 8000 {
+				t.Errorf("Expected port 8000, got %d", analysis.Port)
+			}
+
+			if analysis.StartCommand != tt.start {
+				t.Errorf("Expected start command %q, got %q", tt.start, analysis.StartCommand)
+			}
+
+			for name, specifier := range tt.deps {
+				if analysis.Dependencies[name] != specifier {
+					t.Errorf("Expected dependency %s %s, got %q", name, specifier, analysis.Dependencies[name])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzer_DetectElixir(t *testing.T) {
+	phoenixMix := `defmodule Hello.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      app: :hello,
+      version: "0.1.0",
+      elixir: "~> 1.15",
+      deps: deps()
+    ]
+  end
+
+  defp deps do
+    [
+      {:phoenix, "~> 1.7.10"},
+      {:jason, "~> 1.2"}
+    ]
+  end
+end
+`
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		framework Framework
+		runtime   string
+		start     string
+		port      int
+	}{
+		{
+			name: "phoenix release with bin/server",
+			files: map[string]string{
+				"mix.exs":             phoenixMix,
+				"assets/package.json": `{"name": "assets"}`,
+				"config/runtime.exs": `import Config
+
+if System.get_env("PHX_SERVER") do
+  config :hello, HelloWeb.Endpoint, server: true
+end
+
+port = String.to_integer(System.get_env("PORT") || "4040")
+`,
+				"rel/overlays/bin/server": "#!/bin/sh\nPHX_SERVER=true exec ./hello start\n",
+				"lib/hello.ex":            "defmodule Hello do\nend\n",
+			},
+			framework: FrameworkPhoenix,
+			runtime:   "1.15",
+			start:     "./bin/server",
+			port:      4040,
+		},
+		{
+			name: "plain mix project",
+			files: map[string]string{
+				"mix.exs":       "defmodule Worker.MixProject do\n  def project do\n    [app: :worker, elixir: \">= 1.14.0\"]\n  end\nend\n",
+				"lib/worker.ex": "defmodule Worker do\nend\n",
+			},
+			framework: FrameworkUnknown,
+			runtime:   "1.14",
+			start:     "./bin/worker start",
+			port:      4000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(tempDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			analysis, err := New().Analyze(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if analysis.Language != LanguageElixir {
+				t.Errorf("Expected language Elixir, got %s", analysis.Language)
+			}
+
+			if analysis.Framework != tt.framework {
+				t.Errorf("Expected framework %s, got %s", tt.framework, analysis.Framework)
+			}
+
+			if analysis.BuildTool != BuildToolMix {
+				t.Errorf("Expected build tool mix, got %s", analysis.BuildTool)
+			}
+
+			if analysis.Runtime != tt.runtime {
+				t.Errorf("Expected runtime %s, got %s", tt.runtime, analysis.Runtime)
+			}
+
+			if analysis.StartCommand != tt.start {
+				t.Errorf("Expected start command %q, got %q", tt.start, analysis.StartCommand)
+			}
+
+			if analysis.Port != tt.port {
+				t.Errorf("Expected port %d, got %d", tt.port, analysis.Port)
+			}
+		})
+	}
+}
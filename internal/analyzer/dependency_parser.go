@@ -2,13 +2,21 @@ package analyzer
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/pelletier/go-toml/v2"
 	"github.com/rs/zerolog/log"
 )
 
+// phpVersionPattern finds a major.minor version in a composer PHP constraint such as ^8.2
+var phpVersionPattern = regexp.MustCompile(`\d+\.\d+`)
+
 // BuildInfo contains build and runtime information
 type BuildInfo struct {
 	BuildTool       BuildTool
@@ -39,6 +47,16 @@ func (dp *DependencyParser) Parse(basePath string, language Language) (*BuildInf
 		return dp.parsePython(basePath)
 	case LanguageJava:
 		return dp.parseJava(basePath)
+	case LanguageRust:
+		return dp.parseRust(basePath)
+	case LanguagePHP:
+		return dp.parsePHP(basePath)
+	case LanguageDotNet:
+		return dp.parseDotNet(basePath)
+	case LanguageDeno:
+		return dp.parseDeno(basePath)
+	case LanguageElixir:
+		return dp.parseElixir(basePath)
 	default:
 		return &BuildInfo{
 			BuildTool: BuildToolUnknown,
@@ -232,3 +250,483 @@ func (dp *DependencyParser) parseJava(basePath string) (*BuildInfo, error) {
 	info.BuildTool = BuildToolUnknown
 	return info, nil
 }
+
+// parsePHP parses PHP project files
+func (dp *DependencyParser) parsePHP(basePath string) (*BuildInfo, error) {
+	info := &BuildInfo{
+		BuildTool:    BuildToolComposer,
+		Runtime:      "8.3",
+		Dependencies: make(map[string]string),
+		BuildCommand: "composer install --no-dev --optimize-autoloader",
+		Port:         8080,
+	}
+
+	// Web apps are served from their front controller, CLI apps run their entry script.
+	// Laravel and Symfony apps are served by nginx and php-fpm instead.
+	entrypoints := []struct {
+		file    string
+		command string
+	}{
+		{filepath.Join("public", "index.php"), "php -S 0.0.0.0:8080 -t public"},
+		{"index.php", "php -S 0.0.0.0:8080 -t ."},
+		{"app.php", "php app.php"},
+		{"main.php", "php main.php"},
+	}
+	for _, entrypoint := range entrypoints {
+		if _, err := os.Stat(filepath.Join(basePath, entrypoint.file)); err == nil {
+			info.StartCommand = entrypoint.command
+			break
+		}
+	}
+
+	// Check for composer.json
+	composerPath := filepath.Join(basePath, "composer.json")
+	data, err := os.ReadFile(composerPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read composer.json")
+		return info, nil
+	}
+
+	var composer struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+
+	if err := json.Unmarshal(data, &composer); err != nil {
+		log.Warn().Err(err).Msg("Failed to parse composer.json")
+		return info, nil
+	}
+
+	for name, version := range composer.Require {
+		// The php constraint selects the runtime rather than a package. Alternatives such as
+		// ^7.3|^8.0 are listed oldest first, so the newest is used.
+		if name == "php" {
+			alternatives := strings.Split(version, "|")
+			if v := phpVersionPattern.FindString(alternatives[len(alternatives)-1]); v != "" {
+				info.Runtime = v
+			}
+			continue
+		}
+		info.Dependencies[name] = version
+	}
+	info.DevDependencies = composer.RequireDev
+
+	return info, nil
+}
+
+// csProject is the part of an SDK-style .csproj file used to analyze .NET projects
+type csProject struct {
+	Sdk            string `xml:"Sdk,attr"`
+	PropertyGroups []struct {
+		TargetFramework  string `xml:"TargetFramework"`
+		TargetFrameworks string `xml:"TargetFrameworks"`
+		AssemblyName     string `xml:"AssemblyName"`
+	} `xml:"PropertyGroup"`
+	ItemGroups []struct {
+		PackageReferences []struct {
+			Include string `xml:"Include,attr"`
+			Version string `xml:"Version,attr"`
+		} `xml:"PackageReference"`
+	} `xml:"ItemGroup"`
+
+	// Name is the project file's name without its extension, the default assembly name
+	Name string `xml:"-"`
+}
+
+// readCSProject reads the .csproj file at the root of a .NET project, taking the first in
+// name order when there are several
+func readCSProject(basePath string) (*csProject, error) {
+	matches, err := filepath.Glob(filepath.Join(basePath, "*.csproj"))
+	if err != nil || len(matches) == 0 {
+		return nil, fmt.Errorf("no .csproj file in %s", basePath)
+	}
+
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(matches[0]), err)
+	}
+
+	var project csProject
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(matches[0]), err)
+	}
+	project.Name = strings.TrimSuffix(filepath.Base(matches[0]), ".csproj")
+
+	return &project, nil
+}
+
+// parseDotNet parses .NET project files
+func (dp *DependencyParser) parseDotNet(basePath string) (*BuildInfo, error) {
+	info := &BuildInfo{
+		BuildTool:    BuildToolDotNet,
+		Runtime:      "8.0",
+		Dependencies: make(map[string]string),
+		BuildCommand: "dotnet publish -c Release -o out",
+		StartCommand: "dotnet app.dll",
+		Port:         8080,
+	}
+
+	project, err := readCSProject(basePath)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read .csproj")
+		return info, nil
+	}
+
+	assembly := project.Name
+	for _, group := range project.PropertyGroups {
+		if group.AssemblyName != "" {
+			assembly = group.AssemblyName
+		}
+
+		// Multi-targeted projects list frameworks oldest first, the newest is built
+		targets := strings.Split(group.TargetFrameworks, ";")
+		target := group.TargetFramework
+		if target == "" {
+			target = strings.TrimSpace(targets[len(targets)-1])
+		}
+		if version := dotNetVersion(target); version != "" {
+			info.Runtime = version
+		}
+	}
+	info.StartCommand = "dotnet " + assembly + ".dll"
+
+	for _, group := range project.ItemGroups {
+		for _, ref := range group.PackageReferences {
+			info.Dependencies[ref.Include] = ref.Version
+		}
+	}
+
+	// Only web projects listen for requests
+	if project.Sdk != "Microsoft.NET.Sdk.Web" && project.Sdk != "Microsoft.NET.Sdk.BlazorWebAssembly" {
+		info.Port = 0
+	}
+
+	return info, nil
+}
+
+// dotNetVersion returns the runtime version of a target framework moniker, e.g. 8.0 for
+// net8.0 or net8.0-windows and 3.1 for netcoreapp3.1. .NET Framework and .NET Standard
+// monikers, which have no runtime image, return "".
+func dotNetVersion(target string) string {
+	target, _, _ = strings.Cut(strings.ToLower(target), "-")
+	version, ok := strings.CutPrefix(target, "netcoreapp")
+	if !ok {
+		version, ok = strings.CutPrefix(target, "net")
+	}
+	if !ok || !strings.Contains(version, ".") {
+		return ""
+	}
+	return version
+}
+
+// parseRust parses Rust project files
+func (dp *DependencyParser) parseRust(basePath string) (*BuildInfo, error) {
+	info := &BuildInfo{
+		BuildTool:    BuildToolCargo,
+		Runtime:      "1.75",
+		Dependencies: make(map[string]string),
+		BuildCommand: "cargo build --release",
+		Port:         8080,
+	}
+
+	// Check for Cargo.toml
+	cargoPath := filepath.Join(basePath, "Cargo.toml")
+	data, err := os.ReadFile(cargoPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read Cargo.toml")
+		return info, nil
+	}
+
+	var manifest struct {
+		Package struct {
+			Name        string `toml:"name"`
+			Edition     string `toml:"edition"`
+			RustVersion string `toml:"rust-version"`
+		} `toml:"package"`
+		Bin []struct {
+			Name string `toml:"name"`
+		} `toml:"bin"`
+		Dependencies    map[string]interface{} `toml:"dependencies"`
+		DevDependencies map[string]interface{} `toml:"dev-dependencies"`
+	}
+
+	if err := toml.Unmarshal(data, &manifest); err != nil {
+		log.Warn().Err(err).Msg("Failed to parse Cargo.toml")
+		return info, nil
+	}
+
+	info.Dependencies = cargoDependencies(manifest.Dependencies)
+	info.DevDependencies = cargoDependencies(manifest.DevDependencies)
+
+	// The 2024 edition needs Rust 1.85; a declared rust-version can only raise the toolchain
+	if manifest.Package.Edition == "2024" {
+		info.Runtime = "1.85"
+	}
+	if rustMinorVersion(manifest.Package.RustVersion) > rustMinorVersion(info.Runtime) {
+		info.Runtime = rustToolchain(manifest.Package.RustVersion)
+	}
+
+	// Binary crates build an executable named after the first [[bin]] target, or the
+	// package for src/main.rs; library crates have nothing to run
+	binary := ""
+	if len(manifest.Bin) > 0 && manifest.Bin[0].Name != "" {
+		binary = manifest.Bin[0].Name
+	} else if _, err := os.Stat(filepath.Join(basePath, "src", "main.rs")); err == nil {
+		binary = manifest.Package.Name
+	}
+
+	if binary == "" {
+		log.Warn().Str("crate", manifest.Package.Name).Msg("Rust crate has no binary target")
+		return info, nil
+	}
+
+	info.StartCommand = "./" + binary
+
+	return info, nil
+}
+
+// cargoDependencies flattens Cargo dependencies, which are either a version string or a
+// table that may hold one, into name-version pairs
+func cargoDependencies(deps map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(deps))
+	for name, spec := range deps {
+		version := "*"
+		switch v := spec.(type) {
+		case string:
+			version = v
+		case map[string]interface{}:
+			if s, ok := v["version"].(string); ok {
+				version = s
+			}
+		}
+		result[name] = version
+	}
+	return result
+}
+
+// rustMinorVersion returns the minor version of a Rust version such as 1.80 or 1.80.1,
+// or 0 when it cannot be parsed
+func rustMinorVersion(version string) int {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || parts[0] != "1" {
+		return 0
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0
+	}
+	return minor
+}
+
+// rustToolchain trims a Rust version to the major.minor tag of its toolchain image
+func rustToolchain(version string) string {
+	parts := strings.Split(version, ".")
+	return parts[0] + "." + parts[1]
+}
+
+// denoEntrypoints are the module names a Deno app is conventionally started from, in the
+// order they are looked for
+var denoEntrypoints = []string{"main.ts", "main.js", "server.ts", "mod.ts"}
+
+// parseDeno parses Deno project files. The start command is the start task of deno.json or
+// deno.jsonc, run directly when it is a plain deno command and with deno task otherwise.
+func (dp *DependencyParser) parseDeno(basePath string) (*BuildInfo, error) {
+	entrypoint := denoEntrypoints[0]
+	for _, name := range denoEntrypoints {
+		if _, err := os.Stat(filepath.Join(basePath, name)); err == nil {
+			entrypoint = name
+			break
+		}
+	}
+
+	info := &BuildInfo{
+		BuildTool:    BuildToolDeno,
+		Runtime:      "deno",
+		Dependencies: make(map[string]string),
+		StartCommand: "deno run --allow-net " + entrypoint,
+		Port:         8000,
+	}
+
+	var data []byte
+	for _, name := range []string{"deno.json", "deno.jsonc"} {
+		if content, err := os.ReadFile(filepath.Join(basePath, name)); err == nil {
+			data = content
+			break
+		}
+	}
+	if data == nil {
+		// deps.ts projects have no configuration to read
+		return info, nil
+	}
+
+	var config struct {
+		Tasks   map[string]string `json:"tasks"`
+		Imports map[string]string `json:"imports"`
+	}
+
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		log.Warn().Err(err).Msg("Failed to parse deno.json")
+		return info, nil
+	}
+
+	for name, specifier := range config.Imports {
+		info.Dependencies[name] = specifier
+	}
+
+	if start := strings.TrimSpace(config.Tasks["start"]); start != "" {
+		if strings.HasPrefix(start, "deno ") && !strings.ContainsAny(start, "&|;<>$`'\"") {
+			info.StartCommand = start
+		} else {
+			info.StartCommand = "deno task start"
+		}
+	}
+
+	return info, nil
+}
+
+// stripJSONComments removes the comments and trailing commas JSONC allows, leaving strings
+// untouched, so the result can be decoded as JSON
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			// Drop a comma left before the closing bracket
+			end := len(out)
+			for end > 0 && strings.ContainsRune(" \t\r\n", rune(out[end-1])) {
+				end--
+			}
+			if end > 0 && out[end-1] == ',' {
+				out = append(out[:end-1], out[end:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// defaultElixirRuntime is the Elixir version of apps that do not require one
+const defaultElixirRuntime = "1.17"
+
+var (
+	// mixAppPattern captures the app name of a mix.exs project, e.g. app: :my_app
+	mixAppPattern = regexp.MustCompile(`\bapp:\s*:(\w+)`)
+
+	// mixElixirPattern captures the Elixir version requirement of a mix.exs project, e.g.
+	// elixir: "~> 1.15"
+	mixElixirPattern = regexp.MustCompile(`\belixir:\s*"([^"]+)"`)
+
+	// mixDepPattern captures the name and version requirement of a mix.exs dependency, e.g.
+	// {:phoenix, "~> 1.7.10"}
+	mixDepPattern = regexp.MustCompile(`\{:(\w+),\s*"([^"]+)"`)
+
+	// runtimePortPattern captures the default port config/runtime.exs falls back to when PORT
+	// is unset, e.g. System.get_env("PORT") || "4000"
+	runtimePortPattern = regexp.MustCompile(`System\.get_env\("PORT"\)\s*\|\|\s*"(\d+)"`)
+)
+
+// parseElixir parses Elixir project files. The app is run as a mix release named after it,
+// through the bin/server script of mix phx.gen.release when config/runtime.exs reads
+// PHX_SERVER and the script is present.
+func (dp *DependencyParser) parseElixir(basePath string) (*BuildInfo, error) {
+	info := &BuildInfo{
+		BuildTool:    BuildToolMix,
+		Runtime:      defaultElixirRuntime,
+		Dependencies: make(map[string]string),
+		BuildCommand: "mix release",
+		StartCommand: "./bin/app start",
+		Port:         4000,
+	}
+
+	data, err := os.ReadFile(filepath.Join(basePath, "mix.exs"))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read mix.exs")
+		return info, nil
+	}
+	manifest := string(data)
+
+	for _, match := range mixDepPattern.FindAllStringSubmatch(manifest, -1) {
+		info.Dependencies[match[1]] = match[2]
+	}
+
+	if match := mixElixirPattern.FindStringSubmatch(manifest); match != nil {
+		info.Runtime = elixirRuntime(match[1])
+	}
+
+	app := "app"
+	if match := mixAppPattern.FindStringSubmatch(manifest); match != nil {
+		app = match[1]
+	}
+	info.BuildCommand = "mix release " + app
+	info.StartCommand = "./bin/" + app + " start"
+
+	runtimeConfig, err := os.ReadFile(filepath.Join(basePath, "config", "runtime.exs"))
+	if err != nil {
+		return info, nil
+	}
+
+	if match := runtimePortPattern.FindSubmatch(runtimeConfig); match != nil {
+		if port, err := strconv.Atoi(string(match[1])); err == nil {
+			info.Port = port
+		}
+	}
+
+	if strings.Contains(string(runtimeConfig), "PHX_SERVER") {
+		if _, err := os.Stat(filepath.Join(basePath, "rel", "overlays", "bin", "server")); err == nil {
+			info.StartCommand = "./bin/server"
+		}
+	}
+
+	return info, nil
+}
+
+// elixirRuntime returns the lowest major.minor Elixir version a mix.exs requirement such as
+// ~> 1.15 or >= 1.14.0 allows, or the default version when it cannot be parsed
+func elixirRuntime(requirement string) string {
+	version := strings.TrimSpace(strings.TrimLeft(requirement, "~>= "))
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return defaultElixirRuntime
+	}
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		return defaultElixirRuntime
+	}
+	if _, err := strconv.Atoi(parts[1]); err != nil {
+		return defaultElixirRuntime
+	}
+	return parts[0] + "." + parts[1]
+}
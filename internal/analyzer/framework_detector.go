@@ -3,9 +3,22 @@ package analyzer
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+var (
+	// fastAPIImportPattern matches the import of the FastAPI application class
+	fastAPIImportPattern = regexp.MustCompile(`(?m)^\s*from\s+fastapi\s+import\s+.*\bFastAPI\b`)
+
+	// fastAPIAppPattern matches a module-level FastAPI application, capturing its variable
+	fastAPIAppPattern = regexp.MustCompile(`(?m)^(\w+)\s*(?::\s*\w+\s*)?=\s*FastAPI\(`)
+)
+
+// maxSourceScanBytes bounds the size of source files read for framework imports
+const maxSourceScanBytes = 1 << 20
+
 // FrameworkDetector detects web frameworks
 type FrameworkDetector struct{}
 
@@ -25,6 +38,10 @@ func (fd *FrameworkDetector) Detect(language Language, files []FileInfo) Framewo
 		return fd.detectPythonFramework(files)
 	case LanguageJava:
 		return fd.detectJavaFramework(files)
+	case LanguagePHP:
+		return fd.detectPHPFramework(files)
+	case LanguageDotNet:
+		return fd.detectDotNetFramework(files)
 	default:
 		return FrameworkUnknown
 	}
@@ -134,18 +151,11 @@ func (fd *FrameworkDetector) ParsePackageJSONFile(filePath string) Framework {
 	return FrameworkUnknown
 }
 
-// detectPythonFramework detects Python frameworks
+// detectPythonFramework detects Python frameworks. FastAPI apps are found by their imports,
+// see FastAPIApp.
 func (fd *FrameworkDetector) detectPythonFramework(files []FileInfo) Framework {
-	// Look for requirements.txt or pyproject.toml
-	for _, file := range files {
-		if file.Name == "requirements.txt" {
-			// Would parse requirements.txt
-			return FrameworkUnknown
-		}
-		if file.Name == "pyproject.toml" {
-			// Would parse pyproject.toml
-			return FrameworkUnknown
-		}
+	if fd.DjangoProject(files) != "" {
+		return FrameworkDjango
 	}
 
 	// Check for common framework patterns in file structure
@@ -165,6 +175,67 @@ func (fd *FrameworkDetector) detectPythonFramework(files []FileInfo) Framework {
 	return FrameworkUnknown
 }
 
+// DjangoProject returns the dotted name of a Django project's settings package: the directory
+// holding settings.py, preferring one with a wsgi.py. It returns "" without a manage.py.
+func (fd *FrameworkDetector) DjangoProject(files []FileInfo) string {
+	paths := make(map[string]bool, len(files))
+	for _, file := range files {
+		paths[filepath.ToSlash(file.Path)] = true
+	}
+
+	if !paths["manage.py"] {
+		return ""
+	}
+
+	project := ""
+	for _, file := range files {
+		if file.Name != "settings.py" {
+			continue
+		}
+
+		dir := filepath.ToSlash(filepath.Dir(file.Path))
+		if dir == "." {
+			continue
+		}
+
+		if paths[dir+"/wsgi.py"] {
+			return strings.ReplaceAll(dir, "/", ".")
+		}
+		if project == "" {
+			project = strings.ReplaceAll(dir, "/", ".")
+		}
+	}
+
+	return project
+}
+
+// FastAPIApp returns the module:variable of the FastAPI application in basePath, for uvicorn,
+// or "" when no source file imports FastAPI. A module that creates the app is preferred over
+// one that only imports it, whose app is assumed to be named app.
+func (fd *FrameworkDetector) FastAPIApp(basePath string, files []FileInfo) string {
+	fallback := ""
+	for _, file := range files {
+		if file.IsDirectory || file.Extension != ".py" || file.Size > maxSourceScanBytes {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(basePath, file.Path))
+		if err != nil || !fastAPIImportPattern.Match(data) {
+			continue
+		}
+
+		module := strings.ReplaceAll(strings.TrimSuffix(filepath.ToSlash(file.Path), ".py"), "/", ".")
+		if match := fastAPIAppPattern.FindSubmatch(data); match != nil {
+			return module + ":" + string(match[1])
+		}
+		if fallback == "" {
+			fallback = module + ":app"
+		}
+	}
+
+	return fallback
+}
+
 // ParseRequirementsFile parses a requirements.txt file
 func (fd *FrameworkDetector) ParseRequirementsFile(filePath string) Framework {
 	data, err := os.ReadFile(filePath)
@@ -219,6 +290,75 @@ func (fd *FrameworkDetector) detectJavaFramework(files []FileInfo) Framework {
 	return FrameworkUnknown
 }
 
+// detectPHPFramework detects PHP frameworks from the files their project skeletons ship
+func (fd *FrameworkDetector) detectPHPFramework(files []FileInfo) Framework {
+	paths := make(map[string]bool, len(files))
+	for _, file := range files {
+		paths[filepath.ToSlash(file.Path)] = true
+	}
+
+	// Laravel's artisan console and application bootstrap
+	if paths["artisan"] && paths["bootstrap/app.php"] {
+		return FrameworkLaravel
+	}
+
+	// Symfony's console
+	if paths["bin/console"] {
+		return FrameworkSymfony
+	}
+
+	return FrameworkUnknown
+}
+
+// detectDotNetFramework detects Blazor WebAssembly apps from the _framework directory their
+// published output contains. Other .NET frameworks are found from the project SDK, see
+// DotNetFramework.
+func (fd *FrameworkDetector) detectDotNetFramework(files []FileInfo) Framework {
+	for _, file := range files {
+		if file.IsDirectory && file.Name == "_framework" {
+			return FrameworkBlazor
+		}
+	}
+	return FrameworkUnknown
+}
+
+// DotNetFramework detects the framework of the .NET project in basePath from the SDK its
+// .csproj file uses: web projects are ASP.NET Core, plain SDK projects console apps
+func (fd *FrameworkDetector) DotNetFramework(basePath string, files []FileInfo) Framework {
+	if framework := fd.detectDotNetFramework(files); framework != FrameworkUnknown {
+		return framework
+	}
+
+	project, err := readCSProject(basePath)
+	if err != nil {
+		return FrameworkUnknown
+	}
+
+	switch project.Sdk {
+	case "Microsoft.NET.Sdk.BlazorWebAssembly":
+		return FrameworkBlazor
+	case "Microsoft.NET.Sdk.Web":
+		return FrameworkASPNetCore
+	case "Microsoft.NET.Sdk":
+		return FrameworkConsole
+	default:
+		return FrameworkUnknown
+	}
+}
+
+// ElixirFramework detects Phoenix apps from the phoenix dependency in the mix.exs of basePath
+func (fd *FrameworkDetector) ElixirFramework(basePath string) Framework {
+	data, err := os.ReadFile(filepath.Join(basePath, "mix.exs"))
+	if err != nil {
+		return FrameworkUnknown
+	}
+
+	if strings.Contains(string(data), "{:phoenix,") {
+		return FrameworkPhoenix
+	}
+	return FrameworkUnknown
+}
+
 // GetFrameworkInfo returns additional information about a framework
 func GetFrameworkInfo(framework Framework) map[string]interface{} {
 	info := map[Framework]map[string]interface{}{
@@ -257,6 +397,27 @@ func GetFrameworkInfo(framework Framework) map[string]interface{} {
 			"build_command": "pip install -r requirements.txt",
 			"start_command": "python manage.py runserver",
 		},
+		FrameworkLaravel: {
+			"name":          "Laravel",
+			"language":      "php",
+			"default_port":  8080,
+			"build_command": "composer install --no-dev",
+			"start_command": "php-fpm and nginx",
+		},
+		FrameworkSymfony: {
+			"name":          "Symfony",
+			"language":      "php",
+			"default_port":  8080,
+			"build_command": "composer install --no-dev",
+			"start_command": "php-fpm and nginx",
+		},
+		FrameworkPhoenix: {
+			"name":          "Phoenix",
+			"language":      "elixir",
+			"default_port":  4000,
+			"build_command": "mix deps.get && mix phx.digest && mix release",
+			"start_command": "./bin/app start",
+		},
 		FrameworkSpringBoot: {
 			"name":          "Spring Boot",
 			"language":      "java",
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"path/filepath"
 	"strings"
 )
 
@@ -11,6 +12,9 @@ type LanguageDetector struct {
 
 	// Key files that indicate a language
 	keyFiles map[string]Language
+
+	// Extensions of project files, whose names vary, that indicate a language
+	keyExtensions map[string]Language
 }
 
 // NewLanguageDetector creates a new language detector
@@ -30,33 +34,61 @@ func NewLanguageDetector() *LanguageDetector {
 			".rb":   LanguageRuby,
 			".php":  LanguagePHP,
 			".cs":   LanguageDotNet,
+			".ex":   LanguageElixir,
+			".exs":  LanguageElixir,
 		},
+		// Keys are lowercase, file names are matched case-insensitively
 		keyFiles: map[string]Language{
 			"package.json":    LanguageNodeJS,
 			"go.mod":          LanguageGo,
 			"requirements.txt": LanguagePython,
 			"pyproject.toml":  LanguagePython,
-			"Pipfile":         LanguagePython,
+			"pipfile":         LanguagePython,
 			"pom.xml":         LanguageJava,
 			"build.gradle":    LanguageJava,
-			"Cargo.toml":      LanguageRust,
-			"Gemfile":         LanguageRuby,
+			"cargo.toml":      LanguageRust,
+			"gemfile":         LanguageRuby,
 			"composer.json":   LanguagePHP,
+			"deno.json":       LanguageDeno,
+			"deno.jsonc":      LanguageDeno,
+			"deps.ts":         LanguageDeno,
+			"mix.exs":         LanguageElixir,
+		},
+		keyExtensions: map[string]Language{
+			".csproj": LanguageDotNet,
 		},
 	}
 }
 
+// IsKeyFile reports whether a file name marks the root of a project in some language
+func (ld *LanguageDetector) IsKeyFile(name string) bool {
+	if _, exists := ld.keyFiles[strings.ToLower(name)]; exists {
+		return true
+	}
+	_, exists := ld.keyExtensions[strings.ToLower(filepath.Ext(name))]
+	return exists
+}
+
 // Detect detects the primary language from a list of files
 func (ld *LanguageDetector) Detect(files []FileInfo) (Language, float64) {
 	// Count files by language
 	languageCounts := make(map[Language]int)
 	totalFiles := 0
 
-	// First check for key files (high confidence indicators)
-	for _, file := range files {
-		if lang, exists := ld.keyFiles[strings.ToLower(file.Name)]; exists {
-			// Key file found - very high confidence
-			return lang, 0.95
+	// First check for key files (high confidence indicators), at the root before nested ones
+	// such as the assets/package.json of a Phoenix app
+	for _, atRoot := range []bool{true, false} {
+		for _, file := range files {
+			if (filepath.Dir(file.Path) == ".") != atRoot {
+				continue
+			}
+			if lang, exists := ld.keyFiles[strings.ToLower(file.Name)]; exists {
+				// Key file found - very high confidence
+				return lang, 0.95
+			}
+			if lang, exists := ld.keyExtensions[file.Extension]; exists && !file.IsDirectory {
+				return lang, 0.95
+			}
 		}
 	}
 
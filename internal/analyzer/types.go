@@ -12,6 +12,8 @@ const (
 	LanguageRuby       Language = "ruby"
 	LanguagePHP        Language = "php"
 	LanguageDotNet     Language = "dotnet"
+	LanguageDeno       Language = "deno"
+	LanguageElixir     Language = "elixir"
 	LanguageUnknown    Language = "unknown"
 )
 
@@ -41,6 +43,18 @@ const (
 	FrameworkSpringBoot Framework = "springboot"
 	FrameworkQuarkus   Framework = "quarkus"
 
+	// PHP frameworks
+	FrameworkLaravel   Framework = "laravel"
+	FrameworkSymfony   Framework = "symfony"
+
+	// .NET frameworks
+	FrameworkASPNetCore Framework = "aspnetcore"
+	FrameworkBlazor    Framework = "blazor"
+	FrameworkConsole   Framework = "console"
+
+	// Elixir frameworks
+	FrameworkPhoenix   Framework = "phoenix"
+
 	// Other
 	FrameworkUnknown   Framework = "unknown"
 )
@@ -58,6 +72,10 @@ const (
 	BuildToolMaven     BuildTool = "maven"
 	BuildToolGradle    BuildTool = "gradle"
 	BuildToolCargo     BuildTool = "cargo"
+	BuildToolComposer  BuildTool = "composer"
+	BuildToolDotNet    BuildTool = "dotnet"
+	BuildToolDeno      BuildTool = "deno"
+	BuildToolMix       BuildTool = "mix"
 	BuildToolUnknown   BuildTool = "unknown"
 )
 
@@ -75,6 +93,15 @@ type AnalysisResult struct {
 	HasDockerfile    bool               `json:"has_dockerfile"`
 	Files            []string           `json:"files"`
 	Confidence       float64            `json:"confidence"`
+	IsMonorepo       bool               `json:"is_monorepo,omitempty"`
+	Services         []string           `json:"services,omitempty"` // Service directories of a monorepo, e.g. services/auth
+}
+
+// AnalyzeOptions configures an analysis
+type AnalyzeOptions struct {
+	// SubPath is a directory within the source to analyze instead of its root, e.g. the
+	// services/auth directory of a monorepo
+	SubPath string
 }
 
 // FileInfo represents information about a source file
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// abTestHeaderPattern matches the header names Istio can route on, once lowercased
+var abTestHeaderPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// StartABTest handles POST /api/v1/deployments/{id}/ab-test/start
+func (h *DeploymentHandler) StartABTest(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req StartABTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.RoutingWeight == 0 {
+		req.RoutingWeight = 100
+	}
+	req.HeaderName = strings.ToLower(req.HeaderName)
+
+	if err := validateABTest(id, &req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	variant, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	baseline, err := h.repo.GetDeployment(r.Context(), req.BaselineDeploymentID)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, "Baseline deployment not found")
+		return
+	}
+
+	for _, d := range []*state.Deployment{baseline, variant} {
+		if d.Cloud == "cloudrun" || d.DeployerType == deployer.DeployerTypeKustomize {
+			RespondWithError(w, http.StatusBadRequest, "A/B tests are only supported for Helm deployments on GKE")
+			return
+		}
+	}
+
+	baselineInfra, err := h.repo.GetInfrastructure(r.Context(), baseline.ID)
+	if err != nil || baselineInfra.HelmReleaseName == "" || baselineInfra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusConflict, "Baseline deployment has no Helm release")
+		return
+	}
+
+	// A variant that is not deployed yet gets its traffic once the worker deploys it
+	variantInfra, err := h.repo.GetInfrastructure(r.Context(), variant.ID)
+	deployed := err == nil && variantInfra.HelmReleaseName != "" && variantInfra.ClusterEndpoint != ""
+	if deployed && variantInfra.ClusterEndpoint != baselineInfra.ClusterEndpoint {
+		RespondWithError(w, http.StatusBadRequest, "Deployments must run on the same cluster")
+		return
+	}
+
+	existing, err := h.repo.GetRoutingABTest(r.Context(), baseline.ID)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get A/B test")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start A/B test")
+		return
+	}
+	if existing != nil {
+		RespondWithError(w, http.StatusConflict, "Baseline deployment already has an A/B test")
+		return
+	}
+
+	existing, err = h.repo.GetVariantABTest(r.Context(), variant.ID)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get A/B test")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start A/B test")
+		return
+	}
+	if existing != nil && (existing.Status == state.ABTestStatusRunning || existing.Status == state.ABTestStatusPromoting) {
+		RespondWithError(w, http.StatusConflict, "Deployment already has an A/B test")
+		return
+	}
+
+	test := &state.ABTest{
+		BaselineDeploymentID: baseline.ID,
+		VariantDeploymentID:  variant.ID,
+		HeaderName:           req.HeaderName,
+		HeaderValue:          req.HeaderValue,
+		RoutingWeight:        req.RoutingWeight,
+		Status:               state.ABTestStatusRunning,
+	}
+	if err := h.repo.CreateABTest(r.Context(), test); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to create A/B test")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start A/B test")
+		return
+	}
+
+	if deployed {
+		if err := deployer.SyncServiceRoutes(r.Context(), h.repo, baseline.ID); err != nil {
+			log.Error().Err(err).Str("id", idStr).Msg("Failed to apply A/B test routing")
+			if delErr := h.repo.DeleteABTest(r.Context(), test.ID); delErr != nil {
+				log.Error().Err(delErr).Str("id", idStr).Msg("Failed to delete unapplied A/B test")
+			}
+			RespondWithError(w, http.StatusInternalServerError, "Failed to apply A/B test routing: "+err.Error())
+			return
+		}
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("baseline_deployment_id", baseline.ID.String()).
+		Str("header", test.HeaderName).
+		Int("routing_weight", test.RoutingWeight).
+		Msg("A/B test started")
+
+	RespondWithJSON(w, http.StatusCreated, ABTestToResponse(test))
+}
+
+// ConcludeABTest handles POST /api/v1/deployments/{id}/ab-test/conclude?winner=<id>
+func (h *DeploymentHandler) ConcludeABTest(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	winner, err := uuid.Parse(r.URL.Query().Get("winner"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "winner must be a deployment ID")
+		return
+	}
+
+	if h.orchClient == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Orchestration service unavailable")
+		return
+	}
+
+	test, err := h.repo.GetVariantABTest(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get A/B test")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to conclude A/B test")
+		return
+	}
+	if test == nil || test.Status != state.ABTestStatusRunning {
+		RespondWithError(w, http.StatusConflict, "Deployment has no running A/B test")
+		return
+	}
+
+	loserID := test.VariantDeploymentID
+	switch winner {
+	case test.BaselineDeploymentID:
+	case test.VariantDeploymentID:
+		loserID = test.BaselineDeploymentID
+	default:
+		RespondWithError(w, http.StatusBadRequest, "winner must be the baseline or variant deployment")
+		return
+	}
+
+	loser, err := h.repo.GetDeployment(r.Context(), loserID)
+	if err != nil || loser.InfrastructureID == nil {
+		RespondWithError(w, http.StatusConflict, "Losing deployment has no infrastructure to destroy")
+		return
+	}
+
+	// Concluding does not depend on Prometheus; the counts stay zero when it cannot be reached
+	if metrics, err := h.abTestMetrics(r.Context(), test); err != nil {
+		log.Warn().Err(err).Str("id", idStr).Msg("Failed to record A/B test results")
+	} else {
+		test.BaselineRequests = int64(metrics.BaselineRequests)
+		test.BaselineErrors = int64(metrics.BaselineErrors)
+		test.VariantRequests = int64(metrics.VariantRequests)
+		test.VariantErrors = int64(metrics.VariantErrors)
+	}
+
+	// The baseline's service keeps sending its traffic to a winning variant until it is destroyed
+	now := time.Now()
+	test.Status = state.ABTestStatusConcluded
+	if winner == test.VariantDeploymentID {
+		test.Status = state.ABTestStatusPromoting
+	}
+	test.WinnerDeploymentID = &winner
+	test.ConcludedAt = &now
+	if err := h.repo.UpdateABTest(r.Context(), test); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to update A/B test")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to conclude A/B test")
+		return
+	}
+
+	if err := deployer.SyncServiceRoutes(r.Context(), h.repo, test.BaselineDeploymentID); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to route traffic to A/B test winner")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to route traffic to winner: "+err.Error())
+		return
+	}
+
+	destroyPayload := &queue.DestroyPayload{
+		DeploymentID:     loser.ID.String(),
+		InfrastructureID: loser.InfrastructureID.String(),
+	}
+	if err := h.orchClient.TriggerDestroy(r.Context(), destroyPayload); err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", loser.ID.String()).
+			Msg("Failed to trigger destroy job")
+		RespondWithError(w, http.StatusInternalServerError,
+			"Failed to destroy losing deployment")
+		return
+	}
+	_ = h.repo.UpdateDeploymentStatus(r.Context(), loser.ID, "DESTROYING")
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("winner", winner.String()).
+		Str("loser", loser.ID.String()).
+		Msg("A/B test concluded")
+
+	RespondWithJSON(w, http.StatusOK, ABTestToResponse(test))
+}
+
+// GetABTestMetrics handles GET /api/v1/deployments/{id}/ab-test/metrics
+func (h *DeploymentHandler) GetABTestMetrics(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	test, err := h.repo.GetVariantABTest(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get A/B test")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get A/B test metrics")
+		return
+	}
+	if test == nil {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no A/B test")
+		return
+	}
+
+	response := ABTestMetricsResponse{
+		ABTestID:             test.ID,
+		BaselineDeploymentID: test.BaselineDeploymentID,
+		VariantDeploymentID:  test.VariantDeploymentID,
+		Status:               test.Status,
+	}
+
+	// Concluded tests report the counts recorded when they concluded
+	if test.Status != state.ABTestStatusRunning {
+		if test.ConcludedAt != nil {
+			response.WindowSeconds = int(test.ConcludedAt.Sub(test.CreatedAt).Seconds())
+		}
+		response.BaselineRequests = test.BaselineRequests
+		response.BaselineErrors = test.BaselineErrors
+		response.VariantRequests = test.VariantRequests
+		response.VariantErrors = test.VariantErrors
+		RespondWithJSON(w, http.StatusOK, response)
+		return
+	}
+
+	metrics, err := h.abTestMetrics(r.Context(), test)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get A/B test metrics")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get A/B test metrics: "+err.Error())
+		return
+	}
+
+	response.WindowSeconds = int(metrics.Window.Seconds())
+	response.BaselineRequests = int64(metrics.BaselineRequests)
+	response.BaselineErrors = int64(metrics.BaselineErrors)
+	response.VariantRequests = int64(metrics.VariantRequests)
+	response.VariantErrors = int64(metrics.VariantErrors)
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// abTestMetrics counts the requests each side of a running test served since it started
+func (h *DeploymentHandler) abTestMetrics(ctx context.Context, test *state.ABTest) (*deployer.ABTestMetrics, error) {
+	baseline, err := h.repo.GetInfrastructure(ctx, test.BaselineDeploymentID)
+	if err != nil || baseline.HelmReleaseName == "" {
+		return nil, fmt.Errorf("baseline deployment has no Helm release")
+	}
+
+	variant, err := h.repo.GetInfrastructure(ctx, test.VariantDeploymentID)
+	if err != nil || variant.HelmReleaseName == "" {
+		return nil, fmt.Errorf("variant deployment has no Helm release")
+	}
+
+	kubeClient, err := deployer.NewKubeClient(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prometheus ranges shorter than its scrape interval come back empty
+	window := time.Since(test.CreatedAt).Truncate(time.Second)
+	if window < time.Minute {
+		window = time.Minute
+	}
+
+	return kubeClient.GetABTestMetrics(ctx, deployer.ServiceHost(baseline), deployer.ServiceHost(variant), window)
+}
+
+// validateABTest checks an A/B test's baseline, header and routing weight
+func validateABTest(variantID uuid.UUID, req *StartABTestRequest) error {
+	if req.BaselineDeploymentID == uuid.Nil {
+		return fmt.Errorf("baseline_deployment_id is required")
+	}
+
+	if req.BaselineDeploymentID == variantID {
+		return fmt.Errorf("a deployment cannot be A/B tested against itself")
+	}
+
+	if !abTestHeaderPattern.MatchString(req.HeaderName) {
+		return fmt.Errorf("header_name must contain only letters, digits and hyphens")
+	}
+
+	if req.HeaderValue == "" {
+		return fmt.Errorf("header_value is required")
+	}
+
+	if req.RoutingWeight < 1 || req.RoutingWeight > 100 {
+		return fmt.Errorf("routing_weight must be between 1 and 100")
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/maintenance"
+	"github.com/alvesdmateus/app-deployer/internal/policy"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog/log"
+)
+
+// AdminHandler handles platform-wide settings managed by admins
+type AdminHandler struct {
+	repo          *state.Repository
+	defaultPolicy deployer.ResourceLimitPolicy
+	cleaner       *maintenance.Cleaner
+	policies      *policy.OPAEvaluator // nil when no admission policies are configured
+}
+
+// NewAdminHandler creates a new admin handler. defaultPolicy is the configured resource policy,
+// reported until an admin sets one.
+func NewAdminHandler(repo *state.Repository, defaultPolicy deployer.ResourceLimitPolicy, cleaner *maintenance.Cleaner, policies *policy.OPAEvaluator) *AdminHandler {
+	return &AdminHandler{
+		repo:          repo,
+		defaultPolicy: defaultPolicy,
+		cleaner:       cleaner,
+		policies:      policies,
+	}
+}
+
+// GetResourcePolicy handles GET /api/v1/admin/resource-policy
+func (h *AdminHandler) GetResourcePolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.repo.GetResourcePolicy(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get resource policy")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get resource policy")
+		return
+	}
+
+	if policy == nil {
+		RespondWithJSON(w, http.StatusOK, ResourcePolicyResponse{
+			MaxCPULimit:    h.defaultPolicy.MaxCPULimit,
+			MaxMemoryLimit: h.defaultPolicy.MaxMemoryLimit,
+			MaxReplicas:    h.defaultPolicy.MaxReplicas,
+			Source:         "config",
+		})
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, ResourcePolicyToResponse(policy))
+}
+
+// UpdateResourcePolicy handles PUT /api/v1/admin/resource-policy
+func (h *AdminHandler) UpdateResourcePolicy(w http.ResponseWriter, r *http.Request) {
+	var req ResourcePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := (deployer.ResourceLimitPolicy{
+		MaxCPULimit:    req.MaxCPULimit,
+		MaxMemoryLimit: req.MaxMemoryLimit,
+		MaxReplicas:    req.MaxReplicas,
+	}).Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	actor := requestActor(r)
+	policy := &state.ResourcePolicy{
+		MaxCPULimit:    req.MaxCPULimit,
+		MaxMemoryLimit: req.MaxMemoryLimit,
+		MaxReplicas:    req.MaxReplicas,
+		UpdatedBy:      actor,
+	}
+
+	if err := h.repo.SaveResourcePolicy(r.Context(), policy); err != nil {
+		log.Error().Err(err).Msg("Failed to save resource policy")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update resource policy")
+		return
+	}
+
+	details, _ := json.Marshal(req)
+	if err := h.repo.CreateAuditLog(r.Context(), &state.AuditLog{
+		Action:  "resource_policy.update",
+		Actor:   actor,
+		Details: string(details),
+	}); err != nil {
+		log.Warn().Err(err).Msg("Failed to record resource policy update")
+	}
+
+	log.Info().
+		Str("actor", actor).
+		Str("max_cpu_limit", policy.MaxCPULimit).
+		Str("max_memory_limit", policy.MaxMemoryLimit).
+		Int("max_replicas", policy.MaxReplicas).
+		Msg("Resource policy updated")
+
+	RespondWithJSON(w, http.StatusOK, ResourcePolicyToResponse(policy))
+}
+
+// CreateCVESuppression handles POST /api/v1/admin/suppressed-cves
+// Later scans of the deployment, or of all deployments, neither count nor fail on the CVE.
+func (h *AdminHandler) CreateCVESuppression(w http.ResponseWriter, r *http.Request) {
+	var req CreateCVESuppressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.CVEID = strings.TrimSpace(req.CVEID)
+	if req.CVEID == "" {
+		RespondWithError(w, http.StatusBadRequest, "cve_id is required")
+		return
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		RespondWithError(w, http.StatusBadRequest, "reason is required")
+		return
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		RespondWithError(w, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	if req.DeploymentID != nil {
+		if _, err := h.repo.GetDeployment(r.Context(), *req.DeploymentID); err != nil {
+			RespondWithError(w, http.StatusNotFound, "Deployment not found")
+			return
+		}
+	}
+
+	actor := requestActor(r)
+	suppression := &state.CVESuppression{
+		CVEID:        req.CVEID,
+		UserID:       actor,
+		Reason:       req.Reason,
+		DeploymentID: req.DeploymentID,
+		ExpiresAt:    req.ExpiresAt,
+	}
+
+	if err := h.repo.CreateCVESuppression(r.Context(), suppression); err != nil {
+		log.Error().Err(err).Str("cve_id", req.CVEID).Msg("Failed to create CVE suppression")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to create CVE suppression")
+		return
+	}
+
+	details, _ := json.Marshal(suppression)
+	auditLog := &state.AuditLog{
+		Action:  "cve_suppression.create",
+		Actor:   actor,
+		Details: string(details),
+	}
+	if req.DeploymentID != nil {
+		auditLog.DeploymentID = *req.DeploymentID
+	}
+	if err := h.repo.CreateAuditLog(r.Context(), auditLog); err != nil {
+		log.Warn().Err(err).Msg("Failed to record CVE suppression")
+	}
+
+	log.Info().
+		Str("actor", actor).
+		Str("cve_id", suppression.CVEID).
+		Msg("CVE suppressed")
+
+	RespondWithJSON(w, http.StatusCreated, CVESuppressionToResponse(suppression))
+}
+
+// ListCVESuppressions handles GET /api/v1/admin/suppressed-cves
+func (h *AdminHandler) ListCVESuppressions(w http.ResponseWriter, r *http.Request) {
+	suppressions, err := h.repo.ListCVESuppressions(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list CVE suppressions")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list CVE suppressions")
+		return
+	}
+
+	responses := make([]CVESuppressionResponse, 0, len(suppressions))
+	for i := range suppressions {
+		responses = append(responses, CVESuppressionToResponse(&suppressions[i]))
+	}
+
+	RespondWithJSON(w, http.StatusOK, responses)
+}
+
+// RunCleanup handles POST /api/v1/admin/maintenance/run-cleanup
+// It runs the daily cleanup now, deleting deployment logs past their retention period.
+func (h *AdminHandler) RunCleanup(w http.ResponseWriter, r *http.Request) {
+	result, err := h.cleaner.Run(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to run cleanup")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to run cleanup")
+		return
+	}
+
+	actor := requestActor(r)
+	response := CleanupResponse{
+		LogsDeleted:   result.LogsDeleted,
+		RetentionDays: result.RetentionDays,
+	}
+
+	details, _ := json.Marshal(response)
+	if err := h.repo.CreateAuditLog(r.Context(), &state.AuditLog{
+		Action:  "maintenance.cleanup",
+		Actor:   actor,
+		Details: string(details),
+	}); err != nil {
+		log.Warn().Err(err).Msg("Failed to record cleanup run")
+	}
+
+	log.Info().
+		Str("actor", actor).
+		Int64("logs_deleted", result.LogsDeleted).
+		Msg("Manual cleanup completed")
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
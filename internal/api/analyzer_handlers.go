@@ -25,7 +25,8 @@ func NewAnalyzerHandler() *AnalyzerHandler {
 
 // AnalyzeRequest represents a request to analyze source code
 type AnalyzeRequest struct {
-	Path string `json:"path"`
+	Path    string `json:"path"`
+	SubPath string `json:"sub_path,omitempty"` // Service directory within path to analyze
 }
 
 // AnalyzeSourceCode handles POST /api/v1/analyze
@@ -41,8 +42,15 @@ func (h *AnalyzerHandler) AnalyzeSourceCode(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if req.SubPath != "" {
+		if err := analyzer.ValidateSubPath(req.SubPath); err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
 	// Analyze the source code
-	result, err := h.analyzer.Analyze(req.Path)
+	result, err := h.analyzer.AnalyzeWithOptions(req.Path, analyzer.AnalyzeOptions{SubPath: req.SubPath})
 	if err != nil {
 		log.Error().Err(err).Str("path", req.Path).Msg("Failed to analyze source code")
 		RespondWithError(w, http.StatusInternalServerError, "Failed to analyze source code: "+err.Error())
@@ -144,13 +152,23 @@ func (h *AnalyzerHandler) GetSupportedLanguages(w http.ResponseWriter, r *http.R
 		{
 			"id":   "php",
 			"name": "PHP",
-			"frameworks": []string{},
+			"frameworks": []string{"laravel", "symfony"},
 		},
 		{
 			"id":   "dotnet",
 			"name": ".NET",
+			"frameworks": []string{"aspnetcore", "blazor", "console"},
+		},
+		{
+			"id":   "deno",
+			"name": "Deno",
 			"frameworks": []string{},
 		},
+		{
+			"id":   "elixir",
+			"name": "Elixir",
+			"frameworks": []string{"phoenix"},
+		},
 	}
 
 	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
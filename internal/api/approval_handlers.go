@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// pendingRollout is a rollout started through the deploy endpoint. Rollouts of deployments
+// that require approval are stored JSON-encoded on the approval until it is decided.
+type pendingRollout struct {
+	Port          int                     `json:"port"`
+	DeployerType  string                  `json:"deployer_type"`
+	RepoURL       string                  `json:"repo_url,omitempty"`
+	KustomizePath string                  `json:"kustomize_path,omitempty"`
+	CPULimit      string                  `json:"cpu_limit,omitempty"`
+	MemoryLimit   string                  `json:"memory_limit,omitempty"`
+	Fingerprint   string                  `json:"fingerprint"`
+	ScheduledAt   *time.Time              `json:"scheduled_at,omitempty"`
+	Provision     *queue.ProvisionPayload `json:"provision"`
+}
+
+// requestApproval holds a rollout until it is approved and notifies the approvers
+func (h *DeploymentHandler) requestApproval(w http.ResponseWriter, r *http.Request, deployment *state.Deployment, rollout *pendingRollout) {
+	idStr := deployment.ID.String()
+
+	encoded, err := json.Marshal(rollout)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Failed to encode rollout")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to request approval")
+		return
+	}
+
+	approval := &state.DeploymentApproval{
+		DeploymentID: deployment.ID,
+		RequestedBy:  requestActor(r),
+		Approvers:    deployment.Approvers,
+		Status:       state.ApprovalStatusPending,
+		Rollout:      string(encoded),
+		ExpiresAt:    time.Now().Add(h.approvalExpiry),
+	}
+
+	if err := h.repo.CreateDeploymentApproval(r.Context(), approval); err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Failed to create approval")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to request approval")
+		return
+	}
+
+	// Approvers learn of the request through the notification webhook
+	if h.orchClient != nil {
+		if err := h.orchClient.TriggerNotification(r.Context(), &queue.NotifyPayload{
+			EventType:    "approval_requested",
+			DeploymentID: idStr,
+			Message: fmt.Sprintf("Deployment %s requests approval to roll out %s",
+				deployment.Name, rollout.Provision.ImageTag),
+			Data: map[string]string{
+				"approval_id":  approval.ID.String(),
+				"requested_by": approval.RequestedBy,
+				"approvers":    strings.Join(approval.Approvers, ","),
+				"image_tag":    rollout.Provision.ImageTag,
+				"expires_at":   approval.ExpiresAt.UTC().Format(time.RFC3339),
+			},
+		}); err != nil {
+			log.Warn().Err(err).Str("deployment_id", idStr).Msg("Failed to notify approvers")
+		}
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("approval_id", approval.ID.String()).
+		Str("requested_by", approval.RequestedBy).
+		Msg("Rollout awaiting approval")
+
+	RespondWithJSON(w, http.StatusAccepted, OrchestrationResponse{
+		DeploymentID: idStr,
+		Status:       "PENDING_APPROVAL",
+		Message:      fmt.Sprintf("Deployment requires approval, request expires at %s.", approval.ExpiresAt.UTC().Format(time.RFC3339)),
+		ApprovalID:   approval.ID.String(),
+	})
+}
+
+// ApproveDeployment handles POST /api/v1/approvals/{id}/approve
+// The held rollout is started once the approval is recorded.
+func (h *DeploymentHandler) ApproveDeployment(w http.ResponseWriter, r *http.Request) {
+	approval, actor, ok := h.decidableApproval(w, r)
+	if !ok {
+		return
+	}
+
+	var rollout pendingRollout
+	if err := json.Unmarshal([]byte(approval.Rollout), &rollout); err != nil || rollout.Provision == nil {
+		log.Error().Err(err).Str("approval_id", approval.ID.String()).Msg("Failed to decode held rollout")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to approve deployment")
+		return
+	}
+
+	// A scheduled time that passed while waiting for approval starts the rollout now
+	if rollout.ScheduledAt != nil && !rollout.ScheduledAt.After(time.Now()) {
+		rollout.ScheduledAt = nil
+	}
+
+	// Scheduled rollouts are started by the worker, so the orchestrator is only needed now
+	if h.orchClient == nil && rollout.ScheduledAt == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Orchestration service unavailable")
+		return
+	}
+
+	if actor == approval.RequestedBy {
+		RespondWithError(w, http.StatusForbidden, "A rollout cannot be approved by the client that requested it")
+		return
+	}
+
+	// Read from the primary, since the rollout saves the whole deployment
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), approval.DeploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", approval.DeploymentID.String()).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if !h.decideApproval(w, r, approval, state.ApprovalStatusApproved, actor) {
+		return
+	}
+
+	response, err := h.startRollout(r, deployment, &rollout)
+	if err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", deployment.ID.String()).
+			Str("approval_id", approval.ID.String()).
+			Msg("Failed to start approved deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Deployment approved but failed to start, start it again to request a new approval")
+		return
+	}
+
+	response.ApprovalID = approval.ID.String()
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// RejectDeployment handles POST /api/v1/approvals/{id}/reject
+// The held rollout is discarded.
+func (h *DeploymentHandler) RejectDeployment(w http.ResponseWriter, r *http.Request) {
+	approval, actor, ok := h.decidableApproval(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.decideApproval(w, r, approval, state.ApprovalStatusRejected, actor) {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, ApprovalToResponse(approval))
+}
+
+// decidableApproval loads the approval named in the URL and checks that it is pending and that
+// the caller may decide on it, responding with an error otherwise
+func (h *DeploymentHandler) decidableApproval(w http.ResponseWriter, r *http.Request) (*state.DeploymentApproval, string, bool) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid approval ID")
+		return nil, "", false
+	}
+
+	approval, err := h.repo.GetDeploymentApproval(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("approval_id", idStr).Msg("Approval not found")
+		RespondWithError(w, http.StatusNotFound, "Approval not found")
+		return nil, "", false
+	}
+
+	// Approvers are token fingerprints, so only a caller holding the token can match one
+	actor := requestActor(r)
+	if !isAdminRequest(r, h.adminToken) && !slices.Contains(approval.Approvers, actor) {
+		RespondWithError(w, http.StatusForbidden, "Not an approver of this deployment")
+		return nil, "", false
+	}
+
+	if approval.Status == state.ApprovalStatusPending && time.Now().After(approval.ExpiresAt) {
+		if _, err := h.repo.DecideDeploymentApproval(r.Context(), approval, state.ApprovalStatusExpired, ""); err != nil {
+			log.Warn().Err(err).Str("approval_id", idStr).Msg("Failed to expire approval")
+		}
+		RespondWithError(w, http.StatusConflict, "Approval expired, start the deployment again to request a new one")
+		return nil, "", false
+	}
+
+	if approval.Status != state.ApprovalStatusPending {
+		RespondWithError(w, http.StatusConflict, fmt.Sprintf("Approval is already %s", approval.Status))
+		return nil, "", false
+	}
+
+	return approval, actor, true
+}
+
+// decideApproval records the decision and an audit log entry. It responds with a conflict and
+// returns false when another request decided the approval first.
+func (h *DeploymentHandler) decideApproval(w http.ResponseWriter, r *http.Request, approval *state.DeploymentApproval, status, actor string) bool {
+	decided, err := h.repo.DecideDeploymentApproval(r.Context(), approval, status, actor)
+	if err != nil {
+		log.Error().Err(err).Str("approval_id", approval.ID.String()).Msg("Failed to record approval decision")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to record decision")
+		return false
+	}
+
+	if !decided {
+		RespondWithError(w, http.StatusConflict, "Approval was decided by another request")
+		return false
+	}
+
+	details, _ := json.Marshal(map[string]string{"approval_id": approval.ID.String(), "status": status})
+	if err := h.repo.CreateAuditLog(r.Context(), &state.AuditLog{
+		Action:       "deployment.approval",
+		DeploymentID: approval.DeploymentID,
+		Actor:        actor,
+		Details:      string(details),
+	}); err != nil {
+		log.Warn().Err(err).Str("approval_id", approval.ID.String()).Msg("Failed to record approval decision")
+	}
+
+	log.Info().
+		Str("deployment_id", approval.DeploymentID.String()).
+		Str("approval_id", approval.ID.String()).
+		Str("status", status).
+		Str("actor", actor).
+		Msg("Approval decided")
+
+	return true
+}
@@ -2,21 +2,29 @@ package api
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/alvesdmateus/app-deployer/internal/builder"
+	"github.com/alvesdmateus/app-deployer/internal/builder/scanner"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/rs/zerolog/log"
 )
 
 // BuildHandler handles build-related HTTP requests
 type BuildHandler struct {
-	repo *state.Repository
+	repo      *state.Repository
+	artifacts *builder.ArtifactStore // Optional, nil when no SBOM bucket is configured
 }
 
+// sbomURLTTL is how long signed SBOM download URLs stay valid
+const sbomURLTTL = 15 * time.Minute
+
 // NewBuildHandler creates a new build handler
-func NewBuildHandler(repo *state.Repository) *BuildHandler {
-	return &BuildHandler{repo: repo}
+func NewBuildHandler(repo *state.Repository, artifacts *builder.ArtifactStore) *BuildHandler {
+	return &BuildHandler{repo: repo, artifacts: artifacts}
 }
 
 // GetLatestBuild handles GET /api/v1/deployments/{deployment_id}/builds/latest
@@ -38,3 +46,111 @@ func (h *BuildHandler) GetLatestBuild(w http.ResponseWriter, r *http.Request) {
 	response := BuildToResponse(build)
 	RespondWithJSON(w, http.StatusOK, response)
 }
+
+// GetBuildSBOM handles GET /api/v1/deployments/{id}/builds/{buildID}/sbom
+// It redirects to a short-lived signed URL for the SBOM stored in GCS
+func (h *BuildHandler) GetBuildSBOM(w http.ResponseWriter, r *http.Request) {
+	deploymentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	buildID, err := uuid.Parse(chi.URLParam(r, "buildID"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid build ID")
+		return
+	}
+
+	build, err := h.repo.GetBuildByID(r.Context(), buildID)
+	if err != nil {
+		log.Error().Err(err).Str("build_id", buildID.String()).Msg("Failed to get build")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get build")
+		return
+	}
+
+	if build == nil || build.DeploymentID != deploymentID {
+		RespondWithError(w, http.StatusNotFound, "Build not found")
+		return
+	}
+
+	if build.SBOMPath == "" {
+		RespondWithError(w, http.StatusNotFound, "No SBOM recorded for this build")
+		return
+	}
+
+	if h.artifacts == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Artifact storage not configured")
+		return
+	}
+
+	url, err := h.artifacts.SignedURL(r.Context(), build.SBOMPath, sbomURLTTL)
+	if err != nil {
+		log.Error().Err(err).Str("build_id", buildID.String()).Msg("Failed to sign SBOM URL")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to generate SBOM download URL")
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+}
+
+// GetBuildScan handles GET /api/v1/deployments/{id}/builds/{buildID}/scan
+func (h *BuildHandler) GetBuildScan(w http.ResponseWriter, r *http.Request) {
+	deploymentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	buildID, err := uuid.Parse(chi.URLParam(r, "buildID"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid build ID")
+		return
+	}
+
+	scan, err := h.repo.GetVulnerabilityScanByBuild(r.Context(), buildID)
+	if err != nil {
+		log.Error().Err(err).Str("build_id", buildID.String()).Msg("Failed to get vulnerability scan")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get vulnerability scan")
+		return
+	}
+
+	if scan == nil || scan.DeploymentID != deploymentID {
+		RespondWithError(w, http.StatusNotFound, "No vulnerability scan recorded for this build")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, VulnerabilityScanToResponse(scan))
+}
+
+// ListVulnerabilities handles GET /api/v1/admin/vulnerabilities
+// It lists deployments whose latest scanned build still has vulnerabilities of the severity
+// given by ?severity=, CRITICAL by default.
+func (h *BuildHandler) ListVulnerabilities(w http.ResponseWriter, r *http.Request) {
+	severity := strings.ToUpper(r.URL.Query().Get("severity"))
+	if severity == "" {
+		severity = scanner.SeverityCritical
+	}
+
+	if !scanner.ValidSeverity(severity) {
+		RespondWithError(w, http.StatusBadRequest, "severity must be CRITICAL, HIGH, MEDIUM or LOW")
+		return
+	}
+
+	scans, err := h.repo.ListUnresolvedVulnerabilityScans(r.Context(), severity)
+	if err != nil {
+		log.Error().Err(err).Str("severity", severity).Msg("Failed to list vulnerability scans")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list vulnerabilities")
+		return
+	}
+
+	responses := make([]VulnerabilityScanResponse, 0, len(scans))
+	for i := range scans {
+		responses = append(responses, VulnerabilityScanToResponse(&scans[i]))
+	}
+
+	RespondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"severity": severity,
+		"scans":    responses,
+	})
+}
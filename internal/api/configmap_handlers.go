@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxConfigMapNameLength keeps the rendered "<release>-<name>" within the 63 character limit
+	maxConfigMapNameLength = 40
+
+	// maxConfigMapSize is the Kubernetes limit on the data stored in one ConfigMap
+	maxConfigMapSize = 1 << 20
+)
+
+var (
+	// configMapNamePattern matches a DNS-1123 label
+	configMapNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+	// configMapKeyPattern matches the file names Kubernetes accepts as ConfigMap keys
+	configMapKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+)
+
+// ConfigMapHandler handles deployment ConfigMap HTTP requests
+type ConfigMapHandler struct {
+	repo *state.Repository
+}
+
+// NewConfigMapHandler creates a new ConfigMap handler
+func NewConfigMapHandler(repo *state.Repository) *ConfigMapHandler {
+	return &ConfigMapHandler{
+		repo: repo,
+	}
+}
+
+// ListConfigMaps handles GET /api/v1/deployments/{id}/configmaps
+func (h *ConfigMapHandler) ListConfigMaps(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	configMaps, err := h.repo.ListDeploymentConfigMaps(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list configmaps")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list configmaps")
+		return
+	}
+
+	response := ConfigMapsResponse{
+		DeploymentID: id,
+		ConfigMaps:   make([]ConfigMapResponse, 0, len(configMaps)),
+	}
+	for i := range configMaps {
+		response.ConfigMaps = append(response.ConfigMaps, ConfigMapToResponse(&configMaps[i]))
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// SetConfigMap handles POST /api/v1/deployments/{id}/configmaps
+func (h *ConfigMapHandler) SetConfigMap(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req SetConfigMapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateConfigMap(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.Cloud == "cloudrun" || deployment.DeployerType == deployer.DeployerTypeKustomize {
+		RespondWithError(w, http.StatusBadRequest, "ConfigMaps are only supported for Helm deployments on GKE")
+		return
+	}
+
+	configMap := &state.DeploymentConfigMap{
+		DeploymentID: id,
+		Name:         req.Name,
+		MountPath:    req.MountPath,
+		Data:         req.Data,
+	}
+
+	if err := h.repo.SetDeploymentConfigMap(r.Context(), configMap); err != nil {
+		log.Error().Err(err).Str("id", idStr).Str("configmap", req.Name).Msg("Failed to set configmap")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to set configmap")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("configmap", req.Name).
+		Int("files", len(req.Data)).
+		Msg("ConfigMap set")
+
+	response := ConfigMapToResponse(configMap)
+	response.RestartedWorkloads = h.restartWorkloads(r.Context(), deployment, configMap)
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// DeleteConfigMap handles DELETE /api/v1/deployments/{id}/configmaps/{name}
+func (h *ConfigMapHandler) DeleteConfigMap(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+
+	if err := h.repo.DeleteDeploymentConfigMap(r.Context(), id, name); err != nil {
+		log.Error().Err(err).Str("id", idStr).Str("configmap", name).Msg("Failed to delete configmap")
+		RespondWithError(w, http.StatusNotFound, "ConfigMap not found")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("configmap", name).
+		Msg("ConfigMap deleted")
+
+	// Pods keep mounting the ConfigMap until the next deploy renders the release without it
+	RespondWithSuccess(w, http.StatusOK, "ConfigMap deleted. It is unmounted on the next deploy.", nil)
+}
+
+// restartWorkloads pushes a changed ConfigMap to a running release and rolls its pods so they
+// read the new files. Deployments that are not running yet get the ConfigMap on their first
+// deploy. Failures are recorded in the deployment log rather than failing the request, since
+// the change is stored and applied by the next deploy regardless.
+func (h *ConfigMapHandler) restartWorkloads(ctx context.Context, deployment *state.Deployment, configMap *state.DeploymentConfigMap) []string {
+	infra, err := h.repo.GetInfrastructure(ctx, deployment.ID)
+	if err != nil || infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		return nil
+	}
+
+	logger := log.With().
+		Str("deployment_id", deployment.ID.String()).
+		Str("configmap", configMap.Name).
+		Logger()
+
+	restarted, err := applyConfigMap(ctx, infra, configMap)
+
+	entry := &state.DeploymentLog{
+		DeploymentID: deployment.ID,
+		Phase:        deployment.Status,
+		Level:        "INFO",
+		Source:       "configmap",
+		Message: fmt.Sprintf("Restarted %s after configmap %s changed",
+			strings.Join(restarted, ", "), configMap.Name),
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to restart workloads after configmap change")
+		entry.Level = "ERROR"
+		entry.Message = fmt.Sprintf("Failed to restart pods after configmap %s changed: %v", configMap.Name, err)
+	} else if len(restarted) == 0 {
+		entry.Message = fmt.Sprintf("Configmap %s updated, no running workloads to restart", configMap.Name)
+	}
+
+	if err := h.repo.CreateDeploymentLog(ctx, entry); err != nil {
+		logger.Warn().Err(err).Msg("Failed to record configmap restart")
+	}
+
+	return restarted
+}
+
+// applyConfigMap updates a ConfigMap in a release's namespace and restarts the release's
+// workloads, returning the restarted workload names
+func applyConfigMap(ctx context.Context, infra *state.Infrastructure, configMap *state.DeploymentConfigMap) ([]string, error) {
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	mount := deployer.ConfigMapMount{
+		Name:      configMap.Name,
+		MountPath: configMap.MountPath,
+		Data:      configMap.Data,
+	}
+	if err := deployer.ApplyConfigMap(ctx, kubeClient, infra.KubeNamespace, infra.HelmReleaseName, mount); err != nil {
+		return nil, err
+	}
+
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", infra.HelmReleaseName)
+	return kubeClient.RolloutRestart(ctx, infra.KubeNamespace, labelSelector)
+}
+
+// validateConfigMap checks a ConfigMap's name, file names, size and mount path
+func validateConfigMap(req *SetConfigMapRequest) error {
+	if !configMapNamePattern.MatchString(req.Name) || len(req.Name) > maxConfigMapNameLength {
+		return fmt.Errorf("name must be a lowercase DNS label of at most %d characters", maxConfigMapNameLength)
+	}
+
+	if len(req.Data) == 0 {
+		return fmt.Errorf("data must contain at least one file")
+	}
+
+	size := 0
+	for filename, content := range req.Data {
+		if !configMapKeyPattern.MatchString(filename) {
+			return fmt.Errorf("data: invalid file name %q", filename)
+		}
+		size += len(filename) + len(content)
+	}
+	if size > maxConfigMapSize {
+		return fmt.Errorf("data exceeds the 1MiB ConfigMap limit")
+	}
+
+	if req.MountPath != "" && (!path.IsAbs(req.MountPath) || path.Clean(req.MountPath) == "/") {
+		return fmt.Errorf("mount_path must be an absolute path other than /")
+	}
+
+	return nil
+}
@@ -1,25 +1,61 @@
 package api
 
 import (
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/costs"
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/recommendations"
 	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/google/uuid"
 )
 
 // DeploymentToResponse converts a state.Deployment to DeploymentResponse
 func DeploymentToResponse(d *state.Deployment) DeploymentResponse {
-	return DeploymentResponse{
-		ID:          d.ID,
-		Name:        d.Name,
-		AppName:     d.AppName,
-		Version:     d.Version,
-		Status:      d.Status,
-		Cloud:       d.Cloud,
-		Region:      d.Region,
-		ExternalIP:  d.ExternalIP,
-		ExternalURL: d.ExternalURL,
-		CreatedAt:   d.CreatedAt,
-		UpdatedAt:   d.UpdatedAt,
-		DeployedAt:  d.DeployedAt,
+	response := DeploymentResponse{
+		ID:                 d.ID,
+		Name:               d.Name,
+		AppName:            d.AppName,
+		Version:            d.Version,
+		Status:             d.Status,
+		Cloud:              d.Cloud,
+		Region:             d.Region,
+		DeployerType:       d.DeployerType,
+		Type:               d.DeploymentType,
+		Schedule:           d.Schedule,
+		Paused:             d.Paused,
+		PausedAt:           d.PausedAt,
+		ReconciliationMode: d.ReconciliationMode,
+		ScheduledAt:        d.ScheduledAt,
+		ClonedFromID:       d.ClonedFromID,
+		MirrorFrom:         d.MirrorFrom,
+		Tags:               d.Tags,
+		RequiresApproval:   d.RequiresApproval,
+		Approvers:          d.Approvers,
+		UserID:             d.UserID,
+		MonthlyBudgetUSD:   d.MonthlyBudgetUSD,
+		EgressAlertGB:      d.EgressAlertGB,
+		ExternalIP:         d.ExternalIP,
+		ExternalURL:        d.ExternalURL,
+		Error:              d.Error,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
+		DeployedAt:         d.DeployedAt,
+	}
+
+	if d.VPALastRecommendation != nil {
+		recommendation := VPARecommendationToResponse(d.ID, d.VPALastRecommendation)
+		response.VPALastRecommendation = &recommendation
+	}
+
+	// Deployments that never ran smoke tests store a JSON null
+	if len(d.SmokeTestResult) > 0 && string(d.SmokeTestResult) != "null" {
+		response.SmokeTestResult = d.SmokeTestResult
 	}
+
+	return response
 }
 
 // DeploymentsToResponse converts a slice of state.Deployment to DeploymentResponse
@@ -41,11 +77,68 @@ func InfrastructureToResponse(i *state.Infrastructure) InfrastructureResponse {
 		ServiceName:  i.ServiceName,
 		Status:       i.Status,
 		Config:       i.Config,
-		CreatedAt:    i.CreatedAt,
-		UpdatedAt:    i.UpdatedAt,
+
+		DriftDetected:       i.DriftDetected,
+		DriftDetails:        i.DriftDetails,
+		LastReconciledAt:    i.LastReconciledAt,
+		LastReconcileStatus: i.LastReconcileStatus,
+
+		ImportedExternally: i.ImportedExternally,
+
+		VPCServicePerimeter: i.VPCServicePerimeter,
+
+		EstimatedMonthlyCostUSD: i.EstimatedMonthlyCostUSD,
+
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
 	}
 }
 
+// CloudResourcesToResponse converts a slice of provisioner.CloudResource to CloudResourceResponse
+func CloudResourcesToResponse(resources []provisioner.CloudResource) []CloudResourceResponse {
+	responses := make([]CloudResourceResponse, len(resources))
+	for i, res := range resources {
+		responses[i] = CloudResourceResponse{
+			Type:       res.Type,
+			Name:       res.Name,
+			URN:        res.URN,
+			Status:     res.Status,
+			Properties: res.Properties,
+		}
+	}
+	return responses
+}
+
+// ReleaseRevisionsToResponse converts Helm release revisions to API responses
+func ReleaseRevisionsToResponse(revisions []deployer.ReleaseRevision) []ReleaseRevisionResponse {
+	responses := make([]ReleaseRevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		responses[i] = ReleaseRevisionResponse{
+			Revision:    rev.Revision,
+			Updated:     rev.Updated,
+			Status:      rev.Status,
+			Chart:       rev.Chart,
+			AppVersion:  rev.AppVersion,
+			Description: rev.Description,
+		}
+	}
+	return responses
+}
+
+// RollbackOptionsToResponse converts Helm rollback options to API responses
+func RollbackOptionsToResponse(options []deployer.RollbackOption) []RollbackOptionResponse {
+	responses := make([]RollbackOptionResponse, len(options))
+	for i, opt := range options {
+		responses[i] = RollbackOptionResponse{
+			Revision:   opt.Revision,
+			Status:     opt.Status,
+			ImageTag:   opt.ImageTag,
+			DeployedAt: opt.DeployedAt,
+		}
+	}
+	return responses
+}
+
 // BuildToResponse converts state.Build to BuildResponse
 func BuildToResponse(b *state.Build) BuildResponse {
 	return BuildResponse{
@@ -54,9 +147,530 @@ func BuildToResponse(b *state.Build) BuildResponse {
 		ImageTag:     b.ImageTag,
 		Status:       b.Status,
 		BuildLog:     b.BuildLog,
+		CacheKey:     b.CacheKey,
+		SBOMPath:     b.SBOMPath,
+		SBOMFormat:   b.SBOMFormat,
+		SignatureRef: b.SignatureRef,
+		ScanStatus:   b.ScanStatus,
 		StartedAt:    b.StartedAt,
 		CompletedAt:  b.CompletedAt,
 		CreatedAt:    b.CreatedAt,
 		UpdatedAt:    b.UpdatedAt,
 	}
 }
+
+// VulnerabilityScanToResponse converts state.VulnerabilityScan to VulnerabilityScanResponse
+func VulnerabilityScanToResponse(s *state.VulnerabilityScan) VulnerabilityScanResponse {
+	suppressedVulns := s.SuppressedVulns
+	if suppressedVulns == nil {
+		suppressedVulns = []string{}
+	}
+
+	return VulnerabilityScanResponse{
+		ID:              s.ID,
+		BuildID:         s.BuildID,
+		DeploymentID:    s.DeploymentID,
+		ImageTag:        s.ImageTag,
+		ScanTime:        s.ScanTime,
+		Passed:          s.Passed,
+		CriticalCount:   s.CriticalCount,
+		HighCount:       s.HighCount,
+		MediumCount:     s.MediumCount,
+		LowCount:        s.LowCount,
+		SuppressedCount: s.SuppressedCount,
+		SuppressedVulns: suppressedVulns,
+		RawResult:       s.RawResult,
+	}
+}
+
+// CVESuppressionToResponse converts state.CVESuppression to CVESuppressionResponse
+func CVESuppressionToResponse(s *state.CVESuppression) CVESuppressionResponse {
+	return CVESuppressionResponse{
+		ID:           s.ID,
+		CVEID:        s.CVEID,
+		UserID:       s.UserID,
+		Reason:       s.Reason,
+		DeploymentID: s.DeploymentID,
+		ExpiresAt:    s.ExpiresAt,
+		Active:       s.ExpiresAt == nil || s.ExpiresAt.After(time.Now()),
+		CreatedAt:    s.CreatedAt,
+	}
+}
+
+// RegistryCredentialToResponse converts state.RegistryCredential to RegistryCredentialResponse
+func RegistryCredentialToResponse(c *state.RegistryCredential) RegistryCredentialResponse {
+	return RegistryCredentialResponse{
+		ID:          c.ID,
+		RegistryURL: c.RegistryURL,
+		Username:    c.Username,
+		UserID:      c.UserID,
+		ExpiresAt:   c.ExpiresAt,
+		RotatedAt:   c.RotatedAt,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+// HelmChartToResponse converts state.HelmChart to HelmChartResponse
+func HelmChartToResponse(c *state.HelmChart) HelmChartResponse {
+	return HelmChartResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Version:     c.Version,
+		RegistryURL: c.RegistryURL,
+		Digest:      c.Digest,
+		PublishedBy: c.PublishedBy,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+// DeploymentLogsToResponse converts state.DeploymentLog entries to DeploymentLogResponse
+func DeploymentLogsToResponse(logs []state.DeploymentLog) []DeploymentLogResponse {
+	responses := make([]DeploymentLogResponse, len(logs))
+	for i, l := range logs {
+		responses[i] = DeploymentLogResponse{
+			Phase:     l.Phase,
+			Level:     l.Level,
+			Source:    l.Source,
+			Message:   l.Message,
+			Highlight: l.Highlight,
+			CreatedAt: l.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// PipelineToResponse converts a pipeline to PipelineResponse. Stages run one after another,
+// so each depends on the stage before it.
+func PipelineToResponse(p *state.Pipeline) PipelineResponse {
+	stages := make([]PipelineStageResponse, len(p.Stages))
+	for i, s := range p.Stages {
+		dependsOn := []string{}
+		if i > 0 {
+			dependsOn = append(dependsOn, p.Stages[i-1].Type)
+		}
+
+		stages[i] = PipelineStageResponse{
+			Type:        s.Type,
+			Status:      s.Status,
+			DependsOn:   dependsOn,
+			JobID:       s.JobID,
+			Error:       s.Error,
+			StartedAt:   s.StartedAt,
+			CompletedAt: s.CompletedAt,
+		}
+	}
+
+	response := PipelineResponse{
+		ID:           p.ID,
+		DeploymentID: p.DeploymentID,
+		Stages:       stages,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+	if p.CurrentStageIndex < len(p.Stages) {
+		response.CurrentStage = p.Stages[p.CurrentStageIndex].Type
+	}
+
+	return response
+}
+
+// DeploymentEventsToResponse converts deployment events to DeploymentEventResponse
+func DeploymentEventsToResponse(events []state.DeploymentEvent) []DeploymentEventResponse {
+	responses := make([]DeploymentEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = DeploymentEventResponse{
+			Type:      e.Type,
+			Message:   e.Message,
+			Timestamp: e.CreatedAt,
+			Metadata:  e.Metadata,
+		}
+	}
+	return responses
+}
+
+// VolumesToResponse converts deployer volume info to VolumeResponse
+func VolumesToResponse(volumes []deployer.VolumeInfo) []VolumeResponse {
+	responses := make([]VolumeResponse, len(volumes))
+	for i, v := range volumes {
+		responses[i] = VolumeResponse{
+			Name:         v.Name,
+			StorageClass: v.StorageClass,
+			Requested:    v.Requested,
+			Capacity:     v.Capacity,
+			Status:       v.Status,
+			AccessModes:  v.AccessModes,
+		}
+	}
+	return responses
+}
+
+// PodsToResponse converts deployer pod info to PodResponse
+func PodsToResponse(pods []deployer.PodInfo) []PodResponse {
+	responses := make([]PodResponse, len(pods))
+	for i, p := range pods {
+		containers := make([]ContainerStatusResponse, len(p.Containers))
+		for j, c := range p.Containers {
+			containers[j] = ContainerStatusResponse{
+				Name:     c.Name,
+				Image:    c.Image,
+				Ready:    c.Ready,
+				State:    c.State,
+				Reason:   c.Reason,
+				Restarts: c.Restarts,
+			}
+		}
+
+		responses[i] = PodResponse{
+			Name:              p.Name,
+			Status:            p.Status,
+			Node:              p.Node,
+			CreatedAt:         p.CreatedAt,
+			Age:               time.Since(p.CreatedAt).Round(time.Second).String(),
+			Restarts:          p.Restarts,
+			ContainerStatuses: containers,
+		}
+	}
+	return responses
+}
+
+// PodMetricsToResponse converts pod metrics and totals them over the deployment
+func PodMetricsToResponse(deploymentID uuid.UUID, metrics []deployer.PodMetrics) DeploymentMetricsResponse {
+	response := DeploymentMetricsResponse{
+		DeploymentID: deploymentID,
+		Pods:         make([]PodMetricsResponse, len(metrics)),
+	}
+	for i, m := range metrics {
+		response.Pods[i] = PodMetricsResponse{
+			PodName:       m.PodName,
+			CPUUsageMilli: m.CPUUsageMilli,
+			MemoryUsageMi: m.MemoryUsageMi,
+			Timestamp:     m.Timestamp,
+		}
+		response.TotalCPU += m.CPUUsageMilli
+		response.TotalMemory += m.MemoryUsageMi
+	}
+	return response
+}
+
+// VPARecommendationToResponse converts a deployment's VPA recommendation to VPARecommendationResponse
+func VPARecommendationToResponse(deploymentID uuid.UUID, r *state.VPARecommendation) VPARecommendationResponse {
+	return VPARecommendationResponse{
+		DeploymentID:             deploymentID,
+		Container:                r.Container,
+		RecommendedCPURequest:    r.CPURequest,
+		RecommendedMemoryRequest: r.MemoryRequest,
+		RecommendedCPULimit:      r.CPULimit,
+		RecommendedMemoryLimit:   r.MemoryLimit,
+		RecordedAt:               r.RecordedAt,
+	}
+}
+
+// AddonVersionToResponse converts a platform addon version to AddonVersionResponse
+func AddonVersionToResponse(v *deployer.AddonVersion) AddonVersionResponse {
+	return AddonVersionResponse{
+		Name:             v.Addon.Name,
+		Chart:            v.Addon.Chart,
+		Namespace:        v.Addon.Namespace,
+		Installed:        v.Installed,
+		CurrentVersion:   v.CurrentVersion,
+		AvailableVersion: v.AvailableVersion,
+		Pinned:           v.Addon.ChartVersion != "",
+		UpgradeAvailable: v.UpgradeAvailable,
+	}
+}
+
+// ResourceRecommendationToResponse converts a resource recommendation to ResourceRecommendationResponse
+func ResourceRecommendationToResponse(r *recommendations.Recommendation) ResourceRecommendationResponse {
+	return ResourceRecommendationResponse{
+		CPURequest:      r.CPURequest,
+		CPULimit:        r.CPULimit,
+		MemoryRequest:   r.MemoryRequest,
+		MemoryLimit:     r.MemoryLimit,
+		Replicas:        r.Replicas,
+		ConfidenceLevel: r.ConfidenceLevel,
+		BasedOn:         r.Samples,
+	}
+}
+
+// FederatedDeploymentToResponse converts a federated deployment and its members to FederatedDeploymentResponse
+func FederatedDeploymentToResponse(f *state.FederatedDeployment, members []state.Deployment) FederatedDeploymentResponse {
+	return FederatedDeploymentResponse{
+		ID:          f.ID,
+		Name:        f.Name,
+		AppName:     f.AppName,
+		Version:     f.Version,
+		Deployments: DeploymentsToResponse(members),
+		CreatedAt:   f.CreatedAt,
+	}
+}
+
+// FederatedMembersToResponse converts member deployments to FederatedMemberResponse
+func FederatedMembersToResponse(members []state.Deployment) []FederatedMemberResponse {
+	responses := make([]FederatedMemberResponse, len(members))
+	for i, m := range members {
+		responses[i] = FederatedMemberResponse{
+			DeploymentID: m.ID,
+			Cloud:        m.Cloud,
+			Region:       m.Region,
+			Status:       m.Status,
+			ExternalURL:  m.ExternalURL,
+			Error:        m.Error,
+		}
+	}
+	return responses
+}
+
+// EventsToResponse converts deployer event info to KubeEventResponse
+func EventsToResponse(events []deployer.EventInfo) []KubeEventResponse {
+	responses := make([]KubeEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = KubeEventResponse{
+			Type:     e.Type,
+			Reason:   e.Reason,
+			Message:  e.Message,
+			Object:   e.Object,
+			Count:    e.Count,
+			LastSeen: e.LastSeen,
+		}
+	}
+	return responses
+}
+
+// CostEstimateToResponse converts a costs.CostEstimate to CostEstimateResponse
+func CostEstimateToResponse(e *costs.CostEstimate, region string, addons []provisioner.AddonConfig) CostEstimateResponse {
+	response := CostEstimateResponse{
+		MonthlyCostUSD:      e.MonthlyCostUSD,
+		BreakdownByResource: e.BreakdownByResource,
+		Region:              region,
+		MachineType:         e.MachineType,
+		NodeCount:           e.NodeCount,
+	}
+	for _, addon := range addons {
+		response.NotIncluded = append(response.NotIncluded, addon.Type)
+	}
+	return response
+}
+
+// DeploymentBudgetToResponse compares a deployment's budget with the projected cost recorded on
+// its infrastructure, which may be nil when none is running
+func DeploymentBudgetToResponse(d *state.Deployment, infra *state.Infrastructure) DeploymentBudgetResponse {
+	response := DeploymentBudgetResponse{
+		DeploymentID:     d.ID.String(),
+		Name:             d.Name,
+		MonthlyBudgetUSD: d.MonthlyBudgetUSD,
+		AlertPercent:     d.BudgetAlertPercent,
+		AlertSentAt:      d.BudgetAlertSentAt,
+		Paused:           d.Paused,
+	}
+
+	if infra != nil {
+		response.MonthlyEstimateUSD = infra.EstimatedMonthlyCostUSD
+		response.CostUpdatedAt = infra.CostUpdatedAt
+	}
+	response.PercentUsed = costs.BudgetPercent(response.MonthlyEstimateUSD, d.MonthlyBudgetUSD)
+
+	return response
+}
+
+// ActualCostToResponse converts the incurred costs of a deployment's infrastructure
+func ActualCostToResponse(deploymentID string, c *costs.ActualCost) InfrastructureCostResponse {
+	response := InfrastructureCostResponse{
+		DeploymentID:        deploymentID,
+		DailyCostUSD:        c.DailyCostUSD,
+		MonthlyEstimateUSD:  c.MonthlyEstimateUSD,
+		BreakdownByResource: make([]ResourceCostResponse, 0, len(c.BreakdownByResource)),
+		LookbackDays:        c.LookbackDays,
+	}
+	for _, resource := range c.BreakdownByResource {
+		response.BreakdownByResource = append(response.BreakdownByResource, ResourceCostResponse{
+			ResourceType: resource.ResourceType,
+			Name:         resource.Name,
+			CostUSD:      resource.CostUSD,
+		})
+	}
+	return response
+}
+
+// NetworkStatsToResponse converts a cluster's network traffic to a response
+func NetworkStatsToResponse(d *state.Deployment, infra *state.Infrastructure, s *costs.NetworkStats) NetworkStatsResponse {
+	response := NetworkStatsResponse{
+		DeploymentID:     d.ID.String(),
+		EgressGBLast24h:  s.EgressGBLast24h,
+		IngressGBLast24h: s.IngressGBLast24h,
+		TopDestinations:  make([]NetworkDestinationResponse, 0, len(s.TopDestinations)),
+		EgressAlertGB:    d.EgressAlertGB,
+		LastCheckAt:      infra.LastEgressCheckAt,
+	}
+	for _, destination := range s.TopDestinations {
+		response.TopDestinations = append(response.TopDestinations, NetworkDestinationResponse{
+			Country:  destination.Country,
+			EgressGB: destination.EgressGB,
+		})
+	}
+	return response
+}
+
+// EnvVarToResponse converts a deployment environment variable, masking secret values
+func EnvVarToResponse(e *state.DeploymentEnvVar) EnvVarResponse {
+	value := e.Value
+	if e.IsSecret {
+		value = maskedSecretValue
+	}
+
+	return EnvVarResponse{
+		Key:       e.Key,
+		Value:     value,
+		IsSecret:  e.IsSecret,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+// ConfigMapToResponse converts a deployment ConfigMap, filling in the default mount path
+func ConfigMapToResponse(c *state.DeploymentConfigMap) ConfigMapResponse {
+	mountPath := c.MountPath
+	if mountPath == "" {
+		mountPath = deployer.DefaultConfigMapMountPath(c.Name)
+	}
+
+	return ConfigMapResponse{
+		Name:      c.Name,
+		MountPath: mountPath,
+		Data:      c.Data,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// HPAStatusToResponse converts the live state of a HorizontalPodAutoscaler
+func HPAStatusToResponse(deploymentID uuid.UUID, s *deployer.HPAStatus) HPAStatusResponse {
+	conditions := make([]HPAConditionResponse, 0, len(s.Conditions))
+	for _, c := range s.Conditions {
+		conditions = append(conditions, HPAConditionResponse{
+			Type:               c.Type,
+			Status:             c.Status,
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime,
+		})
+	}
+
+	return HPAStatusResponse{
+		DeploymentID:    deploymentID,
+		Name:            s.Name,
+		MinReplicas:     s.MinReplicas,
+		MaxReplicas:     s.MaxReplicas,
+		CurrentReplicas: s.CurrentReplicas,
+		DesiredReplicas: s.DesiredReplicas,
+		LastScaleTime:   s.LastScaleTime,
+		Conditions:      conditions,
+	}
+}
+
+// ResourcePolicyToResponse converts an admin-set state.ResourcePolicy to ResourcePolicyResponse
+func ResourcePolicyToResponse(p *state.ResourcePolicy) ResourcePolicyResponse {
+	updatedAt := p.UpdatedAt
+	return ResourcePolicyResponse{
+		MaxCPULimit:    p.MaxCPULimit,
+		MaxMemoryLimit: p.MaxMemoryLimit,
+		MaxReplicas:    p.MaxReplicas,
+		Source:         "admin",
+		UpdatedBy:      p.UpdatedBy,
+		UpdatedAt:      &updatedAt,
+	}
+}
+
+// ApprovalToResponse converts a state.DeploymentApproval to ApprovalResponse
+func ApprovalToResponse(a *state.DeploymentApproval) ApprovalResponse {
+	approvers := a.Approvers
+	if approvers == nil {
+		approvers = []string{}
+	}
+
+	return ApprovalResponse{
+		ID:           a.ID,
+		DeploymentID: a.DeploymentID,
+		RequestedBy:  a.RequestedBy,
+		Approvers:    approvers,
+		Status:       a.Status,
+		DecidedBy:    a.DecidedBy,
+		DecidedAt:    a.DecidedAt,
+		ExpiresAt:    a.ExpiresAt,
+		CreatedAt:    a.CreatedAt,
+	}
+}
+
+// OwnershipTransferToResponse converts a state.DeploymentOwnershipHistory to OwnershipTransferResponse
+func OwnershipTransferToResponse(h *state.DeploymentOwnershipHistory) OwnershipTransferResponse {
+	return OwnershipTransferResponse{
+		ID:            h.ID,
+		DeploymentID:  h.DeploymentID,
+		PreviousOwner: h.PreviousOwner,
+		NewOwner:      h.NewOwner,
+		TransferredBy: h.TransferredBy,
+		CreatedAt:     h.CreatedAt,
+	}
+}
+
+// OwnershipHistoryToResponse converts ownership history entries to responses
+func OwnershipHistoryToResponse(history []state.DeploymentOwnershipHistory) []OwnershipTransferResponse {
+	responses := make([]OwnershipTransferResponse, len(history))
+	for i := range history {
+		responses[i] = OwnershipTransferToResponse(&history[i])
+	}
+	return responses
+}
+
+// GitHookToResponse converts a state.GitHook to GitHookResponse
+func GitHookToResponse(h *state.GitHook) GitHookResponse {
+	return GitHookResponse{
+		ID:              h.ID,
+		DeploymentID:    h.DeploymentID,
+		Provider:        h.Provider,
+		RepoURL:         h.RepoURL,
+		Branch:          h.Branch,
+		SubPath:         h.SubPath,
+		WebhookURL:      "/api/v1/webhooks/" + h.Provider,
+		LastCommitSHA:   h.LastCommitSHA,
+		LastTriggeredAt: h.LastTriggeredAt,
+		CreatedAt:       h.CreatedAt,
+	}
+}
+
+// ServiceRouteToResponse converts a state.ServiceRoute to ServiceRouteResponse
+func ServiceRouteToResponse(r *state.ServiceRoute) ServiceRouteResponse {
+	return ServiceRouteResponse{
+		ID:                 r.ID,
+		SourceDeploymentID: r.SourceDeploymentID,
+		TargetDeploymentID: r.TargetDeploymentID,
+		PrefixRewrite:      r.Prefix,
+		WeightPercent:      r.WeightPercent,
+		CreatedAt:          r.CreatedAt,
+	}
+}
+
+// ABTestToResponse converts a state.ABTest to ABTestResponse
+func ABTestToResponse(t *state.ABTest) ABTestResponse {
+	return ABTestResponse{
+		ID:                   t.ID,
+		BaselineDeploymentID: t.BaselineDeploymentID,
+		VariantDeploymentID:  t.VariantDeploymentID,
+		HeaderName:           t.HeaderName,
+		HeaderValue:          t.HeaderValue,
+		RoutingWeight:        t.RoutingWeight,
+		Status:               t.Status,
+		WinnerDeploymentID:   t.WinnerDeploymentID,
+		CreatedAt:            t.CreatedAt,
+		ConcludedAt:          t.ConcludedAt,
+	}
+}
+
+// WorkerStatusToResponse converts a worker's heartbeat status to WorkerResponse
+func WorkerStatusToResponse(s queue.WorkerStatus) WorkerResponse {
+	return WorkerResponse{
+		Hostname:    s.Hostname,
+		PID:         s.PID,
+		Concurrency: s.Concurrency,
+		ActiveJobs:  s.ActiveJobs,
+		StartedAt:   s.StartedAt,
+		Version:     s.Version,
+	}
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/alvesdmateus/app-deployer/internal/costs"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// CostHandler handles cost estimation and incurred cost HTTP requests
+type CostHandler struct {
+	estimator *costs.Estimator
+	tracker   *costs.Tracker
+	monitor   *costs.NetworkMonitor
+	repo      *state.Repository
+}
+
+// NewCostHandler creates a new cost handler. estimator, tracker and monitor may be nil when
+// the Cloud Billing API, the billing export or Cloud Monitoring is not configured.
+func NewCostHandler(estimator *costs.Estimator, tracker *costs.Tracker, monitor *costs.NetworkMonitor, repo *state.Repository) *CostHandler {
+	return &CostHandler{
+		estimator: estimator,
+		tracker:   tracker,
+		monitor:   monitor,
+		repo:      repo,
+	}
+}
+
+// EstimateDeploymentCost handles POST /api/v1/deployments/estimate-cost
+func (h *CostHandler) EstimateDeploymentCost(w http.ResponseWriter, r *http.Request) {
+	var req CreateDeploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := provisioner.ValidateAddons(req.Addons); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Cloud == "" {
+		req.Cloud = "gcp" // default
+	}
+
+	if req.Cloud == "cloudrun" {
+		RespondWithError(w, http.StatusBadRequest, "Cost estimates are only available for GKE deployments")
+		return
+	}
+
+	if req.Region == "" {
+		req.Region = "us-central1" // default
+	}
+
+	if h.estimator == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Cost estimation unavailable - Cloud Billing API not configured")
+		return
+	}
+
+	// Deployments are created without infrastructure settings, so the provisioning defaults apply
+	estimate, err := h.estimator.EstimateGKECost(r.Context(), req.Region, nil)
+	if err != nil {
+		log.Error().Err(err).Str("region", req.Region).Msg("Failed to estimate deployment cost")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to estimate deployment cost")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, CostEstimateToResponse(estimate, req.Region, req.Addons))
+}
+
+// GetInfrastructureCost handles GET /api/v1/deployments/{id}/infrastructure/cost
+func (h *CostHandler) GetInfrastructureCost(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if _, err := h.repo.GetInfrastructure(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if h.tracker == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Cost tracking unavailable - billing export not configured")
+		return
+	}
+
+	cost, err := h.tracker.DeploymentCost(r.Context(), id.String())
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get incurred costs")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get infrastructure cost")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, ActualCostToResponse(id.String(), cost))
+}
+
+// GetNetworkStats handles GET /api/v1/deployments/{id}/network-stats
+// Traffic is queried live from Cloud Monitoring; only traffic to and from the internet counts.
+func (h *CostHandler) GetNetworkStats(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	deployment, err := h.repo.GetDeploymentByID(r.Context(), id)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if deployment.Cloud == "cloudrun" || infra.ClusterName == "" {
+		RespondWithError(w, http.StatusBadRequest, "Network stats are only available for GKE deployments")
+		return
+	}
+
+	if h.monitor == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Network stats unavailable - Cloud Monitoring not configured")
+		return
+	}
+
+	stats, err := h.monitor.ClusterNetworkStats(r.Context(), infra)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get network stats")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get network stats")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, NetworkStatsToResponse(deployment, infra, stats))
+}
+
+// GetCostSummary handles GET /api/v1/admin/costs/summary
+// Totals come from the projections the worker's daily cost job records. An optional
+// group_by query parameter also totals the costs per value of that deployment tag.
+func (h *CostHandler) GetCostSummary(w http.ResponseWriter, r *http.Request) {
+	groupBy := r.URL.Query().Get("group_by")
+
+	infras, err := h.repo.ListInfrastructureByStatus(r.Context(), "READY")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list infrastructure")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get cost summary")
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(infras))
+	for _, infra := range infras {
+		ids = append(ids, infra.DeploymentID)
+	}
+
+	deployments, err := h.repo.GetDeploymentsByIDs(r.Context(), ids)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get deployments")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get cost summary")
+		return
+	}
+
+	byID := make(map[uuid.UUID]*state.Deployment, len(deployments))
+	for i := range deployments {
+		byID[deployments[i].ID] = &deployments[i]
+	}
+
+	response := CostSummaryResponse{
+		Deployments: make([]DeploymentCostResponse, 0, len(infras)),
+		GroupBy:     groupBy,
+	}
+	if groupBy != "" {
+		response.Groups = make(map[string]float64)
+	}
+
+	for _, infra := range infras {
+		deployment, ok := byID[infra.DeploymentID]
+		if !ok {
+			continue
+		}
+
+		response.TotalMonthlyEstimateUSD += infra.EstimatedMonthlyCostUSD
+		response.Deployments = append(response.Deployments, DeploymentCostResponse{
+			DeploymentID:       deployment.ID.String(),
+			Name:               deployment.Name,
+			MonthlyEstimateUSD: infra.EstimatedMonthlyCostUSD,
+			UpdatedAt:          infra.CostUpdatedAt,
+		})
+
+		// Deployments without the tag are totalled under an empty key
+		if groupBy != "" {
+			response.Groups[deployment.Tags[groupBy]] += infra.EstimatedMonthlyCostUSD
+		}
+	}
+
+	response.TotalMonthlyEstimateUSD = math.Round(response.TotalMonthlyEstimateUSD*100) / 100
+	for key, total := range response.Groups {
+		response.Groups[key] = math.Round(total*100) / 100
+	}
+
+	sort.Slice(response.Deployments, func(i, j int) bool {
+		return response.Deployments[i].MonthlyEstimateUSD > response.Deployments[j].MonthlyEstimateUSD
+	})
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// GetBudgetStatus handles GET /api/v1/admin/budget-status
+// Spend is the projection the worker's daily cost job records; deployments without running
+// infrastructure report no spend.
+func (h *CostHandler) GetBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	deployments, err := h.repo.ListBudgetedDeployments(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list budgeted deployments")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get budget status")
+		return
+	}
+
+	infras, err := h.repo.ListInfrastructureByStatus(r.Context(), "READY")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list infrastructure")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get budget status")
+		return
+	}
+
+	byDeployment := make(map[uuid.UUID]*state.Infrastructure, len(infras))
+	for _, infra := range infras {
+		byDeployment[infra.DeploymentID] = infra
+	}
+
+	response := BudgetStatusResponse{
+		Deployments: make([]DeploymentBudgetResponse, 0, len(deployments)),
+	}
+
+	for i := range deployments {
+		status := DeploymentBudgetToResponse(&deployments[i], byDeployment[deployments[i].ID])
+		response.TotalMonthlyBudgetUSD += status.MonthlyBudgetUSD
+		response.TotalMonthlyEstimateUSD += status.MonthlyEstimateUSD
+		response.Deployments = append(response.Deployments, status)
+	}
+
+	response.TotalMonthlyBudgetUSD = math.Round(response.TotalMonthlyBudgetUSD*100) / 100
+	response.TotalMonthlyEstimateUSD = math.Round(response.TotalMonthlyEstimateUSD*100) / 100
+
+	sort.Slice(response.Deployments, func(i, j int) bool {
+		return response.Deployments[i].PercentUsed > response.Deployments[j].PercentUsed
+	})
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// GetDeploymentDependencies handles GET /api/v1/deployments/{id}/dependencies
+func (h *DeploymentHandler) GetDeploymentDependencies(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	dependencies, err := h.dependencyTree(r.Context(), id, map[uuid.UUID]bool{id: true})
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment dependencies")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get deployment dependencies")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, DependencyTreeResponse{
+		DeploymentID: id,
+		Dependencies: dependencies,
+	})
+}
+
+// dependencyTree resolves a deployment's dependencies and theirs in turn. visited holds the
+// deployments on the current path so a cycle cannot recurse forever.
+func (h *DeploymentHandler) dependencyTree(ctx context.Context, id uuid.UUID, visited map[uuid.UUID]bool) ([]DependencyNodeResponse, error) {
+	ids, err := h.repo.ListDeploymentDependencies(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := h.repo.GetDeploymentsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*state.Deployment, len(deployments))
+	for i := range deployments {
+		byID[deployments[i].ID] = &deployments[i]
+	}
+
+	nodes := make([]DependencyNodeResponse, 0, len(ids))
+	for _, depID := range ids {
+		node := DependencyNodeResponse{
+			DeploymentID: depID,
+			Status:       "DELETED",
+		}
+
+		if d, ok := byID[depID]; ok {
+			node.Name = d.Name
+			node.Status = d.Status
+			node.Ready = d.Status == "EXPOSED" || d.Status == "HEALTHY"
+
+			if !visited[depID] {
+				visited[depID] = true
+				node.Dependencies, err = h.dependencyTree(ctx, depID, visited)
+				delete(visited, depID)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// validateDependencies checks that every dependency is listed once and exists
+func (h *DeploymentHandler) validateDependencies(ctx context.Context, dependencies []uuid.UUID) error {
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(dependencies))
+	for i, id := range dependencies {
+		if seen[id] {
+			return fmt.Errorf("dependencies[%d]: duplicate dependency %s", i, id)
+		}
+		seen[id] = true
+	}
+
+	existing, err := h.repo.GetDeploymentsByIDs(ctx, dependencies)
+	if err != nil {
+		return fmt.Errorf("failed to look up dependencies")
+	}
+
+	found := make(map[uuid.UUID]bool, len(existing))
+	for _, d := range existing {
+		found[d.ID] = true
+	}
+	for i, id := range dependencies {
+		if !found[id] {
+			return fmt.Errorf("dependencies[%d]: deployment %s not found", i, id)
+		}
+	}
+
+	return nil
+}
+
+// deploymentResponse converts a deployment and lists the dependencies it is waiting on
+func (h *DeploymentHandler) deploymentResponse(ctx context.Context, deployment *state.Deployment) DeploymentResponse {
+	response := DeploymentToResponse(deployment)
+
+	blocking, err := h.repo.GetBlockingDependencies(ctx, deployment.ID)
+	if err != nil {
+		log.Warn().Err(err).Str("deployment_id", deployment.ID.String()).Msg("Failed to get blocking dependencies")
+		return response
+	}
+
+	for _, id := range blocking {
+		response.BlockedBy = append(response.BlockedBy, id.String())
+	}
+
+	return response
+}
@@ -1,51 +1,200 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
 	"github.com/alvesdmateus/app-deployer/internal/orchestrator"
+	"github.com/alvesdmateus/app-deployer/internal/policy"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
 	"github.com/alvesdmateus/app-deployer/internal/queue"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+var (
+	// Tags become GCP resource labels and Kubernetes labels, so they follow the stricter of
+	// the two formats: lowercase, at most 63 characters, values may be empty
+	tagKeyPattern   = regexp.MustCompile(`^[a-z]([-_a-z0-9]{0,61}[a-z0-9])?$`)
+	tagValuePattern = regexp.MustCompile(`^([a-z0-9]([-_a-z0-9]{0,61}[a-z0-9])?)?$`)
+
+	// reservedTagKeys are labels app-deployer sets itself to identify an app's resources
+	reservedTagKeys = map[string]bool{"app": true, "deployment-id": true, "managed-by": true}
+
+	// Cloud Armor policy names are GCP resource names
+	securityPolicyPattern = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+)
+
+// maxRateLimitPaths caps the path prefixes of a Cloud Armor rate limit
+const maxRateLimitPaths = 5
+
 // DeploymentHandler handles deployment-related HTTP requests
 type DeploymentHandler struct {
-	repo       *state.Repository
-	orchClient *orchestrator.Client
+	repo           *state.Repository
+	orchClient     *orchestrator.Client
+	workers        *queue.RedisQueue    // Where workers register their heartbeats, nil without Redis
+	adminToken     string               // Admins may approve any rollout
+	approvalExpiry time.Duration        // How long rollouts wait for approval
+	policies       *policy.OPAEvaluator // Admits create and start requests, nil admits all
 }
 
-// NewDeploymentHandler creates a new deployment handler
-func NewDeploymentHandler(repo *state.Repository, orchClient *orchestrator.Client) *DeploymentHandler {
+// NewDeploymentHandler creates a new deployment handler. Rollouts of deployments that require
+// approval wait up to approvalExpiry for an approver or an admin bearing adminToken. workers may
+// be nil when Redis is unavailable, in which case no workers are listed.
+func NewDeploymentHandler(repo *state.Repository, orchClient *orchestrator.Client, workers *queue.RedisQueue, adminToken string, approvalExpiry time.Duration, policies *policy.OPAEvaluator) *DeploymentHandler {
 	return &DeploymentHandler{
-		repo:       repo,
-		orchClient: orchClient,
+		repo:           repo,
+		orchClient:     orchClient,
+		workers:        workers,
+		adminToken:     adminToken,
+		approvalExpiry: approvalExpiry,
+		policies:       policies,
 	}
 }
 
 // CreateDeployment handles POST /api/v1/deployments
 func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Request) {
 	var req CreateDeploymentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeValidated(w, r, deploymentSchema, &req) {
 		return
 	}
 
+	h.createDeployment(w, r, req, nil)
+}
+
+// createDeployment validates and records a deployment, starting provisioning when the request
+// carries an image tag. setup, when set, is run once the deployment is recorded to store the
+// rest of its configuration before anything is provisioned.
+func (h *DeploymentHandler) createDeployment(w http.ResponseWriter, r *http.Request, req CreateDeploymentRequest, setup func(*state.Deployment) error) {
 	// Validate request
 	if req.Name == "" || req.AppName == "" || req.Version == "" {
 		RespondWithError(w, http.StatusBadRequest, "Name, app_name, and version are required")
 		return
 	}
 
+	if err := provisioner.ValidateAddons(req.Addons); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if req.Cloud == "" {
 		req.Cloud = "gcp" // default
 	}
 
+	if err := validateDeploymentType(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateHooks(req.Hooks, req.Cloud); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateWorkloadIdentity(req.WorkloadIdentity, req.Cloud); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateWAF(req.WAF, req.Cloud, req.DeploymentType); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateNodeAffinity(req.NodeAffinity, req.Cloud); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validatePDB(req.PDB, req.Cloud, req.DeploymentType); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateSidecars(req.Sidecars, req.Cloud); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateInitContainers(req.InitContainers, req.Cloud); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateSmokeTests(req.SmokeTests); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for key, value := range req.Tags {
+		if err := validateTag(key, value); err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := h.validateDependencies(r.Context(), req.Dependencies); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validateMirrorFrom(r.Context(), req.MirrorFrom, req.Cloud); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateScheduledAt(req.ScheduledAt); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.ScheduledAt != nil && req.ImageTag == "" {
+		RespondWithError(w, http.StatusBadRequest, "image_tag is required when scheduled_at is set")
+		return
+	}
+
+	// Rollouts that need approval are only started through the deploy endpoint
+	if req.RequiresApproval && req.ImageTag != "" {
+		RespondWithError(w, http.StatusBadRequest,
+			"image_tag cannot be set on deployments that require approval, start them with POST /deployments/{id}/deploy")
+		return
+	}
+
+	for _, approver := range req.Approvers {
+		if !isTokenActor(approver) {
+			RespondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("approver %q must be key: followed by the first %d hex characters of the SHA-256 of its bearer token", approver, actorFingerprintLength))
+			return
+		}
+	}
+
+	if req.MonthlyBudgetUSD < 0 {
+		RespondWithError(w, http.StatusBadRequest, "monthly_budget_usd must not be negative")
+		return
+	}
+
+	if req.EgressAlertGB < 0 {
+		RespondWithError(w, http.StatusBadRequest, "egress_alert_gb must not be negative")
+		return
+	}
+
+	if !h.admitRequest(w, r, req) {
+		return
+	}
+
 	if req.Region == "" {
 		req.Region = "us-central1" // default
 	}
@@ -58,32 +207,152 @@ func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Requ
 
 	// Create deployment
 	deployment := &state.Deployment{
-		Name:    req.Name,
-		AppName: req.AppName,
-		Version: req.Version,
-		Status:  "PENDING",
-		Cloud:   req.Cloud,
-		Region:  req.Region,
-		Port:    port,
+		Name:           req.Name,
+		AppName:        req.AppName,
+		Version:        req.Version,
+		Status:         "PENDING",
+		Cloud:          req.Cloud,
+		Region:         req.Region,
+		Port:           port,
+		DeploymentType: req.DeploymentType,
+		Tags:           req.Tags,
+
+		RequiresApproval: req.RequiresApproval,
+		Approvers:        req.Approvers,
+		MonthlyBudgetUSD: req.MonthlyBudgetUSD,
+		EgressAlertGB:    req.EgressAlertGB,
+	}
+
+	if req.CronJob != nil {
+		deployment.Schedule = req.CronJob.Schedule
+		deployment.ConcurrencyPolicy = req.CronJob.Concurrency
+		deployment.StartingDeadlineSeconds = req.CronJob.StartingDeadlineSeconds
+	}
+
+	if req.Storage != nil {
+		deployment.StorageClass = req.Storage.StorageClass
+		deployment.StorageSize = req.Storage.Size
+		deployment.StorageMountPath = req.Storage.MountPath
+	}
+
+	if req.Hooks != nil {
+		hooks, err := json.Marshal(req.Hooks)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid hooks")
+			return
+		}
+		deployment.Hooks = string(hooks)
+	}
+
+	if len(req.SmokeTests) > 0 {
+		smokeTests, err := json.Marshal(req.SmokeTests)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid smoke tests")
+			return
+		}
+		deployment.SmokeTests = string(smokeTests)
+	}
+
+	if req.WorkloadIdentity != nil && req.WorkloadIdentity.Enabled {
+		deployment.WorkloadIdentity = true
+		deployment.GCPServiceAccountEmail = req.WorkloadIdentity.GCPServiceAccountEmail
+	}
+
+	if req.WAF != nil && req.WAF.EnableCloudArmor {
+		deployment.CloudArmorEnabled = true
+		deployment.CloudArmorPolicy = req.WAF.SecurityPolicy
+		if deployment.CloudArmorPolicy == "" {
+			deployment.CloudArmorPolicy = "default"
+		}
+
+		if req.WAF.RateLimit != nil {
+			deployment.RateLimitRequestsPerMinute = req.WAF.RateLimit.RequestsPerMinutePerIP
+			deployment.RateLimitPaths = req.WAF.RateLimit.EnforceOnURIPaths
+		}
+	}
+
+	if req.NodeAffinity != nil {
+		nodeAffinity, err := json.Marshal(req.NodeAffinity)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid node_affinity")
+			return
+		}
+		deployment.NodeAffinity = string(nodeAffinity)
+	}
+
+	if len(req.Sidecars) > 0 {
+		sidecars, err := json.Marshal(req.Sidecars)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid sidecars")
+			return
+		}
+		deployment.Sidecars = string(sidecars)
+	}
+
+	if len(req.InitContainers) > 0 {
+		initContainers, err := json.Marshal(req.InitContainers)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid init containers")
+			return
+		}
+		deployment.InitContainers = string(initContainers)
+	}
+
+	if req.PDB != nil && req.PDB.Enabled {
+		deployment.PDBEnabled = true
+		deployment.PDBMinAvailable = req.PDB.MinAvailable
+		deployment.PDBMaxUnavailable = req.PDB.MaxUnavailable
 	}
 
+	deployment.MirrorFrom = req.MirrorFrom
+
 	if err := h.repo.CreateDeployment(r.Context(), deployment); err != nil {
 		log.Error().Err(err).Msg("Failed to create deployment")
 		RespondWithError(w, http.StatusInternalServerError, "Failed to create deployment")
 		return
 	}
 
-	// Trigger provision job if orchestrator is available and image_tag is provided
-	if h.orchClient != nil && req.ImageTag != "" {
-		provisionPayload := &queue.ProvisionPayload{
-			DeploymentID: deployment.ID.String(),
-			AppName:      deployment.AppName,
-			Version:      deployment.Version,
-			Cloud:        deployment.Cloud,
-			Region:       deployment.Region,
-			ImageTag:     req.ImageTag,
+	if err := h.repo.CreateDeploymentDependencies(r.Context(), deployment.ID, req.Dependencies); err != nil {
+		log.Error().Err(err).Str("deployment_id", deployment.ID.String()).Msg("Failed to record dependencies")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to create deployment")
+		return
+	}
+
+	if setup != nil {
+		if err := setup(deployment); err != nil {
+			log.Error().Err(err).Str("deployment_id", deployment.ID.String()).Msg("Failed to configure deployment")
+			RespondWithError(w, http.StatusInternalServerError, "Deployment created but its configuration could not be stored")
+			return
+		}
+	}
+
+	provisionPayload := &queue.ProvisionPayload{
+		DeploymentID: deployment.ID.String(),
+		AppName:      deployment.AppName,
+		Version:      deployment.Version,
+		Cloud:        deployment.Cloud,
+		Region:       deployment.Region,
+		ImageTag:     req.ImageTag,
+		Addons:       req.Addons,
+	}
+
+	// Scheduled deployments stay PENDING until the worker's scheduler starts them
+	if req.ScheduledAt != nil {
+		if err := h.scheduleProvision(r, deployment, provisionPayload, *req.ScheduledAt); err != nil {
+			log.Error().Err(err).
+				Str("deployment_id", deployment.ID.String()).
+				Msg("Failed to schedule deployment")
+			RespondWithError(w, http.StatusInternalServerError,
+				"Deployment created but scheduling failed")
+			return
 		}
 
+		RespondWithJSON(w, http.StatusCreated, h.deploymentResponse(r.Context(), deployment))
+		return
+	}
+
+	// Trigger provision job if orchestrator is available and image_tag is provided
+	if h.orchClient != nil && req.ImageTag != "" {
 		if err := h.orchClient.TriggerProvision(r.Context(), provisionPayload); err != nil {
 			log.Error().Err(err).
 				Str("deployment_id", deployment.ID.String()).
@@ -102,12 +371,14 @@ func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Requ
 		deployment.Status = "QUEUED"
 	}
 
-	response := DeploymentToResponse(deployment)
+	response := h.deploymentResponse(r.Context(), deployment)
 	RespondWithJSON(w, http.StatusCreated, response)
 }
 
-// GetDeployment handles GET /api/v1/deployments/{id}
-func (h *DeploymentHandler) GetDeployment(w http.ResponseWriter, r *http.Request) {
+// CloneDeployment handles POST /api/v1/deployments/{id}/clone
+// The clone copies the source's settings, environment variables and ConfigMaps but none of its
+// infrastructure or rollout state; it stays PENDING until a deployment is started for it.
+func (h *DeploymentHandler) CloneDeployment(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -115,57 +386,162 @@ func (h *DeploymentHandler) GetDeployment(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	// The body is optional; without one the clone keeps the source's name, cloud and region
+	var req CloneDeploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	source, err := h.repo.GetDeployment(r.Context(), id)
 	if err != nil {
 		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
 		RespondWithError(w, http.StatusNotFound, "Deployment not found")
 		return
 	}
 
-	response := DeploymentToResponse(deployment)
-	RespondWithJSON(w, http.StatusOK, response)
+	clone := &state.Deployment{
+		Name:                       req.Name,
+		AppName:                    source.AppName,
+		Version:                    source.Version,
+		Status:                     "PENDING",
+		Cloud:                      req.Cloud,
+		Region:                     req.Region,
+		Port:                       source.Port,
+		DeployerType:               source.DeployerType,
+		RepoURL:                    source.RepoURL,
+		KustomizePath:              source.KustomizePath,
+		CPULimit:                   source.CPULimit,
+		MemoryLimit:                source.MemoryLimit,
+		CPURequest:                 source.CPURequest,
+		MemoryRequest:              source.MemoryRequest,
+		DeploymentType:             source.DeploymentType,
+		Tags:                       source.Tags,
+		RequiresApproval:           source.RequiresApproval,
+		Approvers:                  source.Approvers,
+		MonthlyBudgetUSD:           source.MonthlyBudgetUSD,
+		EgressAlertGB:              source.EgressAlertGB,
+		Schedule:                   source.Schedule,
+		ConcurrencyPolicy:          source.ConcurrencyPolicy,
+		StartingDeadlineSeconds:    source.StartingDeadlineSeconds,
+		StorageClass:               source.StorageClass,
+		StorageSize:                source.StorageSize,
+		StorageMountPath:           source.StorageMountPath,
+		Hooks:                      source.Hooks,
+		NodeAffinity:               source.NodeAffinity,
+		Sidecars:                   source.Sidecars,
+		InitContainers:             source.InitContainers,
+		SmokeTests:                 source.SmokeTests,
+		WorkloadIdentity:           source.WorkloadIdentity,
+		GCPServiceAccountEmail:     source.GCPServiceAccountEmail,
+		CloudArmorEnabled:          source.CloudArmorEnabled,
+		CloudArmorPolicy:           source.CloudArmorPolicy,
+		RateLimitRequestsPerMinute: source.RateLimitRequestsPerMinute,
+		RateLimitPaths:             source.RateLimitPaths,
+		PDBEnabled:                 source.PDBEnabled,
+		PDBMinAvailable:            source.PDBMinAvailable,
+		PDBMaxUnavailable:          source.PDBMaxUnavailable,
+		ReconciliationMode:         source.ReconciliationMode,
+	}
+
+	if clone.Name == "" {
+		clone.Name = source.Name + "-clone"
+	}
+	if clone.Cloud == "" {
+		clone.Cloud = source.Cloud
+	}
+	if clone.Region == "" {
+		clone.Region = source.Region
+	}
+
+	if err := validateCloneCloud(clone); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.repo.CloneDeployment(r.Context(), clone, source.ID); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to clone deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to clone deployment")
+		return
+	}
+
+	log.Info().
+		Str("source_id", source.ID.String()).
+		Str("deployment_id", clone.ID.String()).
+		Str("cloud", clone.Cloud).
+		Str("region", clone.Region).
+		Msg("Deployment cloned")
+
+	RespondWithJSON(w, http.StatusCreated, h.deploymentResponse(r.Context(), clone))
 }
 
-// ListDeployments handles GET /api/v1/deployments
-func (h *DeploymentHandler) ListDeployments(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+// validateCloneCloud checks that the settings copied into a clone are supported on its cloud,
+// which may differ from the source's
+func validateCloneCloud(clone *state.Deployment) error {
+	if clone.Cloud != "cloudrun" {
+		return nil
+	}
 
-	limit := 20 // default
-	offset := 0
+	if clone.DeploymentType != "" && clone.DeploymentType != deployer.DeploymentTypeService {
+		return fmt.Errorf("%s deployments are not supported on cloudrun", clone.DeploymentType)
+	}
 
-	if limitStr != "" {
-		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
-			limit = parsed
+	if clone.Hooks != "" {
+		var hooks deployer.HooksConfig
+		if err := json.Unmarshal([]byte(clone.Hooks), &hooks); err != nil {
+			return fmt.Errorf("invalid hooks on source deployment: %w", err)
+		}
+		if err := validateHooks(&hooks, clone.Cloud); err != nil {
+			return err
 		}
 	}
 
-	if offsetStr != "" {
-		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
-			offset = parsed
-		}
+	if err := validateWAF(&WAFRequest{EnableCloudArmor: clone.CloudArmorEnabled}, clone.Cloud, clone.DeploymentType); err != nil {
+		return err
+	}
+
+	if err := validatePDB(&PDBRequest{Enabled: clone.PDBEnabled}, clone.Cloud, clone.DeploymentType); err != nil {
+		return err
+	}
+
+	if clone.NodeAffinity != "" {
+		return fmt.Errorf("node_affinity is not supported on cloudrun")
+	}
+
+	if clone.Sidecars != "" {
+		return fmt.Errorf("sidecars are not supported on cloudrun")
 	}
 
-	deployments, err := h.repo.ListDeployments(r.Context(), limit, offset)
+	if clone.InitContainers != "" {
+		return fmt.Errorf("init_containers are not supported on cloudrun")
+	}
+
+	return validateWorkloadIdentity(&WorkloadIdentityRequest{Enabled: clone.WorkloadIdentity}, clone.Cloud)
+}
+
+// GetDeployment handles GET /api/v1/deployments/{id}
+func (h *DeploymentHandler) GetDeployment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to list deployments")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to list deployments")
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
 		return
 	}
 
-	response := ListDeploymentsResponse{
-		Deployments: DeploymentsToResponse(deployments),
-		Total:       len(deployments),
-		Limit:       limit,
-		Offset:      offset,
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
 	}
 
+	response := h.deploymentResponse(r.Context(), deployment)
 	RespondWithJSON(w, http.StatusOK, response)
 }
 
-// UpdateDeploymentStatus handles PATCH /api/v1/deployments/{id}/status
-func (h *DeploymentHandler) UpdateDeploymentStatus(w http.ResponseWriter, r *http.Request) {
+// GetDeploymentLogs handles GET /api/v1/deployments/{id}/logs
+// An optional phase query parameter limits the entries to one status, e.g. ?phase=DEPLOYING
+func (h *DeploymentHandler) GetDeploymentLogs(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -173,28 +549,30 @@ func (h *DeploymentHandler) UpdateDeploymentStatus(w http.ResponseWriter, r *htt
 		return
 	}
 
-	var req UpdateDeploymentStatusRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
 		return
 	}
 
-	if req.Status == "" {
-		RespondWithError(w, http.StatusBadRequest, "Status is required")
+	phase := r.URL.Query().Get("phase")
+	search := strings.TrimSpace(r.URL.Query().Get("q"))
+	logs, err := h.repo.ListDeploymentLogs(r.Context(), id, phase, search)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list deployment logs")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list deployment logs")
 		return
 	}
 
-	if err := h.repo.UpdateDeploymentStatus(r.Context(), id, req.Status); err != nil {
-		log.Error().Err(err).Str("id", idStr).Msg("Failed to update deployment status")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to update deployment status")
-		return
+	response := DeploymentLogsResponse{
+		DeploymentID: id,
+		Logs:         DeploymentLogsToResponse(logs),
 	}
-
-	RespondWithSuccess(w, http.StatusOK, "Deployment status updated", nil)
+	RespondWithJSON(w, http.StatusOK, response)
 }
 
-// DeleteDeployment handles DELETE /api/v1/deployments/{id}
-func (h *DeploymentHandler) DeleteDeployment(w http.ResponseWriter, r *http.Request) {
+// GetDeploymentPipeline handles GET /api/v1/deployments/{id}/pipeline
+func (h *DeploymentHandler) GetDeploymentPipeline(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -202,35 +580,502 @@ func (h *DeploymentHandler) DeleteDeployment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Get deployment to check for infrastructure
-	deployment, err := h.repo.GetDeployment(r.Context(), id)
-	if err != nil {
-		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
 		RespondWithError(w, http.StatusNotFound, "Deployment not found")
 		return
 	}
 
-	// If deployment has infrastructure, trigger destroy job
-	if h.orchClient != nil && deployment.InfrastructureID != nil {
-		destroyPayload := &queue.DestroyPayload{
-			DeploymentID:     id.String(),
-			InfrastructureID: deployment.InfrastructureID.String(),
-		}
-
-		if err := h.orchClient.TriggerDestroy(r.Context(), destroyPayload); err != nil {
-			log.Error().Err(err).
-				Str("deployment_id", idStr).
-				Msg("Failed to trigger destroy job")
-			RespondWithError(w, http.StatusInternalServerError,
-				"Failed to initiate destruction process")
-			return
-		}
+	pipeline, err := h.repo.GetPipeline(r.Context(), id)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, "Deployment has not run a pipeline yet")
+		return
+	}
 
-		// Update status to DESTROYING
-		_ = h.repo.UpdateDeploymentStatus(r.Context(), id, "DESTROYING")
+	RespondWithJSON(w, http.StatusOK, PipelineToResponse(pipeline))
+}
 
-		RespondWithSuccess(w, http.StatusAccepted,
-			"Destruction initiated. Infrastructure will be cleaned up asynchronously.", nil)
+// GetDeploymentEvents handles GET /api/v1/deployments/{id}/events
+// Events are returned oldest first; ?since=<RFC 3339 timestamp> returns only newer events for polling
+func (h *DeploymentHandler) GetDeploymentEvents(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var since time.Time
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		since, err = time.Parse(time.RFC3339Nano, sinceStr)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "since must be an RFC 3339 timestamp")
+			return
+		}
+	}
+
+	limit := 100 // default
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	events, err := h.repo.ListDeploymentEvents(r.Context(), id, since, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list deployment events")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list deployment events")
+		return
+	}
+
+	response := DeploymentEventsResponse{
+		DeploymentID: id,
+		Events:       DeploymentEventsToResponse(events),
+		Limit:        limit,
+		Offset:       offset,
+	}
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// ListDeployments handles GET /api/v1/deployments
+func (h *DeploymentHandler) ListDeployments(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	limitStr := r.URL.Query().Get("limit")
+	offsetStr := r.URL.Query().Get("offset")
+
+	limit := 20 // default
+	offset := 0
+
+	if limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	if offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	filter := state.DeploymentFilter{
+		Status:    r.URL.Query().Get("status"),
+		Cloud:     r.URL.Query().Get("cloud"),
+		Region:    r.URL.Query().Get("region"),
+		Scheduled: r.URL.Query().Get("scheduled") == "true",
+		UserID:    r.URL.Query().Get("user_id"),
+	}
+
+	if clonedFrom := r.URL.Query().Get("cloned_from"); clonedFrom != "" {
+		sourceID, err := uuid.Parse(clonedFrom)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid cloned_from deployment ID")
+			return
+		}
+		filter.ClonedFrom = &sourceID
+	}
+
+	// Repeated tag=key:value parameters must all match
+	for _, tag := range r.URL.Query()["tag"] {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok || key == "" {
+			RespondWithError(w, http.StatusBadRequest, "tag filters must be key:value")
+			return
+		}
+		if filter.Tags == nil {
+			filter.Tags = make(map[string]string)
+		}
+		filter.Tags[key] = value
+	}
+
+	deployments, err := h.repo.ListDeploymentsFiltered(r.Context(), filter, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list deployments")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list deployments")
+		return
+	}
+
+	response := ListDeploymentsResponse{
+		Deployments: DeploymentsToResponse(deployments),
+		Total:       len(deployments),
+		Limit:       limit,
+		Offset:      offset,
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// UpdateDeploymentTags handles PATCH /api/v1/deployments/{id}/tags
+// Tags are merged into the existing ones and take effect on the next rollout.
+func (h *DeploymentHandler) UpdateDeploymentTags(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req UpdateTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.Tags) == 0 {
+		RespondWithError(w, http.StatusBadRequest, "tags is required")
+		return
+	}
+
+	set := make(map[string]string)
+	var remove []string
+	for key, value := range req.Tags {
+		if value == nil {
+			remove = append(remove, key)
+			continue
+		}
+		if err := validateTag(key, *value); err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		set[key] = *value
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	tags, err := h.repo.MergeDeploymentTags(r.Context(), id, set, remove)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to update deployment tags")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update deployment tags")
+		return
+	}
+	deployment.Tags = tags
+
+	RespondWithJSON(w, http.StatusOK, h.deploymentResponse(r.Context(), deployment))
+}
+
+// UpdateDeploymentBudget handles PUT /api/v1/deployments/{id}/budget
+func (h *DeploymentHandler) UpdateDeploymentBudget(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req UpdateBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.MonthlyBudgetUSD == nil {
+		RespondWithError(w, http.StatusBadRequest, "monthly_budget_usd is required")
+		return
+	}
+
+	if *req.MonthlyBudgetUSD < 0 {
+		RespondWithError(w, http.StatusBadRequest, "monthly_budget_usd must not be negative")
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if err := h.repo.SetDeploymentBudget(r.Context(), id, *req.MonthlyBudgetUSD); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to update deployment budget")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update deployment budget")
+		return
+	}
+	deployment.MonthlyBudgetUSD = *req.MonthlyBudgetUSD
+	deployment.BudgetAlertPercent = 0
+	deployment.BudgetAlertSentAt = nil
+
+	RespondWithJSON(w, http.StatusOK, h.deploymentResponse(r.Context(), deployment))
+}
+
+// UpdateDeploymentStatus handles PATCH /api/v1/deployments/{id}/status
+func (h *DeploymentHandler) UpdateDeploymentStatus(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req UpdateDeploymentStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Status == "" {
+		RespondWithError(w, http.StatusBadRequest, "Status is required")
+		return
+	}
+
+	if err := h.repo.UpdateDeploymentStatus(r.Context(), id, req.Status); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to update deployment status")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update deployment status")
+		return
+	}
+
+	RespondWithSuccess(w, http.StatusOK, "Deployment status updated", nil)
+}
+
+// scheduleProvision stores the provision payload on the deployment for the worker's scheduler
+// to enqueue at the given time
+func (h *DeploymentHandler) scheduleProvision(r *http.Request, deployment *state.Deployment, payload *queue.ProvisionPayload, at time.Time) error {
+	scheduledJob, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled job: %w", err)
+	}
+
+	deployment.Status = "PENDING"
+	deployment.ScheduledAt = &at
+	deployment.ScheduledJob = string(scheduledJob)
+	deployment.ImageTag = payload.ImageTag
+
+	return h.repo.UpdateDeployment(r.Context(), deployment)
+}
+
+// deployFingerprint hashes the image and settings of a rollout so repeated requests for the
+// same rollout can be recognized. Env keys are sorted so map order does not matter.
+func deployFingerprint(imageTag string, port, replicas int, env map[string]string, deployerType, repoURL, kustomizePath, cpuLimit, memoryLimit string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%d\x00%s\x00%s\x00%s", imageTag, port, replicas, deployerType, repoURL, kustomizePath)
+
+	// Only hashed when set, so rollouts without limits keep their earlier fingerprints
+	if cpuLimit != "" || memoryLimit != "" {
+		fmt.Fprintf(h, "\x00limits=%s/%s", cpuLimit, memoryLimit)
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "\x00%s=%s", k, env[k])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// validateScheduledAt rejects scheduled times that are not in the future
+func validateScheduledAt(scheduledAt *time.Time) error {
+	if scheduledAt != nil && !scheduledAt.After(time.Now()) {
+		return fmt.Errorf("scheduled_at must be in the future")
+	}
+	return nil
+}
+
+// PauseDeployment handles POST /api/v1/deployments/{id}/pause
+func (h *DeploymentHandler) PauseDeployment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.Paused {
+		RespondWithError(w, http.StatusBadRequest, "Deployment is already paused")
+		return
+	}
+
+	// Jobs that are already running are not interrupted, so only waiting deployments can be paused
+	if deployment.Status != "PENDING" && deployment.Status != "QUEUED" {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("Only PENDING or QUEUED deployments can be paused, current status: %s", deployment.Status))
+		return
+	}
+
+	if err := h.repo.SetDeploymentPaused(r.Context(), id, true); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to pause deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to pause deployment")
+		return
+	}
+
+	h.respondWithDeployment(w, r, id)
+}
+
+// ResumeDeployment handles POST /api/v1/deployments/{id}/resume
+func (h *DeploymentHandler) ResumeDeployment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if !deployment.Paused {
+		RespondWithError(w, http.StatusBadRequest, "Deployment is not paused")
+		return
+	}
+
+	if err := h.repo.SetDeploymentPaused(r.Context(), id, false); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to resume deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to resume deployment")
+		return
+	}
+
+	h.respondWithDeployment(w, r, id)
+}
+
+// EnableReconciliation handles POST /api/v1/deployments/{id}/reconciliation/enable
+func (h *DeploymentHandler) EnableReconciliation(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.Cloud == "cloudrun" || deployment.DeployerType == deployer.DeployerTypeKustomize {
+		RespondWithError(w, http.StatusBadRequest, "Reconciliation mode is only supported for Helm deployments on GKE")
+		return
+	}
+
+	if deployment.ReconciliationMode {
+		RespondWithError(w, http.StatusBadRequest, "Reconciliation mode is already enabled")
+		return
+	}
+
+	if err := h.repo.SetDeploymentReconciliationMode(r.Context(), id, true); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to enable reconciliation mode")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to enable reconciliation mode")
+		return
+	}
+
+	h.respondWithDeployment(w, r, id)
+}
+
+// DisableReconciliation handles POST /api/v1/deployments/{id}/reconciliation/disable
+func (h *DeploymentHandler) DisableReconciliation(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if !deployment.ReconciliationMode {
+		RespondWithError(w, http.StatusBadRequest, "Reconciliation mode is not enabled")
+		return
+	}
+
+	if err := h.repo.SetDeploymentReconciliationMode(r.Context(), id, false); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to disable reconciliation mode")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to disable reconciliation mode")
+		return
+	}
+
+	h.respondWithDeployment(w, r, id)
+}
+
+// respondWithDeployment writes the current state of a deployment. It is called right after a
+// change, so it reads from the primary rather than a replica or cache that may not have it yet.
+func (h *DeploymentHandler) respondWithDeployment(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("Failed to reload deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get deployment")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, h.deploymentResponse(r.Context(), deployment))
+}
+
+// DeleteDeployment handles DELETE /api/v1/deployments/{id}
+func (h *DeploymentHandler) DeleteDeployment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	// Get deployment to check for infrastructure
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	// If deployment has infrastructure, trigger destroy job
+	if h.orchClient != nil && deployment.InfrastructureID != nil {
+		destroyPayload := &queue.DestroyPayload{
+			DeploymentID:     id.String(),
+			InfrastructureID: deployment.InfrastructureID.String(),
+			DeleteVolumes:    r.URL.Query().Get("delete_volumes") == "true",
+		}
+
+		if err := h.orchClient.TriggerDestroy(r.Context(), destroyPayload); err != nil {
+			log.Error().Err(err).
+				Str("deployment_id", idStr).
+				Msg("Failed to trigger destroy job")
+			RespondWithError(w, http.StatusInternalServerError,
+				"Failed to initiate destruction process")
+			return
+		}
+
+		// Update status to DESTROYING
+		_ = h.repo.UpdateDeploymentStatus(r.Context(), id, "DESTROYING")
+
+		RespondWithSuccess(w, http.StatusAccepted,
+			"Destruction initiated. Infrastructure will be cleaned up asynchronously.", nil)
 		return
 	}
 
@@ -241,31 +1086,377 @@ func (h *DeploymentHandler) DeleteDeployment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	RespondWithSuccess(w, http.StatusOK, "Deployment deleted", nil)
-}
+	RespondWithSuccess(w, http.StatusOK, "Deployment deleted", nil)
+}
+
+// GetDeploymentsByStatus handles GET /api/v1/deployments/status/{status}
+func (h *DeploymentHandler) GetDeploymentsByStatus(w http.ResponseWriter, r *http.Request) {
+	status := chi.URLParam(r, "status")
+	if status == "" {
+		RespondWithError(w, http.StatusBadRequest, "Status is required")
+		return
+	}
+
+	deployments, err := h.repo.GetDeploymentsByStatus(r.Context(), status)
+	if err != nil {
+		log.Error().Err(err).Str("status", status).Msg("Failed to get deployments by status")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get deployments")
+		return
+	}
+
+	response := DeploymentsToResponse(deployments)
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// StartDeployment handles POST /api/v1/deployments/{id}/deploy
+// This is called after a build completes to trigger the deployment with the built image
+func (h *DeploymentHandler) StartDeployment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req StartDeploymentRequest
+	if !decodeValidated(w, r, deploySchema, &req) {
+		return
+	}
+
+	if req.ImageTag == "" {
+		RespondWithError(w, http.StatusBadRequest, "image_tag is required")
+		return
+	}
+
+	if err := provisioner.ValidateAddons(req.Addons); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch req.DeployerType {
+	case "":
+		req.DeployerType = deployer.DeployerTypeHelm
+	case deployer.DeployerTypeHelm, deployer.DeployerTypeKustomize:
+	default:
+		RespondWithError(w, http.StatusBadRequest, "deployer_type must be helm or kustomize")
+		return
+	}
+
+	if req.DeployerType == deployer.DeployerTypeKustomize && req.RepoURL == "" {
+		RespondWithError(w, http.StatusBadRequest, "repo_url is required for kustomize deployments")
+		return
+	}
+
+	autoscaling, err := parseAutoscaling(req.Autoscaling)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// An empty policy only checks that the limits parse; the deployer enforces the real one
+	if err := (deployer.ResourceLimitPolicy{}).Check(req.CPULimit, req.MemoryLimit, 0); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validateScheduledAt(req.ScheduledAt); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Read from the primary, since whether the rollout is already live depends on its latest status
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if req.DeployerType == deployer.DeployerTypeKustomize && deployment.Cloud == "cloudrun" {
+		RespondWithError(w, http.StatusBadRequest, "kustomize deployments are not supported on cloudrun")
+		return
+	}
+
+	if autoscaling != nil && deployment.Cloud == "cloudrun" {
+		RespondWithError(w, http.StatusBadRequest, "autoscaling is not supported on cloudrun")
+		return
+	}
+
+	if err := validateDedicatedNodePool(&req, deployment.Cloud); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.admitRequest(w, r, req) {
+		return
+	}
+
+	// Scheduled rollouts are started by the worker, so the orchestrator is only needed now
+	if h.orchClient == nil && req.ScheduledAt == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Orchestration service unavailable")
+		return
+	}
+
+	// Set defaults for port and replicas
+	port := req.Port
+	if port == 0 {
+		port = deployment.Port
+		if port == 0 {
+			port = 8080
+		}
+	}
+	replicas := req.Replicas
+	if replicas == 0 {
+		replicas = 2
+	}
+
+	// Stored environment variables are part of the rollout, so changing one forces a redeploy.
+	// Secrets contribute their ciphertext, which changes whenever they are set again.
+	envVars, err := h.repo.ListDeploymentEnvVars(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Failed to list env vars")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start deployment")
+		return
+	}
+	env := make(map[string]string, len(envVars))
+	for _, envVar := range envVars {
+		env[envVar.Key] = envVar.Value
+	}
+
+	// Retried calls for a rollout that is already live are acknowledged without a new job
+	fingerprint := deployFingerprint(req.ImageTag, port, replicas, env, req.DeployerType, req.RepoURL, req.KustomizePath,
+		req.CPULimit, req.MemoryLimit)
+	if !req.Force && fingerprint == deployment.LastDeployFingerprint &&
+		(deployment.Status == "EXPOSED" || deployment.Status == "HEALTHY") {
+		RespondWithJSON(w, http.StatusOK, OrchestrationResponse{
+			DeploymentID:    idStr,
+			Status:          deployment.Status,
+			Message:         "Deployment is already running this image and configuration.",
+			AlreadyDeployed: true,
+		})
+		return
+	}
+
+	rollout := &pendingRollout{
+		Port:          port,
+		DeployerType:  req.DeployerType,
+		RepoURL:       req.RepoURL,
+		KustomizePath: req.KustomizePath,
+		CPULimit:      req.CPULimit,
+		MemoryLimit:   req.MemoryLimit,
+		Fingerprint:   fingerprint,
+		ScheduledAt:   req.ScheduledAt,
+		Provision: &queue.ProvisionPayload{
+			DeploymentID: deployment.ID.String(),
+			AppName:      deployment.AppName,
+			Version:      deployment.Version,
+			Cloud:        deployment.Cloud,
+			Region:       deployment.Region,
+			ImageTag:     req.ImageTag,
+			Replicas:     replicas,
+			Autoscaling:  autoscaling,
+			Addons:       req.Addons,
+
+			SubnetCIDRBlock:   req.SubnetCIDRBlock,
+			DedicatedNodePool: req.DedicatedNodePool,
+			DedicatedWorkload: req.DedicatedWorkload,
+		},
+	}
+
+	// Production deployments hold the rollout until it is approved
+	if deployment.RequiresApproval {
+		h.requestApproval(w, r, deployment, rollout)
+		return
+	}
+
+	response, err := h.startRollout(r, deployment, rollout)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Failed to start deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start deployment")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// startRollout records how the deployment is rendered and enqueues its provision job, or
+// schedules it when the rollout has a start time
+func (h *DeploymentHandler) startRollout(r *http.Request, deployment *state.Deployment, rollout *pendingRollout) (OrchestrationResponse, error) {
+	idStr := deployment.ID.String()
+
+	// Record how the deployment is rendered before the worker picks it up
+	deployment.Port = rollout.Port
+	deployment.DeployerType = rollout.DeployerType
+	deployment.RepoURL = rollout.RepoURL
+	deployment.KustomizePath = rollout.KustomizePath
+	deployment.CPULimit = rollout.CPULimit
+	deployment.MemoryLimit = rollout.MemoryLimit
+	deployment.LastDeployFingerprint = rollout.Fingerprint
+	if err := h.repo.UpdateDeployment(r.Context(), deployment); err != nil {
+		return OrchestrationResponse{}, err
+	}
+
+	if rollout.ScheduledAt != nil {
+		if err := h.scheduleProvision(r, deployment, rollout.Provision, *rollout.ScheduledAt); err != nil {
+			return OrchestrationResponse{}, fmt.Errorf("failed to schedule deployment: %w", err)
+		}
+
+		return OrchestrationResponse{
+			DeploymentID: idStr,
+			Status:       deployment.Status,
+			Message:      fmt.Sprintf("Deployment scheduled for %s.", rollout.ScheduledAt.UTC().Format(time.RFC3339)),
+		}, nil
+	}
+
+	// Trigger provision job with image tag
+	if err := h.orchClient.TriggerProvision(r.Context(), rollout.Provision); err != nil {
+		return OrchestrationResponse{}, fmt.Errorf("failed to trigger provision job: %w", err)
+	}
+
+	// Update deployment status
+	_ = h.repo.UpdateDeploymentStatus(r.Context(), deployment.ID, "QUEUED")
+
+	return OrchestrationResponse{
+		DeploymentID: idStr,
+		Status:       "QUEUED",
+		Message:      "Deployment started. Infrastructure will be provisioned and application deployed.",
+	}, nil
+}
+
+// TriggerRollback handles POST /api/v1/deployments/{id}/rollback
+func (h *DeploymentHandler) TriggerRollback(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req TriggerRollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Revision < 0 {
+		RespondWithError(w, http.StatusBadRequest, "revision must not be negative")
+		return
+	}
+
+	if req.TargetVersion == "" && req.Revision == 0 {
+		RespondWithError(w, http.StatusBadRequest, "target_version or revision is required")
+		return
+	}
+
+	// Get deployment
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	// Check if deployment has infrastructure
+	if deployment.InfrastructureID == nil {
+		RespondWithError(w, http.StatusBadRequest,
+			"Deployment has no infrastructure to rollback")
+		return
+	}
+
+	// Check if orchestrator is available
+	if h.orchClient == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Orchestration service unavailable")
+		return
+	}
+
+	// Trigger rollback job
+	rollbackPayload := &queue.RollbackPayload{
+		DeploymentID:  idStr,
+		TargetVersion: req.TargetVersion,
+		TargetTag:     req.TargetTag,
+		Revision:      req.Revision,
+	}
+
+	if err := h.orchClient.TriggerRollback(r.Context(), rollbackPayload); err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", idStr).
+			Msg("Failed to trigger rollback job")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start rollback")
+		return
+	}
+
+	// Update deployment status
+	_ = h.repo.UpdateDeploymentStatus(r.Context(), id, "ROLLING_BACK")
+
+	message := fmt.Sprintf("Rollback to version %s initiated", req.TargetVersion)
+	if req.TargetVersion == "" {
+		message = fmt.Sprintf("Rollback to revision %d initiated", req.Revision)
+	}
+
+	response := OrchestrationResponse{
+		DeploymentID: idStr,
+		Status:       "ROLLING_BACK",
+		Message:      message,
+	}
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// ReconcileDeployment handles POST /api/v1/deployments/{id}/reconcile
+func (h *DeploymentHandler) ReconcileDeployment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	// Get deployment
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
 
-// GetDeploymentsByStatus handles GET /api/v1/deployments/status/{status}
-func (h *DeploymentHandler) GetDeploymentsByStatus(w http.ResponseWriter, r *http.Request) {
-	status := chi.URLParam(r, "status")
-	if status == "" {
-		RespondWithError(w, http.StatusBadRequest, "Status is required")
+	// Check if deployment has infrastructure
+	if deployment.InfrastructureID == nil {
+		RespondWithError(w, http.StatusBadRequest,
+			"Deployment has no infrastructure to reconcile")
 		return
 	}
 
-	deployments, err := h.repo.GetDeploymentsByStatus(r.Context(), status)
-	if err != nil {
-		log.Error().Err(err).Str("status", status).Msg("Failed to get deployments by status")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to get deployments")
+	// Check if orchestrator is available
+	if h.orchClient == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Orchestration service unavailable")
 		return
 	}
 
-	response := DeploymentsToResponse(deployments)
-	RespondWithJSON(w, http.StatusOK, response)
+	reconcilePayload := &queue.ReconcilePayload{
+		DeploymentID:     idStr,
+		InfrastructureID: deployment.InfrastructureID.String(),
+	}
+
+	if err := h.orchClient.TriggerReconcile(r.Context(), reconcilePayload); err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", idStr).
+			Msg("Failed to trigger reconcile job")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start reconciliation")
+		return
+	}
+
+	response := OrchestrationResponse{
+		DeploymentID: idStr,
+		Status:       deployment.Status,
+		Message:      "Reconciliation started. Drift results will be recorded on the infrastructure.",
+	}
+	RespondWithJSON(w, http.StatusAccepted, response)
 }
 
-// StartDeployment handles POST /api/v1/deployments/{id}/deploy
-// This is called after a build completes to trigger the deployment with the built image
-func (h *DeploymentHandler) StartDeployment(w http.ResponseWriter, r *http.Request) {
+// RestartDeployment handles POST /api/v1/deployments/{id}/restart
+func (h *DeploymentHandler) RestartDeployment(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -273,22 +1464,26 @@ func (h *DeploymentHandler) StartDeployment(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	var req StartDeploymentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
 		return
 	}
 
-	if req.ImageTag == "" {
-		RespondWithError(w, http.StatusBadRequest, "image_tag is required")
+	// Only running releases have pods to restart
+	switch deployment.Status {
+	case "EXPOSED", "HEALTHY", "UNHEALTHY":
+	default:
+		RespondWithError(w, http.StatusConflict,
+			fmt.Sprintf("Deployment is %s, only running deployments can be restarted", deployment.Status))
 		return
 	}
 
-	// Get deployment
-	deployment, err := h.repo.GetDeployment(r.Context(), id)
-	if err != nil {
-		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
-		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+	infra, err := h.repo.GetInfrastructure(r.Context(), id)
+	if err != nil || infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusBadRequest,
+			"Deployment has no Kubernetes release to restart")
 		return
 	}
 
@@ -299,48 +1494,31 @@ func (h *DeploymentHandler) StartDeployment(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Set defaults for port and replicas
-	port := req.Port
-	if port == 0 {
-		port = deployment.Port
-		if port == 0 {
-			port = 8080
-		}
-	}
-
-	// Trigger provision job with image tag
-	provisionPayload := &queue.ProvisionPayload{
-		DeploymentID: deployment.ID.String(),
-		AppName:      deployment.AppName,
-		Version:      deployment.Version,
-		Cloud:        deployment.Cloud,
-		Region:       deployment.Region,
-		ImageTag:     req.ImageTag,
+	restartPayload := &queue.RestartPayload{
+		DeploymentID:     idStr,
+		InfrastructureID: infra.ID.String(),
 	}
 
-	if err := h.orchClient.TriggerProvision(r.Context(), provisionPayload); err != nil {
+	if err := h.orchClient.TriggerRestart(r.Context(), restartPayload); err != nil {
 		log.Error().Err(err).
 			Str("deployment_id", idStr).
-			Msg("Failed to trigger provision job")
-		RespondWithError(w, http.StatusInternalServerError,
-			"Failed to start deployment")
+			Msg("Failed to trigger restart job")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start restart")
 		return
 	}
 
-	// Update deployment status and port
-	deployment.Port = port
-	_ = h.repo.UpdateDeploymentStatus(r.Context(), id, "QUEUED")
+	_ = h.repo.UpdateDeploymentStatus(r.Context(), id, "RESTARTING")
 
 	response := OrchestrationResponse{
 		DeploymentID: idStr,
-		Status:       "QUEUED",
-		Message:      "Deployment started. Infrastructure will be provisioned and application deployed.",
+		Status:       "RESTARTING",
+		Message:      "Rolling restart initiated. Pods are replaced one by one without downtime.",
 	}
 	RespondWithJSON(w, http.StatusAccepted, response)
 }
 
-// TriggerRollback handles POST /api/v1/deployments/{id}/rollback
-func (h *DeploymentHandler) TriggerRollback(w http.ResponseWriter, r *http.Request) {
+// ApplyVPARecommendation handles POST /api/v1/deployments/{id}/apply-vpa-recommendation
+func (h *DeploymentHandler) ApplyVPARecommendation(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -348,29 +1526,30 @@ func (h *DeploymentHandler) TriggerRollback(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	var req TriggerRollbackRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
 		return
 	}
 
-	if req.TargetVersion == "" {
-		RespondWithError(w, http.StatusBadRequest, "target_version is required")
+	recommendation := deployment.VPALastRecommendation
+	if recommendation == nil {
+		RespondWithError(w, http.StatusNotFound, "No VPA recommendation recorded for this deployment")
 		return
 	}
 
-	// Get deployment
-	deployment, err := h.repo.GetDeployment(r.Context(), id)
-	if err != nil {
-		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
-		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+	switch deployment.Status {
+	case "EXPOSED", "HEALTHY", "UNHEALTHY":
+	default:
+		RespondWithError(w, http.StatusConflict,
+			fmt.Sprintf("Deployment is %s, only running deployments can be resized", deployment.Status))
 		return
 	}
 
-	// Check if deployment has infrastructure
-	if deployment.InfrastructureID == nil {
-		RespondWithError(w, http.StatusBadRequest,
-			"Deployment has no infrastructure to rollback")
+	infra, err := h.repo.GetInfrastructure(r.Context(), id)
+	if err != nil || infra.Status != "READY" {
+		RespondWithError(w, http.StatusBadRequest, "Deployment has no ready infrastructure")
 		return
 	}
 
@@ -381,28 +1560,53 @@ func (h *DeploymentHandler) TriggerRollback(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Trigger rollback job
-	rollbackPayload := &queue.RollbackPayload{
-		DeploymentID:  idStr,
-		TargetVersion: req.TargetVersion,
-		TargetTag:     req.TargetTag,
+	// Limits the recommender did not bound are left as they are
+	deployment.CPURequest = recommendation.CPURequest
+	deployment.MemoryRequest = recommendation.MemoryRequest
+	if recommendation.CPULimit != "" {
+		deployment.CPULimit = recommendation.CPULimit
+	}
+	if recommendation.MemoryLimit != "" {
+		deployment.MemoryLimit = recommendation.MemoryLimit
+	}
+	if err := h.repo.UpdateDeployment(r.Context(), deployment); err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Failed to update deployment resources")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to apply VPA recommendation")
+		return
 	}
 
-	if err := h.orchClient.TriggerRollback(r.Context(), rollbackPayload); err != nil {
+	// Keep the replica count the release was last deployed with
+	replicas := 0
+	var values struct {
+		ReplicaCount int `json:"replicaCount"`
+	}
+	if err := json.Unmarshal([]byte(infra.HelmValues), &values); err == nil {
+		replicas = values.ReplicaCount
+	}
+
+	deployPayload := &queue.DeployPayload{
+		DeploymentID:     idStr,
+		InfrastructureID: infra.ID.String(),
+		ImageTag:         deployment.ImageTag,
+		Port:             deployment.Port,
+		Replicas:         replicas,
+	}
+
+	if err := h.orchClient.TriggerDeploy(r.Context(), deployPayload); err != nil {
 		log.Error().Err(err).
 			Str("deployment_id", idStr).
-			Msg("Failed to trigger rollback job")
-		RespondWithError(w, http.StatusInternalServerError, "Failed to start rollback")
+			Msg("Failed to trigger deploy job")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to apply VPA recommendation")
 		return
 	}
 
-	// Update deployment status
-	_ = h.repo.UpdateDeploymentStatus(r.Context(), id, "ROLLING_BACK")
+	_ = h.repo.UpdateDeploymentStatus(r.Context(), id, "DEPLOYING")
 
 	response := OrchestrationResponse{
 		DeploymentID: idStr,
-		Status:       "ROLLING_BACK",
-		Message:      fmt.Sprintf("Rollback to version %s initiated", req.TargetVersion),
+		Status:       "DEPLOYING",
+		Message: fmt.Sprintf("Redeploying with CPU request %s and memory request %s",
+			recommendation.CPURequest, recommendation.MemoryRequest),
 	}
 	RespondWithJSON(w, http.StatusAccepted, response)
 }
@@ -423,10 +1627,337 @@ func (h *DeploymentHandler) GetQueueStats(w http.ResponseWriter, r *http.Request
 	}
 
 	response := QueueStatsResponse{
-		Provision: stats["provision"],
-		Deploy:    stats["deploy"],
-		Destroy:   stats["destroy"],
-		Rollback:  stats["rollback"],
+		Provision:   stats["provision"],
+		Deploy:      stats["deploy"],
+		Destroy:     stats["destroy"],
+		Rollback:    stats["rollback"],
+		Reconcile:   stats["reconcile"],
+		UpdateInfra: stats["update_infra"],
+	}
+
+	if h.workers != nil {
+		workers, err := h.workers.ListWorkers(r.Context())
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to list workers")
+		}
+		response.ActiveWorkers = len(workers)
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// ListWorkers handles GET /api/v1/orchestrator/workers
+// Workers are listed while their last heartbeat has not expired.
+func (h *DeploymentHandler) ListWorkers(w http.ResponseWriter, r *http.Request) {
+	if h.workers == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Worker registry unavailable - Redis not connected")
+		return
 	}
+
+	workers, err := h.workers.ListWorkers(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list workers")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list workers")
+		return
+	}
+
+	response := WorkersResponse{
+		Workers: make([]WorkerResponse, 0, len(workers)),
+	}
+	for _, worker := range workers {
+		response.Workers = append(response.Workers, WorkerStatusToResponse(worker))
+	}
+
 	RespondWithJSON(w, http.StatusOK, response)
 }
+
+// validateDeploymentType checks the workload type and its cronjob or storage settings, defaulting to service
+func validateDeploymentType(req *CreateDeploymentRequest) error {
+	switch req.DeploymentType {
+	case "":
+		req.DeploymentType = deployer.DeploymentTypeService
+	case deployer.DeploymentTypeService, deployer.DeploymentTypeJob:
+	case deployer.DeploymentTypeStatefulSet:
+		if req.Storage != nil && req.Storage.Size == "" {
+			return fmt.Errorf("storage.size is required when storage is set")
+		}
+	case deployer.DeploymentTypeCronJob:
+		if req.CronJob == nil || req.CronJob.Schedule == "" {
+			return fmt.Errorf("cronjob.schedule is required for cronjob deployments")
+		}
+		switch req.CronJob.Concurrency {
+		case "", "Allow", "Forbid", "Replace":
+		default:
+			return fmt.Errorf("cronjob.concurrency must be Allow, Forbid or Replace")
+		}
+		if req.CronJob.StartingDeadlineSeconds < 0 {
+			return fmt.Errorf("cronjob.starting_deadline_seconds must not be negative")
+		}
+	default:
+		return fmt.Errorf("type must be service, statefulset, cronjob or job")
+	}
+
+	if req.Storage != nil && req.DeploymentType != deployer.DeploymentTypeStatefulSet {
+		return fmt.Errorf("storage is only supported for statefulset deployments")
+	}
+
+	if req.DeploymentType != deployer.DeploymentTypeService && req.Cloud == "cloudrun" {
+		return fmt.Errorf("%s deployments are not supported on cloudrun", req.DeploymentType)
+	}
+
+	return nil
+}
+
+// validateHooks checks that every hook names an image and has a sane timeout
+func validateHooks(hooks *deployer.HooksConfig, cloud string) error {
+	if hooks == nil {
+		return nil
+	}
+
+	if cloud == "cloudrun" && (len(hooks.PreDeploy) > 0 || len(hooks.PostDeploy) > 0) {
+		return fmt.Errorf("hooks are not supported on cloudrun")
+	}
+
+	phases := []struct {
+		name  string
+		specs []deployer.HookSpec
+	}{
+		{"pre_deploy", hooks.PreDeploy},
+		{"post_deploy", hooks.PostDeploy},
+	}
+	for _, phase := range phases {
+		for i, spec := range phase.specs {
+			if spec.Image == "" {
+				return fmt.Errorf("hooks.%s[%d].image is required", phase.name, i)
+			}
+			if spec.TimeoutSeconds < 0 {
+				return fmt.Errorf("hooks.%s[%d].timeout_seconds must not be negative", phase.name, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSmokeTests checks that every smoke test targets a path with a supported method
+func validateSmokeTests(tests []deployer.SmokeTest) error {
+	for i, test := range tests {
+		if !strings.HasPrefix(test.Path, "/") {
+			return fmt.Errorf("smoke_tests[%d].path must start with /", i)
+		}
+
+		switch test.Method {
+		case "", http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		default:
+			return fmt.Errorf("smoke_tests[%d].method %s is not supported", i, test.Method)
+		}
+
+		if test.ExpectedStatus != 0 && (test.ExpectedStatus < 100 || test.ExpectedStatus > 599) {
+			return fmt.Errorf("smoke_tests[%d].expected_status must be a valid HTTP status", i)
+		}
+		if test.TimeoutSeconds < 0 {
+			return fmt.Errorf("smoke_tests[%d].timeout_seconds must not be negative", i)
+		}
+	}
+
+	return nil
+}
+
+// validateTag checks that a tag can be used as both a GCP resource label and a Kubernetes label
+func validateTag(key, value string) error {
+	if !tagKeyPattern.MatchString(key) {
+		return fmt.Errorf("tag key %q must be lowercase letters, digits, - or _, start with a letter and be at most 63 characters", key)
+	}
+	if reservedTagKeys[key] {
+		return fmt.Errorf("tag key %q is reserved", key)
+	}
+	if !tagValuePattern.MatchString(value) {
+		return fmt.Errorf("tag %s value %q must be lowercase letters, digits, - or _ and be at most 63 characters", key, value)
+	}
+	return nil
+}
+
+// validateWorkloadIdentity checks that workload identity targets a GKE cluster and a GCP service account
+func validateWorkloadIdentity(identity *WorkloadIdentityRequest, cloud string) error {
+	if identity == nil || !identity.Enabled {
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("workload_identity is not supported on cloudrun")
+	}
+
+	email := identity.GCPServiceAccountEmail
+	if email != "" && (!strings.Contains(email, "@") || !strings.HasSuffix(email, ".gserviceaccount.com")) {
+		return fmt.Errorf("workload_identity.gcp_service_account_email must be a GCP service account email")
+	}
+
+	return nil
+}
+
+// validateWAF checks that Cloud Armor protects an exposed GKE app with a valid policy name
+func validateWAF(waf *WAFRequest, cloud, deploymentType string) error {
+	if waf == nil || !waf.EnableCloudArmor {
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("waf is not supported on cloudrun")
+	}
+
+	if deploymentType != "" && deploymentType != deployer.DeploymentTypeService && deploymentType != deployer.DeploymentTypeStatefulSet {
+		return fmt.Errorf("waf requires a service or statefulset deployment, %s deployments have no load balancer", deploymentType)
+	}
+
+	if waf.SecurityPolicy != "" && waf.SecurityPolicy != "default" && !securityPolicyPattern.MatchString(waf.SecurityPolicy) {
+		return fmt.Errorf("waf.security_policy must be a Cloud Armor policy name or \"default\"")
+	}
+
+	if waf.RateLimit != nil {
+		if waf.RateLimit.RequestsPerMinutePerIP < 1 {
+			return fmt.Errorf("waf.rate_limit.requests_per_minute_per_ip must be at least 1")
+		}
+		if err := validateRateLimitPaths(waf.RateLimit.EnforceOnURIPaths); err != nil {
+			return fmt.Errorf("waf.rate_limit.%w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateRateLimitPaths checks the path prefixes a rate limit is enforced on. Cloud Armor
+// expressions allow at most five subexpressions, and each path is one.
+func validateRateLimitPaths(paths []string) error {
+	if len(paths) > maxRateLimitPaths {
+		return fmt.Errorf("enforce_on_uri_paths allows at most %d paths", maxRateLimitPaths)
+	}
+
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, "'\\\"") {
+			return fmt.Errorf("enforce_on_uri_paths must be paths starting with / without quotes, got %q", path)
+		}
+	}
+
+	return nil
+}
+
+// validateNodeAffinity checks the node placement of a GKE app's pods
+func validateNodeAffinity(affinity *deployer.NodeAffinity, cloud string) error {
+	if affinity == nil {
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("node_affinity is not supported on cloudrun")
+	}
+
+	if err := affinity.Validate(); err != nil {
+		return fmt.Errorf("node_affinity: %w", err)
+	}
+
+	return nil
+}
+
+// validateSidecars checks the sidecar containers of a GKE app's pods
+func validateSidecars(sidecars []deployer.SidecarConfig, cloud string) error {
+	if len(sidecars) == 0 {
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("sidecars are not supported on cloudrun")
+	}
+
+	if err := deployer.ValidateSidecars(sidecars); err != nil {
+		return fmt.Errorf("sidecars: %w", err)
+	}
+
+	return nil
+}
+
+// validateInitContainers checks the init containers of a GKE app's pods
+func validateInitContainers(initContainers []deployer.InitContainerConfig, cloud string) error {
+	if len(initContainers) == 0 {
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("init_containers are not supported on cloudrun")
+	}
+
+	if err := deployer.ValidateInitContainers(initContainers); err != nil {
+		return fmt.Errorf("init_containers: %w", err)
+	}
+
+	return nil
+}
+
+// validateDedicatedNodePool checks that a dedicated node pool is added to a GKE cluster under a
+// valid taint value
+func validateDedicatedNodePool(req *StartDeploymentRequest, cloud string) error {
+	if !req.DedicatedNodePool {
+		if req.DedicatedWorkload != "" {
+			return fmt.Errorf("dedicated_workload requires dedicated_node_pool")
+		}
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("dedicated_node_pool is not supported on cloudrun")
+	}
+
+	if errs := validation.IsValidLabelValue(req.DedicatedWorkload); len(errs) > 0 {
+		return fmt.Errorf("invalid dedicated_workload: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// validatePDB checks that a disruption budget covers long-running GKE pods and sets at most one bound
+func validatePDB(pdb *PDBRequest, cloud, deploymentType string) error {
+	if pdb == nil || !pdb.Enabled {
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("pdb is not supported on cloudrun")
+	}
+
+	if deploymentType != "" && deploymentType != deployer.DeploymentTypeService && deploymentType != deployer.DeploymentTypeStatefulSet {
+		return fmt.Errorf("pdb requires a service or statefulset deployment, %s pods run to completion", deploymentType)
+	}
+
+	config := deployer.PDBConfig{MinAvailable: pdb.MinAvailable, MaxUnavailable: pdb.MaxUnavailable}
+	return config.Validate()
+}
+
+// parseAutoscaling validates node pool autoscaling bounds, returning nil when autoscaling is not requested
+func parseAutoscaling(req *AutoscalingRequest) (*provisioner.AutoscalingConfig, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	if req.MinNodes < 0 {
+		return nil, fmt.Errorf("autoscaling.min_nodes must not be negative")
+	}
+
+	if req.MaxNodes < 1 || req.MaxNodes < req.MinNodes {
+		return nil, fmt.Errorf("autoscaling.max_nodes must be at least 1 and at least min_nodes")
+	}
+
+	config := &provisioner.AutoscalingConfig{
+		MinNodes: req.MinNodes,
+		MaxNodes: req.MaxNodes,
+	}
+
+	if req.ScaleDownDelay != "" {
+		delay, err := time.ParseDuration(req.ScaleDownDelay)
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("autoscaling.scale_down_delay must be a duration such as 10m")
+		}
+		config.ScaleDownDelay = delay
+	}
+
+	return config, nil
+}
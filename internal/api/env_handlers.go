@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// maskedSecretValue replaces the value of secret environment variables in responses
+const maskedSecretValue = "********"
+
+// envVarKeyPattern matches valid environment variable names
+var envVarKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvHandler handles deployment environment variable HTTP requests
+type EnvHandler struct {
+	repo   *state.Repository
+	cipher *secrets.Cipher
+}
+
+// NewEnvHandler creates a new environment variable handler
+func NewEnvHandler(repo *state.Repository, cipher *secrets.Cipher) *EnvHandler {
+	return &EnvHandler{
+		repo:   repo,
+		cipher: cipher,
+	}
+}
+
+// ListEnvVars handles GET /api/v1/deployments/{id}/env
+func (h *EnvHandler) ListEnvVars(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	envVars, err := h.repo.ListDeploymentEnvVars(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list env vars")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list environment variables")
+		return
+	}
+
+	response := EnvVarsResponse{
+		DeploymentID: id,
+		EnvVars:      make([]EnvVarResponse, 0, len(envVars)),
+	}
+	for i := range envVars {
+		response.EnvVars = append(response.EnvVars, EnvVarToResponse(&envVars[i]))
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// SetEnvVar handles POST /api/v1/deployments/{id}/env
+func (h *EnvHandler) SetEnvVar(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req SetEnvVarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !envVarKeyPattern.MatchString(req.Key) {
+		RespondWithError(w, http.StatusBadRequest,
+			"key must start with a letter or underscore and contain only letters, digits and underscores")
+		return
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	envVar := &state.DeploymentEnvVar{
+		DeploymentID: id,
+		Key:          req.Key,
+		Value:        req.Value,
+		IsSecret:     req.IsSecret,
+	}
+
+	if req.IsSecret {
+		if h.cipher == nil {
+			RespondWithError(w, http.StatusServiceUnavailable,
+				"Secret environment variables unavailable - encryption key not configured")
+			return
+		}
+
+		envVar.Value, err = h.cipher.Encrypt(req.Value)
+		if err != nil {
+			log.Error().Err(err).Str("id", idStr).Msg("Failed to encrypt env var")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to encrypt environment variable")
+			return
+		}
+	}
+
+	if err := h.repo.SetDeploymentEnvVar(r.Context(), envVar); err != nil {
+		log.Error().Err(err).Str("id", idStr).Str("key", req.Key).Msg("Failed to set env var")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to set environment variable")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("key", req.Key).
+		Bool("is_secret", req.IsSecret).
+		Msg("Environment variable set")
+
+	RespondWithJSON(w, http.StatusOK, EnvVarToResponse(envVar))
+}
+
+// DeleteEnvVar handles DELETE /api/v1/deployments/{id}/env/{key}
+func (h *EnvHandler) DeleteEnvVar(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+
+	if err := h.repo.DeleteDeploymentEnvVar(r.Context(), id, key); err != nil {
+		log.Error().Err(err).Str("id", idStr).Str("key", key).Msg("Failed to delete env var")
+		RespondWithError(w, http.StatusNotFound, "Environment variable not found")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("key", key).
+		Msg("Environment variable deleted")
+
+	RespondWithSuccess(w, http.StatusOK, "Environment variable deleted", nil)
+}
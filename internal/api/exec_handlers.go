@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/websocket"
+)
+
+// maxExecSession bounds how long an exec WebSocket stays open
+const maxExecSession = 5 * time.Minute
+
+// ExecPod handles GET /api/v1/deployments/{id}/pods/{podName}/exec. The connection is
+// upgraded to a WebSocket whose first text message is a PodExecRequest; later messages are
+// the command's stdin and its stdout and stderr are sent back as binary messages.
+func (h *PodHandler) ExecPod(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if !h.execEnabled {
+		RespondWithError(w, http.StatusForbidden, "Pod exec is disabled - set server.exec_enabled to allow it")
+		return
+	}
+
+	if !isAdminRequest(r, h.adminToken) {
+		RespondWithError(w, http.StatusForbidden, "Only an admin can exec into a pod")
+		return
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" && !slices.Contains(h.execOrigins, origin) {
+		RespondWithError(w, http.StatusForbidden, "Origin not allowed - add it to server.exec_allowed_origins")
+		return
+	}
+
+	if err := deployer.VerifyKubectlInstalled(); err != nil {
+		log.Error().Err(err).Msg("Pod exec unavailable")
+		RespondWithError(w, http.StatusServiceUnavailable, "Pod exec unavailable - kubectl not installed")
+		return
+	}
+
+	podName := chi.URLParam(r, "podName")
+
+	infra, kubeClient, ok := h.kubeClient(w, r, deploymentID)
+	if !ok {
+		return
+	}
+
+	found, err := releaseHasPod(r.Context(), kubeClient, infra, podName)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to list pods")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start exec session")
+		return
+	}
+	if !found {
+		RespondWithError(w, http.StatusNotFound, "Pod not found")
+		return
+	}
+
+	server := websocket.Server{
+		// The Origin header was checked above; clients other than browsers do not send one
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			h.runExecSession(ws, r, deploymentID, infra, podName)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// runExecSession reads the exec request from the WebSocket, records it in the audit log and
+// streams the command until it exits, the client disconnects or the session times out.
+func (h *PodHandler) runExecSession(ws *websocket.Conn, r *http.Request, deploymentID uuid.UUID, infra *state.Infrastructure, podName string) {
+	defer ws.Close()
+
+	// Replaces the server's read and write timeouts, which would end the session within seconds
+	deadline := time.Now().Add(maxExecSession)
+	if err := ws.SetDeadline(deadline); err != nil {
+		log.Warn().Err(err).Msg("Failed to set exec session deadline")
+	}
+
+	logger := log.With().
+		Str("deployment_id", deploymentID.String()).
+		Str("pod", podName).
+		Logger()
+
+	var req PodExecRequest
+	if err := websocket.JSON.Receive(ws, &req); err != nil {
+		sendExecMessage(ws, fmt.Sprintf("invalid exec request: %v", err))
+		return
+	}
+
+	if len(req.Command) == 0 {
+		sendExecMessage(ws, "command is required")
+		return
+	}
+
+	actor := requestActor(r)
+	details, _ := json.Marshal(map[string]interface{}{
+		"pod":       podName,
+		"container": req.Container,
+		"command":   req.Command,
+	})
+	if err := h.repo.CreateAuditLog(r.Context(), &state.AuditLog{
+		Action:       "pod.exec",
+		DeploymentID: deploymentID,
+		Actor:        actor,
+		Details:      string(details),
+	}); err != nil {
+		// Sessions that cannot be audited are refused
+		logger.Error().Err(err).Msg("Failed to record exec session")
+		sendExecMessage(ws, "failed to record exec session")
+		return
+	}
+
+	logger.Info().
+		Str("actor", actor).
+		Str("container", req.Container).
+		Strs("command", req.Command).
+		Msg("Exec session started")
+
+	start := time.Now()
+	ws.PayloadType = websocket.BinaryFrame
+
+	ctx, cancel := context.WithDeadline(r.Context(), deadline)
+	defer cancel()
+
+	err := deployer.ExecInPod(ctx, infra, podName, req.Container, req.Command, ws, ws, ws)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Exec session ended with error")
+		sendExecMessage(ws, err.Error())
+	}
+
+	logger.Info().
+		Str("actor", actor).
+		Dur("duration", time.Since(start)).
+		Msg("Exec session ended")
+}
+
+// sendExecMessage reports a session error to the client as a text message
+func sendExecMessage(ws *websocket.Conn, message string) {
+	if err := websocket.Message.Send(ws, message); err != nil {
+		log.Debug().Err(err).Msg("Failed to send exec message")
+	}
+}
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/alvesdmateus/app-deployer/internal/orchestrator"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Aggregate statuses of a federated deployment
+const (
+	FederatedStatusExposed    = "EXPOSED"
+	FederatedStatusInProgress = "IN_PROGRESS"
+	FederatedStatusDegraded   = "DEGRADED"
+	FederatedStatusFailed     = "FAILED"
+)
+
+// FederationHandler handles federated deployment HTTP requests
+type FederationHandler struct {
+	repo       *state.Repository
+	orchClient *orchestrator.Client
+}
+
+// NewFederationHandler creates a new federation handler
+func NewFederationHandler(repo *state.Repository, orchClient *orchestrator.Client) *FederationHandler {
+	return &FederationHandler{
+		repo:       repo,
+		orchClient: orchClient,
+	}
+}
+
+// CreateFederatedDeployment handles POST /api/v1/federated-deployments
+func (h *FederationHandler) CreateFederatedDeployment(w http.ResponseWriter, r *http.Request) {
+	var req CreateFederatedDeploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Name == "" || req.AppName == "" || req.Version == "" {
+		RespondWithError(w, http.StatusBadRequest, "Name, app_name, and version are required")
+		return
+	}
+
+	if err := validateFederationTargets(req.Targets); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	port := req.Port
+	if port == 0 {
+		port = 8080
+	}
+
+	// Create one member deployment per target
+	members := make([]state.Deployment, 0, len(req.Targets))
+	memberIDs := make([]uuid.UUID, 0, len(req.Targets))
+	for _, target := range req.Targets {
+		deployment := state.Deployment{
+			Name:    fmt.Sprintf("%s-%s", req.Name, target.Region),
+			AppName: req.AppName,
+			Version: req.Version,
+			Status:  "PENDING",
+			Cloud:   target.Cloud,
+			Region:  target.Region,
+			Port:    port,
+		}
+
+		if err := h.repo.CreateDeployment(r.Context(), &deployment); err != nil {
+			log.Error().Err(err).Str("region", target.Region).Msg("Failed to create member deployment")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to create federated deployment")
+			return
+		}
+
+		members = append(members, deployment)
+		memberIDs = append(memberIDs, deployment.ID)
+	}
+
+	federated := &state.FederatedDeployment{
+		Name:          req.Name,
+		AppName:       req.AppName,
+		Version:       req.Version,
+		DeploymentIDs: memberIDs,
+	}
+
+	if err := h.repo.CreateFederatedDeployment(r.Context(), federated); err != nil {
+		log.Error().Err(err).Msg("Failed to create federated deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to create federated deployment")
+		return
+	}
+
+	// Trigger provision jobs if orchestrator is available and image_tag is provided
+	if h.orchClient != nil && req.ImageTag != "" {
+		for i := range members {
+			deployment := &members[i]
+			provisionPayload := &queue.ProvisionPayload{
+				DeploymentID: deployment.ID.String(),
+				AppName:      deployment.AppName,
+				Version:      deployment.Version,
+				Cloud:        deployment.Cloud,
+				Region:       deployment.Region,
+				ImageTag:     req.ImageTag,
+				Replicas:     req.Targets[i].Replicas,
+			}
+
+			// A member that fails to start is marked FAILED; the others still roll out
+			if err := h.orchClient.TriggerProvision(r.Context(), provisionPayload); err != nil {
+				log.Error().Err(err).
+					Str("federated_id", federated.ID.String()).
+					Str("deployment_id", deployment.ID.String()).
+					Msg("Failed to trigger provision job")
+				_ = h.repo.UpdateDeploymentStatus(r.Context(), deployment.ID, "FAILED")
+				deployment.Status = "FAILED"
+				deployment.Error = "Failed to start provisioning: " + err.Error()
+				continue
+			}
+
+			_ = h.repo.UpdateDeploymentStatus(r.Context(), deployment.ID, "QUEUED")
+			deployment.Status = "QUEUED"
+		}
+	}
+
+	RespondWithJSON(w, http.StatusCreated, FederatedDeploymentToResponse(federated, members))
+}
+
+// GetFederatedDeploymentStatus handles GET /api/v1/federated-deployments/{id}/status
+func (h *FederationHandler) GetFederatedDeploymentStatus(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid federated deployment ID")
+		return
+	}
+
+	federated, err := h.repo.GetFederatedDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get federated deployment")
+		RespondWithError(w, http.StatusNotFound, "Federated deployment not found")
+		return
+	}
+
+	members, err := h.repo.GetDeploymentsByIDs(r.Context(), federated.DeploymentIDs)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get member deployments")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get member deployments")
+		return
+	}
+
+	response := FederatedDeploymentStatusResponse{
+		ID:      federated.ID,
+		Name:    federated.Name,
+		Status:  aggregateFederatedStatus(members),
+		Counts:  make(map[string]int),
+		Members: FederatedMembersToResponse(members),
+	}
+	for _, m := range members {
+		response.Counts[m.Status]++
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// TriggerFederatedRollback handles POST /api/v1/federated-deployments/{id}/rollback
+func (h *FederationHandler) TriggerFederatedRollback(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid federated deployment ID")
+		return
+	}
+
+	var req TriggerRollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.TargetVersion == "" {
+		RespondWithError(w, http.StatusBadRequest, "target_version is required")
+		return
+	}
+
+	federated, err := h.repo.GetFederatedDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Federated deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Federated deployment not found")
+		return
+	}
+
+	if h.orchClient == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Orchestration service unavailable")
+		return
+	}
+
+	members, err := h.repo.GetDeploymentsByIDs(r.Context(), federated.DeploymentIDs)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get member deployments")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get member deployments")
+		return
+	}
+
+	response := FederatedRollbackResponse{
+		ID:      federated.ID,
+		Members: make([]OrchestrationResponse, 0, len(members)),
+	}
+
+	for _, m := range members {
+		result := OrchestrationResponse{
+			DeploymentID: m.ID.String(),
+			Status:       m.Status,
+		}
+
+		if m.InfrastructureID == nil {
+			result.Message = "Deployment has no infrastructure to rollback"
+			response.Members = append(response.Members, result)
+			continue
+		}
+
+		rollbackPayload := &queue.RollbackPayload{
+			DeploymentID:  m.ID.String(),
+			TargetVersion: req.TargetVersion,
+			TargetTag:     req.TargetTag,
+		}
+
+		if err := h.orchClient.TriggerRollback(r.Context(), rollbackPayload); err != nil {
+			log.Error().Err(err).
+				Str("federated_id", idStr).
+				Str("deployment_id", m.ID.String()).
+				Msg("Failed to trigger rollback job")
+			result.Message = "Failed to start rollback"
+			response.Members = append(response.Members, result)
+			continue
+		}
+
+		_ = h.repo.UpdateDeploymentStatus(r.Context(), m.ID, "ROLLING_BACK")
+
+		result.Status = "ROLLING_BACK"
+		result.Message = fmt.Sprintf("Rollback to version %s initiated", req.TargetVersion)
+		response.Members = append(response.Members, result)
+	}
+
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// validateFederationTargets applies target defaults and rejects empty or duplicate targets
+func validateFederationTargets(targets []FederationTargetRequest) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("at least one target is required")
+	}
+
+	seen := make(map[string]bool, len(targets))
+	for i := range targets {
+		target := &targets[i]
+		if target.Cloud == "" {
+			target.Cloud = "gcp"
+		}
+		if target.Cloud == "cloudrun" {
+			return fmt.Errorf("targets[%d]: federation is not supported on cloudrun", i)
+		}
+		if target.Region == "" {
+			return fmt.Errorf("targets[%d].region is required", i)
+		}
+		if target.Replicas < 0 {
+			return fmt.Errorf("targets[%d].replicas must not be negative", i)
+		}
+
+		key := target.Cloud + "/" + target.Region
+		if seen[key] {
+			return fmt.Errorf("targets[%d]: duplicate target %s", i, key)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// aggregateFederatedStatus summarizes member statuses: EXPOSED when every member is live,
+// FAILED when every member failed, DEGRADED when only some failed, IN_PROGRESS otherwise
+func aggregateFederatedStatus(members []state.Deployment) string {
+	exposed, failed := 0, 0
+	for _, m := range members {
+		switch m.Status {
+		case "EXPOSED", "HEALTHY":
+			exposed++
+		case "FAILED", "UNHEALTHY":
+			failed++
+		}
+	}
+
+	switch {
+	case len(members) > 0 && exposed == len(members):
+		return FederatedStatusExposed
+	case len(members) > 0 && failed == len(members):
+		return FederatedStatusFailed
+	case failed > 0:
+		return FederatedStatusDegraded
+	default:
+		return FederatedStatusInProgress
+	}
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alvesdmateus/app-deployer/internal/analyzer"
+	"github.com/alvesdmateus/app-deployer/internal/orchestrator"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// maxWebhookBodyBytes bounds push event payloads, which list up to 20 commits
+const maxWebhookBodyBytes = 5 << 20
+
+// gitHookValidators check a hook registration against what each provider's receiver needs
+var gitHookValidators = map[string]func(req *CreateGitHookRequest) error{
+	state.GitProviderGitHub:    unsupportedGitHook(state.GitProviderGitHub),
+	state.GitProviderGitLab:    validateGitLabHook,
+	state.GitProviderBitbucket: unsupportedGitHook(state.GitProviderBitbucket),
+}
+
+// GitHookHandler handles git hook registration and the push events providers deliver
+type GitHookHandler struct {
+	repo       *state.Repository
+	orchClient *orchestrator.Client
+	cipher     *secrets.Cipher
+}
+
+// NewGitHookHandler creates a new git hook handler. Hook secrets are stored encrypted with
+// cipher, so hooks cannot be registered when it is nil.
+func NewGitHookHandler(repo *state.Repository, orchClient *orchestrator.Client, cipher *secrets.Cipher) *GitHookHandler {
+	return &GitHookHandler{
+		repo:       repo,
+		orchClient: orchClient,
+		cipher:     cipher,
+	}
+}
+
+// CreateGitHook handles POST /api/v1/deployments/{id}/git-hooks
+func (h *GitHookHandler) CreateGitHook(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req CreateGitHookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	validate, ok := gitHookValidators[req.Provider]
+	if !ok {
+		RespondWithError(w, http.StatusBadRequest, "provider must be github, gitlab or bitbucket")
+		return
+	}
+
+	if err := validate(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Branch == "" {
+		req.Branch = "main"
+	}
+
+	if req.SubPath != "" {
+		if err := analyzer.ValidateSubPath(req.SubPath); err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if h.cipher == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Git hooks unavailable - encryption key not configured")
+		return
+	}
+
+	secret, err := h.cipher.Encrypt(req.Secret)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to encrypt git hook secret")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to create git hook")
+		return
+	}
+
+	hook := &state.GitHook{
+		DeploymentID: id,
+		Provider:     req.Provider,
+		RepoURL:      req.RepoURL,
+		Branch:       req.Branch,
+		SubPath:      req.SubPath,
+		Secret:       secret,
+	}
+
+	if err := h.repo.CreateGitHook(r.Context(), hook); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to create git hook")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to create git hook")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("provider", hook.Provider).
+		Str("repo_url", hook.RepoURL).
+		Str("branch", hook.Branch).
+		Msg("Git hook created")
+
+	RespondWithJSON(w, http.StatusCreated, GitHookToResponse(hook))
+}
+
+// gitlabPushEvent is the part of a GitLab push event payload used to start builds
+type gitlabPushEvent struct {
+	ObjectKind  string `json:"object_kind"`
+	Ref         string `json:"ref"`
+	After       string `json:"after"`
+	CheckoutSHA string `json:"checkout_sha"`
+	Project     struct {
+		SSHURLToRepo string `json:"ssh_url_to_repo"`
+		GitSSHURL    string `json:"git_ssh_url"`
+		GitHTTPURL   string `json:"git_http_url"`
+	} `json:"project"`
+}
+
+// GitLabWebhook handles POST /api/v1/webhooks/gitlab
+// Pushes to a hook's branch enqueue a build of the pushed commit for the hook's deployment.
+func (h *GitHookHandler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Gitlab-Token")
+	if token == "" {
+		RespondWithError(w, http.StatusUnauthorized, "X-Gitlab-Token is required")
+		return
+	}
+
+	var event gitlabPushEvent
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes)).Decode(&event); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid GitLab event payload")
+		return
+	}
+
+	if h.orchClient == nil || h.cipher == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Orchestration service unavailable")
+		return
+	}
+
+	// The ssh URL is built from, but hooks may be registered with either clone URL
+	cloneURL := event.Project.SSHURLToRepo
+	if cloneURL == "" {
+		cloneURL = event.Project.GitSSHURL
+	}
+
+	var repoURLs []string
+	for _, u := range []string{event.Project.SSHURLToRepo, event.Project.GitSSHURL, event.Project.GitHTTPURL} {
+		if u != "" {
+			repoURLs = append(repoURLs, u)
+		}
+	}
+
+	hooks, err := h.repo.ListGitHooksByRepo(r.Context(), state.GitProviderGitLab, repoURLs)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list git hooks")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to process GitLab event")
+		return
+	}
+
+	// GitLab sends the secret token as is, so it is compared directly rather than as an HMAC
+	var matched []state.GitHook
+	for _, hook := range hooks {
+		secret, err := h.cipher.Decrypt(hook.Secret)
+		if err != nil {
+			log.Warn().Err(err).Str("hook_id", hook.ID.String()).Msg("Failed to decrypt git hook secret")
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1 {
+			matched = append(matched, hook)
+		}
+	}
+
+	if len(matched) == 0 {
+		RespondWithError(w, http.StatusUnauthorized, "Invalid GitLab token")
+		return
+	}
+
+	commitSHA := event.CheckoutSHA
+	if commitSHA == "" {
+		commitSHA = event.After
+	}
+
+	// Tag pushes, other events and branch deletions have nothing to build
+	if event.ObjectKind != "push" || commitSHA == "" || strings.Trim(commitSHA, "0") == "" || cloneURL == "" {
+		RespondWithSuccess(w, http.StatusOK, "Event ignored", nil)
+		return
+	}
+
+	triggered := []string{}
+	for _, hook := range matched {
+		if event.Ref != "refs/heads/"+hook.Branch {
+			continue
+		}
+
+		if err := h.orchClient.TriggerBuild(r.Context(), &queue.BuildPayload{
+			DeploymentID: hook.DeploymentID.String(),
+			RepoURL:      cloneURL,
+			CommitSHA:    commitSHA,
+			Ref:          event.Ref,
+			Provider:     state.GitProviderGitLab,
+			SubPath:      hook.SubPath,
+		}); err != nil {
+			log.Error().Err(err).
+				Str("deployment_id", hook.DeploymentID.String()).
+				Msg("Failed to trigger build job")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to start build")
+			return
+		}
+
+		if err := h.repo.MarkGitHookTriggered(r.Context(), hook.ID, commitSHA); err != nil {
+			log.Warn().Err(err).Str("hook_id", hook.ID.String()).Msg("Failed to record git hook trigger")
+		}
+
+		triggered = append(triggered, hook.DeploymentID.String())
+	}
+
+	if len(triggered) == 0 {
+		RespondWithSuccess(w, http.StatusOK, fmt.Sprintf("No hook watches %s", event.Ref), nil)
+		return
+	}
+
+	log.Info().
+		Str("ref", event.Ref).
+		Str("commit_sha", commitSHA).
+		Strs("deployments", triggered).
+		Msg("GitLab push started builds")
+
+	RespondWithSuccess(w, http.StatusAccepted, "Build started", map[string]interface{}{
+		"commit_sha":  commitSHA,
+		"deployments": triggered,
+	})
+}
+
+// validateGitLabHook checks a GitLab hook registration. GitLab's secret token is an arbitrary
+// string the project's webhook is configured with.
+func validateGitLabHook(req *CreateGitHookRequest) error {
+	if req.RepoURL == "" {
+		return fmt.Errorf("repo_url is required")
+	}
+
+	if req.Secret == "" {
+		return fmt.Errorf("secret is required, set it as the GitLab webhook's secret token")
+	}
+
+	return nil
+}
+
+// unsupportedGitHook rejects registrations for a provider whose push events are not received yet
+func unsupportedGitHook(provider string) func(req *CreateGitHookRequest) error {
+	return func(req *CreateGitHookRequest) error {
+		return fmt.Errorf("%s git hooks are not supported yet", provider)
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// HPAHandler handles Horizontal Pod Autoscaler HTTP requests
+type HPAHandler struct {
+	repo *state.Repository
+	helm *deployer.HelmDeployer
+}
+
+// NewHPAHandler creates a new HPA handler
+func NewHPAHandler(repo *state.Repository, helm *deployer.HelmDeployer) *HPAHandler {
+	return &HPAHandler{
+		repo: repo,
+		helm: helm,
+	}
+}
+
+// UpdateHPA handles PUT /api/v1/deployments/{id}/hpa
+func (h *HPAHandler) UpdateHPA(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req UpdateHPARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateHPA(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.Cloud == "cloudrun" || deployment.DeployerType == deployer.DeployerTypeKustomize {
+		RespondWithError(w, http.StatusBadRequest, "HPA is only supported for Helm deployments on GKE")
+		return
+	}
+
+	if deployment.DeploymentType != "" && deployment.DeploymentType != deployer.DeploymentTypeService {
+		RespondWithError(w, http.StatusBadRequest, "HPA is only supported for service deployments")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Helm release")
+		return
+	}
+
+	if h.helm == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Deployer unavailable")
+		return
+	}
+
+	// Stored first so later deploys render the same autoscaler
+	infra.HPAConfig = &state.HPAConfig{
+		MinReplicas:         req.MinReplicas,
+		MaxReplicas:         req.MaxReplicas,
+		TargetCPUPercent:    req.TargetCPUPercent,
+		TargetMemoryPercent: req.TargetMemoryPercent,
+	}
+	if err := h.repo.UpdateInfrastructure(r.Context(), infra); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to update infrastructure")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update HPA configuration")
+		return
+	}
+
+	values := map[string]string{
+		"autoscaling.enabled":                           "true",
+		"autoscaling.minReplicas":                       strconv.Itoa(req.MinReplicas),
+		"autoscaling.maxReplicas":                       strconv.Itoa(req.MaxReplicas),
+		"autoscaling.targetCPUUtilizationPercentage":    strconv.Itoa(req.TargetCPUPercent),
+		"autoscaling.targetMemoryUtilizationPercentage": strconv.Itoa(req.TargetMemoryPercent),
+	}
+	if err := h.helm.UpgradeValues(r.Context(), infra, values); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to upgrade Helm release")
+		RespondWithError(w, http.StatusInternalServerError,
+			"Failed to apply HPA configuration. It will be applied on the next deploy.")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Int("min_replicas", req.MinReplicas).
+		Int("max_replicas", req.MaxReplicas).
+		Msg("HPA configuration updated")
+
+	RespondWithJSON(w, http.StatusOK, HPAConfigResponse{
+		DeploymentID:        id,
+		MinReplicas:         req.MinReplicas,
+		MaxReplicas:         req.MaxReplicas,
+		TargetCPUPercent:    req.TargetCPUPercent,
+		TargetMemoryPercent: req.TargetMemoryPercent,
+	})
+}
+
+// GetHPAStatus handles GET /api/v1/deployments/{id}/hpa/status
+func (h *HPAHandler) GetHPAStatus(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Helm release")
+		return
+	}
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to create Kubernetes client")
+		RespondWithError(w, http.StatusServiceUnavailable, "Cluster unavailable")
+		return
+	}
+
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", infra.HelmReleaseName)
+	status, found, err := kubeClient.GetHorizontalPodAutoscaler(r.Context(), infra.KubeNamespace, labelSelector)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get horizontal pod autoscaler")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get HPA status")
+		return
+	}
+
+	if !found {
+		RespondWithError(w, http.StatusNotFound, "Deployment is not autoscaled")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, HPAStatusToResponse(id, status))
+}
+
+// validateHPA checks replica bounds and that at least one utilization target is set
+func validateHPA(req *UpdateHPARequest) error {
+	if req.MinReplicas < 1 {
+		return fmt.Errorf("min_replicas must be at least 1")
+	}
+
+	if req.MaxReplicas < req.MinReplicas {
+		return fmt.Errorf("max_replicas must be at least min_replicas")
+	}
+
+	if req.TargetCPUPercent == 0 && req.TargetMemoryPercent == 0 {
+		return fmt.Errorf("target_cpu_percent or target_memory_percent is required")
+	}
+
+	if req.TargetCPUPercent < 0 || req.TargetCPUPercent > 100 {
+		return fmt.Errorf("target_cpu_percent must be between 1 and 100")
+	}
+
+	if req.TargetMemoryPercent < 0 || req.TargetMemoryPercent > 100 {
+		return fmt.Errorf("target_memory_percent must be between 1 and 100")
+	}
+
+	return nil
+}
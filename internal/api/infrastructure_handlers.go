@@ -1,27 +1,72 @@
 package api
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/orchestrator"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner/gcp"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/security"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/rs/zerolog/log"
 )
 
+// maxAutoscalerEvents caps how many autoscaler events are returned
+const maxAutoscalerEvents = 100
+
+// resourcesCacheTTL bounds how often the Pulumi backend is read for a stack's resources
+const resourcesCacheTTL = 60 * time.Second
+
+// progressHeartbeat is how often an idle progress stream is kept alive and the run's status
+// rechecked, in case its final message was published before the stream subscribed
+const progressHeartbeat = 15 * time.Second
+
+// orphanedStackMinAge is how long infrastructure must have been FAILED or DESTROYED before
+// its stacks are reported, leaving time for retries and in-flight destroys
+const orphanedStackMinAge = 24 * time.Hour
+
+// stackNamePrefix and identityStackSuffix match the GCP provisioner's stack names:
+// deployer-{deployment-id} and deployer-{deployment-id}-identity
+const (
+	stackNamePrefix     = "deployer-"
+	identityStackSuffix = "-identity"
+)
+
 // InfrastructureHandler handles infrastructure-related HTTP requests
 type InfrastructureHandler struct {
-	repo *state.Repository
+	repo        *state.Repository
+	provisioner provisioner.Provisioner
+	cache       *queue.RedisQueue
+	orchClient  *orchestrator.Client
+	gcpProject  string // Project of provisioned clusters' load balancers
 }
 
 // NewInfrastructureHandler creates a new infrastructure handler
-func NewInfrastructureHandler(repo *state.Repository) *InfrastructureHandler {
-	return &InfrastructureHandler{repo: repo}
+func NewInfrastructureHandler(repo *state.Repository, prov provisioner.Provisioner, cache *queue.RedisQueue, orchClient *orchestrator.Client, gcpProject string) *InfrastructureHandler {
+	return &InfrastructureHandler{
+		repo:        repo,
+		provisioner: prov,
+		cache:       cache,
+		orchClient:  orchClient,
+		gcpProject:  gcpProject,
+	}
 }
 
-// GetInfrastructure handles GET /api/v1/deployments/{deployment_id}/infrastructure
+// GetInfrastructure handles GET /api/v1/deployments/{id}/infrastructure
 func (h *InfrastructureHandler) GetInfrastructure(w http.ResponseWriter, r *http.Request) {
-	deploymentIDStr := chi.URLParam(r, "deployment_id")
+	deploymentIDStr := chi.URLParam(r, "id")
 	deploymentID, err := uuid.Parse(deploymentIDStr)
 	if err != nil {
 		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
@@ -38,3 +83,832 @@ func (h *InfrastructureHandler) GetInfrastructure(w http.ResponseWriter, r *http
 	response := InfrastructureToResponse(infra)
 	RespondWithJSON(w, http.StatusOK, response)
 }
+
+// StreamInfrastructureProgress handles GET /api/v1/deployments/{id}/infrastructure/progress
+// Provisioning output is streamed as server-sent events: lines already written are replayed,
+// then new ones follow as Pulumi writes them until the run ends or the client disconnects.
+func (h *InfrastructureHandler) StreamInfrastructureProgress(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if h.cache == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Progress streaming unavailable - Redis not configured")
+		return
+	}
+
+	ctx := r.Context()
+	client := h.cache.Client()
+	key := provisioner.ProgressKey(infra.ID.String())
+
+	// Subscribe before replaying so no line written in between is missed
+	sub := client.Subscribe(ctx, key)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to subscribe to provisioning progress")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to stream provisioning progress")
+		return
+	}
+
+	lines, err := client.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to read provisioning progress")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to stream provisioning progress")
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for i, line := range lines {
+		writeProgressEvent(w, int64(i+1), line)
+	}
+	replayed := int64(len(lines))
+
+	if infra.Status != "PROVISIONING" {
+		writeProgressDone(w, infra.Status)
+		_ = rc.Flush()
+		return
+	}
+	_ = rc.Flush()
+
+	heartbeat := time.NewTicker(progressHeartbeat)
+	defer heartbeat.Stop()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-heartbeat.C:
+			if current, err := h.repo.GetInfrastructureByID(ctx, infra.ID); err == nil && current.Status != "PROVISIONING" {
+				writeProgressDone(w, current.Status)
+				_ = rc.Flush()
+				return
+			}
+			fmt.Fprint(w, ": keepalive\n\n")
+			_ = rc.Flush()
+
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var progress provisioner.ProgressMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &progress); err != nil {
+				continue
+			}
+
+			if progress.Done {
+				writeProgressDone(w, progress.Status)
+				_ = rc.Flush()
+				return
+			}
+
+			// Lines pushed between subscribing and replaying arrive twice
+			if progress.Seq <= replayed {
+				continue
+			}
+
+			writeProgressEvent(w, progress.Seq, progress.Line)
+			_ = rc.Flush()
+		}
+	}
+}
+
+// writeProgressEvent writes a progress line as a server-sent event, identified by its
+// position in the run's output
+func writeProgressEvent(w http.ResponseWriter, seq int64, line string) {
+	// A carriage return would end the data field early
+	fmt.Fprintf(w, "id: %d\nevent: progress\ndata: %s\n\n", seq, strings.ReplaceAll(line, "\r", ""))
+}
+
+// writeProgressDone writes the event that ends a progress stream
+func writeProgressDone(w http.ResponseWriter, status string) {
+	data, _ := json.Marshal(map[string]string{"status": status})
+	fmt.Fprintf(w, "event: done\ndata: %s\n\n", data)
+}
+
+// GetWAFStatus handles GET /api/v1/deployments/{id}/waf
+func (h *InfrastructureHandler) GetWAFStatus(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	response := WAFStatusResponse{
+		DeploymentID: deployment.ID,
+		Enabled:      deployment.CloudArmorEnabled,
+		Status:       "DISABLED",
+	}
+
+	if deployment.CloudArmorEnabled {
+		response.SecurityPolicy = deployment.CloudArmorPolicy
+		response.Status = "PENDING"
+
+		if deployment.RateLimitRequestsPerMinute > 0 {
+			response.RateLimit = &RateLimitRequest{
+				RequestsPerMinutePerIP: deployment.RateLimitRequestsPerMinute,
+				EnforceOnURIPaths:      deployment.RateLimitPaths,
+			}
+		}
+
+		// The policy is attached once the app is first exposed
+		if infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID); err == nil {
+			response.AttachedPolicy = infra.WAFPolicyName
+			response.BackendService = infra.WAFBackendService
+			response.Error = infra.WAFError
+
+			switch {
+			case infra.WAFError != "":
+				response.Status = "FAILED"
+			case infra.WAFPolicyName != "":
+				response.Status = "ATTACHED"
+			}
+
+			response.RateLimitRule = infra.RateLimitRuleName
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// UpdateRateLimit handles PUT /api/v1/deployments/{id}/waf/rate-limit
+// The limit is stored for later deploys and, once the app's policy is attached, applied to
+// the policy straight away. Zero requests per minute removes it.
+func (h *InfrastructureHandler) UpdateRateLimit(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req RateLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.RequestsPerMinutePerIP < 0 {
+		RespondWithError(w, http.StatusBadRequest, "requests_per_minute_per_ip must not be negative")
+		return
+	}
+	if err := validateRateLimitPaths(req.EnforceOnURIPaths); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Read from the primary, since the new limit is saved with the whole deployment
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if !deployment.CloudArmorEnabled {
+		RespondWithError(w, http.StatusBadRequest, "Rate limits require the deployment's WAF to be enabled")
+		return
+	}
+
+	deployment.RateLimitRequestsPerMinute = req.RequestsPerMinutePerIP
+	deployment.RateLimitPaths = req.EnforceOnURIPaths
+	if req.RequestsPerMinutePerIP == 0 {
+		deployment.RateLimitPaths = nil
+	}
+	if err := h.repo.UpdateDeployment(r.Context(), deployment); err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to update deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to update rate limit")
+		return
+	}
+
+	response := WAFStatusResponse{
+		DeploymentID:   deployment.ID,
+		Enabled:        true,
+		Status:         "PENDING",
+		SecurityPolicy: deployment.CloudArmorPolicy,
+	}
+	if req.RequestsPerMinutePerIP > 0 {
+		response.RateLimit = &req
+	}
+
+	// Until the policy is attached the limit is applied with it on the next deploy
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil || infra.WAFPolicyName == "" {
+		RespondWithJSON(w, http.StatusOK, response)
+		return
+	}
+
+	var rule *security.RateLimitRule
+	if req.RequestsPerMinutePerIP > 0 {
+		rule = &security.RateLimitRule{
+			Name:                   security.RateLimitRuleName(deployment.ID.String()),
+			RequestsPerMinutePerIP: req.RequestsPerMinutePerIP,
+			EnforceOnURIPaths:      req.EnforceOnURIPaths,
+		}
+	}
+
+	project := h.gcpProject
+	if infra.ImportedExternally {
+		project = infra.GCPProject
+	}
+
+	ruleName, err := security.ApplyRateLimitRule(r.Context(), project, infra.WAFPolicyName,
+		security.BackendServiceRegion(infra.WAFBackendService), infra.RateLimitRuleName, rule)
+	if recordErr := h.repo.UpdateInfrastructureRateLimitRule(r.Context(), infra.ID, ruleName); recordErr != nil {
+		log.Warn().Err(recordErr).Str("deployment_id", deploymentIDStr).Msg("Failed to record rate limit rule")
+	}
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to apply rate limit rule")
+		RespondWithError(w, http.StatusInternalServerError,
+			"Failed to apply rate limit. It will be applied on the next deploy.")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", deploymentIDStr).
+		Int("requests_per_minute_per_ip", req.RequestsPerMinutePerIP).
+		Msg("Rate limit updated")
+
+	response.Status = "ATTACHED"
+	response.AttachedPolicy = infra.WAFPolicyName
+	response.BackendService = infra.WAFBackendService
+	response.RateLimitRule = ruleName
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// ExportInfrastructure handles GET /api/v1/deployments/{id}/infrastructure/export
+// The recorded infrastructure is rendered as Terraform (the default) or Pulumi YAML that
+// imports the existing resources; no cloud resources are read or changed.
+func (h *InfrastructureHandler) ExportInfrastructure(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "terraform"
+	}
+
+	var export func(*state.Infrastructure) (string, error)
+	switch format {
+	case "terraform":
+		export = gcp.ExportTerraform
+	case "pulumi-yaml":
+		export = gcp.ExportPulumiYAML
+	default:
+		RespondWithError(w, http.StatusBadRequest, "format must be terraform or pulumi-yaml")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.Status != "READY" {
+		RespondWithError(w, http.StatusConflict, fmt.Sprintf("Infrastructure is %s, only READY infrastructure can be exported", infra.Status))
+		return
+	}
+
+	body, err := export(infra)
+	if errors.Is(err, gcp.ErrNotExportable) {
+		RespondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to export infrastructure")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to export infrastructure")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(body)); err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to write infrastructure export")
+	}
+}
+
+// ImportInfrastructure handles POST /api/v1/deployments/{id}/import-infrastructure
+func (h *InfrastructureHandler) ImportInfrastructure(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req ImportInfrastructureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.ClusterName == "" || req.ClusterEndpoint == "" || req.ClusterCACert == "" ||
+		req.GCPProject == "" || req.Region == "" {
+		RespondWithError(w, http.StatusBadRequest,
+			"cluster_name, cluster_endpoint, cluster_ca_cert, gcp_project and region are required")
+		return
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(req.ClusterCACert); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "cluster_ca_cert must be base64 encoded")
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.Cloud == "cloudrun" {
+		RespondWithError(w, http.StatusBadRequest, "GKE clusters cannot be imported for cloudrun deployments")
+		return
+	}
+
+	infra := &state.Infrastructure{
+		DeploymentID:       deploymentID,
+		ClusterName:        req.ClusterName,
+		Namespace:          req.Namespace,
+		Status:             "READY",
+		Config:             `{"type":"imported"}`,
+		ClusterEndpoint:    req.ClusterEndpoint,
+		ClusterCACert:      req.ClusterCACert,
+		ClusterLocation:    req.Region,
+		ImportedExternally: true,
+		GCPProject:         req.GCPProject,
+	}
+
+	// A destroyed record is replaced in place so the deployment keeps a single infrastructure row
+	existing, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err == nil {
+		if existing.Status != "DESTROYED" {
+			RespondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("Deployment already has infrastructure in status %s", existing.Status))
+			return
+		}
+
+		infra.ID = existing.ID
+		infra.CreatedAt = existing.CreatedAt
+		err = h.repo.UpdateInfrastructure(r.Context(), infra)
+	} else {
+		err = h.repo.CreateInfrastructure(r.Context(), infra)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to record imported infrastructure")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to import infrastructure")
+		return
+	}
+
+	if err := h.repo.SetDeploymentInfrastructure(r.Context(), deploymentID, infra.ID); err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to link imported infrastructure")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to import infrastructure")
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", deploymentIDStr).
+		Str("cluster_name", req.ClusterName).
+		Str("gcp_project", req.GCPProject).
+		Msg("Imported existing GKE cluster")
+
+	RespondWithJSON(w, http.StatusCreated, InfrastructureToResponse(infra))
+}
+
+// ListInfrastructureResources handles GET /api/v1/deployments/{id}/infrastructure/resources
+func (h *InfrastructureHandler) ListInfrastructureResources(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	// Cloud Run targets and imported clusters have no stack to list
+	if infra.PulumiStackName == "" {
+		RespondWithError(w, http.StatusNotFound, "Infrastructure has no Pulumi stack")
+		return
+	}
+
+	if h.provisioner == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Provisioner unavailable")
+		return
+	}
+
+	cacheKey := "resources:" + infra.PulumiStackName
+
+	// Serve from cache when possible to avoid hitting the Pulumi backend on every request
+	if h.cache != nil {
+		if data, err := h.cache.GetCache(r.Context(), cacheKey); err != nil {
+			log.Warn().Err(err).Str("stack_name", infra.PulumiStackName).Msg("Failed to read resources cache")
+		} else if data != nil {
+			var response InfrastructureResourcesResponse
+			if err := json.Unmarshal(data, &response); err == nil {
+				response.Cached = true
+				RespondWithJSON(w, http.StatusOK, response)
+				return
+			}
+		}
+	}
+
+	resources, err := h.provisioner.ListResources(r.Context(), infra.PulumiStackName)
+	if err != nil {
+		log.Error().Err(err).Str("stack_name", infra.PulumiStackName).Msg("Failed to list infrastructure resources")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list infrastructure resources")
+		return
+	}
+
+	response := InfrastructureResourcesResponse{
+		DeploymentID: deploymentID,
+		StackName:    infra.PulumiStackName,
+		Resources:    CloudResourcesToResponse(resources),
+	}
+
+	if h.cache != nil {
+		if data, err := json.Marshal(response); err == nil {
+			if err := h.cache.SetCache(r.Context(), cacheKey, data, resourcesCacheTTL); err != nil {
+				log.Warn().Err(err).Str("stack_name", infra.PulumiStackName).Msg("Failed to write resources cache")
+			}
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// UpdateNodePool handles PATCH /api/v1/deployments/{id}/infrastructure/node-pool
+func (h *InfrastructureHandler) UpdateNodePool(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req UpdateNodePoolRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.NodeCount < 0 {
+		RespondWithError(w, http.StatusBadRequest, "node_count must not be negative")
+		return
+	}
+
+	if req.NodeCount == 0 && req.MachineType == "" {
+		RespondWithError(w, http.StatusBadRequest, "node_count or machine_type is required")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	// Cloud Run targets have no node pool and imported clusters are managed by their owners
+	if infra.PulumiStackName == "" {
+		RespondWithError(w, http.StatusBadRequest, "Infrastructure has no node pool to update")
+		return
+	}
+
+	if infra.Status != "READY" {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("Infrastructure must be READY to update, current status is %s", infra.Status))
+		return
+	}
+
+	if h.orchClient == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Orchestration service unavailable")
+		return
+	}
+
+	updatePayload := &queue.UpdateInfraPayload{
+		DeploymentID:     deploymentIDStr,
+		InfrastructureID: infra.ID.String(),
+		NodeCount:        req.NodeCount,
+		MachineType:      req.MachineType,
+	}
+
+	if err := h.orchClient.TriggerUpdateInfra(r.Context(), updatePayload); err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", deploymentIDStr).
+			Msg("Failed to trigger infrastructure update job")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start node pool update")
+		return
+	}
+
+	response := OrchestrationResponse{
+		DeploymentID: deploymentIDStr,
+		Status:       "UPDATING",
+		Message:      "Node pool update initiated",
+	}
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// MigrateBackend handles POST /api/v1/admin/infrastructure/{id}/migrate-backend
+func (h *InfrastructureHandler) MigrateBackend(w http.ResponseWriter, r *http.Request) {
+	infraIDStr := chi.URLParam(r, "id")
+	infraID, err := uuid.Parse(infraIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid infrastructure ID")
+		return
+	}
+
+	var req MigrateBackendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.ToBackend == "" {
+		RespondWithError(w, http.StatusBadRequest, "to_backend is required")
+		return
+	}
+
+	for _, backend := range []string{req.FromBackend, req.ToBackend} {
+		if backend == "" {
+			continue
+		}
+		if parsed, err := url.Parse(backend); err != nil || parsed.Scheme == "" {
+			RespondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("%s is not a backend URL, e.g. gs://bucket or https://api.pulumi.com", backend))
+			return
+		}
+	}
+
+	if req.FromBackend == req.ToBackend {
+		RespondWithError(w, http.StatusBadRequest, "from_backend and to_backend must differ")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructureByID(r.Context(), infraID)
+	if err != nil {
+		log.Error().Err(err).Str("infrastructure_id", infraIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	// Cloud Run targets and imported clusters are not managed by Pulumi
+	if infra.PulumiStackName == "" {
+		RespondWithError(w, http.StatusBadRequest, "Infrastructure has no Pulumi stack to migrate")
+		return
+	}
+
+	if infra.PulumiBackendURL != "" && infra.PulumiBackendURL == req.ToBackend {
+		RespondWithError(w, http.StatusBadRequest, "Stack is already in the target backend")
+		return
+	}
+
+	if infra.Status != "READY" {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("Infrastructure must be READY to migrate, current status is %s", infra.Status))
+		return
+	}
+
+	if h.orchClient == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Orchestration service unavailable")
+		return
+	}
+
+	migratePayload := &queue.MigrateBackendPayload{
+		DeploymentID:     infra.DeploymentID.String(),
+		InfrastructureID: infra.ID.String(),
+		StackName:        infra.PulumiStackName,
+		FromBackend:      req.FromBackend,
+		ToBackend:        req.ToBackend,
+	}
+
+	if err := h.orchClient.TriggerMigrateBackend(r.Context(), migratePayload); err != nil {
+		log.Error().Err(err).
+			Str("infrastructure_id", infraIDStr).
+			Msg("Failed to trigger backend migration job")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to start backend migration")
+		return
+	}
+
+	response := OrchestrationResponse{
+		DeploymentID: infra.DeploymentID.String(),
+		Status:       "MIGRATING",
+		Message:      fmt.Sprintf("Migration of stack %s to %s initiated", infra.PulumiStackName, req.ToBackend),
+	}
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// ListOrphanedResources handles GET /api/v1/admin/orphaned-resources
+func (h *InfrastructureHandler) ListOrphanedResources(w http.ResponseWriter, r *http.Request) {
+	if h.provisioner == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Provisioner unavailable")
+		return
+	}
+
+	orphans, err := h.findOrphanedStacks(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find orphaned stacks")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to find orphaned resources")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, OrphanedResourcesResponse{
+		Stacks: orphans,
+		Total:  len(orphans),
+	})
+}
+
+// CleanupOrphanedResources handles POST /api/v1/admin/orphaned-resources/cleanup
+func (h *InfrastructureHandler) CleanupOrphanedResources(w http.ResponseWriter, r *http.Request) {
+	if h.provisioner == nil || h.orchClient == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Orchestration service unavailable")
+		return
+	}
+
+	orphans, err := h.findOrphanedStacks(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find orphaned stacks")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to find orphaned resources")
+		return
+	}
+
+	response := OrphanedCleanupResponse{Triggered: []string{}}
+	for _, orphan := range orphans {
+		if err := h.orchClient.TriggerDestroyStack(r.Context(), &queue.DestroyStackPayload{
+			DeploymentID:     orphan.DeploymentID,
+			InfrastructureID: orphan.InfrastructureID,
+			StackName:        orphan.StackName,
+		}); err != nil {
+			log.Error().Err(err).
+				Str("stack_name", orphan.StackName).
+				Msg("Failed to trigger stack destroy job")
+			response.Failed = append(response.Failed, orphan.StackName)
+			continue
+		}
+		response.Triggered = append(response.Triggered, orphan.StackName)
+	}
+
+	log.Info().
+		Int("triggered", len(response.Triggered)).
+		Int("failed", len(response.Failed)).
+		Msg("Orphaned stack cleanup started")
+
+	RespondWithJSON(w, http.StatusAccepted, response)
+}
+
+// findOrphanedStacks lists the deployer stacks in the Pulumi backend whose infrastructure
+// record is missing, or has been FAILED or DESTROYED for longer than orphanedStackMinAge
+func (h *InfrastructureHandler) findOrphanedStacks(ctx context.Context) ([]OrphanedStackResponse, error) {
+	stackNames, err := h.provisioner.ListStacks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stacks: %w", err)
+	}
+
+	// Identity stacks are owned by the record of the deployment's main stack
+	owners := make(map[string]string, len(stackNames))
+	mainStacks := make([]string, 0, len(stackNames))
+	for _, name := range stackNames {
+		if !strings.HasPrefix(name, stackNamePrefix) {
+			continue
+		}
+		owner := strings.TrimSuffix(name, identityStackSuffix)
+		if _, err := uuid.Parse(strings.TrimPrefix(owner, stackNamePrefix)); err != nil {
+			continue
+		}
+		owners[name] = owner
+		mainStacks = append(mainStacks, owner)
+	}
+
+	infrastructures, err := h.repo.ListInfrastructureByStackNames(ctx, mainStacks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Records are newest first, so each stack keeps its most recent record
+	byStack := make(map[string]*state.Infrastructure, len(infrastructures))
+	for _, infra := range infrastructures {
+		if _, ok := byStack[infra.PulumiStackName]; !ok {
+			byStack[infra.PulumiStackName] = infra
+		}
+	}
+
+	cutoff := time.Now().Add(-orphanedStackMinAge)
+	orphans := []OrphanedStackResponse{}
+	for _, name := range stackNames {
+		owner, ok := owners[name]
+		if !ok {
+			continue
+		}
+
+		orphan := OrphanedStackResponse{
+			StackName:    name,
+			DeploymentID: strings.TrimPrefix(owner, stackNamePrefix),
+		}
+
+		if infra, ok := byStack[owner]; ok {
+			if infra.Status != "FAILED" && infra.Status != "DESTROYED" {
+				continue
+			}
+			if infra.UpdatedAt.After(cutoff) {
+				continue
+			}
+
+			updatedAt := infra.UpdatedAt
+			orphan.InfrastructureID = infra.ID.String()
+			orphan.InfrastructureStatus = infra.Status
+			orphan.UpdatedAt = &updatedAt
+		}
+
+		orphans = append(orphans, orphan)
+	}
+
+	return orphans, nil
+}
+
+// GetAutoscalerEvents handles GET /api/v1/deployments/{id}/infrastructure/autoscaler-events
+func (h *InfrastructureHandler) GetAutoscalerEvents(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Kubernetes cluster")
+		return
+	}
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to create Kubernetes client")
+		RespondWithError(w, http.StatusServiceUnavailable, "Cluster unavailable")
+		return
+	}
+
+	// Each deployment has its own cluster, so scale events for every namespace belong to it
+	events, err := kubeClient.ListEvents(r.Context(), "", "cluster-autoscaler")
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to list autoscaler events")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list autoscaler events")
+		return
+	}
+
+	if len(events) > maxAutoscalerEvents {
+		events = events[:maxAutoscalerEvents]
+	}
+
+	response := AutoscalerEventsResponse{
+		DeploymentID: deploymentID,
+		Events:       EventsToResponse(events),
+	}
+	RespondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,402 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest apiVersion and kind accepted by import
+const (
+	manifestAPIVersion = "deployer.io/v1"
+	manifestKind       = "Deployment"
+)
+
+// maxManifestBytes bounds imported manifests, leaving room for a few full-size ConfigMaps
+const maxManifestBytes = 4 << 20
+
+// DeploymentManifest is a deployment's configuration as a deployer.yaml file, e.g.
+//
+//	apiVersion: deployer.io/v1
+//	kind: Deployment
+//	metadata:
+//	  name: my-deployment
+//	  tags: {env: production}
+//	spec:
+//	  app_name: my-app
+//	  version: v1.0.0
+//	  env:
+//	    - name: LOG_LEVEL
+//	      value: info
+//
+// The spec holds the create deployment request fields other than name and tags, alongside the
+// deployment's environment variables and ConfigMaps.
+type DeploymentManifest struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   ManifestMetadata `yaml:"metadata"`
+	Spec       ManifestSpec     `yaml:"spec"`
+}
+
+// ManifestMetadata identifies the deployment a manifest describes
+type ManifestMetadata struct {
+	Name string            `yaml:"name"`
+	Tags map[string]string `yaml:"tags,omitempty"`
+}
+
+// ManifestSpec is the desired configuration of a manifest's deployment
+type ManifestSpec struct {
+	Settings   map[string]interface{} `yaml:",inline"` // Create deployment request fields
+	Env        []ManifestEnvVar       `yaml:"env,omitempty"`
+	ConfigMaps []ManifestConfigMap    `yaml:"config_maps,omitempty"`
+}
+
+// ManifestEnvVar is an environment variable of a manifest's deployment. Secret values are
+// never exported or imported; secrets are listed by name and set through the env endpoint.
+type ManifestEnvVar struct {
+	Name   string `yaml:"name"`
+	Value  string `yaml:"value,omitempty"`
+	Secret bool   `yaml:"secret,omitempty"`
+}
+
+// ManifestConfigMap is a ConfigMap mounted into a manifest's deployment
+type ManifestConfigMap struct {
+	Name      string            `yaml:"name"`
+	MountPath string            `yaml:"mount_path,omitempty"`
+	Data      map[string]string `yaml:"data"`
+}
+
+// ExportDeployment handles GET /api/v1/deployments/{id}/export?format=yaml
+func (h *DeploymentHandler) ExportDeployment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if format := r.URL.Query().Get("format"); format != "" && format != "yaml" {
+		RespondWithError(w, http.StatusBadRequest, "format must be yaml")
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	envVars, err := h.repo.ListDeploymentEnvVars(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list env vars")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to export deployment")
+		return
+	}
+
+	configMaps, err := h.repo.ListDeploymentConfigMaps(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list configmaps")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to export deployment")
+		return
+	}
+
+	manifest, err := deploymentManifest(deployment, envVars, configMaps)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to build deployment manifest")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to export deployment")
+		return
+	}
+
+	var body bytes.Buffer
+	encoder := yaml.NewEncoder(&body)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(manifest); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to encode deployment manifest")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to export deployment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", `attachment; filename="deployer.yaml"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to write deployment manifest")
+	}
+}
+
+// ImportDeployment handles POST /api/v1/deployments/import, creating a deployment from a
+// deployer.yaml manifest. The manifest is validated like a create request, and its
+// environment variables and ConfigMaps are stored before any image tag it sets is provisioned.
+func (h *DeploymentHandler) ImportDeployment(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxManifestBytes))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var manifest DeploymentManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid manifest: "+err.Error())
+		return
+	}
+
+	if manifest.APIVersion != manifestAPIVersion || manifest.Kind != manifestKind {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("manifest must have apiVersion %s and kind %s", manifestAPIVersion, manifestKind))
+		return
+	}
+
+	if err := validateManifestSpec(&manifest.Spec); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The spec is checked against the same schema as create requests
+	settings := make(map[string]interface{}, len(manifest.Spec.Settings)+2)
+	for key, value := range manifest.Spec.Settings {
+		settings[key] = value
+	}
+	settings["name"] = manifest.Metadata.Name
+	if len(manifest.Metadata.Tags) > 0 {
+		settings["tags"] = manifest.Metadata.Tags
+	}
+
+	body, err := json.Marshal(settings)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid manifest: "+err.Error())
+		return
+	}
+
+	var req CreateDeploymentRequest
+	if !unmarshalValidated(w, body, deploymentSchema, &req) {
+		return
+	}
+
+	if len(manifest.Spec.ConfigMaps) > 0 && req.Cloud == "cloudrun" {
+		RespondWithError(w, http.StatusBadRequest, "ConfigMaps are only supported for Helm deployments on GKE")
+		return
+	}
+
+	h.createDeployment(w, r, req, func(deployment *state.Deployment) error {
+		return h.applyManifestSpec(r, deployment, &manifest.Spec)
+	})
+}
+
+// applyManifestSpec stores the environment variables and ConfigMaps of an imported deployment
+func (h *DeploymentHandler) applyManifestSpec(r *http.Request, deployment *state.Deployment, spec *ManifestSpec) error {
+	for _, env := range spec.Env {
+		if env.Secret {
+			log.Warn().
+				Str("deployment_id", deployment.ID.String()).
+				Str("key", env.Name).
+				Msg("Imported deployment needs secret environment variable set")
+			continue
+		}
+
+		if err := h.repo.SetDeploymentEnvVar(r.Context(), &state.DeploymentEnvVar{
+			DeploymentID: deployment.ID,
+			Key:          env.Name,
+			Value:        env.Value,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, cm := range spec.ConfigMaps {
+		if err := h.repo.SetDeploymentConfigMap(r.Context(), &state.DeploymentConfigMap{
+			DeploymentID: deployment.ID,
+			Name:         cm.Name,
+			MountPath:    cm.MountPath,
+			Data:         cm.Data,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateManifestSpec checks a manifest's environment variables and ConfigMaps
+func validateManifestSpec(spec *ManifestSpec) error {
+	seen := make(map[string]bool, len(spec.Env))
+	for _, env := range spec.Env {
+		if !envVarKeyPattern.MatchString(env.Name) {
+			return fmt.Errorf("env: invalid name %q", env.Name)
+		}
+		if seen[env.Name] {
+			return fmt.Errorf("env: %s is listed more than once", env.Name)
+		}
+		seen[env.Name] = true
+
+		if env.Secret && env.Value != "" {
+			return fmt.Errorf("env: %s is a secret, set its value with POST /deployments/{id}/env instead of in the manifest", env.Name)
+		}
+	}
+
+	for _, cm := range spec.ConfigMaps {
+		req := SetConfigMapRequest{Name: cm.Name, MountPath: cm.MountPath, Data: cm.Data}
+		if err := validateConfigMap(&req); err != nil {
+			return fmt.Errorf("config_maps: %s: %w", cm.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// deploymentManifest describes a deployment as a manifest that recreates it
+func deploymentManifest(d *state.Deployment, envVars []state.DeploymentEnvVar, configMaps []state.DeploymentConfigMap) (*DeploymentManifest, error) {
+	req, err := manifestRequest(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through JSON so the spec uses the create request's field names
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deployment settings: %w", err)
+	}
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode deployment settings: %w", err)
+	}
+	delete(settings, "name")
+	delete(settings, "tags")
+
+	manifest := &DeploymentManifest{
+		APIVersion: manifestAPIVersion,
+		Kind:       manifestKind,
+		Metadata: ManifestMetadata{
+			Name: d.Name,
+			Tags: d.Tags,
+		},
+		Spec: ManifestSpec{Settings: settings},
+	}
+
+	for _, env := range envVars {
+		entry := ManifestEnvVar{Name: env.Key, Secret: env.IsSecret}
+		if !env.IsSecret {
+			entry.Value = env.Value
+		}
+		manifest.Spec.Env = append(manifest.Spec.Env, entry)
+	}
+
+	for _, cm := range configMaps {
+		manifest.Spec.ConfigMaps = append(manifest.Spec.ConfigMaps, ManifestConfigMap{
+			Name:      cm.Name,
+			MountPath: cm.MountPath,
+			Data:      cm.Data,
+		})
+	}
+
+	return manifest, nil
+}
+
+// manifestRequest rebuilds the create request of a deployment's settings. Rollout state, such
+// as image tags, schedules and dependencies on other deployments, is left out.
+func manifestRequest(d *state.Deployment) (CreateDeploymentRequest, error) {
+	req := CreateDeploymentRequest{
+		Name:             d.Name,
+		AppName:          d.AppName,
+		Version:          d.Version,
+		Cloud:            d.Cloud,
+		Region:           d.Region,
+		Port:             d.Port,
+		DeploymentType:   d.DeploymentType,
+		Tags:             d.Tags,
+		RequiresApproval: d.RequiresApproval,
+		Approvers:        d.Approvers,
+		MonthlyBudgetUSD: d.MonthlyBudgetUSD,
+		EgressAlertGB:    d.EgressAlertGB,
+	}
+
+	if d.Schedule != "" {
+		req.CronJob = &CronJobRequest{
+			Schedule:                d.Schedule,
+			Concurrency:             d.ConcurrencyPolicy,
+			StartingDeadlineSeconds: d.StartingDeadlineSeconds,
+		}
+	}
+
+	if d.StorageSize != "" {
+		req.Storage = &StorageRequest{
+			Size:         d.StorageSize,
+			StorageClass: d.StorageClass,
+			MountPath:    d.StorageMountPath,
+		}
+	}
+
+	if d.Hooks != "" {
+		var hooks deployer.HooksConfig
+		if err := json.Unmarshal([]byte(d.Hooks), &hooks); err != nil {
+			return req, fmt.Errorf("failed to parse hooks: %w", err)
+		}
+		req.Hooks = &hooks
+	}
+
+	if d.WorkloadIdentity {
+		req.WorkloadIdentity = &WorkloadIdentityRequest{
+			Enabled:                true,
+			GCPServiceAccountEmail: d.GCPServiceAccountEmail,
+		}
+	}
+
+	if d.CloudArmorEnabled {
+		req.WAF = &WAFRequest{
+			EnableCloudArmor: true,
+			SecurityPolicy:   d.CloudArmorPolicy,
+		}
+		if d.RateLimitRequestsPerMinute > 0 {
+			req.WAF.RateLimit = &RateLimitRequest{
+				RequestsPerMinutePerIP: d.RateLimitRequestsPerMinute,
+				EnforceOnURIPaths:      d.RateLimitPaths,
+			}
+		}
+	}
+
+	if d.NodeAffinity != "" {
+		var nodeAffinity deployer.NodeAffinity
+		if err := json.Unmarshal([]byte(d.NodeAffinity), &nodeAffinity); err != nil {
+			return req, fmt.Errorf("failed to parse node affinity: %w", err)
+		}
+		req.NodeAffinity = &nodeAffinity
+	}
+
+	if d.Sidecars != "" {
+		if err := json.Unmarshal([]byte(d.Sidecars), &req.Sidecars); err != nil {
+			return req, fmt.Errorf("failed to parse sidecars: %w", err)
+		}
+	}
+
+	if d.InitContainers != "" {
+		if err := json.Unmarshal([]byte(d.InitContainers), &req.InitContainers); err != nil {
+			return req, fmt.Errorf("failed to parse init containers: %w", err)
+		}
+	}
+
+	if d.PDBEnabled {
+		req.PDB = &PDBRequest{
+			Enabled:        true,
+			MinAvailable:   d.PDBMinAvailable,
+			MaxUnavailable: d.PDBMaxUnavailable,
+		}
+	}
+
+	if d.SmokeTests != "" {
+		if err := json.Unmarshal([]byte(d.SmokeTests), &req.SmokeTests); err != nil {
+			return req, fmt.Errorf("failed to parse smoke tests: %w", err)
+		}
+	}
+
+	return req, nil
+}
@@ -1,14 +1,35 @@
 package api
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/pkg/config"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/rs/zerolog/log"
 )
 
+// rateLimitWindow is the window API request limits are counted over
+const rateLimitWindow = time.Minute
+
+// actorFingerprintLength is how many hex characters of a bearer token's SHA-256 identify its
+// caller, enough that a listed identity cannot be turned back into a token that matches it
+const actorFingerprintLength = 16
+
+// actorContextKey is the request context key ActorMiddleware stores the caller's identity under
+type actorContextKey struct{}
+
 // RequestLogger is a middleware that logs HTTP requests
 func RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -37,7 +58,7 @@ func CORSMiddleware() func(http.Handler) http.Handler {
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
@@ -61,3 +82,222 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RateLimitMiddleware limits how many requests each client makes per minute, with separate
+// budgets for reads and mutations. Clients are identified by IP address, since other bearer
+// tokens are not verified and a made-up one would get a fresh budget. Requests bearing the
+// admin token share one larger admin budget instead. Requests are allowed through if Redis
+// is unavailable.
+func RateLimitMiddleware(store *queue.RedisQueue, limits config.RateLimitConfig, adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if store == nil || !limits.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			group, limit, client := "read", limits.ReadPerMinute, rateLimitClient(r)
+			if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
+				group, limit = "mutation", limits.MutationPerMinute
+			}
+			if isAdminRequest(r, adminToken) {
+				group, limit, client = "admin", limits.AdminPerMinute, "admin"
+			}
+
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := fmt.Sprintf("ratelimit:%s:%s", group, client)
+			count, resetIn, err := store.IncrementCounter(r.Context(), key, rateLimitWindow)
+			if err != nil {
+				log.Warn().Err(err).Str("key", key).Msg("Rate limit check failed, allowing request")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			remaining := max(int64(limit)-count, 0)
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(resetIn).Unix(), 10))
+
+			if count > int64(limit) {
+				retryAfter := int(resetIn.Round(time.Second).Seconds())
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				RespondWithJSON(w, http.StatusTooManyRequests, ErrorResponse{
+					Error:      http.StatusText(http.StatusTooManyRequests),
+					Message:    fmt.Sprintf("Rate limit of %d %s requests per minute exceeded", limit, group),
+					RetryAfter: retryAfter,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RealIPMiddleware replaces the RemoteAddr of requests sent through one of the trusted proxies
+// with the client address they forwarded in X-Forwarded-For or X-Real-IP. Forwarded headers
+// from any other sender are ignored, since a client can set them to anything. Proxies are
+// CIDRs or single addresses; invalid entries are logged and skipped.
+func RealIPMiddleware(trustedProxies []string) func(http.Handler) http.Handler {
+	var trusted []netip.Prefix
+	for _, proxy := range trustedProxies {
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			trusted = append(trusted, prefix.Masked())
+		} else if addr, err := netip.ParseAddr(proxy); err == nil {
+			trusted = append(trusted, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			log.Warn().Str("proxy", proxy).Msg("Ignoring invalid trusted proxy")
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := forwardedClientIP(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClientIP returns the client address a trusted proxy forwarded, or "" when the
+// request did not come from one. X-Forwarded-For is read from the right, skipping the
+// trusted proxies that appended to it, since entries left of those may be made up.
+func forwardedClientIP(r *http.Request, trusted []netip.Prefix) string {
+	isTrusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	remote, err := netip.ParseAddr(clientIP(r))
+	if err != nil || !isTrusted(remote) {
+		return ""
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+
+	client := ""
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap().String()
+		if !isTrusted(addr) {
+			return client
+		}
+	}
+	if client != "" {
+		return client
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.Unmap().String()
+	}
+	return ""
+}
+
+// AdminMiddleware restricts routes to requests bearing the configured admin token. Every
+// request is refused when no token is configured.
+func AdminMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				RespondWithError(w, http.StatusForbidden, "Admin endpoints are disabled - set server.admin_token to enable them")
+				return
+			}
+
+			if !isAdminRequest(r, token) {
+				RespondWithError(w, http.StatusUnauthorized, "Invalid admin token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAdminRequest reports whether a request bears the admin token
+func isAdminRequest(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	bearer, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
+// rateLimitClient identifies the client a request is counted against. Until bearer tokens
+// are authenticated this must not depend on them, so it is always the client IP.
+func rateLimitClient(r *http.Request) string {
+	return "ip:" + clientIP(r)
+}
+
+// ActorMiddleware identifies the caller of each request for audit logs and approvals.
+// Requests bearing the admin token are identified as admin, so nothing of the token is
+// recorded.
+func ActorMiddleware(adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), actorContextKey{}, identifyActor(r, adminToken))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requestActor returns the caller identity ActorMiddleware recorded for a request
+func requestActor(r *http.Request) string {
+	if actor, ok := r.Context().Value(actorContextKey{}).(string); ok {
+		return actor
+	}
+	return identifyActor(r, "")
+}
+
+// identifyActor identifies a caller as admin, by a fingerprint of its bearer token, e.g.
+// key:3f1c2b7e9a4d4c1e, or by IP address when no token is sent
+func identifyActor(r *http.Request, adminToken string) string {
+	if isAdminRequest(r, adminToken) {
+		return "admin"
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		sum := sha256.Sum256([]byte(token))
+		return "key:" + hex.EncodeToString(sum[:])[:actorFingerprintLength]
+	}
+	return "ip:" + clientIP(r)
+}
+
+// isTokenActor reports whether actor is a bearer token fingerprint as identifyActor records it
+func isTokenActor(actor string) bool {
+	fingerprint, ok := strings.CutPrefix(actor, "key:")
+	if !ok || len(fingerprint) != actorFingerprintLength {
+		return false
+	}
+	_, err := hex.DecodeString(fingerprint)
+	return err == nil && fingerprint == strings.ToLower(fingerprint)
+}
+
+// clientIP returns the address of the client that sent a request
+func clientIP(r *http.Request) string {
+	// RemoteAddr has already been replaced with the forwarded client address by RealIPMiddleware
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// mirrorStatsWindow is the window mirrored requests are counted over
+const mirrorStatsWindow = time.Hour
+
+// DisableMirror handles POST /api/v1/deployments/{id}/mirror/disable
+func (h *DeploymentHandler) DisableMirror(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	// Read from the primary, since clearing the mirror saves the whole deployment
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.MirrorFrom == nil {
+		RespondWithError(w, http.StatusConflict, "Deployment is not mirroring traffic")
+		return
+	}
+
+	sourceID := *deployment.MirrorFrom
+	deployment.MirrorFrom = nil
+	if err := h.repo.UpdateDeployment(r.Context(), deployment); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to update deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to disable mirror")
+		return
+	}
+
+	// The mirrored deployment's VirtualService no longer names the shadow once reapplied
+	if infra, err := h.repo.GetInfrastructure(r.Context(), sourceID); err == nil && infra.HelmReleaseName != "" {
+		if err := deployer.SyncServiceRoutes(r.Context(), h.repo, sourceID); err != nil {
+			log.Error().Err(err).Str("id", idStr).Msg("Failed to remove traffic mirror")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to remove traffic mirror: "+err.Error())
+			return
+		}
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("mirror_from", sourceID.String()).
+		Msg("Traffic mirror disabled")
+
+	RespondWithJSON(w, http.StatusOK, DeploymentToResponse(deployment))
+}
+
+// GetMirrorStats handles GET /api/v1/deployments/{id}/mirror/stats
+func (h *DeploymentHandler) GetMirrorStats(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	deployment, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.MirrorFrom == nil {
+		RespondWithError(w, http.StatusNotFound, "Deployment is not mirroring traffic")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), id)
+	if err != nil || infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Helm release")
+		return
+	}
+
+	source, err := h.repo.GetInfrastructure(r.Context(), *deployment.MirrorFrom)
+	if err != nil || source.HelmReleaseName == "" {
+		RespondWithError(w, http.StatusNotFound, "Mirrored deployment has no Helm release")
+		return
+	}
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to create Kubernetes client")
+		RespondWithError(w, http.StatusServiceUnavailable, "Cluster unavailable")
+		return
+	}
+
+	stats, err := kubeClient.GetMirrorStats(r.Context(), deployer.ServiceHost(source), deployer.ServiceHost(infra), mirrorStatsWindow)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get mirror stats")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get mirror stats")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, MirrorStatsResponse{
+		DeploymentID:     id,
+		MirrorFrom:       *deployment.MirrorFrom,
+		WindowSeconds:    int(stats.Window.Seconds()),
+		SourceRequests:   int64(stats.SourceRequests),
+		MirroredRequests: int64(stats.MirroredRequests),
+		MirrorErrors:     int64(stats.MirrorErrors),
+	})
+}
+
+// validateMirrorFrom checks that a shadow deployment mirrors an existing Helm deployment that
+// no other deployment mirrors already. Istio sends each request to a single mirror.
+func (h *DeploymentHandler) validateMirrorFrom(ctx context.Context, mirrorFrom *uuid.UUID, cloud string) error {
+	if mirrorFrom == nil {
+		return nil
+	}
+
+	if cloud == "cloudrun" {
+		return fmt.Errorf("mirror_from is not supported on cloudrun")
+	}
+
+	source, err := h.repo.GetDeployment(ctx, *mirrorFrom)
+	if err != nil {
+		return fmt.Errorf("mirror_from: deployment %s not found", *mirrorFrom)
+	}
+
+	if source.Cloud == "cloudrun" || source.DeployerType == deployer.DeployerTypeKustomize {
+		return fmt.Errorf("mirror_from must be a Helm deployment on GKE")
+	}
+
+	mirror, err := h.repo.GetMirroringDeployment(ctx, source.ID)
+	if err != nil {
+		return fmt.Errorf("failed to look up mirror_from")
+	}
+	if mirror != nil {
+		return fmt.Errorf("mirror_from: deployment %s is already mirrored to %s", source.ID, mirror.ID)
+	}
+
+	return nil
+}
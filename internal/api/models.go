@@ -1,8 +1,11 @@
 package api
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
 	"github.com/google/uuid"
 )
 
@@ -15,6 +18,129 @@ type CreateDeploymentRequest struct {
 	Region   string `json:"region"`
 	ImageTag string `json:"image_tag,omitempty"` // Optional: if provided, triggers immediate provisioning
 	Port     int    `json:"port,omitempty"`      // Optional: defaults to 8080
+
+	// Optional: provision at this time instead of immediately, requires image_tag
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+
+	// Optional managed services, e.g. {"type": "cloudsql", "config": {"tier": "db-f1-micro"}}
+	// or {"type": "memorystore", "config": {"tier": "BASIC", "memorySizeGb": 1}}
+	Addons []provisioner.AddonConfig `json:"addons,omitempty"`
+
+	// Optional: "service" (default), "statefulset", "cronjob" or "job"
+	DeploymentType string `json:"type,omitempty"`
+
+	// Required when type is "cronjob"
+	CronJob *CronJobRequest `json:"cronjob,omitempty"`
+
+	// Optional persistent storage when type is "statefulset"
+	Storage *StorageRequest `json:"storage,omitempty"`
+
+	// Optional hooks run as Kubernetes Jobs, e.g.
+	// {"pre_deploy": [{"image": "my-app:v1", "command": ["./migrate"], "timeout_seconds": 600}]}
+	Hooks *deployer.HooksConfig `json:"hooks,omitempty"`
+
+	// Optional workload identity binding for the app's pods (not supported on cloudrun)
+	WorkloadIdentity *WorkloadIdentityRequest `json:"workload_identity,omitempty"`
+
+	// Optional Cloud Armor protection of the app's load balancer (not supported on cloudrun)
+	WAF *WAFRequest `json:"waf,omitempty"`
+
+	// Optional node placement of the app's pods (not supported on cloudrun), e.g.
+	// {"required_labels": {"workload": "production"}, "tolerations": [{"key": "workload", "value": "production", "effect": "NoSchedule"}]}
+	NodeAffinity *deployer.NodeAffinity `json:"node_affinity,omitempty"`
+
+	// Optional containers run next to the app container in its pods (not supported on cloudrun), e.g.
+	// [{"name": "log-shipper", "image": "fluent/fluent-bit:3.0", "volume_mounts": [{"name": "logs", "mount_path": "/var/log/app"}]}]
+	Sidecars []deployer.SidecarConfig `json:"sidecars,omitempty"`
+
+	// Optional containers run to completion before the app starts in each pod (not supported on cloudrun), e.g.
+	// [{"command": ["python", "manage.py", "migrate"], "timeout_seconds": 600}]
+	InitContainers []deployer.InitContainerConfig `json:"init_containers,omitempty"`
+
+	// Optional PodDisruptionBudget limiting how many pods node drains evict at once (not supported on cloudrun)
+	PDB *PDBRequest `json:"pdb,omitempty"`
+
+	// Optional HTTP checks run against the external URL after each deploy, e.g.
+	// [{"path": "/healthz", "expected_status": 200, "expected_body_contains": "ok"}]
+	SmokeTests []deployer.SmokeTest `json:"smoke_tests,omitempty"`
+
+	// Optional: deployments that must be EXPOSED or HEALTHY before this one is provisioned
+	Dependencies []uuid.UUID `json:"dependencies,omitempty"`
+
+	// Optional: deployment on the same cluster whose in-mesh traffic is mirrored to this one
+	// once it is deployed (requires Istio; not supported on cloudrun)
+	MirrorFrom *uuid.UUID `json:"mirror_from,omitempty"`
+
+	// Optional metadata applied as labels to the app's resources, e.g. {"env": "production", "team": "backend"}
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Optional: hold each rollout until one of Approvers, or an admin, approves it. Approvers
+	// are client identities as recorded in audit logs, e.g. "key:abcd1234".
+	RequiresApproval bool     `json:"requires_approval,omitempty"`
+	Approvers        []string `json:"approvers,omitempty"`
+
+	// Optional: monthly infrastructure budget in USD; alerts are sent as projected spend passes 80% and 100% of it
+	MonthlyBudgetUSD float64 `json:"monthly_budget_usd,omitempty"`
+
+	// Optional: daily internet egress in GB above which an egress alert is sent; defaults to billing.egress_alert_gb
+	EgressAlertGB float64 `json:"egress_alert_gb,omitempty"`
+}
+
+// WorkloadIdentityRequest lets application pods act as a GCP service account
+type WorkloadIdentityRequest struct {
+	Enabled                bool   `json:"enabled"`
+	GCPServiceAccountEmail string `json:"gcp_service_account_email,omitempty"` // Optional: a service account is created when empty
+}
+
+// WAFRequest puts the app's load balancer behind a Cloud Armor security policy
+type WAFRequest struct {
+	EnableCloudArmor bool              `json:"enable_cloud_armor"`
+	SecurityPolicy   string            `json:"security_policy,omitempty"` // Optional: name of an existing policy, defaults to "default"
+	RateLimit        *RateLimitRequest `json:"rate_limit,omitempty"`      // Optional: throttle each client IP
+}
+
+// RateLimitRequest throttles each client IP of a Cloud Armor protected app
+type RateLimitRequest struct {
+	RequestsPerMinutePerIP int      `json:"requests_per_minute_per_ip"`     // Zero removes the limit when updating
+	EnforceOnURIPaths      []string `json:"enforce_on_uri_paths,omitempty"` // Optional: path prefixes throttled, every path when empty
+}
+
+// PDBRequest keeps app pods available through voluntary disruptions such as node upgrades
+type PDBRequest struct {
+	Enabled        bool `json:"enabled"`
+	MinAvailable   int  `json:"min_available,omitempty"`   // Optional: pods kept running, defaults to 1
+	MaxUnavailable int  `json:"max_unavailable,omitempty"` // Optional: pods evicted at once, instead of min_available
+}
+
+// StorageRequest holds persistent volume options for statefulset deployments
+type StorageRequest struct {
+	Size         string `json:"size"`                    // Required: e.g. 10Gi
+	StorageClass string `json:"storage_class,omitempty"` // Optional: cluster default when empty
+	MountPath    string `json:"mount_path,omitempty"`    // Optional: defaults to /data
+}
+
+// CronJobRequest holds scheduling options for cronjob deployments
+type CronJobRequest struct {
+	Schedule                string `json:"schedule"`                            // Required: cron expression
+	Concurrency             string `json:"concurrency,omitempty"`               // Optional: Allow (default), Forbid, Replace
+	StartingDeadlineSeconds int    `json:"starting_deadline_seconds,omitempty"` // Optional: 0 means no deadline
+}
+
+// CloneDeploymentRequest overrides fields of a cloned deployment; all are optional
+type CloneDeploymentRequest struct {
+	Name   string `json:"name,omitempty"`   // Optional: defaults to <source name>-clone
+	Cloud  string `json:"cloud,omitempty"`  // Optional: defaults to the source's cloud
+	Region string `json:"region,omitempty"` // Optional: defaults to the source's region
+}
+
+// UpdateTagsRequest merges tags into a deployment's existing ones
+type UpdateTagsRequest struct {
+	Tags map[string]*string `json:"tags"` // Required: a null value removes that tag
+}
+
+// UpdateBudgetRequest sets a deployment's monthly budget
+type UpdateBudgetRequest struct {
+	MonthlyBudgetUSD *float64 `json:"monthly_budget_usd"` // Required: 0 removes the budget
 }
 
 // UpdateDeploymentStatusRequest represents a request to update deployment status
@@ -30,14 +156,47 @@ type DeploymentResponse struct {
 	Version     string     `json:"version"`
 	Status      string     `json:"status"`
 	Cloud       string     `json:"cloud"`
-	Region      string     `json:"region"`
+	Region       string     `json:"region"`
+	DeployerType string     `json:"deployer_type,omitempty"`
+	Type         string     `json:"type,omitempty"`
+	Schedule     string     `json:"schedule,omitempty"`
+	Paused       bool       `json:"paused"`
+	PausedAt     *time.Time `json:"paused_at,omitempty"`
+	ReconciliationMode bool `json:"reconciliation_mode"`
+	ScheduledAt  *time.Time `json:"scheduled_at,omitempty"`
+	ClonedFromID *uuid.UUID `json:"cloned_from_id,omitempty"`
+	MirrorFrom   *uuid.UUID `json:"mirror_from,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	RequiresApproval bool     `json:"requires_approval"`
+	Approvers        []string `json:"approvers,omitempty"`
+	UserID           string   `json:"user_id,omitempty"` // Current owner
+	MonthlyBudgetUSD float64  `json:"monthly_budget_usd,omitempty"`
+	EgressAlertGB    float64  `json:"egress_alert_gb,omitempty"`
+	VPALastRecommendation *VPARecommendationResponse `json:"vpa_last_recommendation,omitempty"`
 	ExternalIP  string     `json:"external_ip,omitempty"`
+	SmokeTestResult json.RawMessage `json:"smoke_test_result,omitempty"` // Outcome of the last smoke test run
+	BlockedBy   []string   `json:"blocked_by,omitempty"` // Dependencies that are not live yet
 	ExternalURL string     `json:"external_url,omitempty"`
+	Error       string     `json:"error,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeployedAt  *time.Time `json:"deployed_at,omitempty"`
 }
 
+// WAFStatusResponse represents the Cloud Armor protection of a deployment's load balancer
+type WAFStatusResponse struct {
+	DeploymentID   uuid.UUID `json:"deployment_id"`
+	Enabled        bool      `json:"enabled"`
+	Status         string    `json:"status"`                    // DISABLED, PENDING, ATTACHED or FAILED
+	SecurityPolicy string    `json:"security_policy,omitempty"` // Policy requested by the deployment
+	AttachedPolicy string    `json:"attached_policy,omitempty"` // Policy attached to the backend service
+	BackendService string    `json:"backend_service,omitempty"`
+	Error          string    `json:"error,omitempty"`
+
+	RateLimit     *RateLimitRequest `json:"rate_limit,omitempty"`
+	RateLimitRule string            `json:"rate_limit_rule,omitempty"` // Rule added to the attached policy
+}
+
 // InfrastructureResponse represents infrastructure in API responses
 type InfrastructureResponse struct {
 	ID           uuid.UUID `json:"id"`
@@ -47,8 +206,536 @@ type InfrastructureResponse struct {
 	ServiceName  string    `json:"service_name,omitempty"`
 	Status       string    `json:"status"`
 	Config       string    `json:"config,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+
+	DriftDetected    bool       `json:"drift_detected"`
+	DriftDetails     *string    `json:"drift_details,omitempty"`
+	LastReconciledAt *time.Time `json:"last_reconciled_at,omitempty"`
+	LastReconcileStatus string  `json:"last_reconcile_status,omitempty"`
+
+	ImportedExternally bool `json:"imported_externally,omitempty"`
+
+	VPCServicePerimeter string `json:"vpc_service_perimeter,omitempty"`
+
+	// Projected from the billing export by the daily cost job, zero until it has run
+	EstimatedMonthlyCostUSD float64 `json:"estimated_monthly_cost_usd,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ImportInfrastructureRequest represents a request to deploy into an existing GKE cluster
+type ImportInfrastructureRequest struct {
+	ClusterName     string `json:"cluster_name"`        // Required
+	ClusterEndpoint string `json:"cluster_endpoint"`    // Required: control plane address
+	ClusterCACert   string `json:"cluster_ca_cert"`     // Required: base64 encoded CA certificate
+	GCPProject      string `json:"gcp_project"`         // Required
+	Region          string `json:"region"`              // Required: cluster region or zone
+	Namespace       string `json:"namespace,omitempty"` // Optional: defaults to deployer-<deployment ID prefix>
+}
+
+// UpdateNodePoolRequest represents a request to change a deployment's node pool in place
+type UpdateNodePoolRequest struct {
+	NodeCount   int    `json:"node_count,omitempty"`   // Optional: keeps the current count when omitted
+	MachineType string `json:"machine_type,omitempty"` // Optional: keeps the current machine type when omitted
+}
+
+// MigrateBackendRequest represents a request to move an infrastructure's Pulumi stack to another state backend
+type MigrateBackendRequest struct {
+	FromBackend string `json:"from_backend,omitempty"` // Optional: defaults to the stack's current backend
+	ToBackend   string `json:"to_backend"`             // Required: e.g. gs://bucket or https://api.pulumi.com
+}
+
+// CloudResourceResponse represents a live cloud resource in API responses
+type CloudResourceResponse struct {
+	Type       string                 `json:"type"`
+	Name       string                 `json:"name"`
+	URN        string                 `json:"urn"`
+	Status     string                 `json:"status"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// InfrastructureResourcesResponse represents the live resources of a deployment's infrastructure
+type InfrastructureResourcesResponse struct {
+	DeploymentID uuid.UUID               `json:"deployment_id"`
+	StackName    string                  `json:"stack_name"`
+	Resources    []CloudResourceResponse `json:"resources"`
+	Cached       bool                    `json:"cached"`
+}
+
+// ReleaseRevisionResponse represents a Helm release revision in API responses
+type ReleaseRevisionResponse struct {
+	Revision    int       `json:"revision"`
+	Updated     time.Time `json:"updated"`
+	Status      string    `json:"status"`
+	Chart       string    `json:"chart"`
+	AppVersion  string    `json:"app_version"`
+	Description string    `json:"description"`
+}
+
+// VolumeResponse represents a persistent volume claim in API responses
+type VolumeResponse struct {
+	Name         string   `json:"name"`
+	StorageClass string   `json:"storage_class,omitempty"`
+	Requested    string   `json:"requested"`
+	Capacity     string   `json:"capacity,omitempty"`
+	Status       string   `json:"status"`
+	AccessModes  []string `json:"access_modes,omitempty"`
+}
+
+// VolumesResponse lists the persistent volume claims of a deployment
+type VolumesResponse struct {
+	DeploymentID uuid.UUID        `json:"deployment_id"`
+	Namespace    string           `json:"namespace"`
+	Volumes      []VolumeResponse `json:"volumes"`
+}
+
+// PodResponse represents a pod of a deployment
+type PodResponse struct {
+	Name              string                    `json:"name"`
+	Status            string                    `json:"status"`
+	Node              string                    `json:"node,omitempty"`
+	CreatedAt         time.Time                 `json:"created_at"`
+	Age               string                    `json:"age"`
+	Restarts          int32                     `json:"restarts"`
+	ContainerStatuses []ContainerStatusResponse `json:"container_statuses"`
+}
+
+// ContainerStatusResponse represents the state of one container in a pod
+type ContainerStatusResponse struct {
+	Name     string `json:"name"`
+	Image    string `json:"image"`
+	Ready    bool   `json:"ready"`
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty"`
+	Restarts int32  `json:"restarts"`
+}
+
+// PodsResponse lists the pods of a deployment
+type PodsResponse struct {
+	DeploymentID uuid.UUID     `json:"deployment_id"`
+	Namespace    string        `json:"namespace"`
+	Pods         []PodResponse `json:"pods"`
+}
+
+// PodLogsResponse holds the tail of a pod container's logs
+type PodLogsResponse struct {
+	DeploymentID uuid.UUID `json:"deployment_id"`
+	Pod          string    `json:"pod"`
+	Container    string    `json:"container,omitempty"`
+	TailLines    int64     `json:"tail_lines"`
+	Logs         string    `json:"logs"`
+}
+
+// DeploymentMetricsResponse holds the current resource usage of a deployment's pods
+type DeploymentMetricsResponse struct {
+	DeploymentID uuid.UUID            `json:"deployment_id"`
+	Pods         []PodMetricsResponse `json:"pods"`
+	TotalCPU     int64                `json:"total_cpu"`    // millicores
+	TotalMemory  int64                `json:"total_memory"` // MiB
+	Cached       bool                 `json:"cached"`
+}
+
+// PodMetricsResponse represents the current resource usage of one pod
+type PodMetricsResponse struct {
+	PodName       string    `json:"pod_name"`
+	CPUUsageMilli int64     `json:"cpu_usage_milli"`
+	MemoryUsageMi int64     `json:"memory_usage_mi"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// VPARecommendationResponse holds the container resources recommended by a deployment's
+// Vertical Pod Autoscaler
+type VPARecommendationResponse struct {
+	DeploymentID             uuid.UUID `json:"deployment_id"`
+	Container                string    `json:"container"`
+	RecommendedCPURequest    string    `json:"recommended_cpu_request"`    // e.g. "250m"
+	RecommendedMemoryRequest string    `json:"recommended_memory_request"` // e.g. "256Mi"
+	RecommendedCPULimit      string    `json:"recommended_cpu_limit,omitempty"`
+	RecommendedMemoryLimit   string    `json:"recommended_memory_limit,omitempty"`
+	RecordedAt               time.Time `json:"recorded_at"`
+}
+
+// ResourceRecommendationResponse holds the container resources and replica count suggested
+// for a new deployment
+type ResourceRecommendationResponse struct {
+	CPURequest      string `json:"cpu_request"`    // e.g. "250m"
+	CPULimit        string `json:"cpu_limit"`      // e.g. "1"
+	MemoryRequest   string `json:"memory_request"` // e.g. "256Mi"
+	MemoryLimit     string `json:"memory_limit"`
+	Replicas        int    `json:"replicas"`
+	ConfidenceLevel string `json:"confidence_level"`               // heuristic or data-driven
+	BasedOn         int    `json:"based_on_deployments,omitempty"` // Deployments whose VPA recommendations were used
+}
+
+// PodExecRequest is the first message of a pod exec WebSocket session
+type PodExecRequest struct {
+	Command   []string `json:"command"`
+	Container string   `json:"container,omitempty"` // Default: the pod's default container
+}
+
+// KubeEventResponse represents a Kubernetes event in API responses
+type KubeEventResponse struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Object   string    `json:"object"`
+	Count    int32     `json:"count,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// AutoscalerEventsResponse lists recent cluster autoscaler events of a deployment's cluster
+type AutoscalerEventsResponse struct {
+	DeploymentID uuid.UUID           `json:"deployment_id"`
+	Events       []KubeEventResponse `json:"events"`
+}
+
+// DeploymentLogResponse represents a deployment log entry in API responses
+type DeploymentLogResponse struct {
+	Phase     string    `json:"phase"`
+	Level     string    `json:"level"`
+	Source    string    `json:"source,omitempty"`
+	Message   string    `json:"message"`
+	Highlight string    `json:"highlight,omitempty"` // Excerpt matching the search, set when q is given
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeploymentLogsResponse lists the log entries of a deployment
+type DeploymentLogsResponse struct {
+	DeploymentID uuid.UUID               `json:"deployment_id"`
+	Logs         []DeploymentLogResponse `json:"logs"`
+}
+
+// PipelineStageResponse represents a stage of a deployment pipeline
+type PipelineStageResponse struct {
+	Type        string     `json:"type"`   // BUILD, PROVISION, DEPLOY
+	Status      string     `json:"status"` // PENDING, QUEUED, RUNNING, COMPLETED, FAILED
+	DependsOn   []string   `json:"depends_on"`
+	JobID       string     `json:"job_id,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// PipelineResponse represents the latest pipeline a deployment ran through
+type PipelineResponse struct {
+	ID           uuid.UUID               `json:"id"`
+	DeploymentID uuid.UUID               `json:"deployment_id"`
+	CurrentStage string                  `json:"current_stage"`
+	Stages       []PipelineStageResponse `json:"stages"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// DeploymentEventResponse represents an entry of a deployment's event feed
+type DeploymentEventResponse struct {
+	Type      string                 `json:"type"`
+	Message   string                 `json:"message"`
+	Timestamp time.Time              `json:"timestamp"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// DeploymentEventsResponse represents a page of a deployment's event feed
+type DeploymentEventsResponse struct {
+	DeploymentID uuid.UUID                 `json:"deployment_id"`
+	Events       []DeploymentEventResponse `json:"events"`
+	Limit        int                       `json:"limit"`
+	Offset       int                       `json:"offset"`
+}
+
+// DependencyTreeResponse lists the deployments a deployment depends on, recursively
+type DependencyTreeResponse struct {
+	DeploymentID uuid.UUID                `json:"deployment_id"`
+	Dependencies []DependencyNodeResponse `json:"dependencies"`
+}
+
+// DependencyNodeResponse represents one dependency and the deployments it depends on in turn
+type DependencyNodeResponse struct {
+	DeploymentID uuid.UUID                `json:"deployment_id"`
+	Name         string                   `json:"name,omitempty"`
+	Status       string                   `json:"status"` // DELETED when the dependency no longer exists
+	Ready        bool                     `json:"ready"`
+	Dependencies []DependencyNodeResponse `json:"dependencies,omitempty"`
+}
+
+// CreateServiceRouteRequest represents a request to route a deployment's in-mesh traffic
+// to another deployment on the same cluster
+type CreateServiceRouteRequest struct {
+	TargetDeploymentID uuid.UUID `json:"target_deployment_id"`
+	PrefixRewrite      string    `json:"prefix_rewrite"` // Default: /
+	WeightPercent      int       `json:"weight_percent"` // Default: 100
+}
+
+// ServiceRouteResponse represents a service route between two deployments
+type ServiceRouteResponse struct {
+	ID                 uuid.UUID `json:"id"`
+	SourceDeploymentID uuid.UUID `json:"source_deployment_id"`
+	TargetDeploymentID uuid.UUID `json:"target_deployment_id"`
+	PrefixRewrite      string    `json:"prefix_rewrite"`
+	WeightPercent      int       `json:"weight_percent"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// MirrorStatsResponse counts the requests mirrored from a deployment to its shadow
+type MirrorStatsResponse struct {
+	DeploymentID     uuid.UUID `json:"deployment_id"`
+	MirrorFrom       uuid.UUID `json:"mirror_from"`
+	WindowSeconds    int       `json:"window_seconds"`
+	SourceRequests   int64     `json:"source_requests"`
+	MirroredRequests int64     `json:"mirrored_requests"`
+	MirrorErrors     int64     `json:"mirror_errors"` // Mirrored requests answered with a 5xx
+}
+
+// StartABTestRequest represents a request to route a baseline deployment's requests carrying
+// a header to the variant the test is started on
+type StartABTestRequest struct {
+	BaselineDeploymentID uuid.UUID `json:"baseline_deployment_id"`
+	HeaderName           string    `json:"header_name"`
+	HeaderValue          string    `json:"header_value"`
+	RoutingWeight        int       `json:"routing_weight"` // Default: 100
+}
+
+// ABTestResponse represents an A/B test between a baseline and a variant deployment
+type ABTestResponse struct {
+	ID                   uuid.UUID  `json:"id"`
+	BaselineDeploymentID uuid.UUID  `json:"baseline_deployment_id"`
+	VariantDeploymentID  uuid.UUID  `json:"variant_deployment_id"`
+	HeaderName           string     `json:"header_name"`
+	HeaderValue          string     `json:"header_value"`
+	RoutingWeight        int        `json:"routing_weight"`
+	Status               string     `json:"status"`
+	WinnerDeploymentID   *uuid.UUID `json:"winner_deployment_id,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	ConcludedAt          *time.Time `json:"concluded_at,omitempty"`
+}
+
+// ABTestMetricsResponse counts the requests each side of an A/B test served since it started,
+// or until it concluded
+type ABTestMetricsResponse struct {
+	ABTestID             uuid.UUID `json:"ab_test_id"`
+	BaselineDeploymentID uuid.UUID `json:"baseline_deployment_id"`
+	VariantDeploymentID  uuid.UUID `json:"variant_deployment_id"`
+	Status               string    `json:"status"`
+	WindowSeconds        int       `json:"window_seconds"`
+	BaselineRequests     int64     `json:"baseline_requests"`
+	BaselineErrors       int64     `json:"baseline_errors"` // Requests answered with a 5xx
+	VariantRequests      int64     `json:"variant_requests"`
+	VariantErrors        int64     `json:"variant_errors"`
+}
+
+// ServiceRoutesResponse lists the service routes of a deployment
+type ServiceRoutesResponse struct {
+	DeploymentID uuid.UUID              `json:"deployment_id"`
+	Routes       []ServiceRouteResponse `json:"routes"`
+}
+
+// SetEnvVarRequest represents a request to set a deployment environment variable
+type SetEnvVarRequest struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	IsSecret bool   `json:"is_secret"`
+}
+
+// EnvVarResponse represents a deployment environment variable; secret values are masked
+type EnvVarResponse struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	IsSecret  bool      `json:"is_secret"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EnvVarsResponse lists the environment variables of a deployment
+type EnvVarsResponse struct {
+	DeploymentID uuid.UUID        `json:"deployment_id"`
+	EnvVars      []EnvVarResponse `json:"env_vars"`
+}
+
+// CreateGitHookRequest represents a request to build and deploy on pushes to a repository
+type CreateGitHookRequest struct {
+	Provider string `json:"provider"`         // Required: github, gitlab or bitbucket
+	RepoURL  string `json:"repo_url"`         // Required: clone URL pushes are matched against and built from
+	Branch   string `json:"branch,omitempty"`   // Optional: defaults to main
+	SubPath  string `json:"sub_path,omitempty"` // Optional: service directory to build in a monorepo, e.g. services/auth
+	Secret   string `json:"secret"`             // Required: the provider's hook secret token
+}
+
+// GitHookResponse represents a git hook in API responses; the secret is never returned
+type GitHookResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	DeploymentID    uuid.UUID  `json:"deployment_id"`
+	Provider        string     `json:"provider"`
+	RepoURL         string     `json:"repo_url"`
+	Branch          string     `json:"branch"`
+	SubPath         string     `json:"sub_path,omitempty"`
+	WebhookURL      string     `json:"webhook_url"` // Path to configure in the provider
+	LastCommitSHA   string     `json:"last_commit_sha,omitempty"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// SetConfigMapRequest represents a request to set a deployment ConfigMap
+type SetConfigMapRequest struct {
+	Name      string            `json:"name"`
+	MountPath string            `json:"mount_path,omitempty"` // Default: /etc/config/<name>
+	Data      map[string]string `json:"data"`                 // File name to content
+}
+
+// ConfigMapResponse represents a deployment ConfigMap
+type ConfigMapResponse struct {
+	Name               string            `json:"name"`
+	MountPath          string            `json:"mount_path"`
+	Data               map[string]string `json:"data"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	RestartedWorkloads []string          `json:"restarted_workloads,omitempty"`
+}
+
+// ConfigMapsResponse lists the ConfigMaps of a deployment
+type ConfigMapsResponse struct {
+	DeploymentID uuid.UUID           `json:"deployment_id"`
+	ConfigMaps   []ConfigMapResponse `json:"configmaps"`
+}
+
+// UpdateHPARequest represents a request to change a deployment's Horizontal Pod Autoscaler.
+// Set at least one target; a zero target leaves that metric out.
+type UpdateHPARequest struct {
+	MinReplicas         int `json:"min_replicas"`
+	MaxReplicas         int `json:"max_replicas"`
+	TargetCPUPercent    int `json:"target_cpu_percent,omitempty"`
+	TargetMemoryPercent int `json:"target_memory_percent,omitempty"`
+}
+
+// HPAConfigResponse represents the Horizontal Pod Autoscaler settings of a deployment
+type HPAConfigResponse struct {
+	DeploymentID        uuid.UUID `json:"deployment_id"`
+	MinReplicas         int       `json:"min_replicas"`
+	MaxReplicas         int       `json:"max_replicas"`
+	TargetCPUPercent    int       `json:"target_cpu_percent,omitempty"`
+	TargetMemoryPercent int       `json:"target_memory_percent,omitempty"`
+}
+
+// HPAStatusResponse represents the live state of a deployment's Horizontal Pod Autoscaler
+type HPAStatusResponse struct {
+	DeploymentID    uuid.UUID              `json:"deployment_id"`
+	Name            string                 `json:"name"`
+	MinReplicas     int32                  `json:"min_replicas"`
+	MaxReplicas     int32                  `json:"max_replicas"`
+	CurrentReplicas int32                  `json:"current_replicas"`
+	DesiredReplicas int32                  `json:"desired_replicas"`
+	LastScaleTime   *time.Time             `json:"last_scale_time,omitempty"`
+	Conditions      []HPAConditionResponse `json:"conditions"`
+}
+
+// HPAConditionResponse represents one Horizontal Pod Autoscaler condition
+type HPAConditionResponse struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"last_transition_time"`
+}
+
+// HelmHistoryResponse represents the revision history of a deployment's Helm release
+type HelmHistoryResponse struct {
+	DeploymentID uuid.UUID                 `json:"deployment_id"`
+	ReleaseName  string                    `json:"release_name"`
+	Namespace    string                    `json:"namespace"`
+	Revisions    []ReleaseRevisionResponse `json:"revisions"`
+}
+
+// RollbackOptionResponse represents a Helm revision a deployment can be rolled back to
+type RollbackOptionResponse struct {
+	Revision   int       `json:"revision"`
+	Status     string    `json:"status"`
+	ImageTag   string    `json:"image_tag,omitempty"`
+	DeployedAt time.Time `json:"deployed_at"`
+}
+
+// RollbackOptionsResponse lists the rollback targets of a deployment, newest first
+type RollbackOptionsResponse struct {
+	DeploymentID uuid.UUID                `json:"deployment_id"`
+	ReleaseName  string                   `json:"release_name"`
+	Options      []RollbackOptionResponse `json:"options"`
+}
+
+// ResourcePolicyRequest represents a request to set the per-deployment resource policy.
+// Empty or zero fields are unlimited.
+type ResourcePolicyRequest struct {
+	MaxCPULimit    string `json:"max_cpu_limit,omitempty"`    // e.g. "2"
+	MaxMemoryLimit string `json:"max_memory_limit,omitempty"` // e.g. "4Gi"
+	MaxReplicas    int    `json:"max_replicas,omitempty"`
+}
+
+// PolicyFileResponse represents an OPA policy file admitting deployment requests
+type PolicyFileResponse struct {
+	Name       string    `json:"name"`
+	SizeBytes  int64     `json:"size_bytes"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// PoliciesResponse lists the loaded OPA policy files
+type PoliciesResponse struct {
+	Enabled   bool                 `json:"enabled"`
+	PolicyDir string               `json:"policy_dir,omitempty"`
+	Policies  []PolicyFileResponse `json:"policies"`
+}
+
+// ResourcePolicyResponse represents the resource policy enforced on deploys
+type ResourcePolicyResponse struct {
+	MaxCPULimit    string     `json:"max_cpu_limit,omitempty"`
+	MaxMemoryLimit string     `json:"max_memory_limit,omitempty"`
+	MaxReplicas    int        `json:"max_replicas,omitempty"`
+	Source         string     `json:"source"` // config or admin
+	UpdatedBy      string     `json:"updated_by,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+}
+
+// CleanupResponse reports what a maintenance cleanup run deleted
+type CleanupResponse struct {
+	LogsDeleted   int64 `json:"logs_deleted"`
+	RetentionDays int   `json:"retention_days"` // -1 when logs are kept forever
+}
+
+// AdoptReleaseRequest represents a request to bring an existing Helm release under deployer management
+type AdoptReleaseRequest struct {
+	Namespace   string `json:"namespace"`    // Required
+	ReleaseName string `json:"release_name"` // Required
+}
+
+// AdoptReleaseResponse describes a release after it has been adopted
+type AdoptReleaseResponse struct {
+	DeploymentID uuid.UUID `json:"deployment_id"`
+	ReleaseName  string    `json:"release_name"`
+	Namespace    string    `json:"namespace"`
+	Chart        string    `json:"chart"`
+	Revision     int       `json:"revision"`
+	Status       string    `json:"status"`
+	ExternalURL  string    `json:"external_url,omitempty"`
+}
+
+// AddonVersionResponse compares a platform addon's chart version on the cluster with the one
+// an upgrade would move to
+type AddonVersionResponse struct {
+	Name             string `json:"name"`
+	Chart            string `json:"chart"`
+	Namespace        string `json:"namespace"`
+	Installed        bool   `json:"installed"`
+	CurrentVersion   string `json:"current_version,omitempty"`
+	AvailableVersion string `json:"available_version"`
+	Pinned           bool   `json:"pinned"` // available_version is pinned in the deployer config
+	UpgradeAvailable bool   `json:"upgrade_available"`
+}
+
+// AddonVersionsResponse lists the platform addons of a deployment's cluster
+type AddonVersionsResponse struct {
+	DeploymentID uuid.UUID              `json:"deployment_id"`
+	Addons       []AddonVersionResponse `json:"addons"`
+}
+
+// UpgradeAddonResponse describes a platform addon after it has been upgraded
+type UpgradeAddonResponse struct {
+	DeploymentID    uuid.UUID `json:"deployment_id"`
+	Addon           string    `json:"addon"`
+	PreviousVersion string    `json:"previous_version"`
+	Version         string    `json:"version"`
 }
 
 // BuildResponse represents a build in API responses
@@ -58,16 +745,100 @@ type BuildResponse struct {
 	ImageTag     string     `json:"image_tag"`
 	Status       string     `json:"status"`
 	BuildLog     string     `json:"build_log,omitempty"`
+	CacheKey     string     `json:"cache_key,omitempty"`
+	SBOMPath     string     `json:"sbom_path,omitempty"`
+	SBOMFormat   string     `json:"sbom_format,omitempty"`
+	SignatureRef string     `json:"signature_ref,omitempty"`
+	ScanStatus   string     `json:"scan_status,omitempty"`
 	StartedAt    time.Time  `json:"started_at"`
 	CompletedAt  *time.Time `json:"completed_at,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
+// VulnerabilityScanResponse represents a build image's vulnerability scan in API responses
+type VulnerabilityScanResponse struct {
+	ID              uuid.UUID       `json:"id"`
+	BuildID         uuid.UUID       `json:"build_id"`
+	DeploymentID    uuid.UUID       `json:"deployment_id"`
+	ImageTag        string          `json:"image_tag"`
+	ScanTime        time.Time       `json:"scan_time"`
+	Passed          bool            `json:"passed"`
+	CriticalCount   int             `json:"critical_count"`
+	HighCount       int             `json:"high_count"`
+	MediumCount     int             `json:"medium_count"`
+	LowCount        int             `json:"low_count"`
+	SuppressedCount int             `json:"suppressed_count"` // Findings not counted because their risk was accepted
+	SuppressedVulns []string        `json:"suppressed_vulns"`
+	RawResult       json.RawMessage `json:"raw_result,omitempty"`
+}
+
+// CreateCVESuppressionRequest represents a request to accept the risk of a vulnerability
+type CreateCVESuppressionRequest struct {
+	CVEID        string     `json:"cve_id"`                  // Required: ID as reported by the scanner, e.g. CVE-2024-12345
+	Reason       string     `json:"reason"`                  // Required
+	DeploymentID *uuid.UUID `json:"deployment_id,omitempty"` // Optional: Applies to all deployments when omitted
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`    // Optional: Never expires when omitted
+}
+
+// CreateRegistryCredentialRequest represents a request to store a private registry login
+type CreateRegistryCredentialRequest struct {
+	RegistryURL string     `json:"registry_url"`         // Required: registry host, e.g. registry.example.com
+	Username    string     `json:"username"`             // Required: _json_key for a GCP service account key
+	Password    string     `json:"password"`             // Required: password, token or service account key file
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // Optional: Never expires when omitted
+}
+
+// RegistryCredentialResponse represents a stored registry login in API responses. The secret
+// is never returned.
+type RegistryCredentialResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	RegistryURL string     `json:"registry_url"`
+	Username    string     `json:"username"`
+	UserID      string     `json:"user_id"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RotatedAt   *time.Time `json:"rotated_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CVESuppressionResponse represents an accepted vulnerability risk in API responses
+type CVESuppressionResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	CVEID        string     `json:"cve_id"`
+	UserID       string     `json:"user_id"`
+	Reason       string     `json:"reason"`
+	DeploymentID *uuid.UUID `json:"deployment_id,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	Active       bool       `json:"active"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// HelmChartResponse represents a chart version published to the chart registry
+type HelmChartResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	RegistryURL string    `json:"registry_url"`
+	Digest      string    `json:"digest,omitempty"`
+	PublishedBy string    `json:"published_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	Error      string `json:"error"`
+	Message    string `json:"message,omitempty"`
+	RetryAfter int    `json:"retry_after,omitempty"` // Seconds until a rate limited request may be retried
+
+	Violations []string `json:"violations,omitempty"` // Deny messages of the policies that rejected a request
+
+	ValidationErrors []ValidationError `json:"validation_errors,omitempty"` // Fields of a request body its schema rejected
+}
+
+// ValidationError is a request body field that failed schema validation
+type ValidationError struct {
+	Field   string `json:"field"` // Dotted path, e.g. cronjob.schedule or addons.0.type
+	Message string `json:"message"`
 }
 
 // SuccessResponse represents a generic success response
@@ -78,9 +849,16 @@ type SuccessResponse struct {
 
 // HealthResponse represents the health check response
 type HealthResponse struct {
-	Status   string `json:"status"`
-	Database string `json:"database"`
-	Version  string `json:"version"`
+	Status          string              `json:"status"`
+	Database        string              `json:"database"`
+	Version         string              `json:"version"`
+	DeploymentCache *CacheStatsResponse `json:"deployment_cache,omitempty"` // Absent when Redis is unavailable
+}
+
+// CacheStatsResponse counts deployment lookups served from and missing the cache since startup
+type CacheStatsResponse struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
 }
 
 // ListDeploymentsResponse represents a paginated list of deployments
@@ -96,25 +874,268 @@ type StartDeploymentRequest struct {
 	ImageTag string `json:"image_tag"` // Required: container image to deploy
 	Port     int    `json:"port"`      // Optional: defaults to 8080
 	Replicas int    `json:"replicas"`  // Optional: defaults to 2
+
+	// Optional container limits as Kubernetes quantities, bounded by the resource policy
+	CPULimit    string `json:"cpu_limit,omitempty"`    // e.g. "500m"
+	MemoryLimit string `json:"memory_limit,omitempty"` // e.g. "512Mi"
+
+	// Optional managed services to provision with the cluster
+	Addons []provisioner.AddonConfig `json:"addons,omitempty"`
+
+	// Optional: "helm" (default) or "kustomize". Kustomize renders manifests from RepoURL.
+	DeployerType  string `json:"deployer_type,omitempty"`
+	RepoURL       string `json:"repo_url,omitempty"`       // Required for kustomize
+	KustomizePath string `json:"kustomize_path,omitempty"` // Optional: directory holding kustomization.yaml
+
+	// Optional node pool autoscaling (not supported on cloudrun)
+	Autoscaling *AutoscalingRequest `json:"autoscaling,omitempty"`
+
+	// Optional: primary IP range of the cluster subnet, defaults to 10.0.0.0/24
+	SubnetCIDRBlock string `json:"subnet_cidr_block,omitempty"`
+
+	// Optional: add a node pool tainted workload=<dedicated_workload>:NoSchedule that only the app's pods run on (not supported on cloudrun)
+	DedicatedNodePool bool   `json:"dedicated_node_pool,omitempty"`
+	DedicatedWorkload string `json:"dedicated_workload,omitempty"` // Optional: defaults to "production"
+
+	// Optional: start the rollout at this time instead of immediately
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+
+	// Optional: deploy even if the deployment is already live with the same image and settings
+	Force bool `json:"force,omitempty"`
+}
+
+// AutoscalingRequest bounds the cluster's node pool size
+type AutoscalingRequest struct {
+	MinNodes       int    `json:"min_nodes"`                  // Required: may be 0
+	MaxNodes       int    `json:"max_nodes"`                  // Required: at least 1 and min_nodes
+	ScaleDownDelay string `json:"scale_down_delay,omitempty"` // Optional: e.g. 5m, idle nodes are removed sooner below 10m
 }
 
 // TriggerRollbackRequest represents a request to rollback a deployment
 type TriggerRollbackRequest struct {
-	TargetVersion string `json:"target_version"`        // Required: version to rollback to
-	TargetTag     string `json:"target_tag,omitempty"`  // Optional: specific image tag
+	TargetVersion string `json:"target_version"`       // Required unless revision is set: version to rollback to
+	TargetTag     string `json:"target_tag,omitempty"` // Optional: specific image tag
+	Revision      int    `json:"revision,omitempty"`   // Optional: Helm revision; 0 for the previous one
+}
+
+// CreateFederatedDeploymentRequest represents a request to deploy one app to several clusters
+type CreateFederatedDeploymentRequest struct {
+	Name     string                    `json:"name"`
+	AppName  string                    `json:"app_name"`
+	Version  string                    `json:"version"`
+	ImageTag string                    `json:"image_tag,omitempty"` // Optional: if provided, triggers immediate provisioning of every target
+	Port     int                       `json:"port,omitempty"`      // Optional: defaults to 8080
+	Targets  []FederationTargetRequest `json:"targets"`             // Required: one deployment is created per target
+}
+
+// FederationTargetRequest describes one cluster of a federated deployment
+type FederationTargetRequest struct {
+	Cloud    string `json:"cloud,omitempty"`    // Optional: defaults to gcp
+	Region   string `json:"region"`             // Required
+	Replicas int    `json:"replicas,omitempty"` // Optional: defaults to 2
+}
+
+// FederatedDeploymentResponse represents a federated deployment in API responses
+type FederatedDeploymentResponse struct {
+	ID          uuid.UUID            `json:"id"`
+	Name        string               `json:"name"`
+	AppName     string               `json:"app_name"`
+	Version     string               `json:"version"`
+	Deployments []DeploymentResponse `json:"deployments"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+// FederatedDeploymentStatusResponse aggregates the statuses of a federated deployment's members
+type FederatedDeploymentStatusResponse struct {
+	ID      uuid.UUID                 `json:"id"`
+	Name    string                    `json:"name"`
+	Status  string                    `json:"status"` // EXPOSED, IN_PROGRESS, DEGRADED or FAILED
+	Counts  map[string]int            `json:"counts"` // Members per deployment status
+	Members []FederatedMemberResponse `json:"members"`
+}
+
+// FederatedMemberResponse represents the status of a single member deployment
+type FederatedMemberResponse struct {
+	DeploymentID uuid.UUID `json:"deployment_id"`
+	Cloud        string    `json:"cloud"`
+	Region       string    `json:"region"`
+	Status       string    `json:"status"`
+	ExternalURL  string    `json:"external_url,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// FederatedRollbackResponse reports the rollback started for each member deployment
+type FederatedRollbackResponse struct {
+	ID      uuid.UUID               `json:"id"`
+	Members []OrchestrationResponse `json:"members"`
+}
+
+// CostEstimateResponse represents the projected monthly cost of a deployment's infrastructure
+type CostEstimateResponse struct {
+	MonthlyCostUSD      float64            `json:"monthly_cost_usd"`
+	BreakdownByResource map[string]float64 `json:"breakdown_by_resource"`
+	Region              string             `json:"region"`
+	MachineType         string             `json:"machine_type"`
+	NodeCount           int                `json:"node_count"`
+	NotIncluded         []string           `json:"not_included,omitempty"` // Addons that are not priced
+}
+
+// ResourceCostResponse is the incurred cost of one billed resource
+type ResourceCostResponse struct {
+	ResourceType string  `json:"resource_type"`
+	Name         string  `json:"name"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// InfrastructureCostResponse represents the costs a deployment's infrastructure has incurred
+type InfrastructureCostResponse struct {
+	DeploymentID        string                 `json:"deployment_id"`
+	DailyCostUSD        float64                `json:"daily_cost_usd"`
+	MonthlyEstimateUSD  float64                `json:"monthly_estimate_usd"`
+	BreakdownByResource []ResourceCostResponse `json:"breakdown_by_resource"`
+	LookbackDays        int                    `json:"lookback_days"` // Days the costs are averaged over
+}
+
+// NetworkDestinationResponse is the egress sent to one destination country
+type NetworkDestinationResponse struct {
+	Country  string  `json:"country"`
+	EgressGB float64 `json:"egress_gb"`
+}
+
+// NetworkStatsResponse represents the internet traffic of a deployment's cluster nodes
+type NetworkStatsResponse struct {
+	DeploymentID     string                       `json:"deployment_id"`
+	EgressGBLast24h  float64                      `json:"egress_gb_last_24h"`
+	IngressGBLast24h float64                      `json:"ingress_gb_last_24h"`
+	TopDestinations  []NetworkDestinationResponse `json:"top_destinations"`          // Largest egress destinations by country
+	EgressAlertGB    float64                      `json:"egress_alert_gb,omitempty"` // The deployment's own alert threshold
+	LastCheckAt      *time.Time                   `json:"last_check_at,omitempty"`   // Last nightly egress check
+}
+
+// DeploymentCostResponse is one deployment's projected monthly cost in a cost summary
+type DeploymentCostResponse struct {
+	DeploymentID       string     `json:"deployment_id"`
+	Name               string     `json:"name"`
+	MonthlyEstimateUSD float64    `json:"monthly_estimate_usd"`
+	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+}
+
+// CostSummaryResponse represents projected monthly costs across all running deployments
+type CostSummaryResponse struct {
+	TotalMonthlyEstimateUSD float64                  `json:"total_monthly_estimate_usd"`
+	Deployments             []DeploymentCostResponse `json:"deployments"`
+	GroupBy                 string                   `json:"group_by,omitempty"`
+	Groups                  map[string]float64       `json:"groups,omitempty"` // Totals per value of the group_by tag
+}
+
+// DeploymentBudgetResponse compares one deployment's projected monthly spend with its budget
+type DeploymentBudgetResponse struct {
+	DeploymentID       string     `json:"deployment_id"`
+	Name               string     `json:"name"`
+	MonthlyBudgetUSD   float64    `json:"monthly_budget_usd"`
+	MonthlyEstimateUSD float64    `json:"monthly_estimate_usd"`
+	PercentUsed        float64    `json:"percent_used"`
+	AlertPercent       int        `json:"alert_percent,omitempty"` // Highest budget threshold last alerted on
+	AlertSentAt        *time.Time `json:"alert_sent_at,omitempty"`
+	Paused             bool       `json:"paused"`
+	CostUpdatedAt      *time.Time `json:"cost_updated_at,omitempty"` // When the projection was last recorded
+}
+
+// BudgetStatusResponse represents projected spend against budget across all budgeted deployments
+type BudgetStatusResponse struct {
+	TotalMonthlyBudgetUSD   float64                    `json:"total_monthly_budget_usd"`
+	TotalMonthlyEstimateUSD float64                    `json:"total_monthly_estimate_usd"`
+	Deployments             []DeploymentBudgetResponse `json:"deployments"`
+}
+
+// OrphanedStackResponse represents a Pulumi stack with no live infrastructure record
+type OrphanedStackResponse struct {
+	StackName            string     `json:"stack_name"`
+	DeploymentID         string     `json:"deployment_id"`
+	InfrastructureID     string     `json:"infrastructure_id,omitempty"`     // Empty when no record uses the stack
+	InfrastructureStatus string     `json:"infrastructure_status,omitempty"` // FAILED or DESTROYED
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`            // When the record last changed
+}
+
+// OrphanedResourcesResponse represents the orphaned stacks found in the Pulumi backend
+type OrphanedResourcesResponse struct {
+	Stacks []OrphanedStackResponse `json:"stacks"`
+	Total  int                     `json:"total"`
+}
+
+// OrphanedCleanupResponse represents the stack destroy jobs started by a cleanup
+type OrphanedCleanupResponse struct {
+	Triggered []string `json:"triggered"`        // Stack names
+	Failed    []string `json:"failed,omitempty"` // Stack names whose jobs could not be enqueued
 }
 
 // OrchestrationResponse represents a response for async orchestration operations
 type OrchestrationResponse struct {
-	DeploymentID string `json:"deployment_id"`
-	Status       string `json:"status"`
-	Message      string `json:"message"`
+	DeploymentID    string `json:"deployment_id"`
+	Status          string `json:"status"`
+	Message         string `json:"message"`
+	AlreadyDeployed bool   `json:"already_deployed,omitempty"` // No job was started, the deployment is already live
+	ApprovalID      string `json:"approval_id,omitempty"`      // Set when the rollout awaits or received approval
+}
+
+// ApprovalResponse represents a deployment approval in API responses
+type ApprovalResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	DeploymentID uuid.UUID  `json:"deployment_id"`
+	RequestedBy  string     `json:"requested_by"`
+	Approvers    []string   `json:"approvers"`
+	Status       string     `json:"status"` // PENDING, APPROVED, REJECTED, EXPIRED
+	DecidedBy    string     `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TransferOwnershipRequest represents a request to transfer deployments to a new owner
+type TransferOwnershipRequest struct {
+	NewOwnerID string `json:"new_owner_id"` // User ID
+}
+
+// OwnershipTransferResponse represents an ownership history entry in API responses
+type OwnershipTransferResponse struct {
+	ID            uuid.UUID `json:"id"`
+	DeploymentID  uuid.UUID `json:"deployment_id"`
+	PreviousOwner string    `json:"previous_owner,omitempty"`
+	NewOwner      string    `json:"new_owner"`
+	TransferredBy string    `json:"transferred_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// BulkTransferResponse represents the result of transferring all of an owner's deployments
+type BulkTransferResponse struct {
+	PreviousOwner string      `json:"previous_owner"`
+	NewOwner      string      `json:"new_owner"`
+	DeploymentIDs []uuid.UUID `json:"deployment_ids"`
+	Count         int         `json:"count"`
 }
 
 // QueueStatsResponse represents queue statistics
 type QueueStatsResponse struct {
-	Provision int64 `json:"provision"`
-	Deploy    int64 `json:"deploy"`
-	Destroy   int64 `json:"destroy"`
-	Rollback  int64 `json:"rollback"`
+	Provision     int64 `json:"provision"`
+	Deploy        int64 `json:"deploy"`
+	Destroy       int64 `json:"destroy"`
+	Rollback      int64 `json:"rollback"`
+	Reconcile     int64 `json:"reconcile"`
+	UpdateInfra   int64 `json:"update_infra"`
+	ActiveWorkers int   `json:"active_workers"` // Workers with a live heartbeat
+}
+
+// WorkerResponse represents a running worker as of its last heartbeat
+type WorkerResponse struct {
+	Hostname    string    `json:"hostname"`
+	PID         int       `json:"pid"`
+	Concurrency int       `json:"concurrency"`
+	ActiveJobs  int       `json:"active_jobs"`
+	StartedAt   time.Time `json:"started_at"`
+	Version     string    `json:"version"`
+}
+
+// WorkersResponse lists the workers with a live heartbeat
+type WorkersResponse struct {
+	Workers []WorkerResponse `json:"workers"`
 }
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// TransferOwnership handles POST /api/v1/deployments/{id}/transfer-ownership. Callers are not
+// authenticated, so until they are only an admin may transfer a deployment.
+func (h *DeploymentHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	newOwner, ok := decodeNewOwner(w, r)
+	if !ok {
+		return
+	}
+
+	if !isAdminRequest(r, h.adminToken) {
+		RespondWithError(w, http.StatusForbidden, "Only an admin can transfer a deployment")
+		return
+	}
+
+	// Read from the primary so the conflict check sees a transfer that just happened
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	actor := requestActor(r)
+
+	if deployment.UserID == newOwner {
+		RespondWithError(w, http.StatusConflict, "Deployment is already owned by "+newOwner)
+		return
+	}
+
+	entry, err := h.repo.TransferDeploymentOwnership(r.Context(), deployment, newOwner, actor)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Failed to transfer deployment ownership")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to transfer ownership")
+		return
+	}
+
+	details, _ := json.Marshal(map[string]string{"previous_owner": entry.PreviousOwner, "new_owner": newOwner})
+	if err := h.repo.CreateAuditLog(r.Context(), &state.AuditLog{
+		Action:       "deployment.transfer_ownership",
+		DeploymentID: deployment.ID,
+		Actor:        actor,
+		Details:      string(details),
+	}); err != nil {
+		log.Warn().Err(err).Str("deployment_id", idStr).Msg("Failed to record ownership transfer")
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("previous_owner", entry.PreviousOwner).
+		Str("new_owner", newOwner).
+		Str("actor", actor).
+		Msg("Deployment ownership transferred")
+
+	RespondWithJSON(w, http.StatusOK, OwnershipTransferToResponse(entry))
+}
+
+// TransferAllDeployments handles POST /api/v1/admin/users/{id}/transfer-all-deployments,
+// moving every deployment the user owns to the new owner
+func (h *DeploymentHandler) TransferAllDeployments(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	owner := ownerID.String()
+
+	newOwner, ok := decodeNewOwner(w, r)
+	if !ok {
+		return
+	}
+
+	if owner == newOwner {
+		RespondWithError(w, http.StatusBadRequest, "new_owner_id must differ from the current owner")
+		return
+	}
+
+	actor := requestActor(r)
+	ids, err := h.repo.TransferAllDeployments(r.Context(), owner, newOwner, actor)
+	if err != nil {
+		log.Error().Err(err).Str("owner", owner).Msg("Failed to transfer deployments")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to transfer deployments")
+		return
+	}
+
+	details, _ := json.Marshal(map[string]string{"previous_owner": owner, "new_owner": newOwner})
+	for _, id := range ids {
+		if err := h.repo.CreateAuditLog(r.Context(), &state.AuditLog{
+			Action:       "deployment.transfer_ownership",
+			DeploymentID: id,
+			Actor:        actor,
+			Details:      string(details),
+		}); err != nil {
+			log.Warn().Err(err).Str("deployment_id", id.String()).Msg("Failed to record ownership transfer")
+		}
+	}
+
+	log.Info().
+		Str("previous_owner", owner).
+		Str("new_owner", newOwner).
+		Int("count", len(ids)).
+		Str("actor", actor).
+		Msg("Deployments transferred")
+
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	RespondWithJSON(w, http.StatusOK, BulkTransferResponse{
+		PreviousOwner: owner,
+		NewOwner:      newOwner,
+		DeploymentIDs: ids,
+		Count:         len(ids),
+	})
+}
+
+// GetOwnershipHistory handles GET /api/v1/deployments/{id}/ownership-history
+func (h *DeploymentHandler) GetOwnershipHistory(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	history, err := h.repo.ListDeploymentOwnershipHistory(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", idStr).Msg("Failed to list ownership history")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list ownership history")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, OwnershipHistoryToResponse(history))
+}
+
+// decodeNewOwner reads the new owner's user ID from a transfer request, responding with an
+// error when it is missing or not a UUID
+func decodeNewOwner(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req TransferOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return "", false
+	}
+
+	if strings.TrimSpace(req.NewOwnerID) == "" {
+		RespondWithError(w, http.StatusBadRequest, "new_owner_id is required")
+		return "", false
+	}
+
+	newOwner, err := uuid.Parse(strings.TrimSpace(req.NewOwnerID))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "new_owner_id must be a user ID (UUID)")
+		return "", false
+	}
+
+	return newOwner.String(), true
+}
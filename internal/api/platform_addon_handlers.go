@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// GetAddonVersions handles GET /api/v1/deployments/{id}/infrastructure/addon-versions
+func (h *ReleaseHandler) GetAddonVersions(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, helm, ok := h.addonCluster(w, r, deploymentID)
+	if !ok {
+		return
+	}
+
+	response := AddonVersionsResponse{
+		DeploymentID: deploymentID,
+		Addons:       []AddonVersionResponse{},
+	}
+	versions := make(map[string]string)
+
+	for _, addon := range helm.PlatformAddons() {
+		version, err := helm.GetAddonVersion(r.Context(), infra, addon)
+		if err != nil {
+			log.Error().Err(err).Str("deployment_id", deploymentIDStr).Str("addon", addon.Name).Msg("Failed to get addon version")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to get addon versions")
+			return
+		}
+
+		if version.Installed {
+			versions[addon.Name] = version.CurrentVersion
+		}
+		response.Addons = append(response.Addons, AddonVersionToResponse(version))
+	}
+
+	if err := h.repo.UpdateInfrastructureAddonVersions(r.Context(), infra.ID, versions); err != nil {
+		log.Warn().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to record addon versions")
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// UpgradeAddon handles POST /api/v1/deployments/{id}/infrastructure/upgrade-addon?addon=istio
+func (h *ReleaseHandler) UpgradeAddon(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	name := r.URL.Query().Get("addon")
+	if name == "" {
+		RespondWithError(w, http.StatusBadRequest, "addon is required")
+		return
+	}
+
+	infra, helm, ok := h.addonCluster(w, r, deploymentID)
+	if !ok {
+		return
+	}
+
+	addon, err := helm.PlatformAddon(name)
+	if errors.Is(err, deployer.ErrUnknownAddon) {
+		RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("Unknown addon %s", name))
+		return
+	}
+
+	version, err := helm.GetAddonVersion(r.Context(), infra, addon)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Str("addon", name).Msg("Failed to get addon version")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get addon version")
+		return
+	}
+
+	if !version.Installed {
+		RespondWithError(w, http.StatusNotFound, fmt.Sprintf("Addon %s is not installed on the cluster", name))
+		return
+	}
+
+	if !version.UpgradeAvailable {
+		RespondWithError(w, http.StatusConflict,
+			fmt.Sprintf("Addon %s is already at %s", name, version.CurrentVersion))
+		return
+	}
+
+	if err := helm.UpgradeAddon(r.Context(), infra, addon, version.AvailableVersion); err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Str("addon", name).Msg("Failed to upgrade addon")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to upgrade addon: "+err.Error())
+		return
+	}
+
+	versions := make(map[string]string, len(infra.AddonVersions)+1)
+	for k, v := range infra.AddonVersions {
+		versions[k] = v
+	}
+	versions[name] = version.AvailableVersion
+	if err := h.repo.UpdateInfrastructureAddonVersions(r.Context(), infra.ID, versions); err != nil {
+		log.Warn().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to record addon versions")
+	}
+
+	log.Info().
+		Str("deployment_id", deploymentIDStr).
+		Str("addon", name).
+		Str("from", version.CurrentVersion).
+		Str("to", version.AvailableVersion).
+		Msg("Platform addon upgraded")
+
+	RespondWithJSON(w, http.StatusOK, UpgradeAddonResponse{
+		DeploymentID:    deploymentID,
+		Addon:           name,
+		PreviousVersion: version.CurrentVersion,
+		Version:         version.AvailableVersion,
+	})
+}
+
+// addonCluster returns the deployment's ready infrastructure and the Helm deployer its addons
+// are managed with, writing an error response and returning false when either is missing
+func (h *ReleaseHandler) addonCluster(w http.ResponseWriter, r *http.Request, deploymentID uuid.UUID) (*state.Infrastructure, *deployer.HelmDeployer, bool) {
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentID.String()).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return nil, nil, false
+	}
+
+	if infra.Status != "READY" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusBadRequest, "Deployment has no ready cluster")
+		return nil, nil, false
+	}
+
+	helm, ok := h.deployer.(*deployer.HelmDeployer)
+	if !ok || helm == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Deployer unavailable")
+		return nil, nil, false
+	}
+
+	return infra, helm, true
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultPodLogTail is how many log lines are returned when tail is not set
+	defaultPodLogTail = 100
+
+	// maxPodLogTail bounds the tail query parameter
+	maxPodLogTail = 5000
+
+	// metricsCacheTTL bounds how often the Metrics Server is queried for a deployment
+	metricsCacheTTL = 30 * time.Second
+)
+
+// PodHandler handles pod inspection HTTP requests
+type PodHandler struct {
+	repo        *state.Repository
+	cache       *queue.RedisQueue
+	execEnabled bool
+	execOrigins []string
+	adminToken  string
+}
+
+// NewPodHandler creates a new pod handler. Exec sessions are refused unless execEnabled is set,
+// and are only opened for admin requests from a browser origin listed in execOrigins, if any.
+func NewPodHandler(repo *state.Repository, cache *queue.RedisQueue, execEnabled bool, execOrigins []string, adminToken string) *PodHandler {
+	return &PodHandler{
+		repo:        repo,
+		cache:       cache,
+		execEnabled: execEnabled,
+		execOrigins: execOrigins,
+		adminToken:  adminToken,
+	}
+}
+
+// ListPods handles GET /api/v1/deployments/{id}/pods
+func (h *PodHandler) ListPods(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, kubeClient, ok := h.kubeClient(w, r, deploymentID)
+	if !ok {
+		return
+	}
+
+	pods, err := kubeClient.ListPods(r.Context(), infra.KubeNamespace, releaseSelector(infra))
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to list pods")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list pods")
+		return
+	}
+
+	response := PodsResponse{
+		DeploymentID: deploymentID,
+		Namespace:    infra.KubeNamespace,
+		Pods:         PodsToResponse(pods),
+	}
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// GetPodLogs handles GET /api/v1/deployments/{id}/pods/{podName}/logs
+func (h *PodHandler) GetPodLogs(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	podName := chi.URLParam(r, "podName")
+	container := r.URL.Query().Get("container")
+
+	tail := int64(defaultPodLogTail)
+	if tailStr := r.URL.Query().Get("tail"); tailStr != "" {
+		tail, err = strconv.ParseInt(tailStr, 10, 64)
+		if err != nil || tail < 1 || tail > maxPodLogTail {
+			RespondWithError(w, http.StatusBadRequest, fmt.Sprintf("tail must be between 1 and %d", maxPodLogTail))
+			return
+		}
+	}
+
+	infra, kubeClient, ok := h.kubeClient(w, r, deploymentID)
+	if !ok {
+		return
+	}
+
+	found, err := releaseHasPod(r.Context(), kubeClient, infra, podName)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to list pods")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get pod logs")
+		return
+	}
+	if !found {
+		RespondWithError(w, http.StatusNotFound, "Pod not found")
+		return
+	}
+
+	logs, err := kubeClient.GetPodLogs(r.Context(), infra.KubeNamespace, podName, container, tail)
+	if err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", deploymentIDStr).
+			Str("pod", podName).
+			Str("container", container).
+			Msg("Failed to get pod logs")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get pod logs")
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, PodLogsResponse{
+		DeploymentID: deploymentID,
+		Pod:          podName,
+		Container:    container,
+		TailLines:    tail,
+		Logs:         logs,
+	})
+}
+
+// GetDeploymentMetrics handles GET /api/v1/deployments/{id}/metrics
+func (h *PodHandler) GetDeploymentMetrics(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	cacheKey := "metrics:" + deploymentIDStr
+
+	// Serve from cache when possible to avoid querying the Metrics Server on every request
+	if h.cache != nil {
+		if data, err := h.cache.GetCache(r.Context(), cacheKey); err != nil {
+			log.Warn().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to read metrics cache")
+		} else if data != nil {
+			var response DeploymentMetricsResponse
+			if err := json.Unmarshal(data, &response); err == nil {
+				response.Cached = true
+				RespondWithJSON(w, http.StatusOK, response)
+				return
+			}
+		}
+	}
+
+	infra, kubeClient, ok := h.kubeClient(w, r, deploymentID)
+	if !ok {
+		return
+	}
+
+	metrics, err := kubeClient.GetPodMetrics(r.Context(), infra.KubeNamespace, releaseSelector(infra))
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get pod metrics")
+		RespondWithError(w, http.StatusServiceUnavailable, "Metrics unavailable")
+		return
+	}
+
+	response := PodMetricsToResponse(deploymentID, metrics)
+
+	if h.cache != nil {
+		if data, err := json.Marshal(response); err == nil {
+			if err := h.cache.SetCache(r.Context(), cacheKey, data, metricsCacheTTL); err != nil {
+				log.Warn().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to write metrics cache")
+			}
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// GetVPARecommendation handles GET /api/v1/deployments/{id}/vpa-recommendation
+func (h *PodHandler) GetVPARecommendation(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, kubeClient, ok := h.kubeClient(w, r, deploymentID)
+	if !ok {
+		return
+	}
+
+	recommendation, err := kubeClient.GetVPARecommendation(r.Context(), infra.KubeNamespace, releaseSelector(infra))
+	if errors.Is(err, deployer.ErrNoVPARecommendation) {
+		RespondWithError(w, http.StatusNotFound, "No VPA recommendation available yet")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get VPA recommendation")
+		RespondWithError(w, http.StatusServiceUnavailable, "VPA recommendation unavailable")
+		return
+	}
+
+	// Record what was shown, so applying the recommendation applies these values
+	if err := h.repo.UpdateVPARecommendation(r.Context(), deploymentID, recommendation); err != nil {
+		log.Warn().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to record VPA recommendation")
+	}
+
+	RespondWithJSON(w, http.StatusOK, VPARecommendationToResponse(deploymentID, recommendation))
+}
+
+// kubeClient looks up a deployment's release and connects to its cluster. It writes the
+// error response and returns false when either is unavailable.
+func (h *PodHandler) kubeClient(w http.ResponseWriter, r *http.Request, deploymentID uuid.UUID) (*state.Infrastructure, *deployer.KubeClient, bool) {
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentID.String()).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return nil, nil, false
+	}
+
+	if infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Kubernetes release")
+		return nil, nil, false
+	}
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentID.String()).Msg("Failed to create Kubernetes client")
+		RespondWithError(w, http.StatusServiceUnavailable, "Cluster unavailable")
+		return nil, nil, false
+	}
+
+	return infra, kubeClient, true
+}
+
+// releaseHasPod reports whether a pod belongs to a deployment's release. Pod operations are
+// limited to those pods so other workloads in the cluster cannot be reached.
+func releaseHasPod(ctx context.Context, kubeClient *deployer.KubeClient, infra *state.Infrastructure, podName string) (bool, error) {
+	pods, err := kubeClient.ListPods(ctx, infra.KubeNamespace, releaseSelector(infra))
+	if err != nil {
+		return false, err
+	}
+
+	for _, pod := range pods {
+		if pod.Name == podName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// releaseSelector selects the objects of a deployment's Helm release
+func releaseSelector(infra *state.Infrastructure) string {
+	return fmt.Sprintf("app.kubernetes.io/instance=%s", infra.HelmReleaseName)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/alvesdmateus/app-deployer/internal/policy"
+	"github.com/rs/zerolog/log"
+)
+
+// admitRequest evaluates a create or start request against the admission policies. It responds
+// with 403 and the deny messages when a policy rejects the request, and with 500 when the
+// policies cannot be evaluated, since they are not enforced then.
+func (h *DeploymentHandler) admitRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if h.policies == nil {
+		return true
+	}
+
+	decision, err := h.policies.Evaluate(r.Context(), policy.AdmitQuery, req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to evaluate admission policies")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to evaluate admission policies")
+		return false
+	}
+
+	if !decision.Allowed() {
+		log.Info().Strs("violations", decision.Deny).Msg("Deployment request denied by policy")
+		RespondWithJSON(w, http.StatusForbidden, ErrorResponse{
+			Error:      http.StatusText(http.StatusForbidden),
+			Message:    "Deployment request denied by policy",
+			Violations: decision.Deny,
+		})
+		return false
+	}
+
+	return true
+}
+
+// ListPolicies handles GET /api/v1/admin/policies
+func (h *AdminHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	response := PoliciesResponse{Policies: []PolicyFileResponse{}}
+	if h.policies == nil {
+		RespondWithJSON(w, http.StatusOK, response)
+		return
+	}
+
+	files, err := h.policies.Policies()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list policies")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list policies")
+		return
+	}
+
+	response.Enabled = true
+	response.PolicyDir = h.policies.PolicyDir()
+	for _, f := range files {
+		response.Policies = append(response.Policies, PolicyFileResponse{
+			Name:       f.Name,
+			SizeBytes:  f.Size,
+			ModifiedAt: f.ModifiedAt,
+		})
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alvesdmateus/app-deployer/internal/analyzer"
+	"github.com/alvesdmateus/app-deployer/internal/recommendations"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog/log"
+)
+
+// RecommendationHandler handles resource recommendation HTTP requests
+type RecommendationHandler struct {
+	repo        *state.Repository
+	recommender *recommendations.Recommender
+}
+
+// NewRecommendationHandler creates a new recommendation handler
+func NewRecommendationHandler(repo *state.Repository, recommender *recommendations.Recommender) *RecommendationHandler {
+	return &RecommendationHandler{
+		repo:        repo,
+		recommender: recommender,
+	}
+}
+
+// RecommendResources handles GET /api/v1/deployments/recommend-resources
+func (h *RecommendationHandler) RecommendResources(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	req := recommendations.Request{
+		Language:  analyzer.Language(strings.ToLower(query.Get("language"))),
+		Framework: analyzer.Framework(strings.ToLower(query.Get("framework"))),
+	}
+	if req.Language == "" {
+		RespondWithError(w, http.StatusBadRequest, "language is required")
+		return
+	}
+
+	if rpsStr := query.Get("expected_rps"); rpsStr != "" {
+		rps, err := strconv.Atoi(rpsStr)
+		if err != nil || rps < 0 {
+			RespondWithError(w, http.StatusBadRequest, "expected_rps must be a non-negative integer")
+			return
+		}
+		req.ExpectedRPS = rps
+	}
+
+	// Without an app name, or VPA data for it, the language profile is all there is to go on
+	var history []state.VPARecommendation
+	if appName := query.Get("app_name"); appName != "" {
+		var err error
+		history, err = h.repo.ListAppVPARecommendations(r.Context(), appName)
+		if err != nil {
+			log.Error().Err(err).Str("app_name", appName).Msg("Failed to list VPA recommendations")
+			RespondWithError(w, http.StatusInternalServerError, "Failed to recommend resources")
+			return
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, ResourceRecommendationToResponse(h.recommender.Recommend(req, history)))
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// RegistryCredentialHandler handles private container registry credential HTTP requests
+type RegistryCredentialHandler struct {
+	repo   *state.Repository
+	cipher *secrets.Cipher // nil when no encryption key is configured
+}
+
+// NewRegistryCredentialHandler creates a new registry credential handler
+func NewRegistryCredentialHandler(repo *state.Repository, cipher *secrets.Cipher) *RegistryCredentialHandler {
+	return &RegistryCredentialHandler{
+		repo:   repo,
+		cipher: cipher,
+	}
+}
+
+// CreateRegistryCredential handles POST /api/v1/registry-credentials
+func (h *RegistryCredentialHandler) CreateRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	if h.cipher == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Registry credentials unavailable - encryption key not configured")
+		return
+	}
+
+	var req CreateRegistryCredentialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	host := registry.RegistryHost(strings.TrimSpace(req.RegistryURL))
+	if host == "" {
+		RespondWithError(w, http.StatusBadRequest, "registry_url is required")
+		return
+	}
+
+	if req.Username == "" || req.Password == "" {
+		RespondWithError(w, http.StatusBadRequest, "username and password are required")
+		return
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		RespondWithError(w, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	encrypted, err := h.cipher.Encrypt(req.Password)
+	if err != nil {
+		log.Error().Err(err).Str("registry_url", host).Msg("Failed to encrypt registry credential")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to store registry credential")
+		return
+	}
+
+	actor := requestActor(r)
+	credential := &state.RegistryCredential{
+		RegistryURL: host,
+		Username:    req.Username,
+		Secret:      encrypted,
+		ExpiresAt:   req.ExpiresAt,
+		UserID:      actor,
+	}
+
+	if err := h.repo.CreateRegistryCredential(r.Context(), credential); err != nil {
+		log.Error().Err(err).Str("registry_url", host).Msg("Failed to create registry credential")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to store registry credential")
+		return
+	}
+
+	log.Info().
+		Str("actor", actor).
+		Str("registry_url", host).
+		Str("credential_id", credential.ID.String()).
+		Msg("Registry credential stored")
+
+	RespondWithJSON(w, http.StatusCreated, RegistryCredentialToResponse(credential))
+}
+
+// DeleteRegistryCredential handles DELETE /api/v1/registry-credentials/{id}
+func (h *RegistryCredentialHandler) DeleteRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid registry credential ID")
+		return
+	}
+
+	if _, err := h.repo.GetRegistryCredential(r.Context(), id); err != nil {
+		RespondWithError(w, http.StatusNotFound, "Registry credential not found")
+		return
+	}
+
+	if err := h.repo.DeleteRegistryCredential(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("credential_id", idStr).Msg("Failed to delete registry credential")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to delete registry credential")
+		return
+	}
+
+	RespondWithSuccess(w, http.StatusOK, "Registry credential deleted", nil)
+}
+
+// RotateRegistryCredential handles POST /api/v1/registry-credentials/{id}/rotate
+func (h *RegistryCredentialHandler) RotateRegistryCredential(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid registry credential ID")
+		return
+	}
+
+	if h.cipher == nil {
+		RespondWithError(w, http.StatusServiceUnavailable,
+			"Registry credentials unavailable - encryption key not configured")
+		return
+	}
+
+	credential, err := h.repo.GetRegistryCredential(r.Context(), id)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, "Registry credential not found")
+		return
+	}
+
+	if err := registry.RotateCredential(r.Context(), h.repo, h.cipher, credential); err != nil {
+		if errors.Is(err, registry.ErrNotRotatable) {
+			RespondWithError(w, http.StatusBadRequest,
+				"Only credentials with username _json_key hold a service account key that can be rotated")
+			return
+		}
+		log.Error().Err(err).Str("credential_id", idStr).Msg("Failed to rotate registry credential")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to rotate registry credential: "+err.Error())
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, RegistryCredentialToResponse(credential))
+}
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog/log"
+)
+
+// ReleaseHandler handles Helm release-related HTTP requests
+type ReleaseHandler struct {
+	repo     *state.Repository
+	deployer deployer.Deployer
+}
+
+// NewReleaseHandler creates a new release handler
+func NewReleaseHandler(repo *state.Repository, dep deployer.Deployer) *ReleaseHandler {
+	return &ReleaseHandler{
+		repo:     repo,
+		deployer: dep,
+	}
+}
+
+// GetHelmHistory handles GET /api/v1/deployments/{id}/helm-history
+func (h *ReleaseHandler) GetHelmHistory(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.HelmReleaseName == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Helm release")
+		return
+	}
+
+	if h.deployer == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Deployer unavailable")
+		return
+	}
+
+	revisions, err := h.deployer.History(r.Context(), infra)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get Helm history")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get Helm history")
+		return
+	}
+
+	response := HelmHistoryResponse{
+		DeploymentID: deploymentID,
+		ReleaseName:  infra.HelmReleaseName,
+		Namespace:    infra.KubeNamespace,
+		Revisions:    ReleaseRevisionsToResponse(revisions),
+	}
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// GetRollbackOptions handles GET /api/v1/deployments/{id}/rollback-options
+func (h *ReleaseHandler) GetRollbackOptions(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.HelmReleaseName == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Helm release")
+		return
+	}
+
+	helm, ok := h.deployer.(*deployer.HelmDeployer)
+	if !ok || helm == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Deployer unavailable")
+		return
+	}
+
+	options, err := helm.RollbackOptions(r.Context(), infra)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get rollback options")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to get rollback options")
+		return
+	}
+
+	response := RollbackOptionsResponse{
+		DeploymentID: deploymentID,
+		ReleaseName:  infra.HelmReleaseName,
+		Options:      RollbackOptionsToResponse(options),
+	}
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// PublishChart handles POST /api/v1/admin/charts/publish
+func (h *ReleaseHandler) PublishChart(w http.ResponseWriter, r *http.Request) {
+	helm, ok := h.deployer.(*deployer.HelmDeployer)
+	if !ok || helm == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Deployer unavailable")
+		return
+	}
+
+	registryURL := helm.ChartRegistry()
+	if registryURL == "" {
+		RespondWithError(w, http.StatusServiceUnavailable, "No chart registry configured")
+		return
+	}
+
+	meta, err := helm.LocalChart()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read chart")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to read chart")
+		return
+	}
+
+	// Registries overwrite a pushed tag, which would change the chart under existing releases
+	existing, err := h.repo.GetHelmChart(r.Context(), registryURL, meta.Name, meta.Version)
+	if err != nil {
+		log.Error().Err(err).Str("chart", meta.Name).Msg("Failed to get published chart")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to publish chart")
+		return
+	}
+	if existing != nil {
+		RespondWithError(w, http.StatusConflict,
+			fmt.Sprintf("Chart %s %s is already published; bump the version in Chart.yaml", meta.Name, meta.Version))
+		return
+	}
+
+	digest, err := helm.PublishChart(r.Context())
+	if err != nil {
+		log.Error().Err(err).Str("chart", meta.Name).Str("version", meta.Version).Msg("Failed to publish chart")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to publish chart: "+err.Error())
+		return
+	}
+
+	actor := requestActor(r)
+	chart := &state.HelmChart{
+		Name:        meta.Name,
+		Version:     meta.Version,
+		RegistryURL: registryURL,
+		Digest:      digest,
+		PublishedBy: actor,
+	}
+
+	if err := h.repo.CreateHelmChart(r.Context(), chart); err != nil {
+		log.Error().Err(err).Str("chart", meta.Name).Str("version", meta.Version).Msg("Failed to record published chart")
+		RespondWithError(w, http.StatusInternalServerError, "Chart was pushed but could not be recorded")
+		return
+	}
+
+	details, _ := json.Marshal(chart)
+	if err := h.repo.CreateAuditLog(r.Context(), &state.AuditLog{
+		Action:  "helm_chart.publish",
+		Actor:   actor,
+		Details: string(details),
+	}); err != nil {
+		log.Warn().Err(err).Msg("Failed to record chart publish")
+	}
+
+	log.Info().
+		Str("actor", actor).
+		Str("chart", chart.Name).
+		Str("version", chart.Version).
+		Str("digest", chart.Digest).
+		Msg("Helm chart published")
+
+	RespondWithJSON(w, http.StatusCreated, HelmChartToResponse(chart))
+}
+
+// AdoptRelease handles POST /api/v1/deployments/{id}/adopt-release
+func (h *ReleaseHandler) AdoptRelease(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req AdoptReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Namespace == "" || req.ReleaseName == "" {
+		RespondWithError(w, http.StatusBadRequest, "namespace and release_name are required")
+		return
+	}
+
+	// Read from the primary, since adopting the release saves the whole deployment
+	deployment, err := h.repo.GetDeploymentConsistent(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Deployment not found")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	if deployment.Cloud == "cloudrun" || deployment.DeployerType == deployer.DeployerTypeKustomize {
+		RespondWithError(w, http.StatusBadRequest, "Helm releases can only be adopted by Helm deployments on GKE")
+		return
+	}
+
+	// The release is read from the deployment's cluster, so it must already be provisioned or imported
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.Status != "READY" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusBadRequest, "Deployment needs a READY cluster to adopt a release from")
+		return
+	}
+
+	if infra.HelmReleaseName != "" && infra.HelmReleaseName != req.ReleaseName {
+		RespondWithError(w, http.StatusBadRequest,
+			fmt.Sprintf("Deployment already manages release %s", infra.HelmReleaseName))
+		return
+	}
+
+	helm, ok := h.deployer.(*deployer.HelmDeployer)
+	if !ok || helm == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Deployer unavailable")
+		return
+	}
+
+	release, found, err := helm.InspectRelease(r.Context(), infra, req.Namespace, req.ReleaseName)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to inspect Helm release")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to inspect Helm release")
+		return
+	}
+	if !found {
+		RespondWithError(w, http.StatusNotFound, "Helm release not found")
+		return
+	}
+
+	values, err := json.Marshal(release.Values)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Failed to record Helm values")
+		return
+	}
+
+	externalIP, externalURL := h.releaseAddress(r, infra, req.Namespace, req.ReleaseName)
+
+	infra.Namespace = req.Namespace
+	infra.KubeNamespace = req.Namespace
+	infra.HelmReleaseName = req.ReleaseName
+	infra.HelmValues = string(values)
+	infra.ExternalIP = externalIP
+	infra.AdoptedExternally = true
+	if err := h.repo.UpdateInfrastructure(r.Context(), infra); err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to update infrastructure")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to adopt release")
+		return
+	}
+
+	now := time.Now()
+	deployment.Status = "EXPOSED"
+	deployment.DeployerType = deployer.DeployerTypeHelm
+	deployment.ExternalIP = externalIP
+	deployment.ExternalURL = externalURL
+	deployment.Error = ""
+	deployment.DeployedAt = &now
+	if err := h.repo.UpdateDeployment(r.Context(), deployment); err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to update deployment")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to adopt release")
+		return
+	}
+
+	if err := h.repo.CreateDeploymentEvent(r.Context(), &state.DeploymentEvent{
+		DeploymentID: deploymentID,
+		Type:         state.EventTypeStatusChange,
+		Message:      fmt.Sprintf("Adopted Helm release %s at revision %d", release.Name, release.Revision),
+		Metadata: map[string]interface{}{
+			"status":   deployment.Status,
+			"release":  release.Name,
+			"revision": release.Revision,
+			"chart":    release.Chart,
+		},
+	}); err != nil {
+		log.Warn().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to record adoption event")
+	}
+
+	log.Info().
+		Str("deployment_id", deploymentIDStr).
+		Str("namespace", req.Namespace).
+		Str("release", req.ReleaseName).
+		Int("revision", release.Revision).
+		Msg("Adopted existing Helm release")
+
+	RespondWithJSON(w, http.StatusOK, AdoptReleaseResponse{
+		DeploymentID: deploymentID,
+		ReleaseName:  release.Name,
+		Namespace:    req.Namespace,
+		Chart:        release.Chart,
+		Revision:     release.Revision,
+		Status:       deployment.Status,
+		ExternalURL:  externalURL,
+	})
+}
+
+// releaseAddress returns the external IP and URL of a release's LoadBalancer Service, or
+// empty strings when it has none or the address cannot be read
+func (h *ReleaseHandler) releaseAddress(r *http.Request, infra *state.Infrastructure, namespace, releaseName string) (string, string) {
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to create Kubernetes client for adopted release")
+		return "", ""
+	}
+
+	selector := fmt.Sprintf("app.kubernetes.io/instance=%s", releaseName)
+	serviceName, port, found, err := kubeClient.GetLoadBalancerService(r.Context(), namespace, selector)
+	if err != nil || !found {
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to find LoadBalancer Service for adopted release")
+		}
+		return "", ""
+	}
+
+	// The Service is already running, so its address should be assigned
+	externalIP, err := kubeClient.GetLoadBalancerIP(r.Context(), namespace, serviceName, 10*time.Second)
+	if err != nil {
+		log.Warn().Err(err).Str("service", serviceName).Msg("Failed to get LoadBalancer IP for adopted release")
+		return "", ""
+	}
+
+	if port == 0 || port == 80 {
+		return externalIP, fmt.Sprintf("http://%s", externalIP)
+	}
+	return externalIP, fmt.Sprintf("http://%s:%d", externalIP, port)
+}
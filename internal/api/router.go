@@ -1,7 +1,10 @@
 package api
 
 import (
+	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -9,11 +12,20 @@ import (
 	"github.com/alvesdmateus/app-deployer/internal/builder"
 	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
 	"github.com/alvesdmateus/app-deployer/internal/builder/strategies"
+	"github.com/alvesdmateus/app-deployer/internal/costs"
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/maintenance"
 	"github.com/alvesdmateus/app-deployer/internal/orchestrator"
+	"github.com/alvesdmateus/app-deployer/internal/policy"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner/gcp"
 	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/recommendations"
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/alvesdmateus/app-deployer/pkg/config"
 	"github.com/alvesdmateus/app-deployer/pkg/database"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
@@ -22,19 +34,48 @@ import (
 type Server struct {
 	router                *chi.Mux
 	db                    *gorm.DB
+	repo                  *state.Repository
 	redisQueue            *queue.RedisQueue
 	orchestratorClient    *orchestrator.Client
+	rateLimits            config.RateLimitConfig
+	trustedProxies        []string
+	adminToken            string
 	deploymentHandler     *DeploymentHandler
 	infrastructureHandler *InfrastructureHandler
+	releaseHandler        *ReleaseHandler
+	volumeHandler         *VolumeHandler
 	buildHandler          *BuildHandler
+	federationHandler     *FederationHandler
+	costHandler           *CostHandler
+	envHandler            *EnvHandler
+	configMapHandler      *ConfigMapHandler
+	hpaHandler            *HPAHandler
+	podHandler            *PodHandler
+	adminHandler          *AdminHandler
 	analyzerHandler       *AnalyzerHandler
 	builderHandler        *BuilderHandler
+	gitHookHandler        *GitHookHandler
+	recommendationHandler *RecommendationHandler
+	credentialHandler     *RegistryCredentialHandler
+
+	readyMu sync.Mutex
+	ready   *readinessResult // Last /api/v1/readyz result, reused for readyzCacheTTL
 }
 
-// NewServer creates a new API server
-func NewServer(db *gorm.DB) *Server {
-	repo := state.NewRepository(db)
+// readyzCacheTTL is how long a readiness result is reused, so every probe cycle does not hit
+// the database and Redis
+const readyzCacheTTL = 5 * time.Second
+
+// readinessResult is the outcome of a readiness check
+type readinessResult struct {
+	checkedAt time.Time
+	status    int
+	checks    map[string]string
+}
 
+// NewServer creates a new API server. Lookups that tolerate replication lag are served from
+// readDB when it is not nil.
+func NewServer(db, readDB *gorm.DB) *Server {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -65,10 +106,35 @@ func NewServer(db *gorm.DB) *Server {
 		// Continue without Redis - orchestration endpoints will return errors
 	}
 
-	// Initialize orchestrator client
-	var orchClient *orchestrator.Client
+	// Single deployment lookups are cached in Redis when it is available
+	var cache *redis.Client
 	if redisQueue != nil {
-		orchClient = orchestrator.NewClient(redisQueue, log.Logger)
+		cache = redisQueue.Client()
+	}
+	repo := state.NewRepository(db, readDB, cache)
+
+	// Initialize orchestrator client on the configured job queue
+	var orchClient *orchestrator.Client
+	switch cfg.Queue.Backend {
+	case "pubsub":
+		project := cfg.Queue.PubSubProject
+		if project == "" {
+			project = cfg.Provisioner.GCPProject
+		}
+
+		pubsubQueue, err := queue.NewPubSubQueue(context.Background(), queue.PubSubConfig{
+			Project:     project,
+			TopicPrefix: cfg.Queue.PubSubTopicPrefix,
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to connect to Pub/Sub, orchestration features disabled")
+		} else {
+			orchClient = orchestrator.NewClient(pubsubQueue, log.Logger)
+		}
+	default:
+		if redisQueue != nil {
+			orchClient = orchestrator.NewClient(redisQueue, log.Logger)
+		}
 	}
 
 	// Initialize analyzer
@@ -77,43 +143,241 @@ func NewServer(db *gorm.DB) *Server {
 	// Initialize build tracker
 	buildTracker := builder.NewTracker(repo)
 
+	// Initialize provisioner for read-only infrastructure queries
+	prov := initializeProvisioner(cfg, repo)
+
+	// Initialize deployer for read-only release queries
+	dep := initializeDeployer(cfg, repo)
+	helmDeployer, _ := dep.(*deployer.HelmDeployer) // nil when Helm is unavailable
+
+	// Secret environment variables, git hook secrets and registry credentials share one cipher
+	cipher := initializeSecretCipher(cfg)
+
 	// Initialize build service
-	buildService, err := initializeBuildService(cfg, buildTracker)
+	buildService, err := initializeBuildService(cfg, buildTracker, registry.NewCredentialProvider(repo, cipher))
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to initialize build service")
 		// Continue with nil build service - endpoints will return errors
 	}
 
+	policies := initializePolicyEvaluator(cfg)
+
 	s := &Server{
 		router:                chi.NewRouter(),
 		db:                    db,
+		repo:                  repo,
 		redisQueue:            redisQueue,
 		orchestratorClient:    orchClient,
-		deploymentHandler:     NewDeploymentHandler(repo, orchClient),
-		infrastructureHandler: NewInfrastructureHandler(repo),
-		buildHandler:          NewBuildHandler(repo),
+		rateLimits:            cfg.Server.RateLimits,
+		trustedProxies:        cfg.Server.TrustedProxies,
+		adminToken:            cfg.Server.AdminToken,
+		deploymentHandler:     NewDeploymentHandler(repo, orchClient, redisQueue, cfg.Server.AdminToken, approvalExpiry(cfg), policies),
+		infrastructureHandler: NewInfrastructureHandler(repo, prov, redisQueue, orchClient, cfg.Provisioner.GCPProject),
+		releaseHandler:        NewReleaseHandler(repo, dep),
+		volumeHandler:         NewVolumeHandler(repo),
+		buildHandler:          NewBuildHandler(repo, initializeArtifactStore(cfg)),
+		federationHandler:     NewFederationHandler(repo, orchClient),
+		costHandler:           NewCostHandler(initializeCostEstimator(redisQueue), initializeCostTracker(cfg, redisQueue), initializeNetworkMonitor(cfg), repo),
+		envHandler:            NewEnvHandler(repo, cipher),
+		configMapHandler:      NewConfigMapHandler(repo),
+		hpaHandler:            NewHPAHandler(repo, helmDeployer),
+		podHandler:            NewPodHandler(repo, redisQueue, cfg.Server.ExecEnabled, cfg.Server.ExecOrigins, cfg.Server.AdminToken),
+		adminHandler:          NewAdminHandler(repo, resourcePolicy(cfg), maintenance.NewCleaner(repo, cfg.LogRetention.RetentionDays, log.Logger), policies),
 		analyzerHandler:       NewAnalyzerHandler(),
 		builderHandler:        NewBuilderHandler(buildService, analyzer),
+		gitHookHandler:        NewGitHookHandler(repo, orchClient, cipher),
+		recommendationHandler: NewRecommendationHandler(repo, recommendations.NewRecommender(resourcePolicy(cfg))),
+		credentialHandler:     NewRegistryCredentialHandler(repo, cipher),
 	}
 
 	s.setupRoutes()
 	return s
 }
 
+// initializeProvisioner creates the GCP provisioner if it is configured, or returns nil
+func initializeProvisioner(cfg *config.Config, repo *state.Repository) provisioner.Provisioner {
+	if cfg.Provisioner.GCPProject == "" || cfg.Provisioner.PulumiBackend == "" {
+		log.Info().Msg("Provisioner not configured, live infrastructure endpoints disabled")
+		return nil
+	}
+
+	gcpProv, err := gcp.NewGCPProvisioner(gcp.Config{
+		GCPProject:      cfg.Provisioner.GCPProject,
+		GCPRegion:       cfg.Provisioner.GCPRegion,
+		PulumiBackend:   cfg.Provisioner.PulumiBackend,
+		DefaultNodeType: cfg.Provisioner.DefaultNodeType,
+		DefaultNodes:    cfg.Provisioner.DefaultNodes,
+		CostAllocation: gcp.CostAllocationConfig{
+			RequiredLabels: cfg.Provisioner.RequiredCostLabels,
+		},
+	}, provisioner.NewTracker(repo, nil))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize provisioner, live infrastructure endpoints disabled")
+		return nil
+	}
+
+	return gcpProv
+}
+
+// initializeDeployer creates the Helm deployer if the Helm CLI is available, or returns nil
+func initializeDeployer(cfg *config.Config, repo *state.Repository) deployer.Deployer {
+	helmDeployer, err := deployer.NewHelmDeployer(deployer.Config{
+		DefaultReplicas: cfg.Deployer.DefaultReplicas,
+		DefaultPort:     cfg.Deployer.DefaultPort,
+		ResourcePolicy:  resourcePolicy(cfg),
+
+		AddonChartVersions: cfg.Deployer.AddonChartVersions,
+		HelmChartRegistry:  deployer.HelmChartRegistry{RegistryURL: cfg.Deployer.ChartRegistryURL},
+	}, deployer.NewTracker(repo))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize Helm deployer, release endpoints disabled")
+		return nil
+	}
+
+	return helmDeployer
+}
+
+// resourcePolicy returns the configured per-deployment resource limits
+func resourcePolicy(cfg *config.Config) deployer.ResourceLimitPolicy {
+	return deployer.ResourceLimitPolicy{
+		MaxCPULimit:    cfg.Deployer.MaxCPULimit,
+		MaxMemoryLimit: cfg.Deployer.MaxMemoryLimit,
+		MaxReplicas:    cfg.Deployer.MaxReplicas,
+	}
+}
+
+// approvalExpiry returns how long rollouts wait for approval, 24 hours unless configured
+func approvalExpiry(cfg *config.Config) time.Duration {
+	if cfg.Approval.ExpiryHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(cfg.Approval.ExpiryHours) * time.Hour
+}
+
+// initializeArtifactStore creates the GCS store build artifacts are served from, or returns nil
+func initializeArtifactStore(cfg *config.Config) *builder.ArtifactStore {
+	if cfg.Builder.SBOMBucket == "" {
+		return nil
+	}
+
+	store, err := builder.NewArtifactStore(context.Background(), cfg.Builder.SBOMBucket, cfg.Builder.SBOMSigner)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize artifact store, SBOM downloads disabled")
+		return nil
+	}
+
+	return store
+}
+
+// initializeCostEstimator creates the Cloud Billing cost estimator, or returns nil
+func initializeCostEstimator(redisQueue *queue.RedisQueue) *costs.Estimator {
+	estimator, err := costs.NewEstimator(context.Background(), redisQueue)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize cost estimator, cost estimates disabled")
+		return nil
+	}
+
+	return estimator
+}
+
+// initializeCostTracker creates the billing export cost tracker, or returns nil when no
+// billing export is configured
+func initializeCostTracker(cfg *config.Config, redisQueue *queue.RedisQueue) *costs.Tracker {
+	if cfg.Billing.BigQueryDataset == "" {
+		log.Info().Msg("Billing export not configured, incurred cost tracking disabled")
+		return nil
+	}
+
+	tracker, err := costs.NewTracker(context.Background(), costs.TrackerConfig{
+		Project: cfg.Billing.BigQueryProject,
+		Dataset: cfg.Billing.BigQueryDataset,
+		Table:   cfg.Billing.BigQueryTable,
+	}, redisQueue)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize cost tracker, incurred cost tracking disabled")
+		return nil
+	}
+
+	return tracker
+}
+
+// initializeNetworkMonitor creates the Cloud Monitoring network monitor, or returns nil when
+// it cannot be created
+func initializeNetworkMonitor(cfg *config.Config) *costs.NetworkMonitor {
+	monitor, err := costs.NewNetworkMonitor(context.Background(), cfg.Provisioner.GCPProject)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize network monitor, network stats disabled")
+		return nil
+	}
+
+	return monitor
+}
+
+// initializeSecretCipher creates the cipher secret environment variables and git hook secrets
+// are encrypted with, or returns nil when no encryption key is configured
+func initializeSecretCipher(cfg *config.Config) *secrets.Cipher {
+	if cfg.Secrets.EncryptionKey == "" {
+		log.Warn().Msg("No secrets encryption key configured, secret environment variables disabled")
+		return nil
+	}
+
+	cipher, err := secrets.NewCipher(cfg.Secrets.EncryptionKey)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to initialize secrets cipher, secret environment variables disabled")
+		return nil
+	}
+
+	return cipher
+}
+
+// initializePolicyEvaluator creates the OPA evaluator admitting deployment requests if a policy
+// directory is configured, or returns nil
+func initializePolicyEvaluator(cfg *config.Config) *policy.OPAEvaluator {
+	if cfg.Policy.PolicyDir == "" {
+		log.Info().Msg("No policy directory configured, admission policies disabled")
+		return nil
+	}
+
+	evaluator, err := policy.NewOPAEvaluator(cfg.Policy.PolicyDir)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load admission policies, admission policies disabled")
+		return nil
+	}
+
+	return evaluator
+}
+
 // initializeBuildService creates and configures the build service
-func initializeBuildService(cfg *config.Config, tracker builder.BuildTracker) (builder.BuildService, error) {
+func initializeBuildService(cfg *config.Config, tracker builder.BuildTracker, credentials registry.CredentialProvider) (builder.BuildService, error) {
 	// Create registry config
 	registryConfig := registry.Config{
-		Type:     cfg.Registry.Type,
-		Host:     "", // Will be determined by registry type
-		Project:  cfg.Registry.Project,
-		Location: cfg.Registry.Location,
+		Type:        cfg.Registry.Type,
+		Host:        "", // Will be determined by registry type
+		Project:     cfg.Registry.Project,
+		Location:    cfg.Registry.Location,
+		Credentials: credentials,
 	}
 
 	// Create build service config
 	serviceConfig := builder.ServiceConfig{
 		RegistryConfig: registryConfig,
 		StrategyType:   strategies.StrategyTypeDocker,
+		CacheConfig: builder.BuildCacheConfig{
+			BucketName: cfg.Builder.CacheBucket,
+			MaxAgeDays: cfg.Builder.CacheMaxAgeDays,
+		},
+		RegistryCache: cfg.Builder.RegistryCache,
+		SBOMConfig: builder.SBOMConfig{
+			BucketName:  cfg.Builder.SBOMBucket,
+			Format:      cfg.Builder.SBOMFormat,
+			SignerEmail: cfg.Builder.SBOMSigner,
+		},
+		ScanConfig: builder.ScanConfig{
+			Enabled: cfg.Security.ScanImages,
+			FailOn:  cfg.Security.ScanFailOn,
+		},
+		SigningKeyRef: cfg.Security.CosignKeyRef,
+		Attestor:      cfg.Security.BinaryAuthAttestor,
 	}
 
 	// Create build service
@@ -132,35 +396,119 @@ func (s *Server) setupRoutes() {
 	s.router.Use(RecoveryMiddleware)
 	s.router.Use(RequestLogger)
 	s.router.Use(CORSMiddleware())
-	s.router.Use(middleware.RealIP)
+	s.router.Use(RealIPMiddleware(s.trustedProxies))
+	s.router.Use(ActorMiddleware(s.adminToken))
 
 	// Health check endpoints
 	s.router.Get("/health", s.healthCheck)
 	s.router.Get("/health/live", s.livenessCheck)
 	s.router.Get("/health/ready", s.readinessCheck)
 
+	// Kubernetes probes, registered outside the /api/v1 group so they are not rate limited
+	s.router.Get("/api/v1/healthz", s.healthz)
+	s.router.Get("/api/v1/readyz", s.readyz)
+
 	// API v1 routes
 	s.router.Route("/api/v1", func(r chi.Router) {
+		r.Use(RateLimitMiddleware(s.redisQueue, s.rateLimits, s.adminToken))
+
 		// Deployment routes
 		r.Route("/deployments", func(r chi.Router) {
 			r.Get("/", s.deploymentHandler.ListDeployments)
 			r.Post("/", s.deploymentHandler.CreateDeployment)
+			r.Post("/import", s.deploymentHandler.ImportDeployment)
 			r.Get("/status/{status}", s.deploymentHandler.GetDeploymentsByStatus)
+			r.Post("/estimate-cost", s.costHandler.EstimateDeploymentCost)
+			r.Get("/recommend-resources", s.recommendationHandler.RecommendResources)
 
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", s.deploymentHandler.GetDeployment)
 				r.Delete("/", s.deploymentHandler.DeleteDeployment)
 				r.Patch("/status", s.deploymentHandler.UpdateDeploymentStatus)
+				r.Patch("/tags", s.deploymentHandler.UpdateDeploymentTags)
+				r.Put("/budget", s.deploymentHandler.UpdateDeploymentBudget)
+				r.Get("/logs", s.deploymentHandler.GetDeploymentLogs)
+				r.Get("/events", s.deploymentHandler.GetDeploymentEvents)
+				r.Get("/pipeline", s.deploymentHandler.GetDeploymentPipeline)
+				r.Get("/dependencies", s.deploymentHandler.GetDeploymentDependencies)
+				r.Post("/service-routes", s.deploymentHandler.CreateServiceRoute)
+				r.Get("/service-routes", s.deploymentHandler.ListServiceRoutes)
+				r.Post("/mirror/disable", s.deploymentHandler.DisableMirror)
+				r.Get("/mirror/stats", s.deploymentHandler.GetMirrorStats)
+				r.Post("/ab-test/start", s.deploymentHandler.StartABTest)
+				r.Post("/ab-test/conclude", s.deploymentHandler.ConcludeABTest)
+				r.Get("/ab-test/metrics", s.deploymentHandler.GetABTestMetrics)
+				r.Post("/clone", s.deploymentHandler.CloneDeployment)
+				r.Get("/export", s.deploymentHandler.ExportDeployment)
+				r.Post("/transfer-ownership", s.deploymentHandler.TransferOwnership)
+				r.Get("/ownership-history", s.deploymentHandler.GetOwnershipHistory)
+
+				// Environment variable sub-routes
+				r.Get("/env", s.envHandler.ListEnvVars)
+				r.Post("/env", s.envHandler.SetEnvVar)
+				r.Delete("/env/{key}", s.envHandler.DeleteEnvVar)
+
+				// ConfigMap sub-routes
+				r.Get("/configmaps", s.configMapHandler.ListConfigMaps)
+				r.Post("/configmaps", s.configMapHandler.SetConfigMap)
+				r.Delete("/configmaps/{name}", s.configMapHandler.DeleteConfigMap)
+
+				// Git hook sub-routes
+				r.Post("/git-hooks", s.gitHookHandler.CreateGitHook)
 
 				// Orchestration endpoints
 				r.Post("/deploy", s.deploymentHandler.StartDeployment)
 				r.Post("/rollback", s.deploymentHandler.TriggerRollback)
+				r.Post("/reconcile", s.deploymentHandler.ReconcileDeployment)
+				r.Post("/restart", s.deploymentHandler.RestartDeployment)
+				r.Post("/pause", s.deploymentHandler.PauseDeployment)
+				r.Post("/resume", s.deploymentHandler.ResumeDeployment)
+				r.Post("/reconciliation/enable", s.deploymentHandler.EnableReconciliation)
+				r.Post("/reconciliation/disable", s.deploymentHandler.DisableReconciliation)
 
 				// Infrastructure sub-routes
 				r.Get("/infrastructure", s.infrastructureHandler.GetInfrastructure)
+				r.Get("/infrastructure/resources", s.infrastructureHandler.ListInfrastructureResources)
+				r.Get("/infrastructure/progress", s.infrastructureHandler.StreamInfrastructureProgress)
+				r.Get("/infrastructure/cost", s.costHandler.GetInfrastructureCost)
+				r.Get("/network-stats", s.costHandler.GetNetworkStats)
+				r.Get("/infrastructure/export", s.infrastructureHandler.ExportInfrastructure)
+				r.Patch("/infrastructure/node-pool", s.infrastructureHandler.UpdateNodePool)
+				r.Get("/infrastructure/autoscaler-events", s.infrastructureHandler.GetAutoscalerEvents)
+				r.Post("/import-infrastructure", s.infrastructureHandler.ImportInfrastructure)
+				r.Get("/waf", s.infrastructureHandler.GetWAFStatus)
+				r.Put("/waf/rate-limit", s.infrastructureHandler.UpdateRateLimit)
+
+				// Release sub-routes
+				r.Get("/helm-history", s.releaseHandler.GetHelmHistory)
+				r.Get("/rollback-options", s.releaseHandler.GetRollbackOptions)
+				r.Post("/adopt-release", s.releaseHandler.AdoptRelease)
+				r.Get("/infrastructure/addon-versions", s.releaseHandler.GetAddonVersions)
+				r.Post("/infrastructure/upgrade-addon", s.releaseHandler.UpgradeAddon)
+				r.Put("/hpa", s.hpaHandler.UpdateHPA)
+				r.Get("/hpa/status", s.hpaHandler.GetHPAStatus)
+				r.Get("/volumes", s.volumeHandler.ListVolumes)
+				r.Get("/pods", s.podHandler.ListPods)
+				r.Get("/metrics", s.podHandler.GetDeploymentMetrics)
+				r.Get("/vpa-recommendation", s.podHandler.GetVPARecommendation)
+				r.Post("/apply-vpa-recommendation", s.deploymentHandler.ApplyVPARecommendation)
+				r.Get("/pods/{podName}/logs", s.podHandler.GetPodLogs)
+				r.Get("/pods/{podName}/exec", s.podHandler.ExecPod)
 
 				// Build sub-routes
 				r.Get("/builds/latest", s.buildHandler.GetLatestBuild)
+				r.Get("/builds/{buildID}/sbom", s.buildHandler.GetBuildSBOM)
+				r.Get("/builds/{buildID}/scan", s.buildHandler.GetBuildScan)
+			})
+		})
+
+		// Federated deployment routes
+		r.Route("/federated-deployments", func(r chi.Router) {
+			r.Post("/", s.federationHandler.CreateFederatedDeployment)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.Get("/status", s.federationHandler.GetFederatedDeploymentStatus)
+				r.Post("/rollback", s.federationHandler.TriggerFederatedRollback)
 			})
 		})
 
@@ -182,9 +530,48 @@ func (s *Server) setupRoutes() {
 			})
 		})
 
+		// Private container registry credential routes
+		r.Route("/registry-credentials", func(r chi.Router) {
+			r.Post("/", s.credentialHandler.CreateRegistryCredential)
+			r.Delete("/{id}", s.credentialHandler.DeleteRegistryCredential)
+			r.Post("/{id}/rotate", s.credentialHandler.RotateRegistryCredential)
+		})
+
+		// Approval routes
+		r.Route("/approvals/{id}", func(r chi.Router) {
+			r.Post("/approve", s.deploymentHandler.ApproveDeployment)
+			r.Post("/reject", s.deploymentHandler.RejectDeployment)
+		})
+
+		// Git provider webhooks, authenticated by each hook's secret
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Post("/gitlab", s.gitHookHandler.GitLabWebhook)
+		})
+
 		// Orchestrator routes
 		r.Route("/orchestrator", func(r chi.Router) {
 			r.Get("/stats", s.deploymentHandler.GetQueueStats)
+			r.Get("/workers", s.deploymentHandler.ListWorkers)
+		})
+
+		// Admin routes
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(AdminMiddleware(s.adminToken))
+
+			r.Post("/infrastructure/{id}/migrate-backend", s.infrastructureHandler.MigrateBackend)
+			r.Get("/orphaned-resources", s.infrastructureHandler.ListOrphanedResources)
+			r.Post("/orphaned-resources/cleanup", s.infrastructureHandler.CleanupOrphanedResources)
+			r.Get("/resource-policy", s.adminHandler.GetResourcePolicy)
+			r.Put("/resource-policy", s.adminHandler.UpdateResourcePolicy)
+			r.Get("/costs/summary", s.costHandler.GetCostSummary)
+			r.Get("/budget-status", s.costHandler.GetBudgetStatus)
+			r.Get("/vulnerabilities", s.buildHandler.ListVulnerabilities)
+			r.Post("/suppressed-cves", s.adminHandler.CreateCVESuppression)
+			r.Get("/suppressed-cves", s.adminHandler.ListCVESuppressions)
+			r.Post("/maintenance/run-cleanup", s.adminHandler.RunCleanup)
+			r.Get("/policies", s.adminHandler.ListPolicies)
+			r.Post("/charts/publish", s.releaseHandler.PublishChart)
+			r.Post("/users/{id}/transfer-all-deployments", s.deploymentHandler.TransferAllDeployments)
 		})
 	})
 }
@@ -202,6 +589,11 @@ func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
 		Version:  "1.0.0",
 	}
 
+	if s.redisQueue != nil {
+		stats := s.repo.CacheStats()
+		response.DeploymentCache = &CacheStatsResponse{Hits: stats.Hits, Misses: stats.Misses}
+	}
+
 	RespondWithJSON(w, http.StatusOK, response)
 }
 
@@ -245,6 +637,67 @@ func (s *Server) readinessCheck(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, response)
 }
 
+// healthz handles GET /api/v1/healthz - liveness probe, ok whenever the server can respond
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	RespondWithJSON(w, http.StatusOK, map[string]string{
+		"status": "ok",
+	})
+}
+
+// readyz handles GET /api/v1/readyz - readiness probe, checking the database and Redis.
+// Results are reused for readyzCacheTTL.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	s.readyMu.Lock()
+	defer s.readyMu.Unlock()
+
+	if s.ready == nil || time.Since(s.ready.checkedAt) >= readyzCacheTTL {
+		s.ready = s.checkReadiness()
+	}
+
+	RespondWithJSON(w, s.ready.status, s.ready.checks)
+}
+
+// checkReadiness checks the database and Redis, reporting the error of each one that fails.
+// The result is shared between probes, so it does not depend on any one request's context.
+func (s *Server) checkReadiness() *readinessResult {
+	result := &readinessResult{
+		checkedAt: time.Now(),
+		status:    http.StatusOK,
+		checks: map[string]string{
+			"db":    "ok",
+			"redis": "ok",
+		},
+	}
+
+	if err := database.HealthCheck(s.db); err != nil {
+		log.Error().Err(err).Msg("Readiness probe failed: database unhealthy")
+		result.status = http.StatusServiceUnavailable
+		result.checks["db"] = err.Error()
+	}
+
+	// Without Redis the server still serves everything but orchestration, as at startup
+	if s.redisQueue == nil {
+		result.checks["redis"] = "not_configured"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := s.redisQueue.Ping(ctx); err != nil {
+		log.Error().Err(err).Msg("Readiness probe failed: redis unhealthy")
+		result.status = http.StatusServiceUnavailable
+		result.checks["redis"] = err.Error()
+	}
+
+	return result
+}
+
+// OrchestratorClient returns the orchestrator client, or nil if Redis is unavailable
+func (s *Server) OrchestratorClient() *orchestrator.Client {
+	return s.orchestratorClient
+}
+
 // Handler returns the http.Handler for the server
 func (s *Server) Handler() http.Handler {
 	return s.router
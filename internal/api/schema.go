@@ -0,0 +1,232 @@
+package api
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// Request body schemas, compiled once at startup
+var (
+	deploymentSchema *gojsonschema.Schema // CreateDeploymentRequest
+	deploySchema     *gojsonschema.Schema // StartDeploymentRequest
+)
+
+func init() {
+	gojsonschema.FormatCheckers.Add("cidr", cidrFormatChecker{})
+	gojsonschema.FormatCheckers.Add("cron", cronFormatChecker{})
+
+	deploymentSchema = mustLoadSchema("schemas/deployment.json")
+	deploySchema = mustLoadSchema("schemas/deploy.json")
+}
+
+// mustLoadSchema compiles an embedded schema; the schemas ship with the binary, so one that
+// fails to compile is a build defect
+func mustLoadSchema(name string) *gojsonschema.Schema {
+	data, err := schemaFiles.ReadFile(name)
+	if err != nil {
+		panic(fmt.Sprintf("failed to read schema %s: %v", name, err))
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		panic(fmt.Sprintf("failed to compile schema %s: %v", name, err))
+	}
+
+	return schema
+}
+
+// decodeValidated decodes the request body into req after validating it against schema. An
+// unparseable body is answered with 400 and one the schema rejects with 422 listing each
+// violation; false is returned once a response has been written.
+func decodeValidated(w http.ResponseWriter, r *http.Request, schema *gojsonschema.Schema, req interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || !json.Valid(body) {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	return unmarshalValidated(w, body, schema, req)
+}
+
+// unmarshalValidated decodes a JSON body into req after validating it against schema,
+// responding as decodeValidated does
+func unmarshalValidated(w http.ResponseWriter, body []byte, schema *gojsonschema.Schema, req interface{}) bool {
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(body))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	if !result.Valid() {
+		errs := make([]ValidationError, 0, len(result.Errors()))
+		for _, e := range result.Errors() {
+			errs = append(errs, ValidationError{
+				Field:   validationErrorField(e),
+				Message: e.Description(),
+			})
+		}
+		RespondWithJSON(w, http.StatusUnprocessableEntity, ErrorResponse{
+			Error:            http.StatusText(http.StatusUnprocessableEntity),
+			Message:          "Request body failed validation",
+			ValidationErrors: errs,
+		})
+		return false
+	}
+
+	if err := json.Unmarshal(body, req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	return true
+}
+
+// validationErrorField returns the dotted path of the field an error is about, e.g.
+// cronjob.schedule or addons.0.type. Missing required fields are reported on the field itself
+// rather than on the object lacking it.
+func validationErrorField(e gojsonschema.ResultError) string {
+	field := e.Field()
+	if field == gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+		field = ""
+	}
+
+	if property, ok := e.Details()["property"].(string); ok && e.Type() == "required" {
+		if field == "" {
+			return property
+		}
+		return field + "." + property
+	}
+
+	return field
+}
+
+// cidrFormatChecker accepts IPv4 and IPv6 CIDR blocks such as 10.0.0.0/24
+type cidrFormatChecker struct{}
+
+func (cidrFormatChecker) IsFormat(input interface{}) bool {
+	s, ok := input.(string)
+	if !ok {
+		return true
+	}
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
+// cronFormatChecker accepts the schedules Kubernetes CronJobs take: five fields of values,
+// ranges, lists and steps, or a macro such as @daily
+type cronFormatChecker struct{}
+
+// cronFields bounds each field of a cron schedule, in order
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    []string // Names of the values from min, e.g. JAN for month 1
+}{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// cronMacros are the schedule shorthands the CronJob controller accepts
+var cronMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+func (cronFormatChecker) IsFormat(input interface{}) bool {
+	s, ok := input.(string)
+	if !ok {
+		return true
+	}
+	return validateCronSchedule(s) == nil
+}
+
+// validateCronSchedule checks a cron schedule, naming the first field that is out of range
+func validateCronSchedule(schedule string) error {
+	if cronMacros[strings.ToLower(strings.TrimSpace(schedule))] {
+		return nil
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("schedule must have %d fields, got %d", len(cronFields), len(fields))
+	}
+
+	for i, field := range fields {
+		bounds := cronFields[i]
+		for _, part := range strings.Split(field, ",") {
+			if err := validateCronPart(part, bounds.min, bounds.max, bounds.names); err != nil {
+				return fmt.Errorf("invalid %s %q: %w", bounds.name, field, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCronPart checks one list entry of a cron field: *, a value or a range, optionally
+// followed by /step
+func validateCronPart(part string, min, max int, names []string) error {
+	rangePart, step, hasStep := strings.Cut(part, "/")
+	if hasStep {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 {
+			return fmt.Errorf("step must be a positive number")
+		}
+	}
+
+	if rangePart == "*" || rangePart == "?" {
+		return nil
+	}
+
+	low, high, isRange := strings.Cut(rangePart, "-")
+	from, err := cronValue(low, min, max, names)
+	if err != nil {
+		return err
+	}
+	if !isRange {
+		return nil
+	}
+
+	to, err := cronValue(high, min, max, names)
+	if err != nil {
+		return err
+	}
+	if to < from {
+		return fmt.Errorf("range %s ends before it starts", rangePart)
+	}
+
+	return nil
+}
+
+// cronValue parses a cron field value, by number or by name
+func cronValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return min + i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d is outside %d-%d", n, min, max)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// CreateServiceRoute handles POST /api/v1/deployments/{id}/service-routes
+func (h *DeploymentHandler) CreateServiceRoute(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	var req CreateServiceRouteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.PrefixRewrite == "" {
+		req.PrefixRewrite = "/"
+	}
+	if req.WeightPercent == 0 {
+		req.WeightPercent = 100
+	}
+
+	if err := validateServiceRoute(id, &req); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	source, err := h.repo.GetDeployment(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	target, err := h.repo.GetDeployment(r.Context(), req.TargetDeploymentID)
+	if err != nil {
+		RespondWithError(w, http.StatusNotFound, "Target deployment not found")
+		return
+	}
+
+	for _, d := range []*state.Deployment{source, target} {
+		if d.Cloud == "cloudrun" || d.DeployerType == deployer.DeployerTypeKustomize {
+			RespondWithError(w, http.StatusBadRequest, "Service routes are only supported for Helm deployments on GKE")
+			return
+		}
+	}
+
+	sourceInfra, err := h.repo.GetInfrastructure(r.Context(), source.ID)
+	if err != nil || sourceInfra.HelmReleaseName == "" || sourceInfra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusConflict, "Deployment has no Helm release")
+		return
+	}
+
+	targetInfra, err := h.repo.GetInfrastructure(r.Context(), target.ID)
+	if err != nil || targetInfra.HelmReleaseName == "" || targetInfra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusConflict, "Target deployment has no Helm release")
+		return
+	}
+
+	// Istio only routes between services of the same mesh
+	if sourceInfra.ClusterEndpoint != targetInfra.ClusterEndpoint {
+		RespondWithError(w, http.StatusBadRequest, "Deployments must run on the same cluster")
+		return
+	}
+
+	routes, err := h.repo.ListServiceRoutes(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list service routes")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to create service route")
+		return
+	}
+
+	weight := req.WeightPercent
+	for _, route := range routes {
+		if route.Prefix != req.PrefixRewrite {
+			continue
+		}
+		if route.TargetDeploymentID == target.ID {
+			RespondWithError(w, http.StatusConflict, "Service route to target already exists for prefix")
+			return
+		}
+		weight += route.WeightPercent
+	}
+	if weight > 100 {
+		RespondWithError(w, http.StatusConflict,
+			fmt.Sprintf("Routes for prefix %s would take %d%% of its traffic", req.PrefixRewrite, weight))
+		return
+	}
+
+	route := &state.ServiceRoute{
+		SourceDeploymentID: source.ID,
+		TargetDeploymentID: target.ID,
+		Prefix:             req.PrefixRewrite,
+		WeightPercent:      req.WeightPercent,
+	}
+	if err := h.repo.CreateServiceRoute(r.Context(), route); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to create service route")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to create service route")
+		return
+	}
+
+	// Applied before responding so a cluster without Istio is reported rather than left with
+	// a route that never takes effect
+	if err := deployer.SyncServiceRoutes(r.Context(), h.repo, source.ID); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to apply service routes")
+		if delErr := h.repo.DeleteServiceRoute(r.Context(), route.ID); delErr != nil {
+			log.Error().Err(delErr).Str("id", idStr).Msg("Failed to delete unapplied service route")
+		}
+		RespondWithError(w, http.StatusInternalServerError, "Failed to apply service route: "+err.Error())
+		return
+	}
+
+	log.Info().
+		Str("deployment_id", idStr).
+		Str("target_deployment_id", target.ID.String()).
+		Str("prefix", route.Prefix).
+		Int("weight_percent", route.WeightPercent).
+		Msg("Service route created")
+
+	RespondWithJSON(w, http.StatusCreated, ServiceRouteToResponse(route))
+}
+
+// ListServiceRoutes handles GET /api/v1/deployments/{id}/service-routes
+func (h *DeploymentHandler) ListServiceRoutes(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	if _, err := h.repo.GetDeployment(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to get deployment")
+		RespondWithError(w, http.StatusNotFound, "Deployment not found")
+		return
+	}
+
+	routes, err := h.repo.ListServiceRoutes(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list service routes")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list service routes")
+		return
+	}
+
+	response := ServiceRoutesResponse{
+		DeploymentID: id,
+		Routes:       make([]ServiceRouteResponse, len(routes)),
+	}
+	for i := range routes {
+		response.Routes[i] = ServiceRouteToResponse(&routes[i])
+	}
+
+	RespondWithJSON(w, http.StatusOK, response)
+}
+
+// validateServiceRoute checks a route's target, prefix and weight
+func validateServiceRoute(sourceID uuid.UUID, req *CreateServiceRouteRequest) error {
+	if req.TargetDeploymentID == uuid.Nil {
+		return fmt.Errorf("target_deployment_id is required")
+	}
+
+	if req.TargetDeploymentID == sourceID {
+		return fmt.Errorf("a deployment cannot route to itself")
+	}
+
+	if !strings.HasPrefix(req.PrefixRewrite, "/") {
+		return fmt.Errorf("prefix_rewrite must start with /")
+	}
+
+	if req.WeightPercent < 1 || req.WeightPercent > 100 {
+		return fmt.Errorf("weight_percent must be between 1 and 100")
+	}
+
+	return nil
+}
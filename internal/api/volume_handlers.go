@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog/log"
+)
+
+// VolumeHandler handles persistent volume HTTP requests
+type VolumeHandler struct {
+	repo *state.Repository
+}
+
+// NewVolumeHandler creates a new volume handler
+func NewVolumeHandler(repo *state.Repository) *VolumeHandler {
+	return &VolumeHandler{
+		repo: repo,
+	}
+}
+
+// ListVolumes handles GET /api/v1/deployments/{id}/volumes
+func (h *VolumeHandler) ListVolumes(w http.ResponseWriter, r *http.Request) {
+	deploymentIDStr := chi.URLParam(r, "id")
+	deploymentID, err := uuid.Parse(deploymentIDStr)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid deployment ID")
+		return
+	}
+
+	infra, err := h.repo.GetInfrastructure(r.Context(), deploymentID)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to get infrastructure")
+		RespondWithError(w, http.StatusNotFound, "Infrastructure not found")
+		return
+	}
+
+	if infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		RespondWithError(w, http.StatusNotFound, "Deployment has no Kubernetes release")
+		return
+	}
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to create Kubernetes client")
+		RespondWithError(w, http.StatusServiceUnavailable, "Cluster unavailable")
+		return
+	}
+
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", infra.HelmReleaseName)
+	volumes, err := kubeClient.ListPersistentVolumeClaims(r.Context(), infra.KubeNamespace, labelSelector)
+	if err != nil {
+		log.Error().Err(err).Str("deployment_id", deploymentIDStr).Msg("Failed to list volumes")
+		RespondWithError(w, http.StatusInternalServerError, "Failed to list volumes")
+		return
+	}
+
+	response := VolumesResponse{
+		DeploymentID: deploymentID,
+		Namespace:    infra.KubeNamespace,
+		Volumes:      VolumesToResponse(volumes),
+	}
+	RespondWithJSON(w, http.StatusOK, response)
+}
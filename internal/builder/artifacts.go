@@ -0,0 +1,151 @@
+package builder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/storage/v1"
+)
+
+// gcsHost is the host signed URLs point at
+const gcsHost = "storage.googleapis.com"
+
+// ArtifactStore uploads build artifacts such as SBOMs to GCS and hands out signed download URLs
+type ArtifactStore struct {
+	storage     *storage.Service
+	credentials *iamcredentials.Service
+	bucket      string
+	signerEmail string
+}
+
+// NewArtifactStore creates a GCS artifact store. signerEmail is the service account used to
+// sign download URLs; when empty, the metadata server's default account is used.
+func NewArtifactStore(ctx context.Context, bucket, signerEmail string) (*ArtifactStore, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("artifact bucket is required")
+	}
+
+	storageSvc, err := storage.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	credentialsSvc, err := iamcredentials.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IAM credentials client: %w", err)
+	}
+
+	return &ArtifactStore{
+		storage:     storageSvc,
+		credentials: credentialsSvc,
+		bucket:      bucket,
+		signerEmail: signerEmail,
+	}, nil
+}
+
+// Upload writes an object to the bucket and returns its gs:// path
+func (a *ArtifactStore) Upload(ctx context.Context, object, contentType string, r io.Reader) (string, error) {
+	obj := &storage.Object{
+		Name:        object,
+		ContentType: contentType,
+	}
+
+	if _, err := a.storage.Objects.Insert(a.bucket, obj).Media(r).Context(ctx).Do(); err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", object, err)
+	}
+
+	return fmt.Sprintf("gs://%s/%s", a.bucket, object), nil
+}
+
+// SignedURL returns a V4 signed GET URL for a gs:// path, valid for ttl
+func (a *ArtifactStore) SignedURL(ctx context.Context, gsPath string, ttl time.Duration) (string, error) {
+	bucket, object, ok := strings.Cut(strings.TrimPrefix(gsPath, "gs://"), "/")
+	if !strings.HasPrefix(gsPath, "gs://") || !ok || object == "" {
+		return "", fmt.Errorf("invalid GCS path: %s", gsPath)
+	}
+
+	signer, err := a.signer(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	datestamp := now.Format("20060102")
+	timestamp := now.Format("20060102T150405Z")
+	scope := datestamp + "/auto/storage/goog4_request"
+
+	segments := strings.Split(object, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	path := "/" + bucket + "/" + strings.Join(segments, "/")
+
+	query := url.Values{}
+	query.Set("X-Goog-Algorithm", "GOOG4-RSA-SHA256")
+	query.Set("X-Goog-Credential", signer+"/"+scope)
+	query.Set("X-Goog-Date", timestamp)
+	query.Set("X-Goog-Expires", fmt.Sprintf("%d", int(ttl.Seconds())))
+	query.Set("X-Goog-SignedHeaders", "host")
+
+	// Encode sorts keys, as the canonical request requires
+	canonicalQuery := query.Encode()
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		path,
+		canonicalQuery,
+		"host:" + gcsHost + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"GOOG4-RSA-SHA256",
+		timestamp,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	resp, err := a.credentials.Projects.ServiceAccounts.SignBlob(
+		"projects/-/serviceAccounts/"+signer,
+		&iamcredentials.SignBlobRequest{Payload: base64.StdEncoding.EncodeToString([]byte(stringToSign))},
+	).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(resp.SignedBlob)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	return fmt.Sprintf("https://%s%s?%s&X-Goog-Signature=%s", gcsHost, path, canonicalQuery, hex.EncodeToString(signature)), nil
+}
+
+// signer returns the service account email used to sign URLs
+func (a *ArtifactStore) signer(ctx context.Context) (string, error) {
+	if a.signerEmail != "" {
+		return a.signerEmail, nil
+	}
+
+	email, err := metadata.EmailWithContext(ctx, "default")
+	if err != nil {
+		return "", fmt.Errorf("no signer service account configured and metadata server unavailable: %w", err)
+	}
+
+	log.Debug().Str("signer", email).Msg("Using default service account to sign URLs")
+	a.signerEmail = email
+
+	return email, nil
+}
@@ -1,6 +1,7 @@
 package buildtypes
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/alvesdmateus/app-deployer/internal/analyzer"
@@ -12,10 +13,21 @@ type BuildContext struct {
 	AppName      string
 	Version      string
 	SourcePath   string
+	SubPath      string // Service directory within SourcePath for monorepos, empty for the root
 	Analysis     *analyzer.AnalysisResult
 	RegistryType string
 	RegistryHost string
 	BuildID      string
+	CacheImage   string // Local image used as a layer cache source, empty for none
+	Strategy     string // Build strategy, e.g. existing or generated; empty uses the build service's
+	Destination  string // Registry tag strategies that push the image themselves push to
+	CacheRepo    string // Registry repository in-cluster builds cache layers in, empty for none
+}
+
+// ContextDir returns the directory the image is built from: the service's directory for a
+// monorepo service, the source root otherwise
+func (c *BuildContext) ContextDir() string {
+	return filepath.Join(c.SourcePath, c.SubPath)
 }
 
 // BuildResult contains the output of a build operation
@@ -26,4 +38,10 @@ type BuildResult struct {
 	BuildLog      string
 	Success       bool
 	Error         error
+	CacheKey      string // Build cache key, empty when caching is disabled
+	SBOMPath      string // gs:// path of the image SBOM, empty when none was generated
+	SBOMFormat    string // spdx-json or cyclonedx-json
+	Signed        bool   // Whether the pushed image was signed with cosign
+	SignatureRef  string // Registry reference of the cosign signature
+	ScanStatus    string // Vulnerability scan outcome: PASSED, FAILED or SKIPPED
 }
@@ -0,0 +1,180 @@
+package builder
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/storage/v1"
+)
+
+// cacheImageRepository is the local image name restored cache layers are tagged with
+const cacheImageRepository = "app-deployer-cache"
+
+// dependencyLockFiles are hashed into the cache key; a change to any of them invalidates the cache
+var dependencyLockFiles = []string{
+	"go.sum",
+	"package-lock.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"requirements.txt",
+	"poetry.lock",
+	"Pipfile.lock",
+	"Gemfile.lock",
+	"composer.lock",
+	"Cargo.lock",
+	"pom.xml",
+	"build.gradle",
+}
+
+// BuildCacheConfig holds configuration for the persistent build cache
+type BuildCacheConfig struct {
+	BucketName string // GCS bucket holding cache archives; empty disables the cache
+	MaxAgeDays int    // Cache entries older than this are ignored and rebuilt. Default: 7
+}
+
+// BuildCacheManager stores the builder stage of multi-stage builds in GCS so repeated
+// builds with unchanged dependencies can reuse its layers
+type BuildCacheManager struct {
+	storage *storage.Service
+	bucket  string
+	maxAge  time.Duration
+}
+
+// NewBuildCacheManager creates a new GCS-backed build cache using application default credentials
+func NewBuildCacheManager(ctx context.Context, config BuildCacheConfig) (*BuildCacheManager, error) {
+	if config.BucketName == "" {
+		return nil, fmt.Errorf("build cache bucket is required")
+	}
+
+	if config.MaxAgeDays == 0 {
+		config.MaxAgeDays = 7
+	}
+
+	svc, err := storage.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	log.Info().
+		Str("bucket", config.BucketName).
+		Int("maxAgeDays", config.MaxAgeDays).
+		Msg("Build cache initialized")
+
+	return &BuildCacheManager{
+		storage: svc,
+		bucket:  config.BucketName,
+		maxAge:  time.Duration(config.MaxAgeDays) * 24 * time.Hour,
+	}, nil
+}
+
+// CacheKey hashes the Dockerfile and any dependency lock files in the source tree
+func (m *BuildCacheManager) CacheKey(sourcePath, dockerfile string) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(dockerfile))
+
+	for _, name := range dependencyLockFiles {
+		data, err := os.ReadFile(filepath.Join(sourcePath, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		// Include the name so moving content between files changes the key
+		hash.Write([]byte(name))
+		hash.Write(data)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:32], nil
+}
+
+// ImageTag returns the local image tag a cache entry is loaded as
+func (m *BuildCacheManager) ImageTag(key string) string {
+	return fmt.Sprintf("%s:%s", cacheImageRepository, key)
+}
+
+// Restore downloads a cache entry and passes the decompressed image tar to load.
+// It returns false when there is no usable entry for the key.
+func (m *BuildCacheManager) Restore(ctx context.Context, key string, load func(io.Reader) error) (bool, error) {
+	object := m.objectName(key)
+
+	attrs, err := m.storage.Objects.Get(m.bucket, object).Context(ctx).Do()
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get cache entry: %w", err)
+	}
+
+	if updated, err := time.Parse(time.RFC3339, attrs.Updated); err == nil && time.Since(updated) > m.maxAge {
+		log.Debug().Str("key", key).Time("updated", updated).Msg("Build cache entry expired")
+		return false, nil
+	}
+
+	resp, err := m.storage.Objects.Get(m.bucket, object).Context(ctx).Download()
+	if err != nil {
+		return false, fmt.Errorf("failed to download cache entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to decompress cache entry: %w", err)
+	}
+	defer gz.Close()
+
+	if err := load(gz); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Save compresses an image tar and uploads it as the cache entry for key
+func (m *BuildCacheManager) Save(ctx context.Context, key string, image io.Reader) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		gz := gzip.NewWriter(pw)
+		_, err := io.Copy(gz, image)
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	object := &storage.Object{
+		Name:        m.objectName(key),
+		ContentType: "application/gzip",
+	}
+
+	if _, err := m.storage.Objects.Insert(m.bucket, object).Media(pr).Context(ctx).Do(); err != nil {
+		pr.CloseWithError(err)
+		return fmt.Errorf("failed to upload cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// objectName returns the GCS object path for a cache key
+func (m *BuildCacheManager) objectName(key string) string {
+	return fmt.Sprintf("cache/%s.tar.gz", key)
+}
+
+// isNotFound reports whether a GCS API error is a 404
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
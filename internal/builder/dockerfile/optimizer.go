@@ -122,6 +122,8 @@ func (o *Optimizer) GetImageSizeEstimate(analysis *analyzer.AnalysisResult) stri
 		analyzer.LanguageRuby:   "150-250 MB",
 		analyzer.LanguagePHP:    "100-200 MB",
 		analyzer.LanguageDotNet: "150-300 MB",
+		analyzer.LanguageDeno:   "100-200 MB",
+		analyzer.LanguageElixir: "30-80 MB",
 	}
 
 	if estimate, ok := estimates[analysis.Language]; ok {
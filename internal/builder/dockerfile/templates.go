@@ -15,6 +15,8 @@ type LanguageTemplate struct {
 	WorkDir       string
 	BuildCommands []string
 	RunCommand    string
+	NoPort        bool // The app listens on no port, so EXPOSE is omitted
+	Entrypoint    bool // RunCommand is written as the ENTRYPOINT instead of the CMD
 }
 
 // GetTemplate returns the appropriate Dockerfile template based on analysis
@@ -33,9 +35,13 @@ func GetTemplate(analysis *analyzer.AnalysisResult) (*LanguageTemplate, error) {
 	case analyzer.LanguageRuby:
 		return getRubyTemplate(analysis), nil
 	case analyzer.LanguagePHP:
-		return getPHPTemplate(analysis), nil
+		return getPHPTemplate(analysis, analysis.Framework), nil
 	case analyzer.LanguageDotNet:
 		return getDotNetTemplate(analysis), nil
+	case analyzer.LanguageDeno:
+		return getDenoTemplate(analysis), nil
+	case analyzer.LanguageElixir:
+		return getElixirTemplate(analysis), nil
 	default:
 		return nil, fmt.Errorf("unsupported language: %s", analysis.Language)
 	}
@@ -171,7 +177,8 @@ USER appuser`, runtime),
 
 // getPythonTemplate returns optimized Dockerfile for Python
 func getPythonTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
-	runtime := analysis.Runtime
+	// The analyzer reports runtimes such as python:3.11
+	runtime := strings.TrimPrefix(analysis.Runtime, "python:")
 	if runtime == "" {
 		runtime = "3.12" // Default Python version
 	}
@@ -182,6 +189,21 @@ func getPythonTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
 		startCmd = "python app.py"
 	}
 
+	// Django and FastAPI apps are served by gunicorn and uvicorn, which not every project
+	// lists; Django's static files are collected into the image
+	var serverCmd string
+	switch analysis.Framework {
+	case analyzer.FrameworkDjango:
+		if !hasPythonDependency(analysis, "gunicorn") {
+			serverCmd = "\n\n# Install the WSGI server\nRUN pip install --no-cache-dir gunicorn"
+		}
+		serverCmd += "\n\n# Collect static files\nRUN python manage.py collectstatic --noinput"
+	case analyzer.FrameworkFastAPI:
+		if !hasPythonDependency(analysis, "uvicorn") {
+			serverCmd = "\n\n# Install the ASGI server\nRUN pip install --no-cache-dir uvicorn"
+		}
+	}
+
 	var installCmd string
 	if buildTool == "poetry" {
 		installCmd = `# Install poetry
@@ -210,7 +232,7 @@ WORKDIR /build
 %s
 
 # Copy source code
-COPY . .`, runtime, installCmd),
+COPY . .%s`, runtime, installCmd, serverCmd),
 		RuntimeStage: fmt.Sprintf(`# Runtime stage
 FROM python:%s-slim
 WORKDIR /app
@@ -218,8 +240,8 @@ WORKDIR /app
 # Create non-root user
 RUN useradd -m -u 1000 appuser
 
-# Copy dependencies and code from builder
-COPY --from=builder /usr/local/lib/python*/site-packages /usr/local/lib/python*/site-packages
+# Copy dependencies, their scripts and code from builder
+COPY --from=builder /usr/local /usr/local
 COPY --from=builder /build .
 
 # Change ownership
@@ -231,6 +253,21 @@ USER appuser`, runtime),
 	}
 }
 
+// hasPythonDependency reports whether a package is among the analyzed dependencies, which may
+// carry extras and version constraints, e.g. uvicorn[standard]>=0.29
+func hasPythonDependency(analysis *analyzer.AnalysisResult, name string) bool {
+	for dep := range analysis.Dependencies {
+		dep = strings.ToLower(dep)
+		if dep == name {
+			return true
+		}
+		if strings.HasPrefix(dep, name) && strings.ContainsAny(dep[len(name):len(name)+1], "[<>=~! ;") {
+			return true
+		}
+	}
+	return false
+}
+
 // getJavaTemplate returns optimized multi-stage Dockerfile for Java
 func getJavaTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
 	runtime := analysis.Runtime
@@ -293,10 +330,22 @@ USER appuser`, runtime),
 
 // getRustTemplate returns optimized multi-stage Dockerfile for Rust
 func getRustTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
+	runtime := analysis.Runtime
+	if runtime == "" {
+		runtime = "1.75" // Default Rust version
+	}
+
+	// Cargo names the release binary after the crate's binary target
+	startCmd := analysis.StartCommand
+	if startCmd == "" {
+		startCmd = "./app"
+	}
+	binary := strings.TrimPrefix(startCmd, "./")
+
 	return &LanguageTemplate{
-		BaseImage: "rust:1.75-alpine",
-		BuildStage: `# Build stage
-FROM rust:1.75-alpine AS builder
+		BaseImage: fmt.Sprintf("rust:%s-alpine", runtime),
+		BuildStage: fmt.Sprintf(`# Build stage
+FROM rust:%s-alpine AS builder
 WORKDIR /build
 
 # Install build dependencies
@@ -309,8 +358,8 @@ COPY Cargo.toml Cargo.lock* ./
 COPY src ./src
 
 # Build application
-RUN cargo build --release`,
-		RuntimeStage: `# Runtime stage
+RUN cargo build --release`, runtime),
+		RuntimeStage: fmt.Sprintf(`# Runtime stage
 FROM alpine:latest
 WORKDIR /app
 
@@ -322,14 +371,14 @@ RUN addgroup -g 1000 appuser && \
     adduser -D -u 1000 -G appuser appuser
 
 # Copy binary from builder
-COPY --from=builder /build/target/release/app .
+COPY --from=builder /build/target/release/%s .
 
 # Change ownership
-RUN chown appuser:appuser app
+RUN chown appuser:appuser %s
 
-USER appuser`,
+USER appuser`, binary, binary),
 		WorkDir:    "/app",
-		RunCommand: "./app",
+		RunCommand: startCmd,
 	}
 }
 
@@ -383,17 +432,30 @@ USER appuser`, runtime),
 	}
 }
 
-// getPHPTemplate returns Dockerfile for PHP
-func getPHPTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
+// getPHPTemplate returns Dockerfile for PHP. Laravel and Symfony apps are served by nginx in
+// front of php-fpm; other apps run their start command with the PHP CLI.
+func getPHPTemplate(analysis *analyzer.AnalysisResult, framework analyzer.Framework) *LanguageTemplate {
 	runtime := analysis.Runtime
 	if runtime == "" {
 		runtime = "8.3" // Default PHP version
 	}
 
+	switch framework {
+	case analyzer.FrameworkLaravel:
+		return getPHPFPMTemplate(analysis, runtime, "storage bootstrap/cache")
+	case analyzer.FrameworkSymfony:
+		return getPHPFPMTemplate(analysis, runtime, "var")
+	}
+
+	startCmd := analysis.StartCommand
+	if startCmd == "" {
+		startCmd = "php -S 0.0.0.0:8080 -t ."
+	}
+
 	return &LanguageTemplate{
-		BaseImage: fmt.Sprintf("php:%s-fpm-alpine", runtime),
+		BaseImage: fmt.Sprintf("php:%s-cli-alpine", runtime),
 		BuildStage: fmt.Sprintf(`# Build stage
-FROM php:%s-fpm-alpine AS builder
+FROM php:%s-cli-alpine AS builder
 WORKDIR /build
 
 # Install composer
@@ -408,7 +470,7 @@ RUN composer install --no-dev --optimize-autoloader
 # Copy source code
 COPY . .`, runtime),
 		RuntimeStage: fmt.Sprintf(`# Runtime stage
-FROM php:%s-fpm-alpine
+FROM php:%s-cli-alpine
 WORKDIR /app
 
 # Create non-root user
@@ -423,20 +485,98 @@ RUN chown -R appuser:appuser /app
 
 USER appuser`, runtime),
 		WorkDir:    "/app",
-		RunCommand: "php-fpm",
+		RunCommand: startCmd,
+	}
+}
+
+// getPHPFPMTemplate returns a Dockerfile that serves a framework's public/ front controller
+// with nginx and php-fpm. Deployments run one container per pod, so php-fpm runs in the
+// background of the nginx container. writableDirs are the framework's cache and storage
+// directories.
+func getPHPFPMTemplate(analysis *analyzer.AnalysisResult, runtime, writableDirs string) *LanguageTemplate {
+	port := analysis.Port
+	if port == 0 {
+		port = 8080
+	}
+
+	return &LanguageTemplate{
+		BaseImage: fmt.Sprintf("php:%s-fpm-alpine", runtime),
+		BuildStage: fmt.Sprintf(`# Build stage
+FROM php:%s-fpm-alpine AS builder
+WORKDIR /build
+
+# Install composer
+COPY --from=composer:latest /usr/bin/composer /usr/bin/composer
+
+# Copy composer files
+COPY composer.json composer.lock* ./
+
+# Install dependencies without running framework scripts, which need the source
+RUN composer install --no-dev --no-scripts --no-autoloader --prefer-dist
+
+# Copy source code and build the optimized autoloader
+COPY . .
+RUN composer dump-autoload --no-dev --optimize`, runtime),
+		RuntimeStage: fmt.Sprintf(`# Runtime stage
+FROM php:%s-fpm-alpine
+WORKDIR /app
+
+# Install nginx
+RUN apk add --no-cache nginx
+
+# Serve public/ and pass PHP requests to php-fpm
+RUN printf '%%s\n' \
+    'server {' \
+    '    listen %d;' \
+    '    root /app/public;' \
+    '    index index.php;' \
+    '    location / { try_files $uri $uri/ /index.php?$query_string; }' \
+    '    location ~ \.php$ {' \
+    '        fastcgi_pass 127.0.0.1:9000;' \
+    '        fastcgi_param SCRIPT_FILENAME $realpath_root$fastcgi_script_name;' \
+    '        include fastcgi_params;' \
+    '    }' \
+    '}' > /etc/nginx/http.d/default.conf && \
+    printf '%%s\n' '#!/bin/sh' 'php-fpm -D' 'exec nginx -g "daemon off;"' \
+    > /usr/local/bin/start-php-fpm && chmod +x /usr/local/bin/start-php-fpm
+
+# Create non-root user
+RUN addgroup -g 1000 appuser && \
+    adduser -D -u 1000 -G appuser appuser
+
+# Copy application from builder
+COPY --from=builder /build .
+
+# Change ownership, nginx runs as appuser so needs its runtime directories
+RUN mkdir -p %s /run/nginx && \
+    chown -R appuser:appuser /app /var/lib/nginx /var/log/nginx /run/nginx
+
+USER appuser`, runtime, port, writableDirs),
+		WorkDir:    "/app",
+		RunCommand: "/usr/local/bin/start-php-fpm",
 	}
 }
 
-// getDotNetTemplate returns optimized multi-stage Dockerfile for .NET
+// getDotNetTemplate returns optimized multi-stage Dockerfile for .NET. ASP.NET Core apps run on
+// the ASP.NET runtime, console apps on the plain runtime and Blazor WebAssembly apps, which are
+// static files once published, are served by nginx.
 func getDotNetTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
 	runtime := analysis.Runtime
 	if runtime == "" {
 		runtime = "8.0" // Default .NET version
 	}
 
-	return &LanguageTemplate{
-		BaseImage: fmt.Sprintf("mcr.microsoft.com/dotnet/sdk:%s-alpine", runtime),
-		BuildStage: fmt.Sprintf(`# Build stage
+	startCmd := analysis.StartCommand
+	if startCmd == "" {
+		startCmd = "dotnet app.dll"
+	}
+
+	port := analysis.Port
+	if port == 0 {
+		port = 8080
+	}
+
+	buildStage := fmt.Sprintf(`# Build stage
 FROM mcr.microsoft.com/dotnet/sdk:%s-alpine AS builder
 WORKDIR /build
 
@@ -448,10 +588,48 @@ RUN dotnet restore
 COPY . .
 
 # Build and publish
-RUN dotnet publish -c Release -o out`, runtime),
+RUN dotnet publish -c Release -o out`, runtime)
+
+	if analysis.Framework == analyzer.FrameworkBlazor {
+		return &LanguageTemplate{
+			BaseImage:  fmt.Sprintf("mcr.microsoft.com/dotnet/sdk:%s-alpine", runtime),
+			BuildStage: buildStage,
+			RuntimeStage: fmt.Sprintf(`# Runtime stage
+FROM nginxinc/nginx-unprivileged:alpine
+USER root
+
+# Serve the app, falling back to index.html for client-side routes
+RUN printf '%%s\n' \
+    'server {' \
+    '    listen %d;' \
+    '    root /usr/share/nginx/html;' \
+    '    location / { try_files $uri $uri/ /index.html; }' \
+    '}' > /etc/nginx/conf.d/default.conf && \
+    echo 'daemon off;' >> /etc/nginx/nginx.conf
+
+# Copy published static files
+COPY --from=builder /build/out/wwwroot /usr/share/nginx/html
+
+USER nginx`, port),
+			WorkDir:    "/usr/share/nginx/html",
+			RunCommand: "nginx",
+		}
+	}
+
+	// Console apps need only the .NET runtime and serve no requests
+	runtimeImage := "aspnet"
+	env := fmt.Sprintf("\n\n# Listen on the container port\nENV ASPNETCORE_URLS=http://+:%d", port)
+	if analysis.Framework == analyzer.FrameworkConsole {
+		runtimeImage = "runtime"
+		env = ""
+	}
+
+	return &LanguageTemplate{
+		BaseImage:  fmt.Sprintf("mcr.microsoft.com/dotnet/sdk:%s-alpine", runtime),
+		BuildStage: buildStage,
 		RuntimeStage: fmt.Sprintf(`# Runtime stage
-FROM mcr.microsoft.com/dotnet/aspnet:%s-alpine
-WORKDIR /app
+FROM mcr.microsoft.com/dotnet/%s:%s-alpine
+WORKDIR /app%s
 
 # Create non-root user
 RUN addgroup -g 1000 appuser && \
@@ -463,9 +641,135 @@ COPY --from=builder /build/out .
 # Change ownership
 RUN chown -R appuser:appuser /app
 
-USER appuser`, runtime),
+USER appuser`, runtimeImage, runtime, env),
+		WorkDir:    "/app",
+		RunCommand: startCmd,
+		NoPort:     analysis.Framework == analyzer.FrameworkConsole,
+	}
+}
+
+// getDenoTemplate returns Dockerfile for Deno. Deno has no install or build step, so the image is
+// single-stage: the source is copied and its remote modules cached for the deno user.
+func getDenoTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
+	startCmd := analysis.StartCommand
+	if startCmd == "" {
+		startCmd = "deno run --allow-net main.ts"
+	}
+
+	runtimeStage := `# Runtime stage
+FROM denoland/deno:alpine
+WORKDIR /app
+
+# Copy source code, owned by the image's non-root deno user
+COPY --chown=deno:deno . .
+
+USER deno`
+
+	// A deno run command names the module to start, the first argument after its flags, so
+	// its remote modules can be downloaded at build time rather than on every start
+	if fields := strings.Fields(startCmd); len(fields) > 2 && fields[0] == "deno" && fields[1] == "run" {
+		for _, field := range fields[2:] {
+			if !strings.HasPrefix(field, "-") {
+				runtimeStage += fmt.Sprintf("\n\n# Download and compile remote modules\nRUN deno cache %s", field)
+				break
+			}
+		}
+	}
+
+	return &LanguageTemplate{
+		BaseImage:    "denoland/deno:alpine",
+		RuntimeStage: runtimeStage,
+		WorkDir:      "/app",
+		RunCommand:   startCmd,
+		Entrypoint:   true,
+	}
+}
+
+// elixirImage pins the hexpm/elixir image an Elixir version is built with: the Elixir patch
+// release, the Erlang/OTP release it runs on and the Alpine release, which the runtime stage
+// must match since a mix release bundles the Erlang runtime built against it
+type elixirImage struct {
+	elixir string
+	otp    string
+	alpine string
+}
+
+// elixirImages are the images of the supported Elixir versions
+var elixirImages = map[string]elixirImage{
+	"1.14": {elixir: "1.14.5", otp: "25.3.2.15", alpine: "3.20.3"},
+	"1.15": {elixir: "1.15.8", otp: "26.2.5.5", alpine: "3.20.3"},
+	"1.16": {elixir: "1.16.3", otp: "26.2.5.5", alpine: "3.20.3"},
+	"1.17": {elixir: "1.17.3", otp: "27.1.2", alpine: "3.20.3"},
+}
+
+// getElixirTemplate returns optimized multi-stage Dockerfile for Elixir. The app is built as a
+// mix release, with Phoenix static assets digested first, and run from the release on Alpine.
+func getElixirTemplate(analysis *analyzer.AnalysisResult) *LanguageTemplate {
+	image, ok := elixirImages[analysis.Runtime]
+	if !ok {
+		image = elixirImages["1.17"] // Default Elixir version
+	}
+
+	// The release is named after the app, the last argument of mix release
+	release := "app"
+	if fields := strings.Fields(analysis.BuildCommand); len(fields) == 3 && fields[0] == "mix" && fields[1] == "release" {
+		release = fields[2]
+	}
+
+	startCmd := analysis.StartCommand
+	if startCmd == "" {
+		startCmd = fmt.Sprintf("./bin/%s start", release)
+	}
+
+	buildCmd := "mix release " + release
+	serverEnv := ""
+	if analysis.Framework == analyzer.FrameworkPhoenix {
+		buildCmd = "mix phx.digest && " + buildCmd
+		// Phoenix endpoints only serve requests from a release with PHX_SERVER set
+		serverEnv = "\nENV PHX_SERVER=true"
+	}
+
+	baseImage := fmt.Sprintf("hexpm/elixir:%s-erlang-%s-alpine-%s", image.elixir, image.otp, image.alpine)
+
+	return &LanguageTemplate{
+		BaseImage: baseImage,
+		BuildStage: fmt.Sprintf(`# Build stage
+FROM %s AS builder
+WORKDIR /build
+
+# Install build dependencies
+RUN apk add --no-cache build-base git
+
+ENV MIX_ENV=prod
+RUN mix local.hex --force && mix local.rebar --force
+
+# Copy mix files and fetch dependencies
+COPY mix.exs mix.lock* ./
+RUN mix deps.get --only prod
+
+# Copy source code
+COPY . .
+
+# Build the release
+RUN mix deps.compile && %s`, baseImage, buildCmd),
+		RuntimeStage: fmt.Sprintf(`# Runtime stage
+FROM alpine:%s
+WORKDIR /app
+
+# Install the libraries the Erlang runtime links against
+RUN apk add --no-cache libstdc++ openssl ncurses-libs
+
+# Create non-root user
+RUN addgroup -g 1000 appuser && \
+    adduser -D -u 1000 -G appuser appuser
+
+# Copy release from builder
+COPY --from=builder --chown=appuser:appuser /build/_build/prod/rel/%s .
+
+USER appuser
+ENV MIX_ENV=prod%s`, image.alpine, release, serverEnv),
 		WorkDir:    "/app",
-		RunCommand: "dotnet app.dll",
+		RunCommand: startCmd,
 	}
 }
 
@@ -473,21 +777,27 @@ USER appuser`, runtime),
 func BuildDockerfileContent(template *LanguageTemplate, port int) string {
 	var builder strings.Builder
 
-	// Build stage
-	builder.WriteString(template.BuildStage)
-	builder.WriteString("\n\n")
+	// Build stage, absent for single-stage images
+	if template.BuildStage != "" {
+		builder.WriteString(template.BuildStage)
+		builder.WriteString("\n\n")
+	}
 
 	// Runtime stage
 	builder.WriteString(template.RuntimeStage)
 	builder.WriteString("\n\n")
 
 	// Expose port
-	if port > 0 {
+	if port > 0 && !template.NoPort {
 		builder.WriteString(fmt.Sprintf("EXPOSE %d\n\n", port))
 	}
 
 	// CMD
-	builder.WriteString(fmt.Sprintf("CMD [%s]\n", formatCmd(template.RunCommand)))
+	instruction := "CMD"
+	if template.Entrypoint {
+		instruction = "ENTRYPOINT"
+	}
+	builder.WriteString(fmt.Sprintf("%s [%s]\n", instruction, formatCmd(template.RunCommand)))
 
 	return builder.String()
 }
@@ -0,0 +1,187 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	dockerregistry "github.com/docker/docker/api/types/registry"
+
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// ErrNoCredentials is returned when there is no login for a registry
+var ErrNoCredentials = errors.New("no registry credentials")
+
+// tokenRefreshMargin is how long before expiry a GCP access token is replaced, so a push that
+// starts with it does not outlive it
+const tokenRefreshMargin = 5 * time.Minute
+
+// gcloudTokenLifetime is how long a gcloud access token is used. gcloud reports no expiry, and
+// its tokens last an hour.
+const gcloudTokenLifetime = 30 * time.Minute
+
+// CredentialProvider supplies the logins images are pushed to and pulled from registries with
+type CredentialProvider interface {
+	// GetAuthConfig returns the login for a registry host or an image reference on it
+	GetAuthConfig(registryURL string) (dockerregistry.AuthConfig, error)
+}
+
+// RegistryHost returns the host of a registry URL or image reference, e.g.
+// us-central1-docker.pkg.dev for us-central1-docker.pkg.dev/project/repo/app:v1
+func RegistryHost(registryURL string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(registryURL, "https://"), "http://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
+
+// isGCPRegistry reports whether host is Artifact Registry or Container Registry
+func isGCPRegistry(host string) bool {
+	return strings.HasSuffix(host, "-docker.pkg.dev") || host == "gcr.io" || strings.HasSuffix(host, ".gcr.io")
+}
+
+// GCPTokenProvider logs in to GCP registries with short-lived access tokens of the worker's
+// service account, from the metadata server on GCP or from gcloud elsewhere. Tokens are
+// refreshed before they expire, so long-running workers never push with a stale one.
+type GCPTokenProvider struct {
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewGCPTokenProvider creates a new GCP access token provider
+func NewGCPTokenProvider() *GCPTokenProvider {
+	return &GCPTokenProvider{}
+}
+
+// GetAuthConfig returns an oauth2accesstoken login for a GCP registry
+func (p *GCPTokenProvider) GetAuthConfig(registryURL string) (dockerregistry.AuthConfig, error) {
+	token, err := p.accessToken(context.Background())
+	if err != nil {
+		return dockerregistry.AuthConfig{}, err
+	}
+
+	return dockerregistry.AuthConfig{
+		Username:      "oauth2accesstoken",
+		Password:      token,
+		ServerAddress: RegistryHost(registryURL),
+	}, nil
+}
+
+// accessToken returns the cached token, fetching a new one when it is about to expire
+func (p *GCPTokenProvider) accessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Add(tokenRefreshMargin).Before(p.expiry) {
+		return p.token, nil
+	}
+
+	if metadata.OnGCEWithContext(ctx) {
+		body, err := metadata.GetWithContext(ctx, "instance/service-accounts/default/token")
+		if err != nil {
+			return "", fmt.Errorf("failed to get metadata server token: %w", err)
+		}
+
+		var token struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int    `json:"expires_in"` // Seconds
+		}
+		if err := json.Unmarshal([]byte(body), &token); err != nil {
+			return "", fmt.Errorf("failed to parse metadata server token: %w", err)
+		}
+
+		p.token = token.AccessToken
+		p.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		return p.token, nil
+	}
+
+	output, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	p.token = strings.TrimSpace(string(output))
+	p.expiry = time.Now().Add(gcloudTokenLifetime)
+	return p.token, nil
+}
+
+// StoredCredentialProvider logs in with the registry credentials stored through the API
+type StoredCredentialProvider struct {
+	repo   *state.Repository
+	cipher *secrets.Cipher
+}
+
+// NewStoredCredentialProvider creates a provider of the stored credentials, decrypted with cipher
+func NewStoredCredentialProvider(repo *state.Repository, cipher *secrets.Cipher) *StoredCredentialProvider {
+	return &StoredCredentialProvider{
+		repo:   repo,
+		cipher: cipher,
+	}
+}
+
+// GetAuthConfig returns the newest unexpired stored login for the registry
+func (p *StoredCredentialProvider) GetAuthConfig(registryURL string) (dockerregistry.AuthConfig, error) {
+	host := RegistryHost(registryURL)
+
+	credential, err := p.repo.GetActiveRegistryCredential(context.Background(), host)
+	if err != nil {
+		return dockerregistry.AuthConfig{}, err
+	}
+	if credential == nil {
+		return dockerregistry.AuthConfig{}, ErrNoCredentials
+	}
+
+	secret, err := p.cipher.Decrypt(credential.Secret)
+	if err != nil {
+		return dockerregistry.AuthConfig{}, fmt.Errorf("failed to decrypt registry credential: %w", err)
+	}
+
+	return dockerregistry.AuthConfig{
+		Username:      credential.Username,
+		Password:      secret,
+		ServerAddress: host,
+	}, nil
+}
+
+// credentialChain prefers stored logins and falls back to GCP access tokens for GCP registries
+type credentialChain struct {
+	stored *StoredCredentialProvider // nil when stored credentials cannot be decrypted
+	gcp    *GCPTokenProvider
+}
+
+// NewCredentialProvider creates the provider builds push with: the credentials stored for a
+// registry, or for GCP registries without any, the worker's own service account. Stored
+// credentials are unavailable when cipher is nil.
+func NewCredentialProvider(repo *state.Repository, cipher *secrets.Cipher) CredentialProvider {
+	chain := &credentialChain{gcp: NewGCPTokenProvider()}
+	if repo != nil && cipher != nil {
+		chain.stored = NewStoredCredentialProvider(repo, cipher)
+	}
+	return chain
+}
+
+// GetAuthConfig returns the stored login for the registry, or a GCP access token login
+func (c *credentialChain) GetAuthConfig(registryURL string) (dockerregistry.AuthConfig, error) {
+	if c.stored != nil {
+		auth, err := c.stored.GetAuthConfig(registryURL)
+		if !errors.Is(err, ErrNoCredentials) {
+			return auth, err
+		}
+	}
+
+	if isGCPRegistry(RegistryHost(registryURL)) {
+		return c.gcp.GetAuthConfig(registryURL)
+	}
+
+	return dockerregistry.AuthConfig{}, ErrNoCredentials
+}
@@ -20,6 +20,7 @@ type GCPArtifactRegistryClient struct {
 	config       Config
 	dockerClient *client.Client
 	authToken    string
+	credentials  CredentialProvider
 }
 
 // NewGCPArtifactRegistryClient creates a new GCP Artifact Registry client
@@ -30,9 +31,15 @@ func NewGCPArtifactRegistryClient(config Config) (*GCPArtifactRegistryClient, er
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
 
+	credentials := config.Credentials
+	if credentials == nil {
+		credentials = NewGCPTokenProvider()
+	}
+
 	return &GCPArtifactRegistryClient{
 		config:       config,
 		dockerClient: cli,
+		credentials:  credentials,
 	}, nil
 }
 
@@ -152,6 +159,16 @@ func (c *GCPArtifactRegistryClient) GetImageTag(appName, version string) string
 	)
 }
 
+// GetCacheRepository returns the Artifact Registry repository an app's build cache is stored in
+func (c *GCPArtifactRegistryClient) GetCacheRepository(appName string) string {
+	// Format: LOCATION-docker.pkg.dev/PROJECT/cache/IMAGE
+	return fmt.Sprintf("%s/%s/cache/%s",
+		c.getRegistryHost(),
+		c.config.Project,
+		strings.ToLower(appName),
+	)
+}
+
 // getRegistryHost returns the full registry host
 func (c *GCPArtifactRegistryClient) getRegistryHost() string {
 	if c.config.Host != "" {
@@ -166,20 +183,15 @@ func (c *GCPArtifactRegistryClient) getRegistryHost() string {
 func (c *GCPArtifactRegistryClient) Push(ctx context.Context, imageTag string) error {
 	log.Info().Str("imageTag", imageTag).Msg("Pushing image to GCP Artifact Registry")
 
-	// Ensure authentication
-	if c.authToken == "" {
-		if err := c.Authenticate(ctx); err != nil {
-			return err
+	// Access tokens expire within the hour, so a current login is requested every time
+	authConfig, err := c.credentials.GetAuthConfig(c.getRegistryHost())
+	if err != nil {
+		return ErrAuthenticationFailed{
+			Registry: c.getRegistryHost(),
+			Err:      err,
 		}
 	}
 
-	// Prepare auth config
-	authConfig := dockerregistry.AuthConfig{
-		Username:      "oauth2accesstoken",
-		Password:      c.authToken,
-		ServerAddress: c.getRegistryHost(),
-	}
-
 	encodedAuth, err := c.encodeAuthConfig(authConfig)
 	if err != nil {
 		return fmt.Errorf("failed to encode auth config: %w", err)
@@ -216,20 +228,15 @@ func (c *GCPArtifactRegistryClient) Push(ctx context.Context, imageTag string) e
 func (c *GCPArtifactRegistryClient) Pull(ctx context.Context, imageTag string) error {
 	log.Info().Str("imageTag", imageTag).Msg("Pulling image from GCP Artifact Registry")
 
-	// Ensure authentication
-	if c.authToken == "" {
-		if err := c.Authenticate(ctx); err != nil {
-			return err
+	// Access tokens expire within the hour, so a current login is requested every time
+	authConfig, err := c.credentials.GetAuthConfig(c.getRegistryHost())
+	if err != nil {
+		return ErrAuthenticationFailed{
+			Registry: c.getRegistryHost(),
+			Err:      err,
 		}
 	}
 
-	// Prepare auth config
-	authConfig := dockerregistry.AuthConfig{
-		Username:      "oauth2accesstoken",
-		Password:      c.authToken,
-		ServerAddress: c.getRegistryHost(),
-	}
-
 	encodedAuth, err := c.encodeAuthConfig(authConfig)
 	if err != nil {
 		return fmt.Errorf("failed to encode auth config: %w", err)
@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	iam "google.golang.org/api/iam/v1"
+
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// JSONKeyUsername is the username registries are logged in to with a service account key file
+const JSONKeyUsername = "_json_key"
+
+// ErrNotRotatable is returned when rotating a credential that is not a service account key
+var ErrNotRotatable = errors.New("only service account key credentials can be rotated")
+
+// serviceAccountKey is the part of a service account key file rotation needs
+type serviceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+}
+
+// RotateCredential replaces the service account key of a _json_key credential with a new one
+// created through the IAM API, then deletes the old key. The API server or worker's own
+// service account needs iam.serviceAccountKeys.create and delete on the key's account.
+func RotateCredential(ctx context.Context, repo *state.Repository, cipher *secrets.Cipher, credential *state.RegistryCredential) error {
+	if credential.Username != JSONKeyUsername {
+		return ErrNotRotatable
+	}
+
+	current, err := cipher.Decrypt(credential.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt registry credential: %w", err)
+	}
+
+	var old serviceAccountKey
+	if err := json.Unmarshal([]byte(current), &old); err != nil || old.ClientEmail == "" {
+		return fmt.Errorf("registry credential is not a service account key file")
+	}
+
+	iamSvc, err := iam.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create IAM client: %w", err)
+	}
+
+	account := "projects/-/serviceAccounts/" + old.ClientEmail
+	key, err := iamSvc.Projects.ServiceAccounts.Keys.Create(account, &iam.CreateServiceAccountKeyRequest{}).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return fmt.Errorf("failed to create service account key: %w", err)
+	}
+
+	keyFile, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
+	if err != nil {
+		return fmt.Errorf("failed to decode service account key: %w", err)
+	}
+
+	encrypted, err := cipher.Encrypt(string(keyFile))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt service account key: %w", err)
+	}
+
+	now := time.Now()
+	credential.Secret = encrypted
+	credential.ExpiresAt = keyExpiry(key.ValidBeforeTime)
+	credential.RotatedAt = &now
+
+	if err := repo.UpdateRegistryCredential(ctx, credential); err != nil {
+		return err
+	}
+
+	// The new key is saved, so a key left behind only needs cleaning up by hand
+	if old.PrivateKeyID != "" {
+		if _, err := iamSvc.Projects.ServiceAccounts.Keys.Delete(account + "/keys/" + old.PrivateKeyID).Context(ctx).Do(); err != nil {
+			log.Warn().
+				Err(err).
+				Str("service_account", old.ClientEmail).
+				Str("key_id", old.PrivateKeyID).
+				Msg("Failed to delete rotated service account key")
+		}
+	}
+
+	log.Info().
+		Str("credential_id", credential.ID.String()).
+		Str("service_account", old.ClientEmail).
+		Msg("Registry credential rotated")
+
+	return nil
+}
+
+// keyExpiry parses a key's validBeforeTime, returning nil for keys that never expire, which
+// IAM reports as valid until the year 9999
+func keyExpiry(validBefore string) *time.Time {
+	t, err := time.Parse(time.RFC3339, validBefore)
+	if err != nil || t.Year() >= 9999 {
+		return nil
+	}
+	return &t
+}
@@ -8,6 +8,9 @@ type Config struct {
 	Host     string // e.g., us-central1-docker.pkg.dev
 	Project  string // GCP project or equivalent
 	Location string // Region or location
+
+	// Logins pushes and pulls use; nil uses access tokens of the worker's GCP service account
+	Credentials CredentialProvider
 }
 
 // Client handles container registry operations
@@ -22,6 +25,10 @@ type Client interface {
 	// Format: registry.host/project/app:version
 	GetImageTag(appName, version string) string
 
+	// GetCacheRepository returns the repository an app's build layer cache is pushed to
+	// Format: registry.host/project/cache/app
+	GetCacheRepository(appName string) string
+
 	// Authenticate authenticates with the registry
 	Authenticate(ctx context.Context) error
 
@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SBOM formats supported by syft
+const (
+	SBOMFormatSPDX      = "spdx-json"
+	SBOMFormatCycloneDX = "cyclonedx-json"
+)
+
+// SBOMConfig holds configuration for SBOM generation
+type SBOMConfig struct {
+	BucketName  string // GCS bucket SBOMs are stored in; empty disables generation
+	Format      string // spdx-json (default) or cyclonedx-json
+	SignerEmail string // Service account signing download URLs, metadata server default when empty
+}
+
+// SBOMResult describes a generated and stored SBOM
+type SBOMResult struct {
+	Path   string // gs:// path of the stored document
+	Format string
+}
+
+// SBOMGenerator produces SBOMs for built images with syft and stores them in GCS
+type SBOMGenerator struct {
+	store  *ArtifactStore
+	format string
+}
+
+// NewSBOMGenerator creates a new SBOM generator. The syft CLI must be installed.
+func NewSBOMGenerator(store *ArtifactStore, format string) (*SBOMGenerator, error) {
+	if _, err := exec.LookPath("syft"); err != nil {
+		return nil, fmt.Errorf("syft CLI not found: %w (please install syft)", err)
+	}
+
+	switch format {
+	case "":
+		format = SBOMFormatSPDX
+	case SBOMFormatSPDX, SBOMFormatCycloneDX:
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format: %s", format)
+	}
+
+	return &SBOMGenerator{
+		store:  store,
+		format: format,
+	}, nil
+}
+
+// Generate scans a local image and uploads the SBOM to sboms/{deployment-id}/{build-id}
+func (g *SBOMGenerator) Generate(ctx context.Context, imageTag, deploymentID, buildID string) (*SBOMResult, error) {
+	tmpDir, err := os.MkdirTemp("", "sbom-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	outputFile := filepath.Join(tmpDir, "sbom.json")
+
+	// Read the image straight from the local daemon rather than pulling it from the registry
+	cmd := exec.CommandContext(ctx, "syft", "docker:"+imageTag, "-o", fmt.Sprintf("%s=%s", g.format, outputFile), "-q")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("syft failed: %w, output: %s", err, string(output))
+	}
+
+	file, err := os.Open(outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SBOM: %w", err)
+	}
+	defer file.Close()
+
+	object := fmt.Sprintf("sboms/%s/%s%s", deploymentID, buildID, sbomExtension(g.format))
+	path, err := g.store.Upload(ctx, object, "application/json", file)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("imageTag", imageTag).
+		Str("path", path).
+		Str("format", g.format).
+		Msg("SBOM generated")
+
+	return &SBOMResult{
+		Path:   path,
+		Format: g.format,
+	}, nil
+}
+
+// sbomExtension returns the conventional file extension for an SBOM format
+func sbomExtension(format string) string {
+	if format == SBOMFormatCycloneDX {
+		return ".cdx.json"
+	}
+	return ".spdx.json"
+}
@@ -0,0 +1,74 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// ScanConfig holds configuration for image vulnerability scanning
+type ScanConfig struct {
+	Enabled bool   // Scan pushed images with trivy
+	FailOn  string // Lowest severity that fails a scan: CRITICAL (default), HIGH, MEDIUM or LOW
+}
+
+// scanImages scans the deployment's sidecar images and then the pushed image, so the pushed
+// image's scan stays the build's latest. The build's scan fails when any of them does.
+func (s *Service) scanImages(ctx context.Context, buildCtx *BuildContext, imageTag string) string {
+	if s.scanner == nil {
+		return state.ScanStatusSkipped
+	}
+
+	sidecarsFailed := false
+	images, err := s.tracker.SidecarImages(ctx, buildCtx.DeploymentID)
+	if err != nil {
+		log.Warn().Err(err).Str("buildID", buildCtx.BuildID).Msg("Failed to load sidecar images, skipping their scans")
+	}
+	for _, image := range images {
+		if s.scanImage(ctx, buildCtx, image) == state.ScanStatusFailed {
+			sidecarsFailed = true
+		}
+	}
+
+	status := s.scanImage(ctx, buildCtx, imageTag)
+	if sidecarsFailed {
+		return state.ScanStatusFailed
+	}
+	return status
+}
+
+// scanImage scans an image for vulnerabilities, stores the scan and returns the
+// build's scan status. Scanner errors skip the scan.
+func (s *Service) scanImage(ctx context.Context, buildCtx *BuildContext, imageTag string) string {
+	if s.scanner == nil {
+		return state.ScanStatusSkipped
+	}
+
+	// Without the allowlist accepted risks would fail scans, so the scan is skipped
+	suppressed, err := s.tracker.SuppressedCVEs(ctx, buildCtx.DeploymentID)
+	if err != nil {
+		log.Warn().Err(err).Str("buildID", buildCtx.BuildID).Msg("Failed to load CVE suppressions, skipping scan")
+		return state.ScanStatusSkipped
+	}
+
+	scan, err := s.scanner.Scan(ctx, imageTag, suppressed)
+	if err != nil {
+		log.Warn().Err(err).Str("buildID", buildCtx.BuildID).Msg("Failed to scan image")
+		return state.ScanStatusSkipped
+	}
+
+	if err := s.tracker.RecordScan(ctx, buildCtx.BuildID, scan); err != nil {
+		log.Warn().Err(err).Str("buildID", buildCtx.BuildID).Msg("Failed to record vulnerability scan")
+	}
+
+	_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, fmt.Sprintf("Vulnerability scan of %s: %d critical, %d high, %d medium, %d low, %d suppressed\n",
+		imageTag, scan.CriticalCount, scan.HighCount, scan.MediumCount, scan.LowCount, scan.SuppressedCount))
+
+	if !scan.Passed {
+		return state.ScanStatusFailed
+	}
+	return state.ScanStatusPassed
+}
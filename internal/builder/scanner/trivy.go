@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Vulnerability severities reported by trivy, most severe first
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+)
+
+// severityRank orders severities so thresholds can be compared; unknown ranks lowest
+var severityRank = map[string]int{
+	SeverityCritical: 4,
+	SeverityHigh:     3,
+	SeverityMedium:   2,
+	SeverityLow:      1,
+}
+
+// Vulnerability is a single CVE found in an image
+type Vulnerability struct {
+	ID               string `json:"id"`
+	Package          string `json:"package"`
+	InstalledVersion string `json:"installed_version"`
+	FixedVersion     string `json:"fixed_version,omitempty"`
+	Severity         string `json:"severity"`
+	Title            string `json:"title,omitempty"`
+}
+
+// ScanResult is the outcome of scanning an image
+type ScanResult struct {
+	ImageTag        string
+	ScanTime        time.Time
+	Passed          bool // No vulnerability at or above the fail-on severity
+	CriticalCount   int
+	HighCount       int
+	MediumCount     int
+	LowCount        int
+	Vulnerabilities []Vulnerability // Counted vulnerabilities, suppressed ones excluded
+	SuppressedCount int             // Findings of suppressed vulnerabilities
+	SuppressedVulns []string        // Distinct IDs of the suppressed vulnerabilities found
+	Raw             json.RawMessage // trivy's JSON report
+}
+
+// trivyReport is the part of trivy's JSON report the result is built from
+type trivyReport struct {
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// TrivyScanner scans built images for known vulnerabilities with the trivy CLI
+type TrivyScanner struct {
+	failOn string
+}
+
+// NewTrivyScanner creates a new scanner. Scans fail when a vulnerability at or above failOn
+// is found; an empty failOn fails on CRITICAL. The trivy CLI must be installed.
+func NewTrivyScanner(failOn string) (*TrivyScanner, error) {
+	if _, err := exec.LookPath("trivy"); err != nil {
+		return nil, fmt.Errorf("trivy CLI not found: %w (please install trivy)", err)
+	}
+
+	failOn = strings.ToUpper(failOn)
+	if failOn == "" {
+		failOn = SeverityCritical
+	}
+	if _, ok := severityRank[failOn]; !ok {
+		return nil, fmt.Errorf("unsupported fail-on severity: %s", failOn)
+	}
+
+	return &TrivyScanner{
+		failOn: failOn,
+	}, nil
+}
+
+// Scan scans an image, read from the local Docker daemon when it is there and from its
+// registry otherwise. Vulnerabilities whose ID is in suppressed are accepted risks; they are
+// reported as suppressed and neither counted nor fail the scan.
+func (s *TrivyScanner) Scan(ctx context.Context, imageTag string, suppressed []string) (*ScanResult, error) {
+	cmd := exec.CommandContext(ctx, "trivy", "image", "--format", "json", "--quiet", imageTag)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("trivy failed: %w, output: %s", err, string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("trivy failed: %w", err)
+	}
+
+	var report trivyReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+
+	result := &ScanResult{
+		ImageTag: imageTag,
+		ScanTime: time.Now(),
+		Raw:      json.RawMessage(output),
+	}
+
+	for _, target := range report.Results {
+		for _, v := range target.Vulnerabilities {
+			result.Vulnerabilities = append(result.Vulnerabilities, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         strings.ToUpper(v.Severity),
+				Title:            v.Title,
+			})
+		}
+	}
+
+	s.checkThresholds(result, suppressed)
+
+	log.Info().
+		Str("imageTag", imageTag).
+		Int("critical", result.CriticalCount).
+		Int("high", result.HighCount).
+		Int("suppressed", result.SuppressedCount).
+		Bool("passed", result.Passed).
+		Msg("Image scanned for vulnerabilities")
+
+	return result, nil
+}
+
+// checkThresholds sets suppressed vulnerabilities aside, counts the rest by severity and
+// decides whether the image passes
+func (s *TrivyScanner) checkThresholds(result *ScanResult, suppressed []string) {
+	result.CriticalCount, result.HighCount, result.MediumCount, result.LowCount = 0, 0, 0, 0
+	result.Passed = true
+
+	accepted := make(map[string]bool, len(suppressed))
+	for _, id := range suppressed {
+		accepted[id] = true
+	}
+
+	counted := result.Vulnerabilities[:0]
+	seen := make(map[string]bool)
+	for _, v := range result.Vulnerabilities {
+		if !accepted[v.ID] {
+			counted = append(counted, v)
+			continue
+		}
+		result.SuppressedCount++
+		if !seen[v.ID] {
+			seen[v.ID] = true
+			result.SuppressedVulns = append(result.SuppressedVulns, v.ID)
+		}
+	}
+	result.Vulnerabilities = counted
+
+	for _, v := range result.Vulnerabilities {
+		switch v.Severity {
+		case SeverityCritical:
+			result.CriticalCount++
+		case SeverityHigh:
+			result.HighCount++
+		case SeverityMedium:
+			result.MediumCount++
+		case SeverityLow:
+			result.LowCount++
+		}
+
+		if severityRank[v.Severity] >= severityRank[s.failOn] {
+			result.Passed = false
+		}
+	}
+}
+
+// ValidSeverity reports whether severity is one trivy reports
+func ValidSeverity(severity string) bool {
+	_, ok := severityRank[severity]
+	return ok
+}
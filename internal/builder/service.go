@@ -10,21 +10,39 @@ import (
 
 	"github.com/alvesdmateus/app-deployer/internal/builder/dockerfile"
 	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
+	"github.com/alvesdmateus/app-deployer/internal/builder/scanner"
+	"github.com/alvesdmateus/app-deployer/internal/builder/signing"
 	"github.com/alvesdmateus/app-deployer/internal/builder/strategies"
+	"github.com/alvesdmateus/app-deployer/internal/security"
 )
 
 // Service implements BuildService interface
 type Service struct {
 	dockerfileGenerator DockerfileGenerator
 	buildStrategy       BuildStrategy
+	kaniko              *strategies.KanikoStrategy // Optional, nil when in-cluster builds are disabled
 	registryClient      RegistryClient
 	tracker             BuildTracker
+	cache               *BuildCacheManager    // Optional, nil when no cache bucket is configured
+	sbom                *SBOMGenerator        // Optional, nil when no SBOM bucket is configured
+	scanner             *scanner.TrivyScanner // Optional, nil when vulnerability scanning is disabled
+	signingKeyRef       string                // Cosign key images are signed with, empty disables signing
+	attestor            string                // Binary Authorization attestor signed images are attested for, empty disables attestations
+	registryCache       bool                  // Cache builder stages in the registry's cache repository
+	credentials         registry.CredentialProvider
 }
 
 // ServiceConfig contains configuration for the build service
 type ServiceConfig struct {
 	RegistryConfig registry.Config
 	StrategyType   strategies.StrategyType
+	CacheConfig    BuildCacheConfig
+	SBOMConfig     SBOMConfig
+	ScanConfig     ScanConfig
+	SigningKeyRef  string                   // Cosign key reference, e.g. gcpkms://projects/p/locations/l/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1
+	Attestor       string                   // Binary Authorization attestor, e.g. projects/p/attestors/built-by-app-deployer
+	Kaniko         *strategies.KanikoConfig // Enables in-cluster Kaniko builds, nil disables them
+	RegistryCache  bool                     // Cache builder stages in the registry, e.g. for workers without the GCS cache
 }
 
 // NewService creates a new build service
@@ -33,12 +51,30 @@ func NewService(config ServiceConfig, tracker BuildTracker) (*Service, error) {
 	generator := dockerfile.NewGenerator()
 
 	// Create build strategy
-	strategyFactory := strategies.NewStrategyFactory()
+	strategyFactory := strategies.NewStrategyFactory(config.RegistryConfig.Credentials)
 	strategy, err := strategyFactory.CreateStrategy(config.StrategyType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create build strategy: %w", err)
 	}
 
+	// Create the in-cluster strategy; builds that select it fail without it
+	var kaniko *strategies.KanikoStrategy
+	if config.Kaniko != nil {
+		kaniko, err = strategies.NewKanikoStrategy(*config.Kaniko)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to initialize Kaniko builds, in-cluster builds disabled")
+		}
+	}
+
+	// Create vulnerability scanner; builds go unscanned without it
+	var trivy *scanner.TrivyScanner
+	if config.ScanConfig.Enabled {
+		trivy, err = scanner.NewTrivyScanner(config.ScanConfig.FailOn)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to initialize vulnerability scanning, builds will not be scanned")
+		}
+	}
+
 	// Create registry client
 	registryFactory := registry.NewClientFactory()
 	registryClient, err := registryFactory.CreateClient(config.RegistryConfig)
@@ -46,11 +82,37 @@ func NewService(config ServiceConfig, tracker BuildTracker) (*Service, error) {
 		return nil, fmt.Errorf("failed to create registry client: %w", err)
 	}
 
+	// Create build cache; builds still work without it, just slower
+	var cache *BuildCacheManager
+	if config.CacheConfig.BucketName != "" {
+		cache, err = NewBuildCacheManager(context.Background(), config.CacheConfig)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to initialize build cache, builds will not be cached")
+		}
+	}
+
+	// Create SBOM generator; SBOMs are best-effort and never block a build
+	var sbom *SBOMGenerator
+	if config.SBOMConfig.BucketName != "" {
+		sbom, err = newSBOMGenerator(context.Background(), config.SBOMConfig)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to initialize SBOM generation, builds will not produce SBOMs")
+		}
+	}
+
 	return &Service{
 		dockerfileGenerator: generator,
 		buildStrategy:       strategy,
+		kaniko:              kaniko,
 		registryClient:      registryClient,
 		tracker:             tracker,
+		cache:               cache,
+		sbom:                sbom,
+		scanner:             trivy,
+		signingKeyRef:       config.SigningKeyRef,
+		attestor:            config.Attestor,
+		registryCache:       config.RegistryCache,
+		credentials:         config.RegistryConfig.Credentials,
 	}, nil
 }
 
@@ -88,28 +150,56 @@ func (s *Service) BuildImage(ctx context.Context, buildCtx *BuildContext) (*Buil
 		}
 	}()
 
-	// Step 2: Generate optimized Dockerfile
-	log.Info().
-		Str("language", string(buildCtx.Analysis.Language)).
-		Str("framework", string(buildCtx.Analysis.Framework)).
-		Msg("Generating Dockerfile")
-
-	dockerfileContent, err := s.dockerfileGenerator.Generate(ctx, buildCtx.Analysis)
+	strategy, err := s.strategyFor(buildCtx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate Dockerfile: %w", err)
+		return nil, fmt.Errorf("failed to select build strategy: %w", err)
+	}
+
+	// Step 2: Generate optimized Dockerfile, unless the source's own is built
+	var dockerfileContent, progressMsg, cacheKey string
+	var cacheHit bool
+	if usesSourceDockerfile(strategy, buildCtx) {
+		progressMsg = "Building from the source's Dockerfile\n"
+	} else {
+		log.Info().
+			Str("language", string(buildCtx.Analysis.Language)).
+			Str("framework", string(buildCtx.Analysis.Framework)).
+			Msg("Generating Dockerfile")
+
+		dockerfileContent, err = s.dockerfileGenerator.Generate(ctx, buildCtx.Analysis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Dockerfile: %w", err)
+		}
+
+		progressMsg = fmt.Sprintf("Generated Dockerfile for %s application\n", buildCtx.Analysis.Language)
 	}
 
 	// Update build progress
-	progressMsg := fmt.Sprintf("Generated Dockerfile for %s application\n", buildCtx.Analysis.Language)
 	_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, progressMsg)
 
+	// Restore the builder stage from the cache when dependencies are unchanged. Only generated
+	// Dockerfiles are known to have a builder stage.
+	if dockerfileContent != "" {
+		cacheKey, cacheHit = s.restoreCache(ctx, buildCtx, dockerfileContent)
+	}
+
+	// Otherwise seed the build from the builder stage last pushed to the registry
+	if !cacheHit {
+		s.pullRegistryCache(ctx, buildCtx, strategy, dockerfileContent)
+	}
+
+	// Strategies that push the image themselves push it straight to the registry tag
+	registryTag := s.registryClient.GetImageTag(buildCtx.AppName, buildCtx.Version)
+	buildCtx.Destination = registryTag
+	pushed := strategy.Name() == string(strategies.StrategyTypeKaniko)
+
 	// Step 3: Build container image
 	log.Info().
 		Str("buildID", buildCtx.BuildID).
-		Str("strategy", s.buildStrategy.Name()).
+		Str("strategy", strategy.Name()).
 		Msg("Building container image")
 
-	result, err := s.buildStrategy.Build(ctx, buildCtx, dockerfileContent)
+	result, err := strategy.Build(ctx, buildCtx, dockerfileContent)
 	if err != nil {
 		result.BuildLog += fmt.Sprintf("\nBuild failed: %v\n", err)
 		_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, result.BuildLog)
@@ -125,38 +215,69 @@ func (s *Service) BuildImage(ctx context.Context, buildCtx *BuildContext) (*Buil
 	progressMsg = fmt.Sprintf("Container image built successfully: %s\n", result.ImageTag)
 	_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, progressMsg)
 
-	// Step 4: Tag image for registry
-	registryTag := s.registryClient.GetImageTag(buildCtx.AppName, buildCtx.Version)
-
-	log.Info().
-		Str("sourceTag", result.ImageTag).
-		Str("registryTag", registryTag).
-		Msg("Tagging image for registry")
+	result.CacheKey = cacheKey
+	if cacheKey != "" && !cacheHit {
+		s.saveCache(ctx, buildCtx, dockerfileContent, cacheKey)
+	}
+	if !pushed {
+		s.pushRegistryCache(ctx, buildCtx, dockerfileContent)
+	}
 
-	// Tag the local image with the registry tag
-	if dockerStrategy, ok := s.buildStrategy.(*strategies.DockerStrategy); ok {
-		if err := dockerStrategy.TagImage(ctx, result.ImageTag, registryTag); err != nil {
-			return nil, fmt.Errorf("failed to tag image: %w", err)
+	if !pushed {
+		// Step 4: Tag image for registry
+		log.Info().
+			Str("sourceTag", result.ImageTag).
+			Str("registryTag", registryTag).
+			Msg("Tagging image for registry")
+
+		// Tag the local image with the registry tag
+		if dockerStrategy, ok := s.buildStrategy.(*strategies.DockerStrategy); ok {
+			if err := dockerStrategy.TagImage(ctx, result.ImageTag, registryTag); err != nil {
+				return nil, fmt.Errorf("failed to tag image: %w", err)
+			}
 		}
-	}
 
-	result.ImageTag = registryTag
+		result.ImageTag = registryTag
 
-	// Step 5: Push to registry
-	log.Info().
-		Str("imageTag", registryTag).
-		Msg("Pushing image to registry")
+		// Step 5: Push to registry
+		log.Info().
+			Str("imageTag", registryTag).
+			Msg("Pushing image to registry")
 
-	progressMsg = fmt.Sprintf("Pushing image to registry: %s\n", registryTag)
-	_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, progressMsg)
+		progressMsg = fmt.Sprintf("Pushing image to registry: %s\n", registryTag)
+		_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, progressMsg)
 
-	if err := s.registryClient.Push(ctx, registryTag); err != nil {
-		return nil, fmt.Errorf("failed to push image to registry: %w", err)
+		if err := s.registryClient.Push(ctx, registryTag); err != nil {
+			return nil, fmt.Errorf("failed to push image to registry: %w", err)
+		}
 	}
 
 	progressMsg = "Image pushed successfully to registry\n"
 	_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, progressMsg)
 
+	// Sign the pushed image; with a key configured, an unsigned image is a failed build
+	if s.signingKeyRef != "" {
+		if err = s.signImage(ctx, result, pushed); err != nil {
+			return nil, err
+		}
+		_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, fmt.Sprintf("Image signed: %s\n", result.SignatureRef))
+	}
+
+	// Record what went into the image
+	if s.sbom != nil {
+		sbom, err := s.sbom.Generate(ctx, registryTag, buildCtx.DeploymentID, buildCtx.BuildID)
+		if err != nil {
+			log.Warn().Err(err).Str("buildID", buildCtx.BuildID).Msg("Failed to generate SBOM")
+		} else {
+			result.SBOMPath = sbom.Path
+			result.SBOMFormat = sbom.Format
+			_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, fmt.Sprintf("SBOM stored at %s\n", sbom.Path))
+		}
+	}
+
+	// Scan the image and its sidecars; a failed scan is recorded on the build, not treated as a failed build
+	result.ScanStatus = s.scanImages(ctx, buildCtx, registryTag)
+
 	// Step 6: Complete build tracking
 	if err := s.tracker.CompleteBuild(ctx, buildCtx.BuildID, result); err != nil {
 		log.Error().
@@ -175,6 +296,212 @@ func (s *Service) BuildImage(ctx context.Context, buildCtx *BuildContext) (*Buil
 	return result, nil
 }
 
+// strategyFor returns the strategy a build runs with: the one chosen for it, or the service's
+// own when none was. Existing Dockerfiles are built on the service's Docker daemon.
+func (s *Service) strategyFor(buildCtx *BuildContext) (BuildStrategy, error) {
+	strategyType := strategies.StrategyType(buildCtx.Strategy)
+
+	switch strategyType {
+	case "", strategies.StrategyType(s.buildStrategy.Name()):
+		return s.buildStrategy, nil
+	case strategies.StrategyTypeGenerated:
+		if _, ok := s.buildStrategy.(*strategies.DockerStrategy); ok {
+			return s.buildStrategy, nil
+		}
+	case strategies.StrategyTypeExisting:
+		if docker, ok := s.buildStrategy.(*strategies.DockerStrategy); ok {
+			return strategies.NewExistingStrategy(docker), nil
+		}
+	case strategies.StrategyTypeKaniko:
+		if s.kaniko == nil {
+			return nil, fmt.Errorf("kaniko builds are not enabled on this worker")
+		}
+		return s.kaniko, nil
+	}
+
+	return strategies.NewStrategyFactory(s.credentials).CreateStrategy(strategyType)
+}
+
+// usesSourceDockerfile reports whether a build uses the source's own Dockerfile rather than
+// a generated one. Kaniko builds use it when the source has one.
+func usesSourceDockerfile(strategy BuildStrategy, buildCtx *BuildContext) bool {
+	switch strategies.StrategyType(strategy.Name()) {
+	case strategies.StrategyTypeExisting:
+		return true
+	case strategies.StrategyTypeKaniko:
+		return strategies.FindDockerfile(buildCtx.ContextDir()) != ""
+	}
+	return false
+}
+
+// signImage signs the pushed image by digest and records the signature on the result. Images
+// pushed by their build strategy carry the registry digest on the result; others are looked
+// up in the local Docker daemon.
+func (s *Service) signImage(ctx context.Context, result *BuildResult, pushed bool) error {
+	var digest string
+	if pushed {
+		digest = strategies.DigestRef(result.ImageTag, result.ImageDigest)
+	} else {
+		dockerStrategy, ok := s.buildStrategy.(*strategies.DockerStrategy)
+		if !ok {
+			return fmt.Errorf("image signing requires the docker build strategy")
+		}
+
+		var err error
+		digest, err = dockerStrategy.RepoDigest(ctx, result.ImageTag)
+		if err != nil {
+			return fmt.Errorf("failed to resolve image digest: %w", err)
+		}
+	}
+
+	if err := signing.Sign(ctx, digest, s.signingKeyRef); err != nil {
+		return fmt.Errorf("failed to sign image: %w", err)
+	}
+
+	// Clusters enforcing Binary Authorization admit the image on the attestor's attestation
+	if s.attestor != "" {
+		if err := security.CreateAttestation(ctx, s.attestor, digest, s.signingKeyRef); err != nil {
+			return fmt.Errorf("failed to attest image: %w", err)
+		}
+	}
+
+	result.Signed = true
+	result.SignatureRef = signing.SignatureRef(digest)
+
+	return nil
+}
+
+// newSBOMGenerator creates the artifact store and SBOM generator for a config
+func newSBOMGenerator(ctx context.Context, config SBOMConfig) (*SBOMGenerator, error) {
+	store, err := NewArtifactStore(ctx, config.BucketName, config.SignerEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSBOMGenerator(store, config.Format)
+}
+
+// restoreCache loads the cached builder stage for this build, if one exists, and points the
+// build at it. Cache failures are logged and never fail the build.
+func (s *Service) restoreCache(ctx context.Context, buildCtx *BuildContext, dockerfileContent string) (key string, hit bool) {
+	dockerStrategy, ok := s.buildStrategy.(*strategies.DockerStrategy)
+	if s.cache == nil || !ok {
+		return "", false
+	}
+
+	key, err := s.cache.CacheKey(buildCtx.ContextDir(), dockerfileContent)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to compute build cache key")
+		return "", false
+	}
+
+	hit, err = s.cache.Restore(ctx, key, func(r io.Reader) error {
+		return dockerStrategy.LoadImage(ctx, r)
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Failed to restore build cache")
+		return key, false
+	}
+
+	if hit {
+		buildCtx.CacheImage = s.cache.ImageTag(key)
+		_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, fmt.Sprintf("Restored build cache %s\n", key))
+	}
+
+	log.Info().
+		Str("key", key).
+		Bool("hit", hit).
+		Msg("Build cache lookup completed")
+
+	return key, hit
+}
+
+// saveCache exports the builder stage of the Dockerfile and uploads it under key
+func (s *Service) saveCache(ctx context.Context, buildCtx *BuildContext, dockerfileContent, key string) {
+	dockerStrategy, ok := s.buildStrategy.(*strategies.DockerStrategy)
+	if s.cache == nil || !ok {
+		return
+	}
+
+	cacheTag := s.cache.ImageTag(key)
+
+	// The stage was just built, so this is served entirely from the local layer cache
+	if err := dockerStrategy.BuildStage(ctx, buildCtx, dockerfileContent, "builder", cacheTag); err != nil {
+		log.Warn().Err(err).Msg("Failed to build cache stage, skipping cache upload")
+		return
+	}
+
+	image, err := dockerStrategy.SaveImage(ctx, cacheTag)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to export cache stage")
+		return
+	}
+	defer image.Close()
+
+	if err := s.cache.Save(ctx, key, image); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Failed to upload build cache")
+		return
+	}
+
+	log.Info().Str("key", key).Msg("Build cache saved")
+}
+
+// registryCacheImage returns the tag an app's builder stage is cached under in the registry
+func (s *Service) registryCacheImage(appName string) string {
+	return s.registryClient.GetCacheRepository(appName) + ":builder-cache"
+}
+
+// pullRegistryCache points the build at the registry's layer cache. Kaniko reads the cache
+// repository itself; Docker builds pull the cached builder stage to use as a cache source.
+// A missing cache, as on an app's first build, is not an error.
+func (s *Service) pullRegistryCache(ctx context.Context, buildCtx *BuildContext, strategy BuildStrategy, dockerfileContent string) {
+	if !s.registryCache {
+		return
+	}
+
+	if strategy.Name() == string(strategies.StrategyTypeKaniko) {
+		buildCtx.CacheRepo = s.registryClient.GetCacheRepository(buildCtx.AppName)
+		return
+	}
+
+	if _, ok := s.buildStrategy.(*strategies.DockerStrategy); !ok || !strings.Contains(dockerfileContent, " AS builder") {
+		return
+	}
+
+	cacheImage := s.registryCacheImage(buildCtx.AppName)
+	if err := s.registryClient.Pull(ctx, cacheImage); err != nil {
+		log.Info().Err(err).Str("cacheImage", cacheImage).Msg("No registry build cache, building without it")
+		return
+	}
+
+	buildCtx.CacheImage = cacheImage
+	_ = s.tracker.UpdateProgress(ctx, buildCtx.BuildID, fmt.Sprintf("Pulled registry build cache %s\n", cacheImage))
+}
+
+// pushRegistryCache tags the builder stage of the Dockerfile as the app's registry cache
+// image and pushes it for the next build
+func (s *Service) pushRegistryCache(ctx context.Context, buildCtx *BuildContext, dockerfileContent string) {
+	dockerStrategy, ok := s.buildStrategy.(*strategies.DockerStrategy)
+	if !s.registryCache || !ok || !strings.Contains(dockerfileContent, " AS builder") {
+		return
+	}
+
+	cacheImage := s.registryCacheImage(buildCtx.AppName)
+
+	// The stage was just built, so this is served entirely from the local layer cache
+	if err := dockerStrategy.BuildStage(ctx, buildCtx, dockerfileContent, "builder", cacheImage); err != nil {
+		log.Warn().Err(err).Msg("Failed to build cache stage, skipping registry cache push")
+		return
+	}
+
+	if err := s.registryClient.Push(ctx, cacheImage); err != nil {
+		log.Warn().Err(err).Str("cacheImage", cacheImage).Msg("Failed to push registry build cache")
+		return
+	}
+
+	log.Info().Str("cacheImage", cacheImage).Msg("Registry build cache pushed")
+}
+
 // GetBuildLogs retrieves build logs for streaming
 func (s *Service) GetBuildLogs(ctx context.Context, buildID string) (io.Reader, error) {
 	log.Debug().Str("buildID", buildID).Msg("Retrieving build logs")
@@ -0,0 +1,61 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Sign signs an image with cosign. imageDigest must be a digest reference
+// (registry/repo@sha256:...) so the signature covers exactly the pushed manifest.
+// keyRef is any key reference cosign accepts, e.g. gcpkms://projects/.../cryptoKeyVersions/1.
+func Sign(ctx context.Context, imageDigest, keyRef string) error {
+	if keyRef == "" {
+		return fmt.Errorf("cosign key reference is required")
+	}
+
+	if !strings.Contains(imageDigest, "@sha256:") {
+		return fmt.Errorf("image must be referenced by digest: %s", imageDigest)
+	}
+
+	log.Info().
+		Str("image", imageDigest).
+		Str("keyRef", keyRef).
+		Msg("Signing image with cosign")
+
+	cmd := exec.CommandContext(ctx, "cosign", "sign", "--key", keyRef, "--yes", imageDigest)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cosign sign failed: %w, output: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// VerifySignature reports whether imageRef carries a valid cosign signature for keyRef
+func VerifySignature(ctx context.Context, imageRef, keyRef string) bool {
+	cmd := exec.CommandContext(ctx, "cosign", "verify", "--key", keyRef, imageRef)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		log.Warn().
+			Err(err).
+			Str("image", imageRef).
+			Str("output", string(output)).
+			Msg("Image signature verification failed")
+		return false
+	}
+
+	return true
+}
+
+// SignatureRef returns the tag cosign stores the signature of a digest reference under,
+// e.g. registry/repo@sha256:abc becomes registry/repo:sha256-abc.sig
+func SignatureRef(imageDigest string) string {
+	repo, digest, ok := strings.Cut(imageDigest, "@")
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%s.sig", repo, strings.Replace(digest, ":", "-", 1))
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,26 +15,34 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/image"
+	dockerregistry "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 	"github.com/rs/zerolog/log"
 
 	"github.com/alvesdmateus/app-deployer/internal/builder/buildtypes"
+	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
 )
 
+// generatedDockerfileName is the file the Dockerfile is written to in the build context. It is
+// never taken for one of the source's own Dockerfiles.
+const generatedDockerfileName = "Dockerfile.generated"
+
 // DockerStrategy implements BuildStrategy using Docker
 type DockerStrategy struct {
-	client *client.Client
+	client      *client.Client
+	credentials registry.CredentialProvider // Optional, nil pushes with the daemon's own logins
 }
 
-// NewDockerStrategy creates a new Docker build strategy
-func NewDockerStrategy() (*DockerStrategy, error) {
+// NewDockerStrategy creates a new Docker build strategy that pushes with the logins of credentials
+func NewDockerStrategy(credentials registry.CredentialProvider) (*DockerStrategy, error) {
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
 
 	return &DockerStrategy{
-		client: cli,
+		client:      cli,
+		credentials: credentials,
 	}, nil
 }
 
@@ -62,26 +71,10 @@ func (s *DockerStrategy) Build(ctx context.Context, buildCtx *buildtypes.BuildCo
 		Str("deploymentID", buildCtx.DeploymentID).
 		Msg("Building Docker image")
 
-	// Write Dockerfile to source directory
-	dockerfilePath := filepath.Join(buildCtx.SourcePath, "Dockerfile.generated")
-	if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
-		result.Error = fmt.Errorf("failed to write Dockerfile: %w", err)
-		return result, result.Error
-	}
-	defer os.Remove(dockerfilePath) // Clean up generated Dockerfile
-
-	// Create build context tar
-	buildContextTar, err := s.createBuildContext(buildCtx.SourcePath, "Dockerfile.generated")
-	if err != nil {
-		result.Error = fmt.Errorf("failed to create build context: %w", err)
-		return result, result.Error
-	}
-	defer buildContextTar.Close()
-
 	// Build options
 	buildOptions := types.ImageBuildOptions{
 		Tags:       []string{imageTag},
-		Dockerfile: "Dockerfile.generated",
+		Dockerfile: generatedDockerfileName,
 		Remove:     true,        // Remove intermediate containers
 		ForceRemove: true,       // Always remove intermediate containers
 		PullParent: true,        // Pull parent images
@@ -94,18 +87,15 @@ func (s *DockerStrategy) Build(ctx context.Context, buildCtx *buildtypes.BuildCo
 		},
 	}
 
-	// Execute build
-	buildResponse, err := s.client.ImageBuild(ctx, buildContextTar, buildOptions)
-	if err != nil {
-		result.Error = fmt.Errorf("docker build failed: %w", err)
-		return result, result.Error
+	// Seed the layer cache from a restored image, if any
+	if buildCtx.CacheImage != "" {
+		buildOptions.CacheFrom = []string{buildCtx.CacheImage}
 	}
-	defer buildResponse.Body.Close()
 
-	// Capture build logs
+	// Execute build
 	var buildLog strings.Builder
-	if err := s.streamBuildOutput(ctx, buildResponse.Body, &buildLog); err != nil {
-		result.Error = fmt.Errorf("failed to stream build output: %w", err)
+	if err := s.imageBuild(ctx, buildCtx.ContextDir(), dockerfile, buildOptions, &buildLog); err != nil {
+		result.Error = err
 		result.BuildLog = buildLog.String()
 		return result, result.Error
 	}
@@ -134,6 +124,85 @@ func (s *DockerStrategy) Build(ctx context.Context, buildCtx *buildtypes.BuildCo
 	return result, nil
 }
 
+// BuildStage builds a single named stage of a Dockerfile and tags the result,
+// e.g. to export the builder stage of a multi-stage build as a cache image
+func (s *DockerStrategy) BuildStage(ctx context.Context, buildCtx *buildtypes.BuildContext, dockerfile, target, tag string) error {
+	log.Info().
+		Str("target", target).
+		Str("tag", tag).
+		Msg("Building Docker stage")
+
+	buildOptions := types.ImageBuildOptions{
+		Tags:        []string{tag},
+		Dockerfile:  generatedDockerfileName,
+		Target:      target,
+		Remove:      true,
+		ForceRemove: true,
+	}
+
+	var buildLog strings.Builder
+	if err := s.imageBuild(ctx, buildCtx.ContextDir(), dockerfile, buildOptions, &buildLog); err != nil {
+		return fmt.Errorf("failed to build stage %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// SaveImage exports an image as a tar stream, as produced by docker save
+func (s *DockerStrategy) SaveImage(ctx context.Context, imageTag string) (io.ReadCloser, error) {
+	reader, err := s.client.ImageSave(ctx, []string{imageTag})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save image: %w", err)
+	}
+	return reader, nil
+}
+
+// LoadImage imports an image tar stream, as produced by docker save
+func (s *DockerStrategy) LoadImage(ctx context.Context, input io.Reader) error {
+	response, err := s.client.ImageLoad(ctx, input, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+	defer response.Body.Close()
+
+	// Drain the response so the load completes before returning
+	if _, err := io.Copy(io.Discard, response.Body); err != nil {
+		return fmt.Errorf("failed to read load response: %w", err)
+	}
+
+	return nil
+}
+
+// imageBuild writes the Dockerfile into the source directory and runs a build with the given options
+func (s *DockerStrategy) imageBuild(ctx context.Context, sourcePath, dockerfile string, buildOptions types.ImageBuildOptions, buildLog *strings.Builder) error {
+	// Write Dockerfile to source directory
+	dockerfilePath := filepath.Join(sourcePath, buildOptions.Dockerfile)
+	if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
+		return fmt.Errorf("failed to write Dockerfile: %w", err)
+	}
+	defer os.Remove(dockerfilePath) // Clean up generated Dockerfile
+
+	// Create build context tar
+	buildContextTar, err := createBuildContext(sourcePath, buildOptions.Dockerfile)
+	if err != nil {
+		return fmt.Errorf("failed to create build context: %w", err)
+	}
+	defer buildContextTar.Close()
+
+	buildResponse, err := s.client.ImageBuild(ctx, buildContextTar, buildOptions)
+	if err != nil {
+		return fmt.Errorf("docker build failed: %w", err)
+	}
+	defer buildResponse.Body.Close()
+
+	// Capture build logs
+	if err := s.streamBuildOutput(ctx, buildResponse.Body, buildLog); err != nil {
+		return fmt.Errorf("failed to stream build output: %w", err)
+	}
+
+	return nil
+}
+
 // verifyDockerAccess checks if Docker daemon is accessible
 func (s *DockerStrategy) verifyDockerAccess(ctx context.Context) error {
 	_, err := s.client.Ping(ctx)
@@ -154,7 +223,7 @@ func (s *DockerStrategy) generateImageTag(buildCtx *buildtypes.BuildContext) str
 }
 
 // createBuildContext creates a tar archive of the build context
-func (s *DockerStrategy) createBuildContext(sourcePath, dockerfileName string) (io.ReadCloser, error) {
+func createBuildContext(sourcePath, dockerfileName string) (io.ReadCloser, error) {
 	buf := new(bytes.Buffer)
 	tw := tar.NewWriter(buf)
 	defer tw.Close()
@@ -287,6 +356,37 @@ func (s *DockerStrategy) TagImage(ctx context.Context, sourceTag, targetTag stri
 	return nil
 }
 
+// RepoDigest returns the registry digest reference (repo@sha256:...) of a pushed image
+func (s *DockerStrategy) RepoDigest(ctx context.Context, imageTag string) (string, error) {
+	imageInspect, _, err := s.client.ImageInspectWithRaw(ctx, imageTag)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	repo := imageRepository(imageTag)
+
+	for _, digest := range imageInspect.RepoDigests {
+		if strings.HasPrefix(digest, repo+"@") {
+			return digest, nil
+		}
+	}
+
+	return "", fmt.Errorf("no registry digest found for %s", imageTag)
+}
+
+// DigestRef returns the registry digest reference (repo@sha256:...) of a tagged image
+func DigestRef(imageTag, digest string) string {
+	return imageRepository(imageTag) + "@" + digest
+}
+
+// imageRepository strips the tag from an image reference
+func imageRepository(imageTag string) string {
+	if i := strings.LastIndex(imageTag, ":"); i > strings.LastIndex(imageTag, "/") {
+		return imageTag[:i]
+	}
+	return imageTag
+}
+
 // RemoveImage removes an image from local Docker daemon
 func (s *DockerStrategy) RemoveImage(ctx context.Context, imageTag string) error {
 	log.Info().Str("imageTag", imageTag).Msg("Removing Docker image")
@@ -309,6 +409,21 @@ func (s *DockerStrategy) PushImage(ctx context.Context, imageTag string) error {
 	log.Info().Str("imageTag", imageTag).Msg("Pushing Docker image")
 
 	pushOptions := image.PushOptions{}
+	if s.credentials != nil {
+		authConfig, err := s.credentials.GetAuthConfig(imageTag)
+		switch {
+		case errors.Is(err, registry.ErrNoCredentials):
+			log.Debug().Str("imageTag", imageTag).Msg("No registry credentials, pushing with the daemon's logins")
+		case err != nil:
+			return fmt.Errorf("failed to get registry credentials: %w", err)
+		default:
+			encodedAuth, err := dockerregistry.EncodeAuthConfig(authConfig)
+			if err != nil {
+				return fmt.Errorf("failed to encode registry credentials: %w", err)
+			}
+			pushOptions.RegistryAuth = encodedAuth
+		}
+	}
 
 	pushResponse, err := s.client.ImagePush(ctx, imageTag, pushOptions)
 	if err != nil {
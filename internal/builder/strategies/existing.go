@@ -0,0 +1,86 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/alvesdmateus/app-deployer/internal/builder/buildtypes"
+)
+
+// ExistingStrategy builds from the Dockerfile found in the source instead of one generated
+// from the analysis. The build itself runs on Docker.
+type ExistingStrategy struct {
+	docker *DockerStrategy
+}
+
+// NewExistingStrategy creates a strategy that builds the source's Dockerfile with docker
+func NewExistingStrategy(docker *DockerStrategy) *ExistingStrategy {
+	return &ExistingStrategy{
+		docker: docker,
+	}
+}
+
+// Name returns the strategy name
+func (s *ExistingStrategy) Name() string {
+	return string(StrategyTypeExisting)
+}
+
+// Build builds the Dockerfile at the root of the build context. The dockerfile argument,
+// a generated Dockerfile, is not used.
+func (s *ExistingStrategy) Build(ctx context.Context, buildCtx *buildtypes.BuildContext, _ string) (*buildtypes.BuildResult, error) {
+	name := FindDockerfile(buildCtx.ContextDir())
+	if name == "" {
+		err := fmt.Errorf("no Dockerfile found in %s", buildCtx.ContextDir())
+		return &buildtypes.BuildResult{Error: err}, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(buildCtx.ContextDir(), name))
+	if err != nil {
+		err = fmt.Errorf("failed to read %s: %w", name, err)
+		return &buildtypes.BuildResult{Error: err}, err
+	}
+
+	log.Info().
+		Str("dockerfile", name).
+		Str("deploymentID", buildCtx.DeploymentID).
+		Msg("Building from the source's Dockerfile")
+
+	return s.docker.Build(ctx, buildCtx, string(content))
+}
+
+// FindDockerfile returns the name of the Dockerfile at the root of dir: Dockerfile when it
+// exists, otherwise the first Dockerfile.* variant by name, e.g. Dockerfile.prod. It returns
+// "" when there is none.
+func FindDockerfile(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	var variants []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == generatedDockerfileName {
+			continue
+		}
+		if name == "Dockerfile" {
+			return name
+		}
+		if strings.HasPrefix(name, "Dockerfile.") {
+			variants = append(variants, name)
+		}
+	}
+
+	if len(variants) == 0 {
+		return ""
+	}
+
+	sort.Strings(variants)
+	return variants[0]
+}
@@ -0,0 +1,359 @@
+package strategies
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/alvesdmateus/app-deployer/internal/builder/buildtypes"
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+)
+
+const (
+	defaultKanikoImage   = "gcr.io/kaniko-project/executor:v1.23.2"
+	defaultKanikoTimeout = 30 * time.Minute
+
+	// kanikoContextKey is the ConfigMap key, and file name under kanikoContextDir, of the
+	// gzipped build context
+	kanikoContextKey = "context.tar.gz"
+	kanikoContextDir = "/workspace"
+
+	// maxKanikoContextBytes keeps the build context ConfigMap under the API server's 1MiB
+	// object limit, leaving room for its metadata
+	maxKanikoContextBytes = 1000 * 1024
+
+	// kanikoLogTailLines bounds the build log read back from a finished pod
+	kanikoLogTailLines = 10000
+
+	// serviceAccountNamespaceFile holds the namespace of the pod the worker runs in
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// kanikoDigestPattern matches the digest kaniko logs, and writes to the termination message,
+// once the image is pushed
+var kanikoDigestPattern = regexp.MustCompile(`sha256:[0-9a-f]{64}`)
+
+// KanikoConfig configures in-cluster builds
+type KanikoConfig struct {
+	Namespace      string        // Namespace build pods run in, empty for the worker's own
+	ServiceAccount string        // Service account build pods push with, empty for the namespace default
+	Image          string        // Kaniko executor image, empty for the pinned default
+	Timeout        time.Duration // Longest a build pod may run, zero for 30 minutes
+}
+
+// KanikoStrategy implements BuildStrategy by running the Kaniko executor in a pod of the
+// cluster the worker runs in, so no Docker daemon is needed. The image is pushed by the pod
+// to buildCtx.Destination rather than left in a local daemon.
+type KanikoStrategy struct {
+	kubeClient *deployer.KubeClient
+	config     KanikoConfig
+}
+
+// NewKanikoStrategy creates a Kaniko build strategy. It must run inside a Kubernetes cluster,
+// with a service account allowed to manage pods and ConfigMaps in the build namespace.
+func NewKanikoStrategy(config KanikoConfig) (*KanikoStrategy, error) {
+	kubeClient, err := deployer.NewInClusterKubeClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	if config.Namespace == "" {
+		namespace, err := os.ReadFile(serviceAccountNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read worker namespace: %w", err)
+		}
+		config.Namespace = strings.TrimSpace(string(namespace))
+	}
+	if config.Image == "" {
+		config.Image = defaultKanikoImage
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultKanikoTimeout
+	}
+
+	return &KanikoStrategy{
+		kubeClient: kubeClient,
+		config:     config,
+	}, nil
+}
+
+// Name returns the strategy name
+func (s *KanikoStrategy) Name() string {
+	return string(StrategyTypeKaniko)
+}
+
+// Build builds and pushes a container image with a Kaniko pod. An empty dockerfile builds the
+// source's own Dockerfile.
+func (s *KanikoStrategy) Build(ctx context.Context, buildCtx *buildtypes.BuildContext, dockerfile string) (*buildtypes.BuildResult, error) {
+	startTime := time.Now()
+	result := &buildtypes.BuildResult{
+		Success: false,
+	}
+
+	if buildCtx.Destination == "" {
+		result.Error = fmt.Errorf("kaniko builds need a registry destination")
+		return result, result.Error
+	}
+
+	dockerfileName := generatedDockerfileName
+	if dockerfile == "" {
+		dockerfileName = FindDockerfile(buildCtx.ContextDir())
+		if dockerfileName == "" {
+			result.Error = fmt.Errorf("no Dockerfile found in %s", buildCtx.ContextDir())
+			return result, result.Error
+		}
+	}
+
+	buildContext, err := s.packContext(buildCtx.ContextDir(), dockerfileName, dockerfile)
+	if err != nil {
+		result.Error = err
+		return result, result.Error
+	}
+
+	name := "kaniko-" + buildCtx.BuildID
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "app-deployer",
+		"app.deployer.build-id":        buildCtx.BuildID,
+	}
+
+	log.Info().
+		Str("pod", name).
+		Str("namespace", s.config.Namespace).
+		Str("destination", buildCtx.Destination).
+		Str("deploymentID", buildCtx.DeploymentID).
+		Msg("Building image with Kaniko")
+
+	configMaps := s.kubeClient.GetClientset().CoreV1().ConfigMaps(s.config.Namespace)
+	if _, err := configMaps.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: s.config.Namespace,
+			Labels:    labels,
+		},
+		BinaryData: map[string][]byte{kanikoContextKey: buildContext},
+	}, metav1.CreateOptions{}); err != nil {
+		result.Error = fmt.Errorf("failed to create build context configmap: %w", err)
+		return result, result.Error
+	}
+
+	pods := s.kubeClient.GetClientset().CoreV1().Pods(s.config.Namespace)
+
+	// Clean up even when the build was cancelled
+	cleanupCtx := context.WithoutCancel(ctx)
+	defer func() {
+		if err := pods.Delete(cleanupCtx, name, metav1.DeleteOptions{}); err != nil {
+			log.Warn().Err(err).Str("pod", name).Msg("Failed to delete Kaniko pod")
+		}
+		if err := configMaps.Delete(cleanupCtx, name, metav1.DeleteOptions{}); err != nil {
+			log.Warn().Err(err).Str("configmap", name).Msg("Failed to delete build context configmap")
+		}
+	}()
+
+	if _, err := pods.Create(ctx, s.podSpec(name, labels, buildCtx, dockerfileName), metav1.CreateOptions{}); err != nil {
+		result.Error = fmt.Errorf("failed to create kaniko pod: %w", err)
+		return result, result.Error
+	}
+
+	pod, waitErr := s.waitForPod(ctx, name)
+
+	logs, err := s.kubeClient.GetPodLogs(cleanupCtx, s.config.Namespace, name, "kaniko", kanikoLogTailLines)
+	if err != nil {
+		log.Warn().Err(err).Str("pod", name).Msg("Failed to read Kaniko logs")
+	}
+	result.BuildLog = logs
+
+	if waitErr != nil {
+		result.Error = waitErr
+		return result, result.Error
+	}
+
+	if pod.Status.Phase != corev1.PodSucceeded {
+		result.Error = fmt.Errorf("kaniko build failed: %s", podFailureMessage(pod))
+		return result, result.Error
+	}
+
+	digest := kanikoDigest(pod, logs)
+	if digest == "" {
+		result.Error = fmt.Errorf("kaniko build finished without reporting an image digest")
+		return result, result.Error
+	}
+
+	// Successful build
+	result.Success = true
+	result.ImageTag = buildCtx.Destination
+	result.ImageDigest = digest
+	result.BuildDuration = time.Since(startTime)
+
+	log.Info().
+		Str("imageTag", result.ImageTag).
+		Str("digest", result.ImageDigest).
+		Dur("duration", result.BuildDuration).
+		Msg("Kaniko build completed successfully")
+
+	return result, nil
+}
+
+// podSpec describes the pod that builds the context in ConfigMap name and pushes the image
+func (s *KanikoStrategy) podSpec(name string, labels map[string]string, buildCtx *buildtypes.BuildContext, dockerfileName string) *corev1.Pod {
+	args := []string{
+		"--context=tar://" + kanikoContextDir + "/" + kanikoContextKey,
+		"--dockerfile=" + dockerfileName,
+		"--destination=" + buildCtx.Destination,
+		"--digest-file=/dev/termination-log",
+	}
+
+	if buildCtx.CacheRepo != "" {
+		args = append(args, "--cache=true", "--cache-repo="+buildCtx.CacheRepo)
+	}
+
+	imageLabels := map[string]string{
+		"app.deployer.deployment": buildCtx.DeploymentID,
+		"app.deployer.app":        buildCtx.AppName,
+		"app.deployer.version":    buildCtx.Version,
+		"app.deployer.build-id":   buildCtx.BuildID,
+	}
+	keys := make([]string, 0, len(imageLabels))
+	for key := range imageLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("--label=%s=%s", key, imageLabels[key]))
+	}
+
+	activeDeadline := int64(s.config.Timeout.Seconds())
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: s.config.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ServiceAccountName:    s.config.ServiceAccount,
+			ActiveDeadlineSeconds: &activeDeadline,
+			Containers: []corev1.Container{{
+				Name:  "kaniko",
+				Image: s.config.Image,
+				Args:  args,
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "context",
+					MountPath: kanikoContextDir,
+					ReadOnly:  true,
+				}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "context",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// waitForPod waits for the build pod to succeed or fail and returns its final state
+func (s *KanikoStrategy) waitForPod(ctx context.Context, name string) (*corev1.Pod, error) {
+	// Allow a little past the active deadline for the kubelet to report the failure
+	deadline := time.Now().Add(s.config.Timeout + 30*time.Second)
+
+	for time.Now().Before(deadline) {
+		pod, err := s.kubeClient.GetClientset().CoreV1().Pods(s.config.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kaniko pod: %w", err)
+		}
+
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return pod, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	return nil, fmt.Errorf("timeout waiting for kaniko pod %s after %v", name, s.config.Timeout)
+}
+
+// packContext returns the gzipped build context, with the generated Dockerfile when one is
+// given
+func (s *KanikoStrategy) packContext(contextDir, dockerfileName, dockerfile string) ([]byte, error) {
+	if dockerfile != "" {
+		dockerfilePath := filepath.Join(contextDir, dockerfileName)
+		if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write Dockerfile: %w", err)
+		}
+		defer os.Remove(dockerfilePath) // Clean up generated Dockerfile
+	}
+
+	archive, err := createBuildContext(contextDir, dockerfileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build context: %w", err)
+	}
+	defer archive.Close()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := io.Copy(gz, archive); err != nil {
+		return nil, fmt.Errorf("failed to compress build context: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress build context: %w", err)
+	}
+
+	if buf.Len() > maxKanikoContextBytes {
+		return nil, fmt.Errorf("build context is %d bytes compressed, more than the %d a kaniko build can take",
+			buf.Len(), maxKanikoContextBytes)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// kanikoDigest returns the pushed image's digest, from the termination message kaniko writes
+// it to or, failing that, the last digest in its log
+func kanikoDigest(pod *corev1.Pod, logs string) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			if digest := kanikoDigestPattern.FindString(status.State.Terminated.Message); digest != "" {
+				return digest
+			}
+		}
+	}
+
+	matches := kanikoDigestPattern.FindAllString(logs, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1]
+}
+
+// podFailureMessage describes why the build pod failed
+func podFailureMessage(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil {
+			return fmt.Sprintf("executor exited with code %d (%s)", terminated.ExitCode, terminated.Reason)
+		}
+	}
+
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	return string(pod.Status.Phase)
+}
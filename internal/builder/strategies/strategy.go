@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/alvesdmateus/app-deployer/internal/builder/buildtypes"
+	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
 )
 
 // Strategy defines how to build container images
@@ -22,23 +23,36 @@ const (
 	StrategyTypeDocker    StrategyType = "docker"
 	StrategyTypeBuildpack StrategyType = "buildpack"
 	StrategyTypeNixpack   StrategyType = "nixpack"
+	StrategyTypeExisting  StrategyType = "existing"  // Docker build of the source's own Dockerfile
+	StrategyTypeGenerated StrategyType = "generated" // Docker build of a Dockerfile generated from the analysis
+	StrategyTypeKaniko    StrategyType = "kaniko"    // In-cluster build without a Docker daemon
 )
 
 // StrategyFactory creates build strategies based on type
 type StrategyFactory struct {
-	// Future: Add configuration, registry for custom strategies
+	credentials registry.CredentialProvider // Logins Docker strategies push with, may be nil
 }
 
 // NewStrategyFactory creates a new strategy factory
-func NewStrategyFactory() *StrategyFactory {
-	return &StrategyFactory{}
+func NewStrategyFactory(credentials registry.CredentialProvider) *StrategyFactory {
+	return &StrategyFactory{
+		credentials: credentials,
+	}
 }
 
 // CreateStrategy creates a build strategy based on the specified type
 func (f *StrategyFactory) CreateStrategy(strategyType StrategyType) (Strategy, error) {
 	switch strategyType {
-	case StrategyTypeDocker:
-		return NewDockerStrategy()
+	case StrategyTypeDocker, StrategyTypeGenerated:
+		return NewDockerStrategy(f.credentials)
+	case StrategyTypeExisting:
+		docker, err := NewDockerStrategy(f.credentials)
+		if err != nil {
+			return nil, err
+		}
+		return NewExistingStrategy(docker), nil
+	case StrategyTypeKaniko:
+		return NewKanikoStrategy(KanikoConfig{})
 	case StrategyTypeBuildpack:
 		// Future implementation
 		return nil, ErrStrategyNotImplemented{Type: strategyType}
@@ -2,12 +2,14 @@ package builder
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
+	"github.com/alvesdmateus/app-deployer/internal/builder/scanner"
 	"github.com/alvesdmateus/app-deployer/internal/queue"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 )
@@ -68,6 +70,7 @@ func (t *Tracker) StartBuild(ctx context.Context, deploymentID string) (*state.B
 		Status:       "BUILDING",
 		ImageTag:     "", // Will be set when build completes
 		BuildLog:     "",
+		ScanStatus:   state.ScanStatusPending,
 		StartedAt:    time.Now(),
 	}
 
@@ -158,6 +161,11 @@ func (t *Tracker) CompleteBuild(ctx context.Context, buildID string, result *Bui
 	build.Status = "COMPLETED"
 	build.ImageTag = result.ImageTag
 	build.BuildLog = result.BuildLog
+	build.CacheKey = result.CacheKey
+	build.SBOMPath = result.SBOMPath
+	build.SBOMFormat = result.SBOMFormat
+	build.SignatureRef = result.SignatureRef
+	build.ScanStatus = result.ScanStatus
 	completedAt := time.Now()
 	build.CompletedAt = &completedAt
 
@@ -247,6 +255,9 @@ func (t *Tracker) FailBuild(ctx context.Context, buildID string, buildErr error)
 
 	// Update build with error
 	build.Status = "FAILED"
+	if build.ScanStatus == state.ScanStatusPending {
+		build.ScanStatus = state.ScanStatusSkipped
+	}
 	errorMsg := buildErr.Error()
 	build.BuildLog += fmt.Sprintf("\n\nBUILD FAILED: %s\n", errorMsg)
 	completedAt := time.Now()
@@ -272,6 +283,93 @@ func (t *Tracker) FailBuild(ctx context.Context, buildID string, buildErr error)
 	return nil
 }
 
+// RecordScan stores the vulnerability scan of a build's image
+func (t *Tracker) RecordScan(ctx context.Context, buildID string, scan *scanner.ScanResult) error {
+	bID, err := uuid.Parse(buildID)
+	if err != nil {
+		return fmt.Errorf("invalid build ID: %w", err)
+	}
+
+	build, err := t.repo.GetBuildByID(ctx, bID)
+	if err != nil {
+		return fmt.Errorf("failed to get build: %w", err)
+	}
+
+	if build == nil {
+		return fmt.Errorf("build not found: %s", buildID)
+	}
+
+	if err := t.repo.CreateVulnerabilityScan(ctx, &state.VulnerabilityScan{
+		BuildID:         bID,
+		DeploymentID:    build.DeploymentID,
+		ImageTag:        scan.ImageTag,
+		ScanTime:        scan.ScanTime,
+		Passed:          scan.Passed,
+		CriticalCount:   scan.CriticalCount,
+		HighCount:       scan.HighCount,
+		MediumCount:     scan.MediumCount,
+		LowCount:        scan.LowCount,
+		RawResult:       scan.Raw,
+		SuppressedCount: scan.SuppressedCount,
+		SuppressedVulns: scan.SuppressedVulns,
+	}); err != nil {
+		return fmt.Errorf("failed to record vulnerability scan: %w", err)
+	}
+
+	return nil
+}
+
+// SuppressedCVEs returns the IDs of the unexpired CVE suppressions that apply to a deployment
+func (t *Tracker) SuppressedCVEs(ctx context.Context, deploymentID string) ([]string, error) {
+	depID, err := uuid.Parse(deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	suppressions, err := t.repo.ListActiveCVESuppressions(ctx, depID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		ids = append(ids, suppression.CVEID)
+	}
+
+	return ids, nil
+}
+
+// SidecarImages returns the images of a deployment's sidecar containers
+func (t *Tracker) SidecarImages(ctx context.Context, deploymentID string) ([]string, error) {
+	depID, err := uuid.Parse(deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	deployment, err := t.repo.GetDeployment(ctx, depID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	if deployment == nil || deployment.Sidecars == "" {
+		return nil, nil
+	}
+
+	var sidecars []struct {
+		Image string `json:"image"`
+	}
+	if err := json.Unmarshal([]byte(deployment.Sidecars), &sidecars); err != nil {
+		return nil, fmt.Errorf("failed to parse sidecars: %w", err)
+	}
+
+	images := make([]string, 0, len(sidecars))
+	for _, sidecar := range sidecars {
+		images = append(images, sidecar.Image)
+	}
+
+	return images, nil
+}
+
 // GetBuildByID retrieves a build by its ID (helper method)
 func (t *Tracker) GetBuildByID(ctx context.Context, buildID string) (*state.Build, error) {
 	bID, err := uuid.Parse(buildID)
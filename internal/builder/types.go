@@ -8,6 +8,7 @@ import (
 	"github.com/alvesdmateus/app-deployer/internal/analyzer"
 	"github.com/alvesdmateus/app-deployer/internal/builder/buildtypes"
 	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
+	"github.com/alvesdmateus/app-deployer/internal/builder/scanner"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 )
 
@@ -51,6 +52,15 @@ type BuildTracker interface {
 
 	// GetBuildByID retrieves a build by its ID
 	GetBuildByID(ctx context.Context, buildID string) (*state.Build, error)
+
+	// RecordScan stores the vulnerability scan of a build's image
+	RecordScan(ctx context.Context, buildID string, scan *scanner.ScanResult) error
+
+	// SuppressedCVEs returns the IDs of the vulnerabilities accepted for a deployment
+	SuppressedCVEs(ctx context.Context, deploymentID string) ([]string, error)
+
+	// SidecarImages returns the images of the sidecar containers run next to a deployment's app
+	SidecarImages(ctx context.Context, deploymentID string) ([]string, error)
 }
 
 // BuildService orchestrates the entire build process
@@ -0,0 +1,198 @@
+package costs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// budgetCheckInterval is how often projected spend is compared against budgets
+	budgetCheckInterval = 24 * time.Hour
+
+	// budgetWarningPercent and budgetExceededPercent are the budget thresholds alerted on
+	budgetWarningPercent  = 80
+	budgetExceededPercent = 100
+)
+
+// Notifier enqueues webhook notifications of platform events
+type Notifier interface {
+	TriggerNotification(ctx context.Context, payload *queue.NotifyPayload) error
+}
+
+// BudgetAlerter notifies when a deployment's projected monthly spend passes 80% and then
+// 100% of its budget. Each threshold is alerted on once per calendar month, and again only
+// after spend has fallen back below it.
+type BudgetAlerter struct {
+	repo            *state.Repository
+	tracker         *Tracker
+	notifier        Notifier
+	pauseOverBudget bool
+	logger          zerolog.Logger
+}
+
+// NewBudgetAlerter creates a nightly budget alerter. When pauseOverBudget is set, deployments
+// over budget are also paused so no new rollouts run until they are resumed.
+func NewBudgetAlerter(repo *state.Repository, tracker *Tracker, notifier Notifier, pauseOverBudget bool, logger zerolog.Logger) *BudgetAlerter {
+	return &BudgetAlerter{
+		repo:            repo,
+		tracker:         tracker,
+		notifier:        notifier,
+		pauseOverBudget: pauseOverBudget,
+		logger:          logger.With().Str("component", "budget-alerter").Logger(),
+	}
+}
+
+// Start checks budgets once, then nightly until the context is cancelled. Alerts already sent
+// this month are not repeated, so restarts do not re-send them.
+func (a *BudgetAlerter) Start(ctx context.Context) {
+	a.logger.Info().
+		Dur("interval", budgetCheckInterval).
+		Bool("pause_over_budget", a.pauseOverBudget).
+		Msg("Starting budget alerter")
+
+	if err := a.Check(ctx); err != nil {
+		a.logger.Error().Err(err).Msg("Failed to check deployment budgets")
+	}
+
+	ticker := time.NewTicker(budgetCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.logger.Info().Msg("Budget alerter stopped")
+			return
+		case <-ticker.C:
+			if err := a.Check(ctx); err != nil {
+				a.logger.Error().Err(err).Msg("Failed to check deployment budgets")
+			}
+		}
+	}
+}
+
+// Check compares the projected monthly spend of every budgeted deployment against its budget
+func (a *BudgetAlerter) Check(ctx context.Context) error {
+	deployments, err := a.repo.ListBudgetedDeployments(ctx)
+	if err != nil {
+		return fmt.Errorf("list budgeted deployments: %w", err)
+	}
+
+	for i := range deployments {
+		deployment := &deployments[i]
+
+		cost, err := a.tracker.DeploymentCost(ctx, deployment.ID.String())
+		if err != nil {
+			a.logger.Warn().
+				Err(err).
+				Str("deployment_id", deployment.ID.String()).
+				Msg("Failed to get incurred costs")
+			continue
+		}
+
+		if err := a.checkDeployment(ctx, deployment, cost.MonthlyEstimateUSD); err != nil {
+			a.logger.Warn().
+				Err(err).
+				Str("deployment_id", deployment.ID.String()).
+				Msg("Failed to check deployment budget")
+		}
+	}
+
+	return nil
+}
+
+// checkDeployment alerts on the highest budget threshold spend has newly passed, and clears
+// the recorded alert once spend has fallen back below every threshold
+func (a *BudgetAlerter) checkDeployment(ctx context.Context, deployment *state.Deployment, monthlyEstimateUSD float64) error {
+	percent := BudgetPercent(monthlyEstimateUSD, deployment.MonthlyBudgetUSD)
+	threshold := budgetThreshold(percent)
+
+	// Alerts sent in an earlier month no longer count, so each month is alerted on afresh
+	alerted := deployment.BudgetAlertPercent
+	if sentAt := deployment.BudgetAlertSentAt; sentAt != nil && !sameMonth(*sentAt, time.Now()) {
+		alerted = 0
+	}
+
+	if threshold == 0 {
+		if deployment.BudgetAlertPercent == 0 {
+			return nil
+		}
+		return a.repo.RecordBudgetAlert(ctx, deployment.ID, 0, nil)
+	}
+
+	if threshold <= alerted {
+		return nil
+	}
+
+	message := fmt.Sprintf("Projected monthly spend of %s is $%.2f, %.0f%% of its $%.2f budget",
+		deployment.Name, monthlyEstimateUSD, percent, deployment.MonthlyBudgetUSD)
+
+	paused := false
+	if threshold == budgetExceededPercent && a.pauseOverBudget && !deployment.Paused {
+		if err := a.repo.SetDeploymentPaused(ctx, deployment.ID, true); err != nil {
+			return fmt.Errorf("pause deployment over budget: %w", err)
+		}
+		paused = true
+		message += "; the deployment has been paused"
+	}
+
+	if err := a.notifier.TriggerNotification(ctx, &queue.NotifyPayload{
+		EventType:    "budget_alert",
+		DeploymentID: deployment.ID.String(),
+		Message:      message,
+		Data: map[string]string{
+			"threshold_percent":    fmt.Sprint(threshold),
+			"percent_used":         fmt.Sprintf("%.1f", percent),
+			"monthly_estimate_usd": fmt.Sprintf("%.2f", monthlyEstimateUSD),
+			"monthly_budget_usd":   fmt.Sprintf("%.2f", deployment.MonthlyBudgetUSD),
+			"paused":               fmt.Sprint(paused),
+		},
+	}); err != nil {
+		return fmt.Errorf("enqueue budget alert: %w", err)
+	}
+
+	now := time.Now()
+	if err := a.repo.RecordBudgetAlert(ctx, deployment.ID, threshold, &now); err != nil {
+		return err
+	}
+
+	a.logger.Info().
+		Str("deployment_id", deployment.ID.String()).
+		Int("threshold_percent", threshold).
+		Float64("percent_used", percent).
+		Bool("paused", paused).
+		Msg("Sent budget alert")
+
+	return nil
+}
+
+// BudgetPercent returns spend as a percentage of budget, rounded to one decimal place
+func BudgetPercent(monthlyEstimateUSD, monthlyBudgetUSD float64) float64 {
+	if monthlyBudgetUSD <= 0 {
+		return 0
+	}
+	return math.Round(monthlyEstimateUSD/monthlyBudgetUSD*1000) / 10
+}
+
+// budgetThreshold returns the highest budget threshold percent exceeds, or 0
+func budgetThreshold(percent float64) int {
+	switch {
+	case percent > budgetExceededPercent:
+		return budgetExceededPercent
+	case percent > budgetWarningPercent:
+		return budgetWarningPercent
+	default:
+		return 0
+	}
+}
+
+// sameMonth reports whether a and b fall in the same calendar month in UTC
+func sameMonth(a, b time.Time) bool {
+	a, b = a.UTC(), b.UTC()
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
@@ -0,0 +1,324 @@
+package costs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/cloudbilling/v1"
+)
+
+const (
+	// computeEngineService is the Cloud Billing catalog ID of Compute Engine
+	computeEngineService = "services/6F81-5844-456A"
+
+	// clusterFeeHourly is the GKE cluster management fee, which has no region-specific SKU
+	clusterFeeHourly = 0.10
+
+	// hoursPerMonth matches the 730 hours the GCP pricing calculator uses
+	hoursPerMonth = 730
+
+	// zonesPerRegion is how many zones a regional node pool spreads its nodes over
+	zonesPerRegion = 3
+
+	// priceCacheTTL is how long SKU prices for a region are cached in Redis
+	priceCacheTTL = time.Hour
+)
+
+// Defaults applied by the provision job when the request leaves them unset
+const (
+	defaultNodeCount   = 2
+	defaultMachineType = "e2-small"
+	defaultDiskSize    = 50 // GB
+	defaultDiskType    = "pd-standard"
+)
+
+// Breakdown keys of a CostEstimate
+const (
+	ResourceCompute    = "compute"
+	ResourceMemory     = "memory"
+	ResourceDisk       = "disk"
+	ResourceClusterFee = "cluster_fee"
+)
+
+// CostEstimate is the projected monthly cost of a deployment's infrastructure
+type CostEstimate struct {
+	MonthlyCostUSD      float64
+	BreakdownByResource map[string]float64
+	MachineType         string
+	NodeCount           int
+}
+
+// machineShape describes the billable vCPUs and memory of a machine type
+type machineShape struct {
+	Family   string
+	VCPUs    float64
+	MemoryGB float64
+}
+
+// machineShapes lists the machine types node pools are provisioned with. Shared-core
+// E2 types are billed for a fraction of a vCPU.
+var machineShapes = map[string]machineShape{
+	"e2-micro":      {Family: "e2", VCPUs: 0.25, MemoryGB: 1},
+	"e2-small":      {Family: "e2", VCPUs: 0.5, MemoryGB: 2},
+	"e2-medium":     {Family: "e2", VCPUs: 1, MemoryGB: 4},
+	"e2-standard-2": {Family: "e2", VCPUs: 2, MemoryGB: 8},
+	"e2-standard-4": {Family: "e2", VCPUs: 4, MemoryGB: 16},
+	"e2-standard-8": {Family: "e2", VCPUs: 8, MemoryGB: 32},
+	"n1-standard-1": {Family: "n1", VCPUs: 1, MemoryGB: 3.75},
+	"n1-standard-2": {Family: "n1", VCPUs: 2, MemoryGB: 7.5},
+	"n1-standard-4": {Family: "n1", VCPUs: 4, MemoryGB: 15},
+	"n2-standard-2": {Family: "n2", VCPUs: 2, MemoryGB: 8},
+	"n2-standard-4": {Family: "n2", VCPUs: 4, MemoryGB: 16},
+	"n2-standard-8": {Family: "n2", VCPUs: 8, MemoryGB: 32},
+}
+
+// instanceSKUPattern matches Compute Engine vCPU and RAM SKU descriptions, e.g.
+// "E2 Instance Core running in Americas" or "Preemptible N1 Predefined Instance Ram running in Iowa"
+var instanceSKUPattern = regexp.MustCompile(`^(Preemptible |Spot Preemptible )?(E2|N1 Predefined|N2) Instance (Core|Ram) running in `)
+
+// diskSKUs maps persistent disk capacity SKU descriptions to disk types
+var diskSKUs = map[string]string{
+	"Storage PD Capacity":    "pd-standard",
+	"Balanced PD Capacity":   "pd-balanced",
+	"SSD backed PD Capacity": "pd-ssd",
+}
+
+// RegionPrices holds the unit prices of a region, keyed by machine family (suffixed with
+// ":preemptible" for preemptible rates) and disk type
+type RegionPrices struct {
+	CoreHourly    map[string]float64 `json:"core_hourly"`
+	RAMHourly     map[string]float64 `json:"ram_hourly"`
+	DiskMonthlyGB map[string]float64 `json:"disk_monthly_gb"`
+}
+
+// Estimator prices GKE infrastructure from the Cloud Billing catalog
+type Estimator struct {
+	billing *cloudbilling.APIService
+	cache   *queue.RedisQueue
+}
+
+// NewEstimator creates an estimator. cache may be nil, in which case every estimate
+// reads the catalog.
+func NewEstimator(ctx context.Context, cache *queue.RedisQueue) (*Estimator, error) {
+	billingSvc, err := cloudbilling.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud billing client: %w", err)
+	}
+
+	return &Estimator{
+		billing: billingSvc,
+		cache:   cache,
+	}, nil
+}
+
+// EstimateGKECost returns the monthly cost of the cluster and node pool the provisioner
+// would create for config in region. A nil config is estimated with the provisioning defaults.
+func (e *Estimator) EstimateGKECost(ctx context.Context, region string, config *provisioner.ProvisionConfig) (*CostEstimate, error) {
+	nodeCount := defaultNodeCount
+	machineType := defaultMachineType
+	preemptible := false
+	diskSize := defaultDiskSize
+	diskType := defaultDiskType
+
+	if config != nil {
+		if config.NodeCount > 0 {
+			nodeCount = config.NodeCount
+		}
+		if config.MachineType != "" {
+			machineType = config.MachineType
+		}
+		preemptible = config.Preemptible
+		if config.DiskSize > 0 {
+			diskSize = config.DiskSize
+		}
+		if config.DiskType != "" {
+			diskType = config.DiskType
+		}
+	}
+
+	// Node pools are regional, so NodeCount nodes run in every zone. Autoscaling limits
+	// are totals, and the estimate assumes the pool sits at its minimum.
+	totalNodes := nodeCount * zonesPerRegion
+	if config != nil && config.EnableAutoscaling {
+		totalNodes = max(config.Autoscaling.MinNodes, 1)
+	}
+
+	// A dedicated node pool is sized like the general one it runs beside
+	if config != nil && config.DedicatedNodePool {
+		totalNodes *= 2
+	}
+
+	shape, ok := machineShapes[machineType]
+	if !ok {
+		return nil, fmt.Errorf("no pricing available for machine type %s", machineType)
+	}
+
+	prices, err := e.regionPrices(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	family := shape.Family
+	if preemptible {
+		family += ":preemptible"
+	}
+
+	coreHourly, ok := prices.CoreHourly[family]
+	if !ok {
+		return nil, fmt.Errorf("no vCPU price found for %s in %s", family, region)
+	}
+	ramHourly, ok := prices.RAMHourly[family]
+	if !ok {
+		return nil, fmt.Errorf("no memory price found for %s in %s", family, region)
+	}
+	diskMonthly, ok := prices.DiskMonthlyGB[diskType]
+	if !ok {
+		return nil, fmt.Errorf("no price found for disk type %s in %s", diskType, region)
+	}
+
+	nodes := float64(totalNodes)
+	breakdown := map[string]float64{
+		ResourceCompute:    roundCents(coreHourly * shape.VCPUs * nodes * hoursPerMonth),
+		ResourceMemory:     roundCents(ramHourly * shape.MemoryGB * nodes * hoursPerMonth),
+		ResourceDisk:       roundCents(diskMonthly * float64(diskSize) * nodes),
+		ResourceClusterFee: roundCents(clusterFeeHourly * hoursPerMonth),
+	}
+
+	var total float64
+	for _, cost := range breakdown {
+		total += cost
+	}
+
+	return &CostEstimate{
+		MonthlyCostUSD:      roundCents(total),
+		BreakdownByResource: breakdown,
+		MachineType:         machineType,
+		NodeCount:           totalNodes,
+	}, nil
+}
+
+// regionPrices returns the unit prices of a region, from the cache when possible
+func (e *Estimator) regionPrices(ctx context.Context, region string) (*RegionPrices, error) {
+	cacheKey := "costs:prices:" + region
+
+	if e.cache != nil {
+		data, err := e.cache.GetCache(ctx, cacheKey)
+		if err != nil {
+			log.Warn().Err(err).Str("region", region).Msg("Failed to read cached SKU prices")
+		} else if data != nil {
+			var prices RegionPrices
+			if err := json.Unmarshal(data, &prices); err == nil {
+				return &prices, nil
+			}
+		}
+	}
+
+	prices, err := e.fetchRegionPrices(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.cache != nil {
+		data, err := json.Marshal(prices)
+		if err == nil {
+			if err := e.cache.SetCache(ctx, cacheKey, data, priceCacheTTL); err != nil {
+				log.Warn().Err(err).Str("region", region).Msg("Failed to cache SKU prices")
+			}
+		}
+	}
+
+	return prices, nil
+}
+
+// fetchRegionPrices pages through the Compute Engine SKUs and keeps the on-demand and
+// preemptible instance and disk prices offered in region
+func (e *Estimator) fetchRegionPrices(ctx context.Context, region string) (*RegionPrices, error) {
+	prices := &RegionPrices{
+		CoreHourly:    make(map[string]float64),
+		RAMHourly:     make(map[string]float64),
+		DiskMonthlyGB: make(map[string]float64),
+	}
+
+	err := e.billing.Services.Skus.List(computeEngineService).
+		CurrencyCode("USD").
+		Pages(ctx, func(resp *cloudbilling.ListSkusResponse) error {
+			for _, sku := range resp.Skus {
+				if !servesRegion(sku, region) {
+					continue
+				}
+
+				price, ok := unitPrice(sku)
+				if !ok {
+					continue
+				}
+
+				if diskType, ok := diskSKUs[sku.Description]; ok {
+					prices.DiskMonthlyGB[diskType] = price
+					continue
+				}
+
+				match := instanceSKUPattern.FindStringSubmatch(sku.Description)
+				if match == nil {
+					continue
+				}
+
+				// Spot and preemptible SKUs share a price; the first one seen wins
+				family := strings.ToLower(strings.Fields(match[2])[0])
+				if match[1] != "" {
+					family += ":preemptible"
+				}
+
+				target := prices.CoreHourly
+				if match[3] == "Ram" {
+					target = prices.RAMHourly
+				}
+				if _, exists := target[family]; !exists {
+					target[family] = price
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compute engine SKUs: %w", err)
+	}
+
+	return prices, nil
+}
+
+// servesRegion reports whether a SKU is offered in region
+func servesRegion(sku *cloudbilling.Sku, region string) bool {
+	for _, r := range sku.ServiceRegions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// unitPrice returns the price of one usage unit of a SKU, using its highest tier so
+// that free-tier allowances do not zero the estimate
+func unitPrice(sku *cloudbilling.Sku) (float64, bool) {
+	if len(sku.PricingInfo) == 0 || sku.PricingInfo[0].PricingExpression == nil {
+		return 0, false
+	}
+
+	rates := sku.PricingInfo[0].PricingExpression.TieredRates
+	if len(rates) == 0 || rates[len(rates)-1].UnitPrice == nil {
+		return 0, false
+	}
+
+	money := rates[len(rates)-1].UnitPrice
+	return float64(money.Units) + float64(money.Nanos)/1e9, true
+}
+
+// roundCents rounds a USD amount to whole cents
+func roundCents(amount float64) float64 {
+	return float64(int64(amount*100+0.5)) / 100
+}
@@ -0,0 +1,306 @@
+package costs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+	"google.golang.org/api/monitoring/v3"
+)
+
+const (
+	// egressCheckInterval is how often cluster egress is compared against alert thresholds
+	egressCheckInterval = 24 * time.Hour
+
+	// networkStatsWindow is the window network traffic is totalled over
+	networkStatsWindow = 24 * time.Hour
+
+	// topDestinationsLimit caps how many egress destinations are reported
+	topDestinationsLimit = 5
+
+	// VPC flow metrics sampled from the cluster's nodes. Cloud Monitoring has no single
+	// external traffic metric; traffic leaving Google Cloud is told apart by its remote
+	// location type.
+	nodeEgressMetric      = "networking.googleapis.com/node_flow/egress_bytes_count"
+	nodeIngressMetric     = "networking.googleapis.com/node_flow/ingress_bytes_count"
+	externalLocationType  = "INTERNET"
+	bytesPerGB            = 1e9
+	destinationGroupLabel = "metric.labels.remote_country"
+)
+
+// NetworkDestination is the egress sent to one destination country
+type NetworkDestination struct {
+	Country  string  `json:"country"`
+	EgressGB float64 `json:"egress_gb"`
+}
+
+// NetworkStats is the external traffic of a cluster's nodes over the last 24 hours
+type NetworkStats struct {
+	EgressGBLast24h  float64              `json:"egress_gb_last_24h"`
+	IngressGBLast24h float64              `json:"ingress_gb_last_24h"`
+	TopDestinations  []NetworkDestination `json:"top_destinations"`
+}
+
+// NetworkMonitor reads cluster network traffic from Cloud Monitoring's VPC flow metrics
+type NetworkMonitor struct {
+	monitoring *monitoring.Service
+	project    string
+}
+
+// NewNetworkMonitor creates a network monitor. project is the project provisioned clusters
+// run in; imported clusters are queried in their own project.
+func NewNetworkMonitor(ctx context.Context, project string) (*NetworkMonitor, error) {
+	if project == "" {
+		return nil, fmt.Errorf("no GCP project configured")
+	}
+
+	monitoringSvc, err := monitoring.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitoring client: %w", err)
+	}
+
+	return &NetworkMonitor{
+		monitoring: monitoringSvc,
+		project:    project,
+	}, nil
+}
+
+// ClusterNetworkStats returns the traffic between infrastructure's cluster nodes and the
+// internet over the last 24 hours. Traffic within Google Cloud, including the cluster's own
+// VPC, is not counted.
+func (m *NetworkMonitor) ClusterNetworkStats(ctx context.Context, infra *state.Infrastructure) (*NetworkStats, error) {
+	if infra.ClusterName == "" {
+		return nil, fmt.Errorf("infrastructure has no cluster")
+	}
+
+	project := m.project
+	if infra.ImportedExternally && infra.GCPProject != "" {
+		project = infra.GCPProject
+	}
+
+	end := time.Now().UTC()
+	start := end.Add(-networkStatsWindow)
+
+	egress, err := m.sumTraffic(ctx, project, infra, nodeEgressMetric, start, end, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ingress, err := m.sumTraffic(ctx, project, infra, nodeIngressMetric, start, end, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byCountry, err := m.sumTraffic(ctx, project, infra, nodeEgressMetric, start, end, destinationGroupLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations := make([]NetworkDestination, 0, len(byCountry))
+	for country, bytes := range byCountry {
+		destinations = append(destinations, NetworkDestination{
+			Country:  country,
+			EgressGB: bytesToGB(bytes),
+		})
+	}
+	sort.Slice(destinations, func(i, j int) bool {
+		return destinations[i].EgressGB > destinations[j].EgressGB
+	})
+	if len(destinations) > topDestinationsLimit {
+		destinations = destinations[:topDestinationsLimit]
+	}
+
+	return &NetworkStats{
+		EgressGBLast24h:  bytesToGB(egress[""]),
+		IngressGBLast24h: bytesToGB(ingress[""]),
+		TopDestinations:  destinations,
+	}, nil
+}
+
+// sumTraffic totals a flow metric's bytes over the window for the cluster's nodes. When
+// groupBy names a metric label the totals are keyed by its value, otherwise by "".
+func (m *NetworkMonitor) sumTraffic(ctx context.Context, project string, infra *state.Infrastructure, metric string, start, end time.Time, groupBy string) (map[string]float64, error) {
+	filter := fmt.Sprintf(`metric.type = %q AND resource.type = "k8s_node" AND resource.labels.cluster_name = %q AND metric.labels.remote_location_type = %q`,
+		metric, infra.ClusterName, externalLocationType)
+	if infra.ClusterLocation != "" {
+		filter += fmt.Sprintf(` AND resource.labels.location = %q`, infra.ClusterLocation)
+	}
+
+	call := m.monitoring.Projects.TimeSeries.List("projects/" + project).
+		Filter(filter).
+		IntervalStartTime(start.Format(time.RFC3339)).
+		IntervalEndTime(end.Format(time.RFC3339)).
+		AggregationAlignmentPeriod(fmt.Sprintf("%ds", int(networkStatsWindow.Seconds()))).
+		AggregationPerSeriesAligner("ALIGN_SUM").
+		AggregationCrossSeriesReducer("REDUCE_SUM")
+	if groupBy != "" {
+		call = call.AggregationGroupByFields(groupBy)
+	}
+
+	totals := make(map[string]float64)
+	err := call.Pages(ctx, func(resp *monitoring.ListTimeSeriesResponse) error {
+		for _, series := range resp.TimeSeries {
+			key := ""
+			if groupBy != "" && series.Metric != nil {
+				key = series.Metric.Labels["remote_country"]
+			}
+
+			for _, point := range series.Points {
+				if point.Value == nil {
+					continue
+				}
+				switch {
+				case point.Value.Int64Value != nil:
+					totals[key] += float64(*point.Value.Int64Value)
+				case point.Value.DoubleValue != nil:
+					totals[key] += *point.Value.DoubleValue
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", metric, err)
+	}
+
+	return totals, nil
+}
+
+// bytesToGB converts bytes to decimal gigabytes, as egress is billed, rounded to 3 places
+func bytesToGB(bytes float64) float64 {
+	return math.Round(bytes/bytesPerGB*1000) / 1000
+}
+
+// EgressAlerter notifies when a deployment's cluster sent more to the internet over the last
+// day than its egress threshold. Each nightly check that finds egress over the threshold
+// alerts, since egress is measured per day.
+type EgressAlerter struct {
+	repo               *state.Repository
+	monitor            *NetworkMonitor
+	notifier           Notifier
+	defaultThresholdGB float64
+	logger             zerolog.Logger
+}
+
+// NewEgressAlerter creates a nightly egress alerter. defaultThresholdGB applies to
+// deployments without their own threshold; zero leaves them unalerted.
+func NewEgressAlerter(repo *state.Repository, monitor *NetworkMonitor, notifier Notifier, defaultThresholdGB float64, logger zerolog.Logger) *EgressAlerter {
+	return &EgressAlerter{
+		repo:               repo,
+		monitor:            monitor,
+		notifier:           notifier,
+		defaultThresholdGB: defaultThresholdGB,
+		logger:             logger.With().Str("component", "egress-alerter").Logger(),
+	}
+}
+
+// Start checks egress once, then nightly until the context is cancelled
+func (a *EgressAlerter) Start(ctx context.Context) {
+	a.logger.Info().
+		Dur("interval", egressCheckInterval).
+		Float64("default_threshold_gb", a.defaultThresholdGB).
+		Msg("Starting egress alerter")
+
+	if err := a.Check(ctx); err != nil {
+		a.logger.Error().Err(err).Msg("Failed to check deployment egress")
+	}
+
+	ticker := time.NewTicker(egressCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.logger.Info().Msg("Egress alerter stopped")
+			return
+		case <-ticker.C:
+			if err := a.Check(ctx); err != nil {
+				a.logger.Error().Err(err).Msg("Failed to check deployment egress")
+			}
+		}
+	}
+}
+
+// Check records the last day's egress of every ready cluster and alerts on those over their
+// deployment's threshold. Cloud Run services have no nodes and are skipped.
+func (a *EgressAlerter) Check(ctx context.Context) error {
+	infras, err := a.repo.ListInfrastructureByStatus(ctx, "READY")
+	if err != nil {
+		return fmt.Errorf("list ready infrastructure: %w", err)
+	}
+
+	for _, infra := range infras {
+		if infra.ClusterName == "" {
+			continue
+		}
+
+		if err := a.checkInfrastructure(ctx, infra); err != nil {
+			a.logger.Warn().
+				Err(err).
+				Str("deployment_id", infra.DeploymentID.String()).
+				Msg("Failed to check deployment egress")
+		}
+	}
+
+	return nil
+}
+
+// checkInfrastructure measures and records a cluster's egress, then alerts when it exceeds
+// the deployment's threshold
+func (a *EgressAlerter) checkInfrastructure(ctx context.Context, infra *state.Infrastructure) error {
+	deployment, err := a.repo.GetDeploymentByID(ctx, infra.DeploymentID)
+	if err != nil {
+		return fmt.Errorf("get deployment: %w", err)
+	}
+	if deployment.Cloud == "cloudrun" {
+		return nil
+	}
+
+	stats, err := a.monitor.ClusterNetworkStats(ctx, infra)
+	if err != nil {
+		return err
+	}
+
+	if err := a.repo.UpdateInfrastructureEgress(ctx, infra.ID, stats.EgressGBLast24h, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	threshold := deployment.EgressAlertGB
+	if threshold <= 0 {
+		threshold = a.defaultThresholdGB
+	}
+	if threshold <= 0 || stats.EgressGBLast24h <= threshold {
+		return nil
+	}
+
+	data := map[string]string{
+		"egress_gb":    fmt.Sprintf("%.3f", stats.EgressGBLast24h),
+		"threshold_gb": fmt.Sprintf("%.3f", threshold),
+	}
+	if len(stats.TopDestinations) > 0 {
+		data["top_destination"] = stats.TopDestinations[0].Country
+	}
+
+	if err := a.notifier.TriggerNotification(ctx, &queue.NotifyPayload{
+		EventType:    "egress_alert",
+		DeploymentID: deployment.ID.String(),
+		Message: fmt.Sprintf("%s sent %.2f GB to the internet in the last 24 hours, over its %.2f GB threshold",
+			deployment.Name, stats.EgressGBLast24h, threshold),
+		Data: data,
+	}); err != nil {
+		return fmt.Errorf("enqueue egress alert: %w", err)
+	}
+
+	a.logger.Info().
+		Str("deployment_id", deployment.ID.String()).
+		Float64("egress_gb", stats.EgressGBLast24h).
+		Float64("threshold_gb", threshold).
+		Msg("Sent egress alert")
+
+	return nil
+}
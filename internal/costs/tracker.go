@@ -0,0 +1,251 @@
+package costs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/bigquery/v2"
+)
+
+const (
+	// costLookbackDays is the window incurred costs are averaged over. The billing export
+	// lags usage by up to a day, so a single day would under-report.
+	costLookbackDays = 7
+
+	// daysPerMonth matches the 730 hours used for estimates
+	daysPerMonth = hoursPerMonth / 24.0
+
+	// actualCostCacheTTL is how long a deployment's incurred costs are cached in Redis
+	actualCostCacheTTL = time.Hour
+
+	// queryTimeout bounds how long a billing query is waited for
+	queryTimeout = 2 * time.Minute
+)
+
+// bigQueryIdentifierPattern matches project, dataset and table names, which are
+// interpolated into the query because BigQuery cannot parameterize them
+var bigQueryIdentifierPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ResourceCost is the incurred cost of one billed resource
+type ResourceCost struct {
+	ResourceType string  `json:"resource_type"` // Billing service, e.g. Compute Engine
+	Name         string  `json:"name"`
+	CostUSD      float64 `json:"cost_usd"` // Average per day over the lookback window
+}
+
+// ActualCost is the cost a deployment's infrastructure has incurred, from the billing export
+type ActualCost struct {
+	DailyCostUSD        float64        `json:"daily_cost_usd"`
+	MonthlyEstimateUSD  float64        `json:"monthly_estimate_usd"`
+	BreakdownByResource []ResourceCost `json:"breakdown_by_resource"`
+	LookbackDays        int            `json:"lookback_days"`
+}
+
+// TrackerConfig locates the Cloud Billing export table
+type TrackerConfig struct {
+	Project string
+	Dataset string
+	Table   string
+}
+
+// Tracker reads incurred infrastructure costs from the Cloud Billing BigQuery export.
+// Resources are attributed to a deployment by their deployment-id label, which holds the
+// short ID on provisioned GKE resources and the full ID on Cloud Run services.
+type Tracker struct {
+	bigquery *bigquery.Service
+	config   TrackerConfig
+	cache    *queue.RedisQueue
+}
+
+// NewTracker creates a cost tracker. cache may be nil, in which case every lookup
+// queries BigQuery.
+func NewTracker(ctx context.Context, config TrackerConfig, cache *queue.RedisQueue) (*Tracker, error) {
+	for name, value := range map[string]string{"project": config.Project, "dataset": config.Dataset, "table": config.Table} {
+		if !bigQueryIdentifierPattern.MatchString(value) {
+			return nil, fmt.Errorf("invalid billing export %s %q", name, value)
+		}
+	}
+
+	bigquerySvc, err := bigquery.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bigquery client: %w", err)
+	}
+
+	return &Tracker{
+		bigquery: bigquerySvc,
+		config:   config,
+		cache:    cache,
+	}, nil
+}
+
+// DeploymentCost returns the costs a deployment has incurred, from the cache when possible
+func (t *Tracker) DeploymentCost(ctx context.Context, deploymentID string) (*ActualCost, error) {
+	cacheKey := "costs:actual:" + deploymentID
+
+	if t.cache != nil {
+		data, err := t.cache.GetCache(ctx, cacheKey)
+		if err != nil {
+			log.Warn().Err(err).Str("deployment_id", deploymentID).Msg("Failed to read cached costs")
+		} else if data != nil {
+			var cost ActualCost
+			if err := json.Unmarshal(data, &cost); err == nil {
+				return &cost, nil
+			}
+		}
+	}
+
+	cost, err := t.queryDeploymentCost(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.cache != nil {
+		data, err := json.Marshal(cost)
+		if err == nil {
+			if err := t.cache.SetCache(ctx, cacheKey, data, actualCostCacheTTL); err != nil {
+				log.Warn().Err(err).Str("deployment_id", deploymentID).Msg("Failed to cache costs")
+			}
+		}
+	}
+
+	return cost, nil
+}
+
+// queryDeploymentCost sums the net cost, after credits, of every resource labelled with
+// the deployment over the lookback window
+func (t *Tracker) queryDeploymentCost(ctx context.Context, deploymentID string) (*ActualCost, error) {
+	query := fmt.Sprintf(`
+SELECT
+  service.description AS resource_type,
+  IFNULL(resource.name, sku.description) AS name,
+  SUM(cost) + SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c), 0)) AS cost_usd
+FROM `+"`%s.%s.%s`"+`
+WHERE usage_start_time >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL @days DAY)
+  AND EXISTS (SELECT 1 FROM UNNEST(labels) l WHERE l.key = 'deployment-id' AND l.value IN (@deployment_label, @deployment_id))
+GROUP BY resource_type, name
+ORDER BY cost_usd DESC`, t.config.Project, t.config.Dataset, t.config.Table)
+
+	useLegacySQL := false
+	req := &bigquery.QueryRequest{
+		Query:         query,
+		UseLegacySql:  &useLegacySQL,
+		ParameterMode: "NAMED",
+		QueryParameters: []*bigquery.QueryParameter{
+			stringParameter("deployment_label", deploymentLabel(deploymentID)),
+			stringParameter("deployment_id", deploymentID),
+			{
+				Name:           "days",
+				ParameterType:  &bigquery.QueryParameterType{Type: "INT64"},
+				ParameterValue: &bigquery.QueryParameterValue{Value: strconv.Itoa(costLookbackDays)},
+			},
+		},
+		TimeoutMs: queryTimeout.Milliseconds(),
+	}
+
+	rows, err := t.runQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := &ActualCost{
+		BreakdownByResource: make([]ResourceCost, 0, len(rows)),
+		LookbackDays:        costLookbackDays,
+	}
+
+	var total float64
+	for _, row := range rows {
+		if len(row.F) < 3 {
+			continue
+		}
+
+		amount, err := strconv.ParseFloat(cellString(row.F[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse billed cost: %w", err)
+		}
+		total += amount
+
+		cost.BreakdownByResource = append(cost.BreakdownByResource, ResourceCost{
+			ResourceType: cellString(row.F[0]),
+			Name:         cellString(row.F[1]),
+			CostUSD:      roundCents(amount / costLookbackDays),
+		})
+	}
+
+	daily := total / costLookbackDays
+	cost.DailyCostUSD = roundCents(daily)
+	cost.MonthlyEstimateUSD = roundCents(daily * daysPerMonth)
+
+	return cost, nil
+}
+
+// runQuery runs a query and waits for its rows. Long-running queries return before they
+// complete, so their results are polled for.
+func (t *Tracker) runQuery(ctx context.Context, req *bigquery.QueryRequest) ([]*bigquery.TableRow, error) {
+	resp, err := t.bigquery.Jobs.Query(t.config.Project, req).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query billing export: %w", err)
+	}
+
+	if resp.JobComplete {
+		return resp.Rows, nil
+	}
+
+	if resp.JobReference == nil {
+		return nil, fmt.Errorf("billing query did not complete")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	for {
+		results, err := t.bigquery.Jobs.GetQueryResults(resp.JobReference.ProjectId, resp.JobReference.JobId).
+			Location(resp.JobReference.Location).
+			TimeoutMs(10000).
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get billing query results: %w", err)
+		}
+
+		if results.JobComplete {
+			return results.Rows, nil
+		}
+	}
+}
+
+// stringParameter builds a named STRING query parameter
+func stringParameter(name, value string) *bigquery.QueryParameter {
+	return &bigquery.QueryParameter{
+		Name:           name,
+		ParameterType:  &bigquery.QueryParameterType{Type: "STRING"},
+		ParameterValue: &bigquery.QueryParameterValue{Value: value},
+	}
+}
+
+// deploymentLabel returns the value of the deployment-id label the GCP provisioner puts on
+// a deployment's resources: the first 8 hex digits of its ID
+func deploymentLabel(deploymentID string) string {
+	cleaned := strings.ReplaceAll(deploymentID, "-", "")
+	if len(cleaned) >= 8 {
+		return cleaned[:8]
+	}
+	return cleaned
+}
+
+// cellString returns a result cell as a string; BigQuery returns every scalar as one
+func cellString(cell *bigquery.TableCell) string {
+	if cell == nil || cell.V == nil {
+		return ""
+	}
+	if s, ok := cell.V.(string); ok {
+		return s
+	}
+	return fmt.Sprint(cell.V)
+}
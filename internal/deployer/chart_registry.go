@@ -0,0 +1,124 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoChartRegistry is returned when publishing charts without a chart registry configured
+var ErrNoChartRegistry = errors.New("no chart registry configured")
+
+// digestPattern matches the manifest digest helm push reports, e.g. Digest: sha256:4f1c...
+var digestPattern = regexp.MustCompile(`Digest:\s*(sha256:[0-9a-f]+)`)
+
+// HelmChartRegistry is the OCI registry the deployer's chart is published to and installed from
+type HelmChartRegistry struct {
+	RegistryURL string // e.g. oci://us-central1-docker.pkg.dev/project/helm-charts
+}
+
+// ChartMetadata is the name and version a chart declares in its Chart.yaml
+type ChartMetadata struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+// ChartRegistry returns the OCI registry URL charts are published to, empty when none is configured
+func (h *HelmDeployer) ChartRegistry() string {
+	return h.chartRegistry
+}
+
+// LocalChart reads the name and version of the chart at the deployer's chart path
+func (h *HelmDeployer) LocalChart() (*ChartMetadata, error) {
+	data, err := os.ReadFile(filepath.Join(h.chartPath, "Chart.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Chart.yaml: %w", err)
+	}
+
+	var meta ChartMetadata
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse Chart.yaml: %w", err)
+	}
+
+	if meta.Name == "" || meta.Version == "" {
+		return nil, fmt.Errorf("chart at %s has no name or version", h.chartPath)
+	}
+
+	return &meta, nil
+}
+
+// PublishChart packages the chart at the deployer's chart path and pushes it to the chart
+// registry, returning the digest the registry stored it under. Registry credentials come from
+// helm registry login, or a credential helper such as gcloud's for Artifact Registry.
+func (h *HelmDeployer) PublishChart(ctx context.Context) (string, error) {
+	if h.chartRegistry == "" {
+		return "", ErrNoChartRegistry
+	}
+
+	meta, err := h.LocalChart()
+	if err != nil {
+		return "", err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "helm-chart-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create package directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	output, err := exec.CommandContext(ctx, "helm", "package", h.chartPath, "-d", tmpDir).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("helm package failed: %w, output: %s", err, string(output))
+	}
+
+	pkg := filepath.Join(tmpDir, fmt.Sprintf("%s-%s.tgz", meta.Name, meta.Version))
+	output, err = exec.CommandContext(ctx, "helm", "push", pkg, h.chartRegistry).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("helm push failed: %w, output: %s", err, string(output))
+	}
+
+	var digest string
+	if m := digestPattern.FindStringSubmatch(string(output)); m != nil {
+		digest = m[1]
+	}
+
+	return digest, nil
+}
+
+// resolveChart returns the chart reference and version releases are installed from. With a
+// chart registry that is the latest version published there; otherwise, or when nothing has
+// been published yet, it is the local chart path and an empty version.
+func (h *HelmDeployer) resolveChart(ctx context.Context) (chart string, version string) {
+	if h.chartRegistry == "" {
+		return h.chartPath, ""
+	}
+
+	meta, err := h.LocalChart()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read chart name, installing from the local chart")
+		return h.chartPath, ""
+	}
+
+	version, found, err := h.tracker.GetPublishedChartVersion(ctx, h.chartRegistry, meta.Name)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to resolve published chart version, installing from the local chart")
+		return h.chartPath, ""
+	}
+	if !found {
+		log.Warn().
+			Str("chart", meta.Name).
+			Str("registry", h.chartRegistry).
+			Msg("No chart version published to the registry, installing from the local chart")
+		return h.chartPath, ""
+	}
+
+	return strings.TrimSuffix(h.chartRegistry, "/") + "/" + meta.Name, version
+}
@@ -0,0 +1,534 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/googleapi"
+	run "google.golang.org/api/run/v2"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// cloudRunOperationTimeout bounds how long a single Cloud Run operation is waited on
+const cloudRunOperationTimeout = 10 * time.Minute
+
+// CloudRunDeployer implements Deployer by running the image as a Cloud Run service
+// instead of on a provisioned GKE cluster. The infrastructure record's KubeNamespace
+// holds the service region and HelmReleaseName holds the service name.
+type CloudRunDeployer struct {
+	tracker       *Tracker
+	service       *run.Service
+	project       string
+	region        string
+	defaultCPU    string
+	defaultMemory string
+}
+
+// CloudRunConfig holds Cloud Run deployer configuration
+type CloudRunConfig struct {
+	Project       string
+	Region        string // Default region when the infrastructure record has none
+	DefaultCPU    string // Default: 1
+	DefaultMemory string // Default: 512Mi
+}
+
+// NewCloudRunDeployer creates a new Cloud Run deployer using Application Default Credentials
+func NewCloudRunDeployer(ctx context.Context, config CloudRunConfig, tracker *Tracker) (*CloudRunDeployer, error) {
+	if config.Project == "" {
+		return nil, fmt.Errorf("GCP project is required for Cloud Run")
+	}
+
+	if config.Region == "" {
+		config.Region = "us-central1"
+	}
+
+	if config.DefaultCPU == "" {
+		config.DefaultCPU = "1"
+	}
+
+	if config.DefaultMemory == "" {
+		config.DefaultMemory = "512Mi"
+	}
+
+	service, err := run.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Run client: %w", err)
+	}
+
+	log.Info().
+		Str("project", config.Project).
+		Str("region", config.Region).
+		Msg("Cloud Run deployer initialized")
+
+	return &CloudRunDeployer{
+		tracker:       tracker,
+		service:       service,
+		project:       config.Project,
+		region:        config.Region,
+		defaultCPU:    config.DefaultCPU,
+		defaultMemory: config.DefaultMemory,
+	}, nil
+}
+
+// Deploy creates or updates the Cloud Run service for a deployment
+func (c *CloudRunDeployer) Deploy(ctx context.Context, req *DeployRequest) (*DeployResult, error) {
+	startTime := time.Now()
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("imageTag", req.ImageTag).
+		Msg("Starting Cloud Run deployment")
+
+	// Start deployment tracking
+	if err := c.tracker.StartDeployment(ctx, req.InfrastructureID); err != nil {
+		return nil, fmt.Errorf("failed to start deployment tracking: %w", err)
+	}
+
+	infra, err := c.tracker.GetInfrastructure(ctx, req.InfrastructureID)
+	if err != nil {
+		c.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	region := infra.ClusterLocation
+	if region == "" {
+		region = c.region
+	}
+
+	serviceName := infra.ServiceName
+	if serviceName == "" {
+		serviceName = fmt.Sprintf("app-%s", req.DeploymentID[:8])
+	}
+
+	fullName := c.serviceResourceName(region, serviceName)
+
+	// Patch with allowMissing creates the service on first deploy and updates it afterwards
+	op, err := c.service.Projects.Locations.Services.Patch(fullName, c.buildService(req)).
+		AllowMissing(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		c.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to deploy Cloud Run service: %w", err)
+	}
+
+	if err := c.waitForOperation(ctx, op); err != nil {
+		c.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("cloud run deployment did not complete: %w", err)
+	}
+
+	// Expose the service publicly, matching the LoadBalancer behaviour of GKE deployments
+	if err := c.allowPublicAccess(ctx, fullName); err != nil {
+		c.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to allow public access: %w", err)
+	}
+
+	svc, err := c.service.Projects.Locations.Services.Get(fullName).Context(ctx).Do()
+	if err != nil {
+		c.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to get Cloud Run service: %w", err)
+	}
+
+	result := &DeployResult{
+		ReleaseName: serviceName,
+		Namespace:   region,
+		ExternalURL: svc.Uri,
+		Status:      "deployed",
+		Message:     "Application deployed to Cloud Run successfully",
+		Duration:    time.Since(startTime),
+	}
+
+	// Complete deployment tracking
+	if err := c.tracker.CompleteDeployment(ctx, req.InfrastructureID, result); err != nil {
+		return nil, fmt.Errorf("failed to complete deployment tracking: %w", err)
+	}
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("service", serviceName).
+		Str("url", svc.Uri).
+		Dur("duration", result.Duration).
+		Msg("Cloud Run deployment completed successfully")
+
+	return result, nil
+}
+
+// Destroy deletes the Cloud Run service. Namespace is the service region and
+// ReleaseName the service name.
+func (c *CloudRunDeployer) Destroy(ctx context.Context, req *DestroyRequest) error {
+	log.Info().
+		Str("region", req.Namespace).
+		Str("service", req.ReleaseName).
+		Msg("Destroying Cloud Run service")
+
+	op, err := c.service.Projects.Locations.Services.Delete(c.serviceResourceName(req.Namespace, req.ReleaseName)).
+		Context(ctx).
+		Do()
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete Cloud Run service: %w", err)
+	}
+
+	if err := c.waitForOperation(ctx, op); err != nil {
+		return fmt.Errorf("cloud run deletion did not complete: %w", err)
+	}
+
+	log.Info().
+		Str("service", req.ReleaseName).
+		Msg("Cloud Run service destroyed successfully")
+
+	return nil
+}
+
+// GetStatus gets the status of a Cloud Run service. Namespace is the service region
+// and releaseName the service name.
+func (c *CloudRunDeployer) GetStatus(ctx context.Context, namespace, releaseName string) (*DeploymentStatus, error) {
+	svc, err := c.service.Projects.Locations.Services.Get(c.serviceResourceName(namespace, releaseName)).
+		Context(ctx).
+		Do()
+	if err != nil {
+		if isNotFound(err) {
+			return &DeploymentStatus{
+				ReleaseName: releaseName,
+				Namespace:   namespace,
+				Status:      "not_found",
+				UpdatedAt:   time.Now(),
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get Cloud Run service: %w", err)
+	}
+
+	status := &DeploymentStatus{
+		ReleaseName: releaseName,
+		Namespace:   namespace,
+		Status:      cloudRunStatus(svc),
+		Revision:    int(svc.Generation),
+		ExternalIP:  svc.Uri,
+	}
+
+	if updated, err := time.Parse(time.RFC3339, svc.UpdateTime); err == nil {
+		status.UpdatedAt = updated
+	}
+
+	return status, nil
+}
+
+// Rollback routes all traffic to an earlier revision of the Cloud Run service.
+// Revision is the revision sequence number as listed by History, 0 for the previous one.
+func (c *CloudRunDeployer) Rollback(ctx context.Context, req *RollbackRequest) error {
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("service", req.ReleaseName).
+		Int("revision", req.Revision).
+		Msg("Rolling back Cloud Run service")
+
+	fullName := c.serviceResourceName(req.Namespace, req.ReleaseName)
+
+	revisions, err := c.listRevisions(ctx, fullName)
+	if err != nil {
+		return err
+	}
+
+	var target *run.GoogleCloudRunV2Revision
+	if req.Revision > 0 {
+		for _, rev := range revisions {
+			if revisionNumber(rev.Name) == req.Revision {
+				target = rev
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("revision %d not found", req.Revision)
+		}
+	} else {
+		if len(revisions) < 2 {
+			return fmt.Errorf("no earlier revision to roll back to")
+		}
+		target = revisions[1]
+	}
+
+	svc, err := c.service.Projects.Locations.Services.Get(fullName).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get Cloud Run service: %w", err)
+	}
+
+	svc.Traffic = []*run.GoogleCloudRunV2TrafficTarget{
+		{
+			Type:     "TRAFFIC_TARGET_ALLOCATION_TYPE_REVISION",
+			Revision: shortResourceName(target.Name),
+			Percent:  100,
+		},
+	}
+
+	op, err := c.service.Projects.Locations.Services.Patch(fullName, svc).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to update Cloud Run traffic: %w", err)
+	}
+
+	if err := c.waitForOperation(ctx, op); err != nil {
+		return fmt.Errorf("cloud run rollback did not complete: %w", err)
+	}
+
+	log.Info().
+		Str("service", req.ReleaseName).
+		Str("revision", shortResourceName(target.Name)).
+		Msg("Rollback completed successfully")
+
+	return nil
+}
+
+// History lists the revisions of the infrastructure's Cloud Run service, oldest first
+func (c *CloudRunDeployer) History(ctx context.Context, infra *state.Infrastructure) ([]ReleaseRevision, error) {
+	if infra.HelmReleaseName == "" || infra.KubeNamespace == "" {
+		return nil, fmt.Errorf("infrastructure has no Cloud Run service")
+	}
+
+	revisions, err := c.listRevisions(ctx, c.serviceResourceName(infra.KubeNamespace, infra.HelmReleaseName))
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]ReleaseRevision, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		rev := revisions[i]
+
+		entry := ReleaseRevision{
+			Revision:    revisionNumber(rev.Name),
+			Status:      revisionStatus(rev),
+			Chart:       shortResourceName(rev.Name),
+			Description: "Cloud Run revision",
+		}
+		if len(rev.Containers) > 0 {
+			entry.AppVersion = rev.Containers[0].Image
+		}
+		if created, err := time.Parse(time.RFC3339, rev.CreateTime); err == nil {
+			entry.Updated = created
+		}
+
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
+
+// buildService converts a deploy request into a Cloud Run service definition
+func (c *CloudRunDeployer) buildService(req *DeployRequest) *run.GoogleCloudRunV2Service {
+	port := req.Port
+	if port == 0 {
+		port = 8080
+	}
+
+	cpu := req.CPULimit
+	if cpu == "" {
+		cpu = c.defaultCPU
+	}
+
+	memory := req.MemoryLimit
+	if memory == "" {
+		memory = c.defaultMemory
+	}
+
+	env := make([]*run.GoogleCloudRunV2EnvVar, 0, len(req.Env))
+	for name, value := range req.Env {
+		env = append(env, &run.GoogleCloudRunV2EnvVar{Name: name, Value: value})
+	}
+	sort.Slice(env, func(i, j int) bool { return env[i].Name < env[j].Name })
+
+	labels := map[string]string{
+		"deployment-id": req.DeploymentID,
+		"managed-by":    "app-deployer",
+	}
+	if req.Config != nil {
+		for k, v := range req.Config.Labels {
+			if _, ok := labels[k]; !ok {
+				labels[k] = v
+			}
+		}
+	}
+
+	return &run.GoogleCloudRunV2Service{
+		Labels:  labels,
+		Ingress: "INGRESS_TRAFFIC_ALL",
+		Template: &run.GoogleCloudRunV2RevisionTemplate{
+			Containers: []*run.GoogleCloudRunV2Container{
+				{
+					Image: req.ImageTag,
+					Ports: []*run.GoogleCloudRunV2ContainerPort{
+						{ContainerPort: int64(port)},
+					},
+					Env: env,
+					Resources: &run.GoogleCloudRunV2ResourceRequirements{
+						Limits: map[string]string{
+							"cpu":    cpu,
+							"memory": memory,
+						},
+					},
+				},
+			},
+		},
+		// Always serve the newest revision; Rollback pins an explicit one
+		Traffic: []*run.GoogleCloudRunV2TrafficTarget{
+			{
+				Type:    "TRAFFIC_TARGET_ALLOCATION_TYPE_LATEST",
+				Percent: 100,
+			},
+		},
+	}
+}
+
+// allowPublicAccess grants allUsers the invoker role on the service if not already granted
+func (c *CloudRunDeployer) allowPublicAccess(ctx context.Context, fullName string) error {
+	const invokerRole = "roles/run.invoker"
+
+	policy, err := c.service.Projects.Locations.Services.GetIamPolicy(fullName).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("get IAM policy: %w", err)
+	}
+
+	for _, binding := range policy.Bindings {
+		if binding.Role != invokerRole {
+			continue
+		}
+		for _, member := range binding.Members {
+			if member == "allUsers" {
+				return nil
+			}
+		}
+	}
+
+	policy.Bindings = append(policy.Bindings, &run.GoogleIamV1Binding{
+		Role:    invokerRole,
+		Members: []string{"allUsers"},
+	})
+
+	if _, err := c.service.Projects.Locations.Services.SetIamPolicy(fullName, &run.GoogleIamV1SetIamPolicyRequest{
+		Policy: policy,
+	}).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("set IAM policy: %w", err)
+	}
+
+	return nil
+}
+
+// listRevisions returns the service's revisions, newest first
+func (c *CloudRunDeployer) listRevisions(ctx context.Context, fullName string) ([]*run.GoogleCloudRunV2Revision, error) {
+	var revisions []*run.GoogleCloudRunV2Revision
+
+	err := c.service.Projects.Locations.Services.Revisions.List(fullName).
+		Context(ctx).
+		Pages(ctx, func(resp *run.GoogleCloudRunV2ListRevisionsResponse) error {
+			revisions = append(revisions, resp.Revisions...)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Cloud Run revisions: %w", err)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].CreateTime > revisions[j].CreateTime
+	})
+
+	return revisions, nil
+}
+
+// waitForOperation blocks until a long-running operation finishes
+func (c *CloudRunDeployer) waitForOperation(ctx context.Context, op *run.GoogleLongrunningOperation) error {
+	ctx, cancel := context.WithTimeout(ctx, cloudRunOperationTimeout)
+	defer cancel()
+
+	for !op.Done {
+		var err error
+		op, err = c.service.Projects.Locations.Operations.Wait(op.Name, &run.GoogleLongrunningWaitOperationRequest{
+			Timeout: "60s",
+		}).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("wait for operation: %w", err)
+		}
+	}
+
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %s", op.Error.Message)
+	}
+
+	return nil
+}
+
+// serviceResourceName builds the fully qualified Cloud Run service name
+func (c *CloudRunDeployer) serviceResourceName(region, serviceName string) string {
+	if region == "" {
+		region = c.region
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/services/%s", c.project, region, serviceName)
+}
+
+// cloudRunStatus maps a service's terminal condition to deployer status values
+func cloudRunStatus(svc *run.GoogleCloudRunV2Service) string {
+	if svc.Reconciling {
+		return "pending"
+	}
+
+	if svc.TerminalCondition != nil {
+		switch svc.TerminalCondition.State {
+		case "CONDITION_SUCCEEDED":
+			return "deployed"
+		case "CONDITION_FAILED":
+			return "failed"
+		}
+	}
+
+	return "pending"
+}
+
+// revisionStatus maps a revision's Ready condition to Helm-like status values
+func revisionStatus(rev *run.GoogleCloudRunV2Revision) string {
+	for _, cond := range rev.Conditions {
+		if cond.Type != "Ready" {
+			continue
+		}
+		switch cond.State {
+		case "CONDITION_SUCCEEDED":
+			return "deployed"
+		case "CONDITION_FAILED":
+			return "failed"
+		}
+	}
+	return "pending"
+}
+
+// shortResourceName returns the last path segment of a resource name
+func shortResourceName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// revisionNumber extracts the sequence number from a revision name such as "app-1a2b3c4d-00003-xyz"
+func revisionNumber(name string) int {
+	parts := strings.Split(shortResourceName(name), "-")
+	if len(parts) < 2 {
+		return 0
+	}
+
+	n, err := strconv.Atoi(parts[len(parts)-2])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// isNotFound reports whether a Google API error is a 404
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,65 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigMapName returns the name of the ConfigMap the base chart renders for a release.
+// It must match templates/configmap.yaml.
+func ConfigMapName(releaseName, name string) string {
+	return fmt.Sprintf("%s-%s", releaseName, name)
+}
+
+// DefaultConfigMapMountPath returns where a ConfigMap is mounted when no path is set
+func DefaultConfigMapMountPath(name string) string {
+	return "/etc/config/" + name
+}
+
+// ApplyConfigMap creates or updates a release's ConfigMap in place so running pods can pick
+// up new content without a Helm upgrade. The Helm ownership metadata lets the next upgrade
+// adopt a ConfigMap created here instead of failing on it.
+func ApplyConfigMap(ctx context.Context, kubeClient *KubeClient, namespace, releaseName string, mount ConfigMapMount) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(releaseName, mount.Name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/instance":   releaseName,
+				"app.kubernetes.io/managed-by": "Helm",
+			},
+			Annotations: map[string]string{
+				"meta.helm.sh/release-name":      releaseName,
+				"meta.helm.sh/release-namespace": namespace,
+			},
+		},
+		Data: mount.Data,
+	}
+
+	configMaps := kubeClient.GetClientset().CoreV1().ConfigMaps(namespace)
+
+	existing, err := configMaps.Get(ctx, configMap.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := configMaps.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create configmap: %w", err)
+		}
+		log.Info().Str("namespace", namespace).Str("configmap", configMap.Name).Msg("ConfigMap created")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get configmap: %w", err)
+	}
+
+	configMap.ResourceVersion = existing.ResourceVersion
+	if _, err := configMaps.Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update configmap: %w", err)
+	}
+
+	log.Info().Str("namespace", namespace).Str("configmap", configMap.Name).Msg("ConfigMap updated")
+	return nil
+}
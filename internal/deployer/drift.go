@@ -0,0 +1,78 @@
+package deployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ValuesDrift compares the values a release was deployed with against its live values and
+// returns the dotted paths that differ, sorted. Null values are ignored on both sides since
+// Helm treats them as unset.
+func ValuesDrift(desired, live map[string]interface{}) ([]string, error) {
+	want, err := normalizeValues(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize desired values: %w", err)
+	}
+
+	got, err := normalizeValues(live)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize live values: %w", err)
+	}
+
+	var paths []string
+	diffValues("", want, got, &paths)
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// normalizeValues round-trips values through JSON so numbers and nested maps compare equal
+// whichever decoder produced them
+func normalizeValues(values map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := map[string]interface{}{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// diffValues appends the paths under prefix whose values differ between want and got
+func diffValues(prefix string, want, got map[string]interface{}, paths *[]string) {
+	keys := map[string]struct{}{}
+	for key, value := range want {
+		if value != nil {
+			keys[key] = struct{}{}
+		}
+	}
+	for key, value := range got {
+		if value != nil {
+			keys[key] = struct{}{}
+		}
+	}
+
+	for key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		wantMap, wantIsMap := want[key].(map[string]interface{})
+		gotMap, gotIsMap := got[key].(map[string]interface{})
+		if wantIsMap && gotIsMap {
+			diffValues(path, wantMap, gotMap, paths)
+			continue
+		}
+
+		if !reflect.DeepEqual(want[key], got[key]) {
+			*paths = append(*paths, path)
+		}
+	}
+}
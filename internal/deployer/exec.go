@@ -0,0 +1,61 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// VerifyKubectlInstalled checks that the kubectl CLI is available
+func VerifyKubectlInstalled() error {
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return fmt.Errorf("kubectl CLI not found in PATH: %w", err)
+	}
+	return nil
+}
+
+// ExecInPod runs a command in a pod's container through kubectl exec, streaming stdin to it
+// and its output back. An empty container selects the pod's default container. The command
+// runs without a TTY and is stopped when ctx is done.
+func ExecInPod(ctx context.Context, infra *state.Infrastructure, podName, container string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	args := []string{"exec", "-i", "-n", infra.KubeNamespace, podName}
+	if container != "" {
+		args = append(args, "-c", container)
+	}
+	args = append(args, "--")
+	args = append(args, command...)
+
+	log.Info().
+		Str("namespace", infra.KubeNamespace).
+		Str("pod", podName).
+		Str("container", container).
+		Strs("command", command).
+		Msg("Starting pod exec")
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// stdin may block until the client sends more input, so don't wait on it once the command exits
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("kubectl exec failed: %w", err)
+	}
+
+	return nil
+}
@@ -2,10 +2,12 @@ package deployer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,6 +23,10 @@ type HelmDeployer struct {
 	chartPath       string
 	defaultReplicas int
 	defaultPort     int
+	networkPolicies bool
+	resourcePolicy  ResourceLimitPolicy
+	platformAddons  []PlatformAddon
+	chartRegistry   string
 }
 
 // Config holds deployer configuration
@@ -28,6 +34,19 @@ type Config struct {
 	ChartPath       string
 	DefaultReplicas int
 	DefaultPort     int
+
+	// Isolate each deployment namespace with a default NetworkPolicy.
+	// Requires ingress through an Istio ingress gateway.
+	EnableNetworkPolicies bool
+
+	// Limits enforced on every deploy unless an admin has set a policy through the API
+	ResourcePolicy ResourceLimitPolicy
+
+	// Chart versions platform addon upgrades move to by addon name, e.g. {"istio": "1.24.2"}
+	AddonChartVersions map[string]string
+
+	// Install the chart from this registry instead of ChartPath once a version is published
+	HelmChartRegistry HelmChartRegistry
 }
 
 // NewHelmDeployer creates a new Helm-based deployer
@@ -44,6 +63,10 @@ func NewHelmDeployer(config Config, tracker *Tracker) (*HelmDeployer, error) {
 		config.DefaultPort = 8080
 	}
 
+	if err := config.ResourcePolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid resource policy: %w", err)
+	}
+
 	// Verify Helm is installed
 	if err := verifyHelmInstalled(); err != nil {
 		return nil, err
@@ -59,6 +82,10 @@ func NewHelmDeployer(config Config, tracker *Tracker) (*HelmDeployer, error) {
 		chartPath:       config.ChartPath,
 		defaultReplicas: config.DefaultReplicas,
 		defaultPort:     config.DefaultPort,
+		networkPolicies: config.EnableNetworkPolicies,
+		resourcePolicy:  config.ResourcePolicy,
+		platformAddons:  PlatformAddons(config.AddonChartVersions),
+		chartRegistry:   config.HelmChartRegistry.RegistryURL,
 	}, nil
 }
 
@@ -81,6 +108,10 @@ func (h *HelmDeployer) Deploy(ctx context.Context, req *DeployRequest) (*DeployR
 		Str("imageTag", req.ImageTag).
 		Msg("Starting Helm deployment")
 
+	if err := h.checkResourcePolicy(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// Start deployment tracking
 	if err := h.tracker.StartDeployment(ctx, req.InfrastructureID); err != nil {
 		return nil, fmt.Errorf("failed to start deployment tracking: %w", err)
@@ -106,6 +137,10 @@ func (h *HelmDeployer) Deploy(ctx context.Context, req *DeployRequest) (*DeployR
 		namespace = fmt.Sprintf("deployer-%s", req.DeploymentID[:8])
 	}
 	releaseName := fmt.Sprintf("app-%s", req.DeploymentID[:8])
+	if infra.HelmReleaseName != "" {
+		// Adopted releases keep the name they were installed with
+		releaseName = infra.HelmReleaseName
+	}
 
 	// Create namespace
 	labels := map[string]string{
@@ -118,6 +153,13 @@ func (h *HelmDeployer) Deploy(ctx context.Context, req *DeployRequest) (*DeployR
 		return nil, fmt.Errorf("failed to create namespace: %w", err)
 	}
 
+	if h.networkPolicies {
+		if err := ApplyDefaultNetworkPolicy(ctx, kubeClient, namespace, req.AppName); err != nil {
+			h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+			return nil, fmt.Errorf("failed to apply network policy: %w", err)
+		}
+	}
+
 	// Generate Helm values
 	values, err := h.generateValues(req, infra)
 	if err != nil {
@@ -133,38 +175,93 @@ func (h *HelmDeployer) Deploy(ctx context.Context, req *DeployRequest) (*DeployR
 	}
 	defer os.Remove(valuesFile)
 
+	// Pre-deploy hooks (e.g. migrations) must succeed before the release changes
+	if err := runHooks(ctx, kubeClient, h.tracker, req, namespace, HookPhasePreDeploy, req.Hooks.PreDeploy); err != nil {
+		h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, err
+	}
+
 	// Install or upgrade Helm release
 	if err := h.installOrUpgrade(ctx, releaseName, namespace, valuesFile, infra); err != nil {
 		h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
 		return nil, fmt.Errorf("helm install/upgrade failed: %w", err)
 	}
 
-	// Wait for pods to be ready
-	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", releaseName)
-	if err := kubeClient.WaitForPodsReady(ctx, namespace, labelSelector, 5*time.Minute); err != nil {
-		h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
-		return nil, fmt.Errorf("pods failed to become ready: %w", err)
+	if err := h.tracker.RecordHelmValues(ctx, req.InfrastructureID, values); err != nil {
+		log.Warn().Err(err).Msg("Failed to record Helm values")
 	}
 
-	// Get LoadBalancer external IP
-	serviceName := releaseName
-	externalIP, err := kubeClient.GetLoadBalancerIP(ctx, namespace, serviceName, 5*time.Minute)
-	if err != nil {
-		h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
-		return nil, fmt.Errorf("failed to get external IP: %w", err)
+	// Charts configured in place of the base chart may ignore extraManifests, so the budget
+	// is applied directly instead
+	if pdb := pdbConfig(req); pdb != nil && exposesService(req.DeploymentType) && !chartRendersExtraManifests(h.chartPath) {
+		replicas := req.Replicas
+		if replicas == 0 {
+			replicas = h.defaultReplicas
+		}
+		if err := kubeClient.ApplyPDB(ctx, namespace, releaseName, pdb.minAvailable(replicas)); err != nil {
+			h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+			return nil, fmt.Errorf("failed to apply pod disruption budget: %w", err)
+		}
 	}
 
 	// Build result
 	result := &DeployResult{
 		ReleaseName: releaseName,
 		Namespace:   namespace,
-		ExternalIP:  externalIP,
-		ExternalURL: fmt.Sprintf("http://%s", externalIP),
 		Status:      "deployed",
 		Message:     "Application deployed successfully",
-		Duration:    time.Since(startTime),
 	}
 
+	// Cronjobs and jobs have no long-running pods or Service to wait for
+	if exposesService(req.DeploymentType) {
+		// Wait for pods to be ready
+		labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", releaseName)
+		watch := &ContainerWatch{
+			OnReady:          h.sidecarStartupLogger(ctx, infra.DeploymentID, req.Config),
+			OnInitTerminated: h.initContainerLogger(ctx, kubeClient, namespace, infra.DeploymentID, req.Config),
+		}
+		if err := kubeClient.WaitForPodContainersReady(ctx, namespace, labelSelector, podsReadyTimeout(req.Config), watch); err != nil {
+			h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+			return nil, fmt.Errorf("pods failed to become ready: %w", err)
+		}
+
+		// Get LoadBalancer external IP
+		serviceName := releaseName
+		externalIP, err := kubeClient.GetLoadBalancerIP(ctx, namespace, serviceName, 5*time.Minute)
+		if err != nil {
+			h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+			return nil, fmt.Errorf("failed to get external IP: %w", err)
+		}
+
+		result.ExternalIP = externalIP
+		result.ExternalURL = fmt.Sprintf("http://%s", externalIP)
+
+		// Volume claims are created by the StatefulSet controller, so record what now exists
+		if req.DeploymentType == DeploymentTypeStatefulSet {
+			volumes, err := kubeClient.ListPersistentVolumeClaims(ctx, namespace, labelSelector)
+			if err != nil {
+				log.Warn().Err(err).Msg("Failed to list persistent volume claims")
+			} else {
+				names := make([]string, 0, len(volumes))
+				for _, volume := range volumes {
+					names = append(names, volume.Name)
+				}
+				if err := h.tracker.RecordPVCNames(ctx, req.InfrastructureID, names); err != nil {
+					log.Warn().Err(err).Msg("Failed to record persistent volume claims")
+				}
+			}
+		}
+	} else {
+		result.Message = fmt.Sprintf("%s scheduled successfully", req.DeploymentType)
+	}
+
+	if err := runHooks(ctx, kubeClient, h.tracker, req, namespace, HookPhasePostDeploy, req.Hooks.PostDeploy); err != nil {
+		h.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, err
+	}
+
+	result.Duration = time.Since(startTime)
+
 	// Complete deployment tracking
 	if err := h.tracker.CompleteDeployment(ctx, req.InfrastructureID, result); err != nil {
 		return nil, fmt.Errorf("failed to complete deployment tracking: %w", err)
@@ -173,13 +270,34 @@ func (h *HelmDeployer) Deploy(ctx context.Context, req *DeployRequest) (*DeployR
 	log.Info().
 		Str("deploymentID", req.DeploymentID).
 		Str("releaseName", releaseName).
-		Str("externalIP", externalIP).
+		Str("externalIP", result.ExternalIP).
 		Dur("duration", result.Duration).
 		Msg("Helm deployment completed successfully")
 
 	return result, nil
 }
 
+// checkResourcePolicy rejects deploys whose limits or replica count exceed the resource policy
+func (h *HelmDeployer) checkResourcePolicy(ctx context.Context, req *DeployRequest) error {
+	policy := h.resourcePolicy
+	if stored, found, err := h.tracker.GetResourcePolicy(ctx); err != nil {
+		return fmt.Errorf("failed to get resource policy: %w", err)
+	} else if found {
+		policy = stored
+	}
+
+	replicas := req.Replicas
+	if replicas == 0 {
+		replicas = h.defaultReplicas
+	}
+
+	if err := policy.Check(req.CPULimit, req.MemoryLimit, replicas); err != nil {
+		return fmt.Errorf("resource policy violation: %w", err)
+	}
+
+	return nil
+}
+
 // Destroy removes a Helm deployment
 func (h *HelmDeployer) Destroy(ctx context.Context, req *DestroyRequest) error {
 	log.Info().
@@ -201,12 +319,19 @@ func (h *HelmDeployer) Destroy(ctx context.Context, req *DestroyRequest) error {
 	}
 
 	// Setup kubeconfig environment
-	kubeconfigPath, cleanup, err := h.setupKubeconfig(infra)
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
 	if err != nil {
 		return fmt.Errorf("failed to setup kubeconfig: %w", err)
 	}
 	defer cleanup()
 
+	// Remove disruption budgets first, so evictions of the release's pods are not blocked
+	// while it is uninstalled
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", req.ReleaseName)
+	if err := kubeClient.DeletePDBs(ctx, req.Namespace, labelSelector); err != nil {
+		log.Warn().Err(err).Msg("Failed to delete pod disruption budgets")
+	}
+
 	// Uninstall Helm release
 	cmd := exec.CommandContext(ctx, "helm", "uninstall", req.ReleaseName, "-n", req.Namespace)
 	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
@@ -219,9 +344,40 @@ func (h *HelmDeployer) Destroy(ctx context.Context, req *DestroyRequest) error {
 			Msg("Helm uninstall failed (may already be deleted)")
 	}
 
-	// Delete namespace
-	if err := kubeClient.DeleteNamespace(ctx, req.Namespace); err != nil {
-		log.Warn().Err(err).Msg("Failed to delete namespace (may already be deleted)")
+	// StatefulSet volume claims outlive the release; keep them (and their namespace) unless asked
+	volumes, err := kubeClient.ListPersistentVolumeClaims(ctx, req.Namespace, labelSelector)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to list persistent volume claims")
+	}
+
+	if len(volumes) > 0 && !req.DeleteVolumes {
+		log.Info().
+			Str("namespace", req.Namespace).
+			Int("volumes", len(volumes)).
+			Msg("Keeping persistent volume claims and namespace")
+
+		// The retained namespace stays isolated until its volumes are deleted
+		if h.networkPolicies {
+			if err := ApplyDefaultNetworkPolicy(ctx, kubeClient, req.Namespace, infra.ServiceName); err != nil {
+				log.Warn().Err(err).Msg("Failed to apply network policy to retained namespace")
+			}
+		}
+	} else {
+		if len(volumes) > 0 {
+			if err := kubeClient.DeletePersistentVolumeClaims(ctx, req.Namespace, labelSelector); err != nil {
+				log.Warn().Err(err).Msg("Failed to delete persistent volume claims")
+			}
+		}
+
+		// Leave the namespace on imported clusters and adopted releases, whose owners may run
+		// other workloads in it
+		if infra.ImportedExternally || infra.AdoptedExternally {
+			log.Info().
+				Str("namespace", req.Namespace).
+				Msg("Keeping namespace on imported cluster")
+		} else if err := kubeClient.DeleteNamespace(ctx, req.Namespace); err != nil {
+			log.Warn().Err(err).Msg("Failed to delete namespace (may already be deleted)")
+		}
 	}
 
 	log.Info().
@@ -287,6 +443,8 @@ func (h *HelmDeployer) GetStatus(ctx context.Context, namespace, releaseName str
 		UpdatedAt:   helmStatus.Info.LastDeployed,
 	}
 
+	h.addWorkloadStatus(ctx, status)
+
 	log.Debug().
 		Str("status", status.Status).
 		Int("revision", status.Revision).
@@ -310,7 +468,7 @@ func (h *HelmDeployer) Rollback(ctx context.Context, req *RollbackRequest) error
 	}
 
 	// Setup kubeconfig
-	kubeconfigPath, cleanup, err := h.setupKubeconfig(infra)
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
 	if err != nil {
 		return fmt.Errorf("failed to setup kubeconfig: %w", err)
 	}
@@ -338,6 +496,265 @@ func (h *HelmDeployer) Rollback(ctx context.Context, req *RollbackRequest) error
 	return nil
 }
 
+// History returns the revision history of the infrastructure's Helm release, oldest first
+func (h *HelmDeployer) History(ctx context.Context, infra *state.Infrastructure) ([]ReleaseRevision, error) {
+	if infra.HelmReleaseName == "" || infra.KubeNamespace == "" {
+		return nil, fmt.Errorf("infrastructure has no Helm release")
+	}
+
+	// Setup kubeconfig
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	cmd := exec.CommandContext(ctx, "helm", "history", infra.HelmReleaseName,
+		"-n", infra.KubeNamespace,
+		"-o", "json",
+	)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get helm history: %w", err)
+	}
+
+	var entries []struct {
+		Revision    int       `json:"revision"`
+		Updated     time.Time `json:"updated"`
+		Status      string    `json:"status"`
+		Chart       string    `json:"chart"`
+		AppVersion  string    `json:"app_version"`
+		Description string    `json:"description"`
+	}
+
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse helm history: %w", err)
+	}
+
+	revisions := make([]ReleaseRevision, 0, len(entries))
+	for _, e := range entries {
+		revisions = append(revisions, ReleaseRevision{
+			Revision:    e.Revision,
+			Updated:     e.Updated,
+			Status:      e.Status,
+			Chart:       e.Chart,
+			AppVersion:  e.AppVersion,
+			Description: e.Description,
+		})
+	}
+
+	return revisions, nil
+}
+
+// InspectRelease reads a Helm release on the infrastructure's cluster, which need not have
+// been installed by the deployer. found is false when there is no such release.
+func (h *HelmDeployer) InspectRelease(ctx context.Context, infra *state.Infrastructure, namespace, releaseName string) (release *ReleaseInfo, found bool, err error) {
+	// Setup kubeconfig
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	cmd := exec.CommandContext(ctx, "helm", "status", releaseName,
+		"-n", namespace,
+		"-o", "json",
+	)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && strings.Contains(string(exitErr.Stderr), "not found") {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get helm status: %w", err)
+	}
+
+	var helmStatus struct {
+		Name string `json:"name"`
+		Info struct {
+			Status       string    `json:"status"`
+			LastDeployed time.Time `json:"last_deployed"`
+		} `json:"info"`
+		Chart struct {
+			Metadata struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"metadata"`
+		} `json:"chart"`
+		Config    map[string]interface{} `json:"config"`
+		Version   int                    `json:"version"`
+		Namespace string                 `json:"namespace"`
+	}
+
+	if err := json.Unmarshal(output, &helmStatus); err != nil {
+		return nil, false, fmt.Errorf("failed to parse helm status: %w", err)
+	}
+
+	release = &ReleaseInfo{
+		Name:         helmStatus.Name,
+		Namespace:    helmStatus.Namespace,
+		Status:       helmStatus.Info.Status,
+		Revision:     helmStatus.Version,
+		Chart:        fmt.Sprintf("%s-%s", helmStatus.Chart.Metadata.Name, helmStatus.Chart.Metadata.Version),
+		LastDeployed: helmStatus.Info.LastDeployed,
+		Values:       helmStatus.Config,
+	}
+	if release.Values == nil {
+		release.Values = map[string]interface{}{}
+	}
+
+	return release, true, nil
+}
+
+// GetValues returns the user-supplied values of the infrastructure's live Helm release
+func (h *HelmDeployer) GetValues(ctx context.Context, infra *state.Infrastructure) (map[string]interface{}, error) {
+	if infra.HelmReleaseName == "" || infra.KubeNamespace == "" {
+		return nil, fmt.Errorf("infrastructure has no Helm release")
+	}
+
+	// Setup kubeconfig
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	return releaseValues(ctx, kubeconfigPath, infra, 0)
+}
+
+// RollbackOptions returns the revisions of the infrastructure's Helm release that can be
+// rolled back to, newest first, each with the image tag it deployed
+func (h *HelmDeployer) RollbackOptions(ctx context.Context, infra *state.Infrastructure) ([]RollbackOption, error) {
+	revisions, err := h.History(ctx, infra)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup kubeconfig
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	options := make([]RollbackOption, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		rev := revisions[i]
+		// Failed and pending revisions never ran, so there is nothing to return to
+		if rev.Status != "superseded" && rev.Status != "deployed" {
+			continue
+		}
+
+		values, err := releaseValues(ctx, kubeconfigPath, infra, rev.Revision)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, RollbackOption{
+			Revision:   rev.Revision,
+			Status:     rev.Status,
+			ImageTag:   valuesImageTag(values),
+			DeployedAt: rev.Updated,
+		})
+	}
+
+	return options, nil
+}
+
+// releaseValues runs helm get values for a revision of the infrastructure's release, or for
+// the live revision when revision is 0
+func releaseValues(ctx context.Context, kubeconfigPath string, infra *state.Infrastructure, revision int) (map[string]interface{}, error) {
+	args := []string{"get", "values", infra.HelmReleaseName,
+		"-n", infra.KubeNamespace,
+		"-o", "json",
+	}
+	if revision > 0 {
+		args = append(args, "--revision", fmt.Sprintf("%d", revision))
+	}
+
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get helm values: %w", err)
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(output, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse helm values: %w", err)
+	}
+
+	return values, nil
+}
+
+// valuesImageTag returns image.tag from Helm values, or "" when it is not set
+func valuesImageTag(values map[string]interface{}) string {
+	image, ok := values["image"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	tag, _ := image["tag"].(string)
+	return tag
+}
+
+// addWorkloadStatus fills in live workload details: the last schedule time for cronjobs,
+// pod readiness for everything else. Failures are logged and leave the Helm status as-is.
+func (h *HelmDeployer) addWorkloadStatus(ctx context.Context, status *DeploymentStatus) {
+	infra, err := h.tracker.GetInfrastructureByRelease(ctx, status.Namespace, status.ReleaseName)
+	if err != nil {
+		log.Debug().Err(err).Str("release", status.ReleaseName).Msg("No infrastructure found for release")
+		return
+	}
+
+	kubeClient, err := NewKubeClient(infra)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to create Kubernetes client for status")
+		return
+	}
+
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", status.ReleaseName)
+
+	lastSchedule, isCronJob, err := kubeClient.GetCronJobLastSchedule(ctx, status.Namespace, labelSelector)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get cronjob status")
+		return
+	}
+
+	if isCronJob {
+		status.LastScheduleTime = lastSchedule
+		return
+	}
+
+	ready, total, err := kubeClient.GetPodCount(ctx, status.Namespace, labelSelector)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get pod status")
+		return
+	}
+
+	status.ReadyReplicas = ready
+	status.TotalReplicas = total
+}
+
+// pdbConfig returns the disruption budget requested for the app, or nil when there is none
+func pdbConfig(req *DeployRequest) *PDBConfig {
+	if req.Config == nil || !req.Config.EnablePDB {
+		return nil
+	}
+	if req.Config.PDB == nil {
+		return &PDBConfig{}
+	}
+	return req.Config.PDB
+}
+
+// exposesService reports whether a deployment type runs long-lived pods behind a LoadBalancer Service
+func exposesService(deploymentType string) bool {
+	return deploymentType == "" || deploymentType == DeploymentTypeService || deploymentType == DeploymentTypeStatefulSet
+}
+
 // generateValues generates Helm values from deployment request
 func (h *HelmDeployer) generateValues(req *DeployRequest, infra *state.Infrastructure) (map[string]interface{}, error) {
 	replicas := req.Replicas
@@ -386,6 +803,110 @@ func (h *HelmDeployer) generateValues(req *DeployRequest, infra *state.Infrastru
 		},
 	}
 
+	// Deployment tags are added to the release's labels without replacing the ones above
+	if req.Config != nil {
+		labels := values["labels"].(map[string]interface{})
+		for k, v := range req.Config.Labels {
+			if _, ok := labels[k]; !ok {
+				labels[k] = v
+			}
+		}
+	}
+
+	if req.DeploymentType != "" && req.DeploymentType != DeploymentTypeService {
+		values["workloadType"] = req.DeploymentType
+	}
+
+	if req.DeploymentType == DeploymentTypeStatefulSet && req.Config != nil && req.Config.StorageSize != "" {
+		mountPath := req.Config.StorageMountPath
+		if mountPath == "" {
+			mountPath = "/data"
+		}
+
+		values["storage"] = map[string]interface{}{
+			"size":         req.Config.StorageSize,
+			"storageClass": req.Config.StorageClass,
+			"mountPath":    mountPath,
+		}
+	}
+
+	if req.DeploymentType == DeploymentTypeCronJob {
+		if req.Config == nil || req.Config.CronJob == nil || req.Config.CronJob.Schedule == "" {
+			return nil, fmt.Errorf("cronjob deployments require a schedule")
+		}
+
+		cronJob := req.Config.CronJob
+		concurrency := cronJob.Concurrency
+		if concurrency == "" {
+			concurrency = "Allow"
+		}
+
+		values["cronJob"] = map[string]interface{}{
+			"schedule":                cronJob.Schedule,
+			"concurrencyPolicy":       concurrency,
+			"startingDeadlineSeconds": cronJob.StartingDeadlineSeconds,
+		}
+	}
+
+	if req.Config != nil && req.Config.WorkloadIdentity != nil && req.Config.WorkloadIdentity.GCPServiceAccountEmail != "" {
+		identity := req.Config.WorkloadIdentity
+		values["serviceAccount"] = map[string]interface{}{
+			"create": true,
+			"name":   identity.KubernetesServiceAccount,
+			"annotations": map[string]interface{}{
+				"iam.gke.io/gcp-service-account": identity.GCPServiceAccountEmail,
+			},
+		}
+	}
+
+	// Cloud Armor policies attach to backend services, which GKE only creates for
+	// LoadBalancer Services that are backend service based
+	if req.Config != nil && req.Config.WAF != nil && req.Config.WAF.EnableCloudArmor {
+		service := values["service"].(map[string]interface{})
+		service["annotations"] = map[string]interface{}{
+			"cloud.google.com/l4-rbs": "enabled",
+		}
+	}
+
+	if autoscaling := autoscalingValues(req, infra); autoscaling != nil {
+		values["autoscaling"] = autoscaling
+	}
+
+	if req.Config != nil && req.Config.EnableVPA {
+		values["vpa"] = map[string]interface{}{
+			"enabled": true,
+		}
+	}
+
+	if req.Config != nil && req.Config.NodeAffinity != nil {
+		if err := req.Config.NodeAffinity.Validate(); err != nil {
+			return nil, err
+		}
+		placementValues(values, req.Config.NodeAffinity)
+	}
+
+	if req.Config != nil && len(req.Config.Sidecars) > 0 {
+		if err := ValidateSidecars(req.Config.Sidecars); err != nil {
+			return nil, err
+		}
+		sidecarValues(values, req.Config.Sidecars, req.ConfigMaps)
+	}
+
+	if req.Config != nil && len(req.Config.InitContainers) > 0 {
+		if err := ValidateInitContainers(req.Config.InitContainers); err != nil {
+			return nil, err
+		}
+		initContainerValues(values, req)
+	}
+
+	// Cronjob and job pods run to completion, so there is nothing to keep available
+	if pdb := pdbConfig(req); pdb != nil && exposesService(req.DeploymentType) {
+		if err := pdb.Validate(); err != nil {
+			return nil, err
+		}
+		values["extraManifests"] = []interface{}{pdbManifest(pdb)}
+	}
+
 	// Add environment variables if provided
 	if len(req.Env) > 0 {
 		envVars := make([]map[string]interface{}, 0, len(req.Env))
@@ -398,9 +919,115 @@ func (h *HelmDeployer) generateValues(req *DeployRequest, infra *state.Infrastru
 		values["env"] = envVars
 	}
 
+	// The base chart renders each ConfigMap and mounts it into the app container
+	if len(req.ConfigMaps) > 0 {
+		configMaps := make([]map[string]interface{}, 0, len(req.ConfigMaps))
+		for _, cm := range req.ConfigMaps {
+			mountPath := cm.MountPath
+			if mountPath == "" {
+				mountPath = DefaultConfigMapMountPath(cm.Name)
+			}
+			configMaps = append(configMaps, map[string]interface{}{
+				"name":      cm.Name,
+				"mountPath": mountPath,
+				"data":      cm.Data,
+			})
+		}
+		values["extraConfigMaps"] = configMaps
+	}
+
 	return values, nil
 }
 
+// autoscalingValues returns the chart's autoscaling values, or nil when the app is not
+// autoscaled. Settings stored on the infrastructure take precedence over the deploy request
+// so HPA changes made after the deploy survive later deploys.
+func autoscalingValues(req *DeployRequest, infra *state.Infrastructure) map[string]interface{} {
+	if req.DeploymentType != "" && req.DeploymentType != DeploymentTypeService {
+		return nil
+	}
+
+	if hpa := infra.HPAConfig; hpa != nil {
+		return map[string]interface{}{
+			"enabled":                           true,
+			"minReplicas":                       hpa.MinReplicas,
+			"maxReplicas":                       hpa.MaxReplicas,
+			"targetCPUUtilizationPercentage":    hpa.TargetCPUPercent,
+			"targetMemoryUtilizationPercentage": hpa.TargetMemoryPercent,
+		}
+	}
+
+	if req.Config != nil && req.Config.EnableHPA {
+		return map[string]interface{}{
+			"enabled":                           true,
+			"minReplicas":                       req.Config.MinReplicas,
+			"maxReplicas":                       req.Config.MaxReplicas,
+			"targetCPUUtilizationPercentage":    req.Config.TargetCPU,
+			"targetMemoryUtilizationPercentage": req.Config.TargetMemory,
+		}
+	}
+
+	return nil
+}
+
+// UpgradeValues changes some values of a release in place, keeping the rest of the values
+// it was deployed with. Keys use Helm's --set syntax, e.g. autoscaling.minReplicas.
+func (h *HelmDeployer) UpgradeValues(ctx context.Context, infra *state.Infrastructure, values map[string]string) error {
+	log.Info().
+		Str("releaseName", infra.HelmReleaseName).
+		Str("namespace", infra.KubeNamespace).
+		Int("values", len(values)).
+		Msg("Upgrading Helm release values")
+
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	chart, chartVersion := h.resolveChart(ctx)
+	args := []string{"upgrade",
+		infra.HelmReleaseName,
+		chart,
+		"-n", infra.KubeNamespace,
+		"--reuse-values",
+		"--wait",
+		"--timeout", "5m",
+	}
+	if chartVersion != "" {
+		args = append(args, "--version", chartVersion)
+	}
+	for _, key := range keys {
+		args = append(args, "--set", fmt.Sprintf("%s=%s", key, values[key]))
+	}
+
+	cmd := exec.CommandContext(ctx, "helm", args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+
+	output, err := cmd.CombinedOutput()
+	log.Debug().Str("output", string(output)).Msg("Helm output")
+
+	if err != nil {
+		return fmt.Errorf("helm upgrade failed: %w, output: %s", err, string(output))
+	}
+
+	// Keep the recorded values current so GitOps reconciliation does not revert this upgrade
+	live, err := h.GetValues(ctx, infra)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get Helm values after upgrade")
+	} else if err := h.tracker.RecordHelmValues(ctx, infra.ID.String(), live); err != nil {
+		log.Warn().Err(err).Msg("Failed to record Helm values")
+	}
+
+	return nil
+}
+
 // writeValuesFile writes values to a temporary YAML file
 func (h *HelmDeployer) writeValuesFile(values map[string]interface{}) (string, error) {
 	tmpDir := os.TempDir()
@@ -425,25 +1052,41 @@ func (h *HelmDeployer) installOrUpgrade(ctx context.Context, releaseName, namesp
 		Str("namespace", namespace).
 		Msg("Installing/upgrading Helm release")
 
+	chart, chartVersion := h.resolveChart(ctx)
+
+	// Catch chart and values errors before anything reaches the cluster. Lint needs a chart
+	// directory, so the local chart stands in for the published version it was packaged from.
+	issues, err := h.Lint(ctx, h.chartPath, valuesFile)
+	for _, issue := range issues {
+		h.tracker.RecordDeploymentLog(ctx, infra.DeploymentID, "DEPLOYING", issue.Severity, "helm-lint", issue.Message)
+	}
+	if err != nil {
+		return err
+	}
+
 	// Setup kubeconfig
-	kubeconfigPath, cleanup, err := h.setupKubeconfig(infra)
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
 	if err != nil {
 		return fmt.Errorf("failed to setup kubeconfig: %w", err)
 	}
 	defer cleanup()
 
 	// Helm upgrade --install command
-	cmd := exec.CommandContext(ctx, "helm", "upgrade",
+	args := []string{"upgrade",
 		releaseName,
-		h.chartPath,
+		chart,
 		"--install",
 		"--create-namespace",
 		"-n", namespace,
 		"-f", valuesFile,
 		"--wait",
 		"--timeout", "10m",
-	)
+	}
+	if chartVersion != "" {
+		args = append(args, "--version", chartVersion)
+	}
 
+	cmd := exec.CommandContext(ctx, "helm", args...)
 	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
 
 	output, err := cmd.CombinedOutput()
@@ -456,8 +1099,62 @@ func (h *HelmDeployer) installOrUpgrade(ctx context.Context, releaseName, namesp
 	return nil
 }
 
+// Lint runs helm lint against a chart with the given values file. It returns all reported
+// issues, and a *LintError when any of them are at ERROR level.
+func (h *HelmDeployer) Lint(ctx context.Context, chartPath, valuesFile string) ([]LintIssue, error) {
+	cmd := exec.CommandContext(ctx, "helm", "lint", chartPath, "-f", valuesFile)
+	output, runErr := cmd.CombinedOutput()
+
+	issues := parseLintOutput(string(output))
+
+	for _, issue := range issues {
+		if issue.Severity == LintSeverityError {
+			return issues, &LintError{Issues: issues}
+		}
+	}
+
+	// helm lint exits non-zero for errors it reports; anything else means it did not run
+	if runErr != nil {
+		return issues, fmt.Errorf("helm lint failed: %w, output: %s", runErr, string(output))
+	}
+
+	log.Debug().
+		Str("chartPath", chartPath).
+		Int("issues", len(issues)).
+		Msg("Helm lint passed")
+
+	return issues, nil
+}
+
+// parseLintOutput extracts "[SEVERITY] message" lines from helm lint output
+func parseLintOutput(output string) []LintIssue {
+	var issues []LintIssue
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		severity, message, ok := strings.Cut(line[1:], "]")
+		if !ok {
+			continue
+		}
+
+		switch severity {
+		case LintSeverityInfo, LintSeverityWarning, LintSeverityError:
+			issues = append(issues, LintIssue{
+				Severity: severity,
+				Message:  strings.TrimSpace(message),
+			})
+		}
+	}
+
+	return issues
+}
+
 // setupKubeconfig creates a temporary kubeconfig file and returns cleanup function
-func (h *HelmDeployer) setupKubeconfig(infra *state.Infrastructure) (string, func(), error) {
+func setupKubeconfig(infra *state.Infrastructure) (string, func(), error) {
 	// Create Kubernetes client to verify connectivity
 	_, err := NewKubeClient(infra)
 	if err != nil {
@@ -469,6 +1166,18 @@ func (h *HelmDeployer) setupKubeconfig(infra *state.Infrastructure) (string, fun
 	tmpDir := os.TempDir()
 	kubeconfigPath := filepath.Join(tmpDir, fmt.Sprintf("kubeconfig-%d", time.Now().UnixNano()))
 
+	cleanup := func() {
+		os.Remove(kubeconfigPath)
+	}
+
+	// Imported clusters may live outside GCP_PROJECT, so their stored credentials are used as-is
+	if infra.ImportedExternally {
+		if err := writeKubeConfig(infra, kubeconfigPath); err != nil {
+			return "", nil, fmt.Errorf("failed to write kubeconfig: %w", err)
+		}
+		return kubeconfigPath, cleanup, nil
+	}
+
 	// Use gcloud to get cluster credentials
 	cmd := exec.Command("gcloud", "container", "clusters", "get-credentials",
 		infra.ClusterName,
@@ -482,9 +1191,5 @@ func (h *HelmDeployer) setupKubeconfig(infra *state.Infrastructure) (string, fun
 		return "", nil, fmt.Errorf("failed to get cluster credentials: %w, output: %s", err, string(output))
 	}
 
-	cleanup := func() {
-		os.Remove(kubeconfigPath)
-	}
-
 	return kubeconfigPath, cleanup, nil
 }
@@ -0,0 +1,63 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Hook phases, used in Job names and log entries
+const (
+	HookPhasePreDeploy  = "pre-deploy"
+	HookPhasePostDeploy = "post-deploy"
+)
+
+// runHooks runs hooks one after another as Jobs in the deployment namespace, stopping at the
+// first failure. Each hook gets the app's environment, overridden by its own Env.
+func runHooks(ctx context.Context, kubeClient *KubeClient, tracker *Tracker, req *DeployRequest, namespace, phase string, hooks []HookSpec) error {
+	deploymentID, err := uuid.Parse(req.DeploymentID)
+	if err != nil {
+		return fmt.Errorf("invalid deployment ID: %w", err)
+	}
+
+	for i, hook := range hooks {
+		name := fmt.Sprintf("app-%s-%s-%d-%d", req.DeploymentID[:8], phase, i, time.Now().Unix())
+
+		env := make(map[string]string, len(req.Env)+len(hook.Env))
+		for key, value := range req.Env {
+			env[key] = value
+		}
+		for key, value := range hook.Env {
+			env[key] = value
+		}
+
+		// Not labelled deployment-id, so finished hook pods never match app readiness selectors
+		labels := map[string]string{
+			"hook-for":   req.DeploymentID,
+			"hook-phase": phase,
+			"managed-by": "app-deployer",
+		}
+
+		started := time.Now()
+		if err := kubeClient.RunJob(ctx, namespace, name, labels, env, hook); err != nil {
+			tracker.RecordDeploymentLog(ctx, deploymentID, "DEPLOYING", "ERROR", phase+"-hook",
+				fmt.Sprintf("Hook %s (%s) failed: %v", name, hook.Image, err))
+			return fmt.Errorf("%s hook %d failed: %w", phase, i, err)
+		}
+
+		duration := time.Since(started).Round(time.Second)
+		tracker.RecordDeploymentLog(ctx, deploymentID, "DEPLOYING", "INFO", phase+"-hook",
+			fmt.Sprintf("Hook %s (%s) succeeded in %v", name, hook.Image, duration))
+
+		log.Info().
+			Str("deploymentID", req.DeploymentID).
+			Str("job", name).
+			Dur("duration", duration).
+			Msg("Hook completed")
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// initContainerLogTailLines bounds the log lines of an init container run kept in the deployment logs
+const initContainerLogTailLines = 100
+
+// defaultInitContainerTimeout is how long an init container with no timeout is waited for
+const defaultInitContainerTimeout = 5 * time.Minute
+
+// ValidateInitContainers checks the environment and timeouts of init containers
+func ValidateInitContainers(initContainers []InitContainerConfig) error {
+	for i, init := range initContainers {
+		if init.TimeoutSeconds < 0 {
+			return fmt.Errorf("init container %d: timeout must not be negative", i)
+		}
+		for key := range init.Env {
+			if errs := validation.IsEnvVarName(key); len(errs) > 0 {
+				return fmt.Errorf("init container %d: invalid environment variable %q: %s", i, key, strings.Join(errs, "; "))
+			}
+		}
+	}
+	return nil
+}
+
+// initContainerName names the init container at index i in the app's pods
+func initContainerName(i int) string {
+	return fmt.Sprintf("init-%d", i)
+}
+
+// initContainerValues sets the chart's initContainers. Each one runs with the app's environment,
+// secrets included, overridden by its own Env, and with the app image when it sets none.
+func initContainerValues(values map[string]interface{}, req *DeployRequest) {
+	containers := make([]interface{}, 0, len(req.Config.InitContainers))
+
+	for i, init := range req.Config.InitContainers {
+		image := init.Image
+		if image == "" {
+			image = req.ImageTag
+		}
+
+		env := make(map[string]string, len(req.Env)+len(init.Env))
+		for key, value := range req.Env {
+			env[key] = value
+		}
+		for key, value := range init.Env {
+			env[key] = value
+		}
+
+		container := map[string]interface{}{
+			"name":  initContainerName(i),
+			"image": image,
+		}
+
+		if len(init.Command) > 0 {
+			container["command"] = init.Command
+		}
+
+		if len(env) > 0 {
+			keys := make([]string, 0, len(env))
+			for key := range env {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			envVars := make([]interface{}, 0, len(keys))
+			for _, key := range keys {
+				envVars = append(envVars, map[string]interface{}{
+					"name":  key,
+					"value": env[key],
+				})
+			}
+			container["env"] = envVars
+		}
+
+		containers = append(containers, container)
+	}
+
+	values["initContainers"] = containers
+}
+
+// podsReadyTimeout is how long a deploy waits for the app's pods, lengthened by the timeout of
+// each init container since they run one after another before the app starts
+func podsReadyTimeout(config *DeployConfig) time.Duration {
+	timeout := 5 * time.Minute
+	if config == nil {
+		return timeout
+	}
+
+	for _, init := range config.InitContainers {
+		if init.TimeoutSeconds > 0 {
+			timeout += time.Duration(init.TimeoutSeconds) * time.Second
+		} else {
+			timeout += defaultInitContainerTimeout
+		}
+	}
+	return timeout
+}
+
+// initContainerLogger returns a callback recording the outcome and logs of each init container
+// run in the deployment logs, or nil when the app has no init containers
+func (h *HelmDeployer) initContainerLogger(ctx context.Context, kubeClient *KubeClient, namespace string, deploymentID uuid.UUID,
+	config *DeployConfig) func(string, corev1.ContainerStatus, *corev1.ContainerStateTerminated, bool) {
+	if config == nil || len(config.InitContainers) == 0 {
+		return nil
+	}
+
+	return func(pod string, container corev1.ContainerStatus, run *corev1.ContainerStateTerminated, previous bool) {
+		level, outcome := "INFO", "completed"
+		if run.ExitCode != 0 {
+			level, outcome = "ERROR", fmt.Sprintf("failed with exit code %d and will be restarted", run.ExitCode)
+		}
+
+		getLogs := kubeClient.GetPodLogs
+		if previous {
+			getLogs = kubeClient.GetPreviousPodLogs
+		}
+
+		message := fmt.Sprintf("Init container %s in pod %s %s", container.Name, pod, outcome)
+		logs, err := getLogs(ctx, namespace, pod, container.Name, initContainerLogTailLines)
+		if err != nil {
+			message += fmt.Sprintf(" (logs unavailable: %v)", err)
+		} else if logs = strings.TrimSpace(logs); logs != "" {
+			message += ":\n" + logs
+		}
+
+		h.tracker.RecordDeploymentLog(ctx, deploymentID, "DEPLOYING", level, "init-container", message)
+	}
+}
@@ -0,0 +1,367 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// virtualServiceResource is the Istio VirtualService CRD. v1beta1 is served by every Istio
+// release still in support.
+var virtualServiceResource = schema.GroupVersionResource{
+	Group:    "networking.istio.io",
+	Version:  "v1beta1",
+	Resource: "virtualservices",
+}
+
+// VirtualServiceConfig describes an Istio VirtualService routing in-mesh traffic to a host
+type VirtualServiceConfig struct {
+	Name      string
+	Namespace string
+	Host      string // Service host whose traffic is routed
+
+	// Rule matched before Routes, nil for none
+	HeaderRoute *VirtualServiceHeaderRoute
+
+	// Routes are matched in order; traffic no route matches goes to Host
+	Routes []VirtualServiceRoute
+
+	// Host every request is also mirrored to, empty for none. Mirrored responses are discarded.
+	MirrorHost string
+}
+
+// VirtualServiceRoute splits the requests under a URI prefix between destinations
+type VirtualServiceRoute struct {
+	Prefix       string // Rewritten to / unless it is / itself
+	Destinations []VirtualServiceDestination
+}
+
+// VirtualServiceHeaderRoute splits the requests carrying a header between destinations,
+// without rewriting their path
+type VirtualServiceHeaderRoute struct {
+	Header       string // Matched exactly against Value; empty matches every request
+	Value        string
+	Destinations []VirtualServiceDestination
+}
+
+// VirtualServiceDestination is a service host and the percentage of a route's traffic it gets
+type VirtualServiceDestination struct {
+	Host   string
+	Weight int
+}
+
+// ServiceHost returns the cluster-local host of a release's service, which the base chart
+// names after the release
+func ServiceHost(infra *state.Infrastructure) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", infra.HelmReleaseName, infra.KubeNamespace)
+}
+
+// serviceRoutesVirtualServiceName names the VirtualService holding a release's service routes
+func serviceRoutesVirtualServiceName(releaseName string) string {
+	return releaseName + "-routes"
+}
+
+// ServiceRoutesVirtualService builds the VirtualService for the service routes of the
+// deployment running on source. Routes sharing a prefix split its traffic between their
+// targets, and the source keeps whatever weight they leave over. targets holds the
+// infrastructure of each route's target deployment.
+func ServiceRoutesVirtualService(source *state.Infrastructure, routes []state.ServiceRoute, targets map[uuid.UUID]*state.Infrastructure) VirtualServiceConfig {
+	sourceHost := ServiceHost(source)
+
+	byPrefix := make(map[string][]VirtualServiceDestination)
+	for _, route := range routes {
+		target, ok := targets[route.TargetDeploymentID]
+		if !ok {
+			continue
+		}
+		byPrefix[route.Prefix] = append(byPrefix[route.Prefix], VirtualServiceDestination{
+			Host:   ServiceHost(target),
+			Weight: route.WeightPercent,
+		})
+	}
+
+	prefixes := make([]string, 0, len(byPrefix))
+	for prefix := range byPrefix {
+		prefixes = append(prefixes, prefix)
+	}
+	// Longest prefix first, so /api/v2 is matched before /api
+	sort.Slice(prefixes, func(i, j int) bool {
+		if len(prefixes[i]) != len(prefixes[j]) {
+			return len(prefixes[i]) > len(prefixes[j])
+		}
+		return prefixes[i] < prefixes[j]
+	})
+
+	config := VirtualServiceConfig{
+		Name:      serviceRoutesVirtualServiceName(source.HelmReleaseName),
+		Namespace: source.KubeNamespace,
+		Host:      sourceHost,
+	}
+	for _, prefix := range prefixes {
+		destinations := byPrefix[prefix]
+
+		remaining := 100
+		for _, d := range destinations {
+			remaining -= d.Weight
+		}
+		if remaining > 0 {
+			destinations = append(destinations, VirtualServiceDestination{Host: sourceHost, Weight: remaining})
+		}
+
+		config.Routes = append(config.Routes, VirtualServiceRoute{
+			Prefix:       prefix,
+			Destinations: destinations,
+		})
+	}
+
+	return config
+}
+
+// ABTestHeaderRoute builds the rule sending an A/B test's traffic to its variant. While the test
+// runs, RoutingWeight percent of the baseline's requests carrying the header go to the variant.
+// Once the variant won, every request does until the baseline is destroyed.
+func ABTestHeaderRoute(test *state.ABTest, baselineHost, variantHost string) *VirtualServiceHeaderRoute {
+	if test.Status == state.ABTestStatusPromoting {
+		return &VirtualServiceHeaderRoute{
+			Destinations: []VirtualServiceDestination{{Host: variantHost, Weight: 100}},
+		}
+	}
+
+	route := &VirtualServiceHeaderRoute{
+		Header:       test.HeaderName,
+		Value:        test.HeaderValue,
+		Destinations: []VirtualServiceDestination{{Host: variantHost, Weight: test.RoutingWeight}},
+	}
+	if test.RoutingWeight < 100 {
+		route.Destinations = append(route.Destinations, VirtualServiceDestination{Host: baselineHost, Weight: 100 - test.RoutingWeight})
+	}
+
+	return route
+}
+
+// virtualServiceDestinations renders weighted route destinations
+func virtualServiceDestinations(destinations []VirtualServiceDestination) []interface{} {
+	rendered := make([]interface{}, 0, len(destinations))
+	for _, d := range destinations {
+		rendered = append(rendered, map[string]interface{}{
+			"destination": map[string]interface{}{"host": d.Host},
+			"weight":      int64(d.Weight),
+		})
+	}
+	return rendered
+}
+
+// virtualServiceManifest renders the VirtualService. A route's prefix is rewritten to / so
+// targets receive paths relative to their own root.
+func virtualServiceManifest(config VirtualServiceConfig) *unstructured.Unstructured {
+	rules := make([]map[string]interface{}, 0, len(config.Routes)+2)
+	if hr := config.HeaderRoute; hr != nil {
+		rule := map[string]interface{}{
+			"route": virtualServiceDestinations(hr.Destinations),
+		}
+		if hr.Header != "" {
+			rule["match"] = []interface{}{
+				map[string]interface{}{
+					"headers": map[string]interface{}{
+						hr.Header: map[string]interface{}{"exact": hr.Value},
+					},
+				},
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	for _, route := range config.Routes {
+		rule := map[string]interface{}{
+			"match": []interface{}{
+				map[string]interface{}{
+					"uri": map[string]interface{}{"prefix": route.Prefix},
+				},
+			},
+			"route": virtualServiceDestinations(route.Destinations),
+		}
+		if route.Prefix != "/" {
+			rule["rewrite"] = map[string]interface{}{"uri": "/"}
+		}
+		rules = append(rules, rule)
+	}
+
+	// A VirtualService answers 404 to requests none of its rules match, so everything else
+	// keeps going to the host itself
+	rules = append(rules, map[string]interface{}{
+		"route": []interface{}{
+			map[string]interface{}{
+				"destination": map[string]interface{}{"host": config.Host},
+			},
+		},
+	})
+
+	http := make([]interface{}, len(rules))
+	for i, rule := range rules {
+		if config.MirrorHost != "" {
+			rule["mirror"] = map[string]interface{}{"host": config.MirrorHost}
+			rule["mirrorPercentage"] = map[string]interface{}{"value": 100.0}
+		}
+		http[i] = rule
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": virtualServiceResource.GroupVersion().String(),
+			"kind":       "VirtualService",
+			"metadata": map[string]interface{}{
+				"name":      config.Name,
+				"namespace": config.Namespace,
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/managed-by": "app-deployer",
+				},
+			},
+			"spec": map[string]interface{}{
+				"hosts": []interface{}{config.Host},
+				"http":  http,
+			},
+		},
+	}
+}
+
+// CreateVirtualService creates or updates an Istio VirtualService
+func CreateVirtualService(ctx context.Context, kubeClient *KubeClient, config VirtualServiceConfig) error {
+	client, err := dynamic.NewForConfig(kubeClient.config)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	virtualServices := client.Resource(virtualServiceResource).Namespace(config.Namespace)
+	manifest := virtualServiceManifest(config)
+
+	existing, err := virtualServices.Get(ctx, config.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := virtualServices.Create(ctx, manifest, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				// Clusters without Istio do not serve the API at all
+				return fmt.Errorf("istio is not installed on the cluster: %w", err)
+			}
+			return fmt.Errorf("failed to create virtual service: %w", err)
+		}
+		log.Info().Str("namespace", config.Namespace).Str("name", config.Name).Msg("Virtual service created")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get virtual service: %w", err)
+	}
+
+	manifest.SetResourceVersion(existing.GetResourceVersion())
+	if _, err := virtualServices.Update(ctx, manifest, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update virtual service: %w", err)
+	}
+
+	log.Info().Str("namespace", config.Namespace).Str("name", config.Name).Msg("Virtual service updated")
+	return nil
+}
+
+// DeleteVirtualService deletes an Istio VirtualService. A missing one is not an error.
+func DeleteVirtualService(ctx context.Context, kubeClient *KubeClient, namespace, name string) error {
+	client, err := dynamic.NewForConfig(kubeClient.config)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	err = client.Resource(virtualServiceResource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete virtual service: %w", err)
+	}
+
+	return nil
+}
+
+// SyncServiceRoutes applies a deployment's stored service routes, traffic mirror and A/B test
+// to its cluster, deleting its VirtualService once it has none of them
+func SyncServiceRoutes(ctx context.Context, repo *state.Repository, sourceDeploymentID uuid.UUID) error {
+	source, err := repo.GetInfrastructure(ctx, sourceDeploymentID)
+	if err != nil {
+		return err
+	}
+	if source.HelmReleaseName == "" || source.ClusterEndpoint == "" {
+		return fmt.Errorf("deployment has no Helm release")
+	}
+
+	routes, err := repo.ListServiceRoutes(ctx, sourceDeploymentID)
+	if err != nil {
+		return err
+	}
+
+	mirrorHost, err := mirrorHost(ctx, repo, source)
+	if err != nil {
+		return err
+	}
+
+	headerRoute, err := abTestHeaderRoute(ctx, repo, source)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := NewKubeClient(source)
+	if err != nil {
+		return err
+	}
+
+	if len(routes) == 0 && mirrorHost == "" && headerRoute == nil {
+		return DeleteVirtualService(ctx, kubeClient, source.KubeNamespace, serviceRoutesVirtualServiceName(source.HelmReleaseName))
+	}
+
+	targets := make(map[uuid.UUID]*state.Infrastructure, len(routes))
+	for _, route := range routes {
+		target, err := repo.GetInfrastructure(ctx, route.TargetDeploymentID)
+		if err != nil {
+			return err
+		}
+		targets[route.TargetDeploymentID] = target
+	}
+
+	config := ServiceRoutesVirtualService(source, routes, targets)
+	config.MirrorHost = mirrorHost
+	config.HeaderRoute = headerRoute
+	return CreateVirtualService(ctx, kubeClient, config)
+}
+
+// mirrorHost returns the service host of the deployment mirroring source's traffic, empty when
+// none does. A mirror that is not deployed on source's cluster yet is left out.
+func mirrorHost(ctx context.Context, repo *state.Repository, source *state.Infrastructure) (string, error) {
+	mirror, err := repo.GetMirroringDeployment(ctx, source.DeploymentID)
+	if err != nil || mirror == nil {
+		return "", err
+	}
+
+	infra, err := repo.GetInfrastructure(ctx, mirror.ID)
+	if err != nil || infra.HelmReleaseName == "" || infra.ClusterEndpoint != source.ClusterEndpoint {
+		return "", nil
+	}
+
+	return ServiceHost(infra), nil
+}
+
+// abTestHeaderRoute returns the rule of the A/B test routing source's traffic, nil when none
+// does. A variant that is not deployed on source's cluster yet is left out.
+func abTestHeaderRoute(ctx context.Context, repo *state.Repository, source *state.Infrastructure) (*VirtualServiceHeaderRoute, error) {
+	test, err := repo.GetRoutingABTest(ctx, source.DeploymentID)
+	if err != nil || test == nil {
+		return nil, err
+	}
+
+	variant, err := repo.GetInfrastructure(ctx, test.VariantDeploymentID)
+	if err != nil || variant.HelmReleaseName == "" || variant.ClusterEndpoint != source.ClusterEndpoint {
+		return nil, nil
+	}
+
+	return ABTestHeaderRoute(test, ServiceHost(source), ServiceHost(variant)), nil
+}
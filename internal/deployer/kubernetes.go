@@ -3,12 +3,18 @@ package deployer
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -66,10 +72,45 @@ func NewKubeClient(infra *state.Infrastructure) (*KubeClient, error) {
 	}, nil
 }
 
+// NewInClusterKubeClient creates a Kubernetes client for the cluster the process runs in,
+// authenticated as its pod's service account
+func NewInClusterKubeClient() (*KubeClient, error) {
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load in-cluster config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return &KubeClient{
+		clientset: clientset,
+		config:    restConfig,
+	}, nil
+}
+
+// writeKubeConfig writes a kubeconfig for the infrastructure's cluster to path, built from
+// the stored endpoint and CA certificate
+func writeKubeConfig(infra *state.Infrastructure, path string) error {
+	caCert, err := base64.StdEncoding.DecodeString(infra.ClusterCACert)
+	if err != nil {
+		return fmt.Errorf("failed to decode CA certificate: %w", err)
+	}
+
+	config := createKubeConfig(infra.ClusterEndpoint, caCert, infra.ClusterName)
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
+		return fmt.Errorf("failed to write kubeconfig file: %w", err)
+	}
+
+	return nil
+}
+
 // createKubeConfig creates a kubeconfig from cluster details
 func createKubeConfig(endpoint string, caCert []byte, clusterName string) *clientcmdapi.Config {
 	// Add https:// prefix if not present
-	if endpoint[:8] != "https://" && endpoint[:7] != "http://" {
+	if !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
 		endpoint = "https://" + endpoint
 	}
 
@@ -205,14 +246,85 @@ func (k *KubeClient) GetLoadBalancerIP(ctx context.Context, namespace, serviceNa
 	return "", fmt.Errorf("timeout waiting for LoadBalancer IP after %v", timeout)
 }
 
-// WaitForPodsReady waits for pods to be ready
+// GetBackendService returns the GCP backend service GKE created for a LoadBalancer Service, as
+// regions/<region>/backendServices/<name>. It waits up to timeout for GKE to record it.
+func (k *KubeClient) GetBackendService(ctx context.Context, namespace, serviceName, region string, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		svc, err := k.clientset.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get service: %w", err)
+		}
+
+		if name := svc.Annotations["service.kubernetes.io/backend-service"]; name != "" {
+			return fmt.Sprintf("regions/%s/backendServices/%s", region, name), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	return "", fmt.Errorf("timeout waiting for backend service of %s after %v", serviceName, timeout)
+}
+
+// GetLoadBalancerService returns the name and first port of the LoadBalancer Service matching
+// the selector. found is false when the selected objects include none.
+func (k *KubeClient) GetLoadBalancerService(ctx context.Context, namespace string, labelSelector string) (name string, port int32, found bool, err error) {
+	services, err := k.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return "", 0, false, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	for _, svc := range services.Items {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		if len(svc.Spec.Ports) > 0 {
+			port = svc.Spec.Ports[0].Port
+		}
+		return svc.Name, port, true, nil
+	}
+
+	return "", 0, false, nil
+}
+
+// ContainerWatch receives the container changes seen while waiting for pods to be ready. Each
+// callback is optional and called once per change.
+type ContainerWatch struct {
+	// OnReady is called as a container of a pod first becomes ready
+	OnReady func(pod string, container corev1.ContainerStatus)
+
+	// OnInitTerminated is called as each run of an init container ends. previous is set when
+	// the run had already been replaced by a restart, so its logs are the previous container's.
+	OnInitTerminated func(pod string, container corev1.ContainerStatus, run *corev1.ContainerStateTerminated, previous bool)
+}
+
+// WaitForPodsReady waits for pods, and every container in them, to be ready
 func (k *KubeClient) WaitForPodsReady(ctx context.Context, namespace string, labelSelector string, timeout time.Duration) error {
+	return k.WaitForPodContainersReady(ctx, namespace, labelSelector, timeout, nil)
+}
+
+// WaitForPodContainersReady waits for pods, and every container in them, to be ready, reporting
+// container changes to watch when it is set
+func (k *KubeClient) WaitForPodContainersReady(ctx context.Context, namespace string, labelSelector string, timeout time.Duration,
+	watch *ContainerWatch) error {
+	if watch == nil {
+		watch = &ContainerWatch{}
+	}
+
 	log.Info().
 		Str("namespace", namespace).
 		Str("labelSelector", labelSelector).
 		Msg("Waiting for pods to be ready")
 
 	deadline := time.Now().Add(timeout)
+	reported := make(map[string]bool)
 
 	for time.Now().Before(deadline) {
 		pods, err := k.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
@@ -231,6 +343,23 @@ func (k *KubeClient) WaitForPodsReady(ctx context.Context, namespace string, lab
 		// Check if all pods are ready
 		allReady := true
 		for _, pod := range pods.Items {
+			if watch.OnInitTerminated != nil {
+				for _, status := range pod.Status.InitContainerStatuses {
+					run, previous := status.State.Terminated, false
+					if run == nil {
+						run, previous = status.LastTerminationState.Terminated, true
+					}
+					if run == nil {
+						continue
+					}
+					key := fmt.Sprintf("%s/%s/%s", pod.Name, status.Name, run.ContainerID)
+					if !reported[key] {
+						reported[key] = true
+						watch.OnInitTerminated(pod.Name, status, run, previous)
+					}
+				}
+			}
+
 			podReady := false
 			for _, condition := range pod.Status.Conditions {
 				if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
@@ -239,13 +368,26 @@ func (k *KubeClient) WaitForPodsReady(ctx context.Context, namespace string, lab
 				}
 			}
 
-			if !podReady {
+			// Every container, sidecars included, must report ready as well as the pod
+			containersReady := len(pod.Status.ContainerStatuses) >= len(pod.Spec.Containers)
+			for _, status := range pod.Status.ContainerStatuses {
+				if !status.Ready {
+					containersReady = false
+					continue
+				}
+				key := pod.Name + "/" + status.Name
+				if watch.OnReady != nil && !reported[key] {
+					reported[key] = true
+					watch.OnReady(pod.Name, status)
+				}
+			}
+
+			if !podReady || !containersReady {
 				allReady = false
 				log.Debug().
 					Str("pod", pod.Name).
 					Str("phase", string(pod.Status.Phase)).
 					Msg("Pod not ready yet")
-				break
 			}
 		}
 
@@ -287,6 +429,146 @@ func (k *KubeClient) GetPodCount(ctx context.Context, namespace string, labelSel
 	return ready, total, nil
 }
 
+// ListPods returns the pods matching the selector, oldest first
+func (k *KubeClient) ListPods(ctx context.Context, namespace string, labelSelector string) ([]PodInfo, error) {
+	pods, err := k.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	infos := make([]PodInfo, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		info := PodInfo{
+			Name:       pod.Name,
+			Status:     string(pod.Status.Phase),
+			Node:       pod.Spec.NodeName,
+			CreatedAt:  pod.CreationTimestamp.Time,
+			Containers: make([]ContainerStatusInfo, 0, len(pod.Status.ContainerStatuses)),
+		}
+
+		for _, cs := range pod.Status.ContainerStatuses {
+			container := ContainerStatusInfo{
+				Name:     cs.Name,
+				Image:    cs.Image,
+				Ready:    cs.Ready,
+				Restarts: cs.RestartCount,
+			}
+
+			switch {
+			case cs.State.Waiting != nil:
+				container.State = "waiting"
+				container.Reason = cs.State.Waiting.Reason
+				// Like kubectl, surface why a running pod's container is not up
+				if container.Reason != "" {
+					info.Status = container.Reason
+				}
+			case cs.State.Running != nil:
+				container.State = "running"
+			case cs.State.Terminated != nil:
+				container.State = "terminated"
+				container.Reason = cs.State.Terminated.Reason
+			}
+
+			info.Restarts += cs.RestartCount
+			info.Containers = append(info.Containers, container)
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+	})
+
+	return infos, nil
+}
+
+// GetPodLogs returns the last tailLines lines logged by a pod's container. An empty container
+// selects the pod's only container.
+func (k *KubeClient) GetPodLogs(ctx context.Context, namespace, podName, container string, tailLines int64) (string, error) {
+	return k.getContainerLogs(ctx, namespace, podName, container, tailLines, false)
+}
+
+// GetPreviousPodLogs returns the last tailLines lines logged by the run of a pod's container
+// before it was last restarted
+func (k *KubeClient) GetPreviousPodLogs(ctx context.Context, namespace, podName, container string, tailLines int64) (string, error) {
+	return k.getContainerLogs(ctx, namespace, podName, container, tailLines, true)
+}
+
+// getContainerLogs returns the last tailLines lines logged by a pod's container, or by its run
+// before the last restart when previous is set
+func (k *KubeClient) getContainerLogs(ctx context.Context, namespace, podName, container string, tailLines int64, previous bool) (string, error) {
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+		Previous:  previous,
+	}
+
+	logs, err := k.clientset.CoreV1().Pods(namespace).GetLogs(podName, opts).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod logs: %w", err)
+	}
+
+	return string(logs), nil
+}
+
+// podMetricsList mirrors the metrics.k8s.io/v1beta1 PodMetricsList served by the Metrics Server
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Timestamp  time.Time `json:"timestamp"`
+		Containers []struct {
+			Usage map[corev1.ResourceName]resource.Quantity `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// GetPodMetrics returns the current CPU and memory usage of the pods matching the selector,
+// as reported by the Metrics Server
+func (k *KubeClient) GetPodMetrics(ctx context.Context, namespace string, labelSelector string) ([]PodMetrics, error) {
+	data, err := k.clientset.Discovery().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", namespace, "pods").
+		Param("labelSelector", labelSelector).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod metrics: %w", err)
+	}
+
+	var list podMetricsList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pod metrics: %w", err)
+	}
+
+	metrics := make([]PodMetrics, 0, len(list.Items))
+	for _, item := range list.Items {
+		pod := PodMetrics{
+			PodName:   item.Metadata.Name,
+			Timestamp: item.Timestamp,
+		}
+		var memoryBytes int64
+		for _, container := range item.Containers {
+			if cpu, ok := container.Usage[corev1.ResourceCPU]; ok {
+				pod.CPUUsageMilli += cpu.MilliValue()
+			}
+			if memory, ok := container.Usage[corev1.ResourceMemory]; ok {
+				memoryBytes += memory.Value()
+			}
+		}
+		pod.MemoryUsageMi = memoryBytes / (1024 * 1024)
+		metrics = append(metrics, pod)
+	}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].PodName < metrics[j].PodName
+	})
+
+	return metrics, nil
+}
+
 // GetClientset returns the underlying Kubernetes clientset
 func (k *KubeClient) GetClientset() *kubernetes.Clientset {
 	return k.clientset
@@ -296,3 +578,282 @@ func (k *KubeClient) GetClientset() *kubernetes.Clientset {
 func (k *KubeClient) GetRestConfig() *rest.Config {
 	return k.config
 }
+
+// GetCronJobLastSchedule returns the last schedule time of the first CronJob matching the selector.
+// found is false when no CronJob matches; last is nil when it has never been scheduled.
+func (k *KubeClient) GetCronJobLastSchedule(ctx context.Context, namespace string, labelSelector string) (last *time.Time, found bool, err error) {
+	cronJobs, err := k.clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+
+	if len(cronJobs.Items) == 0 {
+		return nil, false, nil
+	}
+
+	if scheduled := cronJobs.Items[0].Status.LastScheduleTime; scheduled != nil {
+		t := scheduled.Time
+		return &t, true, nil
+	}
+
+	return nil, true, nil
+}
+
+// ListPersistentVolumeClaims returns the PVCs matching the selector
+func (k *KubeClient) ListPersistentVolumeClaims(ctx context.Context, namespace string, labelSelector string) ([]VolumeInfo, error) {
+	pvcs, err := k.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+
+	volumes := make([]VolumeInfo, 0, len(pvcs.Items))
+	for _, pvc := range pvcs.Items {
+		volume := VolumeInfo{
+			Name:   pvc.Name,
+			Status: string(pvc.Status.Phase),
+		}
+
+		if pvc.Spec.StorageClassName != nil {
+			volume.StorageClass = *pvc.Spec.StorageClassName
+		}
+		if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			volume.Requested = requested.String()
+		}
+		if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			volume.Capacity = capacity.String()
+		}
+		for _, mode := range pvc.Spec.AccessModes {
+			volume.AccessModes = append(volume.AccessModes, string(mode))
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	return volumes, nil
+}
+
+// ListEvents returns the events reported by a component, newest first. An empty namespace
+// lists events across all namespaces.
+func (k *KubeClient) ListEvents(ctx context.Context, namespace, source string) ([]EventInfo, error) {
+	events, err := k.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("source=%s", source),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	infos := make([]EventInfo, 0, len(events.Items))
+	for _, event := range events.Items {
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = event.CreationTimestamp.Time
+		}
+
+		infos = append(infos, EventInfo{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Object:   fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+			Count:    event.Count,
+			LastSeen: lastSeen,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].LastSeen.After(infos[j].LastSeen)
+	})
+
+	return infos, nil
+}
+
+// DeletePersistentVolumeClaims deletes the PVCs matching the selector
+func (k *KubeClient) DeletePersistentVolumeClaims(ctx context.Context, namespace string, labelSelector string) error {
+	err := k.clientset.CoreV1().PersistentVolumeClaims(namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete persistent volume claims: %w", err)
+	}
+
+	log.Info().
+		Str("namespace", namespace).
+		Str("selector", labelSelector).
+		Msg("Persistent volume claims deleted")
+
+	return nil
+}
+
+// GetHorizontalPodAutoscaler returns the status of the HorizontalPodAutoscaler matching the selector.
+// found is false when there is none, i.e. the app is not autoscaled.
+func (k *KubeClient) GetHorizontalPodAutoscaler(ctx context.Context, namespace string, labelSelector string) (status *HPAStatus, found bool, err error) {
+	hpas, err := k.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list horizontal pod autoscalers: %w", err)
+	}
+
+	if len(hpas.Items) == 0 {
+		return nil, false, nil
+	}
+
+	hpa := hpas.Items[0]
+	status = &HPAStatus{
+		Name:            hpa.Name,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Conditions:      make([]HPACondition, 0, len(hpa.Status.Conditions)),
+	}
+	if hpa.Spec.MinReplicas != nil {
+		status.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	if hpa.Status.LastScaleTime != nil {
+		t := hpa.Status.LastScaleTime.Time
+		status.LastScaleTime = &t
+	}
+
+	for _, c := range hpa.Status.Conditions {
+		status.Conditions = append(status.Conditions, HPACondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+
+	return status, true, nil
+}
+
+// RolloutRestart restarts the pods of every Deployment and StatefulSet matching the selector,
+// the way kubectl rollout restart does: by stamping the pod template with the restart time
+// so the controller rolls the pods. It returns the names of the restarted workloads.
+func (k *KubeClient) RolloutRestart(ctx context.Context, namespace string, labelSelector string) ([]string, error) {
+	patch := []byte(fmt.Sprintf(
+		`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`,
+		time.Now().Format(time.RFC3339)))
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+
+	var restarted []string
+
+	deployments, err := k.clientset.AppsV1().Deployments(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		if _, err := k.clientset.AppsV1().Deployments(namespace).Patch(ctx, d.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return restarted, fmt.Errorf("failed to restart deployment %s: %w", d.Name, err)
+		}
+		restarted = append(restarted, "deployment/"+d.Name)
+	}
+
+	statefulSets, err := k.clientset.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+	if err != nil {
+		return restarted, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		if _, err := k.clientset.AppsV1().StatefulSets(namespace).Patch(ctx, s.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return restarted, fmt.Errorf("failed to restart statefulset %s: %w", s.Name, err)
+		}
+		restarted = append(restarted, "statefulset/"+s.Name)
+	}
+
+	log.Info().
+		Str("namespace", namespace).
+		Str("selector", labelSelector).
+		Strs("workloads", restarted).
+		Msg("Workloads restarted")
+
+	return restarted, nil
+}
+
+// RunJob creates a single-attempt Job from a hook spec and waits for it to finish.
+// It returns an error when the Job fails or does not complete within the hook timeout.
+func (k *KubeClient) RunJob(ctx context.Context, namespace, name string, labels map[string]string, env map[string]string, spec HookSpec) error {
+	timeout := time.Duration(spec.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+
+	envVars := make([]corev1.EnvVar, 0, len(env))
+	for key, value := range env {
+		envVars = append(envVars, corev1.EnvVar{Name: key, Value: value})
+	}
+
+	backoffLimit := int32(0)
+	activeDeadline := int64(timeout.Seconds())
+	ttl := int32(3600) // Keep finished Jobs around for an hour so their logs can be inspected
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &activeDeadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    "hook",
+						Image:   spec.Image,
+						Command: spec.Command,
+						Env:     envVars,
+					}},
+				},
+			},
+		},
+	}
+
+	log.Info().
+		Str("namespace", namespace).
+		Str("job", name).
+		Str("image", spec.Image).
+		Msg("Running hook job")
+
+	if _, err := k.clientset.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	// Allow a little past the active deadline for the controller to report the failure
+	deadline := time.Now().Add(timeout + 30*time.Second)
+
+	for time.Now().Before(deadline) {
+		current, err := k.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get job: %w", err)
+		}
+
+		for _, condition := range current.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return nil
+			case batchv1.JobFailed:
+				return fmt.Errorf("job %s failed: %s", name, condition.Message)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	return fmt.Errorf("timeout waiting for job %s after %v", name, timeout)
+}
@@ -0,0 +1,466 @@
+package deployer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// kustomizeImagePlaceholder is the image name user manifests reference; it is
+// replaced with the built image on every deploy
+const kustomizeImagePlaceholder = "app"
+
+// kustomizeOverlayDir is the overlay generated inside the cloned repository
+const kustomizeOverlayDir = ".app-deployer"
+
+// KustomizeDeployer implements Deployer by rendering the user's kustomize overlays
+// and applying them with kubectl
+type KustomizeDeployer struct {
+	tracker *Tracker
+	repo    *state.Repository
+}
+
+// NewKustomizeDeployer creates a new kustomize-based deployer
+func NewKustomizeDeployer(tracker *Tracker, repo *state.Repository) (*KustomizeDeployer, error) {
+	for _, bin := range []string{"kustomize", "kubectl", "git"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			return nil, fmt.Errorf("%s CLI not found: %w (please install %s)", bin, err, bin)
+		}
+	}
+
+	log.Info().Msg("Kustomize deployer initialized")
+
+	return &KustomizeDeployer{
+		tracker: tracker,
+		repo:    repo,
+	}, nil
+}
+
+// Deploy clones the repository, renders its kustomization and applies it to the cluster
+func (k *KustomizeDeployer) Deploy(ctx context.Context, req *DeployRequest) (*DeployResult, error) {
+	startTime := time.Now()
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("repoURL", req.RepoURL).
+		Str("imageTag", req.ImageTag).
+		Msg("Starting kustomize deployment")
+
+	if req.RepoURL == "" {
+		return nil, fmt.Errorf("repository URL is required for kustomize deployments")
+	}
+
+	// Start deployment tracking
+	if err := k.tracker.StartDeployment(ctx, req.InfrastructureID); err != nil {
+		return nil, fmt.Errorf("failed to start deployment tracking: %w", err)
+	}
+
+	infra, err := k.tracker.GetInfrastructure(ctx, req.InfrastructureID)
+	if err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	kubeClient, err := NewKubeClient(infra)
+	if err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	namespace := infra.Namespace
+	if namespace == "" {
+		namespace = fmt.Sprintf("deployer-%s", req.DeploymentID[:8])
+	}
+	releaseName := fmt.Sprintf("app-%s", req.DeploymentID[:8])
+
+	labels := map[string]string{
+		"app":           req.AppName,
+		"deployment-id": req.DeploymentID,
+		"managed-by":    "app-deployer",
+	}
+	if err := kubeClient.CreateNamespace(ctx, namespace, labels); err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to create namespace: %w", err)
+	}
+
+	manifest, err := k.render(ctx, req, namespace)
+	if err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("failed to render kustomization: %w", err)
+	}
+
+	if err := runHooks(ctx, kubeClient, k.tracker, req, namespace, HookPhasePreDeploy, req.Hooks.PreDeploy); err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, err
+	}
+
+	if err := k.apply(ctx, infra, namespace, manifest); err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("kubectl apply failed: %w", err)
+	}
+
+	if err := k.tracker.RecordKustomizeManifest(ctx, req.InfrastructureID, manifest); err != nil {
+		return nil, fmt.Errorf("failed to record manifest: %w", err)
+	}
+
+	labelSelector := fmt.Sprintf("deployment-id=%s", req.DeploymentID)
+	if err := kubeClient.WaitForPodsReady(ctx, namespace, labelSelector, 5*time.Minute); err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, fmt.Errorf("pods failed to become ready: %w", err)
+	}
+
+	result := &DeployResult{
+		ReleaseName: releaseName,
+		Namespace:   namespace,
+		Status:      "deployed",
+		Message:     "Application deployed with kustomize successfully",
+	}
+
+	// Expose the first LoadBalancer service, if the manifests define one
+	if serviceName, err := findLoadBalancerService(ctx, kubeClient, namespace, labelSelector); err != nil {
+		log.Warn().Err(err).Msg("Failed to look up LoadBalancer service")
+	} else if serviceName != "" {
+		externalIP, err := kubeClient.GetLoadBalancerIP(ctx, namespace, serviceName, 5*time.Minute)
+		if err != nil {
+			k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+			return nil, fmt.Errorf("failed to get external IP: %w", err)
+		}
+		result.ExternalIP = externalIP
+		result.ExternalURL = fmt.Sprintf("http://%s", externalIP)
+	}
+
+	if err := runHooks(ctx, kubeClient, k.tracker, req, namespace, HookPhasePostDeploy, req.Hooks.PostDeploy); err != nil {
+		k.tracker.FailDeployment(ctx, req.InfrastructureID, err)
+		return nil, err
+	}
+
+	result.Duration = time.Since(startTime)
+
+	if err := k.tracker.CompleteDeployment(ctx, req.InfrastructureID, result); err != nil {
+		return nil, fmt.Errorf("failed to complete deployment tracking: %w", err)
+	}
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("namespace", namespace).
+		Str("externalIP", result.ExternalIP).
+		Dur("duration", result.Duration).
+		Msg("Kustomize deployment completed successfully")
+
+	return result, nil
+}
+
+// Destroy deletes the applied manifest's resources and, unless it predates the deployment, the namespace
+func (k *KustomizeDeployer) Destroy(ctx context.Context, req *DestroyRequest) error {
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("namespace", req.Namespace).
+		Msg("Destroying kustomize deployment")
+
+	infra, err := k.tracker.GetInfrastructure(ctx, req.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	kubeClient, err := NewKubeClient(infra)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	if infra.KustomizeManifest != "" {
+		if output, err := k.kubectl(ctx, infra, infra.KustomizeManifest, "delete", "--ignore-not-found", "-n", req.Namespace, "-f", "-"); err != nil {
+			log.Warn().
+				Err(err).
+				Str("output", output).
+				Msg("kubectl delete failed (resources may already be deleted)")
+		}
+	}
+
+	// Imported clusters and adopted releases keep their namespace, which may hold workloads the
+	// deployer does not manage
+	if !infra.ImportedExternally && !infra.AdoptedExternally {
+		if err := kubeClient.DeleteNamespace(ctx, req.Namespace); err != nil {
+			log.Warn().Err(err).Msg("Failed to delete namespace (may already be deleted)")
+		}
+	}
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Msg("Kustomize deployment destroyed successfully")
+
+	return nil
+}
+
+// GetStatus reports pod readiness for a kustomize deployment
+func (k *KustomizeDeployer) GetStatus(ctx context.Context, namespace, releaseName string) (*DeploymentStatus, error) {
+	infra, err := k.repo.GetInfrastructureByRelease(ctx, namespace, releaseName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	kubeClient, err := NewKubeClient(infra)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	ready, total, err := kubeClient.GetPodCount(ctx, namespace, fmt.Sprintf("deployment-id=%s", infra.DeploymentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod status: %w", err)
+	}
+
+	status := "pending"
+	switch {
+	case total == 0:
+		status = "not_found"
+	case ready == total:
+		status = "deployed"
+	}
+
+	return &DeploymentStatus{
+		ReleaseName:   releaseName,
+		Namespace:     namespace,
+		Status:        status,
+		UpdatedAt:     infra.UpdatedAt,
+		ReadyReplicas: ready,
+		TotalReplicas: total,
+		ExternalIP:    infra.ExternalIP,
+	}, nil
+}
+
+// Rollback re-applies the previously applied manifest
+func (k *KustomizeDeployer) Rollback(ctx context.Context, req *RollbackRequest) error {
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("namespace", req.Namespace).
+		Msg("Rolling back kustomize deployment")
+
+	infra, err := k.tracker.GetInfrastructure(ctx, req.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	if infra.LastKustomizeManifest == "" {
+		return fmt.Errorf("no previous manifest to roll back to")
+	}
+
+	if err := k.apply(ctx, infra, req.Namespace, infra.LastKustomizeManifest); err != nil {
+		return fmt.Errorf("kubectl apply failed: %w", err)
+	}
+
+	// Swap manifests so a second rollback returns to the newer version
+	if err := k.tracker.RecordKustomizeManifest(ctx, req.InfrastructureID, infra.LastKustomizeManifest); err != nil {
+		return fmt.Errorf("failed to record manifest: %w", err)
+	}
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Msg("Rollback completed successfully")
+
+	return nil
+}
+
+// History returns the previous and current manifests as revisions 1 and 2
+func (k *KustomizeDeployer) History(ctx context.Context, infra *state.Infrastructure) ([]ReleaseRevision, error) {
+	var revisions []ReleaseRevision
+
+	if infra.LastKustomizeManifest != "" {
+		revisions = append(revisions, ReleaseRevision{
+			Revision:    len(revisions) + 1,
+			Status:      "superseded",
+			Chart:       "kustomize",
+			Description: "Previous manifest",
+		})
+	}
+
+	if infra.KustomizeManifest != "" {
+		revisions = append(revisions, ReleaseRevision{
+			Revision:    len(revisions) + 1,
+			Updated:     infra.UpdatedAt,
+			Status:      "deployed",
+			Chart:       "kustomize",
+			Description: "Current manifest",
+		})
+	}
+
+	return revisions, nil
+}
+
+// render clones the repository and builds its kustomization with the deploy image and labels applied
+func (k *KustomizeDeployer) render(ctx context.Context, req *DeployRequest, namespace string) (string, error) {
+	cloneDir, err := os.MkdirTemp("", "kustomize-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(cloneDir)
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", req.RepoURL, cloneDir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git clone failed: %w, output: %s", err, string(output))
+	}
+
+	baseDir, err := findKustomization(cloneDir, req.KustomizePath)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(filepath.Join(cloneDir, kustomizeOverlayDir), baseDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve kustomization path: %w", err)
+	}
+
+	// Wrap the user's kustomization in an overlay that pins namespace, image and tracking labels
+	imageName, imageTag := splitImage(req.ImageTag)
+	overlay := fmt.Sprintf(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: %s
+resources:
+  - %s
+labels:
+  - pairs:
+      deployment-id: %s
+      managed-by: app-deployer
+    includeTemplates: true
+images:
+  - name: %s
+    newName: %s
+    newTag: %q
+`, namespace, filepath.ToSlash(rel), req.DeploymentID, kustomizeImagePlaceholder, imageName, imageTag)
+
+	overlayDir := filepath.Join(cloneDir, kustomizeOverlayDir)
+	if err := os.MkdirAll(overlayDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create overlay dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(overlayDir, "kustomization.yaml"), []byte(overlay), 0600); err != nil {
+		return "", fmt.Errorf("failed to write overlay: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd = exec.CommandContext(ctx, "kustomize", "build", overlayDir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("kustomize build failed: %w, output: %s", err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// apply pipes a rendered manifest into kubectl apply
+func (k *KustomizeDeployer) apply(ctx context.Context, infra *state.Infrastructure, namespace, manifest string) error {
+	output, err := k.kubectl(ctx, infra, manifest, "apply", "-n", namespace, "-f", "-")
+	if err != nil {
+		return fmt.Errorf("%w, output: %s", err, output)
+	}
+
+	log.Debug().Str("output", output).Msg("kubectl apply output")
+	return nil
+}
+
+// kubectl runs kubectl against the infrastructure's cluster with manifest on stdin
+func (k *KustomizeDeployer) kubectl(ctx context.Context, infra *state.Infrastructure, manifest string, args ...string) (string, error) {
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return "", fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+	cmd.Stdin = strings.NewReader(manifest)
+
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// findKustomization returns the directory holding kustomization.yaml. An explicit path
+// is used as-is; otherwise the shallowest match in the repository wins.
+func findKustomization(root, path string) (string, error) {
+	if path != "" {
+		dir := filepath.Join(root, filepath.Clean("/"+path))
+		if !hasKustomization(dir) {
+			return "", fmt.Errorf("no kustomization.yaml found in %s", path)
+		}
+		return dir, nil
+	}
+
+	var found string
+	foundDepth := -1
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || (d.Name() != "kustomization.yaml" && d.Name() != "kustomization.yml") {
+			return nil
+		}
+
+		dir := filepath.Dir(p)
+		depth := strings.Count(strings.TrimPrefix(dir, root), string(filepath.Separator))
+		if foundDepth == -1 || depth < foundDepth {
+			found, foundDepth = dir, depth
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search repository: %w", err)
+	}
+
+	if found == "" {
+		return "", fmt.Errorf("no kustomization.yaml found in repository")
+	}
+
+	return found, nil
+}
+
+// hasKustomization reports whether dir contains a kustomization file
+func hasKustomization(dir string) bool {
+	for _, name := range []string{"kustomization.yaml", "kustomization.yml"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// splitImage splits an image reference into name and tag, defaulting the tag to latest
+func splitImage(image string) (string, string) {
+	// A colon after the last slash separates the tag; earlier colons belong to a registry port
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		return image[:colon], image[colon+1:]
+	}
+	return image, "latest"
+}
+
+// findLoadBalancerService returns the name of the first LoadBalancer service matching the selector
+func findLoadBalancerService(ctx context.Context, kubeClient *KubeClient, namespace, labelSelector string) (string, error) {
+	services, err := kubeClient.GetClientset().CoreV1().Services(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", err)
+	}
+
+	for _, svc := range services.Items {
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			return svc.Name, nil
+		}
+	}
+
+	return "", nil
+}
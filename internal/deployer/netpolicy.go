@@ -0,0 +1,144 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// defaultNetworkPolicyName is the name of the isolation policy applied to deployment namespaces
+const defaultNetworkPolicyName = "app-deployer-default"
+
+// ApplyDefaultNetworkPolicy isolates a deployment namespace. Pods may talk to each other,
+// receive traffic from the Istio ingress gateway, and reach cluster DNS and the API server;
+// all other ingress and egress, including to other namespaces, is denied.
+func ApplyDefaultNetworkPolicy(ctx context.Context, kubeClient *KubeClient, namespace, appName string) error {
+	policy, err := defaultNetworkPolicy(kubeClient, namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if manifest, err := json.MarshalIndent(policy, "", "  "); err == nil {
+		log.Debug().
+			Str("namespace", namespace).
+			RawJSON("manifest", manifest).
+			Msg("Applying default network policy")
+	}
+
+	policies := kubeClient.GetClientset().NetworkingV1().NetworkPolicies(namespace)
+
+	existing, err := policies.Get(ctx, defaultNetworkPolicyName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := policies.Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create network policy: %w", err)
+		}
+		log.Info().Str("namespace", namespace).Msg("Default network policy created")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get network policy: %w", err)
+	}
+
+	policy.ResourceVersion = existing.ResourceVersion
+	if _, err := policies.Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update network policy: %w", err)
+	}
+
+	log.Info().Str("namespace", namespace).Msg("Default network policy updated")
+	return nil
+}
+
+// defaultNetworkPolicy builds the isolation policy for a namespace
+func defaultNetworkPolicy(kubeClient *KubeClient, namespace, appName string) (*networkingv1.NetworkPolicy, error) {
+	apiServerIP, err := apiServerIP(kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	tcp := corev1.ProtocolTCP
+	udp := corev1.ProtocolUDP
+	dnsPort := intstr.FromInt32(53)
+	httpsPort := intstr.FromInt32(443)
+
+	samePods := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      defaultNetworkPolicyName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":        appName,
+				"managed-by": "app-deployer",
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{samePods}},
+				{
+					From: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"kubernetes.io/metadata.name": "istio-system"},
+						},
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"istio": "ingressgateway"},
+						},
+					}},
+				},
+			},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{To: []networkingv1.NetworkPolicyPeer{samePods}},
+				{
+					To: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"},
+						},
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+						},
+					}},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &udp, Port: &dnsPort},
+						{Protocol: &tcp, Port: &dnsPort},
+					},
+				},
+				{
+					To: []networkingv1.NetworkPolicyPeer{{
+						IPBlock: &networkingv1.IPBlock{CIDR: apiServerIP + "/32"},
+					}},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &tcp, Port: &httpsPort},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// apiServerIP returns the IP of the cluster endpoint the client talks to
+func apiServerIP(kubeClient *KubeClient) (string, error) {
+	endpoint, err := url.Parse(kubeClient.GetRestConfig().Host)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse cluster endpoint: %w", err)
+	}
+
+	ip := net.ParseIP(endpoint.Hostname())
+	if ip == nil || ip.To4() == nil {
+		return "", fmt.Errorf("cluster endpoint is not an IPv4 address: %s", endpoint.Hostname())
+	}
+
+	return ip.String(), nil
+}
@@ -0,0 +1,147 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Validate checks that a disruption budget sets at most one of its bounds
+func (p *PDBConfig) Validate() error {
+	if p.MinAvailable < 0 || p.MaxUnavailable < 0 {
+		return fmt.Errorf("pdb bounds must not be negative")
+	}
+	if p.MinAvailable > 0 && p.MaxUnavailable > 0 {
+		return fmt.Errorf("pdb may set min_available or max_unavailable, not both")
+	}
+	return nil
+}
+
+// minAvailable returns the pods the budget keeps running out of replicas. A budget without
+// bounds keeps one pod running.
+func (p *PDBConfig) minAvailable(replicas int) int {
+	switch {
+	case p.MinAvailable > 0:
+		return p.MinAvailable
+	case p.MaxUnavailable > 0:
+		return max(replicas-p.MaxUnavailable, 0)
+	default:
+		return 1
+	}
+}
+
+// pdbManifest builds the PodDisruptionBudget the base chart renders from extraManifests. The
+// chart passes each manifest through tpl, so names and selectors follow the release.
+func pdbManifest(pdb *PDBConfig) map[string]interface{} {
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				"app.kubernetes.io/name":     `{{ include "base-app.name" . }}`,
+				"app.kubernetes.io/instance": "{{ .Release.Name }}",
+			},
+		},
+	}
+	if pdb.MaxUnavailable > 0 {
+		spec["maxUnavailable"] = pdb.MaxUnavailable
+	} else {
+		spec["minAvailable"] = max(pdb.MinAvailable, 1)
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "policy/v1",
+		"kind":       "PodDisruptionBudget",
+		"metadata": map[string]interface{}{
+			"name": `{{ include "base-app.fullname" . }}`,
+			"labels": map[string]interface{}{
+				"app.kubernetes.io/instance":   "{{ .Release.Name }}",
+				"app.kubernetes.io/managed-by": "{{ .Release.Service }}",
+			},
+		},
+		"spec": spec,
+	}
+}
+
+// chartRendersExtraManifests reports whether a chart's templates render extraManifests.
+// Custom charts configured in place of the base chart may not.
+func chartRendersExtraManifests(chartPath string) bool {
+	templates, err := filepath.Glob(filepath.Join(chartPath, "templates", "*"))
+	if err != nil {
+		return false
+	}
+
+	for _, template := range templates {
+		data, err := os.ReadFile(template)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(string(data), ".Values.extraManifests") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApplyPDB creates or updates a PodDisruptionBudget keeping minAvailable of the release's
+// pods running through voluntary disruptions such as node upgrades
+func (k *KubeClient) ApplyPDB(ctx context.Context, namespace, name string, minAvailable int) error {
+	available := intstr.FromInt32(int32(minAvailable))
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/instance":   name,
+				"app.kubernetes.io/managed-by": "app-deployer",
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &available,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app.kubernetes.io/instance": name},
+			},
+		},
+	}
+
+	pdbs := k.clientset.PolicyV1().PodDisruptionBudgets(namespace)
+
+	existing, err := pdbs.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := pdbs.Create(ctx, pdb, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create pod disruption budget: %w", err)
+		}
+		log.Info().Str("namespace", namespace).Str("name", name).Msg("Pod disruption budget created")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get pod disruption budget: %w", err)
+	}
+
+	pdb.ResourceVersion = existing.ResourceVersion
+	if _, err := pdbs.Update(ctx, pdb, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update pod disruption budget: %w", err)
+	}
+
+	log.Info().Str("namespace", namespace).Str("name", name).Msg("Pod disruption budget updated")
+	return nil
+}
+
+// DeletePDBs deletes the PodDisruptionBudgets matching the selector
+func (k *KubeClient) DeletePDBs(ctx context.Context, namespace string, labelSelector string) error {
+	err := k.clientset.PolicyV1().PodDisruptionBudgets(namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete pod disruption budgets: %w", err)
+	}
+
+	return nil
+}
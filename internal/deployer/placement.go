@@ -0,0 +1,142 @@
+package deployer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Validate checks node label selectors and tolerations against Kubernetes naming rules
+func (a *NodeAffinity) Validate() error {
+	for _, labels := range []map[string]string{a.RequiredLabels, a.PreferredLabels} {
+		for key, value := range labels {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return fmt.Errorf("invalid node label %q: %s", key, strings.Join(errs, "; "))
+			}
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				return fmt.Errorf("invalid value of node label %q: %s", key, strings.Join(errs, "; "))
+			}
+		}
+	}
+
+	for _, toleration := range a.Tolerations {
+		switch toleration.Operator {
+		case "", "Equal":
+			if toleration.Key == "" {
+				return fmt.Errorf("toleration with operator Equal requires a key")
+			}
+		case "Exists":
+			if toleration.Value != "" {
+				return fmt.Errorf("toleration of %q with operator Exists must not set a value", toleration.Key)
+			}
+		default:
+			return fmt.Errorf("toleration operator must be Equal or Exists, got %q", toleration.Operator)
+		}
+
+		switch toleration.Effect {
+		case "", "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			return fmt.Errorf("toleration effect must be NoSchedule, PreferNoSchedule or NoExecute, got %q", toleration.Effect)
+		}
+	}
+
+	return nil
+}
+
+// WithDedicatedPool returns a copy of the affinity that also requires, and tolerates the taint
+// of, the node pool dedicated to workload under labelKey
+func (a *NodeAffinity) WithDedicatedPool(labelKey, workload string) *NodeAffinity {
+	placed := &NodeAffinity{
+		RequiredLabels: map[string]string{labelKey: workload},
+		Tolerations: []Toleration{{
+			Key:      labelKey,
+			Operator: "Equal",
+			Value:    workload,
+			Effect:   "NoSchedule",
+		}},
+	}
+	if a == nil {
+		return placed
+	}
+
+	for k, v := range a.RequiredLabels {
+		if _, ok := placed.RequiredLabels[k]; !ok {
+			placed.RequiredLabels[k] = v
+		}
+	}
+	placed.PreferredLabels = a.PreferredLabels
+	placed.Tolerations = append(placed.Tolerations, a.Tolerations...)
+
+	return placed
+}
+
+// placementValues sets the chart's nodeSelector, affinity and tolerations values. Required
+// labels become the pod's nodeSelector; each preferred label is an equally weighted
+// scheduling preference.
+func placementValues(values map[string]interface{}, affinity *NodeAffinity) {
+	if len(affinity.RequiredLabels) > 0 {
+		nodeSelector := make(map[string]interface{}, len(affinity.RequiredLabels))
+		for k, v := range affinity.RequiredLabels {
+			nodeSelector[k] = v
+		}
+		values["nodeSelector"] = nodeSelector
+	}
+
+	if len(affinity.PreferredLabels) > 0 {
+		// Sorted so the rendered values are the same on every deploy
+		keys := make([]string, 0, len(affinity.PreferredLabels))
+		for k := range affinity.PreferredLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		preferred := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			preferred = append(preferred, map[string]interface{}{
+				"weight": 1,
+				"preference": map[string]interface{}{
+					"matchExpressions": []interface{}{
+						map[string]interface{}{
+							"key":      k,
+							"operator": "In",
+							"values":   []interface{}{affinity.PreferredLabels[k]},
+						},
+					},
+				},
+			})
+		}
+
+		values["affinity"] = map[string]interface{}{
+			"nodeAffinity": map[string]interface{}{
+				"preferredDuringSchedulingIgnoredDuringExecution": preferred,
+			},
+		}
+	}
+
+	if len(affinity.Tolerations) > 0 {
+		tolerations := make([]interface{}, 0, len(affinity.Tolerations))
+		for _, t := range affinity.Tolerations {
+			operator := t.Operator
+			if operator == "" {
+				operator = "Equal"
+			}
+
+			toleration := map[string]interface{}{
+				"operator": operator,
+			}
+			if t.Key != "" {
+				toleration["key"] = t.Key
+			}
+			if t.Value != "" {
+				toleration["value"] = t.Value
+			}
+			if t.Effect != "" {
+				toleration["effect"] = t.Effect
+			}
+			tolerations = append(tolerations, toleration)
+		}
+		values["tolerations"] = tolerations
+	}
+}
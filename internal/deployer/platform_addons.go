@@ -0,0 +1,246 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// ErrUnknownAddon is returned for a platform addon the deployer does not manage
+var ErrUnknownAddon = errors.New("unknown platform addon")
+
+// chartIndexTimeout bounds how long a Helm repository index is downloaded for
+const chartIndexTimeout = 30 * time.Second
+
+// PlatformAddon is a Helm chart the platform relies on in every cluster, next to the apps
+type PlatformAddon struct {
+	Name         string // e.g. istio
+	RepoName     string // Local name the repository is added under
+	RepoURL      string
+	Chart        string
+	ReleaseName  string
+	Namespace    string
+	ChartVersion string // Version upgrades move to; the newest stable release when empty
+}
+
+// AddonVersion compares the chart version of a platform addon installed on a cluster with the
+// one it would be upgraded to
+type AddonVersion struct {
+	Addon            PlatformAddon
+	Installed        bool
+	CurrentVersion   string
+	AvailableVersion string
+	UpgradeAvailable bool
+}
+
+// defaultPlatformAddons lists the addons the mesh and certificate features assume are installed
+var defaultPlatformAddons = []PlatformAddon{
+	{
+		Name:        "istio-base",
+		RepoName:    "istio",
+		RepoURL:     "https://istio-release.storage.googleapis.com/charts",
+		Chart:       "base",
+		ReleaseName: "istio-base",
+		Namespace:   "istio-system",
+	},
+	{
+		Name:        "istio",
+		RepoName:    "istio",
+		RepoURL:     "https://istio-release.storage.googleapis.com/charts",
+		Chart:       "istiod",
+		ReleaseName: "istiod",
+		Namespace:   "istio-system",
+	},
+	{
+		Name:        "cert-manager",
+		RepoName:    "jetstack",
+		RepoURL:     "https://charts.jetstack.io",
+		Chart:       "cert-manager",
+		ReleaseName: "cert-manager",
+		Namespace:   "cert-manager",
+	},
+}
+
+// PlatformAddons returns the platform addons with the chart versions pinned by name, e.g.
+// {"istio": "1.24.2"}
+func PlatformAddons(pinned map[string]string) []PlatformAddon {
+	addons := make([]PlatformAddon, len(defaultPlatformAddons))
+	for i, addon := range defaultPlatformAddons {
+		addon.ChartVersion = pinned[addon.Name]
+		addons[i] = addon
+	}
+	return addons
+}
+
+// PlatformAddons returns a copy of the platform addons the deployer manages
+func (h *HelmDeployer) PlatformAddons() []PlatformAddon {
+	return append([]PlatformAddon(nil), h.platformAddons...)
+}
+
+// PlatformAddon returns the platform addon with the given name, or ErrUnknownAddon
+func (h *HelmDeployer) PlatformAddon(name string) (PlatformAddon, error) {
+	for _, addon := range h.platformAddons {
+		if addon.Name == name {
+			return addon, nil
+		}
+	}
+	return PlatformAddon{}, fmt.Errorf("%w: %s", ErrUnknownAddon, name)
+}
+
+// GetAddonVersion reads the chart version of addon installed on the infrastructure's cluster
+// and the version it would be upgraded to: the pinned one, or the newest stable release in the
+// addon's repository.
+func (h *HelmDeployer) GetAddonVersion(ctx context.Context, infra *state.Infrastructure, addon PlatformAddon) (*AddonVersion, error) {
+	result := &AddonVersion{Addon: addon}
+
+	release, found, err := h.InspectRelease(ctx, infra, addon.Namespace, addon.ReleaseName)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		result.Installed = true
+		result.CurrentVersion = strings.TrimPrefix(release.Chart, addon.Chart+"-")
+	}
+
+	result.AvailableVersion = addon.ChartVersion
+	if result.AvailableVersion == "" {
+		result.AvailableVersion, err = LatestChartVersion(ctx, addon)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.UpgradeAvailable = found && newerVersion(result.AvailableVersion, result.CurrentVersion)
+	return result, nil
+}
+
+// UpgradeAddon upgrades the addon's release on the infrastructure's cluster to chartVersion,
+// refreshing the addon's repository first. The release's values are kept.
+func (h *HelmDeployer) UpgradeAddon(ctx context.Context, infra *state.Infrastructure, addon PlatformAddon, chartVersion string) error {
+	log.Info().
+		Str("addon", addon.Name).
+		Str("release", addon.ReleaseName).
+		Str("version", chartVersion).
+		Msg("Upgrading platform addon")
+
+	// Setup kubeconfig
+	kubeconfigPath, cleanup, err := setupKubeconfig(infra)
+	if err != nil {
+		return fmt.Errorf("failed to setup kubeconfig: %w", err)
+	}
+	defer cleanup()
+
+	for _, args := range [][]string{
+		{"repo", "add", addon.RepoName, addon.RepoURL, "--force-update"},
+		{"repo", "update", addon.RepoName},
+	} {
+		cmd := exec.CommandContext(ctx, "helm", args...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("helm %s failed: %w, output: %s", strings.Join(args[:2], " "), err, string(output))
+		}
+	}
+
+	// Upgrades only; an addon that is not installed is left for the cluster's operators to set up
+	cmd := exec.CommandContext(ctx, "helm", "upgrade",
+		addon.ReleaseName,
+		addon.RepoName+"/"+addon.Chart,
+		"--version", chartVersion,
+		"-n", addon.Namespace,
+		"--reuse-values",
+		"--wait",
+		"--timeout", "10m",
+	)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+
+	output, err := cmd.CombinedOutput()
+	log.Debug().Str("output", string(output)).Msg("Helm output")
+
+	if err != nil {
+		return fmt.Errorf("helm upgrade failed: %w, output: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// chartIndex is the part of a Helm repository's index.yaml listing each chart's versions
+type chartIndex struct {
+	Entries map[string][]struct {
+		Version string `yaml:"version"`
+	} `yaml:"entries"`
+}
+
+// LatestChartVersion returns the newest stable version of the addon's chart in its Helm
+// repository index. Pre-releases are skipped.
+func LatestChartVersion(ctx context.Context, addon PlatformAddon) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, chartIndexTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(addon.RepoURL, "/")+"/index.yaml", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create index request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download chart index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download chart index: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read chart index: %w", err)
+	}
+
+	var index chartIndex
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return "", fmt.Errorf("failed to parse chart index: %w", err)
+	}
+
+	var latest *version.Version
+	latestStr := ""
+	for _, entry := range index.Entries[addon.Chart] {
+		v, err := version.ParseSemantic(entry.Version)
+		if err != nil || v.PreRelease() != "" {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+			latestStr = entry.Version
+		}
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("chart %s has no stable versions in %s", addon.Chart, addon.RepoURL)
+	}
+
+	return latestStr, nil
+}
+
+// newerVersion reports whether available is a later version than current. Versions that do
+// not parse are only compared for equality.
+func newerVersion(available, current string) bool {
+	a, err := version.ParseGeneric(available)
+	if err != nil {
+		return available != current
+	}
+	c, err := version.ParseGeneric(current)
+	if err != nil {
+		return available != current
+	}
+	return a.GreaterThan(c)
+}
@@ -0,0 +1,80 @@
+package deployer
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceLimitPolicy bounds the resources a single deployment may ask for. Empty or zero
+// fields leave that resource unbounded.
+type ResourceLimitPolicy struct {
+	MaxCPULimit    string // Kubernetes quantity, e.g. "2" or "1500m"
+	MaxMemoryLimit string // Kubernetes quantity, e.g. "4Gi"
+	MaxReplicas    int
+}
+
+// Validate checks that the policy's limits are valid Kubernetes quantities
+func (p ResourceLimitPolicy) Validate() error {
+	if p.MaxCPULimit != "" {
+		if _, err := resource.ParseQuantity(p.MaxCPULimit); err != nil {
+			return fmt.Errorf("invalid max CPU limit %q: %w", p.MaxCPULimit, err)
+		}
+	}
+
+	if p.MaxMemoryLimit != "" {
+		if _, err := resource.ParseQuantity(p.MaxMemoryLimit); err != nil {
+			return fmt.Errorf("invalid max memory limit %q: %w", p.MaxMemoryLimit, err)
+		}
+	}
+
+	if p.MaxReplicas < 0 {
+		return fmt.Errorf("max replicas must not be negative")
+	}
+
+	return nil
+}
+
+// Check returns an error when the CPU limit, memory limit or replica count exceeds the policy
+func (p ResourceLimitPolicy) Check(cpuLimit, memoryLimit string, replicas int) error {
+	if err := checkQuantity("CPU limit", cpuLimit, p.MaxCPULimit); err != nil {
+		return err
+	}
+
+	if err := checkQuantity("memory limit", memoryLimit, p.MaxMemoryLimit); err != nil {
+		return err
+	}
+
+	if p.MaxReplicas > 0 && replicas > p.MaxReplicas {
+		return fmt.Errorf("%d replicas exceeds the maximum of %d", replicas, p.MaxReplicas)
+	}
+
+	return nil
+}
+
+// checkQuantity compares a requested quantity with its maximum; either may be empty
+func checkQuantity(name, requested, maximum string) error {
+	if requested == "" {
+		return nil
+	}
+
+	value, err := resource.ParseQuantity(requested)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, requested, err)
+	}
+
+	if maximum == "" {
+		return nil
+	}
+
+	limit, err := resource.ParseQuantity(maximum)
+	if err != nil {
+		return fmt.Errorf("invalid maximum %s %q: %w", name, maximum, err)
+	}
+
+	if value.Cmp(limit) > 0 {
+		return fmt.Errorf("%s %s exceeds the maximum of %s", name, requested, maximum)
+	}
+
+	return nil
+}
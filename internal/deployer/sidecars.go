@@ -0,0 +1,200 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Validate checks a sidecar's name, environment, mounts and resources against Kubernetes
+// naming and quantity rules
+func (s *SidecarConfig) Validate() error {
+	if errs := validation.IsDNS1123Label(s.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid sidecar name %q: %s", s.Name, strings.Join(errs, "; "))
+	}
+
+	if strings.TrimSpace(s.Image) == "" {
+		return fmt.Errorf("sidecar %s requires an image", s.Name)
+	}
+
+	for key := range s.Env {
+		if errs := validation.IsEnvVarName(key); len(errs) > 0 {
+			return fmt.Errorf("sidecar %s: invalid environment variable %q: %s", s.Name, key, strings.Join(errs, "; "))
+		}
+	}
+
+	for _, mount := range s.VolumeMounts {
+		if errs := validation.IsDNS1123Label(mount.Name); len(errs) > 0 {
+			return fmt.Errorf("sidecar %s: invalid volume name %q: %s", s.Name, mount.Name, strings.Join(errs, "; "))
+		}
+		if !path.IsAbs(mount.MountPath) || path.Clean(mount.MountPath) == "/" {
+			return fmt.Errorf("sidecar %s: mount path of %s must be an absolute path other than /", s.Name, mount.Name)
+		}
+	}
+
+	if r := s.Resources; r != nil {
+		for field, quantity := range map[string]string{
+			"cpu_request":    r.CPURequest,
+			"cpu_limit":      r.CPULimit,
+			"memory_request": r.MemoryRequest,
+			"memory_limit":   r.MemoryLimit,
+		} {
+			if quantity == "" {
+				continue
+			}
+			if _, err := resource.ParseQuantity(quantity); err != nil {
+				return fmt.Errorf("sidecar %s: invalid %s %q", s.Name, field, quantity)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateSidecars checks each sidecar and that no two share a name
+func ValidateSidecars(sidecars []SidecarConfig) error {
+	seen := make(map[string]bool, len(sidecars))
+	for i := range sidecars {
+		if err := sidecars[i].Validate(); err != nil {
+			return err
+		}
+		if seen[sidecars[i].Name] {
+			return fmt.Errorf("sidecar %s is defined more than once", sidecars[i].Name)
+		}
+		seen[sidecars[i].Name] = true
+	}
+	return nil
+}
+
+// sidecarStartupLogger returns a callback recording a deployment log entry as each sidecar
+// container becomes ready, or nil when the app has no sidecars
+func (h *HelmDeployer) sidecarStartupLogger(ctx context.Context, deploymentID uuid.UUID, config *DeployConfig) func(string, corev1.ContainerStatus) {
+	if config == nil || len(config.Sidecars) == 0 {
+		return nil
+	}
+
+	images := make(map[string]string, len(config.Sidecars))
+	for _, sidecar := range config.Sidecars {
+		images[sidecar.Name] = sidecar.Image
+	}
+
+	return func(pod string, container corev1.ContainerStatus) {
+		image, ok := images[container.Name]
+		if !ok {
+			return
+		}
+		h.tracker.RecordDeploymentLog(ctx, deploymentID, "DEPLOYING", "INFO", "sidecar",
+			fmt.Sprintf("Sidecar %s (%s) started in pod %s", container.Name, image, pod))
+	}
+}
+
+// sidecarValues sets the chart's extraContainers to the sidecars, and adds an emptyDir to its
+// volumes for each mount that does not name one of the deployment's ConfigMaps
+func sidecarValues(values map[string]interface{}, sidecars []SidecarConfig, configMaps []ConfigMapMount) {
+	isConfigMap := make(map[string]bool, len(configMaps))
+	for _, cm := range configMaps {
+		isConfigMap[cm.Name] = true
+	}
+
+	containers := make([]interface{}, 0, len(sidecars))
+	shared := make(map[string]bool)
+
+	for _, sidecar := range sidecars {
+		container := map[string]interface{}{
+			"name":  sidecar.Name,
+			"image": sidecar.Image,
+		}
+
+		if len(sidecar.Command) > 0 {
+			container["command"] = sidecar.Command
+		}
+
+		if len(sidecar.Env) > 0 {
+			keys := make([]string, 0, len(sidecar.Env))
+			for key := range sidecar.Env {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			env := make([]interface{}, 0, len(keys))
+			for _, key := range keys {
+				env = append(env, map[string]interface{}{
+					"name":  key,
+					"value": sidecar.Env[key],
+				})
+			}
+			container["env"] = env
+		}
+
+		if len(sidecar.VolumeMounts) > 0 {
+			mounts := make([]interface{}, 0, len(sidecar.VolumeMounts))
+			for _, mount := range sidecar.VolumeMounts {
+				// The chart names ConfigMap volumes configmap-<name>
+				volume := mount.Name
+				readOnly := mount.ReadOnly
+				if isConfigMap[mount.Name] {
+					volume = "configmap-" + mount.Name
+					readOnly = true
+				} else {
+					shared[mount.Name] = true
+				}
+
+				mounts = append(mounts, map[string]interface{}{
+					"name":      volume,
+					"mountPath": mount.MountPath,
+					"readOnly":  readOnly,
+				})
+			}
+			container["volumeMounts"] = mounts
+		}
+
+		if r := sidecar.Resources; r != nil {
+			container["resources"] = map[string]interface{}{
+				"requests": quantities(r.CPURequest, r.MemoryRequest),
+				"limits":   quantities(r.CPULimit, r.MemoryLimit),
+			}
+		}
+
+		containers = append(containers, container)
+	}
+
+	values["extraContainers"] = containers
+
+	if len(shared) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(shared))
+	for name := range shared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	volumes, _ := values["volumes"].([]interface{})
+	for _, name := range names {
+		volumes = append(volumes, map[string]interface{}{
+			"name":     name,
+			"emptyDir": map[string]interface{}{},
+		})
+	}
+	values["volumes"] = volumes
+}
+
+// quantities returns the set cpu and memory quantities of a requests or limits block
+func quantities(cpu, memory string) map[string]interface{} {
+	q := make(map[string]interface{}, 2)
+	if cpu != "" {
+		q["cpu"] = cpu
+	}
+	if memory != "" {
+		q["memory"] = memory
+	}
+	return q
+}
@@ -0,0 +1,126 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSmokeTestTimeout bounds a smoke test request that sets no timeout
+	defaultSmokeTestTimeout = 10 * time.Second
+
+	// maxSmokeTestBody is how much of a response body is searched for the expected content
+	maxSmokeTestBody = 1 << 20
+)
+
+// SmokeTestResult aggregates the outcome of a deployment's smoke tests
+type SmokeTestResult struct {
+	Passed bool              `json:"passed"`
+	Tests  []SmokeTestReport `json:"tests"`
+	RanAt  time.Time         `json:"ran_at"`
+}
+
+// SmokeTestReport is the outcome of a single smoke test
+type SmokeTestReport struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Passed     bool   `json:"passed"`
+	StatusCode int    `json:"status_code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RunSmokeTests issues each test's request against externalURL and reports which passed.
+// The result only passes when every test does.
+func RunSmokeTests(ctx context.Context, externalURL string, tests []SmokeTest) SmokeTestResult {
+	result := SmokeTestResult{
+		Passed: true,
+		Tests:  make([]SmokeTestReport, 0, len(tests)),
+		RanAt:  time.Now(),
+	}
+
+	client := &http.Client{}
+	baseURL := strings.TrimRight(externalURL, "/")
+
+	for _, test := range tests {
+		report := runSmokeTest(ctx, client, baseURL, test)
+		if !report.Passed {
+			result.Passed = false
+		}
+		result.Tests = append(result.Tests, report)
+	}
+
+	return result
+}
+
+// runSmokeTest runs a single smoke test and checks its status code and body
+func runSmokeTest(ctx context.Context, client *http.Client, baseURL string, test SmokeTest) SmokeTestReport {
+	method := test.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	expectedStatus := test.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+
+	timeout := defaultSmokeTestTimeout
+	if test.TimeoutSeconds > 0 {
+		timeout = time.Duration(test.TimeoutSeconds) * time.Second
+	}
+
+	report := SmokeTestReport{
+		Method: method,
+		Path:   test.Path,
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(reqCtx, method, baseURL+test.Path, nil)
+	if err != nil {
+		report.Error = fmt.Sprintf("failed to create request: %v", err)
+		report.DurationMs = time.Since(start).Milliseconds()
+		return report
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		report.Error = fmt.Sprintf("request failed: %v", err)
+		report.DurationMs = time.Since(start).Milliseconds()
+		return report
+	}
+	defer resp.Body.Close()
+
+	report.StatusCode = resp.StatusCode
+	if resp.StatusCode != expectedStatus {
+		report.Error = fmt.Sprintf("expected status %d, got %d", expectedStatus, resp.StatusCode)
+		report.DurationMs = time.Since(start).Milliseconds()
+		return report
+	}
+
+	if test.ExpectedBodyContains != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxSmokeTestBody))
+		if err != nil {
+			report.Error = fmt.Sprintf("failed to read response body: %v", err)
+			report.DurationMs = time.Since(start).Milliseconds()
+			return report
+		}
+		if !strings.Contains(string(body), test.ExpectedBodyContains) {
+			report.Error = fmt.Sprintf("response body does not contain %q", test.ExpectedBodyContains)
+			report.DurationMs = time.Since(start).Milliseconds()
+			return report
+		}
+	}
+
+	report.Passed = true
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report
+}
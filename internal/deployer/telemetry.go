@@ -0,0 +1,142 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// The Prometheus addon shipped with Istio, which scrapes the mesh's standard metrics
+const (
+	istioPrometheusNamespace = "istio-system"
+	istioPrometheusService   = "prometheus"
+	istioPrometheusPort      = "9090"
+)
+
+// MirrorStats counts the requests a mirrored deployment served and those shadowed to its mirror
+type MirrorStats struct {
+	Window           time.Duration
+	SourceRequests   float64
+	MirroredRequests float64
+	MirrorErrors     float64 // Mirrored requests the mirror answered with a 5xx
+}
+
+// prometheusResponse mirrors the body of a Prometheus instant vector query
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryPrometheus runs an instant PromQL query against the cluster's Istio Prometheus through
+// the API server's service proxy. Each series' value is keyed by its label, or by "" when label
+// is empty.
+func (k *KubeClient) QueryPrometheus(ctx context.Context, query, label string) (map[string]float64, error) {
+	data, err := k.clientset.CoreV1().Services(istioPrometheusNamespace).
+		ProxyGet("http", istioPrometheusService, istioPrometheusPort, "api/v1/query", map[string]string{"query": query}).
+		DoRaw(ctx)
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("istio prometheus is not installed on the cluster: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+
+	var resp prometheusResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse prometheus response: %w", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", resp.Error)
+	}
+
+	values := make(map[string]float64, len(resp.Data.Result))
+	for _, series := range resp.Data.Result {
+		raw, ok := series.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		values[series.Metric[label]] += value
+	}
+
+	return values, nil
+}
+
+// GetMirrorStats counts, over the window, the requests sourceHost served and those mirrorHost
+// received. Requests sent to the mirror's own service directly count as mirrored too.
+func (k *KubeClient) GetMirrorStats(ctx context.Context, sourceHost, mirrorHost string, window time.Duration) (*MirrorStats, error) {
+	stats := &MirrorStats{Window: window}
+
+	for _, q := range []struct {
+		filter string
+		into   *float64
+	}{
+		{fmt.Sprintf(`destination_service=%q`, sourceHost), &stats.SourceRequests},
+		{fmt.Sprintf(`destination_service=%q`, mirrorHost), &stats.MirroredRequests},
+		{fmt.Sprintf(`destination_service=%q,response_code=~"5.."`, mirrorHost), &stats.MirrorErrors},
+	} {
+		query := fmt.Sprintf(`sum(increase(istio_requests_total{reporter="destination",%s}[%s]))`,
+			q.filter, promDuration(window))
+		values, err := k.QueryPrometheus(ctx, query, "")
+		if err != nil {
+			return nil, err
+		}
+		*q.into = values[""]
+	}
+
+	return stats, nil
+}
+
+// ABTestMetrics counts the requests each side of an A/B test served
+type ABTestMetrics struct {
+	Window           time.Duration
+	BaselineRequests float64
+	BaselineErrors   float64 // Requests answered with a 5xx
+	VariantRequests  float64
+	VariantErrors    float64
+}
+
+// GetABTestMetrics counts, over the window, the requests baselineHost and variantHost served and
+// those they answered with a 5xx
+func (k *KubeClient) GetABTestMetrics(ctx context.Context, baselineHost, variantHost string, window time.Duration) (*ABTestMetrics, error) {
+	metrics := &ABTestMetrics{Window: window}
+	hosts := fmt.Sprintf(`destination_service=~%q`, regexp.QuoteMeta(baselineHost)+"|"+regexp.QuoteMeta(variantHost))
+
+	for _, q := range []struct {
+		filter            string
+		baseline, variant *float64
+	}{
+		{hosts, &metrics.BaselineRequests, &metrics.VariantRequests},
+		{hosts + `,response_code=~"5.."`, &metrics.BaselineErrors, &metrics.VariantErrors},
+	} {
+		query := fmt.Sprintf(`sum by (destination_service) (increase(istio_requests_total{reporter="destination",%s}[%s]))`,
+			q.filter, promDuration(window))
+		values, err := k.QueryPrometheus(ctx, query, "destination_service")
+		if err != nil {
+			return nil, err
+		}
+		*q.baseline = values[baselineHost]
+		*q.variant = values[variantHost]
+	}
+
+	return metrics, nil
+}
+
+// promDuration formats a duration as a PromQL range in whole seconds
+func promDuration(d time.Duration) string {
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
@@ -2,6 +2,7 @@ package deployer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -135,6 +136,110 @@ func (t *Tracker) FailDeployment(ctx context.Context, infraID string, deployErr
 	return nil
 }
 
+// RecordKustomizeManifest stores a newly applied manifest, keeping the previous one for rollback
+func (t *Tracker) RecordKustomizeManifest(ctx context.Context, infraID, manifest string) error {
+	infra, err := t.GetInfrastructure(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	infra.LastKustomizeManifest = infra.KustomizeManifest
+	infra.KustomizeManifest = manifest
+
+	if err := t.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		return fmt.Errorf("failed to update infrastructure: %w", err)
+	}
+
+	return nil
+}
+
+// RecordHelmValues stores the values a release was last deployed with, so GitOps
+// reconciliation can compare them against the live release
+func (t *Tracker) RecordHelmValues(ctx context.Context, infraID string, values map[string]interface{}) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode Helm values: %w", err)
+	}
+
+	infra, err := t.GetInfrastructure(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	infra.HelmValues = string(data)
+
+	if err := t.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		return fmt.Errorf("failed to update infrastructure: %w", err)
+	}
+
+	return nil
+}
+
+// GetResourcePolicy returns the admin-set resource policy, found is false when none is set
+func (t *Tracker) GetResourcePolicy(ctx context.Context) (policy ResourceLimitPolicy, found bool, err error) {
+	stored, err := t.repo.GetResourcePolicy(ctx)
+	if err != nil || stored == nil {
+		return ResourceLimitPolicy{}, false, err
+	}
+
+	return ResourceLimitPolicy{
+		MaxCPULimit:    stored.MaxCPULimit,
+		MaxMemoryLimit: stored.MaxMemoryLimit,
+		MaxReplicas:    stored.MaxReplicas,
+	}, true, nil
+}
+
+// GetPublishedChartVersion returns the latest version of a chart published to the registry,
+// found is false when none has been published
+func (t *Tracker) GetPublishedChartVersion(ctx context.Context, registryURL, name string) (version string, found bool, err error) {
+	chart, err := t.repo.GetLatestHelmChart(ctx, registryURL, name)
+	if err != nil || chart == nil {
+		return "", false, err
+	}
+
+	return chart.Version, true, nil
+}
+
+// RecordPVCNames stores the persistent volume claims created for a deployment
+func (t *Tracker) RecordPVCNames(ctx context.Context, infraID string, names []string) error {
+	infra, err := t.GetInfrastructure(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	infra.PVCNames = names
+
+	if err := t.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		return fmt.Errorf("failed to update infrastructure: %w", err)
+	}
+
+	return nil
+}
+
+// RecordDeploymentLog stores a user-visible log entry for a deployment.
+// Failures are logged rather than returned since log entries are informational.
+func (t *Tracker) RecordDeploymentLog(ctx context.Context, deploymentID uuid.UUID, phase, level, source, message string) {
+	entry := &state.DeploymentLog{
+		DeploymentID: deploymentID,
+		Phase:        phase,
+		Level:        level,
+		Source:       source,
+		Message:      message,
+	}
+
+	if err := t.repo.CreateDeploymentLog(ctx, entry); err != nil {
+		log.Warn().
+			Err(err).
+			Str("deploymentID", deploymentID.String()).
+			Msg("Failed to record deployment log")
+	}
+}
+
+// GetInfrastructureByRelease retrieves the infrastructure hosting a release
+func (t *Tracker) GetInfrastructureByRelease(ctx context.Context, namespace, releaseName string) (*state.Infrastructure, error) {
+	return t.repo.GetInfrastructureByRelease(ctx, namespace, releaseName)
+}
+
 // GetInfrastructure retrieves infrastructure by ID
 func (t *Tracker) GetInfrastructure(ctx context.Context, infraID string) (*state.Infrastructure, error) {
 	id, err := uuid.Parse(infraID)
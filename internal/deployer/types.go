@@ -2,7 +2,25 @@ package deployer
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// Deployer types selectable per deployment
+const (
+	DeployerTypeHelm      = "helm"
+	DeployerTypeKustomize = "kustomize"
+)
+
+// Workload kinds a deployment can run as
+const (
+	DeploymentTypeService     = "service"
+	DeploymentTypeStatefulSet = "statefulset"
+	DeploymentTypeCronJob     = "cronjob"
+	DeploymentTypeJob         = "job"
 )
 
 // Deployer defines the interface for Kubernetes deployment
@@ -18,6 +36,9 @@ type Deployer interface {
 
 	// Rollback rolls back to a previous version
 	Rollback(ctx context.Context, req *RollbackRequest) error
+
+	// History lists the release revisions available for rollback
+	History(ctx context.Context, infra *state.Infrastructure) ([]ReleaseRevision, error)
 }
 
 // DeployRequest contains information needed to deploy an application
@@ -43,10 +64,47 @@ type DeployRequest struct {
 	// Environment variables
 	Env map[string]string
 
+	// ConfigMaps mounted into the app's pods as files
+	ConfigMaps []ConfigMapMount
+
+	// Deployer selection: helm (default) or kustomize
+	DeployerType string
+
+	// Workload kind: service (default), statefulset, cronjob or job
+	DeploymentType string
+
+	// Kustomize source, used when DeployerType is kustomize
+	RepoURL       string
+	KustomizePath string
+
+	// One-off containers run before the release upgrade and after pods are ready
+	Hooks HooksConfig
+
 	// Optional configuration
 	Config *DeployConfig
 }
 
+// ConfigMapMount is a set of files mounted into the app's pods from a ConfigMap
+type ConfigMapMount struct {
+	Name      string
+	MountPath string            // Default: /etc/config/<name>
+	Data      map[string]string // File name to content
+}
+
+// HooksConfig lists the hooks run around a deploy
+type HooksConfig struct {
+	PreDeploy  []HookSpec `json:"pre_deploy,omitempty"`
+	PostDeploy []HookSpec `json:"post_deploy,omitempty"`
+}
+
+// HookSpec describes a hook container, run to completion as a Kubernetes Job
+type HookSpec struct {
+	Image          string            `json:"image"`
+	Command        []string          `json:"command,omitempty"` // Image entrypoint when empty
+	Env            map[string]string `json:"env,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"` // Default: 300
+}
+
 // DeployConfig holds optional deployment configuration
 type DeployConfig struct {
 	// Service configuration
@@ -72,6 +130,154 @@ type DeployConfig struct {
 	// Labels and annotations
 	Labels      map[string]string
 	Annotations map[string]string
+
+	// Schedule settings, required when DeploymentType is cronjob
+	CronJob *CronJobConfig
+
+	// Persistent storage for statefulsets; a volume per replica is created when StorageSize is set
+	StorageClass     string // Empty uses the cluster default
+	StorageSize      string // e.g. 10Gi
+	StorageMountPath string // Default: /data
+
+	// Workload identity binding for the app's Kubernetes ServiceAccount
+	WorkloadIdentity *WorkloadIdentityConfig
+
+	// HTTP checks run against the external URL once the app is exposed
+	SmokeTests []SmokeTest
+
+	// Cloud Armor protection of the app's LoadBalancer
+	WAF *WAFConfig
+
+	// Per client IP request limit added to the Cloud Armor policy, nil leaves clients unthrottled
+	RateLimit *RateLimitConfig
+
+	// Deployment whose in-mesh traffic is mirrored to this one once it is deployed
+	Mirror *MirrorConfig
+
+	// A/B test routing the requests to a baseline deployment carrying a header to this one
+	ABTest *ABTestConfig
+
+	// Vertical Pod Autoscaler recommending resources for the workload without applying them
+	EnableVPA bool
+
+	// PodDisruptionBudget bounding how many of the app's pods node drains and upgrades evict at once
+	EnablePDB bool
+	PDB       *PDBConfig
+
+	// Nodes the app's pods are placed on, nil places them anywhere
+	NodeAffinity *NodeAffinity
+
+	// Containers run alongside the app container in each pod, e.g. a log shipper or a proxy
+	Sidecars []SidecarConfig
+
+	// Containers run to completion, one after another, before the app container starts in each
+	// pod, e.g. database migrations
+	InitContainers []InitContainerConfig
+}
+
+// SidecarConfig describes a container run next to the app container for the life of its pods
+type SidecarConfig struct {
+	Name         string               `json:"name"`
+	Image        string               `json:"image"`
+	Command      []string             `json:"command,omitempty"` // Image entrypoint when empty
+	Env          map[string]string    `json:"env,omitempty"`
+	VolumeMounts []SidecarVolumeMount `json:"volume_mounts,omitempty"`
+	Resources    *SidecarResources    `json:"resources,omitempty"` // Cluster defaults when nil
+}
+
+// SidecarVolumeMount mounts one of the deployment's ConfigMaps, by name, into a sidecar. Any
+// other name is an emptyDir volume shared by the sidecars that mount it.
+type SidecarVolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+	ReadOnly  bool   `json:"read_only,omitempty"`
+}
+
+// SidecarResources holds a sidecar's resource requests and limits; empty values are unset
+type SidecarResources struct {
+	CPURequest    string `json:"cpu_request,omitempty"`
+	CPULimit      string `json:"cpu_limit,omitempty"`
+	MemoryRequest string `json:"memory_request,omitempty"`
+	MemoryLimit   string `json:"memory_limit,omitempty"`
+}
+
+// InitContainerConfig describes a container run to completion before the app container starts.
+// Kubernetes restarts it until it succeeds, so its command should be safe to repeat.
+type InitContainerConfig struct {
+	Image          string            `json:"image,omitempty"`           // App image when empty
+	Command        []string          `json:"command,omitempty"`         // Image entrypoint when empty
+	Env            map[string]string `json:"env,omitempty"`             // Added to the app's environment
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"` // Default: 300
+}
+
+// NodeAffinity places the app's pods by node label and lets them run on tainted nodes
+type NodeAffinity struct {
+	RequiredLabels  map[string]string `json:"required_labels,omitempty"`  // Pods only run on nodes with all of these labels
+	PreferredLabels map[string]string `json:"preferred_labels,omitempty"` // Nodes with these labels are preferred when they have room
+	Tolerations     []Toleration      `json:"tolerations,omitempty"`
+}
+
+// Toleration lets pods run on nodes with a matching taint
+type Toleration struct {
+	Key      string `json:"key,omitempty"`
+	Operator string `json:"operator,omitempty"` // Equal (default) or Exists
+	Value    string `json:"value,omitempty"`
+	Effect   string `json:"effect,omitempty"` // NoSchedule, PreferNoSchedule or NoExecute; empty matches all
+}
+
+// PDBConfig sets one bound of a PodDisruptionBudget; one pod is kept available when neither is set
+type PDBConfig struct {
+	MinAvailable   int // Pods kept running
+	MaxUnavailable int // Pods evicted at once, instead of MinAvailable
+}
+
+// WAFConfig puts the app's LoadBalancer behind a Cloud Armor security policy
+type WAFConfig struct {
+	EnableCloudArmor bool
+	SecurityPolicy   string // Name of an existing policy, or "default" for the platform's policy
+}
+
+// RateLimitConfig throttles each client IP of a Cloud Armor protected app
+type RateLimitConfig struct {
+	RequestsPerMinutePerIP int
+	EnforceOnURIPaths      []string // Path prefixes throttled; every path when empty
+}
+
+// MirrorConfig shadows another deployment's traffic to the app. Responses from the app are
+// discarded, so its users are unaffected.
+type MirrorConfig struct {
+	MirrorFrom string // ID of the deployment whose traffic is mirrored
+}
+
+// ABTestConfig sends the baseline's requests carrying a header to the app, the variant. All
+// other requests keep going to the baseline.
+type ABTestConfig struct {
+	BaselineDeploymentID string
+	HeaderName           string // Lowercase, as Istio matches it
+	HeaderValue          string
+	RoutingWeight        int // Percentage of matching requests sent to the variant
+}
+
+// SmokeTest describes an HTTP request expected to succeed against a freshly deployed app
+type SmokeTest struct {
+	Path                 string `json:"path"`
+	Method               string `json:"method,omitempty"`                 // Default: GET
+	ExpectedStatus       int    `json:"expected_status,omitempty"`        // Default: 200
+	ExpectedBodyContains string `json:"expected_body_contains,omitempty"` // Optional substring of the response body
+	TimeoutSeconds       int    `json:"timeout_seconds,omitempty"`        // Default: 10
+}
+
+// WorkloadIdentityConfig binds the app's Kubernetes ServiceAccount to a GCP service account
+type WorkloadIdentityConfig struct {
+	GCPServiceAccountEmail   string // Optional: a service account is created for the app when empty
+	KubernetesServiceAccount string // Name of the ServiceAccount created by the chart
+}
+
+// CronJobConfig holds scheduling options for cronjob deployments
+type CronJobConfig struct {
+	Schedule                string // Cron expression, e.g. "*/15 * * * *"
+	Concurrency             string // Allow, Forbid, Replace
+	StartingDeadlineSeconds int    // 0 means no deadline
 }
 
 // DeployResult contains the result of a deployment
@@ -92,6 +298,7 @@ type DestroyRequest struct {
 	InfrastructureID string
 	Namespace        string
 	ReleaseName      string
+	DeleteVolumes    bool // Delete persistent volume claims; otherwise they and the namespace are kept
 }
 
 // RollbackRequest contains information for rolling back a deployment
@@ -103,6 +310,131 @@ type RollbackRequest struct {
 	Revision         int // 0 for previous revision
 }
 
+// ReleaseRevision describes a single Helm release revision
+type ReleaseRevision struct {
+	Revision    int       `json:"revision"`
+	Updated     time.Time `json:"updated"`
+	Status      string    `json:"status"`
+	Chart       string    `json:"chart"`
+	AppVersion  string    `json:"app_version"`
+	Description string    `json:"description"`
+}
+
+// RollbackOption is a Helm revision a release can be rolled back to
+type RollbackOption struct {
+	Revision   int
+	Status     string // superseded, or deployed for the live revision
+	ImageTag   string // image.tag of the revision's values; empty when they set none
+	DeployedAt time.Time
+}
+
+// ReleaseInfo describes a live Helm release as reported by helm status
+type ReleaseInfo struct {
+	Name         string
+	Namespace    string
+	Status       string
+	Revision     int
+	Chart        string // Chart name and version, e.g. base-app-0.1.0
+	LastDeployed time.Time
+	Values       map[string]interface{} // User-supplied values
+}
+
+// PodInfo describes a pod of a deployment
+type PodInfo struct {
+	Name       string
+	Status     string // Pod phase, or the waiting reason of a container such as CrashLoopBackOff
+	Node       string
+	CreatedAt  time.Time
+	Restarts   int32 // Sum over all containers
+	Containers []ContainerStatusInfo
+}
+
+// ContainerStatusInfo describes the state of one container in a pod
+type ContainerStatusInfo struct {
+	Name     string
+	Image    string
+	Ready    bool
+	State    string // waiting, running, terminated
+	Reason   string // e.g. CrashLoopBackOff, OOMKilled
+	Restarts int32
+}
+
+// PodMetrics is the current resource usage of a pod, summed over its containers
+type PodMetrics struct {
+	PodName       string
+	CPUUsageMilli int64 // millicores
+	MemoryUsageMi int64 // MiB
+	Timestamp     time.Time
+}
+
+// HPAStatus describes the current state of a HorizontalPodAutoscaler
+type HPAStatus struct {
+	Name            string
+	MinReplicas     int32
+	MaxReplicas     int32
+	CurrentReplicas int32
+	DesiredReplicas int32
+	Conditions      []HPACondition
+	LastScaleTime   *time.Time
+}
+
+// HPACondition is one condition reported by a HorizontalPodAutoscaler, e.g. ScalingActive
+type HPACondition struct {
+	Type               string
+	Status             string // True, False, Unknown
+	Reason             string
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// VolumeInfo describes a persistent volume claim created for a deployment
+type VolumeInfo struct {
+	Name         string
+	StorageClass string
+	Requested    string // Requested size, e.g. 10Gi
+	Capacity     string // Provisioned size, empty until bound
+	Status       string // Pending, Bound, Lost
+	AccessModes  []string
+}
+
+// EventInfo describes a Kubernetes event
+type EventInfo struct {
+	Type     string // Normal, Warning
+	Reason   string // e.g. TriggeredScaleUp
+	Message  string
+	Object   string // Involved object, e.g. Pod/my-app-5d4f
+	Count    int32
+	LastSeen time.Time
+}
+
+// Lint severities reported by helm lint
+const (
+	LintSeverityInfo    = "INFO"
+	LintSeverityWarning = "WARNING"
+	LintSeverityError   = "ERROR"
+)
+
+// LintIssue is a single finding reported by helm lint
+type LintIssue struct {
+	Severity string // INFO, WARNING, ERROR
+	Message  string
+}
+
+// LintError is returned when helm lint reports ERROR level issues
+type LintError struct {
+	Issues []LintIssue
+}
+
+func (e *LintError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		if issue.Severity == LintSeverityError {
+			messages = append(messages, issue.Message)
+		}
+	}
+	return fmt.Sprintf("helm lint failed: %s", strings.Join(messages, "; "))
+}
+
 // DeploymentStatus represents the current status of a deployment
 type DeploymentStatus struct {
 	ReleaseName   string
@@ -113,4 +445,7 @@ type DeploymentStatus struct {
 	ReadyReplicas int
 	TotalReplicas int
 	ExternalIP    string
+
+	// Set for cronjob deployments instead of replica counts
+	LastScheduleTime *time.Time
 }
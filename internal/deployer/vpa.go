@@ -0,0 +1,95 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// ErrNoVPARecommendation is returned when a release has no VerticalPodAutoscaler, or its
+// recommender has not observed enough usage to recommend resources yet
+var ErrNoVPARecommendation = errors.New("no VPA recommendation available")
+
+// verticalPodAutoscalerList mirrors the autoscaling.k8s.io/v1 VerticalPodAutoscalerList
+// served once Vertical Pod Autoscaling is enabled on the cluster
+type verticalPodAutoscalerList struct {
+	Items []struct {
+		Status struct {
+			Recommendation *struct {
+				ContainerRecommendations []struct {
+					ContainerName string                                    `json:"containerName"`
+					Target        map[corev1.ResourceName]resource.Quantity `json:"target"`
+					UpperBound    map[corev1.ResourceName]resource.Quantity `json:"upperBound"`
+				} `json:"containerRecommendations"`
+			} `json:"recommendation"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// GetVPARecommendation returns the recommendation of the VerticalPodAutoscaler matching the
+// selector. Requests are the recommender's target and limits its upper bound. The base chart
+// creates the VPA in recommendation-only mode, so the values are never applied to running pods.
+func (k *KubeClient) GetVPARecommendation(ctx context.Context, namespace string, labelSelector string) (*state.VPARecommendation, error) {
+	data, err := k.clientset.Discovery().RESTClient().Get().
+		AbsPath("/apis/autoscaling.k8s.io/v1/namespaces", namespace, "verticalpodautoscalers").
+		Param("labelSelector", labelSelector).
+		DoRaw(ctx)
+	if apierrors.IsNotFound(err) {
+		// Clusters without Vertical Pod Autoscaling do not serve the API at all
+		return nil, ErrNoVPARecommendation
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vertical pod autoscalers: %w", err)
+	}
+
+	var list verticalPodAutoscalerList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse vertical pod autoscalers: %w", err)
+	}
+
+	for _, item := range list.Items {
+		if item.Status.Recommendation == nil {
+			continue
+		}
+
+		// The chart's workloads run a single app container
+		for _, container := range item.Status.Recommendation.ContainerRecommendations {
+			return &state.VPARecommendation{
+				Container:     container.ContainerName,
+				CPURequest:    cpuQuantity(container.Target[corev1.ResourceCPU]),
+				MemoryRequest: memoryQuantity(container.Target[corev1.ResourceMemory]),
+				CPULimit:      cpuQuantity(container.UpperBound[corev1.ResourceCPU]),
+				MemoryLimit:   memoryQuantity(container.UpperBound[corev1.ResourceMemory]),
+				RecordedAt:    time.Now().UTC(),
+			}, nil
+		}
+	}
+
+	return nil, ErrNoVPARecommendation
+}
+
+// cpuQuantity formats a CPU quantity in millicores, e.g. 250m
+func cpuQuantity(q resource.Quantity) string {
+	if q.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+// memoryQuantity formats a memory quantity in mebibytes, rounded up, e.g. 256Mi. The
+// recommender reports memory in bytes.
+func memoryQuantity(q resource.Quantity) string {
+	if q.IsZero() {
+		return ""
+	}
+	const mebibyte = 1024 * 1024
+	return fmt.Sprintf("%dMi", (q.Value()+mebibyte-1)/mebibyte)
+}
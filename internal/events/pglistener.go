@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// minReconnectDelay and maxReconnectDelay bound the backoff while the connection is down
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+
+	// notificationBuffer is how many notifications are held for a slow reader before blocking
+	notificationBuffer = 64
+)
+
+// Notification is a payload sent on a Postgres channel with NOTIFY. A notification with no
+// channel is sent after the connection is reopened, since others may have been missed.
+type Notification struct {
+	Channel string
+	Payload string
+}
+
+// PGListener receives Postgres notifications over a dedicated connection. LISTEN is
+// session-scoped, so it cannot go through GORM's pool.
+type PGListener struct {
+	dsn string
+}
+
+// NewPGListener creates a listener connecting with dsn
+func NewPGListener(dsn string) *PGListener {
+	return &PGListener{dsn: dsn}
+}
+
+// Listen starts listening on the given channels over one connection and returns the
+// notifications received on them. The connection is reopened when it is lost; notifications
+// sent while it is down are lost, so readers should reload the state they follow when they
+// receive the reconnect notification. The returned channel is closed when ctx is done.
+func (l *PGListener) Listen(ctx context.Context, channels ...string) (<-chan Notification, error) {
+	// The first connection is made here so a bad DSN or an unreachable database is reported
+	conn, err := l.connect(ctx, channels)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Notification, notificationBuffer)
+	go l.run(ctx, conn, channels, out)
+
+	return out, nil
+}
+
+// run forwards notifications until ctx is done, reconnecting with backoff on failures
+func (l *PGListener) run(ctx context.Context, conn *pgx.Conn, channels []string, out chan<- Notification) {
+	defer close(out)
+
+	channel := strings.Join(channels, ",")
+	delay := minReconnectDelay
+	for {
+		if conn == nil {
+			var err error
+			if conn, err = l.connect(ctx, channels); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warn().Err(err).Str("channel", channel).Dur("retry_in", delay).
+					Msg("Failed to reconnect Postgres listener")
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delay):
+				}
+				delay = min(delay*2, maxReconnectDelay)
+				continue
+			}
+			log.Info().Str("channel", channel).Msg("Postgres listener reconnected")
+			delay = minReconnectDelay
+
+			select {
+			case out <- Notification{}:
+			case <-ctx.Done():
+				closeConn(conn)
+				return
+			}
+		}
+
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			closeConn(conn)
+			conn = nil
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warn().Err(err).Str("channel", channel).Msg("Postgres listener connection lost")
+			continue
+		}
+
+		select {
+		case out <- Notification{Channel: n.Channel, Payload: n.Payload}:
+		case <-ctx.Done():
+			closeConn(conn)
+			return
+		}
+	}
+}
+
+// connect opens a connection and subscribes it to channels
+func (l *PGListener) connect(ctx context.Context, channels []string) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	for _, channel := range channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			closeConn(conn)
+			return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// closeConn closes conn without waiting on a context that may already be done
+func closeConn(conn *pgx.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = conn.Close(ctx)
+}
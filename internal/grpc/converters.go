@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/alvesdmateus/app-deployer/api/proto"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// deploymentToProto converts a state.Deployment to its protobuf representation
+func deploymentToProto(d *state.Deployment) *pb.Deployment {
+	deployment := &pb.Deployment{
+		Id:          d.ID.String(),
+		Name:        d.Name,
+		AppName:     d.AppName,
+		Version:     d.Version,
+		Status:      d.Status,
+		Cloud:       d.Cloud,
+		Region:      d.Region,
+		ExternalIp:  d.ExternalIP,
+		ExternalUrl: d.ExternalURL,
+		Error:       d.Error,
+		CreatedAt:   timestamppb.New(d.CreatedAt),
+		UpdatedAt:   timestamppb.New(d.UpdatedAt),
+	}
+
+	if d.DeployedAt != nil {
+		deployment.DeployedAt = timestamppb.New(*d.DeployedAt)
+	}
+
+	return deployment
+}
+
+// addonsFromProto converts protobuf addons to provisioner addon configs
+func addonsFromProto(addons []*pb.Addon) []provisioner.AddonConfig {
+	if len(addons) == 0 {
+		return nil
+	}
+
+	configs := make([]provisioner.AddonConfig, len(addons))
+	for i, addon := range addons {
+		configs[i] = provisioner.AddonConfig{
+			Type:   addon.Type,
+			Config: addon.Config.AsMap(),
+		}
+	}
+	return configs
+}
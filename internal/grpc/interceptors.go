@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LoggingUnaryInterceptor logs unary RPCs, mirroring the HTTP RequestLogger
+func LoggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	log.Info().
+		Str("method", info.FullMethod).
+		Str("code", status.Code(err).String()).
+		Dur("duration", time.Since(start)).
+		Msg("gRPC request")
+
+	return resp, err
+}
+
+// LoggingStreamInterceptor logs streaming RPCs once the stream ends
+func LoggingStreamInterceptor(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	log.Info().
+		Str("method", info.FullMethod).
+		Str("code", status.Code(err).String()).
+		Dur("duration", time.Since(start)).
+		Msg("gRPC stream")
+
+	return err
+}
+
+// RecoveryUnaryInterceptor recovers from panics in unary handlers, mirroring RecoveryMiddleware
+func RecoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().
+				Interface("error", r).
+				Str("method", info.FullMethod).
+				Msg("Panic recovered")
+
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// RecoveryStreamInterceptor recovers from panics in streaming handlers
+func RecoveryStreamInterceptor(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().
+				Interface("error", r).
+				Str("method", info.FullMethod).
+				Msg("Panic recovered")
+
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
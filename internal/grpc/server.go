@@ -0,0 +1,487 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/alvesdmateus/app-deployer/api/proto"
+	"github.com/alvesdmateus/app-deployer/internal/events"
+	"github.com/alvesdmateus/app-deployer/internal/orchestrator"
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// Server implements the DeployerService gRPC API on top of the same repository
+// and orchestrator client used by the HTTP handlers
+type Server struct {
+	pb.UnimplementedDeployerServiceServer
+
+	repo       *state.Repository
+	orchClient *orchestrator.Client
+	listener   *events.PGListener
+	grpcServer *grpclib.Server
+}
+
+// NewServer creates a new gRPC server. orchClient may be nil, in which case
+// orchestration RPCs return Unavailable. listener may be nil, in which case
+// StreamLogs cannot follow.
+func NewServer(repo *state.Repository, orchClient *orchestrator.Client, listener *events.PGListener) *Server {
+	s := &Server{
+		repo:       repo,
+		orchClient: orchClient,
+		listener:   listener,
+	}
+
+	s.grpcServer = grpclib.NewServer(
+		grpclib.ChainUnaryInterceptor(RecoveryUnaryInterceptor, LoggingUnaryInterceptor),
+		grpclib.ChainStreamInterceptor(RecoveryStreamInterceptor, LoggingStreamInterceptor),
+	)
+	pb.RegisterDeployerServiceServer(s.grpcServer, s)
+
+	return s
+}
+
+// Serve accepts connections on the listener until Stop or GracefulStop is called
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpcServer.Serve(lis)
+}
+
+// GracefulStop stops accepting connections and waits for in-flight RPCs to finish
+func (s *Server) GracefulStop() {
+	s.grpcServer.GracefulStop()
+}
+
+// CreateDeployment creates a deployment and triggers provisioning if an image tag is given
+func (s *Server) CreateDeployment(ctx context.Context, req *pb.CreateDeploymentRequest) (*pb.Deployment, error) {
+	if req.Name == "" || req.AppName == "" || req.Version == "" {
+		return nil, status.Error(codes.InvalidArgument, "Name, app_name, and version are required")
+	}
+
+	addons := addonsFromProto(req.Addons)
+	if err := provisioner.ValidateAddons(addons); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	cloud := req.Cloud
+	if cloud == "" {
+		cloud = "gcp"
+	}
+
+	region := req.Region
+	if region == "" {
+		region = "us-central1"
+	}
+
+	port := int(req.Port)
+	if port == 0 {
+		port = 8080
+	}
+
+	deployment := &state.Deployment{
+		Name:    req.Name,
+		AppName: req.AppName,
+		Version: req.Version,
+		Status:  "PENDING",
+		Cloud:   cloud,
+		Region:  region,
+		Port:    port,
+	}
+
+	if err := s.repo.CreateDeployment(ctx, deployment); err != nil {
+		log.Error().Err(err).Msg("Failed to create deployment")
+		return nil, status.Error(codes.Internal, "Failed to create deployment")
+	}
+
+	if s.orchClient != nil && req.ImageTag != "" {
+		provisionPayload := &queue.ProvisionPayload{
+			DeploymentID: deployment.ID.String(),
+			AppName:      deployment.AppName,
+			Version:      deployment.Version,
+			Cloud:        deployment.Cloud,
+			Region:       deployment.Region,
+			ImageTag:     req.ImageTag,
+			Addons:       addons,
+		}
+
+		if err := s.orchClient.TriggerProvision(ctx, provisionPayload); err != nil {
+			log.Error().Err(err).
+				Str("deployment_id", deployment.ID.String()).
+				Msg("Failed to trigger provision job")
+			_ = s.repo.UpdateDeploymentStatus(ctx, deployment.ID, "FAILED")
+			return nil, status.Error(codes.Internal, "Deployment created but provisioning failed to start")
+		}
+
+		_ = s.repo.UpdateDeploymentStatus(ctx, deployment.ID, "QUEUED")
+		deployment.Status = "QUEUED"
+	}
+
+	return deploymentToProto(deployment), nil
+}
+
+// GetDeployment returns a single deployment
+func (s *Server) GetDeployment(ctx context.Context, req *pb.GetDeploymentRequest) (*pb.Deployment, error) {
+	deployment, err := s.getDeployment(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return deploymentToProto(deployment), nil
+}
+
+// ListDeployments returns a page of deployments matching the request filters
+func (s *Server) ListDeployments(ctx context.Context, req *pb.ListDeploymentsRequest) (*pb.ListDeploymentsResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 20
+	}
+
+	offset := int(req.Offset)
+	if offset < 0 {
+		offset = 0
+	}
+
+	filter := state.DeploymentFilter{
+		Status: req.Status,
+		Cloud:  req.Cloud,
+		Region: req.Region,
+	}
+
+	deployments, err := s.repo.ListDeploymentsFiltered(ctx, filter, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list deployments")
+		return nil, status.Error(codes.Internal, "Failed to list deployments")
+	}
+
+	response := &pb.ListDeploymentsResponse{
+		Deployments: make([]*pb.Deployment, len(deployments)),
+		Total:       int32(len(deployments)),
+		Limit:       int32(limit),
+		Offset:      int32(offset),
+	}
+	for i := range deployments {
+		response.Deployments[i] = deploymentToProto(&deployments[i])
+	}
+
+	return response, nil
+}
+
+// DeleteDeployment triggers destruction of a deployment's infrastructure, or deletes
+// the record directly when there is nothing to destroy
+func (s *Server) DeleteDeployment(ctx context.Context, req *pb.DeleteDeploymentRequest) (*pb.DeleteDeploymentResponse, error) {
+	deployment, err := s.getDeployment(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.orchClient != nil && deployment.InfrastructureID != nil {
+		destroyPayload := &queue.DestroyPayload{
+			DeploymentID:     deployment.ID.String(),
+			InfrastructureID: deployment.InfrastructureID.String(),
+		}
+
+		if err := s.orchClient.TriggerDestroy(ctx, destroyPayload); err != nil {
+			log.Error().Err(err).
+				Str("deployment_id", req.Id).
+				Msg("Failed to trigger destroy job")
+			return nil, status.Error(codes.Internal, "Failed to initiate destruction process")
+		}
+
+		_ = s.repo.UpdateDeploymentStatus(ctx, deployment.ID, "DESTROYING")
+
+		return &pb.DeleteDeploymentResponse{
+			Message: "Destruction initiated. Infrastructure will be cleaned up asynchronously.",
+		}, nil
+	}
+
+	if err := s.repo.DeleteDeployment(ctx, deployment.ID); err != nil {
+		log.Error().Err(err).Str("id", req.Id).Msg("Failed to delete deployment")
+		return nil, status.Error(codes.Internal, "Failed to delete deployment")
+	}
+
+	return &pb.DeleteDeploymentResponse{Message: "Deployment deleted"}, nil
+}
+
+// StartDeployment provisions infrastructure and deploys the given image
+func (s *Server) StartDeployment(ctx context.Context, req *pb.StartDeploymentRequest) (*pb.OrchestrationResponse, error) {
+	if req.ImageTag == "" {
+		return nil, status.Error(codes.InvalidArgument, "image_tag is required")
+	}
+
+	addons := addonsFromProto(req.Addons)
+	if err := provisioner.ValidateAddons(addons); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	deployment, err := s.getDeployment(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Approval requests and decisions are only available over REST
+	if deployment.RequiresApproval {
+		return nil, status.Error(codes.FailedPrecondition,
+			"Deployment requires approval, start it with POST /api/v1/deployments/{id}/deploy")
+	}
+
+	if s.orchClient == nil {
+		return nil, status.Error(codes.Unavailable, "Orchestration service unavailable")
+	}
+
+	provisionPayload := &queue.ProvisionPayload{
+		DeploymentID: deployment.ID.String(),
+		AppName:      deployment.AppName,
+		Version:      deployment.Version,
+		Cloud:        deployment.Cloud,
+		Region:       deployment.Region,
+		ImageTag:     req.ImageTag,
+		Addons:       addons,
+	}
+
+	if err := s.orchClient.TriggerProvision(ctx, provisionPayload); err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", req.Id).
+			Msg("Failed to trigger provision job")
+		return nil, status.Error(codes.Internal, "Failed to start deployment")
+	}
+
+	_ = s.repo.UpdateDeploymentStatus(ctx, deployment.ID, "QUEUED")
+
+	return &pb.OrchestrationResponse{
+		DeploymentId: deployment.ID.String(),
+		Status:       "QUEUED",
+		Message:      "Deployment started. Infrastructure will be provisioned and application deployed.",
+	}, nil
+}
+
+// TriggerRollback rolls a deployment back to a previous version
+func (s *Server) TriggerRollback(ctx context.Context, req *pb.TriggerRollbackRequest) (*pb.OrchestrationResponse, error) {
+	if req.TargetVersion == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_version is required")
+	}
+
+	deployment, err := s.getDeployment(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if deployment.InfrastructureID == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Deployment has no infrastructure to rollback")
+	}
+
+	if s.orchClient == nil {
+		return nil, status.Error(codes.Unavailable, "Orchestration service unavailable")
+	}
+
+	rollbackPayload := &queue.RollbackPayload{
+		DeploymentID:  deployment.ID.String(),
+		TargetVersion: req.TargetVersion,
+		TargetTag:     req.TargetTag,
+	}
+
+	if err := s.orchClient.TriggerRollback(ctx, rollbackPayload); err != nil {
+		log.Error().Err(err).
+			Str("deployment_id", req.Id).
+			Msg("Failed to trigger rollback job")
+		return nil, status.Error(codes.Internal, "Failed to start rollback")
+	}
+
+	_ = s.repo.UpdateDeploymentStatus(ctx, deployment.ID, "ROLLING_BACK")
+
+	return &pb.OrchestrationResponse{
+		DeploymentId: deployment.ID.String(),
+		Status:       "ROLLING_BACK",
+		Message:      "Rollback to version " + req.TargetVersion + " initiated",
+	}, nil
+}
+
+// StreamLogs sends status changes and new provision/build log lines for a deployment.
+// Without follow it sends what is currently recorded and returns. When following, the
+// deployment is reloaded each time its status or one of its builds changes.
+func (s *Server) StreamLogs(req *pb.StreamLogsRequest, stream pb.DeployerService_StreamLogsServer) error {
+	ctx := stream.Context()
+
+	if _, err := s.getDeployment(ctx, req.Id); err != nil {
+		return err
+	}
+
+	id, _ := uuid.Parse(req.Id)
+	cursor := &logCursor{}
+
+	// Subscribe before the first load so that no change made in between is missed
+	var updates <-chan events.Notification
+	if req.Follow {
+		if s.listener == nil {
+			return status.Error(codes.Unavailable, "Following logs is not available")
+		}
+
+		var err error
+		updates, err = s.listener.Listen(ctx, state.ChannelDeploymentUpdates, state.ChannelBuildUpdates)
+		if err != nil {
+			log.Error().Err(err).Str("id", req.Id).Msg("Failed to listen for deployment updates")
+			return status.Error(codes.Unavailable, "Failed to follow deployment logs")
+		}
+	}
+
+	for {
+		// Read from the primary, since a notification may arrive before a replica or the
+		// cache has caught up with the change
+		deployment, err := s.repo.GetDeploymentConsistent(ctx, id)
+		if err != nil {
+			// Deployment was deleted while streaming
+			return nil
+		}
+
+		for _, entry := range cursor.next(deployment) {
+			if err := stream.Send(entry); err != nil {
+				return err
+			}
+		}
+
+		if !req.Follow || isSettled(deployment.Status) {
+			return nil
+		}
+
+		if !waitForUpdate(ctx, updates, id) {
+			return nil
+		}
+	}
+}
+
+// waitForUpdate blocks until a notification concerns the deployment or the listener has
+// reconnected, and reports false once the stream is done
+func waitForUpdate(ctx context.Context, updates <-chan events.Notification, id uuid.UUID) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+
+		case n, ok := <-updates:
+			if !ok {
+				return false
+			}
+
+			// Notifications may have been missed while the listener was reconnecting
+			if n.Channel == "" {
+				return true
+			}
+
+			var payload state.StatusNotification
+			if err := json.Unmarshal([]byte(n.Payload), &payload); err != nil {
+				continue
+			}
+
+			switch n.Channel {
+			case state.ChannelDeploymentUpdates:
+				if payload.ID == id {
+					return true
+				}
+			case state.ChannelBuildUpdates:
+				if payload.DeploymentID != nil && *payload.DeploymentID == id {
+					return true
+				}
+			}
+		}
+	}
+}
+
+// getDeployment parses an ID and loads the deployment, mapping failures to gRPC status errors
+func (s *Server) getDeployment(ctx context.Context, idStr string) (*state.Deployment, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid deployment ID")
+	}
+
+	deployment, err := s.repo.GetDeployment(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Deployment not found")
+		return nil, status.Error(codes.NotFound, "Deployment not found")
+	}
+
+	return deployment, nil
+}
+
+// logCursor tracks how much of a deployment's output has already been streamed
+type logCursor struct {
+	status       string
+	provisionPos int
+	buildID      uuid.UUID
+	buildPos     int
+}
+
+// next returns log entries for anything that changed since the previous call
+func (c *logCursor) next(d *state.Deployment) []*pb.DeploymentLogEntry {
+	var entries []*pb.DeploymentLogEntry
+	now := timestamppb.Now()
+
+	if d.Status != c.status {
+		entries = append(entries, &pb.DeploymentLogEntry{
+			Timestamp: timestamppb.New(d.UpdatedAt),
+			Source:    "status",
+			Message:   d.Status,
+		})
+		c.status = d.Status
+	}
+
+	if d.Infrastructure != nil && len(d.Infrastructure.ProvisionLog) > c.provisionPos {
+		for _, line := range splitLines(d.Infrastructure.ProvisionLog[c.provisionPos:]) {
+			entries = append(entries, &pb.DeploymentLogEntry{Timestamp: now, Source: "provision", Message: line})
+		}
+		c.provisionPos = len(d.Infrastructure.ProvisionLog)
+	}
+
+	if build := latestBuild(d.Builds); build != nil {
+		if build.ID != c.buildID {
+			c.buildID = build.ID
+			c.buildPos = 0
+		}
+		if len(build.BuildLog) > c.buildPos {
+			for _, line := range splitLines(build.BuildLog[c.buildPos:]) {
+				entries = append(entries, &pb.DeploymentLogEntry{Timestamp: now, Source: "build", Message: line})
+			}
+			c.buildPos = len(build.BuildLog)
+		}
+	}
+
+	return entries
+}
+
+// latestBuild returns the most recently started build, or nil
+func latestBuild(builds []state.Build) *state.Build {
+	var latest *state.Build
+	for i := range builds {
+		if latest == nil || builds[i].StartedAt.After(latest.StartedAt) {
+			latest = &builds[i]
+		}
+	}
+	return latest
+}
+
+// splitLines splits log output into non-empty lines
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// isSettled reports whether a deployment status is not expected to change on its own
+func isSettled(status string) bool {
+	switch status {
+	case "PENDING", "EXPOSED", "HEALTHY", "UNHEALTHY", "DRIFTED", "FAILED", "DESTROYED":
+		return true
+	}
+	return false
+}
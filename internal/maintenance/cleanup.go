@@ -0,0 +1,90 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+)
+
+// cleanupInterval is how often old records are cleaned up
+const cleanupInterval = 24 * time.Hour
+
+// CleanupResult reports what one cleanup run deleted
+type CleanupResult struct {
+	LogsDeleted   int64
+	RetentionDays int
+}
+
+// Cleaner deletes records that have outlived their retention period
+type Cleaner struct {
+	repo          *state.Repository
+	retentionDays int
+	logger        zerolog.Logger
+
+	logsDeleted atomic.Int64
+}
+
+// NewCleaner creates a cleaner keeping deployment logs for retentionDays; -1 keeps them forever
+func NewCleaner(repo *state.Repository, retentionDays int, logger zerolog.Logger) *Cleaner {
+	return &Cleaner{
+		repo:          repo,
+		retentionDays: retentionDays,
+		logger:        logger.With().Str("component", "cleanup").Logger(),
+	}
+}
+
+// LogsDeleted returns how many deployment log entries this cleaner has deleted since it was created
+func (c *Cleaner) LogsDeleted() int64 {
+	return c.logsDeleted.Load()
+}
+
+// Start runs a cleanup now and then daily until the context is cancelled
+func (c *Cleaner) Start(ctx context.Context) {
+	if c.retentionDays < 1 {
+		c.logger.Info().Msg("Deployment log retention disabled, logs are kept forever")
+		return
+	}
+
+	c.logger.Info().
+		Int("retention_days", c.retentionDays).
+		Msg("Starting deployment log cleanup")
+
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Run(ctx); err != nil {
+			c.logger.Error().Err(err).Msg("Cleanup failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			c.logger.Info().Msg("Deployment log cleanup stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run deletes deployment log entries older than the retention period
+func (c *Cleaner) Run(ctx context.Context) (*CleanupResult, error) {
+	deleted, err := c.repo.CleanupOldLogs(ctx, c.retentionDays)
+	if err != nil {
+		return nil, fmt.Errorf("clean up deployment logs: %w", err)
+	}
+
+	c.logsDeleted.Add(deleted)
+
+	if deleted > 0 {
+		c.logger.Info().
+			Int64("logs_deleted", deleted).
+			Int("retention_days", c.retentionDays).
+			Msg("Deleted expired deployment logs")
+	}
+
+	return &CleanupResult{LogsDeleted: deleted, RetentionDays: c.retentionDays}, nil
+}
@@ -0,0 +1,37 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// startABTest routes the baseline's matching requests to a freshly deployed variant. As with
+// mirrors, the variant stays deployed when this fails and the error goes to its logs.
+func (w *Worker) startABTest(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure, test *deployer.ABTestConfig) {
+	logger := w.logger.With().
+		Str("deployment_id", deployment.ID.String()).
+		Str("baseline_deployment_id", test.BaselineDeploymentID).
+		Logger()
+
+	level := "INFO"
+	message := fmt.Sprintf("Routing requests to deployment %s with header %s: %s", test.BaselineDeploymentID, test.HeaderName, test.HeaderValue)
+	if err := w.syncSourceRoutes(ctx, infra, test.BaselineDeploymentID); err != nil {
+		logger.Error().Err(err).Msg("Failed to start A/B test routing")
+		level, message = "ERROR", fmt.Sprintf("Failed to route A/B test traffic of deployment %s: %s", test.BaselineDeploymentID, err)
+	} else {
+		logger.Info().Msg("A/B test routing started")
+	}
+
+	if err := w.engine.repo.CreateDeploymentLog(ctx, &state.DeploymentLog{
+		DeploymentID: deployment.ID,
+		Phase:        deployment.Status,
+		Level:        level,
+		Source:       "ab-test",
+		Message:      message,
+	}); err != nil {
+		logger.Warn().Err(err).Msg("Failed to record A/B test log")
+	}
+}
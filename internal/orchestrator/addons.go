@@ -0,0 +1,27 @@
+package orchestrator
+
+import (
+	"strconv"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// addonEnv builds the environment variables that expose provisioned addons to the application
+func addonEnv(infra *state.Infrastructure) map[string]string {
+	env := make(map[string]string)
+
+	if infra.DatabaseConnectionName != "" {
+		env["DATABASE_CONNECTION_NAME"] = infra.DatabaseConnectionName
+		env["DATABASE_HOST"] = infra.DatabaseHost
+		env["DATABASE_PORT"] = strconv.Itoa(infra.DatabasePort)
+		env["DATABASE_NAME"] = infra.DatabaseName
+		env["DATABASE_USER"] = infra.DatabaseUser
+	}
+
+	if infra.RedisHost != "" {
+		env["REDIS_HOST"] = infra.RedisHost
+		env["REDIS_PORT"] = strconv.Itoa(infra.RedisPort)
+	}
+
+	return env
+}
@@ -0,0 +1,183 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/alvesdmateus/app-deployer/internal/analyzer"
+	"github.com/alvesdmateus/app-deployer/internal/builder"
+	"github.com/alvesdmateus/app-deployer/internal/builder/strategies"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/google/uuid"
+)
+
+// shortSHALength is how much of a commit SHA versions built images
+const shortSHALength = 12
+
+// handleBuildJob checks out a pushed commit, builds and pushes its image, and starts a rollout
+// of the image. Deployments that require approval are only built; the rollout is left to be
+// started, and approved, through the API.
+func (w *Worker) handleBuildJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	payload, err := parseBuildPayload(job)
+	if err != nil {
+		return fmt.Errorf("parse build payload: %w", err)
+	}
+
+	if w.engine.buildService == nil {
+		return fmt.Errorf("build service not configured on this worker")
+	}
+
+	deploymentID, err := uuid.Parse(payload.DeploymentID)
+	if err != nil {
+		return fmt.Errorf("parse deployment ID: %w", err)
+	}
+
+	deployment, err := w.engine.repo.GetDeploymentByID(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("get deployment: %w", err)
+	}
+
+	logger.Info().
+		Str("repo_url", payload.RepoURL).
+		Str("commit_sha", payload.CommitSHA).
+		Str("sub_path", payload.SubPath).
+		Msg("Building pushed commit")
+
+	sourceDir, err := os.MkdirTemp("", "build-")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(sourceDir)
+
+	if err := checkoutCommit(ctx, payload.RepoURL, payload.CommitSHA, sourceDir); err != nil {
+		w.recordBuildLog(ctx, deployment, "ERROR", fmt.Sprintf("Checkout of %s failed: %v", payload.CommitSHA, err))
+		return err
+	}
+
+	analysis, err := analyzer.New().AnalyzeWithOptions(sourceDir, analyzer.AnalyzeOptions{SubPath: payload.SubPath})
+	if err != nil {
+		w.recordBuildLog(ctx, deployment, "ERROR", fmt.Sprintf("Source analysis failed: %v", err))
+		return fmt.Errorf("analyze source: %w", err)
+	}
+
+	detected := w.detectBuildStrategy(filepath.Join(sourceDir, payload.SubPath))
+	strategy := detected
+	if payload.BuildStrategy != "" {
+		strategy = strategies.StrategyType(payload.BuildStrategy)
+	}
+
+	logger.Info().
+		Str("detected_strategy", string(detected)).
+		Str("strategy", string(strategy)).
+		Msg("Build strategy selected")
+
+	version := payload.CommitSHA
+	if len(version) > shortSHALength {
+		version = version[:shortSHALength]
+	}
+
+	buildCtx := &builder.BuildContext{
+		DeploymentID: deployment.ID.String(),
+		AppName:      deployment.AppName,
+		Version:      version,
+		SourcePath:   sourceDir,
+		SubPath:      payload.SubPath,
+		Analysis:     analysis,
+		Strategy:     string(strategy),
+	}
+
+	result, err := w.engine.buildService.BuildImage(ctx, buildCtx)
+	if err != nil {
+		w.recordBuildLog(ctx, deployment, "ERROR", fmt.Sprintf("Build of %s failed: %v", version, err))
+		return fmt.Errorf("build image: %w", err)
+	}
+
+	logger.Info().
+		Str("build_id", buildCtx.BuildID).
+		Str("image_tag", result.ImageTag).
+		Msg("Pushed commit built")
+
+	if deployment.RequiresApproval {
+		w.recordBuildLog(ctx, deployment, "INFO",
+			fmt.Sprintf("Built %s from %s; the deployment requires approval, start it to request one", result.ImageTag, version))
+		return w.engine.AdvanceStage(ctx, job, nil)
+	}
+
+	if err := w.engine.AdvanceStage(ctx, job, newProvisionJob(&queue.ProvisionPayload{
+		DeploymentID: deployment.ID.String(),
+		AppName:      deployment.AppName,
+		Version:      deployment.Version,
+		Cloud:        deployment.Cloud,
+		Region:       deployment.Region,
+		ImageTag:     result.ImageTag,
+		BuildID:      buildCtx.BuildID,
+	})); err != nil {
+		return err
+	}
+
+	if err := w.engine.repo.UpdateDeploymentStatus(ctx, deployment.ID, "QUEUED"); err != nil {
+		return fmt.Errorf("update deployment status: %w", err)
+	}
+
+	w.recordBuildLog(ctx, deployment, "INFO", fmt.Sprintf("Built %s from %s, deploying", result.ImageTag, version))
+
+	return nil
+}
+
+// detectBuildStrategy picks how the source in contextDir is built: with Kaniko when enabled on
+// a worker running in Kubernetes, from the source's own Dockerfile when it has one, and from a
+// generated Dockerfile otherwise
+func (w *Worker) detectBuildStrategy(contextDir string) strategies.StrategyType {
+	if w.engine.useKaniko && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return strategies.StrategyTypeKaniko
+	}
+
+	if strategies.FindDockerfile(contextDir) != "" {
+		return strategies.StrategyTypeExisting
+	}
+
+	return strategies.StrategyTypeGenerated
+}
+
+// checkoutCommit fetches a single commit of a repository into dir. Fetching by SHA avoids
+// cloning the full history and deploys the pushed commit even if the branch moved since.
+func checkoutCommit(ctx context.Context, repoURL, commitSHA, dir string) error {
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", repoURL},
+		{"fetch", "--quiet", "--depth", "1", "origin", commitSHA},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	}
+
+	for _, args := range steps {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s failed: %w, output: %s", args[0], err, string(output))
+		}
+	}
+
+	return nil
+}
+
+// recordBuildLog adds a build log entry to the deployment's logs
+func (w *Worker) recordBuildLog(ctx context.Context, deployment *state.Deployment, level, message string) {
+	if err := w.engine.repo.CreateDeploymentLog(ctx, &state.DeploymentLog{
+		DeploymentID: deployment.ID,
+		Phase:        deployment.Status,
+		Level:        level,
+		Source:       "git-hook",
+		Message:      message,
+	}); err != nil {
+		w.logger.Warn().Err(err).Str("deployment_id", deployment.ID.String()).Msg("Failed to record build log")
+	}
+}
@@ -12,12 +12,12 @@ import (
 // Client provides orchestration capabilities for the API layer
 // It only requires a queue connection, not the full worker dependencies
 type Client struct {
-	queue  *queue.RedisQueue
+	queue  queue.Queue
 	logger zerolog.Logger
 }
 
 // NewClient creates a new orchestrator client for API use
-func NewClient(q *queue.RedisQueue, logger zerolog.Logger) *Client {
+func NewClient(q queue.Queue, logger zerolog.Logger) *Client {
 	return &Client{
 		queue:  q,
 		logger: logger.With().Str("component", "orchestrator-client").Logger(),
@@ -41,6 +41,9 @@ func (c *Client) TriggerProvision(ctx context.Context, payload *queue.ProvisionP
 		"region":        payload.Region,
 		"image_tag":     payload.ImageTag,
 		"build_id":      payload.BuildID,
+		"replicas":      payload.Replicas,
+		"autoscaling":   payload.Autoscaling,
+		"addons":        payload.Addons,
 	}
 
 	job := &queue.Job{
@@ -67,6 +70,31 @@ func (c *Client) TriggerProvision(ctx context.Context, payload *queue.ProvisionP
 	return nil
 }
 
+// TriggerDeploy enqueues a deploy job to roll out a deployment onto its existing infrastructure
+func (c *Client) TriggerDeploy(ctx context.Context, payload *queue.DeployPayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("infrastructure_id", payload.InfrastructureID).
+		Str("image_tag", payload.ImageTag).
+		Msg("Triggering deploy job")
+
+	job := newDeployJob(payload)
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue deploy job")
+		return fmt.Errorf("enqueue deploy job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Deploy job enqueued successfully")
+
+	return nil
+}
+
 // TriggerDestroy enqueues a destroy job to tear down infrastructure
 func (c *Client) TriggerDestroy(ctx context.Context, payload *queue.DestroyPayload) error {
 	c.logger.Info().
@@ -140,18 +168,267 @@ func (c *Client) TriggerRollback(ctx context.Context, payload *queue.RollbackPay
 	return nil
 }
 
+// TriggerReconcile enqueues a reconcile job to check infrastructure for drift
+func (c *Client) TriggerReconcile(ctx context.Context, payload *queue.ReconcilePayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("infrastructure_id", payload.InfrastructureID).
+		Msg("Triggering reconcile job")
+
+	payloadMap := map[string]interface{}{
+		"deployment_id":     payload.DeploymentID,
+		"infrastructure_id": payload.InfrastructureID,
+	}
+
+	job := &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeReconcile,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue reconcile job")
+		return fmt.Errorf("enqueue reconcile job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Reconcile job enqueued successfully")
+
+	return nil
+}
+
+// TriggerUpdateInfra enqueues a job to update a deployment's infrastructure in place
+func (c *Client) TriggerUpdateInfra(ctx context.Context, payload *queue.UpdateInfraPayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("infrastructure_id", payload.InfrastructureID).
+		Int("node_count", payload.NodeCount).
+		Str("machine_type", payload.MachineType).
+		Msg("Triggering infrastructure update job")
+
+	payloadMap := map[string]interface{}{
+		"deployment_id":     payload.DeploymentID,
+		"infrastructure_id": payload.InfrastructureID,
+		"node_count":        payload.NodeCount,
+		"machine_type":      payload.MachineType,
+	}
+
+	job := &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeUpdateInfra,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue infrastructure update job")
+		return fmt.Errorf("enqueue update infra job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Infrastructure update job enqueued successfully")
+
+	return nil
+}
+
+// TriggerMigrateBackend enqueues a job to move a Pulumi stack to another state backend
+func (c *Client) TriggerMigrateBackend(ctx context.Context, payload *queue.MigrateBackendPayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("infrastructure_id", payload.InfrastructureID).
+		Str("stack_name", payload.StackName).
+		Str("to_backend", payload.ToBackend).
+		Msg("Triggering backend migration job")
+
+	payloadMap := map[string]interface{}{
+		"deployment_id":     payload.DeploymentID,
+		"infrastructure_id": payload.InfrastructureID,
+		"stack_name":        payload.StackName,
+		"from_backend":      payload.FromBackend,
+		"to_backend":        payload.ToBackend,
+	}
+
+	job := &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeMigrateBackend,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue backend migration job")
+		return fmt.Errorf("enqueue migrate backend job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Backend migration job enqueued successfully")
+
+	return nil
+}
+
+// TriggerDestroyStack enqueues a job to destroy a Pulumi stack left behind by a deployment
+func (c *Client) TriggerDestroyStack(ctx context.Context, payload *queue.DestroyStackPayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("stack_name", payload.StackName).
+		Msg("Triggering stack destroy job")
+
+	payloadMap := map[string]interface{}{
+		"deployment_id":     payload.DeploymentID,
+		"infrastructure_id": payload.InfrastructureID,
+		"stack_name":        payload.StackName,
+	}
+
+	job := &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeDestroyStack,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue stack destroy job")
+		return fmt.Errorf("enqueue destroy stack job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Stack destroy job enqueued successfully")
+
+	return nil
+}
+
+// TriggerNotification enqueues a webhook notification of a platform event
+func (c *Client) TriggerNotification(ctx context.Context, payload *queue.NotifyPayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("event_type", payload.EventType).
+		Msg("Triggering notification job")
+
+	job := newNotifyJob(payload)
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue notification job")
+		return fmt.Errorf("enqueue notify job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Notification job enqueued successfully")
+
+	return nil
+}
+
+// TriggerRestart enqueues a job to restart a deployment's pods without redeploying
+func (c *Client) TriggerRestart(ctx context.Context, payload *queue.RestartPayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("infrastructure_id", payload.InfrastructureID).
+		Msg("Triggering restart job")
+
+	payloadMap := map[string]interface{}{
+		"deployment_id":     payload.DeploymentID,
+		"infrastructure_id": payload.InfrastructureID,
+	}
+
+	job := &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeRestart,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue restart job")
+		return fmt.Errorf("enqueue restart job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Restart job enqueued successfully")
+
+	return nil
+}
+
+// TriggerBuild enqueues a job to build a commit and deploy the resulting image
+func (c *Client) TriggerBuild(ctx context.Context, payload *queue.BuildPayload) error {
+	c.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("repo_url", payload.RepoURL).
+		Str("commit_sha", payload.CommitSHA).
+		Msg("Triggering build job")
+
+	payloadMap := map[string]interface{}{
+		"deployment_id":  payload.DeploymentID,
+		"repo_url":       payload.RepoURL,
+		"commit_sha":     payload.CommitSHA,
+		"ref":            payload.Ref,
+		"provider":       payload.Provider,
+		"sub_path":       payload.SubPath,
+		"build_strategy": payload.BuildStrategy,
+	}
+
+	job := &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeBuild,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+
+	if err := c.queue.Enqueue(ctx, job); err != nil {
+		c.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue build job")
+		return fmt.Errorf("enqueue build job: %w", err)
+	}
+
+	c.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Build job enqueued successfully")
+
+	return nil
+}
+
 // GetQueueStats returns statistics about the job queues
 func (c *Client) GetQueueStats(ctx context.Context) (map[string]int64, error) {
 	stats := make(map[string]int64)
 
-	jobTypes := []queue.JobType{
-		queue.JobTypeProvision,
-		queue.JobTypeDeploy,
-		queue.JobTypeDestroy,
-		queue.JobTypeRollback,
-	}
-
-	for _, jt := range jobTypes {
+	for _, jt := range queue.JobTypes {
 		length, err := c.queue.GetQueueLength(ctx, jt)
 		if err != nil {
 			return nil, fmt.Errorf("get queue length for %s: %w", jt, err)
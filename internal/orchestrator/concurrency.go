@@ -0,0 +1,229 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// concurrencyCheckInterval is how often queue depth is checked to tune concurrency
+	concurrencyCheckInterval = 30 * time.Second
+
+	// lowDepthChecks is how many checks in a row must find the queues below the low water
+	// mark before concurrency is lowered, so a brief lull does not shrink the pool
+	lowDepthChecks = 5
+)
+
+// ConcurrencyConfig holds worker concurrency tuning configuration
+type ConcurrencyConfig struct {
+	Initial       int   // Concurrency to start with
+	Min           int   // Lowest concurrency tuning goes down to
+	Max           int   // Highest concurrency tuning goes up to
+	HighWaterMark int64 // Total queue depth above which concurrency is raised
+	LowWaterMark  int64 // Total queue depth below which concurrency is lowered
+}
+
+// DynamicConcurrencyManager bounds how many jobs the worker runs at once and tunes that
+// bound to the depth of the job queues. A job slot is a token in a channel; raising
+// concurrency adds tokens and lowering it retires them, waiting for held ones to be released.
+type DynamicConcurrencyManager struct {
+	queue  queue.Queue
+	config ConcurrencyConfig
+	logger zerolog.Logger
+	tokens chan struct{}
+
+	mu         sync.Mutex
+	level      int   // Current concurrency, counting tokens yet to be retired
+	retiring   int   // Held tokens to drop instead of returning once released
+	queueDepth int64 // Total queue depth at the last check
+	lowChecks  int   // Consecutive checks below the low water mark
+}
+
+// NewDynamicConcurrencyManager creates a concurrency manager starting at config.Initial.
+// Tuning is off when Max is not above Min.
+func NewDynamicConcurrencyManager(q queue.Queue, config ConcurrencyConfig, logger zerolog.Logger) *DynamicConcurrencyManager {
+	if config.Min < 1 {
+		config.Min = 1
+	}
+
+	if config.Max < config.Min {
+		config.Max = config.Min
+	}
+
+	if config.HighWaterMark <= 0 {
+		config.HighWaterMark = 50
+	}
+
+	if config.LowWaterMark <= 0 {
+		config.LowWaterMark = 5
+	}
+
+	config.Initial = min(max(config.Initial, config.Min), config.Max)
+
+	m := &DynamicConcurrencyManager{
+		queue:  q,
+		config: config,
+		logger: logger.With().Str("component", "concurrency").Logger(),
+		tokens: make(chan struct{}, config.Max),
+		level:  config.Initial,
+	}
+
+	for i := 0; i < config.Initial; i++ {
+		m.tokens <- struct{}{}
+	}
+
+	return m
+}
+
+// MaxConcurrency returns the most jobs that may ever run at once
+func (m *DynamicConcurrencyManager) MaxConcurrency() int {
+	return m.config.Max
+}
+
+// Concurrency returns how many jobs may currently run at once
+func (m *DynamicConcurrencyManager) Concurrency() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.level
+}
+
+// QueueDepth returns the total number of queued jobs seen at the last check
+func (m *DynamicConcurrencyManager) QueueDepth() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.queueDepth
+}
+
+// Acquire blocks until a job slot is free or ctx is done, reporting whether a slot was taken.
+// A taken slot must be given back with Release.
+func (m *DynamicConcurrencyManager) Acquire(ctx context.Context) bool {
+	select {
+	case <-m.tokens:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Release gives back a job slot taken with Acquire
+func (m *DynamicConcurrencyManager) Release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.retiring > 0 {
+		m.retiring--
+		return
+	}
+
+	m.tokens <- struct{}{}
+}
+
+// Start checks queue depth and tunes concurrency until the context is cancelled
+func (m *DynamicConcurrencyManager) Start(ctx context.Context) {
+	if m.config.Max == m.config.Min {
+		m.logger.Info().Int("concurrency", m.config.Max).Msg("Concurrency tuning disabled")
+		return
+	}
+
+	m.logger.Info().
+		Int("min", m.config.Min).
+		Int("max", m.config.Max).
+		Int64("high_water_mark", m.config.HighWaterMark).
+		Int64("low_water_mark", m.config.LowWaterMark).
+		Msg("Starting concurrency tuning")
+
+	ticker := time.NewTicker(concurrencyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			depth, err := m.totalQueueDepth(ctx)
+			if errors.Is(err, queue.ErrQueueLengthUnsupported) {
+				m.logger.Info().Int("concurrency", m.Concurrency()).
+					Msg("Queue backend cannot report queue depth, concurrency tuning disabled")
+				return
+			}
+			if err != nil {
+				m.logger.Error().Err(err).Msg("Failed to check queue depth")
+				continue
+			}
+			m.tune(depth)
+		}
+	}
+}
+
+// totalQueueDepth sums the lengths of all job queues
+func (m *DynamicConcurrencyManager) totalQueueDepth(ctx context.Context) (int64, error) {
+	var total int64
+	for _, jobType := range jobTypes {
+		length, err := m.queue.GetQueueLength(ctx, jobType)
+		if err != nil {
+			return 0, err
+		}
+		total += length
+	}
+	return total, nil
+}
+
+// tune doubles concurrency while the queues are above the high water mark and lowers it by
+// one once they have stayed below the low water mark for lowDepthChecks checks
+func (m *DynamicConcurrencyManager) tune(depth int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueDepth = depth
+	previous := m.level
+
+	switch {
+	case depth > m.config.HighWaterMark:
+		m.lowChecks = 0
+		for target := min(m.level*2, m.config.Max); m.level < target; m.level++ {
+			m.addToken()
+		}
+	case depth < m.config.LowWaterMark:
+		m.lowChecks++
+		if m.lowChecks >= lowDepthChecks && m.level > m.config.Min {
+			m.lowChecks = 0
+			m.level--
+			m.removeToken()
+		}
+	default:
+		m.lowChecks = 0
+	}
+
+	if m.level != previous {
+		m.logger.Info().
+			Int64("queue_depth", depth).
+			Int("from", previous).
+			Int("to", m.level).
+			Msg("Adjusted worker concurrency")
+	}
+}
+
+// addToken adds a job slot, first cancelling a pending retirement if there is one. The
+// caller holds mu.
+func (m *DynamicConcurrencyManager) addToken() {
+	if m.retiring > 0 {
+		m.retiring--
+		return
+	}
+	m.tokens <- struct{}{}
+}
+
+// removeToken takes a free job slot away, or retires a held one when none is free. The
+// caller holds mu.
+func (m *DynamicConcurrencyManager) removeToken() {
+	select {
+	case <-m.tokens:
+	default:
+		m.retiring++
+	}
+}
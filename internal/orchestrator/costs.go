@@ -0,0 +1,89 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/costs"
+	"github.com/rs/zerolog"
+)
+
+// costRefreshInterval is how often projected infrastructure costs are recomputed. The
+// billing export is updated a few times a day, so refreshing more often gains little.
+const costRefreshInterval = 24 * time.Hour
+
+// CostCollector records the projected monthly cost of all READY infrastructure from the
+// billing export, so cost summaries need not query BigQuery per deployment
+type CostCollector struct {
+	engine  *Engine
+	tracker *costs.Tracker
+	logger  zerolog.Logger
+}
+
+// NewCostCollector creates a new daily cost collector
+func NewCostCollector(engine *Engine, tracker *costs.Tracker, logger zerolog.Logger) *CostCollector {
+	return &CostCollector{
+		engine:  engine,
+		tracker: tracker,
+		logger:  logger.With().Str("component", "cost-collector").Logger(),
+	}
+}
+
+// Start collects costs once, then daily until the context is cancelled
+func (c *CostCollector) Start(ctx context.Context) {
+	c.logger.Info().
+		Dur("interval", costRefreshInterval).
+		Msg("Starting cost collector")
+
+	if err := c.collect(ctx); err != nil {
+		c.logger.Error().Err(err).Msg("Failed to collect infrastructure costs")
+	}
+
+	ticker := time.NewTicker(costRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info().Msg("Cost collector stopped")
+			return
+		case <-ticker.C:
+			if err := c.collect(ctx); err != nil {
+				c.logger.Error().Err(err).Msg("Failed to collect infrastructure costs")
+			}
+		}
+	}
+}
+
+// collect updates the projected cost of every READY infrastructure record
+func (c *CostCollector) collect(ctx context.Context) error {
+	infras, err := c.engine.repo.ListInfrastructureByStatus(ctx, "READY")
+	if err != nil {
+		return fmt.Errorf("list ready infrastructure: %w", err)
+	}
+
+	for _, infra := range infras {
+		cost, err := c.tracker.DeploymentCost(ctx, infra.DeploymentID.String())
+		if err != nil {
+			c.logger.Warn().
+				Err(err).
+				Str("deployment_id", infra.DeploymentID.String()).
+				Msg("Failed to get incurred costs")
+			continue
+		}
+
+		if err := c.engine.repo.UpdateInfrastructureCost(ctx, infra.ID, cost.MonthlyEstimateUSD); err != nil {
+			c.logger.Warn().
+				Err(err).
+				Str("infrastructure_id", infra.ID.String()).
+				Msg("Failed to record infrastructure cost")
+		}
+	}
+
+	c.logger.Info().
+		Int("infrastructures", len(infras)).
+		Msg("Infrastructure costs collected")
+
+	return nil
+}
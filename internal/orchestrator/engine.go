@@ -5,26 +5,46 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/alvesdmateus/app-deployer/internal/builder"
 	"github.com/alvesdmateus/app-deployer/internal/deployer"
 	"github.com/alvesdmateus/app-deployer/internal/provisioner"
 	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/secrets"
 	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
+// cloudRunCloud is the deployment cloud that targets Cloud Run instead of a GKE cluster
+const cloudRunCloud = "cloudrun"
+
 // Engine orchestrates the deployment pipeline by coordinating queue, provisioner, and deployer
 type Engine struct {
-	queue       *queue.RedisQueue
-	repo        *state.Repository
-	provisioner provisioner.Provisioner
-	deployer    deployer.Deployer
-	logger      zerolog.Logger
+	queue             queue.Queue
+	repo              *state.Repository
+	provisioner       provisioner.Provisioner
+	deployer          deployer.Deployer
+	cloudRunDeployer  deployer.Deployer    // Optional, nil when Cloud Run is not enabled
+	kustomizeDeployer deployer.Deployer    // Optional, nil when kustomize is not installed
+	cosignKeyRef      string               // Key image signatures are verified against, empty skips verification
+	enforceSigned     bool                 // Fail deploys whose image signature does not verify
+	secretCipher      *secrets.Cipher      // Optional, decrypts secret environment variables
+	webhookURL        string               // Notifications are delivered here, empty drops them
+	webhookSecret     string               // Signs notification bodies, empty leaves them unsigned
+	buildService      builder.BuildService // Optional, nil when build jobs cannot run on this worker
+	useKaniko         bool                 // Build with Kaniko when running in Kubernetes
+	gcpProject        string               // Project of provisioned clusters' load balancers
+	defaultWAFPolicy  string               // Cloud Armor policy used for "default", empty when there is none
+	binaryAuth        bool                 // Provisioned clusters enforce Binary Authorization
+	binaryAuthPolicy  string               // Policy applied to the project before provisioning, empty keeps it
+	servicePerimeter  string               // VPC Service Controls perimeter clusters are put inside, empty when none
+	vpa               bool                 // Clusters run Vertical Pod Autoscaling and workloads get recommendations
+	logger            zerolog.Logger
 }
 
 // NewEngine creates a new orchestrator engine
 func NewEngine(
-	queue *queue.RedisQueue,
+	queue queue.Queue,
 	repo *state.Repository,
 	provisioner provisioner.Provisioner,
 	deployer deployer.Deployer,
@@ -39,6 +59,92 @@ func NewEngine(
 	}
 }
 
+// SetCloudRunDeployer enables Cloud Run deployments for deployments with cloud "cloudrun"
+func (e *Engine) SetCloudRunDeployer(d deployer.Deployer) {
+	e.cloudRunDeployer = d
+}
+
+// SetKustomizeDeployer enables deployments with deployer type "kustomize"
+func (e *Engine) SetKustomizeDeployer(d deployer.Deployer) {
+	e.kustomizeDeployer = d
+}
+
+// SetImageVerification verifies image signatures against keyRef before each deploy.
+// When enforce is true, deploys of images that fail verification are failed.
+func (e *Engine) SetImageVerification(keyRef string, enforce bool) {
+	e.cosignKeyRef = keyRef
+	e.enforceSigned = enforce
+}
+
+// SetSecretCipher sets the cipher secret deployment environment variables are decrypted with
+func (e *Engine) SetSecretCipher(c *secrets.Cipher) {
+	e.secretCipher = c
+}
+
+// SetBuildService enables build jobs, which build pushed commits and deploy the images.
+// When useKaniko is true, builds on a worker running in Kubernetes use Kaniko.
+func (e *Engine) SetBuildService(s builder.BuildService, useKaniko bool) {
+	e.buildService = s
+	e.useKaniko = useKaniko
+}
+
+// SetNotificationWebhook enables delivery of notification jobs to a webhook. Bodies are signed
+// with secret when it is set.
+func (e *Engine) SetNotificationWebhook(url, secret string) {
+	e.webhookURL = url
+	e.webhookSecret = secret
+}
+
+// SetCloudArmor lets deployments put their load balancer behind Cloud Armor. Load balancers of
+// provisioned clusters are in project; a deployment's "default" policy is defaultPolicy.
+func (e *Engine) SetCloudArmor(project, defaultPolicy string) {
+	e.gcpProject = project
+	e.defaultWAFPolicy = defaultPolicy
+}
+
+// SetBinaryAuthorization makes provisioned clusters enforce the project's Binary Authorization
+// policy. A non-empty policyYAML replaces that policy before each cluster is provisioned.
+func (e *Engine) SetBinaryAuthorization(policyYAML string) {
+	e.binaryAuth = true
+	e.binaryAuthPolicy = policyYAML
+}
+
+// SetServicePerimeter puts the service accounts of provisioned clusters inside a VPC Service
+// Controls perimeter
+func (e *Engine) SetServicePerimeter(perimeter string) {
+	e.servicePerimeter = perimeter
+}
+
+// SetVerticalPodAutoscaling enables Vertical Pod Autoscaling on provisioned clusters and
+// deploys each workload with a VPA that only recommends resources
+func (e *Engine) SetVerticalPodAutoscaling() {
+	e.vpa = true
+}
+
+// deployerFor returns the deployer responsible for a deployment's cloud and deployer type.
+// A nil deployment selects the default Helm deployer.
+func (e *Engine) deployerFor(deployment *state.Deployment) (deployer.Deployer, error) {
+	if deployment == nil {
+		return e.deployer, nil
+	}
+
+	if deployment.Cloud == cloudRunCloud {
+		if e.cloudRunDeployer == nil {
+			return nil, fmt.Errorf("cloud run deployments are not enabled")
+		}
+		return e.cloudRunDeployer, nil
+	}
+
+	if deployment.DeployerType == deployer.DeployerTypeKustomize {
+		if e.kustomizeDeployer == nil {
+			return nil, fmt.Errorf("kustomize deployments are not enabled")
+		}
+		return e.kustomizeDeployer, nil
+	}
+
+	return e.deployer, nil
+}
+
 // EnqueueProvisionJob enqueues a provision job to the queue
 func (e *Engine) EnqueueProvisionJob(ctx context.Context, payload *queue.ProvisionPayload) error {
 	e.logger.Info().
@@ -47,6 +153,25 @@ func (e *Engine) EnqueueProvisionJob(ctx context.Context, payload *queue.Provisi
 		Str("cloud", payload.Cloud).
 		Msg("Enqueueing provision job")
 
+	job := newProvisionJob(payload)
+	if err := e.queue.Enqueue(ctx, job); err != nil {
+		e.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue provision job")
+		return fmt.Errorf("enqueue provision job: %w", err)
+	}
+
+	e.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Provision job enqueued successfully")
+
+	return nil
+}
+
+// newProvisionJob creates a provision job for payload
+func newProvisionJob(payload *queue.ProvisionPayload) *queue.Job {
 	payloadMap := map[string]interface{}{
 		"deployment_id": payload.DeploymentID,
 		"app_name":      payload.AppName,
@@ -55,40 +180,47 @@ func (e *Engine) EnqueueProvisionJob(ctx context.Context, payload *queue.Provisi
 		"region":        payload.Region,
 		"image_tag":     payload.ImageTag,
 		"build_id":      payload.BuildID,
+		"replicas":      payload.Replicas,
+		"autoscaling":   payload.Autoscaling,
+		"addons":        payload.Addons,
 	}
 
-	job := &queue.Job{
+	return &queue.Job{
 		ID:           uuid.New().String(),
 		Type:         queue.JobTypeProvision,
 		DeploymentID: payload.DeploymentID,
 		Payload:      payloadMap,
 		MaxAttempts:  3,
 	}
+}
 
+// EnqueueDeployJob enqueues a deploy job to the queue
+func (e *Engine) EnqueueDeployJob(ctx context.Context, payload *queue.DeployPayload) error {
+	e.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("infrastructure_id", payload.InfrastructureID).
+		Str("image_tag", payload.ImageTag).
+		Msg("Enqueueing deploy job")
+
+	job := newDeployJob(payload)
 	if err := e.queue.Enqueue(ctx, job); err != nil {
 		e.logger.Error().
 			Err(err).
 			Str("deployment_id", payload.DeploymentID).
-			Msg("Failed to enqueue provision job")
-		return fmt.Errorf("enqueue provision job: %w", err)
+			Msg("Failed to enqueue deploy job")
+		return fmt.Errorf("enqueue deploy job: %w", err)
 	}
 
 	e.logger.Info().
 		Str("job_id", job.ID).
 		Str("deployment_id", payload.DeploymentID).
-		Msg("Provision job enqueued successfully")
+		Msg("Deploy job enqueued successfully")
 
 	return nil
 }
 
-// EnqueueDeployJob enqueues a deploy job to the queue
-func (e *Engine) EnqueueDeployJob(ctx context.Context, payload *queue.DeployPayload) error {
-	e.logger.Info().
-		Str("deployment_id", payload.DeploymentID).
-		Str("infrastructure_id", payload.InfrastructureID).
-		Str("image_tag", payload.ImageTag).
-		Msg("Enqueueing deploy job")
-
+// newDeployJob creates a deploy job for payload
+func newDeployJob(payload *queue.DeployPayload) *queue.Job {
 	payloadMap := map[string]interface{}{
 		"deployment_id":     payload.DeploymentID,
 		"infrastructure_id": payload.InfrastructureID,
@@ -97,30 +229,49 @@ func (e *Engine) EnqueueDeployJob(ctx context.Context, payload *queue.DeployPayl
 		"replicas":          payload.Replicas,
 	}
 
-	job := &queue.Job{
+	return &queue.Job{
 		ID:           uuid.New().String(),
 		Type:         queue.JobTypeDeploy,
 		DeploymentID: payload.DeploymentID,
 		Payload:      payloadMap,
 		MaxAttempts:  3,
 	}
+}
 
+// EnqueueNotifyJob enqueues a webhook notification of a platform event
+func (e *Engine) EnqueueNotifyJob(ctx context.Context, payload *queue.NotifyPayload) error {
+	job := newNotifyJob(payload)
 	if err := e.queue.Enqueue(ctx, job); err != nil {
-		e.logger.Error().
-			Err(err).
-			Str("deployment_id", payload.DeploymentID).
-			Msg("Failed to enqueue deploy job")
-		return fmt.Errorf("enqueue deploy job: %w", err)
+		return fmt.Errorf("enqueue notify job: %w", err)
 	}
 
 	e.logger.Info().
 		Str("job_id", job.ID).
 		Str("deployment_id", payload.DeploymentID).
-		Msg("Deploy job enqueued successfully")
+		Str("event_type", payload.EventType).
+		Msg("Notification job enqueued successfully")
 
 	return nil
 }
 
+// newNotifyJob creates a notify job for payload
+func newNotifyJob(payload *queue.NotifyPayload) *queue.Job {
+	payloadMap := map[string]interface{}{
+		"event_type":    payload.EventType,
+		"deployment_id": payload.DeploymentID,
+		"message":       payload.Message,
+		"data":          payload.Data,
+	}
+
+	return &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeNotify,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+}
+
 // EnqueueDestroyJob enqueues a destroy job to the queue
 func (e *Engine) EnqueueDestroyJob(ctx context.Context, payload *queue.DestroyPayload) error {
 	e.logger.Info().
@@ -131,6 +282,7 @@ func (e *Engine) EnqueueDestroyJob(ctx context.Context, payload *queue.DestroyPa
 	payloadMap := map[string]interface{}{
 		"deployment_id":     payload.DeploymentID,
 		"infrastructure_id": payload.InfrastructureID,
+		"delete_volumes":    payload.DeleteVolumes,
 	}
 
 	job := &queue.Job{
@@ -194,6 +346,42 @@ func (e *Engine) EnqueueRollbackJob(ctx context.Context, payload *queue.Rollback
 	return nil
 }
 
+// EnqueueReconcileJob enqueues a reconcile job to the queue
+func (e *Engine) EnqueueReconcileJob(ctx context.Context, payload *queue.ReconcilePayload) error {
+	e.logger.Info().
+		Str("deployment_id", payload.DeploymentID).
+		Str("infrastructure_id", payload.InfrastructureID).
+		Msg("Enqueueing reconcile job")
+
+	payloadMap := map[string]interface{}{
+		"deployment_id":     payload.DeploymentID,
+		"infrastructure_id": payload.InfrastructureID,
+	}
+
+	job := &queue.Job{
+		ID:           uuid.New().String(),
+		Type:         queue.JobTypeReconcile,
+		DeploymentID: payload.DeploymentID,
+		Payload:      payloadMap,
+		MaxAttempts:  3,
+	}
+
+	if err := e.queue.Enqueue(ctx, job); err != nil {
+		e.logger.Error().
+			Err(err).
+			Str("deployment_id", payload.DeploymentID).
+			Msg("Failed to enqueue reconcile job")
+		return fmt.Errorf("enqueue reconcile job: %w", err)
+	}
+
+	e.logger.Info().
+		Str("job_id", job.ID).
+		Str("deployment_id", payload.DeploymentID).
+		Msg("Reconcile job enqueued successfully")
+
+	return nil
+}
+
 // parseProvisionPayload parses a provision job payload
 func parseProvisionPayload(job *queue.Job) (*queue.ProvisionPayload, error) {
 	data, err := json.Marshal(job.Payload)
@@ -253,3 +441,108 @@ func parseRollbackPayload(job *queue.Job) (*queue.RollbackPayload, error) {
 
 	return &payload, nil
 }
+
+// parseReconcilePayload parses a reconcile job payload
+func parseReconcilePayload(job *queue.Job) (*queue.ReconcilePayload, error) {
+	data, err := json.Marshal(job.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var payload queue.ReconcilePayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// parseUpdateInfraPayload parses an infrastructure update job payload
+func parseUpdateInfraPayload(job *queue.Job) (*queue.UpdateInfraPayload, error) {
+	data, err := json.Marshal(job.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var payload queue.UpdateInfraPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// parseMigrateBackendPayload parses a state backend migration job payload
+func parseMigrateBackendPayload(job *queue.Job) (*queue.MigrateBackendPayload, error) {
+	data, err := json.Marshal(job.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var payload queue.MigrateBackendPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// parseDestroyStackPayload parses an orphaned stack destroy job payload
+func parseDestroyStackPayload(job *queue.Job) (*queue.DestroyStackPayload, error) {
+	data, err := json.Marshal(job.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var payload queue.DestroyStackPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// parseNotifyPayload parses a webhook notification job payload
+func parseNotifyPayload(job *queue.Job) (*queue.NotifyPayload, error) {
+	data, err := json.Marshal(job.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var payload queue.NotifyPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// parseBuildPayload parses a build job payload
+func parseBuildPayload(job *queue.Job) (*queue.BuildPayload, error) {
+	data, err := json.Marshal(job.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var payload queue.BuildPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// parseRestartPayload parses a rolling restart job payload
+func parseRestartPayload(job *queue.Job) (*queue.RestartPayload, error) {
+	data, err := json.Marshal(job.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var payload queue.RestartPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return &payload, nil
+}
@@ -0,0 +1,38 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// deploymentEnv builds the environment of a deploy: the addon connection details overlaid
+// with the environment variables stored for the deployment, secrets decrypted.
+func (w *Worker) deploymentEnv(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure) (map[string]string, error) {
+	env := addonEnv(infra)
+
+	envVars, err := w.engine.repo.ListDeploymentEnvVars(ctx, deployment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list env vars: %w", err)
+	}
+
+	for _, envVar := range envVars {
+		if !envVar.IsSecret {
+			env[envVar.Key] = envVar.Value
+			continue
+		}
+
+		if w.engine.secretCipher == nil {
+			return nil, fmt.Errorf("decrypt env var %s: secrets encryption key not configured", envVar.Key)
+		}
+
+		value, err := w.engine.secretCipher.Decrypt(envVar.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt env var %s: %w", envVar.Key, err)
+		}
+		env[envVar.Key] = value
+	}
+
+	return env, nil
+}
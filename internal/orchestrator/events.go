@@ -0,0 +1,135 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/google/uuid"
+)
+
+// recordEvent adds an entry to a deployment's event feed. Failures are logged rather than
+// returned since the feed is informational.
+func (w *Worker) recordEvent(ctx context.Context, deploymentID uuid.UUID, eventType, message string, metadata map[string]interface{}) {
+	event := &state.DeploymentEvent{
+		DeploymentID: deploymentID,
+		Type:         eventType,
+		Message:      message,
+		Metadata:     metadata,
+	}
+
+	if err := w.engine.repo.CreateDeploymentEvent(ctx, event); err != nil {
+		w.logger.Warn().
+			Err(err).
+			Str("deployment_id", deploymentID.String()).
+			Str("type", eventType).
+			Msg("Failed to record deployment event")
+	}
+}
+
+// recordStatusChange adds the deployment's current status to its event feed
+func (w *Worker) recordStatusChange(ctx context.Context, deployment *state.Deployment) {
+	metadata := map[string]interface{}{
+		"status": deployment.Status,
+	}
+	if deployment.Error != "" {
+		metadata["error"] = deployment.Error
+	}
+
+	w.recordEvent(ctx, deployment.ID, state.EventTypeStatusChange,
+		fmt.Sprintf("Deployment is %s", deployment.Status), metadata)
+}
+
+// recordHelmRevision adds the release's latest Helm revision to the event feed after a
+// deploy or rollback. Deployments not managed by Helm have no revisions to record.
+func (w *Worker) recordHelmRevision(ctx context.Context, dep deployer.Deployer, deploymentID uuid.UUID, infraID uuid.UUID) {
+	helm, ok := dep.(*deployer.HelmDeployer)
+	if !ok {
+		return
+	}
+
+	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	if err != nil || infra.HelmReleaseName == "" {
+		return
+	}
+
+	revisions, err := helm.History(ctx, infra)
+	if err != nil || len(revisions) == 0 {
+		w.logger.Warn().
+			Err(err).
+			Str("deployment_id", deploymentID.String()).
+			Msg("Failed to get Helm revision for event feed")
+		return
+	}
+
+	latest := revisions[len(revisions)-1]
+	w.recordEvent(ctx, deploymentID, state.EventTypeHelmRevision,
+		fmt.Sprintf("Helm release %s is at revision %d", infra.HelmReleaseName, latest.Revision),
+		map[string]interface{}{
+			"release":     infra.HelmReleaseName,
+			"revision":    latest.Revision,
+			"chart":       latest.Chart,
+			"app_version": latest.AppVersion,
+			"description": latest.Description,
+		})
+}
+
+// recordHPAScaling compares the autoscaler's replica count with the last one in the event
+// feed and records a scaling event when it changed. It runs on every reconcile, so scaling
+// is seen at the reconcile interval rather than the moment it happens.
+func (w *Worker) recordHPAScaling(ctx context.Context, infra *state.Infrastructure) {
+	if infra.HPAConfig == nil || infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		return
+	}
+
+	logger := w.logger.With().
+		Str("deployment_id", infra.DeploymentID.String()).
+		Logger()
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to create Kubernetes client for HPA check")
+		return
+	}
+
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", infra.HelmReleaseName)
+	status, found, err := kubeClient.GetHorizontalPodAutoscaler(ctx, infra.KubeNamespace, labelSelector)
+	if err != nil || !found {
+		if err != nil {
+			logger.Warn().Err(err).Msg("Failed to get horizontal pod autoscaler")
+		}
+		return
+	}
+
+	previous := -1
+	if last, err := w.engine.repo.GetLatestDeploymentEvent(ctx, infra.DeploymentID, state.EventTypeHPAScaling); err == nil {
+		// Metadata numbers come back from JSON as float64
+		if replicas, ok := last.Metadata["current_replicas"].(float64); ok {
+			previous = int(replicas)
+		}
+	}
+
+	current := int(status.CurrentReplicas)
+	if current == previous {
+		return
+	}
+
+	message := fmt.Sprintf("Autoscaler %s is at %d replicas", status.Name, current)
+	if previous >= 0 {
+		message = fmt.Sprintf("Autoscaler %s scaled from %d to %d replicas", status.Name, previous, current)
+	}
+
+	metadata := map[string]interface{}{
+		"hpa":              status.Name,
+		"current_replicas": current,
+		"desired_replicas": status.DesiredReplicas,
+		"min_replicas":     status.MinReplicas,
+		"max_replicas":     status.MaxReplicas,
+	}
+	if previous >= 0 {
+		metadata["previous_replicas"] = previous
+	}
+
+	w.recordEvent(ctx, infra.DeploymentID, state.EventTypeHPAScaling, message, metadata)
+}
@@ -0,0 +1,149 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// Outcomes of a GitOps release check, stored in Infrastructure.LastReconcileStatus
+const (
+	reconcileStatusInSync      = "in_sync"
+	reconcileStatusRedeploying = "redeploying"
+	reconcileStatusSkipped     = "skipped"
+	reconcileStatusError       = "error"
+)
+
+// reconcileRelease compares a GitOps deployment's live Helm release with the values it was
+// deployed with and enqueues a deploy job to restore them when they differ, e.g. after a
+// manual helm upgrade. The outcome is stored on the infrastructure record.
+func (w *Worker) reconcileRelease(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure) error {
+	if !deployment.ReconciliationMode {
+		return nil
+	}
+
+	logger := w.logger.With().
+		Str("deployment_id", deployment.ID.String()).
+		Str("release", infra.HelmReleaseName).
+		Logger()
+
+	status, drift, err := w.checkRelease(ctx, deployment, infra)
+	if err != nil {
+		logger.Error().Err(err).Msg("GitOps release check failed")
+		status = reconcileStatusError
+	}
+
+	if status == reconcileStatusRedeploying {
+		logger.Warn().
+			Strs("drifted_values", drift).
+			Msg("Helm release drifted, redeploying desired state")
+
+		if err := w.engine.repo.CreateDeploymentLog(ctx, &state.DeploymentLog{
+			DeploymentID: deployment.ID,
+			Phase:        deployment.Status,
+			Level:        "WARNING",
+			Source:       "gitops",
+			Message:      fmt.Sprintf("Helm release drifted (%s), redeploying", strings.Join(drift, ", ")),
+		}); err != nil {
+			logger.Warn().Err(err).Msg("Failed to record release drift")
+		}
+
+		if err := w.redeploy(ctx, deployment, infra); err != nil {
+			status = reconcileStatusError
+			logger.Error().Err(err).Msg("Failed to enqueue GitOps redeploy")
+		}
+	}
+
+	now := time.Now()
+	infra.LastReconciledAt = &now
+	infra.LastReconcileStatus = status
+	if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		return fmt.Errorf("update infrastructure: %w", err)
+	}
+
+	return nil
+}
+
+// checkRelease returns reconcileStatusRedeploying and the drifted value paths when the live
+// release differs from the recorded values, or the reason no comparison was made
+func (w *Worker) checkRelease(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure) (string, []string, error) {
+	// Only settled deployments are compared; a rollout in progress is not drift
+	switch deployment.Status {
+	case "EXPOSED", "HEALTHY", "UNHEALTHY":
+	default:
+		return reconcileStatusSkipped, nil, nil
+	}
+
+	if deployment.Paused || infra.HelmReleaseName == "" || infra.HelmValues == "" {
+		return reconcileStatusSkipped, nil, nil
+	}
+
+	dep, err := w.engine.deployerFor(deployment)
+	if err != nil {
+		return "", nil, fmt.Errorf("select deployer: %w", err)
+	}
+
+	helm, ok := dep.(*deployer.HelmDeployer)
+	if !ok {
+		return reconcileStatusSkipped, nil, nil
+	}
+
+	var desired map[string]interface{}
+	if err := json.Unmarshal([]byte(infra.HelmValues), &desired); err != nil {
+		return "", nil, fmt.Errorf("parse recorded helm values: %w", err)
+	}
+
+	live, err := helm.GetValues(ctx, infra)
+	if err != nil {
+		return "", nil, fmt.Errorf("get helm values: %w", err)
+	}
+
+	drift, err := deployer.ValuesDrift(desired, live)
+	if err != nil {
+		return "", nil, fmt.Errorf("compare helm values: %w", err)
+	}
+
+	// A failed or interrupted manual upgrade leaves the release in a state worth restoring too
+	revisions, err := helm.History(ctx, infra)
+	if err != nil {
+		return "", nil, fmt.Errorf("get helm history: %w", err)
+	}
+	if n := len(revisions); n > 0 && revisions[n-1].Status != "deployed" {
+		drift = append(drift, fmt.Sprintf("release status %s", revisions[n-1].Status))
+	}
+
+	if len(drift) == 0 {
+		return reconcileStatusInSync, nil, nil
+	}
+
+	return reconcileStatusRedeploying, drift, nil
+}
+
+// redeploy enqueues a deploy job with the deployment's current image and settings
+func (w *Worker) redeploy(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure) error {
+	return w.engine.EnqueueDeployJob(ctx, &queue.DeployPayload{
+		DeploymentID:     deployment.ID.String(),
+		InfrastructureID: infra.ID.String(),
+		ImageTag:         deployment.ImageTag,
+		Port:             deployment.Port,
+		Replicas:         deployedReplicas(infra),
+	})
+}
+
+// deployedReplicas returns the replica count the release was last deployed with, or 0 for the
+// default when it has not been deployed
+func deployedReplicas(infra *state.Infrastructure) int {
+	var values struct {
+		ReplicaCount int `json:"replicaCount"`
+	}
+	if err := json.Unmarshal([]byte(infra.HelmValues), &values); err != nil {
+		return 0
+	}
+	return values.ReplicaCount
+}
@@ -2,14 +2,28 @@ package orchestrator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/alvesdmateus/app-deployer/internal/builder/signing"
 	"github.com/alvesdmateus/app-deployer/internal/deployer"
 	"github.com/alvesdmateus/app-deployer/internal/provisioner"
 	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/google/uuid"
 )
 
+const (
+	// dependencyWaitDelay is how long a provision job waits before its dependencies are checked again
+	dependencyWaitDelay = 30 * time.Second
+
+	// maxDependencyWaits is how many times a provision job waits before the deployment fails
+	maxDependencyWaits = 10
+)
+
 // handleProvisionJob handles infrastructure provisioning jobs
 func (w *Worker) handleProvisionJob(ctx context.Context, job *queue.Job) error {
 	logger := w.logger.With().
@@ -36,6 +50,36 @@ func (w *Worker) handleProvisionJob(ctx context.Context, job *queue.Job) error {
 		return fmt.Errorf("get deployment: %w", err)
 	}
 
+	// Provisioning waits until every deployment this one depends on is live
+	ready, err := w.dependenciesReady(ctx, job, deployment)
+	if err != nil || !ready {
+		return err
+	}
+
+	// Record the image and provision settings so a stuck pipeline can be resumed by the watchdog
+	provisionPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal provision payload: %w", err)
+	}
+	if deployment.ImageTag != payload.ImageTag || deployment.ProvisionPayload != string(provisionPayload) {
+		deployment.ImageTag = payload.ImageTag
+		deployment.ProvisionPayload = string(provisionPayload)
+		if err := w.engine.repo.UpdateDeployment(ctx, deployment); err != nil {
+			return fmt.Errorf("update deployment: %w", err)
+		}
+	}
+
+	// Cloud Run needs no cluster, go straight to the deploy step
+	if payload.Cloud == cloudRunCloud {
+		return w.prepareCloudRunDeploy(ctx, job, payload, deployment)
+	}
+
+	// Imported clusters already exist, so they are deployed to without provisioning
+	if infra, err := w.engine.repo.GetInfrastructure(ctx, deployment.ID); err == nil &&
+		infra.ImportedExternally && infra.Status == "READY" {
+		return w.prepareImportedDeploy(ctx, job, payload, deployment, infra)
+	}
+
 	logger.Info().
 		Str("app_name", payload.AppName).
 		Str("cloud", payload.Cloud).
@@ -60,9 +104,39 @@ func (w *Worker) handleProvisionJob(ctx context.Context, job *queue.Job) error {
 		Cloud:        payload.Cloud,
 		Region:       payload.Region,
 		Config: &provisioner.ProvisionConfig{
-			NodeCount:   nodeCount,
-			MachineType: machineType,
+			NodeCount:       nodeCount,
+			MachineType:     machineType,
+			Labels:          deployment.Tags,
+			SubnetCIDRBlock: payload.SubnetCIDRBlock,
 		},
+		Addons: payload.Addons,
+	}
+
+	if payload.Autoscaling != nil {
+		provisionReq.Config.EnableAutoscaling = true
+		provisionReq.Config.Autoscaling = *payload.Autoscaling
+	}
+
+	if payload.DedicatedNodePool {
+		provisionReq.Config.DedicatedNodePool = true
+		provisionReq.Config.DedicatedWorkload = payload.DedicatedWorkload
+	}
+
+	if w.engine.binaryAuth {
+		provisionReq.Config.BinaryAuth = &provisioner.BinaryAuthConfig{
+			Enable: true,
+			Policy: w.engine.binaryAuthPolicy,
+		}
+	}
+
+	if w.engine.vpa {
+		provisionReq.Config.EnableVPA = true
+	}
+
+	if w.engine.servicePerimeter != "" {
+		provisionReq.Config.VPCServiceControls = &provisioner.VPCServiceControlsConfig{
+			ServicePerimeterName: w.engine.servicePerimeter,
+		}
 	}
 
 	// Provision infrastructure
@@ -79,6 +153,8 @@ func (w *Worker) handleProvisionJob(ctx context.Context, job *queue.Job) error {
 			logger.Error().
 				Err(updateErr).
 				Msg("Failed to update deployment status")
+		} else {
+			w.recordStatusChange(ctx, deployment)
 		}
 
 		return fmt.Errorf("provision infrastructure: %w", err)
@@ -105,17 +181,129 @@ func (w *Worker) handleProvisionJob(ctx context.Context, job *queue.Job) error {
 		Replicas:         replicas,
 	}
 
-	if err := w.engine.EnqueueDeployJob(ctx, deployPayload); err != nil {
+	if err := w.engine.AdvanceStage(ctx, job, newDeployJob(deployPayload)); err != nil {
 		logger.Error().
 			Err(err).
 			Msg("Failed to enqueue deploy job")
-		return fmt.Errorf("enqueue deploy job: %w", err)
+		return err
 	}
 
 	logger.Info().Msg("Deploy job enqueued, provision job complete")
 	return nil
 }
 
+// prepareCloudRunDeploy records a Cloud Run target for the deployment and enqueues its deploy job,
+// skipping cluster provisioning entirely
+func (w *Worker) prepareCloudRunDeploy(ctx context.Context, job *queue.Job, payload *queue.ProvisionPayload, deployment *state.Deployment) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	if _, err := w.engine.deployerFor(deployment); err != nil {
+		deployment.Status = "FAILED"
+		deployment.Error = err.Error()
+		if updateErr := w.engine.repo.UpdateDeployment(ctx, deployment); updateErr != nil {
+			logger.Error().
+				Err(updateErr).
+				Msg("Failed to update deployment status")
+		} else {
+			w.recordStatusChange(ctx, deployment)
+		}
+		return fmt.Errorf("select deployer: %w", err)
+	}
+
+	if len(payload.Addons) > 0 {
+		logger.Warn().
+			Int("addons", len(payload.Addons)).
+			Msg("Addons are not supported on Cloud Run, ignoring")
+	}
+
+	// Reuse the existing Cloud Run record on redeploys so the same service is updated
+	infra, err := w.engine.repo.GetInfrastructure(ctx, deployment.ID)
+	if err != nil || infra.Status == "DESTROYED" {
+		infra = &state.Infrastructure{
+			DeploymentID:    deployment.ID,
+			ServiceName:     fmt.Sprintf("app-%s", deployment.ID.String()[:8]),
+			Status:          "READY",
+			Config:          `{"type":"cloudrun"}`,
+			ClusterLocation: payload.Region,
+		}
+
+		if err := w.engine.repo.CreateInfrastructure(ctx, infra); err != nil {
+			return fmt.Errorf("create cloud run infrastructure: %w", err)
+		}
+	}
+
+	if err := w.engine.repo.SetDeploymentInfrastructure(ctx, deployment.ID, infra.ID); err != nil {
+		return fmt.Errorf("link infrastructure: %w", err)
+	}
+
+	logger.Info().
+		Str("infrastructure_id", infra.ID.String()).
+		Str("service_name", infra.ServiceName).
+		Str("region", infra.ClusterLocation).
+		Msg("Cloud Run deployment skips provisioning")
+
+	deployPayload := &queue.DeployPayload{
+		DeploymentID:     payload.DeploymentID,
+		InfrastructureID: infra.ID.String(),
+		ImageTag:         payload.ImageTag,
+		Port:             deployment.Port,
+		Replicas:         payload.Replicas,
+	}
+
+	if err := w.engine.AdvanceStage(ctx, job, newDeployJob(deployPayload)); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to enqueue deploy job")
+		return err
+	}
+
+	return nil
+}
+
+// prepareImportedDeploy enqueues the deploy job for a deployment targeting an imported cluster
+func (w *Worker) prepareImportedDeploy(ctx context.Context, job *queue.Job, payload *queue.ProvisionPayload, deployment *state.Deployment, infra *state.Infrastructure) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	if len(payload.Addons) > 0 {
+		logger.Warn().
+			Int("addons", len(payload.Addons)).
+			Msg("Addons are not provisioned on imported clusters, ignoring")
+	}
+
+	logger.Info().
+		Str("infrastructure_id", infra.ID.String()).
+		Str("cluster_name", infra.ClusterName).
+		Msg("Imported cluster skips provisioning")
+
+	replicas := payload.Replicas
+	if replicas == 0 {
+		replicas = 2
+	}
+
+	deployPayload := &queue.DeployPayload{
+		DeploymentID:     payload.DeploymentID,
+		InfrastructureID: infra.ID.String(),
+		ImageTag:         payload.ImageTag,
+		Port:             deployment.Port,
+		Replicas:         replicas,
+	}
+
+	if err := w.engine.AdvanceStage(ctx, job, newDeployJob(deployPayload)); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to enqueue deploy job")
+		return err
+	}
+
+	return nil
+}
+
 // handleDeployJob handles Kubernetes deployment jobs
 func (w *Worker) handleDeployJob(ctx context.Context, job *queue.Job) error {
 	logger := w.logger.With().
@@ -142,6 +330,39 @@ func (w *Worker) handleDeployJob(ctx context.Context, job *queue.Job) error {
 		return fmt.Errorf("get deployment: %w", err)
 	}
 
+	if err := w.verifyImage(ctx, deployment, payload.ImageTag); err != nil {
+		return err
+	}
+
+	// Get infrastructure to wire addon connection details into the app
+	infraID, err := uuid.Parse(payload.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("parse infrastructure ID: %w", err)
+	}
+
+	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("get infrastructure: %w", err)
+	}
+
+	env, err := w.deploymentEnv(ctx, deployment, infra)
+	if err != nil {
+		return err
+	}
+
+	storedConfigMaps, err := w.engine.repo.ListDeploymentConfigMaps(ctx, deployment.ID)
+	if err != nil {
+		return fmt.Errorf("list configmaps: %w", err)
+	}
+	configMaps := make([]deployer.ConfigMapMount, 0, len(storedConfigMaps))
+	for _, cm := range storedConfigMaps {
+		configMaps = append(configMaps, deployer.ConfigMapMount{
+			Name:      cm.Name,
+			MountPath: cm.MountPath,
+			Data:      cm.Data,
+		})
+	}
+
 	logger.Info().
 		Str("infrastructure_id", payload.InfrastructureID).
 		Str("image_tag", payload.ImageTag).
@@ -156,10 +377,177 @@ func (w *Worker) handleDeployJob(ctx context.Context, job *queue.Job) error {
 		ImageTag:         payload.ImageTag,
 		Port:             payload.Port,
 		Replicas:         payload.Replicas,
+		Env:              env,
+		ConfigMaps:       configMaps,
+		DeployerType:     deployment.DeployerType,
+		DeploymentType:   deployment.DeploymentType,
+		RepoURL:          deployment.RepoURL,
+		KustomizePath:    deployment.KustomizePath,
+		CPULimit:         deployment.CPULimit,
+		MemoryLimit:      deployment.MemoryLimit,
+		CPURequest:       deployment.CPURequest,
+		MemoryRequest:    deployment.MemoryRequest,
+	}
+
+	switch deployment.DeploymentType {
+	case deployer.DeploymentTypeCronJob:
+		deployReq.Config = &deployer.DeployConfig{
+			CronJob: &deployer.CronJobConfig{
+				Schedule:                deployment.Schedule,
+				Concurrency:             deployment.ConcurrencyPolicy,
+				StartingDeadlineSeconds: deployment.StartingDeadlineSeconds,
+			},
+		}
+	case deployer.DeploymentTypeStatefulSet:
+		deployReq.Config = &deployer.DeployConfig{
+			StorageClass:     deployment.StorageClass,
+			StorageSize:      deployment.StorageSize,
+			StorageMountPath: deployment.StorageMountPath,
+		}
+	}
+
+	if deployment.Hooks != "" {
+		if err := json.Unmarshal([]byte(deployment.Hooks), &deployReq.Hooks); err != nil {
+			return fmt.Errorf("parse deployment hooks: %w", err)
+		}
 	}
 
-	// Deploy to Kubernetes
-	result, err := w.engine.deployer.Deploy(ctx, deployReq)
+	if deployment.SmokeTests != "" {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		if err := json.Unmarshal([]byte(deployment.SmokeTests), &deployReq.Config.SmokeTests); err != nil {
+			return fmt.Errorf("parse smoke tests: %w", err)
+		}
+	}
+
+	if len(deployment.Tags) > 0 {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.Labels = deployment.Tags
+	}
+
+	if deployment.WorkloadIdentity && deployment.Cloud != cloudRunCloud {
+		identity, err := w.bindWorkloadIdentity(ctx, deployment, infra)
+		if err != nil {
+			deployment.Status = "FAILED"
+			deployment.Error = err.Error()
+			if updateErr := w.engine.repo.UpdateDeployment(ctx, deployment); updateErr != nil {
+				logger.Error().
+					Err(updateErr).
+					Msg("Failed to update deployment status")
+			} else {
+				w.recordStatusChange(ctx, deployment)
+			}
+			return err
+		}
+
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.WorkloadIdentity = identity
+	}
+
+	if deployment.CloudArmorEnabled {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.WAF = &deployer.WAFConfig{
+			EnableCloudArmor: true,
+			SecurityPolicy:   deployment.CloudArmorPolicy,
+		}
+
+		if deployment.RateLimitRequestsPerMinute > 0 {
+			deployReq.Config.RateLimit = &deployer.RateLimitConfig{
+				RequestsPerMinutePerIP: deployment.RateLimitRequestsPerMinute,
+				EnforceOnURIPaths:      deployment.RateLimitPaths,
+			}
+		}
+	}
+
+	if deployment.MirrorFrom != nil {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.Mirror = &deployer.MirrorConfig{MirrorFrom: deployment.MirrorFrom.String()}
+	}
+
+	abTest, err := w.engine.repo.GetVariantABTest(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("get A/B test: %w", err)
+	}
+	if abTest != nil && abTest.Status == state.ABTestStatusRunning {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.ABTest = &deployer.ABTestConfig{
+			BaselineDeploymentID: abTest.BaselineDeploymentID.String(),
+			HeaderName:           abTest.HeaderName,
+			HeaderValue:          abTest.HeaderValue,
+			RoutingWeight:        abTest.RoutingWeight,
+		}
+	}
+
+	var affinity *deployer.NodeAffinity
+	if deployment.NodeAffinity != "" {
+		if err := json.Unmarshal([]byte(deployment.NodeAffinity), &affinity); err != nil {
+			return fmt.Errorf("parse node affinity: %w", err)
+		}
+	}
+	if infra.DedicatedWorkload != "" {
+		affinity = affinity.WithDedicatedPool(provisioner.DedicatedPoolLabel, infra.DedicatedWorkload)
+	}
+	if affinity != nil {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.NodeAffinity = affinity
+	}
+
+	if deployment.Sidecars != "" {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		if err := json.Unmarshal([]byte(deployment.Sidecars), &deployReq.Config.Sidecars); err != nil {
+			return fmt.Errorf("parse sidecars: %w", err)
+		}
+	}
+
+	if deployment.InitContainers != "" {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		if err := json.Unmarshal([]byte(deployment.InitContainers), &deployReq.Config.InitContainers); err != nil {
+			return fmt.Errorf("parse init containers: %w", err)
+		}
+	}
+
+	if deployment.PDBEnabled {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.EnablePDB = true
+		deployReq.Config.PDB = &deployer.PDBConfig{
+			MinAvailable:   deployment.PDBMinAvailable,
+			MaxUnavailable: deployment.PDBMaxUnavailable,
+		}
+	}
+
+	if w.engine.vpa && deployment.Cloud != cloudRunCloud {
+		if deployReq.Config == nil {
+			deployReq.Config = &deployer.DeployConfig{}
+		}
+		deployReq.Config.EnableVPA = true
+	}
+
+	dep, err := w.engine.deployerFor(deployment)
+	if err != nil {
+		return fmt.Errorf("select deployer: %w", err)
+	}
+
+	// Deploy to Kubernetes (or Cloud Run)
+	result, err := dep.Deploy(ctx, deployReq)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -172,6 +560,8 @@ func (w *Worker) handleDeployJob(ctx context.Context, job *queue.Job) error {
 			logger.Error().
 				Err(updateErr).
 				Msg("Failed to update deployment status")
+		} else {
+			w.recordStatusChange(ctx, deployment)
 		}
 
 		return fmt.Errorf("deploy to kubernetes: %w", err)
@@ -185,7 +575,10 @@ func (w *Worker) handleDeployJob(ctx context.Context, job *queue.Job) error {
 
 	// Update deployment status to EXPOSED with external URL
 	deployment.Status = "EXPOSED"
-	deployment.ExternalURL = fmt.Sprintf("http://%s:%d", result.ExternalIP, payload.Port)
+	deployment.ExternalURL = result.ExternalURL
+	if result.ExternalIP != "" {
+		deployment.ExternalURL = fmt.Sprintf("http://%s:%d", result.ExternalIP, payload.Port)
+	}
 	deployment.Error = ""
 
 	if err := w.engine.repo.UpdateDeployment(ctx, deployment); err != nil {
@@ -195,102 +588,358 @@ func (w *Worker) handleDeployJob(ctx context.Context, job *queue.Job) error {
 		return fmt.Errorf("update deployment: %w", err)
 	}
 
+	w.recordStatusChange(ctx, deployment)
+	w.recordHelmRevision(ctx, dep, deployment.ID, infraID)
+
+	// Only the Helm chart exposes apps through a backend service based LoadBalancer
+	if deployment.CloudArmorEnabled && result.ExternalIP != "" && dep == w.engine.deployer {
+		w.attachWAF(ctx, deployment, infra, result, deployReq.Config.RateLimit)
+	}
+
+	// The mirror is only started once the new version is up, so no traffic is shadowed to it
+	// while it rolls out
+	if deployReq.Config != nil && deployReq.Config.Mirror != nil && dep == w.engine.deployer {
+		w.startMirror(ctx, deployment, infra, deployReq.Config.Mirror)
+	}
+	if deployReq.Config != nil && deployReq.Config.ABTest != nil && dep == w.engine.deployer {
+		w.startABTest(ctx, deployment, infra, deployReq.Config.ABTest)
+	}
+
 	logger.Info().
 		Str("external_url", deployment.ExternalURL).
 		Msg("Deploy job complete, application is live")
 
-	return nil
+	if deployReq.Config != nil && len(deployReq.Config.SmokeTests) > 0 {
+		if err := w.runSmokeTests(ctx, deployment, deployReq.Config.SmokeTests); err != nil {
+			return err
+		}
+	}
+
+	return w.engine.AdvanceStage(ctx, job, nil)
 }
 
-// handleDestroyJob handles infrastructure and deployment destruction jobs
-func (w *Worker) handleDestroyJob(ctx context.Context, job *queue.Job) error {
+// runSmokeTests checks a freshly exposed deployment, marking it HEALTHY when every smoke
+// test passes and UNHEALTHY otherwise. Each test's outcome is recorded as a deployment log.
+func (w *Worker) runSmokeTests(ctx context.Context, deployment *state.Deployment, tests []deployer.SmokeTest) error {
 	logger := w.logger.With().
-		Str("job_id", job.ID).
-		Str("deployment_id", job.DeploymentID).
+		Str("deployment_id", deployment.ID.String()).
 		Logger()
 
-	logger.Info().Msg("Handling destroy job")
-
-	// Parse destroy payload
-	payload, err := parseDestroyPayload(job)
-	if err != nil {
-		return fmt.Errorf("parse destroy payload: %w", err)
+	// Jobs and cronjobs are not exposed, so there is nothing to call
+	if deployment.ExternalURL == "" {
+		logger.Warn().Msg("Deployment has no external URL, skipping smoke tests")
+		return nil
 	}
 
-	// Get infrastructure from database
-	infraID, err := uuid.Parse(payload.InfrastructureID)
-	if err != nil {
-		return fmt.Errorf("parse infrastructure ID: %w", err)
+	result := deployer.RunSmokeTests(ctx, deployment.ExternalURL, tests)
+
+	for _, report := range result.Tests {
+		level, message := "INFO", fmt.Sprintf("%s %s passed with status %d in %dms",
+			report.Method, report.Path, report.StatusCode, report.DurationMs)
+		if !report.Passed {
+			level, message = "ERROR", fmt.Sprintf("%s %s failed: %s", report.Method, report.Path, report.Error)
+		}
+
+		if err := w.engine.repo.CreateDeploymentLog(ctx, &state.DeploymentLog{
+			DeploymentID: deployment.ID,
+			Phase:        deployment.Status,
+			Level:        level,
+			Source:       "smoke-test",
+			Message:      message,
+		}); err != nil {
+			logger.Warn().Err(err).Msg("Failed to record smoke test log")
+		}
 	}
 
-	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	resultJSON, err := json.Marshal(result)
 	if err != nil {
-		return fmt.Errorf("get infrastructure: %w", err)
+		return fmt.Errorf("encode smoke test result: %w", err)
 	}
 
-	logger.Info().
-		Str("infrastructure_id", payload.InfrastructureID).
-		Str("cluster_name", infra.ClusterName).
-		Msg("Starting destruction process")
-
-	// Step 1: Destroy Helm deployment if it exists
-	if infra.HelmReleaseName != "" && infra.KubeNamespace != "" {
-		logger.Info().
-			Str("namespace", infra.KubeNamespace).
-			Str("release", infra.HelmReleaseName).
-			Msg("Destroying Helm release")
+	deployment.SmokeTestResult = resultJSON
+	deployment.Status = "HEALTHY"
+	if !result.Passed {
+		deployment.Status = "UNHEALTHY"
+		deployment.Error = "Smoke tests failed"
+	}
 
-		destroyDeployReq := &deployer.DestroyRequest{
-			InfrastructureID: payload.InfrastructureID,
-			Namespace:        infra.KubeNamespace,
-			ReleaseName:      infra.HelmReleaseName,
-		}
+	if err := w.engine.repo.UpdateDeployment(ctx, deployment); err != nil {
+		return fmt.Errorf("update deployment: %w", err)
+	}
 
-		if err := w.engine.deployer.Destroy(ctx, destroyDeployReq); err != nil {
-			logger.Warn().
-				Err(err).
-				Msg("Failed to destroy Helm release, continuing with infrastructure destruction")
-			// Continue even if Helm destroy fails - we want to clean up infrastructure
-		} else {
-			logger.Info().Msg("Helm release destroyed successfully")
+	passed := 0
+	for _, report := range result.Tests {
+		if report.Passed {
+			passed++
 		}
 	}
+	w.recordEvent(ctx, deployment.ID, state.EventTypeSmokeTest,
+		fmt.Sprintf("%d of %d smoke tests passed", passed, len(result.Tests)),
+		map[string]interface{}{
+			"passed": result.Passed,
+			"tests":  len(result.Tests),
+		})
+	if !result.Passed {
+		w.recordEvent(ctx, deployment.ID, state.EventTypeHealthCheck,
+			"Deployment is unhealthy: smoke tests failed",
+			map[string]interface{}{
+				"url": deployment.ExternalURL,
+			})
+	}
+	w.recordStatusChange(ctx, deployment)
 
-	// Step 2: Destroy infrastructure (Pulumi stack)
 	logger.Info().
-		Str("stack_name", infra.PulumiStackName).
-		Msg("Destroying Pulumi stack")
+		Bool("passed", result.Passed).
+		Int("tests", len(result.Tests)).
+		Msg("Smoke tests complete")
 
-	destroyProvisionReq := &provisioner.DestroyRequest{
-		InfrastructureID: payload.InfrastructureID,
-		StackName:        infra.PulumiStackName,
-		DeploymentID:     payload.DeploymentID,
-	}
+	return nil
+}
 
-	if err := w.engine.provisioner.Destroy(ctx, destroyProvisionReq); err != nil {
-		logger.Error().
-			Err(err).
-			Msg("Failed to destroy infrastructure")
-		return fmt.Errorf("destroy infrastructure: %w", err)
+// dependenciesReady reports whether all of a deployment's dependencies are live. When some
+// are not, the job is delayed and checked again; after maxDependencyWaits the deployment fails.
+func (w *Worker) dependenciesReady(ctx context.Context, job *queue.Job, deployment *state.Deployment) (bool, error) {
+	blocking, err := w.engine.repo.GetBlockingDependencies(ctx, deployment.ID)
+	if err != nil {
+		return false, fmt.Errorf("get blocking dependencies: %w", err)
+	}
+	if len(blocking) == 0 {
+		return true, nil
 	}
 
-	logger.Info().Msg("Infrastructure destroyed successfully")
-
-	// Step 3: Update database - mark infrastructure as DESTROYED
-	infra.Status = "DESTROYED"
-	if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
-		logger.Error().
-			Err(err).
-			Msg("Failed to update infrastructure status")
-		return fmt.Errorf("update infrastructure: %w", err)
+	ids := make([]string, len(blocking))
+	for i, id := range blocking {
+		ids[i] = id.String()
+	}
+
+	if job.DependencyWaits >= maxDependencyWaits {
+		err := fmt.Errorf("dependencies not ready after %d checks: %s",
+			job.DependencyWaits, strings.Join(ids, ", "))
+
+		deployment.Status = "FAILED"
+		deployment.Error = err.Error()
+		if updateErr := w.engine.repo.UpdateDeployment(ctx, deployment); updateErr != nil {
+			w.logger.Error().
+				Err(updateErr).
+				Msg("Failed to update deployment status")
+		} else {
+			w.recordStatusChange(ctx, deployment)
+		}
+		return false, err
+	}
+
+	w.logger.Info().
+		Str("deployment_id", deployment.ID.String()).
+		Strs("blocked_by", ids).
+		Int("wait", job.DependencyWaits+1).
+		Msg("Dependencies not ready, delaying provision job")
+
+	job.DependencyWaits++
+	if err := w.engine.queue.EnqueueDelayed(ctx, job, dependencyWaitDelay); err != nil {
+		return false, fmt.Errorf("delay provision job: %w", err)
+	}
+
+	return false, nil
+}
+
+// bindWorkloadIdentity binds the app's Kubernetes ServiceAccount to a GCP service account,
+// reusing the binding recorded on the infrastructure when it still matches the deployment.
+func (w *Worker) bindWorkloadIdentity(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure) (*deployer.WorkloadIdentityConfig, error) {
+	identity := &deployer.WorkloadIdentityConfig{
+		GCPServiceAccountEmail:   infra.AppServiceAccountEmail,
+		KubernetesServiceAccount: fmt.Sprintf("app-%s", deployment.ID.String()[:8]),
+	}
+
+	requested := deployment.GCPServiceAccountEmail
+	if identity.GCPServiceAccountEmail != "" && (requested == "" || requested == identity.GCPServiceAccountEmail) {
+		return identity, nil
+	}
+
+	namespace := infra.Namespace
+	if namespace == "" {
+		namespace = fmt.Sprintf("deployer-%s", deployment.ID.String()[:8])
+	}
+
+	result, err := w.engine.provisioner.BindWorkloadIdentity(ctx, &provisioner.WorkloadIdentityRequest{
+		DeploymentID:             deployment.ID.String(),
+		InfrastructureID:         infra.ID.String(),
+		AppName:                  deployment.AppName,
+		Namespace:                namespace,
+		KubernetesServiceAccount: identity.KubernetesServiceAccount,
+		GCPServiceAccountEmail:   requested,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bind workload identity: %w", err)
+	}
+
+	infra.AppServiceAccountEmail = result.GCPServiceAccountEmail
+	if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		return nil, fmt.Errorf("update infrastructure: %w", err)
+	}
+
+	identity.GCPServiceAccountEmail = result.GCPServiceAccountEmail
+	return identity, nil
+}
+
+// verifyImage checks the cosign signature of the image about to be deployed. Unsigned
+// images only fail the deployment when signed images are enforced.
+func (w *Worker) verifyImage(ctx context.Context, deployment *state.Deployment, imageRef string) error {
+	if w.engine.cosignKeyRef == "" {
+		return nil
+	}
+
+	if signing.VerifySignature(ctx, imageRef, w.engine.cosignKeyRef) {
+		return nil
+	}
+
+	if !w.engine.enforceSigned {
+		w.logger.Warn().
+			Str("deployment_id", deployment.ID.String()).
+			Str("image_tag", imageRef).
+			Msg("Image signature could not be verified, deploying anyway")
+		return nil
+	}
+
+	err := fmt.Errorf("image signature verification failed: %s", imageRef)
+
+	deployment.Status = "FAILED"
+	deployment.Error = err.Error()
+	if updateErr := w.engine.repo.UpdateDeployment(ctx, deployment); updateErr != nil {
+		w.logger.Error().
+			Err(updateErr).
+			Msg("Failed to update deployment status")
+	} else {
+		w.recordStatusChange(ctx, deployment)
+	}
+
+	return err
+}
+
+// handleDestroyJob handles infrastructure and deployment destruction jobs
+func (w *Worker) handleDestroyJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	logger.Info().Msg("Handling destroy job")
+
+	// Parse destroy payload
+	payload, err := parseDestroyPayload(job)
+	if err != nil {
+		return fmt.Errorf("parse destroy payload: %w", err)
+	}
+
+	// Get infrastructure from database
+	infraID, err := uuid.Parse(payload.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("parse infrastructure ID: %w", err)
+	}
+
+	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("get infrastructure: %w", err)
 	}
 
-	// Step 4: Update deployment status
 	deploymentID, err := uuid.Parse(payload.DeploymentID)
 	if err != nil {
 		return fmt.Errorf("parse deployment ID: %w", err)
 	}
 
+	// The deployment's cloud and deployer type decide which deployer owns the release
+	var owner *state.Deployment
+	if deployment, err := w.engine.repo.GetDeploymentByID(ctx, deploymentID); err == nil {
+		owner = deployment
+	}
+
+	dep, err := w.engine.deployerFor(owner)
+	if err != nil {
+		return fmt.Errorf("select deployer: %w", err)
+	}
+
+	logger.Info().
+		Str("infrastructure_id", payload.InfrastructureID).
+		Str("cluster_name", infra.ClusterName).
+		Msg("Starting destruction process")
+
+	// Routes are removed while the cluster is still up so other deployments stop sending
+	// traffic to this one
+	var mirrorFrom *uuid.UUID
+	if owner != nil {
+		mirrorFrom = owner.MirrorFrom
+	}
+	if err := w.removeServiceRoutes(ctx, deploymentID, mirrorFrom); err != nil {
+		logger.Warn().
+			Err(err).
+			Msg("Failed to remove service routes, continuing with destruction")
+	}
+
+	if infra.RateLimitRuleName != "" {
+		if err := w.removeRateLimitRule(ctx, infra); err != nil {
+			logger.Warn().
+				Err(err).
+				Msg("Failed to remove Cloud Armor rate limit rule, continuing with destruction")
+		}
+	}
+
+	// Step 1: Destroy Helm deployment if it exists
+	if infra.HelmReleaseName != "" && infra.KubeNamespace != "" {
+		logger.Info().
+			Str("namespace", infra.KubeNamespace).
+			Str("release", infra.HelmReleaseName).
+			Msg("Destroying Helm release")
+
+		destroyDeployReq := &deployer.DestroyRequest{
+			DeploymentID:     payload.DeploymentID,
+			InfrastructureID: payload.InfrastructureID,
+			Namespace:        infra.KubeNamespace,
+			ReleaseName:      infra.HelmReleaseName,
+			DeleteVolumes:    payload.DeleteVolumes,
+		}
+
+		if err := dep.Destroy(ctx, destroyDeployReq); err != nil {
+			logger.Warn().
+				Err(err).
+				Msg("Failed to destroy Helm release, continuing with infrastructure destruction")
+			// Continue even if Helm destroy fails - we want to clean up infrastructure
+		} else {
+			logger.Info().Msg("Helm release destroyed successfully")
+		}
+	}
+
+	// Step 2: Destroy infrastructure (Pulumi stack); Cloud Run targets have none and
+	// imported clusters are left running
+	if infra.PulumiStackName != "" && !infra.ImportedExternally {
+		logger.Info().
+			Str("stack_name", infra.PulumiStackName).
+			Msg("Destroying Pulumi stack")
+
+		destroyProvisionReq := &provisioner.DestroyRequest{
+			InfrastructureID: payload.InfrastructureID,
+			StackName:        infra.PulumiStackName,
+			DeploymentID:     payload.DeploymentID,
+		}
+
+		if err := w.engine.provisioner.Destroy(ctx, destroyProvisionReq); err != nil {
+			logger.Error().
+				Err(err).
+				Msg("Failed to destroy infrastructure")
+			return fmt.Errorf("destroy infrastructure: %w", err)
+		}
+
+		logger.Info().Msg("Infrastructure destroyed successfully")
+	}
+
+	// Step 3: Update database - mark infrastructure as DESTROYED
+	infra.Status = "DESTROYED"
+	if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to update infrastructure status")
+		return fmt.Errorf("update infrastructure: %w", err)
+	}
+
+	// Step 4: Update deployment status
 	deployment, err := w.engine.repo.GetDeploymentByID(ctx, deploymentID)
 	if err != nil {
 		logger.Warn().
@@ -303,6 +952,8 @@ func (w *Worker) handleDestroyJob(ctx context.Context, job *queue.Job) error {
 			logger.Error().
 				Err(updateErr).
 				Msg("Failed to update deployment status")
+		} else {
+			w.recordStatusChange(ctx, deployment)
 		}
 	}
 
@@ -310,6 +961,61 @@ func (w *Worker) handleDestroyJob(ctx context.Context, job *queue.Job) error {
 	return nil
 }
 
+// removeServiceRoutes deletes the service routes from or to a deployment and the traffic
+// mirrors to or from it, and ends its A/B tests. It then reapplies the routes of every
+// deployment that had one, deleting the VirtualService of any left with nothing to route.
+// mirrorFrom is the deployment the destroyed one shadowed, if any.
+func (w *Worker) removeServiceRoutes(ctx context.Context, deploymentID uuid.UUID, mirrorFrom *uuid.UUID) error {
+	routes, err := w.engine.repo.DeleteServiceRoutesForDeployment(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("delete service routes: %w", err)
+	}
+
+	sources := make([]uuid.UUID, 0, len(routes)+2)
+	for _, route := range routes {
+		sources = append(sources, route.SourceDeploymentID)
+	}
+	if mirrorFrom != nil {
+		sources = append(sources, *mirrorFrom)
+	}
+
+	mirror, err := w.engine.repo.GetMirroringDeployment(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("get mirroring deployment: %w", err)
+	}
+	if mirror != nil {
+		if err := w.engine.repo.ClearDeploymentMirrors(ctx, deploymentID); err != nil {
+			return fmt.Errorf("clear deployment mirrors: %w", err)
+		}
+		sources = append(sources, deploymentID)
+	}
+
+	tests, err := w.engine.repo.EndABTests(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("end A/B tests: %w", err)
+	}
+	for _, test := range tests {
+		sources = append(sources, test.BaselineDeploymentID)
+	}
+
+	synced := make(map[uuid.UUID]bool)
+	for _, source := range sources {
+		if synced[source] {
+			continue
+		}
+		synced[source] = true
+
+		if err := deployer.SyncServiceRoutes(ctx, w.engine.repo, source); err != nil {
+			w.logger.Warn().
+				Err(err).
+				Str("deployment_id", source.String()).
+				Msg("Failed to reapply service routes")
+		}
+	}
+
+	return nil
+}
+
 // handleRollbackJob handles deployment rollback jobs
 func (w *Worker) handleRollbackJob(ctx context.Context, job *queue.Job) error {
 	logger := w.logger.With().
@@ -350,6 +1056,7 @@ func (w *Worker) handleRollbackJob(ctx context.Context, job *queue.Job) error {
 		Str("current_version", deployment.Version).
 		Str("target_version", payload.TargetVersion).
 		Str("target_tag", payload.TargetTag).
+		Int("revision", payload.Revision).
 		Msg("Starting rollback")
 
 	// Create rollback request
@@ -358,10 +1065,15 @@ func (w *Worker) handleRollbackJob(ctx context.Context, job *queue.Job) error {
 		InfrastructureID: infra.ID.String(),
 		Namespace:        infra.KubeNamespace,
 		ReleaseName:      infra.HelmReleaseName,
-		Revision:         0, // 0 means previous revision
+		Revision:         payload.Revision, // 0 means previous revision
 	}
 
-	if err := w.engine.deployer.Rollback(ctx, rollbackReq); err != nil {
+	dep, err := w.engine.deployerFor(deployment)
+	if err != nil {
+		return fmt.Errorf("select deployer: %w", err)
+	}
+
+	if err := dep.Rollback(ctx, rollbackReq); err != nil {
 		logger.Error().
 			Err(err).
 			Msg("Rollback failed")
@@ -379,8 +1091,10 @@ func (w *Worker) handleRollbackJob(ctx context.Context, job *queue.Job) error {
 
 	logger.Info().Msg("Rollback completed successfully")
 
-	// Update deployment version
-	deployment.Version = payload.TargetVersion
+	// Update deployment version. A rollback to a bare revision keeps the recorded one.
+	if payload.TargetVersion != "" {
+		deployment.Version = payload.TargetVersion
+	}
 	deployment.Status = "EXPOSED"
 	deployment.Error = ""
 
@@ -391,6 +1105,351 @@ func (w *Worker) handleRollbackJob(ctx context.Context, job *queue.Job) error {
 		return fmt.Errorf("update deployment: %w", err)
 	}
 
+	w.recordStatusChange(ctx, deployment)
+	w.recordHelmRevision(ctx, dep, deployment.ID, infra.ID)
+
 	logger.Info().Msg("Rollback job complete")
 	return nil
 }
+
+// handleReconcileJob compares live infrastructure state against the database record
+func (w *Worker) handleReconcileJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	logger.Info().Msg("Handling reconcile job")
+
+	// Parse reconcile payload
+	payload, err := parseReconcilePayload(job)
+	if err != nil {
+		return fmt.Errorf("parse reconcile payload: %w", err)
+	}
+
+	// Get infrastructure from database
+	infraID, err := uuid.Parse(payload.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("parse infrastructure ID: %w", err)
+	}
+
+	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("get infrastructure: %w", err)
+	}
+
+	if infra.Status != "READY" {
+		logger.Info().
+			Str("status", infra.Status).
+			Msg("Infrastructure is not READY, skipping reconciliation")
+		return nil
+	}
+
+	// Cloud Run targets and imported clusters have no Pulumi stack to compare against
+	if infra.PulumiStackName == "" {
+		logger.Info().Msg("Infrastructure has no Pulumi stack, skipping reconciliation")
+		return nil
+	}
+
+	// Fetch live stack outputs
+	status, err := w.engine.provisioner.GetStatus(ctx, infra.PulumiStackName)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("stack_name", infra.PulumiStackName).
+			Msg("Failed to get live infrastructure status")
+		return fmt.Errorf("get infrastructure status: %w", err)
+	}
+
+	drift := detectDrift(infra, status.Outputs)
+
+	now := time.Now()
+	infra.DriftDetected = len(drift) > 0
+	infra.DriftDetails = nil
+	infra.LastReconciledAt = &now
+	if infra.DriftDetected {
+		details, err := json.Marshal(drift)
+		if err != nil {
+			return fmt.Errorf("marshal drift details: %w", err)
+		}
+		driftDetails := string(details)
+		infra.DriftDetails = &driftDetails
+	}
+
+	if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to update infrastructure drift status")
+		return fmt.Errorf("update infrastructure: %w", err)
+	}
+
+	deployment, err := w.engine.repo.GetDeploymentByID(ctx, infra.DeploymentID)
+	if err != nil {
+		return fmt.Errorf("get deployment: %w", err)
+	}
+
+	if infra.DriftDetected {
+		fields := make([]string, 0, len(drift))
+		for field := range drift {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		logger.Warn().
+			Str("event", "infrastructure.drift_detected").
+			Str("infrastructure_id", infra.ID.String()).
+			Str("cluster_name", infra.ClusterName).
+			Strs("drifted_fields", fields).
+			Msg("Infrastructure drift detected")
+
+		if err := w.engine.repo.UpdateDeploymentStatus(ctx, deployment.ID, "DRIFTED"); err != nil {
+			return fmt.Errorf("update deployment status: %w", err)
+		}
+		if deployment.Status != "DRIFTED" {
+			deployment.Status = "DRIFTED"
+			w.recordStatusChange(ctx, deployment)
+
+			// Notify operators once per drift; it requires manual review before re-provisioning
+			if err := w.engine.EnqueueNotifyJob(ctx, &queue.NotifyPayload{
+				EventType:    "drift_detected",
+				DeploymentID: deployment.ID.String(),
+				Message: fmt.Sprintf("Infrastructure of deployment %s drifted on cluster %s: %s",
+					deployment.Name, infra.ClusterName, strings.Join(fields, ", ")),
+				Data: map[string]string{
+					"infrastructure_id": infra.ID.String(),
+					"cluster_name":      infra.ClusterName,
+					"drifted_fields":    strings.Join(fields, ","),
+				},
+			}); err != nil {
+				logger.Warn().Err(err).Msg("Failed to enqueue drift notification")
+			}
+		}
+	} else if deployment.Status == "DRIFTED" {
+		// Drift has been resolved, restore the deployment to its live state
+		if err := w.engine.repo.UpdateDeploymentStatus(ctx, deployment.ID, "EXPOSED"); err != nil {
+			return fmt.Errorf("update deployment status: %w", err)
+		}
+		deployment.Status = "EXPOSED"
+		w.recordStatusChange(ctx, deployment)
+	}
+
+	w.recordHPAScaling(ctx, infra)
+
+	if err := w.reconcileRelease(ctx, deployment, infra); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Bool("drift_detected", infra.DriftDetected).
+		Msg("Reconcile job complete")
+
+	return nil
+}
+
+// handleUpdateInfraJob updates a deployment's node pool in place
+func (w *Worker) handleUpdateInfraJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	logger.Info().Msg("Handling infrastructure update job")
+
+	// Parse update payload
+	payload, err := parseUpdateInfraPayload(job)
+	if err != nil {
+		return fmt.Errorf("parse update infra payload: %w", err)
+	}
+
+	// Get infrastructure from database
+	infraID, err := uuid.Parse(payload.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("parse infrastructure ID: %w", err)
+	}
+
+	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("get infrastructure: %w", err)
+	}
+
+	if infra.PulumiStackName == "" {
+		return fmt.Errorf("infrastructure %s has no Pulumi stack to update", infra.ID)
+	}
+
+	// Keep reconciliation away from the stack while it is being updated
+	if err := w.engine.repo.UpdateInfrastructureStatus(ctx, infra.ID, "UPDATING"); err != nil {
+		return fmt.Errorf("update infrastructure status: %w", err)
+	}
+
+	err = w.engine.provisioner.UpdateNodePool(ctx, infra.PulumiStackName, provisioner.NodePoolUpdateConfig{
+		InfrastructureID: infra.ID.String(),
+		NodeCount:        payload.NodeCount,
+		MachineType:      payload.MachineType,
+	})
+
+	// The cluster keeps serving whether or not the update went through
+	infra.Status = "READY"
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("stack_name", infra.PulumiStackName).
+			Msg("Node pool update failed")
+
+		infra.LastError = err.Error()
+		if updateErr := w.engine.repo.UpdateInfrastructure(ctx, infra); updateErr != nil {
+			logger.Error().
+				Err(updateErr).
+				Msg("Failed to update infrastructure status")
+		}
+
+		return fmt.Errorf("update node pool: %w", err)
+	}
+
+	if payload.NodeCount > 0 {
+		infra.NodeCount = payload.NodeCount
+	}
+	infra.LastError = ""
+
+	if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		return fmt.Errorf("update infrastructure: %w", err)
+	}
+
+	logger.Info().
+		Int("node_count", infra.NodeCount).
+		Str("machine_type", payload.MachineType).
+		Msg("Infrastructure update job complete")
+
+	return nil
+}
+
+// handleMigrateBackendJob moves a deployment's Pulumi stack to another state backend
+func (w *Worker) handleMigrateBackendJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	logger.Info().Msg("Handling backend migration job")
+
+	payload, err := parseMigrateBackendPayload(job)
+	if err != nil {
+		return fmt.Errorf("parse migrate backend payload: %w", err)
+	}
+
+	infraID, err := uuid.Parse(payload.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("parse infrastructure ID: %w", err)
+	}
+
+	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("get infrastructure: %w", err)
+	}
+
+	if infra.PulumiStackName == "" || infra.PulumiStackName != payload.StackName {
+		return fmt.Errorf("infrastructure %s does not own stack %s", infra.ID, payload.StackName)
+	}
+
+	// Provisioning and reconciliation would write to the old backend mid-migration
+	if err := w.engine.repo.UpdateInfrastructureStatus(ctx, infra.ID, "MIGRATING"); err != nil {
+		return fmt.Errorf("update infrastructure status: %w", err)
+	}
+
+	err = w.engine.provisioner.MigrateBackend(ctx, &provisioner.BackendMigrationRequest{
+		StackName:   payload.StackName,
+		FromBackend: payload.FromBackend,
+		ToBackend:   payload.ToBackend,
+	})
+
+	// The old stack is untouched on failure, so the infrastructure stays usable either way
+	infra.Status = "READY"
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("stack_name", payload.StackName).
+			Str("to_backend", payload.ToBackend).
+			Msg("Backend migration failed")
+
+		infra.LastError = err.Error()
+		if updateErr := w.engine.repo.UpdateInfrastructure(ctx, infra); updateErr != nil {
+			logger.Error().
+				Err(updateErr).
+				Msg("Failed to update infrastructure status")
+		}
+
+		return fmt.Errorf("migrate backend: %w", err)
+	}
+
+	infra.PulumiBackendURL = payload.ToBackend
+	infra.LastError = ""
+
+	if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
+		return fmt.Errorf("update infrastructure: %w", err)
+	}
+
+	logger.Info().
+		Str("stack_name", payload.StackName).
+		Str("backend", payload.ToBackend).
+		Msg("Backend migration job complete")
+
+	return nil
+}
+
+// handleDestroyStackJob destroys a Pulumi stack that outlived its deployment's infrastructure,
+// e.g. after a failed destroy. The owning infrastructure record, if any, is marked DESTROYED.
+func (w *Worker) handleDestroyStackJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	logger.Info().Msg("Handling stack destroy job")
+
+	payload, err := parseDestroyStackPayload(job)
+	if err != nil {
+		return fmt.Errorf("parse destroy stack payload: %w", err)
+	}
+
+	var infra *state.Infrastructure
+	if payload.InfrastructureID != "" {
+		infraID, err := uuid.Parse(payload.InfrastructureID)
+		if err != nil {
+			return fmt.Errorf("parse infrastructure ID: %w", err)
+		}
+
+		infra, err = w.engine.repo.GetInfrastructureByID(ctx, infraID)
+		if err != nil {
+			return fmt.Errorf("get infrastructure: %w", err)
+		}
+
+		// The record may have been reused by a redeploy since the stack was reported
+		if infra.Status != "FAILED" && infra.Status != "DESTROYED" {
+			return fmt.Errorf("infrastructure %s is %s, not destroying its stack", infra.ID, infra.Status)
+		}
+	}
+
+	logger.Info().
+		Str("stack_name", payload.StackName).
+		Msg("Destroying orphaned Pulumi stack")
+
+	if err := w.engine.provisioner.Destroy(ctx, &provisioner.DestroyRequest{
+		InfrastructureID: payload.InfrastructureID,
+		StackName:        payload.StackName,
+		DeploymentID:     payload.DeploymentID,
+	}); err != nil {
+		return fmt.Errorf("destroy stack: %w", err)
+	}
+
+	if infra != nil && infra.Status != "DESTROYED" {
+		infra.Status = "DESTROYED"
+		if err := w.engine.repo.UpdateInfrastructure(ctx, infra); err != nil {
+			return fmt.Errorf("update infrastructure: %w", err)
+		}
+	}
+
+	logger.Info().
+		Str("stack_name", payload.StackName).
+		Msg("Orphaned stack destroyed")
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// workerHeartbeatInterval is how often a worker refreshes its registered status
+	workerHeartbeatInterval = 30 * time.Second
+
+	// workerStatusTTL is how long a status outlives its last heartbeat; a worker that misses
+	// two heartbeats in a row is considered dead
+	workerStatusTTL = 60 * time.Second
+)
+
+// WorkerHeartbeat registers a worker's status in Redis and keeps it alive, so the API can
+// list the workers that are running
+type WorkerHeartbeat struct {
+	registry *queue.RedisQueue
+	worker   *Worker
+	pool     *DynamicConcurrencyManager
+	status   queue.WorkerStatus
+	logger   zerolog.Logger
+}
+
+// NewWorkerHeartbeat creates a heartbeat for worker, registered under the host's name
+func NewWorkerHeartbeat(registry *queue.RedisQueue, worker *Worker, pool *DynamicConcurrencyManager, version string, logger zerolog.Logger) *WorkerHeartbeat {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = fmt.Sprintf("worker-%d", os.Getpid())
+	}
+
+	return &WorkerHeartbeat{
+		registry: registry,
+		worker:   worker,
+		pool:     pool,
+		status: queue.WorkerStatus{
+			Hostname:  hostname,
+			PID:       os.Getpid(),
+			StartedAt: time.Now().UTC(),
+			Version:   version,
+		},
+		logger: logger.With().Str("component", "heartbeat").Logger(),
+	}
+}
+
+// Start registers the worker, then refreshes its status until the context is cancelled. The
+// status is left to expire once the worker stops.
+func (h *WorkerHeartbeat) Start(ctx context.Context) {
+	h.logger.Info().
+		Str("hostname", h.status.Hostname).
+		Dur("interval", workerHeartbeatInterval).
+		Msg("Starting worker heartbeat")
+
+	h.beat(ctx)
+
+	ticker := time.NewTicker(workerHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.logger.Info().Msg("Worker heartbeat stopped")
+			return
+		case <-ticker.C:
+			h.beat(ctx)
+		}
+	}
+}
+
+// beat publishes the worker's current status
+func (h *WorkerHeartbeat) beat(ctx context.Context) {
+	status := h.status
+	status.Concurrency = h.pool.Concurrency()
+	status.ActiveJobs = h.worker.ActiveJobs()
+
+	if err := h.registry.SetWorkerStatus(ctx, &status, workerStatusTTL); err != nil {
+		h.logger.Warn().Err(err).Msg("Failed to publish worker heartbeat")
+	}
+}
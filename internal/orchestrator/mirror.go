@@ -0,0 +1,59 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/google/uuid"
+)
+
+// startMirror mirrors the traffic of the deployment a freshly deployed shadow was created from
+// to it. The shadow is deployed either way, so failures are recorded in its logs instead of
+// failing the deploy.
+func (w *Worker) startMirror(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure, mirror *deployer.MirrorConfig) {
+	logger := w.logger.With().
+		Str("deployment_id", deployment.ID.String()).
+		Str("mirror_from", mirror.MirrorFrom).
+		Logger()
+
+	level, message := "INFO", fmt.Sprintf("Mirroring traffic of deployment %s", mirror.MirrorFrom)
+	if err := w.syncSourceRoutes(ctx, infra, mirror.MirrorFrom); err != nil {
+		logger.Error().Err(err).Msg("Failed to start traffic mirror")
+		level, message = "ERROR", fmt.Sprintf("Failed to mirror traffic of deployment %s: %s", mirror.MirrorFrom, err)
+	} else {
+		logger.Info().Msg("Traffic mirror started")
+	}
+
+	if err := w.engine.repo.CreateDeploymentLog(ctx, &state.DeploymentLog{
+		DeploymentID: deployment.ID,
+		Phase:        deployment.Status,
+		Level:        level,
+		Source:       "mirror",
+		Message:      message,
+	}); err != nil {
+		logger.Warn().Err(err).Msg("Failed to record traffic mirror log")
+	}
+}
+
+// syncSourceRoutes checks that the deployment whose traffic a freshly deployed one receives runs
+// on its cluster and reapplies that deployment's VirtualService, which picks the new one up
+func (w *Worker) syncSourceRoutes(ctx context.Context, infra *state.Infrastructure, source string) error {
+	sourceID, err := uuid.Parse(source)
+	if err != nil {
+		return fmt.Errorf("parse source deployment ID: %w", err)
+	}
+
+	sourceInfra, err := w.engine.repo.GetInfrastructure(ctx, sourceID)
+	if err != nil {
+		return fmt.Errorf("get source infrastructure: %w", err)
+	}
+
+	// Istio only routes between services of the same mesh
+	if sourceInfra.ClusterEndpoint != infra.ClusterEndpoint {
+		return fmt.Errorf("deployment %s runs on another cluster", source)
+	}
+
+	return deployer.SyncServiceRoutes(ctx, w.engine.repo, sourceID)
+}
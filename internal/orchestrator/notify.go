@@ -0,0 +1,93 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+)
+
+// webhookTimeout bounds a single notification delivery attempt
+const webhookTimeout = 10 * time.Second
+
+// webhookSignatureHeader carries the HMAC-SHA256 of the body, hex-encoded with a sha256= prefix
+const webhookSignatureHeader = "X-Deployer-Signature"
+
+// notificationEvent is the JSON body POSTed to the notification webhook
+type notificationEvent struct {
+	EventType    string            `json:"event_type"`
+	DeploymentID string            `json:"deployment_id"`
+	Message      string            `json:"message"`
+	Data         map[string]string `json:"data,omitempty"`
+	Timestamp    time.Time         `json:"timestamp"`
+}
+
+// handleNotifyJob delivers a notification to the configured webhook. Failed deliveries are
+// retried with the job; notifications are dropped when no webhook is configured.
+func (w *Worker) handleNotifyJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	payload, err := parseNotifyPayload(job)
+	if err != nil {
+		return fmt.Errorf("parse notify payload: %w", err)
+	}
+
+	if w.engine.webhookURL == "" {
+		logger.Debug().
+			Str("event_type", payload.EventType).
+			Msg("No notification webhook configured, dropping notification")
+		return nil
+	}
+
+	body, err := json.Marshal(notificationEvent{
+		EventType:    payload.EventType,
+		DeploymentID: payload.DeploymentID,
+		Message:      payload.Message,
+		Data:         payload.Data,
+		Timestamp:    time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.engine.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.engine.webhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(w.engine.webhookSecret))
+		mac.Write(body)
+		req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("deliver notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook returned %s", resp.Status)
+	}
+
+	logger.Info().
+		Str("event_type", payload.EventType).
+		Msg("Notification delivered")
+
+	return nil
+}
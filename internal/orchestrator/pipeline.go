@@ -0,0 +1,214 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/google/uuid"
+)
+
+// pipelineStages are the stages of a full pipeline, in order
+var pipelineStages = []string{
+	state.StageTypeBuild,
+	state.StageTypeProvision,
+	state.StageTypeDeploy,
+}
+
+// stageTypes maps the job types that run a pipeline stage to the stage they run
+var stageTypes = map[queue.JobType]string{
+	queue.JobTypeBuild:     state.StageTypeBuild,
+	queue.JobTypeProvision: state.StageTypeProvision,
+	queue.JobTypeDeploy:    state.StageTypeDeploy,
+}
+
+// startStage marks the pipeline stage job runs as running. A job that belongs to neither the
+// deployment's current pipeline nor its next pending stage starts a new pipeline at its stage,
+// e.g. a provision job from the API or a deploy job from GitOps. Pipelines only record
+// progress, so failures are logged rather than failing the job.
+func (e *Engine) startStage(ctx context.Context, job *queue.Job) {
+	stageType, ok := stageTypes[job.Type]
+	if !ok {
+		return
+	}
+
+	deploymentID, err := uuid.Parse(job.DeploymentID)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	pipeline, err := e.repo.GetPipeline(ctx, deploymentID)
+	if err == nil {
+		if i := stageIndex(pipeline, job, stageType); i >= 0 {
+			stage := &pipeline.Stages[i]
+			stage.Status = state.StageStatusRunning
+			stage.JobID = job.ID
+			stage.Error = ""
+			if stage.StartedAt == nil {
+				stage.StartedAt = &now
+			}
+			pipeline.CurrentStageIndex = i
+
+			if err := e.repo.UpdatePipeline(ctx, pipeline); err != nil {
+				e.logger.Warn().Err(err).Str("job_id", job.ID).Msg("Failed to record pipeline stage start")
+			}
+			return
+		}
+	}
+
+	pipeline = newPipeline(deploymentID, stageType)
+	pipeline.Stages[0].Status = state.StageStatusRunning
+	pipeline.Stages[0].JobID = job.ID
+	pipeline.Stages[0].StartedAt = &now
+
+	if err := e.repo.StartPipeline(ctx, pipeline); err != nil {
+		e.logger.Warn().Err(err).Str("job_id", job.ID).Msg("Failed to start pipeline")
+	}
+}
+
+// AdvanceStage marks the pipeline stage run by completed as done and enqueues next as the
+// job of the following stage. next is nil when completed ran the last stage. next is
+// enqueued even when the deployment has no pipeline.
+func (e *Engine) AdvanceStage(ctx context.Context, completed, next *queue.Job) error {
+	e.completeStage(ctx, completed, next)
+
+	if next == nil {
+		return nil
+	}
+
+	if err := e.queue.Enqueue(ctx, next); err != nil {
+		return fmt.Errorf("enqueue %s job: %w", next.Type, err)
+	}
+
+	e.logger.Info().
+		Str("job_id", next.ID).
+		Str("job_type", string(next.Type)).
+		Str("deployment_id", next.DeploymentID).
+		Msg("Next pipeline stage enqueued")
+
+	return nil
+}
+
+// completeStage records the stage run by completed as done and next as queued for the stage
+// after it, if completed is part of a pipeline. When an earlier attempt already queued a job
+// for the next stage, next takes over its ID so the stage stays linked to the job that runs it.
+func (e *Engine) completeStage(ctx context.Context, completed, next *queue.Job) {
+	deploymentID, err := uuid.Parse(completed.DeploymentID)
+	if err != nil {
+		return
+	}
+
+	pipeline, err := e.repo.GetPipeline(ctx, deploymentID)
+	if err != nil {
+		return
+	}
+
+	i := jobStageIndex(pipeline, completed.ID)
+	if i < 0 {
+		return
+	}
+
+	now := time.Now()
+	pipeline.Stages[i].Status = state.StageStatusCompleted
+	pipeline.Stages[i].Error = ""
+	pipeline.Stages[i].CompletedAt = &now
+
+	if next != nil {
+		nextType := stageTypes[next.Type]
+		if i+1 >= len(pipeline.Stages) || pipeline.Stages[i+1].Type != nextType {
+			pipeline.Stages = append(pipeline.Stages[:i+1], state.PipelineStage{Type: nextType})
+		}
+
+		stage := &pipeline.Stages[i+1]
+		if stage.Status == state.StageStatusQueued && stage.JobID != "" {
+			next.ID = stage.JobID
+		}
+		stage.Status = state.StageStatusQueued
+		stage.JobID = next.ID
+		pipeline.CurrentStageIndex = i + 1
+	}
+
+	if err := e.repo.UpdatePipeline(ctx, pipeline); err != nil {
+		e.logger.Warn().Err(err).Str("job_id", completed.ID).Msg("Failed to record pipeline stage completion")
+	}
+}
+
+// failStage marks the pipeline stage run by job as failed after its last attempt
+func (e *Engine) failStage(ctx context.Context, job *queue.Job, jobErr error) {
+	deploymentID, err := uuid.Parse(job.DeploymentID)
+	if err != nil {
+		return
+	}
+
+	pipeline, err := e.repo.GetPipeline(ctx, deploymentID)
+	if err != nil {
+		return
+	}
+
+	i := jobStageIndex(pipeline, job.ID)
+	if i < 0 {
+		return
+	}
+
+	now := time.Now()
+	pipeline.Stages[i].Status = state.StageStatusFailed
+	pipeline.Stages[i].Error = jobErr.Error()
+	pipeline.Stages[i].CompletedAt = &now
+
+	if err := e.repo.UpdatePipeline(ctx, pipeline); err != nil {
+		e.logger.Warn().Err(err).Str("job_id", job.ID).Msg("Failed to record pipeline stage failure")
+	}
+}
+
+// newPipeline creates a pipeline running from the stage of the given type to the last stage
+func newPipeline(deploymentID uuid.UUID, from string) *state.Pipeline {
+	pipeline := &state.Pipeline{DeploymentID: deploymentID}
+
+	started := false
+	for _, stageType := range pipelineStages {
+		if stageType == from {
+			started = true
+		}
+		if started {
+			pipeline.Stages = append(pipeline.Stages, state.PipelineStage{
+				Type:   stageType,
+				Status: state.StageStatusPending,
+			})
+		}
+	}
+
+	return pipeline
+}
+
+// stageIndex returns the index of the stage job runs in pipeline: the stage it was queued
+// for, or the next stage when it is still pending after a completed one, e.g. a provision
+// job started once a build awaiting approval is approved. It returns -1 when job is not
+// part of pipeline.
+func stageIndex(pipeline *state.Pipeline, job *queue.Job, stageType string) int {
+	if i := jobStageIndex(pipeline, job.ID); i >= 0 {
+		return i
+	}
+
+	i := pipeline.CurrentStageIndex
+	if i+1 < len(pipeline.Stages) &&
+		pipeline.Stages[i].Status == state.StageStatusCompleted &&
+		pipeline.Stages[i+1].Status == state.StageStatusPending &&
+		pipeline.Stages[i+1].Type == stageType {
+		return i + 1
+	}
+
+	return -1
+}
+
+// jobStageIndex returns the index of the stage run by the job with jobID, or -1
+func jobStageIndex(pipeline *state.Pipeline, jobID string) int {
+	for i, stage := range pipeline.Stages {
+		if stage.JobID == jobID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,146 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/rs/zerolog"
+)
+
+// addonCheckInterval is how often clusters' platform addons are compared with the versions
+// available. Each check notifies again of upgrades still pending.
+const addonCheckInterval = 7 * 24 * time.Hour
+
+// AddonChecker records the chart versions of the platform addons running on each cluster and
+// notifies when one of them can be upgraded
+type AddonChecker struct {
+	engine *Engine
+	helm   *deployer.HelmDeployer
+	client *Client
+	logger zerolog.Logger
+}
+
+// NewAddonChecker creates a new weekly platform addon checker
+func NewAddonChecker(engine *Engine, helm *deployer.HelmDeployer, logger zerolog.Logger) *AddonChecker {
+	logger = logger.With().Str("component", "addon-checker").Logger()
+
+	return &AddonChecker{
+		engine: engine,
+		helm:   helm,
+		client: NewClient(engine.queue, logger),
+		logger: logger,
+	}
+}
+
+// Start checks addons weekly until the context is cancelled
+func (a *AddonChecker) Start(ctx context.Context) {
+	a.logger.Info().
+		Dur("interval", addonCheckInterval).
+		Msg("Starting platform addon checker")
+
+	ticker := time.NewTicker(addonCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.logger.Info().Msg("Platform addon checker stopped")
+			return
+		case <-ticker.C:
+			if err := a.Check(ctx); err != nil {
+				a.logger.Error().Err(err).Msg("Failed to check platform addons")
+			}
+		}
+	}
+}
+
+// Check reads the addon versions of every cluster on ready infrastructure. The versions
+// available are looked up once per check, not once per cluster.
+func (a *AddonChecker) Check(ctx context.Context) error {
+	infras, err := a.engine.repo.ListInfrastructureByStatus(ctx, "READY")
+	if err != nil {
+		return fmt.Errorf("list ready infrastructure: %w", err)
+	}
+
+	addons := a.helm.PlatformAddons()
+	available := make(map[string]string, len(addons))
+	for i, addon := range addons {
+		if addon.ChartVersion == "" {
+			latest, err := deployer.LatestChartVersion(ctx, addon)
+			if err != nil {
+				a.logger.Warn().Err(err).Str("addon", addon.Name).Msg("Failed to look up addon versions")
+				continue
+			}
+			addons[i].ChartVersion = latest
+		}
+		available[addon.Name] = addons[i].ChartVersion
+	}
+
+	upgrades := 0
+	for _, infra := range infras {
+		if infra.ClusterEndpoint == "" {
+			continue
+		}
+
+		versions := make(map[string]string)
+		for _, addon := range addons {
+			if _, ok := available[addon.Name]; !ok {
+				continue
+			}
+
+			version, err := a.helm.GetAddonVersion(ctx, infra, addon)
+			if err != nil {
+				a.logger.Warn().
+					Err(err).
+					Str("deployment_id", infra.DeploymentID.String()).
+					Str("addon", addon.Name).
+					Msg("Failed to read addon version")
+				continue
+			}
+			if !version.Installed {
+				continue
+			}
+			versions[addon.Name] = version.CurrentVersion
+
+			if !version.UpgradeAvailable {
+				continue
+			}
+			upgrades++
+
+			if err := a.client.TriggerNotification(ctx, &queue.NotifyPayload{
+				EventType:    "addon_upgrade_available",
+				DeploymentID: infra.DeploymentID.String(),
+				Message: fmt.Sprintf("%s %s can be upgraded to %s on cluster %s",
+					addon.Name, version.CurrentVersion, version.AvailableVersion, infra.ClusterName),
+				Data: map[string]string{
+					"addon":             addon.Name,
+					"current_version":   version.CurrentVersion,
+					"available_version": version.AvailableVersion,
+					"cluster_name":      infra.ClusterName,
+				},
+			}); err != nil {
+				a.logger.Warn().
+					Err(err).
+					Str("deployment_id", infra.DeploymentID.String()).
+					Msg("Failed to enqueue addon upgrade notification")
+			}
+		}
+
+		if err := a.engine.repo.UpdateInfrastructureAddonVersions(ctx, infra.ID, versions); err != nil {
+			a.logger.Warn().
+				Err(err).
+				Str("deployment_id", infra.DeploymentID.String()).
+				Msg("Failed to record addon versions")
+		}
+	}
+
+	a.logger.Info().
+		Int("clusters", len(infras)).
+		Int("upgrades_available", upgrades).
+		Msg("Platform addons checked")
+
+	return nil
+}
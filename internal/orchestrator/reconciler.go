@@ -0,0 +1,122 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+)
+
+// DriftEntry describes a single field whose live value differs from the database record
+type DriftEntry struct {
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// Reconciler periodically enqueues reconcile jobs for all READY infrastructure
+type Reconciler struct {
+	engine   *Engine
+	interval time.Duration
+	logger   zerolog.Logger
+}
+
+// NewReconciler creates a new periodic reconciler
+func NewReconciler(engine *Engine, interval time.Duration, logger zerolog.Logger) *Reconciler {
+	return &Reconciler{
+		engine:   engine,
+		interval: interval,
+		logger:   logger.With().Str("component", "reconciler").Logger(),
+	}
+}
+
+// Start runs the reconciliation loop until the context is cancelled
+func (r *Reconciler) Start(ctx context.Context) {
+	if r.interval <= 0 {
+		r.logger.Info().Msg("Periodic reconciliation disabled")
+		return
+	}
+
+	r.logger.Info().
+		Dur("interval", r.interval).
+		Msg("Starting periodic reconciliation")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info().Msg("Periodic reconciliation stopped")
+			return
+		case <-ticker.C:
+			if err := r.enqueueAll(ctx); err != nil {
+				r.logger.Error().Err(err).Msg("Failed to enqueue reconcile jobs")
+			}
+		}
+	}
+}
+
+// enqueueAll enqueues a reconcile job for every READY infrastructure record
+func (r *Reconciler) enqueueAll(ctx context.Context) error {
+	infras, err := r.engine.repo.ListInfrastructureByStatus(ctx, "READY")
+	if err != nil {
+		return fmt.Errorf("list ready infrastructure: %w", err)
+	}
+
+	for _, infra := range infras {
+		payload := &queue.ReconcilePayload{
+			DeploymentID:     infra.DeploymentID.String(),
+			InfrastructureID: infra.ID.String(),
+		}
+
+		if err := r.engine.EnqueueReconcileJob(ctx, payload); err != nil {
+			r.logger.Warn().
+				Err(err).
+				Str("infrastructure_id", infra.ID.String()).
+				Msg("Failed to enqueue reconcile job")
+		}
+	}
+
+	r.logger.Info().
+		Int("count", len(infras)).
+		Msg("Reconcile jobs enqueued")
+
+	return nil
+}
+
+// detectDrift compares live Pulumi stack outputs against the stored infrastructure record
+func detectDrift(infra *state.Infrastructure, outputs map[string]interface{}) map[string]DriftEntry {
+	expected := map[string]string{
+		"clusterName":     infra.ClusterName,
+		"clusterEndpoint": infra.ClusterEndpoint,
+		"clusterLocation": infra.ClusterLocation,
+		"vpcName":         infra.VPCName,
+		"vpcNetwork":      infra.VPCNetwork,
+		"subnetName":      infra.SubnetName,
+		"subnetCIDR":      infra.SubnetCIDR,
+		"nodePoolName":    infra.NodePoolName,
+		"serviceAccount":  infra.ServiceAccountEmail,
+	}
+
+	drift := make(map[string]DriftEntry)
+	for key, want := range expected {
+		// Fields never recorded in the database cannot be compared
+		if want == "" {
+			continue
+		}
+
+		got := ""
+		if val, ok := outputs[key]; ok && val != nil {
+			got = fmt.Sprintf("%v", val)
+		}
+
+		if got != want {
+			drift[key] = DriftEntry{Expected: want, Actual: got}
+		}
+	}
+
+	return drift
+}
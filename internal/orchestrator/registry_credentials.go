@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/builder/registry"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+)
+
+// credentialCheckInterval is how often registry credentials are checked for expiry. Each
+// check covers the credentials expiring before the next one, so each is handled once.
+const credentialCheckInterval = 24 * time.Hour
+
+// CredentialRotator rotates the service account keys of registry credentials that expire within
+// a day, and notifies of expiring credentials it cannot rotate so they are replaced in time
+type CredentialRotator struct {
+	engine *Engine
+	client *Client
+	logger zerolog.Logger
+}
+
+// NewCredentialRotator creates a new daily registry credential rotator
+func NewCredentialRotator(engine *Engine, logger zerolog.Logger) *CredentialRotator {
+	logger = logger.With().Str("component", "credential-rotator").Logger()
+
+	return &CredentialRotator{
+		engine: engine,
+		client: NewClient(engine.queue, logger),
+		logger: logger,
+	}
+}
+
+// Start checks credentials daily until the context is cancelled
+func (c *CredentialRotator) Start(ctx context.Context) {
+	c.logger.Info().
+		Dur("interval", credentialCheckInterval).
+		Msg("Starting registry credential rotator")
+
+	ticker := time.NewTicker(credentialCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info().Msg("Registry credential rotator stopped")
+			return
+		case <-ticker.C:
+			if err := c.check(ctx); err != nil {
+				c.logger.Error().Err(err).Msg("Failed to check registry credentials")
+			}
+		}
+	}
+}
+
+// check rotates or notifies of every credential expiring before the next check
+func (c *CredentialRotator) check(ctx context.Context) error {
+	credentials, err := c.engine.repo.ListExpiringRegistryCredentials(ctx, time.Now().Add(credentialCheckInterval))
+	if err != nil {
+		return fmt.Errorf("list expiring registry credentials: %w", err)
+	}
+
+	for i := range credentials {
+		credential := &credentials[i]
+		expiresAt := credential.ExpiresAt.UTC().Format(time.RFC3339)
+
+		if credential.Username == registry.JSONKeyUsername && c.engine.secretCipher != nil {
+			err := registry.RotateCredential(ctx, c.engine.repo, c.engine.secretCipher, credential)
+			if err == nil {
+				c.notify(ctx, credential, "registry_credential_rotated",
+					fmt.Sprintf("Service account key for %s was rotated before it expired", credential.RegistryURL))
+				continue
+			}
+
+			c.logger.Warn().
+				Err(err).
+				Str("credential_id", credential.ID.String()).
+				Msg("Failed to rotate registry credential")
+		}
+
+		c.notify(ctx, credential, "registry_credential_expiring",
+			fmt.Sprintf("Credential for %s expires on %s", credential.RegistryURL, expiresAt))
+	}
+
+	if len(credentials) > 0 {
+		c.logger.Info().
+			Int("count", len(credentials)).
+			Msg("Handled expiring registry credentials")
+	}
+
+	return nil
+}
+
+// notify enqueues a notification about a registry credential
+func (c *CredentialRotator) notify(ctx context.Context, credential *state.RegistryCredential, eventType, message string) {
+	data := map[string]string{
+		"credential_id": credential.ID.String(),
+		"registry_url":  credential.RegistryURL,
+		"username":      credential.Username,
+		"user_id":       credential.UserID,
+	}
+	if credential.ExpiresAt != nil {
+		data["expires_at"] = credential.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	if err := c.client.TriggerNotification(ctx, &queue.NotifyPayload{
+		EventType: eventType,
+		Message:   message,
+		Data:      data,
+	}); err != nil {
+		c.logger.Warn().
+			Err(err).
+			Str("credential_id", credential.ID.String()).
+			Msg("Failed to enqueue registry credential notification")
+	}
+}
@@ -0,0 +1,143 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/google/uuid"
+)
+
+// restartRolloutTimeout bounds the wait for restarted pods to become ready
+const restartRolloutTimeout = 5 * time.Minute
+
+// handleRestartJob rolls a deployment's pods without redeploying, so they pick up changed
+// secrets or configuration, then waits for the replacement pods to become ready
+func (w *Worker) handleRestartJob(ctx context.Context, job *queue.Job) error {
+	logger := w.logger.With().
+		Str("job_id", job.ID).
+		Str("deployment_id", job.DeploymentID).
+		Logger()
+
+	logger.Info().Msg("Handling restart job")
+
+	payload, err := parseRestartPayload(job)
+	if err != nil {
+		return fmt.Errorf("parse restart payload: %w", err)
+	}
+
+	deploymentID, err := uuid.Parse(payload.DeploymentID)
+	if err != nil {
+		return fmt.Errorf("parse deployment ID: %w", err)
+	}
+
+	infraID, err := uuid.Parse(payload.InfrastructureID)
+	if err != nil {
+		return fmt.Errorf("parse infrastructure ID: %w", err)
+	}
+
+	deployment, err := w.engine.repo.GetDeploymentByID(ctx, deploymentID)
+	if err != nil {
+		return fmt.Errorf("get deployment: %w", err)
+	}
+
+	infra, err := w.engine.repo.GetInfrastructureByID(ctx, infraID)
+	if err != nil {
+		return fmt.Errorf("get infrastructure: %w", err)
+	}
+
+	if deployment.Status != "RESTARTING" {
+		if err := w.engine.repo.UpdateDeploymentStatus(ctx, deployment.ID, "RESTARTING"); err != nil {
+			return fmt.Errorf("update deployment status: %w", err)
+		}
+		deployment.Status = "RESTARTING"
+	}
+	w.recordStatusChange(ctx, deployment)
+	w.recordRestartLog(ctx, deployment, "INFO", "Rolling restart started")
+
+	restarted, err := w.restartPods(ctx, infra)
+	if err != nil {
+		logger.Error().Err(err).Msg("Rolling restart failed")
+		w.recordRestartLog(ctx, deployment, "ERROR", fmt.Sprintf("Rolling restart failed: %s", err))
+
+		deployment.Status = "UNHEALTHY"
+		deployment.Error = fmt.Sprintf("restart failed: %v", err)
+		if updateErr := w.engine.repo.UpdateDeployment(ctx, deployment); updateErr != nil {
+			logger.Error().
+				Err(updateErr).
+				Msg("Failed to update deployment with restart error")
+		}
+		w.recordStatusChange(ctx, deployment)
+
+		return fmt.Errorf("restart deployment: %w", err)
+	}
+
+	message := fmt.Sprintf("Restarted %s, all pods ready", strings.Join(restarted, ", "))
+	if len(restarted) == 0 {
+		message = "No running workloads to restart"
+	}
+	w.recordRestartLog(ctx, deployment, "INFO", message)
+
+	deployment.Status = "EXPOSED"
+	deployment.Error = ""
+	if err := w.engine.repo.UpdateDeployment(ctx, deployment); err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to update deployment after restart")
+		return fmt.Errorf("update deployment: %w", err)
+	}
+	w.recordStatusChange(ctx, deployment)
+
+	logger.Info().
+		Strs("workloads", restarted).
+		Msg("Restart job complete")
+	return nil
+}
+
+// restartPods patches the release's workloads to roll their pods and waits for the new pods to
+// become ready, returning the restarted workload names
+func (w *Worker) restartPods(ctx context.Context, infra *state.Infrastructure) ([]string, error) {
+	if infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+		return nil, fmt.Errorf("infrastructure has no running release to restart")
+	}
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		return nil, fmt.Errorf("create kubernetes client: %w", err)
+	}
+
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", infra.HelmReleaseName)
+	restarted, err := kubeClient.RolloutRestart(ctx, infra.KubeNamespace, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	if len(restarted) == 0 {
+		return restarted, nil
+	}
+
+	if err := kubeClient.WaitForPodsReady(ctx, infra.KubeNamespace, labelSelector, restartRolloutTimeout); err != nil {
+		return restarted, fmt.Errorf("wait for restarted pods: %w", err)
+	}
+
+	return restarted, nil
+}
+
+// recordRestartLog adds a RESTARTING entry to the deployment's logs
+func (w *Worker) recordRestartLog(ctx context.Context, deployment *state.Deployment, level, message string) {
+	if err := w.engine.repo.CreateDeploymentLog(ctx, &state.DeploymentLog{
+		DeploymentID: deployment.ID,
+		Phase:        "RESTARTING",
+		Level:        level,
+		Source:       "restart",
+		Message:      message,
+	}); err != nil {
+		w.logger.Warn().
+			Err(err).
+			Str("deployment_id", deployment.ID.String()).
+			Msg("Failed to record restart log")
+	}
+}
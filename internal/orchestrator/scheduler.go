@@ -0,0 +1,121 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+)
+
+// schedulerInterval is how often due scheduled deployments are looked up
+const schedulerInterval = time.Minute
+
+// Scheduler starts scheduled deployments once their rollout time has passed
+type Scheduler struct {
+	engine *Engine
+	logger zerolog.Logger
+}
+
+// NewScheduler creates a new deployment scheduler
+func NewScheduler(engine *Engine, logger zerolog.Logger) *Scheduler {
+	return &Scheduler{
+		engine: engine,
+		logger: logger.With().Str("component", "scheduler").Logger(),
+	}
+}
+
+// Start runs the scheduling loop until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.logger.Info().
+		Dur("interval", schedulerInterval).
+		Msg("Starting deployment scheduler")
+
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info().Msg("Deployment scheduler stopped")
+			return
+		case <-ticker.C:
+			if err := s.enqueueDue(ctx); err != nil {
+				s.logger.Error().Err(err).Msg("Failed to start scheduled deployments")
+			}
+		}
+	}
+}
+
+// enqueueDue enqueues the provision job of every scheduled deployment that is due
+func (s *Scheduler) enqueueDue(ctx context.Context) error {
+	deployments, err := s.engine.repo.GetDueScheduledDeployments(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("get scheduled deployments: %w", err)
+	}
+
+	for i := range deployments {
+		deployment := &deployments[i]
+
+		started, err := s.start(ctx, deployment)
+		if err != nil {
+			s.logger.Error().
+				Err(err).
+				Str("deployment_id", deployment.ID.String()).
+				Msg("Failed to start scheduled deployment")
+			continue
+		}
+		if !started {
+			s.logger.Debug().
+				Str("deployment_id", deployment.ID.String()).
+				Msg("Scheduled deployment already claimed")
+			continue
+		}
+
+		s.logger.Info().
+			Str("deployment_id", deployment.ID.String()).
+			Time("scheduled_at", *deployment.ScheduledAt).
+			Msg("Scheduled deployment started")
+	}
+
+	return nil
+}
+
+// start claims a scheduled deployment by marking it QUEUED and then enqueues its provision
+// job. It reports false, without enqueueing, when another scheduler claimed it first.
+// Deployments whose stored payload cannot be read are marked FAILED so they are not retried
+// every minute, and those whose job cannot be enqueued go back to PENDING.
+func (s *Scheduler) start(ctx context.Context, deployment *state.Deployment) (bool, error) {
+	claimed, err := s.engine.repo.ClaimScheduledDeployment(ctx, deployment.ID)
+	if err != nil {
+		return false, fmt.Errorf("claim deployment: %w", err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	var payload queue.ProvisionPayload
+	if err := json.Unmarshal([]byte(deployment.ScheduledJob), &payload); err != nil {
+		deployment.Status = "FAILED"
+		deployment.Error = "Invalid scheduled deployment: " + err.Error()
+		if updateErr := s.engine.repo.UpdateDeployment(ctx, deployment); updateErr != nil {
+			return false, fmt.Errorf("update deployment: %w", updateErr)
+		}
+		return false, fmt.Errorf("decode scheduled job: %w", err)
+	}
+
+	if err := s.engine.EnqueueProvisionJob(ctx, &payload); err != nil {
+		if revertErr := s.engine.repo.UpdateDeploymentStatus(ctx, deployment.ID, "PENDING"); revertErr != nil {
+			s.logger.Error().
+				Err(revertErr).
+				Str("deployment_id", deployment.ID.String()).
+				Msg("Failed to release scheduled deployment claim")
+		}
+		return false, fmt.Errorf("enqueue provision job: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,97 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/rs/zerolog"
+)
+
+// suppressionReminderInterval is how often admins are reminded of CVE suppressions about to
+// expire. Each reminder covers the suppressions expiring before the next one.
+const suppressionReminderInterval = 7 * 24 * time.Hour
+
+// SuppressionReminder notifies admins of CVE suppressions that expire within the week, so
+// accepted risks can be renewed or fixed before scans start failing on them again
+type SuppressionReminder struct {
+	engine *Engine
+	client *Client
+	logger zerolog.Logger
+}
+
+// NewSuppressionReminder creates a new weekly CVE suppression reminder
+func NewSuppressionReminder(engine *Engine, logger zerolog.Logger) *SuppressionReminder {
+	logger = logger.With().Str("component", "suppression-reminder").Logger()
+
+	return &SuppressionReminder{
+		engine: engine,
+		client: NewClient(engine.queue, logger),
+		logger: logger,
+	}
+}
+
+// Start sends reminders weekly until the context is cancelled
+func (s *SuppressionReminder) Start(ctx context.Context) {
+	s.logger.Info().
+		Dur("interval", suppressionReminderInterval).
+		Msg("Starting CVE suppression reminder")
+
+	ticker := time.NewTicker(suppressionReminderInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info().Msg("CVE suppression reminder stopped")
+			return
+		case <-ticker.C:
+			if err := s.remind(ctx); err != nil {
+				s.logger.Error().Err(err).Msg("Failed to send CVE suppression reminders")
+			}
+		}
+	}
+}
+
+// remind enqueues a notification for every suppression expiring before the next reminder
+func (s *SuppressionReminder) remind(ctx context.Context) error {
+	suppressions, err := s.engine.repo.ListExpiringCVESuppressions(ctx, time.Now().Add(suppressionReminderInterval))
+	if err != nil {
+		return fmt.Errorf("list expiring cve suppressions: %w", err)
+	}
+
+	for _, suppression := range suppressions {
+		deploymentID := ""
+		if suppression.DeploymentID != nil {
+			deploymentID = suppression.DeploymentID.String()
+		}
+
+		if err := s.client.TriggerNotification(ctx, &queue.NotifyPayload{
+			EventType:    "cve_suppression_expiring",
+			DeploymentID: deploymentID,
+			Message: fmt.Sprintf("Suppression of %s expires on %s",
+				suppression.CVEID, suppression.ExpiresAt.UTC().Format(time.RFC3339)),
+			Data: map[string]string{
+				"suppression_id": suppression.ID.String(),
+				"cve_id":         suppression.CVEID,
+				"expires_at":     suppression.ExpiresAt.UTC().Format(time.RFC3339),
+				"reason":         suppression.Reason,
+				"user_id":        suppression.UserID,
+			},
+		}); err != nil {
+			s.logger.Warn().
+				Err(err).
+				Str("suppression_id", suppression.ID.String()).
+				Msg("Failed to enqueue CVE suppression reminder")
+		}
+	}
+
+	if len(suppressions) > 0 {
+		s.logger.Info().
+			Int("count", len(suppressions)).
+			Msg("Sent CVE suppression reminders")
+	}
+
+	return nil
+}
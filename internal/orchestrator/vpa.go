@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+)
+
+// vpaRecommendationInterval is how often VPA recommendations are read from the clusters
+const vpaRecommendationInterval = 24 * time.Hour
+
+// VPARecommender records the resources each running deployment's Vertical Pod Autoscaler
+// recommends, so they can be reviewed and applied without querying the cluster
+type VPARecommender struct {
+	engine *Engine
+	logger zerolog.Logger
+}
+
+// NewVPARecommender creates a nightly VPA recommendation recorder
+func NewVPARecommender(engine *Engine, logger zerolog.Logger) *VPARecommender {
+	return &VPARecommender{
+		engine: engine,
+		logger: logger.With().Str("component", "vpa-recommender").Logger(),
+	}
+}
+
+// Start records recommendations once, then nightly until the context is cancelled
+func (v *VPARecommender) Start(ctx context.Context) {
+	v.logger.Info().
+		Dur("interval", vpaRecommendationInterval).
+		Msg("Starting VPA recommender")
+
+	if err := v.Record(ctx); err != nil {
+		v.logger.Error().Err(err).Msg("Failed to record VPA recommendations")
+	}
+
+	ticker := time.NewTicker(vpaRecommendationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			v.logger.Info().Msg("VPA recommender stopped")
+			return
+		case <-ticker.C:
+			if err := v.Record(ctx); err != nil {
+				v.logger.Error().Err(err).Msg("Failed to record VPA recommendations")
+			}
+		}
+	}
+}
+
+// Record reads the VPA recommendation of every running deployment on ready infrastructure
+func (v *VPARecommender) Record(ctx context.Context) error {
+	infras, err := v.engine.repo.ListInfrastructureByStatus(ctx, "READY")
+	if err != nil {
+		return fmt.Errorf("list ready infrastructure: %w", err)
+	}
+
+	recorded := 0
+	for _, infra := range infras {
+		if infra.HelmReleaseName == "" || infra.ClusterEndpoint == "" {
+			continue
+		}
+
+		ok, err := v.recordDeployment(ctx, infra)
+		if err != nil && !errors.Is(err, deployer.ErrNoVPARecommendation) {
+			v.logger.Warn().
+				Err(err).
+				Str("deployment_id", infra.DeploymentID.String()).
+				Msg("Failed to record VPA recommendation")
+		}
+		if ok {
+			recorded++
+		}
+	}
+
+	v.logger.Info().
+		Int("recorded", recorded).
+		Msg("VPA recommendations recorded")
+
+	return nil
+}
+
+// recordDeployment stores the recommendation for the deployment running on infra, reporting
+// whether one was recorded. Deployments that are not running are skipped.
+func (v *VPARecommender) recordDeployment(ctx context.Context, infra *state.Infrastructure) (bool, error) {
+	deployment, err := v.engine.repo.GetDeploymentByID(ctx, infra.DeploymentID)
+	if err != nil {
+		return false, fmt.Errorf("get deployment: %w", err)
+	}
+
+	switch deployment.Status {
+	case "EXPOSED", "HEALTHY", "UNHEALTHY":
+	default:
+		return false, nil
+	}
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		return false, fmt.Errorf("create kubernetes client: %w", err)
+	}
+
+	labelSelector := fmt.Sprintf("app.kubernetes.io/instance=%s", infra.HelmReleaseName)
+	recommendation, err := kubeClient.GetVPARecommendation(ctx, infra.KubeNamespace, labelSelector)
+	if err != nil {
+		return false, err
+	}
+
+	if err := v.engine.repo.UpdateVPARecommendation(ctx, deployment.ID, recommendation); err != nil {
+		return false, err
+	}
+	return true, nil
+}
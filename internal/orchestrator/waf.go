@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/security"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// backendServiceTimeout bounds the wait for GKE to create a LoadBalancer's backend service
+const backendServiceTimeout = 2 * time.Minute
+
+// attachWAF attaches the deployment's Cloud Armor policy to the backend service of its freshly
+// exposed LoadBalancer, then adds, updates or removes the policy's rate limit rule for the
+// deployment. The app is already serving, so failures are recorded on the infrastructure and
+// in the deployment's logs instead of failing the deploy.
+func (w *Worker) attachWAF(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure, result *deployer.DeployResult, rateLimit *deployer.RateLimitConfig) {
+	logger := w.logger.With().
+		Str("deployment_id", deployment.ID.String()).
+		Logger()
+
+	policy, backendService, err := w.attachSecurityPolicy(ctx, deployment, infra, result)
+
+	wafError, level, message := "", "INFO", fmt.Sprintf("Cloud Armor policy %s attached to %s", policy, backendService)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to attach Cloud Armor policy")
+		policy, wafError = "", err.Error()
+		level, message = "ERROR", fmt.Sprintf("Failed to attach Cloud Armor policy: %s", err)
+	}
+
+	if err := w.engine.repo.UpdateInfrastructureWAF(ctx, infra.ID, policy, backendService, wafError); err != nil {
+		logger.Warn().Err(err).Msg("Failed to record Cloud Armor policy")
+	}
+
+	w.recordWAFLog(ctx, deployment, level, message)
+
+	if err != nil || (rateLimit == nil && infra.RateLimitRuleName == "") {
+		return
+	}
+
+	var rule *security.RateLimitRule
+	if rateLimit != nil {
+		rule = &security.RateLimitRule{
+			Name:                   security.RateLimitRuleName(deployment.ID.String()),
+			RequestsPerMinutePerIP: rateLimit.RequestsPerMinutePerIP,
+			EnforceOnURIPaths:      rateLimit.EnforceOnURIPaths,
+		}
+	}
+
+	ruleName, err := security.ApplyRateLimitRule(ctx, w.wafProject(infra), policy,
+		security.BackendServiceRegion(backendService), infra.RateLimitRuleName, rule)
+
+	level, message = "INFO", fmt.Sprintf("Cloud Armor rate limit rule %s removed from %s", infra.RateLimitRuleName, policy)
+	if rule != nil {
+		message = fmt.Sprintf("Cloud Armor rate limit of %d requests per minute per IP applied to %s",
+			rule.RequestsPerMinutePerIP, policy)
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to apply Cloud Armor rate limit rule")
+		level, message = "ERROR", fmt.Sprintf("Failed to apply Cloud Armor rate limit rule: %s", err)
+	}
+
+	if err := w.engine.repo.UpdateInfrastructureRateLimitRule(ctx, infra.ID, ruleName); err != nil {
+		logger.Warn().Err(err).Msg("Failed to record Cloud Armor rate limit rule")
+	}
+
+	w.recordWAFLog(ctx, deployment, level, message)
+}
+
+// recordWAFLog records a Cloud Armor outcome in the deployment's logs
+func (w *Worker) recordWAFLog(ctx context.Context, deployment *state.Deployment, level, message string) {
+	if err := w.engine.repo.CreateDeploymentLog(ctx, &state.DeploymentLog{
+		DeploymentID: deployment.ID,
+		Phase:        deployment.Status,
+		Level:        level,
+		Source:       "waf",
+		Message:      message,
+	}); err != nil {
+		w.logger.Warn().
+			Err(err).
+			Str("deployment_id", deployment.ID.String()).
+			Msg("Failed to record Cloud Armor log")
+	}
+}
+
+// removeRateLimitRule deletes the Cloud Armor rate limit rule of a deployment being destroyed.
+// The policy may protect other deployments' load balancers, so it is left in place.
+func (w *Worker) removeRateLimitRule(ctx context.Context, infra *state.Infrastructure) error {
+	if err := security.DeleteRateLimitRule(ctx, w.wafProject(infra), infra.WAFPolicyName,
+		security.BackendServiceRegion(infra.WAFBackendService), infra.RateLimitRuleName); err != nil {
+		return err
+	}
+
+	if err := w.engine.repo.UpdateInfrastructureRateLimitRule(ctx, infra.ID, ""); err != nil {
+		return fmt.Errorf("update infrastructure rate limit rule: %w", err)
+	}
+
+	return nil
+}
+
+// wafProject returns the project of an infrastructure's load balancer
+func (w *Worker) wafProject(infra *state.Infrastructure) string {
+	if infra.ImportedExternally {
+		return infra.GCPProject
+	}
+	return w.engine.gcpProject
+}
+
+// attachSecurityPolicy resolves the deployment's policy and backend service and attaches one to
+// the other, returning both
+func (w *Worker) attachSecurityPolicy(ctx context.Context, deployment *state.Deployment, infra *state.Infrastructure, result *deployer.DeployResult) (string, string, error) {
+	policy := deployment.CloudArmorPolicy
+	if policy == "" || policy == "default" {
+		policy = w.engine.defaultWAFPolicy
+		if policy == "" {
+			return "", "", fmt.Errorf("no default Cloud Armor policy is configured")
+		}
+	}
+
+	project := w.wafProject(infra)
+
+	kubeClient, err := deployer.NewKubeClient(infra)
+	if err != nil {
+		return policy, "", fmt.Errorf("create kubernetes client: %w", err)
+	}
+
+	// The Helm chart names the Service after the release
+	backendService, err := kubeClient.GetBackendService(ctx, result.Namespace, result.ReleaseName,
+		infra.ClusterLocation, backendServiceTimeout)
+	if err != nil {
+		return policy, "", fmt.Errorf("get backend service: %w", err)
+	}
+
+	if err := security.AttachSecurityPolicy(ctx, project, backendService, policy); err != nil {
+		return policy, backendService, err
+	}
+
+	return policy, backendService, nil
+}
@@ -0,0 +1,209 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"github.com/rs/zerolog"
+)
+
+// stuckGracePeriod is added to each expected timeout before a deployment is considered stuck
+const stuckGracePeriod = 5 * time.Minute
+
+// WatchdogConfig holds watchdog configuration
+type WatchdogConfig struct {
+	Interval         time.Duration // How often to scan for stuck deployments
+	ProvisionTimeout time.Duration // Expected upper bound for PROVISIONING
+	DeployTimeout    time.Duration // Expected upper bound for DEPLOYING
+	DestroyTimeout   time.Duration // Expected upper bound for DESTROYING
+}
+
+// Watchdog detects deployments stuck in transient states (e.g. after a worker crash)
+// and re-enqueues the job that should move them forward
+type Watchdog struct {
+	engine *Engine
+	config WatchdogConfig
+	logger zerolog.Logger
+}
+
+// NewWatchdog creates a new stuck deployment watchdog
+func NewWatchdog(engine *Engine, config WatchdogConfig, logger zerolog.Logger) *Watchdog {
+	if config.ProvisionTimeout == 0 {
+		config.ProvisionTimeout = 30 * time.Minute
+	}
+
+	if config.DeployTimeout == 0 {
+		config.DeployTimeout = 10 * time.Minute
+	}
+
+	if config.DestroyTimeout == 0 {
+		config.DestroyTimeout = config.ProvisionTimeout
+	}
+
+	return &Watchdog{
+		engine: engine,
+		config: config,
+		logger: logger.With().Str("component", "watchdog").Logger(),
+	}
+}
+
+// Start runs the watchdog loop until the context is cancelled
+func (wd *Watchdog) Start(ctx context.Context) {
+	if wd.config.Interval <= 0 {
+		wd.logger.Info().Msg("Stuck deployment watchdog disabled")
+		return
+	}
+
+	wd.logger.Info().
+		Dur("interval", wd.config.Interval).
+		Dur("provision_timeout", wd.config.ProvisionTimeout).
+		Dur("deploy_timeout", wd.config.DeployTimeout).
+		Msg("Starting stuck deployment watchdog")
+
+	ticker := time.NewTicker(wd.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wd.logger.Info().Msg("Stuck deployment watchdog stopped")
+			return
+		case <-ticker.C:
+			wd.check(ctx)
+		}
+	}
+}
+
+// check scans every transient status for stuck deployments and attempts recovery
+func (wd *Watchdog) check(ctx context.Context) {
+	timeouts := map[string]time.Duration{
+		"PROVISIONING": wd.config.ProvisionTimeout,
+		"DEPLOYING":    wd.config.DeployTimeout,
+		"DESTROYING":   wd.config.DestroyTimeout,
+	}
+
+	for status, timeout := range timeouts {
+		since := time.Now().Add(-(timeout + stuckGracePeriod))
+
+		deployments, err := wd.engine.repo.GetStuckDeployments(ctx, status, since)
+		if err != nil {
+			wd.logger.Error().
+				Err(err).
+				Str("status", status).
+				Msg("Failed to query stuck deployments")
+			continue
+		}
+
+		for i := range deployments {
+			deployment := &deployments[i]
+
+			// Reset the progress clock first, so the same deployment is not re-enqueued on the
+			// next scan or by another watchdog scanning at the same time
+			claimed, err := wd.engine.repo.ClaimStuckDeployment(ctx, deployment.ID, deployment.Status, deployment.UpdatedAt)
+			if err != nil {
+				wd.logger.Error().
+					Err(err).
+					Str("deployment_id", deployment.ID.String()).
+					Msg("Failed to claim stuck deployment")
+				continue
+			}
+			if !claimed {
+				wd.logger.Debug().
+					Str("deployment_id", deployment.ID.String()).
+					Msg("Stuck deployment changed or already claimed")
+				continue
+			}
+
+			wd.logger.Warn().
+				Str("deployment_id", deployment.ID.String()).
+				Str("status", deployment.Status).
+				Time("last_progress_at", deployment.LastProgressAt).
+				Msg("Deployment appears stuck, re-enqueueing job")
+
+			if err := wd.recover(ctx, deployment); err != nil {
+				wd.logger.Error().
+					Err(err).
+					Str("deployment_id", deployment.ID.String()).
+					Msg("Failed to recover stuck deployment")
+			}
+		}
+	}
+}
+
+// recover re-enqueues the job matching a stuck deployment's status
+func (wd *Watchdog) recover(ctx context.Context, deployment *state.Deployment) error {
+	deploymentID := deployment.ID.String()
+
+	switch deployment.Status {
+	case "PROVISIONING":
+		if err := wd.engine.EnqueueProvisionJob(ctx, storedProvisionPayload(deployment)); err != nil {
+			return fmt.Errorf("enqueue provision job: %w", err)
+		}
+
+	case "DEPLOYING":
+		infra, err := wd.engine.repo.GetInfrastructure(ctx, deployment.ID)
+		if err != nil {
+			return fmt.Errorf("get infrastructure: %w", err)
+		}
+
+		// Before the first rollout there are no deployed values, so fall back to the
+		// replicas the deployment was provisioned with
+		replicas := deployedReplicas(infra)
+		if replicas == 0 {
+			replicas = storedProvisionPayload(deployment).Replicas
+		}
+
+		if err := wd.engine.EnqueueDeployJob(ctx, &queue.DeployPayload{
+			DeploymentID:     deploymentID,
+			InfrastructureID: infra.ID.String(),
+			ImageTag:         deployment.ImageTag,
+			Port:             deployment.Port,
+			Replicas:         replicas,
+		}); err != nil {
+			return fmt.Errorf("enqueue deploy job: %w", err)
+		}
+
+	case "DESTROYING":
+		infra, err := wd.engine.repo.GetInfrastructure(ctx, deployment.ID)
+		if err != nil {
+			return fmt.Errorf("get infrastructure: %w", err)
+		}
+
+		if err := wd.engine.EnqueueDestroyJob(ctx, &queue.DestroyPayload{
+			DeploymentID:     deploymentID,
+			InfrastructureID: infra.ID.String(),
+		}); err != nil {
+			return fmt.Errorf("enqueue destroy job: %w", err)
+		}
+
+	default:
+		return fmt.Errorf("no recovery action for status: %s", deployment.Status)
+	}
+
+	return nil
+}
+
+// storedProvisionPayload rebuilds the provision job last started for a deployment, with its
+// addons, replicas and autoscaling. Deployments recorded before provision payloads were kept
+// get one built from their own fields, with default settings.
+func storedProvisionPayload(deployment *state.Deployment) *queue.ProvisionPayload {
+	payload := &queue.ProvisionPayload{}
+	if deployment.ProvisionPayload != "" {
+		if err := json.Unmarshal([]byte(deployment.ProvisionPayload), payload); err != nil {
+			payload = &queue.ProvisionPayload{}
+		}
+	}
+
+	payload.DeploymentID = deployment.ID.String()
+	payload.AppName = deployment.AppName
+	payload.Version = deployment.Version
+	payload.Cloud = deployment.Cloud
+	payload.Region = deployment.Region
+	payload.ImageTag = deployment.ImageTag
+
+	return payload
+}
@@ -4,165 +4,351 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alvesdmateus/app-deployer/internal/queue"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
-// Worker processes jobs from the queue with configurable concurrency
+const (
+	// pausedJobDelay is how long a job for a paused deployment waits before it is checked again
+	pausedJobDelay = 30 * time.Second
+
+	// delayedJobPollInterval is how often delayed jobs that are due are moved onto their queues
+	delayedJobPollInterval = 5 * time.Second
+
+	// defaultDrainTimeout is how long in-flight jobs may run on after shutdown starts
+	defaultDrainTimeout = 30 * time.Second
+)
+
+// jobTypes are the queues the worker takes jobs from, in round-robin order
+var jobTypes = queue.JobTypes
+
+// Worker processes jobs from the queue, running as many at once as its concurrency manager allows
 type Worker struct {
-	engine      *Engine
-	concurrency int
-	pollTimeout time.Duration
-	logger      zerolog.Logger
+	engine       *Engine
+	pollTimeout  time.Duration
+	drainTimeout time.Duration
+	logger       zerolog.Logger
+
+	// drainMu orders starting a job against the start of draining, so no job is added to
+	// activeJobs once it is being waited on
+	drainMu    sync.Mutex
+	draining   bool
+	activeJobs sync.WaitGroup
+	running    atomic.Int64 // Jobs in activeJobs, reported in heartbeats
 }
 
-// NewWorker creates a new worker
-func NewWorker(engine *Engine, concurrency int, logger zerolog.Logger) *Worker {
-	if concurrency < 1 {
-		concurrency = 1
+// NewWorker creates a new worker. Once its context is cancelled, in-flight jobs get up to
+// drainTimeout to finish; 0 uses the default of 30 seconds.
+func NewWorker(engine *Engine, drainTimeout time.Duration, logger zerolog.Logger) *Worker {
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
 	}
 
 	return &Worker{
-		engine:      engine,
-		concurrency: concurrency,
-		pollTimeout: 5 * time.Second, // Blocking poll timeout
-		logger:      logger.With().Str("component", "worker").Logger(),
+		engine:       engine,
+		pollTimeout:  5 * time.Second, // Blocking poll timeout
+		drainTimeout: drainTimeout,
+		logger:       logger.With().Str("component", "worker").Logger(),
 	}
 }
 
-// Start starts the worker with N concurrent job processors
-func (w *Worker) Start(ctx context.Context) error {
+// ActiveJobs returns how many jobs the worker is running
+func (w *Worker) ActiveJobs() int {
+	return int(w.running.Load())
+}
+
+// Draining reports whether the worker has stopped taking jobs to shut down
+func (w *Worker) Draining() bool {
+	w.drainMu.Lock()
+	defer w.drainMu.Unlock()
+	return w.draining
+}
+
+// Start starts the worker with a job processor for each slot pool can grant; each blocks on
+// pool for a slot before taking a job
+func (w *Worker) Start(ctx context.Context, pool *DynamicConcurrencyManager) error {
 	w.logger.Info().
-		Int("concurrency", w.concurrency).
+		Int("concurrency", pool.Concurrency()).
+		Int("max_concurrency", pool.MaxConcurrency()).
 		Msg("Starting orchestrator worker")
 
+	// Jobs outlive ctx so that shutting down lets them finish; they are only cancelled once
+	// the drain timeout passes
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
 	var wg sync.WaitGroup
 
-	// Start N worker goroutines
-	for i := 0; i < w.concurrency; i++ {
+	// Start a worker goroutine per slot the pool may grow to
+	for i := 0; i < pool.MaxConcurrency(); i++ {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-			w.processJobs(ctx, workerID)
+			w.processJobs(ctx, jobCtx, pool, workerID)
 		}(i)
 	}
 
-	// Wait for all workers to finish (when context is cancelled)
+	// Tune the pool to queue depth
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pool.Start(ctx)
+	}()
+
+	// Move delayed jobs onto their queues once they are due
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.promoteDelayedJobs(ctx)
+	}()
+
+	<-ctx.Done()
+	w.drain(cancelJobs)
+
+	// Wait for all workers to finish
 	wg.Wait()
 
 	w.logger.Info().Msg("Orchestrator worker stopped")
 	return nil
 }
 
-// processJobs is the main worker loop that processes jobs from the queue
-func (w *Worker) processJobs(ctx context.Context, workerID int) {
+// drain stops new jobs from starting and waits up to the drain timeout for in-flight ones,
+// cancelling those still running after it
+func (w *Worker) drain(cancelJobs context.CancelFunc) {
+	w.drainMu.Lock()
+	w.draining = true
+	w.drainMu.Unlock()
+
+	w.logger.Info().Dur("drain_timeout", w.drainTimeout).Msg("Draining worker, waiting for in-flight jobs")
+
+	done := make(chan struct{})
+	go func() {
+		w.activeJobs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.logger.Info().Msg("In-flight jobs finished")
+	case <-time.After(w.drainTimeout):
+		w.logger.Warn().
+			Dur("drain_timeout", w.drainTimeout).
+			Msg("Jobs still running after drain timeout, cancelling them")
+		cancelJobs()
+		<-done
+	}
+}
+
+// startJob registers a job as in flight, unless the worker is draining
+func (w *Worker) startJob() bool {
+	w.drainMu.Lock()
+	defer w.drainMu.Unlock()
+
+	if w.draining {
+		return false
+	}
+
+	w.activeJobs.Add(1)
+	w.running.Add(1)
+	return true
+}
+
+// processJobs is the main worker loop that processes jobs from the queue, holding a slot
+// from pool while it takes and runs each one. Jobs are taken until ctx is cancelled and run
+// with jobCtx, which outlives it while the worker drains.
+func (w *Worker) processJobs(ctx, jobCtx context.Context, pool *DynamicConcurrencyManager, workerID int) {
 	logger := w.logger.With().Int("worker_id", workerID).Logger()
 	logger.Info().Msg("Worker goroutine started")
 
 	// Round-robin between job types for fair processing
-	jobTypes := []queue.JobType{
-		queue.JobTypeProvision,
-		queue.JobTypeDeploy,
-		queue.JobTypeDestroy,
-		queue.JobTypeRollback,
-	}
 	currentTypeIndex := 0
 
 	for {
-		select {
-		case <-ctx.Done():
+		if !pool.Acquire(ctx) {
 			logger.Info().Msg("Worker goroutine stopped (context cancelled)")
 			return
-		default:
-			// Try to dequeue from current job type
-			jobType := jobTypes[currentTypeIndex]
-			job, err := w.engine.queue.Dequeue(ctx, jobType, w.pollTimeout)
+		}
 
-			if err != nil {
-				// Log non-timeout errors
-				if err.Error() != "redis: nil" {
-					logger.Error().
-						Err(err).
-						Str("job_type", string(jobType)).
-						Msg("Failed to dequeue job")
-				}
-
-				// Move to next job type for round-robin
-				currentTypeIndex = (currentTypeIndex + 1) % len(jobTypes)
-				continue
+		// Try to dequeue from current job type
+		jobType := jobTypes[currentTypeIndex]
+		job, err := w.engine.queue.Dequeue(ctx, jobType, w.pollTimeout)
+
+		if err != nil {
+			pool.Release()
+
+			// Log non-timeout errors
+			if err.Error() != "redis: nil" {
+				logger.Error().
+					Err(err).
+					Str("job_type", string(jobType)).
+					Msg("Failed to dequeue job")
 			}
 
-			if job == nil {
-				// No job available, move to next job type
-				currentTypeIndex = (currentTypeIndex + 1) % len(jobTypes)
-				continue
+			// Move to next job type for round-robin
+			currentTypeIndex = (currentTypeIndex + 1) % len(jobTypes)
+			continue
+		}
+
+		if job == nil {
+			pool.Release()
+
+			// No job available, move to next job type
+			currentTypeIndex = (currentTypeIndex + 1) % len(jobTypes)
+			continue
+		}
+
+		// A job taken just as draining began goes back on its queue for another worker
+		if !w.startJob() {
+			if err := w.engine.queue.Enqueue(jobCtx, job); err != nil {
+				logger.Error().
+					Err(err).
+					Str("job_id", job.ID).
+					Msg("Failed to requeue job while draining")
 			}
+			pool.Release()
+			return
+		}
+
+		w.runJob(jobCtx, logger, job)
+		pool.Release()
+
+		// Move to next job type for round-robin
+		currentTypeIndex = (currentTypeIndex + 1) % len(jobTypes)
+	}
+}
+
+// runJob processes a job taken off the queue, retrying or failing it when its handler fails.
+// The job was registered in activeJobs by startJob.
+func (w *Worker) runJob(ctx context.Context, logger zerolog.Logger, job *queue.Job) {
+	defer w.activeJobs.Done()
+	defer w.running.Add(-1)
+
+	// Hold jobs for paused deployments back until they are resumed
+	if w.isPaused(ctx, job) {
+		logger.Info().
+			Str("job_id", job.ID).
+			Str("job_type", string(job.Type)).
+			Str("deployment_id", job.DeploymentID).
+			Msg("Deployment is paused, delaying job")
+
+		if err := w.engine.queue.EnqueueDelayed(ctx, job, pausedJobDelay); err != nil {
+			logger.Error().
+				Err(err).
+				Str("job_id", job.ID).
+				Msg("Failed to delay job for paused deployment")
+		}
+		return
+	}
 
-			// Process the job
-			logger.Info().
+	w.engine.startStage(ctx, job)
+
+	// Process the job
+	logger.Info().
+		Str("job_id", job.ID).
+		Str("job_type", string(job.Type)).
+		Str("deployment_id", job.DeploymentID).
+		Int("attempt", job.Attempts).
+		Msg("Processing job")
+
+	if err := w.handleJob(ctx, job); err != nil {
+		logger.Error().
+			Err(err).
+			Str("job_id", job.ID).
+			Str("job_type", string(job.Type)).
+			Str("deployment_id", job.DeploymentID).
+			Msg("Job processing failed")
+
+		// Handle job retry or failure
+		if job.Attempts < job.MaxAttempts {
+			logger.Warn().
 				Str("job_id", job.ID).
-				Str("job_type", string(job.Type)).
-				Str("deployment_id", job.DeploymentID).
 				Int("attempt", job.Attempts).
-				Msg("Processing job")
+				Int("max_attempts", job.MaxAttempts).
+				Msg("Requeueing failed job for retry")
 
-			if err := w.handleJob(ctx, job); err != nil {
+			job.Attempts++
+			if requeueErr := w.engine.queue.Enqueue(ctx, job); requeueErr != nil {
 				logger.Error().
-					Err(err).
+					Err(requeueErr).
 					Str("job_id", job.ID).
-					Str("job_type", string(job.Type)).
-					Str("deployment_id", job.DeploymentID).
-					Msg("Job processing failed")
-
-				// Handle job retry or failure
-				if job.Attempts < job.MaxAttempts {
-					logger.Warn().
-						Str("job_id", job.ID).
-						Int("attempt", job.Attempts).
-						Int("max_attempts", job.MaxAttempts).
-						Msg("Requeueing failed job for retry")
-
-					job.Attempts++
-					if requeueErr := w.engine.queue.Enqueue(ctx, job); requeueErr != nil {
-						logger.Error().
-							Err(requeueErr).
-							Str("job_id", job.ID).
-							Msg("Failed to requeue job")
-					}
-				} else {
-					logger.Error().
-						Str("job_id", job.ID).
-						Int("attempts", job.Attempts).
-						Msg("Job failed after max attempts, marking deployment as failed")
-
-					// Mark job as permanently failed
-					if markErr := w.engine.queue.MarkFailed(ctx, job.ID, err); markErr != nil {
-						logger.Error().
-							Err(markErr).
-							Str("job_id", job.ID).
-							Msg("Failed to mark job as failed")
-					}
-				}
-			} else {
-				logger.Info().
+					Msg("Failed to requeue job")
+			}
+		} else {
+			logger.Error().
+				Str("job_id", job.ID).
+				Int("attempts", job.Attempts).
+				Msg("Job failed after max attempts, marking deployment as failed")
+
+			w.engine.failStage(ctx, job, err)
+
+			// Mark job as permanently failed
+			if markErr := w.engine.queue.MarkFailed(ctx, job.ID, err); markErr != nil {
+				logger.Error().
+					Err(markErr).
 					Str("job_id", job.ID).
-					Str("job_type", string(job.Type)).
-					Str("deployment_id", job.DeploymentID).
-					Msg("Job processed successfully")
-
-				// Mark job as complete
-				if err := w.engine.queue.MarkComplete(ctx, job.ID); err != nil {
-					logger.Error().
-						Err(err).
-						Str("job_id", job.ID).
-						Msg("Failed to mark job as complete")
-				}
+					Msg("Failed to mark job as failed")
 			}
+		}
+	} else {
+		logger.Info().
+			Str("job_id", job.ID).
+			Str("job_type", string(job.Type)).
+			Str("deployment_id", job.DeploymentID).
+			Msg("Job processed successfully")
 
-			// Move to next job type for round-robin
-			currentTypeIndex = (currentTypeIndex + 1) % len(jobTypes)
+		// Mark job as complete
+		if err := w.engine.queue.MarkComplete(ctx, job.ID); err != nil {
+			logger.Error().
+				Err(err).
+				Str("job_id", job.ID).
+				Msg("Failed to mark job as complete")
+		}
+	}
+}
+
+// isPaused reports whether a job belongs to a paused deployment. Destroy jobs always run so
+// that paused deployments can still be deleted, and notifications are not held back.
+func (w *Worker) isPaused(ctx context.Context, job *queue.Job) bool {
+	switch job.Type {
+	case queue.JobTypeDestroy, queue.JobTypeDestroyStack, queue.JobTypeNotify:
+		return false
+	}
+
+	deploymentID, err := uuid.Parse(job.DeploymentID)
+	if err != nil {
+		return false
+	}
+
+	deployment, err := w.engine.repo.GetDeploymentByID(ctx, deploymentID)
+	if err != nil {
+		return false
+	}
+
+	return deployment.Paused
+}
+
+// promoteDelayedJobs periodically moves delayed jobs that are due onto their queues
+func (w *Worker) promoteDelayedJobs(ctx context.Context) {
+	ticker := time.NewTicker(delayedJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			promoted, err := w.engine.queue.PromoteDelayedJobs(ctx)
+			if err != nil {
+				w.logger.Error().Err(err).Msg("Failed to promote delayed jobs")
+				continue
+			}
+			if promoted > 0 {
+				w.logger.Debug().Int("count", promoted).Msg("Promoted delayed jobs")
+			}
 		}
 	}
 }
@@ -178,6 +364,20 @@ func (w *Worker) handleJob(ctx context.Context, job *queue.Job) error {
 		return w.handleDestroyJob(ctx, job)
 	case queue.JobTypeRollback:
 		return w.handleRollbackJob(ctx, job)
+	case queue.JobTypeReconcile:
+		return w.handleReconcileJob(ctx, job)
+	case queue.JobTypeUpdateInfra:
+		return w.handleUpdateInfraJob(ctx, job)
+	case queue.JobTypeMigrateBackend:
+		return w.handleMigrateBackendJob(ctx, job)
+	case queue.JobTypeDestroyStack:
+		return w.handleDestroyStackJob(ctx, job)
+	case queue.JobTypeNotify:
+		return w.handleNotifyJob(ctx, job)
+	case queue.JobTypeBuild:
+		return w.handleBuildJob(ctx, job)
+	case queue.JobTypeRestart:
+		return w.handleRestartJob(ctx, job)
 	default:
 		return fmt.Errorf("unknown job type: %s", job.Type)
 	}
@@ -0,0 +1,21 @@
+package orchestrator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/alvesdmateus/app-deployer/internal/queue"
+)
+
+func TestWorker_JobTypesHaveQueueSubscriptions(t *testing.T) {
+	// Queues create a topic and subscription for each of queue.JobTypes only
+	for _, jobType := range jobTypes {
+		if !slices.Contains(queue.JobTypes, jobType) {
+			t.Errorf("Worker takes %s jobs, but no queue subscription is created for them", jobType)
+		}
+	}
+
+	if !slices.Contains(jobTypes, queue.JobTypeRestart) {
+		t.Errorf("Worker does not take %s jobs", queue.JobTypeRestart)
+	}
+}
@@ -0,0 +1,177 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AdmitQuery is the document deployment requests are admitted by. Each message in its deny set
+// rejects the request.
+const AdmitQuery = "data.deployer.admit"
+
+// PolicyFile is a .rego file in the policy directory
+type PolicyFile struct {
+	Name       string // Path relative to the policy directory
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// Decision is the outcome of evaluating a request against the policies
+type Decision struct {
+	Deny []string
+}
+
+// Allowed reports whether no policy denied the request
+func (d *Decision) Allowed() bool {
+	return len(d.Deny) == 0
+}
+
+// opaEvalOutput is the JSON written by opa eval; an undefined query has no result
+type opaEvalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// OPAEvaluator evaluates Rego policies with the opa CLI. Policies are read from the policy
+// directory on every evaluation, so edits apply without a restart.
+type OPAEvaluator struct {
+	policyDir string
+}
+
+// NewOPAEvaluator creates a new evaluator for the .rego files under policyDir, which must
+// compile. The opa CLI must be installed.
+func NewOPAEvaluator(policyDir string) (*OPAEvaluator, error) {
+	if _, err := exec.LookPath("opa"); err != nil {
+		return nil, fmt.Errorf("opa CLI not found: %w (please install opa)", err)
+	}
+
+	info, err := os.Stat(policyDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("policy directory %s is not a directory", policyDir)
+	}
+
+	cmd := exec.Command("opa", "check", policyDir)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("invalid policies: %w, output: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	e := &OPAEvaluator{
+		policyDir: policyDir,
+	}
+
+	policies, err := e.Policies()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("policyDir", policyDir).
+		Int("policies", len(policies)).
+		Msg("OPA policies loaded")
+
+	return e, nil
+}
+
+// PolicyDir returns the directory policies are read from
+func (e *OPAEvaluator) PolicyDir() string {
+	return e.policyDir
+}
+
+// Policies lists the .rego files under the policy directory, sorted by path
+func (e *OPAEvaluator) Policies() ([]PolicyFile, error) {
+	var policies []PolicyFile
+
+	err := filepath.WalkDir(e.policyDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".rego" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(e.policyDir, path)
+		if err != nil {
+			return err
+		}
+
+		policies = append(policies, PolicyFile{
+			Name:       filepath.ToSlash(name),
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// Evaluate evaluates query against the policies with input as the input document. The query's
+// value must be an object; the messages in its deny field make up the decision. An undefined
+// query denies nothing.
+func (e *OPAEvaluator) Evaluate(ctx context.Context, query string, input interface{}) (*Decision, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "opa", "eval", "--format", "json", "--data", e.policyDir, "--stdin-input", query)
+	cmd.Stdin = bytes.NewReader(data)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("opa eval failed: %w, output: %s", err, string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("opa eval failed: %w", err)
+	}
+
+	var result opaEvalOutput
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	decision := &Decision{}
+	if len(result.Result) == 0 || len(result.Result[0].Expressions) == 0 {
+		return decision, nil
+	}
+
+	var value struct {
+		Deny []interface{} `json:"deny"`
+	}
+	if err := json.Unmarshal(result.Result[0].Expressions[0].Value, &value); err != nil {
+		return nil, fmt.Errorf("%s must be an object: %w", query, err)
+	}
+
+	for _, message := range value.Deny {
+		if s, ok := message.(string); ok {
+			decision.Deny = append(decision.Deny, s)
+			continue
+		}
+		encoded, _ := json.Marshal(message)
+		decision.Deny = append(decision.Deny, string(encoded))
+	}
+
+	return decision, nil
+}
@@ -0,0 +1,46 @@
+package provisioner
+
+import "fmt"
+
+const (
+	// AddonTypeCloudSQL provisions a managed Cloud SQL Postgres instance
+	AddonTypeCloudSQL = "cloudsql"
+
+	// AddonTypeMemorystore provisions a managed Memorystore Redis instance
+	AddonTypeMemorystore = "memorystore"
+)
+
+// AddonConfig describes an optional managed service provisioned alongside the cluster
+type AddonConfig struct {
+	Type   string                 `json:"type"`
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// ValidateAddons checks that every requested addon is of a supported type and requested at most once
+func ValidateAddons(addons []AddonConfig) error {
+	seen := make(map[string]bool)
+	for _, addon := range addons {
+		switch addon.Type {
+		case AddonTypeCloudSQL, AddonTypeMemorystore:
+		default:
+			return fmt.Errorf("unsupported addon type: %s", addon.Type)
+		}
+
+		if seen[addon.Type] {
+			return fmt.Errorf("addon %s specified more than once", addon.Type)
+		}
+		seen[addon.Type] = true
+	}
+
+	return nil
+}
+
+// FindAddon returns the addon of the given type, or nil if it was not requested
+func FindAddon(addons []AddonConfig, addonType string) *AddonConfig {
+	for i := range addons {
+		if addons[i].Type == addonType {
+			return &addons[i]
+		}
+	}
+	return nil
+}
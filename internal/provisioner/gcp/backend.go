@@ -0,0 +1,126 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/rs/zerolog/log"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+)
+
+// backendFor returns the state backend a stack lives in. Stacks moved with MigrateBackend
+// keep their new backend; all others use the configured one.
+func (p *GCPProvisioner) backendFor(ctx context.Context, stackName string) string {
+	if backendURL := p.tracker.GetStackBackend(ctx, stackName); backendURL != "" {
+		return backendURL
+	}
+	return p.backendURL
+}
+
+// MigrateBackend exports a stack's state and config from one backend and imports them into
+// a stack of the same name in another. The source stack is left in place so a failed import
+// can be retried; both backends must use the same secrets provider for the state to decrypt.
+func (p *GCPProvisioner) MigrateBackend(ctx context.Context, req *provisioner.BackendMigrationRequest) error {
+	fromBackend := req.FromBackend
+	if fromBackend == "" {
+		fromBackend = p.backendFor(ctx, req.StackName)
+	}
+
+	log.Info().
+		Str("stackName", req.StackName).
+		Str("fromBackend", fromBackend).
+		Str("toBackend", req.ToBackend).
+		Msg("Migrating Pulumi stack to a new backend")
+
+	if fromBackend == req.ToBackend {
+		return fmt.Errorf("stack %s is already in backend %s", req.StackName, req.ToBackend)
+	}
+
+	// Neither stack's program is run, only their state is read and written
+	program := pulumi.RunFunc(func(ctx *pulumi.Context) error {
+		return nil
+	})
+
+	source, err := auto.SelectStackInlineSource(ctx, req.StackName, p.projectName, program,
+		auto.Project(p.backendProject(fromBackend)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to select stack in source backend: %w", err)
+	}
+
+	deployment, err := source.Export(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to export stack: %w", err)
+	}
+
+	config, err := source.GetAllConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read stack config: %w", err)
+	}
+
+	target, err := auto.UpsertStackInlineSource(ctx, req.StackName, p.projectName, program,
+		auto.Project(p.backendProject(req.ToBackend)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create stack in target backend: %w", err)
+	}
+
+	// Later updates rebuild the program from the recorded provision request
+	if err := target.SetAllConfig(ctx, config); err != nil {
+		return fmt.Errorf("failed to write stack config: %w", err)
+	}
+
+	if err := target.Import(ctx, deployment); err != nil {
+		return fmt.Errorf("failed to import stack: %w", err)
+	}
+
+	log.Info().
+		Str("stackName", req.StackName).
+		Str("toBackend", req.ToBackend).
+		Msg("Pulumi stack migrated")
+
+	return nil
+}
+
+// ListStacks lists the names of the app-deployer stacks in the configured backend. Stacks
+// moved elsewhere with MigrateBackend are not included.
+func (p *GCPProvisioner) ListStacks(ctx context.Context) ([]string, error) {
+	ws, err := auto.NewLocalWorkspace(ctx, auto.Project(p.backendProject(p.backendURL)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	summaries, err := ws.ListStacks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stacks: %w", err)
+	}
+
+	names := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		// Some backends qualify names as organization/project/stack
+		name := summary.Name
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// backendProject describes the app-deployer Pulumi project stored in the given backend
+func (p *GCPProvisioner) backendProject(backendURL string) workspace.Project {
+	return workspace.Project{
+		Name:    tokens.PackageName(p.projectName),
+		Runtime: workspace.NewProjectRuntimeInfo("go", nil),
+		Backend: &workspace.ProjectBackend{
+			URL: backendURL,
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/projects"
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/servicenetworking"
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/sql"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+)
+
+// cloudSQLPort is the port Postgres listens on for Cloud SQL instances
+const cloudSQLPort = 5432
+
+// CloudSQLAddonConfig holds user-facing configuration for the cloudsql addon
+type CloudSQLAddonConfig struct {
+	Tier            string `json:"tier"`            // Default: db-f1-micro
+	DatabaseName    string `json:"databaseName"`    // Default: app
+	DatabaseVersion string `json:"databaseVersion"` // Default: POSTGRES_15
+	DiskSizeGb      int    `json:"diskSizeGb"`      // Default: 10
+}
+
+// CloudSQLRequest contains everything needed to create a Cloud SQL instance
+type CloudSQLRequest struct {
+	AppName        string
+	DeploymentID   string
+	Project        string
+	Region         string
+	Network        *compute.Network
+	ServiceAccount *serviceaccount.Account
+	Labels         pulumi.StringMap
+	Config         CloudSQLAddonConfig
+}
+
+// CloudSQLResources holds references to created Cloud SQL resources
+type CloudSQLResources struct {
+	PrivateIPRange *compute.GlobalAddress
+	Connection     *servicenetworking.Connection
+	Instance       *sql.DatabaseInstance
+	Database       *sql.Database
+	User           *sql.User
+	UserName       pulumi.StringOutput
+}
+
+// parseCloudSQLAddon converts a generic addon config into a CloudSQLAddonConfig with defaults applied
+func parseCloudSQLAddon(addon *provisioner.AddonConfig) (CloudSQLAddonConfig, error) {
+	var cfg CloudSQLAddonConfig
+
+	if len(addon.Config) > 0 {
+		data, err := json.Marshal(addon.Config)
+		if err != nil {
+			return cfg, fmt.Errorf("marshal cloudsql config: %w", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("unmarshal cloudsql config: %w", err)
+		}
+	}
+
+	if cfg.Tier == "" {
+		cfg.Tier = "db-f1-micro"
+	}
+	if cfg.DatabaseName == "" {
+		cfg.DatabaseName = "app"
+	}
+	if cfg.DatabaseVersion == "" {
+		cfg.DatabaseVersion = "POSTGRES_15"
+	}
+	if cfg.DiskSizeGb == 0 {
+		cfg.DiskSizeGb = 10
+	}
+
+	return cfg, nil
+}
+
+// CreateCloudSQLInstance creates a private-IP Cloud SQL Postgres instance reachable from the cluster VPC.
+// Authentication uses IAM: the GKE node service account is registered as a database user,
+// so no password is generated or stored.
+func CreateCloudSQLInstance(ctx *pulumi.Context, req *CloudSQLRequest) (*CloudSQLResources, error) {
+	instanceName := generateCloudSQLInstanceName(req.AppName, req.DeploymentID)
+
+	// Reserve an internal range in the VPC for Google-managed services
+	ipRange, err := compute.NewGlobalAddress(ctx, instanceName+"-range", &compute.GlobalAddressArgs{
+		Name:         pulumi.String(instanceName + "-range"),
+		Purpose:      pulumi.String("VPC_PEERING"),
+		AddressType:  pulumi.String("INTERNAL"),
+		PrefixLength: pulumi.Int(16),
+		Network:      req.Network.ID(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve private IP range: %w", err)
+	}
+
+	// Peer the VPC with the service producer network so the instance gets a private IP
+	connection, err := servicenetworking.NewConnection(ctx, instanceName+"-peering", &servicenetworking.ConnectionArgs{
+		Network:               req.Network.ID(),
+		Service:               pulumi.String("servicenetworking.googleapis.com"),
+		ReservedPeeringRanges: pulumi.StringArray{ipRange.Name},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service networking connection: %w", err)
+	}
+
+	instance, err := sql.NewDatabaseInstance(ctx, instanceName, &sql.DatabaseInstanceArgs{
+		Name:               pulumi.String(instanceName),
+		Region:             pulumi.String(req.Region),
+		DatabaseVersion:    pulumi.String(req.Config.DatabaseVersion),
+		DeletionProtection: pulumi.Bool(false), // Lifecycle is owned by the deployment
+		Settings: &sql.DatabaseInstanceSettingsArgs{
+			Tier:       pulumi.String(req.Config.Tier),
+			DiskSize:   pulumi.Int(req.Config.DiskSizeGb),
+			UserLabels: req.Labels,
+			IpConfiguration: &sql.DatabaseInstanceSettingsIpConfigurationArgs{
+				Ipv4Enabled:    pulumi.Bool(false),
+				PrivateNetwork: req.Network.SelfLink,
+			},
+			DatabaseFlags: sql.DatabaseInstanceSettingsDatabaseFlagArray{
+				&sql.DatabaseInstanceSettingsDatabaseFlagArgs{
+					Name:  pulumi.String("cloudsql.iam_authentication"),
+					Value: pulumi.String("on"),
+				},
+			},
+		},
+	}, pulumi.DependsOn([]pulumi.Resource{connection}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud SQL instance: %w", err)
+	}
+
+	database, err := sql.NewDatabase(ctx, instanceName+"-db", &sql.DatabaseArgs{
+		Name:     pulumi.String(req.Config.DatabaseName),
+		Instance: instance.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud SQL database: %w", err)
+	}
+
+	// IAM database users for service accounts omit the ".gserviceaccount.com" suffix
+	userName := req.ServiceAccount.Email.ApplyT(func(email string) string {
+		return strings.TrimSuffix(email, ".gserviceaccount.com")
+	}).(pulumi.StringOutput)
+
+	user, err := sql.NewUser(ctx, instanceName+"-user", &sql.UserArgs{
+		Name:     userName,
+		Instance: instance.Name,
+		Type:     pulumi.String("CLOUD_IAM_SERVICE_ACCOUNT"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud SQL user: %w", err)
+	}
+
+	// Allow the node service account to connect and log in
+	for _, role := range []string{"roles/cloudsql.client", "roles/cloudsql.instanceUser"} {
+		_, err := projects.NewIAMMember(ctx, fmt.Sprintf("%s-%s", instanceName, strings.TrimPrefix(role, "roles/cloudsql.")), &projects.IAMMemberArgs{
+			Project: pulumi.String(req.Project),
+			Role:    pulumi.String(role),
+			Member:  pulumi.Sprintf("serviceAccount:%s", req.ServiceAccount.Email),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to grant %s: %w", role, err)
+		}
+	}
+
+	return &CloudSQLResources{
+		PrivateIPRange: ipRange,
+		Connection:     connection,
+		Instance:       instance,
+		Database:       database,
+		User:           user,
+		UserName:       userName,
+	}, nil
+}
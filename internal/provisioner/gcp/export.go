@@ -0,0 +1,316 @@
+package gcp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alvesdmateus/app-deployer/internal/state"
+)
+
+// ErrNotExportable is returned for infrastructure records that hold no GCP resources to export
+var ErrNotExportable = errors.New("infrastructure cannot be exported")
+
+// exportResource is one provisioned resource as written to an exported configuration
+type exportResource struct {
+	name       string // Logical name in the exported configuration, e.g. vpc
+	tfType     string // e.g. google_compute_network
+	pulumiType string // e.g. gcp:compute:Network
+	importID   string // ID both Terraform and Pulumi import the resource by
+	attributes []exportAttribute
+	blocks     []exportBlock
+}
+
+// exportAttribute is a resource argument, keyed by its Terraform name. Pulumi names are the
+// camel-cased Terraform ones.
+type exportAttribute struct {
+	key   string
+	value interface{} // string, bool, int or exportReference
+}
+
+// exportReference refers to an attribute of another exported resource
+type exportReference struct {
+	resource  *exportResource
+	attribute string
+}
+
+// exportBlock is a nested block; repeated blocks become a list under pulumiKey in Pulumi
+type exportBlock struct {
+	key        string
+	pulumiKey  string
+	repeated   bool
+	attributes []exportAttribute
+}
+
+// ExportTerraform renders the provisioned cluster, VPC, subnet and service accounts of the
+// infrastructure as Terraform import blocks and matching resource blocks, so the resources can
+// be brought under Terraform without recreating them. The configuration is rebuilt from the
+// deployer's records; run terraform plan after importing to review remaining differences.
+func ExportTerraform(infra *state.Infrastructure) (string, error) {
+	resources, err := exportResources(infra)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Infrastructure of deployment %s, exported by app-deployer.\n", infra.DeploymentID)
+	b.WriteString("# Review terraform plan after importing; settings the deployer does not record are left out.\n")
+
+	for _, resource := range resources {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "import {\n  to = %s.%s\n  id = %s\n}\n\n", resource.tfType, resource.name, strconv.Quote(resource.importID))
+
+		fmt.Fprintf(&b, "resource %q %q {\n", resource.tfType, resource.name)
+		writeHCLAttributes(&b, "  ", resource.attributes)
+		for _, block := range resource.blocks {
+			fmt.Fprintf(&b, "\n  %s {\n", block.key)
+			writeHCLAttributes(&b, "    ", block.attributes)
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.String(), nil
+}
+
+// ExportPulumiYAML renders the same resources as ExportTerraform as a Pulumi YAML program,
+// each imported by its ID through the import resource option
+func ExportPulumiYAML(infra *state.Infrastructure) (string, error) {
+	resources, err := exportResources(infra)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Infrastructure of deployment %s, exported by app-deployer.\n", infra.DeploymentID)
+	b.WriteString("# Review pulumi preview after importing; settings the deployer does not record are left out.\n")
+	fmt.Fprintf(&b, "name: %s\n", yamlScalar(generateStackName(infra.DeploymentID.String())+"-export"))
+	b.WriteString("runtime: yaml\n")
+	b.WriteString("resources:\n")
+
+	for _, resource := range resources {
+		fmt.Fprintf(&b, "  %s:\n", resource.name)
+		fmt.Fprintf(&b, "    type: %s\n", resource.pulumiType)
+		b.WriteString("    properties:\n")
+		for _, attribute := range resource.attributes {
+			fmt.Fprintf(&b, "      %s: %s\n", camelCase(attribute.key), yamlValue(attribute.value))
+		}
+		for i, block := range resource.blocks {
+			if block.repeated {
+				// Repeated blocks of one kind share a single list
+				if i == 0 || resource.blocks[i-1].pulumiKey != block.pulumiKey {
+					fmt.Fprintf(&b, "      %s:\n", block.pulumiKey)
+				}
+				for j, attribute := range block.attributes {
+					prefix := "          "
+					if j == 0 {
+						prefix = "        - "
+					}
+					fmt.Fprintf(&b, "%s%s: %s\n", prefix, camelCase(attribute.key), yamlValue(attribute.value))
+				}
+				continue
+			}
+			fmt.Fprintf(&b, "      %s:\n", block.pulumiKey)
+			for _, attribute := range block.attributes {
+				fmt.Fprintf(&b, "        %s: %s\n", camelCase(attribute.key), yamlValue(attribute.value))
+			}
+		}
+		b.WriteString("    options:\n")
+		fmt.Fprintf(&b, "      import: %s\n", yamlScalar(resource.importID))
+	}
+
+	return b.String(), nil
+}
+
+// exportResources builds the resources recorded on infra. Imported clusters are exported
+// without the network the deployer did not create.
+func exportResources(infra *state.Infrastructure) ([]*exportResource, error) {
+	if infra.ClusterName == "" || infra.ClusterLocation == "" {
+		return nil, fmt.Errorf("%w: it has no GKE cluster", ErrNotExportable)
+	}
+
+	project := exportProject(infra)
+	if project == "" {
+		return nil, fmt.Errorf("%w: its GCP project is not recorded", ErrNotExportable)
+	}
+
+	var resources []*exportResource
+
+	for _, account := range []struct{ name, email string }{
+		{"node_service_account", infra.ServiceAccountEmail},
+		{"app_service_account", infra.AppServiceAccountEmail},
+	} {
+		accountID, _, ok := strings.Cut(account.email, "@")
+		if !ok {
+			continue
+		}
+		resources = append(resources, &exportResource{
+			name:       account.name,
+			tfType:     "google_service_account",
+			pulumiType: "gcp:serviceaccount:Account",
+			importID:   fmt.Sprintf("projects/%s/serviceAccounts/%s", project, account.email),
+			attributes: []exportAttribute{
+				{"project", project},
+				{"account_id", accountID},
+			},
+		})
+	}
+
+	var vpc, subnet *exportResource
+	if infra.VPCName != "" {
+		vpc = &exportResource{
+			name:       "vpc",
+			tfType:     "google_compute_network",
+			pulumiType: "gcp:compute:Network",
+			importID:   fmt.Sprintf("projects/%s/global/networks/%s", project, infra.VPCName),
+			attributes: []exportAttribute{
+				{"project", project},
+				{"name", infra.VPCName},
+				{"auto_create_subnetworks", false},
+				{"routing_mode", "REGIONAL"},
+			},
+		}
+		resources = append(resources, vpc)
+	}
+
+	if vpc != nil && infra.SubnetName != "" {
+		subnet = &exportResource{
+			name:       "subnet",
+			tfType:     "google_compute_subnetwork",
+			pulumiType: "gcp:compute:Subnetwork",
+			importID:   fmt.Sprintf("projects/%s/regions/%s/subnetworks/%s", project, infra.ClusterLocation, infra.SubnetName),
+			attributes: []exportAttribute{
+				{"project", project},
+				{"name", infra.SubnetName},
+				{"region", infra.ClusterLocation},
+				{"network", exportReference{vpc, "id"}},
+				{"ip_cidr_range", infra.SubnetCIDR},
+				{"private_ip_google_access", true},
+			},
+		}
+		for _, secondary := range [][2]string{{podsRangeName, podsRangeCIDR}, {servicesRangeName, servicesRangeCIDR}} {
+			subnet.blocks = append(subnet.blocks, exportBlock{
+				key:       "secondary_ip_range",
+				pulumiKey: "secondaryIpRanges",
+				repeated:  true,
+				attributes: []exportAttribute{
+					{"range_name", secondary[0]},
+					{"ip_cidr_range", secondary[1]},
+				},
+			})
+		}
+		resources = append(resources, subnet)
+	}
+
+	cluster := &exportResource{
+		name:       "cluster",
+		tfType:     "google_container_cluster",
+		pulumiType: "gcp:container:Cluster",
+		importID:   fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project, infra.ClusterLocation, infra.ClusterName),
+		attributes: []exportAttribute{
+			{"project", project},
+			{"name", infra.ClusterName},
+			{"location", infra.ClusterLocation},
+		},
+	}
+	if subnet != nil {
+		cluster.attributes = append(cluster.attributes,
+			exportAttribute{"network", exportReference{vpc, "id"}},
+			exportAttribute{"subnetwork", exportReference{subnet, "id"}},
+			exportAttribute{"remove_default_node_pool", true},
+			exportAttribute{"initial_node_count", 1},
+		)
+		cluster.blocks = append(cluster.blocks, exportBlock{
+			key:       "ip_allocation_policy",
+			pulumiKey: "ipAllocationPolicy",
+			attributes: []exportAttribute{
+				{"cluster_secondary_range_name", podsRangeName},
+				{"services_secondary_range_name", servicesRangeName},
+			},
+		})
+	}
+	resources = append(resources, cluster)
+
+	return resources, nil
+}
+
+// exportProject returns the GCP project the infrastructure runs in: from the VPC self link of
+// provisioned infrastructure, the node service account's domain, or an imported cluster's record
+func exportProject(infra *state.Infrastructure) string {
+	if _, rest, ok := strings.Cut(infra.VPCNetwork, "/projects/"); ok {
+		if project, _, _ := strings.Cut(rest, "/"); project != "" {
+			return project
+		}
+	}
+
+	if _, domain, ok := strings.Cut(infra.ServiceAccountEmail, "@"); ok {
+		if project, ok := strings.CutSuffix(domain, ".iam.gserviceaccount.com"); ok {
+			return project
+		}
+	}
+
+	return infra.GCPProject
+}
+
+// writeHCLAttributes writes attributes with their equals signs aligned, as terraform fmt does
+func writeHCLAttributes(b *strings.Builder, indent string, attributes []exportAttribute) {
+	width := 0
+	for _, attribute := range attributes {
+		width = max(width, len(attribute.key))
+	}
+
+	for _, attribute := range attributes {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, attribute.key, hclValue(attribute.value))
+	}
+}
+
+// hclValue renders an attribute value as an HCL expression
+func hclValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case exportReference:
+		return fmt.Sprintf("%s.%s.%s", v.resource.tfType, v.resource.name, v.attribute)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// yamlValue renders an attribute value as a Pulumi YAML value
+func yamlValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return yamlScalar(v)
+	case exportReference:
+		return fmt.Sprintf("${%s.%s}", v.resource.name, v.attribute)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// yamlScalar quotes a string when YAML would read it as anything but that string
+func yamlScalar(s string) string {
+	if s == "" || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`") || strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// camelCase converts a Terraform argument name to its Pulumi name, e.g. ip_cidr_range to ipCidrRange
+func camelCase(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -2,7 +2,9 @@ package gcp
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
 	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/container"
 	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/serviceaccount"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
@@ -13,6 +15,9 @@ type GKEResources struct {
 	Cluster        *container.Cluster
 	NodePool       *container.NodePool
 	ServiceAccount *serviceaccount.Account
+
+	// Tainted pool reserved for the app's pods, nil unless a dedicated node pool was requested
+	DedicatedNodePool *container.NodePool
 }
 
 // createServiceAccount creates a service account for GKE nodes
@@ -43,19 +48,11 @@ func createServiceAccount(ctx *pulumi.Context, req *ProvisionRequestInternal) (*
 func createGKECluster(ctx *pulumi.Context, vpcResources *VPCResources, sa *serviceaccount.Account, req *ProvisionRequestInternal) (*container.Cluster, error) {
 	clusterName := generateClusterName(req.AppName, req.DeploymentID)
 
-	// Generate labels
-	labels := generateLabels(req.AppName, req.DeploymentID, "production")
-	if req.Config != nil && req.Config.Labels != nil {
-		for k, v := range req.Config.Labels {
-			labels[k] = v
-		}
-	}
-
-	// Convert to Pulumi StringMap
-	pulumiLabels := make(pulumi.StringMap)
-	for k, v := range labels {
-		pulumiLabels[k] = pulumi.String(v)
+	labels, err := resourceLabels(req)
+	if err != nil {
+		return nil, err
 	}
+	pulumiLabels := toPulumiLabels(labels)
 
 	// Create the GKE cluster
 	cluster, err := container.NewCluster(ctx, clusterName, &container.ClusterArgs{
@@ -77,8 +74,8 @@ func createGKECluster(ctx *pulumi.Context, vpcResources *VPCResources, sa *servi
 
 		// IP allocation policy for VPC-native cluster
 		IpAllocationPolicy: &container.ClusterIpAllocationPolicyArgs{
-			ClusterSecondaryRangeName:  pulumi.String("pods"),
-			ServicesSecondaryRangeName: pulumi.String("services"),
+			ClusterSecondaryRangeName:  pulumi.String(podsRangeName),
+			ServicesSecondaryRangeName: pulumi.String(servicesRangeName),
 		},
 
 		// Network policy configuration
@@ -91,6 +88,7 @@ func createGKECluster(ctx *pulumi.Context, vpcResources *VPCResources, sa *servi
 			HttpLoadBalancing: &container.ClusterAddonsConfigHttpLoadBalancingArgs{
 				Disabled: pulumi.Bool(false), // Enable HTTP(S) load balancing
 			},
+			// GKE always runs the Metrics Server; HPA and the deployment metrics endpoint rely on it
 			HorizontalPodAutoscaling: &container.ClusterAddonsConfigHorizontalPodAutoscalingArgs{
 				Disabled: pulumi.Bool(false), // Enable HPA
 			},
@@ -130,14 +128,22 @@ func createGKECluster(ctx *pulumi.Context, vpcResources *VPCResources, sa *servi
 		LoggingService:    pulumi.String("logging.googleapis.com/kubernetes"),
 		MonitoringService: pulumi.String("monitoring.googleapis.com/kubernetes"),
 
-		// Binary authorization (optional - for image security)
+		// Binary authorization, enforcing the project's policy when enabled
 		BinaryAuthorization: &container.ClusterBinaryAuthorizationArgs{
-			EvaluationMode: pulumi.String("DISABLED"), // Can enable for production
+			EvaluationMode: pulumi.String(binaryAuthorizationMode(req)),
 		},
 
 		// Enable shielded nodes for enhanced security
 		EnableShieldedNodes: pulumi.Bool(true),
 
+		// Cluster autoscaler behaviour for autoscaled node pools
+		ClusterAutoscaling: clusterAutoscaling(req),
+
+		// Vertical Pod Autoscaling; the base chart only creates recommendation-mode VPAs
+		VerticalPodAutoscaling: &container.ClusterVerticalPodAutoscalingArgs{
+			Enabled: pulumi.Bool(req.Config != nil && req.Config.EnableVPA),
+		},
+
 		// Enable autopilot mode (optional - fully managed GKE)
 		// EnableAutopilot: pulumi.Bool(false),
 
@@ -152,12 +158,46 @@ func createGKECluster(ctx *pulumi.Context, vpcResources *VPCResources, sa *servi
 	return cluster, nil
 }
 
-// createNodePool creates a node pool for the GKE cluster
-func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *serviceaccount.Account, req *ProvisionRequestInternal) (*container.NodePool, error) {
+// binaryAuthorizationMode returns the cluster's Binary Authorization evaluation mode
+func binaryAuthorizationMode(req *ProvisionRequestInternal) string {
+	if req.Config != nil && req.Config.EnableBinaryAuthorization {
+		return "PROJECT_SINGLETON_POLICY_ENFORCE"
+	}
+	return "DISABLED"
+}
+
+// clusterAutoscaling picks the autoscaling profile from the requested scale-down delay. GKE runs
+// the cluster autoscaler itself and does not expose the delay, but OPTIMIZE_UTILIZATION removes
+// idle nodes within minutes instead of after the default 10 minutes.
+func clusterAutoscaling(req *ProvisionRequestInternal) container.ClusterClusterAutoscalingPtrInput {
+	if req.Config == nil || !req.Config.EnableAutoscaling {
+		return nil
+	}
+
+	profile := "BALANCED"
+	if delay := req.Config.Autoscaling.ScaleDownDelay; delay > 0 && delay < 10*time.Minute {
+		profile = "OPTIMIZE_UTILIZATION"
+	}
+
+	return &container.ClusterClusterAutoscalingArgs{
+		// Node auto-provisioning stays off, only the app's node pool is autoscaled
+		Enabled:            pulumi.Bool(false),
+		AutoscalingProfile: pulumi.String(profile),
+	}
+}
+
+// createNodePool creates a node pool for the GKE cluster. When workload is set the pool is
+// dedicated to it: its nodes are labelled and tainted workload=<workload>:NoSchedule, so only
+// pods tolerating the taint are scheduled there.
+func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *serviceaccount.Account, req *ProvisionRequestInternal, workload string) (*container.NodePool, error) {
 	nodePoolName := generateNodePoolName(req.AppName, req.DeploymentID)
+	if workload != "" {
+		nodePoolName = generateDedicatedNodePoolName(req.AppName, req.DeploymentID)
+	}
 
 	// Get configuration with defaults
 	nodeCount := 2
+	currentNodeCount := nodeCount
 	machineType := "e2-small"
 	preemptible := false
 	diskSize := 50 // GB
@@ -167,6 +207,10 @@ func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *service
 		if req.Config.NodeCount > 0 {
 			nodeCount = req.Config.NodeCount
 		}
+		currentNodeCount = nodeCount
+		if req.Config.TargetNodeCount > 0 {
+			currentNodeCount = req.Config.TargetNodeCount
+		}
 		if req.Config.MachineType != "" {
 			machineType = req.Config.MachineType
 		}
@@ -179,11 +223,20 @@ func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *service
 		}
 	}
 
-	// Generate labels
-	labels := generateLabels(req.AppName, req.DeploymentID, "production")
-	if req.Config != nil && req.Config.Labels != nil {
-		for k, v := range req.Config.Labels {
-			labels[k] = v
+	labels, err := resourceLabels(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var taints container.NodePoolNodeConfigTaintArray
+	if workload != "" {
+		labels[provisioner.DedicatedPoolLabel] = workload
+		taints = container.NodePoolNodeConfigTaintArray{
+			&container.NodePoolNodeConfigTaintArgs{
+				Key:    pulumi.String(provisioner.DedicatedPoolLabel),
+				Value:  pulumi.String(workload),
+				Effect: pulumi.String("NO_SCHEDULE"),
+			},
 		}
 	}
 
@@ -193,6 +246,21 @@ func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *service
 		pulumiLabels[k] = pulumi.String(v)
 	}
 
+	// The autoscaler owns the node count when enabled, so it is only pinned otherwise
+	var nodeCountArg pulumi.IntPtrInput = pulumi.Int(currentNodeCount)
+	var autoscaling container.NodePoolAutoscalingPtrInput
+	if req.Config != nil && req.Config.EnableAutoscaling {
+		nodeCountArg = nil
+		nodeCount = max(nodeCount, req.Config.Autoscaling.MinNodes)
+		nodeCount = min(nodeCount, req.Config.Autoscaling.MaxNodes)
+		autoscaling = &container.NodePoolAutoscalingArgs{
+			// Totals rather than per-zone limits, regional pools span several zones
+			TotalMinNodeCount: pulumi.Int(req.Config.Autoscaling.MinNodes),
+			TotalMaxNodeCount: pulumi.Int(req.Config.Autoscaling.MaxNodes),
+			LocationPolicy:    pulumi.String("BALANCED"),
+		}
+	}
+
 	nodePool, err := container.NewNodePool(ctx, nodePoolName, &container.NodePoolArgs{
 		Name:     pulumi.String(nodePoolName),
 		Cluster:  cluster.Name,
@@ -201,11 +269,11 @@ func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *service
 		// Initial node count
 		InitialNodeCount: pulumi.Int(nodeCount),
 
+		// Current node count, resized in place (initialNodeCount changes replace the pool)
+		NodeCount: nodeCountArg,
+
 		// Autoscaling configuration (optional)
-		// Autoscaling: &container.NodePoolAutoscalingArgs{
-		// 	MinNodeCount: pulumi.Int(1),
-		// 	MaxNodeCount: pulumi.Int(5),
-		// },
+		Autoscaling: autoscaling,
 
 		// Node configuration
 		NodeConfig: &container.NodePoolNodeConfigArgs{
@@ -221,8 +289,9 @@ func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *service
 				pulumi.String("https://www.googleapis.com/auth/cloud-platform"),
 			},
 
-			// Labels
-			Labels: pulumiLabels,
+			// Labels, as Kubernetes node labels and as labels on the node VMs
+			Labels:         pulumiLabels,
+			ResourceLabels: pulumiLabels,
 
 			// Metadata
 			Metadata: pulumi.StringMap{
@@ -246,14 +315,8 @@ func createNodePool(ctx *pulumi.Context, cluster *container.Cluster, sa *service
 				Mode: pulumi.String("GKE_METADATA"), // Use workload identity
 			},
 
-			// Taints (optional - for node specialization)
-			// Taints: container.NodePoolNodeConfigTaintArray{
-			// 	&container.NodePoolNodeConfigTaintArgs{
-			// 		Key:    pulumi.String("workload"),
-			// 		Value:  pulumi.String("general"),
-			// 		Effect: pulumi.String("NoSchedule"),
-			// 	},
-			// },
+			// Taints, set on dedicated pools only
+			Taints: taints,
 		},
 
 		// Management configuration
@@ -291,14 +354,32 @@ func createGKEResources(ctx *pulumi.Context, vpcResources *VPCResources, req *Pr
 	}
 
 	// Create node pool
-	nodePool, err := createNodePool(ctx, cluster, sa, req)
+	nodePool, err := createNodePool(ctx, cluster, sa, req, "")
 	if err != nil {
 		return nil, err
 	}
 
-	return &GKEResources{
+	resources := &GKEResources{
 		Cluster:        cluster,
 		NodePool:       nodePool,
 		ServiceAccount: sa,
-	}, nil
+	}
+
+	// The untainted pool keeps running system pods, which do not tolerate the dedicated taint
+	if req.Config != nil && req.Config.DedicatedNodePool {
+		resources.DedicatedNodePool, err = createNodePool(ctx, cluster, sa, req, dedicatedWorkload(req.Config))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resources, nil
+}
+
+// dedicatedWorkload returns the workload a dedicated node pool is reserved for
+func dedicatedWorkload(config *ProvisionConfigInternal) string {
+	if config.DedicatedWorkload == "" {
+		return provisioner.DefaultDedicatedWorkload
+	}
+	return config.DedicatedWorkload
 }
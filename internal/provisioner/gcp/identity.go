@@ -0,0 +1,122 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/serviceaccount"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/rs/zerolog/log"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+)
+
+// workloadIdentityRole allows a Kubernetes service account to impersonate a GCP service account
+const workloadIdentityRole = "roles/iam.workloadIdentityUser"
+
+// BindWorkloadIdentity binds a Kubernetes service account to a GCP service account, creating
+// one for the app when none is given. It runs in its own stack so it can be applied at deploy
+// time without touching the cluster stack.
+func (p *GCPProvisioner) BindWorkloadIdentity(ctx context.Context, req *provisioner.WorkloadIdentityRequest) (*provisioner.WorkloadIdentityResult, error) {
+	stackName := generateIdentityStackName(req.DeploymentID)
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("stackName", stackName).
+		Str("kubernetesServiceAccount", req.Namespace+"/"+req.KubernetesServiceAccount).
+		Msg("Binding workload identity")
+
+	stack, err := p.createOrSelectStack(ctx, stackName, p.createIdentityProgram(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create identity stack: %w", err)
+	}
+
+	if err := stack.SetConfig(ctx, "gcp:project", auto.ConfigValue{Value: p.gcpProject}); err != nil {
+		return nil, fmt.Errorf("failed to set gcp:project: %w", err)
+	}
+
+	upResult, err := stack.Up(ctx, optup.ProgressStreams(p.createProgressWriter(ctx, req.InfrastructureID)))
+	if err != nil {
+		return nil, fmt.Errorf("pulumi up failed: %w", err)
+	}
+
+	email, ok := upResult.Outputs["serviceAccountEmail"].Value.(string)
+	if !ok || email == "" {
+		return nil, fmt.Errorf("identity stack did not export a service account email")
+	}
+
+	log.Info().
+		Str("deploymentID", req.DeploymentID).
+		Str("serviceAccount", email).
+		Msg("Workload identity bound")
+
+	return &provisioner.WorkloadIdentityResult{GCPServiceAccountEmail: email}, nil
+}
+
+// createIdentityProgram creates the inline Pulumi program for a workload identity binding
+func (p *GCPProvisioner) createIdentityProgram(req *provisioner.WorkloadIdentityRequest) pulumi.RunFunc {
+	return func(ctx *pulumi.Context) error {
+		member := pulumi.Sprintf("serviceAccount:%s.svc.id.goog[%s/%s]", p.gcpProject, req.Namespace, req.KubernetesServiceAccount)
+
+		if req.GCPServiceAccountEmail != "" {
+			// The account is not ours, so add a member rather than owning the role binding
+			_, err := serviceaccount.NewIAMMember(ctx, "workload-identity", &serviceaccount.IAMMemberArgs{
+				ServiceAccountId: pulumi.Sprintf("projects/%s/serviceAccounts/%s", p.gcpProject, req.GCPServiceAccountEmail),
+				Role:             pulumi.String(workloadIdentityRole),
+				Member:           member,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to bind workload identity: %w", err)
+			}
+
+			ctx.Export("serviceAccountEmail", pulumi.String(req.GCPServiceAccountEmail))
+			return nil
+		}
+
+		saName := generateAppServiceAccountName(req.AppName, req.DeploymentID)
+		sa, err := serviceaccount.NewAccount(ctx, saName, &serviceaccount.AccountArgs{
+			AccountId:   pulumi.String(saName),
+			DisplayName: pulumi.Sprintf("Workload identity for %s", req.AppName),
+			Description: pulumi.String("Service account used by application pods through workload identity"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create app service account: %w", err)
+		}
+
+		_, err = serviceaccount.NewIAMBinding(ctx, "workload-identity", &serviceaccount.IAMBindingArgs{
+			ServiceAccountId: sa.Name,
+			Role:             pulumi.String(workloadIdentityRole),
+			Members:          pulumi.StringArray{member},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to bind workload identity: %w", err)
+		}
+
+		ctx.Export("serviceAccountEmail", sa.Email)
+		return nil
+	}
+}
+
+// destroyIdentityStack removes a deployment's workload identity stack, if it has one
+func (p *GCPProvisioner) destroyIdentityStack(ctx context.Context, req *provisioner.DestroyRequest) error {
+	stackName := generateIdentityStackName(req.DeploymentID)
+
+	stack, err := p.selectExistingStack(ctx, stackName)
+	if err != nil {
+		log.Debug().Str("stackName", stackName).Msg("No workload identity stack to destroy")
+		return nil
+	}
+
+	if _, err := stack.Destroy(ctx, optdestroy.ProgressStreams(p.createProgressWriter(ctx, req.InfrastructureID))); err != nil {
+		return fmt.Errorf("pulumi destroy failed: %w", err)
+	}
+
+	if err := stack.Workspace().RemoveStack(ctx, stackName); err != nil {
+		log.Warn().Err(err).Msg("Failed to remove identity stack (may already be removed)")
+	}
+
+	return nil
+}
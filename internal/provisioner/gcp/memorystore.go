@@ -0,0 +1,89 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/compute"
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/redis"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+)
+
+// MemorystoreAddonConfig holds user-facing configuration for the memorystore addon
+type MemorystoreAddonConfig struct {
+	Tier         string `json:"tier"`         // BASIC or STANDARD_HA. Default: BASIC
+	MemorySizeGb int    `json:"memorySizeGb"` // Default: 1
+	RedisVersion string `json:"redisVersion"` // Default: provider default
+}
+
+// MemorystoreRequest contains everything needed to create a Memorystore Redis instance
+type MemorystoreRequest struct {
+	AppName      string
+	DeploymentID string
+	Region       string
+	Network      *compute.Network
+	Labels       pulumi.StringMap
+	Config       MemorystoreAddonConfig
+}
+
+// MemorystoreResources holds references to created Memorystore resources
+type MemorystoreResources struct {
+	Instance *redis.Instance
+}
+
+// parseMemorystoreAddon converts a generic addon config into a MemorystoreAddonConfig with defaults applied
+func parseMemorystoreAddon(addon *provisioner.AddonConfig) (MemorystoreAddonConfig, error) {
+	var cfg MemorystoreAddonConfig
+
+	if len(addon.Config) > 0 {
+		data, err := json.Marshal(addon.Config)
+		if err != nil {
+			return cfg, fmt.Errorf("marshal memorystore config: %w", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("unmarshal memorystore config: %w", err)
+		}
+	}
+
+	if cfg.Tier == "" {
+		cfg.Tier = "BASIC"
+	}
+	if cfg.Tier != "BASIC" && cfg.Tier != "STANDARD_HA" {
+		return cfg, fmt.Errorf("unsupported memorystore tier: %s", cfg.Tier)
+	}
+	if cfg.MemorySizeGb == 0 {
+		cfg.MemorySizeGb = 1
+	}
+
+	return cfg, nil
+}
+
+// CreateMemorystoreInstance creates a Memorystore Redis instance peered with the cluster VPC
+func CreateMemorystoreInstance(ctx *pulumi.Context, req *MemorystoreRequest) (*MemorystoreResources, error) {
+	instanceName := generateMemorystoreInstanceName(req.AppName, req.DeploymentID)
+
+	args := &redis.InstanceArgs{
+		Name:              pulumi.String(instanceName),
+		DisplayName:       pulumi.Sprintf("Redis for %s", req.AppName),
+		Tier:              pulumi.String(req.Config.Tier),
+		MemorySizeGb:      pulumi.Int(req.Config.MemorySizeGb),
+		Region:            pulumi.String(req.Region),
+		AuthorizedNetwork: req.Network.ID(),
+		ConnectMode:       pulumi.String("DIRECT_PEERING"),
+		Labels:            req.Labels,
+	}
+	if req.Config.RedisVersion != "" {
+		args.RedisVersion = pulumi.String(req.Config.RedisVersion)
+	}
+
+	instance, err := redis.NewInstance(ctx, instanceName, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Memorystore instance: %w", err)
+	}
+
+	return &MemorystoreResources{
+		Instance: instance,
+	}, nil
+}
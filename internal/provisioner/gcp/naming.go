@@ -21,6 +21,9 @@ var (
 
 	// Regex to match multiple consecutive hyphens
 	multiHyphenRegex = regexp.MustCompile(`-+`)
+
+	// Regex to match a valid GCP label key
+	labelKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
 )
 
 // generateStackName generates a Pulumi stack name from deployment ID
@@ -29,6 +32,12 @@ func generateStackName(deploymentID string) string {
 	return fmt.Sprintf("deployer-%s", deploymentID)
 }
 
+// generateIdentityStackName generates the Pulumi stack name for a deployment's workload identity
+// Format: deployer-{deployment-id}-identity
+func generateIdentityStackName(deploymentID string) string {
+	return generateStackName(deploymentID) + "-identity"
+}
+
 // generateClusterName generates a GKE cluster name
 // Format: deployer-cluster-{app}-{id-short}
 func generateClusterName(appName, deploymentID string) string {
@@ -77,6 +86,14 @@ func generateNodePoolName(appName, deploymentID string) string {
 	return fmt.Sprintf("deployer-pool-%s-%s", sanitized, shortID)
 }
 
+// generateDedicatedNodePoolName generates the name of a node pool dedicated to the app
+// Format: deployer-dpool-{app}-{id-short}
+func generateDedicatedNodePoolName(appName, deploymentID string) string {
+	shortID := getShortID(deploymentID)
+	sanitized := sanitizeName(appName)
+	return fmt.Sprintf("deployer-dpool-%s-%s", sanitized, shortID)
+}
+
 // generateServiceAccountName generates a service account name
 // Format: deployer-sa-{app}-{id-short}
 // Note: SA names have stricter limits (6-30 chars)
@@ -102,6 +119,41 @@ func generateServiceAccountName(appName, deploymentID string) string {
 	return name
 }
 
+// generateAppServiceAccountName generates the service account name pods run as
+// Format: deployer-app-{app}-{id-short}, limited to 30 chars like node service accounts
+func generateAppServiceAccountName(appName, deploymentID string) string {
+	shortID := getShortID(deploymentID)
+	sanitized := sanitizeName(appName)
+
+	name := fmt.Sprintf("deployer-app-%s-%s", sanitized, shortID)
+	if len(name) > 30 {
+		maxAppLen := 30 - len("deployer-app-") - len(shortID) - 1
+		if maxAppLen > 0 {
+			name = fmt.Sprintf("deployer-app-%s-%s", sanitized[:maxAppLen], shortID)
+		} else {
+			name = fmt.Sprintf("deployer-app-%s", shortID)
+		}
+	}
+
+	return name
+}
+
+// generateCloudSQLInstanceName generates a Cloud SQL instance name
+// Format: deployer-sql-{app}-{id-short}
+func generateCloudSQLInstanceName(appName, deploymentID string) string {
+	shortID := getShortID(deploymentID)
+	sanitized := sanitizeName(appName)
+	return fmt.Sprintf("deployer-sql-%s-%s", sanitized, shortID)
+}
+
+// generateMemorystoreInstanceName generates a Memorystore Redis instance name
+// Format: deployer-redis-{app}-{id-short}
+func generateMemorystoreInstanceName(appName, deploymentID string) string {
+	shortID := getShortID(deploymentID)
+	sanitized := sanitizeName(appName)
+	return fmt.Sprintf("deployer-redis-%s-%s", sanitized, shortID)
+}
+
 // generateFirewallRuleName generates a firewall rule name
 // Format: deployer-fw-{purpose}-{app}-{id-short}
 func generateFirewallRuleName(purpose, appName, deploymentID string) string {
@@ -185,3 +237,27 @@ func generateLabels(appName, deploymentID, environment string) map[string]string
 		"cost-tracking": "enabled",
 	}
 }
+
+// resourceLabels merges the deployment's labels over the standard ones and checks that every
+// required cost allocation label is set. Networks, subnetworks and service accounts take no
+// labels in GCP, so the cluster, its node pools and addons carry them.
+func resourceLabels(req *ProvisionRequestInternal) (map[string]string, error) {
+	labels := generateLabels(req.AppName, req.DeploymentID, "production")
+	if req.Config != nil && req.Config.Labels != nil {
+		for k, v := range req.Config.Labels {
+			labels[k] = v
+		}
+	}
+
+	var missing []string
+	for _, key := range req.RequiredLabels {
+		if labels[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required cost allocation labels: %s", strings.Join(missing, ", "))
+	}
+
+	return labels, nil
+}
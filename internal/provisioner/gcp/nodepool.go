@@ -0,0 +1,99 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/rs/zerolog/log"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+)
+
+// provisionRequestConfigKey is the stack config key holding the request a stack was provisioned with
+const provisionRequestConfigKey = "app-deployer:provisionRequest"
+
+// UpdateNodePool re-runs a stack's program with only the node pool settings changed, so
+// Pulumi updates the pool in place and leaves the cluster and addons untouched
+func (p *GCPProvisioner) UpdateNodePool(ctx context.Context, stackName string, config provisioner.NodePoolUpdateConfig) error {
+	log.Info().
+		Str("stackName", stackName).
+		Int("nodeCount", config.NodeCount).
+		Str("machineType", config.MachineType).
+		Msg("Updating node pool")
+
+	if err := p.VerifyAccess(ctx); err != nil {
+		return fmt.Errorf("GCP access verification failed: %w", err)
+	}
+
+	// The program reads the request once it is loaded from the stack config below
+	req := &ProvisionRequestInternal{}
+
+	stack, err := auto.SelectStackInlineSource(ctx, stackName, p.projectName, p.createPulumiProgram(req),
+		auto.Project(workspace.Project{
+			Name:    tokens.PackageName(p.projectName),
+			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
+			Backend: &workspace.ProjectBackend{
+				URL: p.backendFor(ctx, stackName),
+			},
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to select stack: %w", err)
+	}
+
+	value, err := stack.GetConfig(ctx, provisionRequestConfigKey)
+	if err != nil {
+		return fmt.Errorf("stack %s has no recorded provision request, reprovision it to enable updates: %w", stackName, err)
+	}
+
+	if err := json.Unmarshal([]byte(value.Value), req); err != nil {
+		return fmt.Errorf("failed to parse recorded provision request: %w", err)
+	}
+
+	if req.Config == nil {
+		req.Config = &ProvisionConfigInternal{NodeCount: p.defaultNodes, MachineType: p.defaultType}
+	}
+	if config.NodeCount > 0 {
+		if req.Config.EnableAutoscaling {
+			return fmt.Errorf("node pool of stack %s is autoscaled, its node count cannot be set", stackName)
+		}
+		req.Config.TargetNodeCount = config.NodeCount
+	}
+	if config.MachineType != "" {
+		req.Config.MachineType = config.MachineType
+	}
+
+	if _, err := stack.Up(ctx, optup.ProgressStreams(p.createProgressWriter(ctx, config.InfrastructureID))); err != nil {
+		return fmt.Errorf("pulumi up failed: %w", err)
+	}
+
+	// Later updates start from the new settings
+	if err := saveProvisionRequest(ctx, stack, req); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("stackName", stackName).
+		Msg("Node pool updated")
+
+	return nil
+}
+
+// saveProvisionRequest records a provision request in the stack config
+func saveProvisionRequest(ctx context.Context, stack auto.Stack, req *ProvisionRequestInternal) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal provision request: %w", err)
+	}
+
+	if err := stack.SetConfig(ctx, provisionRequestConfigKey, auto.ConfigValue{Value: string(data)}); err != nil {
+		return fmt.Errorf("failed to set %s: %w", provisionRequestConfigKey, err)
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+	"github.com/alvesdmateus/app-deployer/internal/security"
 )
 
 // GCPProvisioner implements the Provisioner interface for GCP using Pulumi
@@ -28,6 +29,8 @@ type GCPProvisioner struct {
 	tracker      *provisioner.Tracker
 	defaultNodes int
 	defaultType  string
+
+	requiredLabels []string
 }
 
 // Config holds GCP provisioner configuration
@@ -37,6 +40,13 @@ type Config struct {
 	PulumiBackend   string
 	DefaultNodeType string
 	DefaultNodes    int
+	CostAllocation  CostAllocationConfig
+}
+
+// CostAllocationConfig lists the label keys provisioned resources must carry, so that billing
+// exports can attribute their cost. Keys the deployer does not set come from deployment tags.
+type CostAllocationConfig struct {
+	RequiredLabels []string
 }
 
 // NewGCPProvisioner creates a new GCP provisioner
@@ -61,6 +71,12 @@ func NewGCPProvisioner(config Config, tracker *provisioner.Tracker) (*GCPProvisi
 		config.DefaultNodes = 2
 	}
 
+	for _, key := range config.CostAllocation.RequiredLabels {
+		if !labelKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("invalid required cost allocation label %q", key)
+		}
+	}
+
 	log.Info().
 		Str("gcpProject", config.GCPProject).
 		Str("gcpRegion", config.GCPRegion).
@@ -75,6 +91,8 @@ func NewGCPProvisioner(config Config, tracker *provisioner.Tracker) (*GCPProvisi
 		tracker:      tracker,
 		defaultNodes: config.DefaultNodes,
 		defaultType:  config.DefaultNodeType,
+
+		requiredLabels: config.CostAllocation.RequiredLabels,
 	}, nil
 }
 
@@ -145,7 +163,17 @@ func (p *GCPProvisioner) Provision(ctx context.Context, req *provisioner.Provisi
 			SubnetCIDR:       existingInfra.SubnetCIDR,
 			ServiceAccount:   existingInfra.ServiceAccountEmail,
 			Namespace:        existingInfra.Namespace,
-			Duration:         0,
+
+			DatabaseConnectionName: existingInfra.DatabaseConnectionName,
+			DatabaseHost:           existingInfra.DatabaseHost,
+			DatabasePort:           existingInfra.DatabasePort,
+			DatabaseName:           existingInfra.DatabaseName,
+			DatabaseUser:           existingInfra.DatabaseUser,
+
+			RedisHost: existingInfra.RedisHost,
+			RedisPort: existingInfra.RedisPort,
+
+			Duration: 0,
 		}, nil
 	}
 
@@ -155,9 +183,23 @@ func (p *GCPProvisioner) Provision(ctx context.Context, req *provisioner.Provisi
 		return nil, fmt.Errorf("failed to start provisioning tracking: %w", err)
 	}
 
+	// The policy must be in place before an enforcing cluster starts admitting pods
+	if req.Config != nil && req.Config.BinaryAuth != nil && req.Config.BinaryAuth.Enable && req.Config.BinaryAuth.Policy != "" {
+		if err := security.CreateAttestorPolicy(ctx, p.gcpProject, req.Config.BinaryAuth.Policy); err != nil {
+			p.tracker.FailProvisioning(ctx, infraID, err)
+			return nil, fmt.Errorf("failed to apply Binary Authorization policy: %w", err)
+		}
+	}
+
 	// Convert request to internal format
 	internalReq := p.convertRequest(req)
 
+	// Untagged resources would go unnoticed until the bill, so nothing is created without them
+	if _, err := resourceLabels(internalReq); err != nil {
+		p.tracker.FailProvisioning(ctx, infraID, err)
+		return nil, err
+	}
+
 	// Create Pulumi program
 	program := p.createPulumiProgram(internalReq)
 
@@ -174,6 +216,8 @@ func (p *GCPProvisioner) Provision(ctx context.Context, req *provisioner.Provisi
 		return nil, fmt.Errorf("failed to set stack config: %w", err)
 	}
 
+	p.setStackTags(ctx, stack, internalReq.Config.Labels)
+
 	// Run pulumi up with progress streaming
 	log.Info().Str("stackName", stackName).Msg("Running pulumi up")
 
@@ -190,6 +234,20 @@ func (p *GCPProvisioner) Provision(ctx context.Context, req *provisioner.Provisi
 		return nil, fmt.Errorf("failed to extract outputs: %w", err)
 	}
 
+	// Keep workloads from moving data out of the perimeter with the node service account
+	if req.Config != nil && req.Config.VPCServiceControls != nil && req.Config.VPCServiceControls.ServicePerimeterName != "" {
+		perimeter := req.Config.VPCServiceControls.ServicePerimeterName
+		if err := security.AddToPerimeter(ctx, p.gcpProject, perimeter, result.ServiceAccount); err != nil {
+			p.tracker.FailProvisioning(ctx, infraID, err)
+			return nil, fmt.Errorf("failed to add service account to perimeter: %w", err)
+		}
+		result.VPCServicePerimeter = perimeter
+	}
+
+	if internalReq.Config.DedicatedNodePool {
+		result.DedicatedWorkload = dedicatedWorkload(internalReq.Config)
+	}
+
 	result.Duration = time.Since(startTime)
 
 	// Update tracker with success
@@ -218,6 +276,16 @@ func (p *GCPProvisioner) Destroy(ctx context.Context, req *provisioner.DestroyRe
 		return fmt.Errorf("GCP access verification failed: %w", err)
 	}
 
+	// The app's workload identity binding lives in its own stack
+	if err := p.destroyIdentityStack(ctx, req); err != nil {
+		return fmt.Errorf("failed to destroy workload identity: %w", err)
+	}
+
+	// Orphaned identity stacks are cleaned up on their own, with nothing else to destroy
+	if req.StackName == generateIdentityStackName(req.DeploymentID) {
+		return nil
+	}
+
 	// Create empty program for destroy
 	program := pulumi.RunFunc(func(ctx *pulumi.Context) error {
 		return nil
@@ -229,7 +297,7 @@ func (p *GCPProvisioner) Destroy(ctx context.Context, req *provisioner.DestroyRe
 			Name: tokens.PackageName(p.projectName),
 			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
 			Backend: &workspace.ProjectBackend{
-				URL: p.backendURL,
+				URL: p.backendFor(ctx, req.StackName),
 			},
 		}),
 	)
@@ -271,7 +339,7 @@ func (p *GCPProvisioner) GetStatus(ctx context.Context, stackName string) (*prov
 			Name: tokens.PackageName(p.projectName),
 			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
 			Backend: &workspace.ProjectBackend{
-				URL: p.backendURL,
+				URL: p.backendFor(ctx, stackName),
 			},
 		}),
 	)
@@ -302,6 +370,11 @@ func (p *GCPProvisioner) GetStatus(ctx context.Context, stackName string) (*prov
 // createPulumiProgram creates the inline Pulumi program
 func (p *GCPProvisioner) createPulumiProgram(req *ProvisionRequestInternal) pulumi.RunFunc {
 	return func(ctx *pulumi.Context) error {
+		labels, err := resourceLabels(req)
+		if err != nil {
+			return err
+		}
+
 		log.Info().Msg("Creating VPC resources")
 
 		// Create VPC resources
@@ -338,17 +411,76 @@ func (p *GCPProvisioner) createPulumiProgram(req *ProvisionRequestInternal) pulu
 		ctx.Export("clusterLocation", gkeResources.Cluster.Location)
 		ctx.Export("serviceAccount", gkeResources.ServiceAccount.Email)
 		ctx.Export("nodePoolName", gkeResources.NodePool.Name)
+		if gkeResources.DedicatedNodePool != nil {
+			ctx.Export("dedicatedNodePoolName", gkeResources.DedicatedNodePool.Name)
+		}
 		ctx.Export("namespace", pulumi.String(generateNamespace(req.AppName, req.DeploymentID)))
 
+		// Optional addons
+		if addon := provisioner.FindAddon(req.Addons, provisioner.AddonTypeCloudSQL); addon != nil {
+			log.Info().Msg("Creating Cloud SQL instance")
+
+			sqlConfig, err := parseCloudSQLAddon(addon)
+			if err != nil {
+				return fmt.Errorf("invalid cloudsql addon config: %w", err)
+			}
+
+			sqlResources, err := CreateCloudSQLInstance(ctx, &CloudSQLRequest{
+				AppName:        req.AppName,
+				DeploymentID:   req.DeploymentID,
+				Project:        p.gcpProject,
+				Region:         req.Region,
+				Network:        vpcResources.VPC,
+				ServiceAccount: gkeResources.ServiceAccount,
+				Labels:         toPulumiLabels(labels),
+				Config:         sqlConfig,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create Cloud SQL resources: %w", err)
+			}
+
+			ctx.Export("databaseConnectionName", sqlResources.Instance.ConnectionName)
+			ctx.Export("databaseHost", sqlResources.Instance.PrivateIpAddress)
+			ctx.Export("databasePort", pulumi.Int(cloudSQLPort))
+			ctx.Export("databaseName", sqlResources.Database.Name)
+			ctx.Export("databaseUser", sqlResources.UserName)
+		}
+
+		if addon := provisioner.FindAddon(req.Addons, provisioner.AddonTypeMemorystore); addon != nil {
+			log.Info().Msg("Creating Memorystore Redis instance")
+
+			redisConfig, err := parseMemorystoreAddon(addon)
+			if err != nil {
+				return fmt.Errorf("invalid memorystore addon config: %w", err)
+			}
+
+			redisResources, err := CreateMemorystoreInstance(ctx, &MemorystoreRequest{
+				AppName:      req.AppName,
+				DeploymentID: req.DeploymentID,
+				Region:       req.Region,
+				Network:      vpcResources.VPC,
+				Labels:       toPulumiLabels(labels),
+				Config:       redisConfig,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create Memorystore resources: %w", err)
+			}
+
+			ctx.Export("redisHost", redisResources.Instance.Host)
+			ctx.Export("redisPort", redisResources.Instance.Port)
+		}
+
 		return nil
 	}
 }
 
 // createOrSelectStack creates or selects a Pulumi stack
 func (p *GCPProvisioner) createOrSelectStack(ctx context.Context, stackName string, program pulumi.RunFunc) (auto.Stack, error) {
+	backendURL := p.backendFor(ctx, stackName)
+
 	log.Info().
 		Str("stackName", stackName).
-		Str("backend", p.backendURL).
+		Str("backend", backendURL).
 		Msg("Creating or selecting Pulumi stack")
 
 	stack, err := auto.UpsertStackInlineSource(ctx, stackName, p.projectName, program,
@@ -356,7 +488,7 @@ func (p *GCPProvisioner) createOrSelectStack(ctx context.Context, stackName stri
 			Name: tokens.PackageName(p.projectName),
 			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
 			Backend: &workspace.ProjectBackend{
-				URL: p.backendURL,
+				URL: backendURL,
 			},
 		}),
 	)
@@ -368,6 +500,28 @@ func (p *GCPProvisioner) createOrSelectStack(ctx context.Context, stackName stri
 	return stack, nil
 }
 
+// selectExistingStack selects an existing Pulumi stack with an empty program, for read-only operations
+func (p *GCPProvisioner) selectExistingStack(ctx context.Context, stackName string) (auto.Stack, error) {
+	program := pulumi.RunFunc(func(ctx *pulumi.Context) error {
+		return nil
+	})
+
+	stack, err := auto.SelectStackInlineSource(ctx, stackName, p.projectName, program,
+		auto.Project(workspace.Project{
+			Name:    tokens.PackageName(p.projectName),
+			Runtime: workspace.NewProjectRuntimeInfo("go", nil),
+			Backend: &workspace.ProjectBackend{
+				URL: p.backendFor(ctx, stackName),
+			},
+		}),
+	)
+	if err != nil {
+		return auto.Stack{}, fmt.Errorf("failed to select stack: %w", err)
+	}
+
+	return stack, nil
+}
+
 // setStackConfig sets Pulumi stack configuration
 func (p *GCPProvisioner) setStackConfig(ctx context.Context, stack auto.Stack, req *ProvisionRequestInternal) error {
 	log.Info().Msg("Setting stack configuration")
@@ -382,9 +536,29 @@ func (p *GCPProvisioner) setStackConfig(ctx context.Context, stack auto.Stack, r
 		return fmt.Errorf("failed to set gcp:region: %w", err)
 	}
 
+	// Record the request so the program can be rebuilt for in-place updates
+	if err := saveProvisionRequest(ctx, stack, req); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// setStackTags copies deployment tags onto the stack. Not every backend supports stack
+// tags, so failures are logged and provisioning continues.
+func (p *GCPProvisioner) setStackTags(ctx context.Context, stack auto.Stack, tags map[string]string) {
+	for key, value := range tags {
+		if err := stack.SetTag(ctx, key, value); err != nil {
+			log.Warn().
+				Err(err).
+				Str("stackName", stack.Name()).
+				Str("tag", key).
+				Msg("Failed to set stack tag")
+			return
+		}
+	}
+}
+
 // extractOutputs extracts outputs from Pulumi up result
 func (p *GCPProvisioner) extractOutputs(upResult auto.UpResult, stackName, infraID string) (*provisioner.ProvisionResult, error) {
 	log.Info().Msg("Extracting Pulumi outputs")
@@ -396,6 +570,13 @@ func (p *GCPProvisioner) extractOutputs(upResult auto.UpResult, stackName, infra
 		return ""
 	}
 
+	getInt := func(key string) int {
+		if val, ok := upResult.Outputs[key].Value.(float64); ok {
+			return int(val)
+		}
+		return 0
+	}
+
 	return &provisioner.ProvisionResult{
 		InfrastructureID: infraID,
 		StackName:        stackName,
@@ -409,7 +590,17 @@ func (p *GCPProvisioner) extractOutputs(upResult auto.UpResult, stackName, infra
 		SubnetCIDR:       getString("subnetCIDR"),
 		ServiceAccount:   getString("serviceAccount"),
 		Namespace:        getString("namespace"),
-		ProvisionLog:     upResult.StdOut,
+
+		DatabaseConnectionName: getString("databaseConnectionName"),
+		DatabaseHost:           getString("databaseHost"),
+		DatabasePort:           getInt("databasePort"),
+		DatabaseName:           getString("databaseName"),
+		DatabaseUser:           getString("databaseUser"),
+
+		RedisHost: getString("redisHost"),
+		RedisPort: getInt("redisPort"),
+
+		ProvisionLog: upResult.StdOut,
 	}, nil
 }
 
@@ -435,6 +626,11 @@ func (w *progressWriter) Write(p []byte) (n int, err error) {
 	// Log to zerolog
 	log.Debug().Str("infraID", w.infraID).Msg(strings.TrimSpace(logEntry))
 
+	// Stacks cleaned up without an infrastructure record have nowhere to record progress
+	if w.infraID == "" {
+		return len(p), nil
+	}
+
 	// Update tracker with progress
 	if err := w.tracker.UpdateProgress(w.ctx, w.infraID, logEntry); err != nil {
 		log.Warn().Err(err).Msg("Failed to update provision progress")
@@ -451,6 +647,9 @@ func (p *GCPProvisioner) convertRequest(req *provisioner.ProvisionRequest) *Prov
 		Version:      req.Version,
 		Cloud:        req.Cloud,
 		Region:       req.Region,
+		Addons:       req.Addons,
+
+		RequiredLabels: p.requiredLabels,
 	}
 
 	// Convert config
@@ -464,6 +663,17 @@ func (p *GCPProvisioner) convertRequest(req *provisioner.ProvisionRequest) *Prov
 			Labels:          req.Config.Labels,
 			VPCCIDRBlock:    req.Config.VPCCIDRBlock,
 			SubnetCIDRBlock: req.Config.SubnetCIDRBlock,
+
+			EnableAutoscaling: req.Config.EnableAutoscaling,
+			Autoscaling:       req.Config.Autoscaling,
+
+			EnableVPA: req.Config.EnableVPA,
+
+			DedicatedNodePool: req.Config.DedicatedNodePool,
+			DedicatedWorkload: req.Config.DedicatedWorkload,
+		}
+		if req.Config.BinaryAuth != nil {
+			internalReq.Config.EnableBinaryAuthorization = req.Config.BinaryAuth.Enable
 		}
 	} else {
 		// Use defaults
@@ -480,3 +690,12 @@ func (p *GCPProvisioner) convertRequest(req *provisioner.ProvisionRequest) *Prov
 
 	return internalReq
 }
+
+// toPulumiLabels converts a label map to a Pulumi StringMap
+func toPulumiLabels(labels map[string]string) pulumi.StringMap {
+	pulumiLabels := make(pulumi.StringMap)
+	for k, v := range labels {
+		pulumiLabels[k] = pulumi.String(v)
+	}
+	return pulumiLabels
+}
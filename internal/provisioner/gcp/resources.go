@@ -0,0 +1,104 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+)
+
+// checkpointDeployment mirrors the parts of an exported Pulumi deployment we need
+type checkpointDeployment struct {
+	Resources         []checkpointResource         `json:"resources"`
+	PendingOperations []checkpointPendingOperation `json:"pending_operations"`
+}
+
+// checkpointResource is a single resource entry in an exported Pulumi deployment
+type checkpointResource struct {
+	URN     string                 `json:"urn"`
+	Type    string                 `json:"type"`
+	Outputs map[string]interface{} `json:"outputs"`
+	Delete  bool                   `json:"delete"`
+}
+
+// checkpointPendingOperation is an in-flight operation recorded in an exported Pulumi deployment
+type checkpointPendingOperation struct {
+	Resource checkpointResource `json:"resource"`
+	Type     string             `json:"type"`
+}
+
+// ListResources exports the Pulumi stack state and lists the GCP resources it manages
+func (p *GCPProvisioner) ListResources(ctx context.Context, stackName string) ([]provisioner.CloudResource, error) {
+	stack, err := p.selectExistingStack(ctx, stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	exported, err := stack.Export(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export stack: %w", err)
+	}
+
+	resources, err := parseCheckpointResources(exported.Deployment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stack checkpoint: %w", err)
+	}
+
+	log.Debug().
+		Str("stackName", stackName).
+		Int("resources", len(resources)).
+		Msg("Listed stack resources")
+
+	return resources, nil
+}
+
+// parseCheckpointResources walks an exported deployment and extracts cloud resources
+func parseCheckpointResources(data json.RawMessage) ([]provisioner.CloudResource, error) {
+	var deployment checkpointDeployment
+	if err := json.Unmarshal(data, &deployment); err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]string)
+	for _, op := range deployment.PendingOperations {
+		pending[op.Resource.URN] = op.Type
+	}
+
+	resources := make([]provisioner.CloudResource, 0, len(deployment.Resources))
+	for _, res := range deployment.Resources {
+		// Skip the stack itself and provider plugins, they are not cloud resources
+		if res.Type == "pulumi:pulumi:Stack" || strings.HasPrefix(res.Type, "pulumi:providers:") {
+			continue
+		}
+
+		status := "created"
+		if res.Delete {
+			status = "pending_delete"
+		}
+		if opType, ok := pending[res.URN]; ok {
+			status = opType
+		}
+
+		resources = append(resources, provisioner.CloudResource{
+			Type:       res.Type,
+			Name:       resourceNameFromURN(res.URN),
+			URN:        res.URN,
+			Status:     status,
+			Properties: res.Outputs,
+		})
+	}
+
+	return resources, nil
+}
+
+// resourceNameFromURN extracts the logical resource name (the last "::" segment) from a URN
+func resourceNameFromURN(urn string) string {
+	if idx := strings.LastIndex(urn, "::"); idx >= 0 {
+		return urn[idx+2:]
+	}
+	return urn
+}
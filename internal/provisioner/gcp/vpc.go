@@ -5,6 +5,16 @@ import (
 
 	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/compute"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
+)
+
+// Secondary ranges of every subnet, which GKE allocates pod and service IPs from
+const (
+	podsRangeName     = "pods"
+	podsRangeCIDR     = "10.1.0.0/16" // Large range for pods
+	servicesRangeName = "services"
+	servicesRangeCIDR = "10.2.0.0/16" // Range for services
 )
 
 // VPCResources holds references to created VPC resources
@@ -54,12 +64,12 @@ func createSubnet(ctx *pulumi.Context, vpc *compute.Network, req *ProvisionReque
 		// Secondary IP ranges for GKE pods and services
 		SecondaryIpRanges: compute.SubnetworkSecondaryIpRangeArray{
 			&compute.SubnetworkSecondaryIpRangeArgs{
-				RangeName:   pulumi.String("pods"),
-				IpCidrRange: pulumi.String("10.1.0.0/16"), // Large range for pods
+				RangeName:   pulumi.String(podsRangeName),
+				IpCidrRange: pulumi.String(podsRangeCIDR),
 			},
 			&compute.SubnetworkSecondaryIpRangeArgs{
-				RangeName:   pulumi.String("services"),
-				IpCidrRange: pulumi.String("10.2.0.0/16"), // Range for services
+				RangeName:   pulumi.String(servicesRangeName),
+				IpCidrRange: pulumi.String(servicesRangeCIDR),
 			},
 		},
 
@@ -179,11 +189,15 @@ type ProvisionRequestInternal struct {
 	Cloud        string
 	Region       string
 	Config       *ProvisionConfigInternal
+	Addons       []provisioner.AddonConfig
+
+	RequiredLabels []string // Cost allocation label keys every labeled resource must carry
 }
 
 // ProvisionConfigInternal is an internal version of ProvisionConfig
 type ProvisionConfigInternal struct {
 	NodeCount       int
+	TargetNodeCount int // Node count set after creation; the pool is created with NodeCount
 	MachineType     string
 	Preemptible     bool
 	DiskSize        int
@@ -191,4 +205,14 @@ type ProvisionConfigInternal struct {
 	Labels          map[string]string
 	VPCCIDRBlock    string
 	SubnetCIDRBlock string
+
+	EnableAutoscaling bool
+	Autoscaling       provisioner.AutoscalingConfig
+
+	EnableBinaryAuthorization bool
+
+	EnableVPA bool
+
+	DedicatedNodePool bool
+	DedicatedWorkload string
 }
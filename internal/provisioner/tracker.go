@@ -2,22 +2,46 @@ package provisioner
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 
 	"github.com/alvesdmateus/app-deployer/internal/state"
 )
 
+// progressTTL is how long a provisioning run's progress stays available for replay
+const progressTTL = 24 * time.Hour
+
+// ProgressKey returns the Redis list a provisioning run's progress lines are stored in. The
+// Pub/Sub channel new lines are announced on has the same name.
+func ProgressKey(infraID string) string {
+	return "deployer:infra-progress:" + infraID
+}
+
+// ProgressMessage is published on an infrastructure's progress channel. Seq is the line's
+// position in the progress list, starting at 1; the final message has Done set and no line.
+type ProgressMessage struct {
+	Seq    int64  `json:"seq"`
+	Line   string `json:"line,omitempty"`
+	Done   bool   `json:"done,omitempty"`
+	Status string `json:"status,omitempty"` // READY or FAILED, set on the final message
+}
+
 // Tracker tracks infrastructure provisioning state in the database
 type Tracker struct {
-	repo *state.Repository
+	repo     *state.Repository
+	progress *redis.Client
 }
 
-// NewTracker creates a new infrastructure tracker
-func NewTracker(repo *state.Repository) *Tracker {
-	return &Tracker{repo: repo}
+// NewTracker creates a new infrastructure tracker. When progress is not nil, provisioning
+// output is also streamed through it line by line for the API to tail.
+func NewTracker(repo *state.Repository, progress *redis.Client) *Tracker {
+	return &Tracker{repo: repo, progress: progress}
 }
 
 // StartProvisioning creates an infrastructure record and marks deployment as PROVISIONING
@@ -39,6 +63,7 @@ func (t *Tracker) StartProvisioning(ctx context.Context, deploymentID, stackName
 			Str("infraID", existing.ID.String()).
 			Str("status", existing.Status).
 			Msg("Infrastructure already exists, reusing")
+		t.resetProgress(ctx, existing.ID.String())
 		return existing.ID.String(), nil
 	}
 
@@ -49,6 +74,7 @@ func (t *Tracker) StartProvisioning(ctx context.Context, deploymentID, stackName
 			Str("infraID", existingByStack.ID.String()).
 			Str("stackName", stackName).
 			Msg("Infrastructure found by stack name, reusing")
+		t.resetProgress(ctx, existingByStack.ID.String())
 		return existingByStack.ID.String(), nil
 	}
 
@@ -66,6 +92,11 @@ func (t *Tracker) StartProvisioning(ctx context.Context, deploymentID, stackName
 		return "", fmt.Errorf("failed to create infrastructure record: %w", err)
 	}
 
+	// Link deployment to its infrastructure
+	if err := t.repo.SetDeploymentInfrastructure(ctx, depID, infra.ID); err != nil {
+		log.Warn().Err(err).Msg("Failed to link deployment to infrastructure")
+	}
+
 	// Update deployment status to PROVISIONING
 	if err := t.repo.UpdateDeploymentStatus(ctx, depID, "PROVISIONING"); err != nil {
 		log.Warn().Err(err).Msg("Failed to update deployment status to PROVISIONING")
@@ -95,7 +126,68 @@ func (t *Tracker) UpdateProgress(ctx context.Context, infraID, logEntry string)
 		return fmt.Errorf("invalid infrastructure ID: %w", err)
 	}
 
-	return t.repo.AppendProvisionLog(ctx, id, logEntry)
+	if err := t.repo.AppendProvisionLog(ctx, id, logEntry); err != nil {
+		return err
+	}
+
+	t.streamProgress(ctx, infraID, logEntry)
+	return nil
+}
+
+// streamProgress stores each line of logEntry in the run's progress list and announces it.
+// Streaming is best effort; the provision log in the database stays the record.
+func (t *Tracker) streamProgress(ctx context.Context, infraID, logEntry string) {
+	if t.progress == nil {
+		return
+	}
+
+	key := ProgressKey(infraID)
+	for _, line := range strings.Split(strings.TrimRight(logEntry, "\n"), "\n") {
+		seq, err := t.progress.RPush(ctx, key, line).Result()
+		if err != nil {
+			log.Warn().Err(err).Str("infraID", infraID).Msg("Failed to store provisioning progress")
+			return
+		}
+
+		t.publishProgress(ctx, infraID, ProgressMessage{Seq: seq, Line: line})
+	}
+}
+
+// resetProgress drops the progress of an earlier run before infrastructure is provisioned again
+func (t *Tracker) resetProgress(ctx context.Context, infraID string) {
+	if t.progress == nil {
+		return
+	}
+
+	if err := t.progress.Del(ctx, ProgressKey(infraID)).Err(); err != nil {
+		log.Warn().Err(err).Str("infraID", infraID).Msg("Failed to reset provisioning progress")
+	}
+}
+
+// finishProgress tells progress subscribers that the run ended with status
+func (t *Tracker) finishProgress(ctx context.Context, infraID, status string) {
+	if t.progress == nil {
+		return
+	}
+
+	t.publishProgress(ctx, infraID, ProgressMessage{Done: true, Status: status})
+}
+
+// publishProgress announces a message on the run's progress channel and keeps its list alive
+func (t *Tracker) publishProgress(ctx context.Context, infraID string, msg ProgressMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	key := ProgressKey(infraID)
+	if _, err := t.progress.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Expire(ctx, key, progressTTL)
+		pipe.Publish(ctx, key, data)
+		return nil
+	}); err != nil {
+		log.Warn().Err(err).Str("infraID", infraID).Msg("Failed to publish provisioning progress")
+	}
 }
 
 // CompleteProvisioning marks provisioning as complete and updates infrastructure details
@@ -128,13 +220,24 @@ func (t *Tracker) CompleteProvisioning(ctx context.Context, infraID string, resu
 	infra.SubnetName = result.SubnetName
 	infra.SubnetCIDR = result.SubnetCIDR
 	infra.ServiceAccountEmail = result.ServiceAccount
+	infra.VPCServicePerimeter = result.VPCServicePerimeter
+	infra.DedicatedWorkload = result.DedicatedWorkload
 	infra.Namespace = result.Namespace
+	infra.DatabaseConnectionName = result.DatabaseConnectionName
+	infra.DatabaseHost = result.DatabaseHost
+	infra.DatabasePort = result.DatabasePort
+	infra.DatabaseName = result.DatabaseName
+	infra.DatabaseUser = result.DatabaseUser
+	infra.RedisHost = result.RedisHost
+	infra.RedisPort = result.RedisPort
 	infra.ProvisionLog += result.ProvisionLog
 
 	if err := t.repo.UpdateInfrastructure(ctx, infra); err != nil {
 		return fmt.Errorf("failed to update infrastructure: %w", err)
 	}
 
+	t.finishProgress(ctx, infraID, infra.Status)
+
 	// Update deployment status to DEPLOYING (ready for K8s deployment phase)
 	if err := t.repo.UpdateDeploymentStatus(ctx, infra.DeploymentID, "DEPLOYING"); err != nil {
 		log.Warn().Err(err).Msg("Failed to update deployment status to DEPLOYING")
@@ -175,6 +278,8 @@ func (t *Tracker) FailProvisioning(ctx context.Context, infraID string, provisio
 		return fmt.Errorf("failed to update infrastructure: %w", err)
 	}
 
+	t.finishProgress(ctx, infraID, infra.Status)
+
 	// Update deployment status to FAILED
 	if err := t.repo.UpdateDeploymentStatus(ctx, infra.DeploymentID, "FAILED"); err != nil {
 		log.Warn().Err(err).Msg("Failed to update deployment status to FAILED")
@@ -207,3 +312,14 @@ func (t *Tracker) GetInfrastructureByDeployment(ctx context.Context, deploymentI
 
 	return t.repo.GetInfrastructure(ctx, depID)
 }
+
+// GetStackBackend returns the state backend recorded for a stack, or an empty string when the
+// stack is unknown or lives in the configured default backend
+func (t *Tracker) GetStackBackend(ctx context.Context, stackName string) string {
+	infra, err := t.repo.GetInfrastructureByStackName(ctx, stackName)
+	if err != nil {
+		return ""
+	}
+
+	return infra.PulumiBackendURL
+}
@@ -7,6 +7,13 @@ import (
 	"github.com/alvesdmateus/app-deployer/internal/analyzer"
 )
 
+// DedicatedPoolLabel is the node label and taint key of dedicated node pools, whose value names
+// the workload the pool is reserved for
+const DedicatedPoolLabel = "workload"
+
+// DefaultDedicatedWorkload is the workload dedicated node pools are reserved for unless named
+const DefaultDedicatedWorkload = "production"
+
 // Provisioner defines the interface for infrastructure provisioning
 type Provisioner interface {
 	// Provision creates all required infrastructure
@@ -20,6 +27,21 @@ type Provisioner interface {
 
 	// VerifyAccess verifies cloud provider access
 	VerifyAccess(ctx context.Context) error
+
+	// ListResources lists the live cloud resources managed by a stack
+	ListResources(ctx context.Context, stackName string) ([]CloudResource, error)
+
+	// UpdateNodePool resizes or changes the machine type of a stack's node pool in place
+	UpdateNodePool(ctx context.Context, stackName string, config NodePoolUpdateConfig) error
+
+	// BindWorkloadIdentity lets a Kubernetes service account act as a cloud service account
+	BindWorkloadIdentity(ctx context.Context, req *WorkloadIdentityRequest) (*WorkloadIdentityResult, error)
+
+	// MigrateBackend copies a stack's state and config from one state backend to another
+	MigrateBackend(ctx context.Context, req *BackendMigrationRequest) error
+
+	// ListStacks lists the names of the stacks in the configured state backend
+	ListStacks(ctx context.Context) ([]string, error)
 }
 
 // ProvisionRequest contains all info needed to provision infrastructure
@@ -36,6 +58,9 @@ type ProvisionRequest struct {
 	// Optional configuration overrides
 	Config *ProvisionConfig
 
+	// Optional managed services (databases, caches) to provision with the cluster
+	Addons []AddonConfig
+
 	// Infrastructure ID (if already created)
 	InfrastructureID string
 }
@@ -56,10 +81,42 @@ type ProvisionConfig struct {
 	VPCCIDRBlock    string
 	SubnetCIDRBlock string
 
-	// Advanced options
-	EnableAutoScaling bool
-	MinNodes          int
-	MaxNodes          int
+	// Node pool autoscaling, NodeCount is the initial size when enabled
+	EnableAutoscaling bool
+	Autoscaling       AutoscalingConfig
+
+	// Vertical Pod Autoscaling in recommendation mode; workloads get resource recommendations
+	// but are never resized
+	EnableVPA bool
+
+	// Binary Authorization enforcement, nil leaves it disabled
+	BinaryAuth *BinaryAuthConfig
+
+	// VPC Service Controls perimeter the cluster is put inside, nil leaves it outside any
+	VPCServiceControls *VPCServiceControlsConfig
+
+	// A second node pool tainted DedicatedPoolLabel=DedicatedWorkload:NoSchedule and reserved
+	// for the app's pods; DedicatedWorkload defaults to DefaultDedicatedWorkload
+	DedicatedNodePool bool
+	DedicatedWorkload string
+}
+
+// VPCServiceControlsConfig names the perimeter a cluster's service account is added to
+type VPCServiceControlsConfig struct {
+	ServicePerimeterName string // accessPolicies/<policy>/servicePerimeters/<name>
+}
+
+// BinaryAuthConfig makes a cluster only run images its project's Binary Authorization policy admits
+type BinaryAuthConfig struct {
+	Enable bool
+	Policy string // Policy YAML applied to the project before provisioning, empty keeps the current policy
+}
+
+// AutoscalingConfig bounds the node pool size managed by the cluster autoscaler
+type AutoscalingConfig struct {
+	MinNodes       int           `json:"min_nodes"`
+	MaxNodes       int           `json:"max_nodes"`
+	ScaleDownDelay time.Duration `json:"scale_down_delay,omitempty"` // Below 10m removes idle nodes more aggressively
 }
 
 // ProvisionResult contains the result of provisioning
@@ -76,8 +133,26 @@ type ProvisionResult struct {
 	SubnetCIDR        string
 	Namespace         string
 	ServiceAccount    string
-	ProvisionLog      string
-	Duration          time.Duration
+
+	// VPC Service Controls perimeter the service account was added to, empty when none
+	VPCServicePerimeter string
+
+	// Workload the dedicated node pool is reserved for, empty when there is none
+	DedicatedWorkload string
+
+	// Cloud SQL addon outputs (empty when the addon is not provisioned)
+	DatabaseConnectionName string
+	DatabaseHost           string
+	DatabasePort           int
+	DatabaseName           string
+	DatabaseUser           string
+
+	// Memorystore addon outputs (empty when the addon is not provisioned)
+	RedisHost string
+	RedisPort int
+
+	ProvisionLog string
+	Duration     time.Duration
 }
 
 // DestroyRequest contains info for destroying infrastructure
@@ -87,6 +162,35 @@ type DestroyRequest struct {
 	StackName        string
 }
 
+// NodePoolUpdateConfig holds the node pool settings to change; zero values keep the current setting
+type NodePoolUpdateConfig struct {
+	InfrastructureID string // Used to record update progress
+	NodeCount        int
+	MachineType      string
+}
+
+// BackendMigrationRequest identifies a stack and the state backends to move it between
+type BackendMigrationRequest struct {
+	StackName   string
+	FromBackend string // Empty for the backend the stack is currently recorded in
+	ToBackend   string
+}
+
+// WorkloadIdentityRequest contains info for binding an app's Kubernetes service account
+type WorkloadIdentityRequest struct {
+	DeploymentID             string
+	InfrastructureID         string
+	AppName                  string
+	Namespace                string
+	KubernetesServiceAccount string
+	GCPServiceAccountEmail   string // Existing account to bind; a new one is created when empty
+}
+
+// WorkloadIdentityResult contains the bound cloud service account
+type WorkloadIdentityResult struct {
+	GCPServiceAccountEmail string
+}
+
 // InfrastructureStatus represents current infrastructure state
 type InfrastructureStatus struct {
 	Status      string // "PROVISIONING", "READY", "FAILED", "DESTROYING"
@@ -94,3 +198,12 @@ type InfrastructureStatus struct {
 	LastUpdated time.Time
 	Outputs     map[string]interface{}
 }
+
+// CloudResource represents a single cloud resource tracked in a stack's state
+type CloudResource struct {
+	Type       string                 `json:"type"`
+	Name       string                 `json:"name"`
+	URN        string                 `json:"urn"`
+	Status     string                 `json:"status"` // "created", "pending_delete", or a pending operation type
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
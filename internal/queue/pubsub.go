@@ -0,0 +1,368 @@
+package queue
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/googleapi"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+const (
+	// pubsubAckDeadline is how long Pub/Sub waits for a pulled job to be acknowledged before
+	// redelivering it; held jobs have it extended every pubsubLeaseInterval
+	pubsubAckDeadline   = 120 * time.Second
+	pubsubLeaseInterval = 60 * time.Second
+
+	// pubsubMaxAckDeadline is the longest Pub/Sub lets a message be held back for
+	pubsubMaxAckDeadline = 600 * time.Second
+
+	// notBeforeAttribute holds the Unix time a delayed job becomes due
+	notBeforeAttribute = "not_before"
+)
+
+// PubSubConfig holds Pub/Sub queue configuration
+type PubSubConfig struct {
+	Project     string
+	TopicPrefix string // Default: deployer
+}
+
+// heldMessage is a pulled job that has not been acknowledged yet
+type heldMessage struct {
+	subscription string
+	ackID        string
+}
+
+// PubSubQueue implements a job queue using GCP Cloud Pub/Sub. Each job type has a topic
+// named <prefix>-<type> and one subscription all worker processes pull from, so each job
+// is delivered to one of them. A pulled job stays leased until it is completed, failed or
+// enqueued again, and is redelivered if its worker dies first.
+type PubSubQueue struct {
+	service     *pubsub.Service
+	project     string
+	topicPrefix string
+
+	mu   sync.Mutex
+	held map[string]heldMessage // Keyed by job ID
+
+	stopLease context.CancelFunc
+	leaseDone chan struct{}
+}
+
+var _ Queue = (*PubSubQueue)(nil)
+
+// NewPubSubQueue creates a Pub/Sub job queue using Application Default Credentials. Missing
+// topics and subscriptions are created, so jobs published before any worker starts are kept.
+func NewPubSubQueue(ctx context.Context, config PubSubConfig) (*PubSubQueue, error) {
+	if config.Project == "" {
+		return nil, fmt.Errorf("GCP project is required for the Pub/Sub queue")
+	}
+
+	if config.TopicPrefix == "" {
+		config.TopicPrefix = "deployer"
+	}
+
+	service, err := pubsub.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+
+	q := &PubSubQueue{
+		service:     service,
+		project:     config.Project,
+		topicPrefix: config.TopicPrefix,
+		held:        make(map[string]heldMessage),
+	}
+
+	for _, jobType := range JobTypes {
+		if err := q.ensureSubscription(ctx, jobType); err != nil {
+			return nil, err
+		}
+	}
+
+	leaseCtx, stopLease := context.WithCancel(context.Background())
+	q.stopLease = stopLease
+	q.leaseDone = make(chan struct{})
+	go q.extendLeases(leaseCtx)
+
+	log.Info().
+		Str("project", config.Project).
+		Str("topic_prefix", config.TopicPrefix).
+		Msg("Pub/Sub queue connected successfully")
+
+	return q, nil
+}
+
+// topicName returns the full name of the topic for a job type, e.g. deployer-update-infra
+func (q *PubSubQueue) topicName(jobType JobType) string {
+	return fmt.Sprintf("projects/%s/topics/%s-%s", q.project, q.topicPrefix,
+		strings.ReplaceAll(string(jobType), "_", "-"))
+}
+
+// subscriptionName returns the full name of the subscription workers pull a job type from
+func (q *PubSubQueue) subscriptionName(jobType JobType) string {
+	return fmt.Sprintf("projects/%s/subscriptions/%s-%s-worker", q.project, q.topicPrefix,
+		strings.ReplaceAll(string(jobType), "_", "-"))
+}
+
+// ensureSubscription creates the topic and subscription for a job type unless they exist
+func (q *PubSubQueue) ensureSubscription(ctx context.Context, jobType JobType) error {
+	topic := q.topicName(jobType)
+	if _, err := q.service.Projects.Topics.Create(topic, &pubsub.Topic{}).Context(ctx).Do(); err != nil && !isAlreadyExists(err) {
+		return fmt.Errorf("failed to create topic %s: %w", topic, err)
+	}
+
+	subscription := q.subscriptionName(jobType)
+	if _, err := q.service.Projects.Subscriptions.Create(subscription, &pubsub.Subscription{
+		Topic:              topic,
+		AckDeadlineSeconds: int64(pubsubAckDeadline / time.Second),
+	}).Context(ctx).Do(); err != nil && !isAlreadyExists(err) {
+		return fmt.Errorf("failed to create subscription %s: %w", subscription, err)
+	}
+
+	return nil
+}
+
+// Enqueue publishes a job to its topic
+func (q *PubSubQueue) Enqueue(ctx context.Context, job *Job) error {
+	return q.publish(ctx, job, nil)
+}
+
+// EnqueueDelayed publishes a job that is not handed out until delay has passed. Pub/Sub
+// cannot delay delivery, so a job pulled early is put back until it is due.
+func (q *PubSubQueue) EnqueueDelayed(ctx context.Context, job *Job, delay time.Duration) error {
+	notBefore := time.Now().Add(delay).Unix()
+	return q.publish(ctx, job, map[string]string{notBeforeAttribute: strconv.FormatInt(notBefore, 10)})
+}
+
+// publish sends a job to its topic. A job that is held, such as one being retried, has its
+// earlier message acknowledged once the new one is published.
+func (q *PubSubQueue) publish(ctx context.Context, job *Job, attributes map[string]string) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	_, err = q.service.Projects.Topics.Publish(q.topicName(job.Type), &pubsub.PublishRequest{
+		Messages: []*pubsub.PubsubMessage{{
+			Data:       base64.StdEncoding.EncodeToString(data),
+			Attributes: attributes,
+		}},
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return q.ack(ctx, job.ID)
+}
+
+// PromoteDelayedJobs is a no-op; delayed jobs stay on their topic until they are due
+func (q *PubSubQueue) PromoteDelayedJobs(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+// Dequeue pulls a job from the subscription for jobType, waiting up to timeout. The job is
+// leased until it is marked complete or failed, or enqueued again.
+func (q *PubSubQueue) Dequeue(ctx context.Context, jobType JobType, timeout time.Duration) (*Job, error) {
+	subscription := q.subscriptionName(jobType)
+
+	pullCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := q.service.Projects.Subscriptions.Pull(subscription, &pubsub.PullRequest{
+		MaxMessages: 1,
+	}).Context(pullCtx).Do()
+	if err != nil {
+		if ctx.Err() == nil && pullCtx.Err() != nil {
+			// No job available within timeout - this is normal
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to dequeue job: %w", err)
+	}
+
+	if len(resp.ReceivedMessages) == 0 {
+		return nil, nil
+	}
+	received := resp.ReceivedMessages[0]
+
+	// Put a delayed job back until it is due
+	if due, ok := received.Message.Attributes[notBeforeAttribute]; ok {
+		if notBefore, err := strconv.ParseInt(due, 10, 64); err == nil {
+			if wait := time.Until(time.Unix(notBefore, 0)); wait > 0 {
+				return nil, q.modifyAckDeadline(ctx, subscription, []string{received.AckId}, min(wait, pubsubMaxAckDeadline))
+			}
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(received.Message.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		// A message that is not a job would be redelivered forever
+		_ = q.acknowledge(ctx, subscription, received.AckId)
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+
+	q.mu.Lock()
+	q.held[job.ID] = heldMessage{subscription: subscription, ackID: received.AckId}
+	q.mu.Unlock()
+
+	log.Debug().
+		Str("jobID", job.ID).
+		Str("type", string(job.Type)).
+		Str("deploymentID", job.DeploymentID).
+		Msg("Job dequeued")
+
+	return &job, nil
+}
+
+// MarkComplete acknowledges a job so it is not delivered again
+func (q *PubSubQueue) MarkComplete(ctx context.Context, jobID string) error {
+	if err := q.ack(ctx, jobID); err != nil {
+		return fmt.Errorf("failed to mark job as complete: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed acknowledges a job that will not be retried
+func (q *PubSubQueue) MarkFailed(ctx context.Context, jobID string, jobErr error) error {
+	if err := q.ack(ctx, jobID); err != nil {
+		return fmt.Errorf("failed to mark job as failed: %w", err)
+	}
+
+	log.Warn().
+		Str("jobID", jobID).
+		Err(jobErr).
+		Msg("Job marked as failed")
+
+	return nil
+}
+
+// GetQueueLength is not supported; Pub/Sub only reports backlog through Cloud Monitoring
+func (q *PubSubQueue) GetQueueLength(ctx context.Context, jobType JobType) (int64, error) {
+	return 0, ErrQueueLengthUnsupported
+}
+
+// Ping checks that the Pub/Sub API is reachable
+func (q *PubSubQueue) Ping(ctx context.Context) error {
+	if _, err := q.service.Projects.Topics.Get(q.topicName(JobTypeProvision)).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("pubsub ping failed: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops extending leases and hands jobs still held back to Pub/Sub for redelivery
+func (q *PubSubQueue) Close() error {
+	q.stopLease()
+	<-q.leaseDone
+
+	q.mu.Lock()
+	held := q.held
+	q.held = make(map[string]heldMessage)
+	q.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for subscription, ackIDs := range groupBySubscription(held) {
+		if err := q.modifyAckDeadline(ctx, subscription, ackIDs, 0); err != nil {
+			return fmt.Errorf("failed to release held jobs: %w", err)
+		}
+	}
+
+	log.Info().Msg("Pub/Sub queue closed")
+	return nil
+}
+
+// ack acknowledges the message a job was pulled in, if it is held
+func (q *PubSubQueue) ack(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	msg, ok := q.held[jobID]
+	delete(q.held, jobID)
+	q.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return q.acknowledge(ctx, msg.subscription, msg.ackID)
+}
+
+// acknowledge acknowledges a message on subscription
+func (q *PubSubQueue) acknowledge(ctx context.Context, subscription, ackID string) error {
+	_, err := q.service.Projects.Subscriptions.Acknowledge(subscription, &pubsub.AcknowledgeRequest{
+		AckIds: []string{ackID},
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge message: %w", err)
+	}
+
+	return nil
+}
+
+// modifyAckDeadline sets how long until the messages are redelivered; 0 redelivers them now
+func (q *PubSubQueue) modifyAckDeadline(ctx context.Context, subscription string, ackIDs []string, deadline time.Duration) error {
+	_, err := q.service.Projects.Subscriptions.ModifyAckDeadline(subscription, &pubsub.ModifyAckDeadlineRequest{
+		AckIds:             ackIDs,
+		AckDeadlineSeconds: int64(deadline / time.Second),
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to modify ack deadline: %w", err)
+	}
+
+	return nil
+}
+
+// extendLeases keeps held jobs from being redelivered while they run
+func (q *PubSubQueue) extendLeases(ctx context.Context) {
+	defer close(q.leaseDone)
+
+	ticker := time.NewTicker(pubsubLeaseInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.mu.Lock()
+			bySubscription := groupBySubscription(q.held)
+			q.mu.Unlock()
+
+			for subscription, ackIDs := range bySubscription {
+				if err := q.modifyAckDeadline(ctx, subscription, ackIDs, pubsubAckDeadline); err != nil {
+					log.Error().Err(err).Str("subscription", subscription).Msg("Failed to extend job leases")
+				}
+			}
+		}
+	}
+}
+
+// groupBySubscription returns the ack IDs of held messages by subscription
+func groupBySubscription(held map[string]heldMessage) map[string][]string {
+	grouped := make(map[string][]string)
+	for _, msg := range held {
+		grouped[msg.subscription] = append(grouped[msg.subscription], msg.ackID)
+	}
+	return grouped
+}
+
+// isAlreadyExists reports whether err is Pub/Sub refusing to create an existing resource
+func isAlreadyExists(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict
+}
@@ -10,11 +10,37 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// delayedQueueKey is the sorted set delayed jobs wait in, scored by the time they are due
+const delayedQueueKey = "queue:delayed"
+
+// promoteDelayedScript moves the jobs of KEYS[1] scored up to ARGV[1] onto the list ARGV[2]
+// followed by their type. Running as a script makes the move atomic, so a job is neither lost
+// between removal and push nor promoted twice by concurrent workers. It returns how many were
+// promoted and how many were dropped because they could not be decoded.
+var promoteDelayedScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+local promoted, dropped = 0, 0
+for _, data in ipairs(due) do
+	if redis.call('ZREM', KEYS[1], data) == 1 then
+		local ok, job = pcall(cjson.decode, data)
+		if ok and type(job) == 'table' and type(job.type) == 'string' then
+			redis.call('RPUSH', ARGV[2] .. job.type, data)
+			promoted = promoted + 1
+		else
+			dropped = dropped + 1
+		end
+	end
+end
+return {promoted, dropped}
+`)
+
 // RedisQueue implements a job queue using Redis
 type RedisQueue struct {
 	client *redis.Client
 }
 
+var _ Queue = (*RedisQueue)(nil)
+
 // NewRedisQueue creates a new Redis-based job queue
 func NewRedisQueue(url, password string, db int) (*RedisQueue, error) {
 	// Parse Redis URL if needed (for now, assume simple host:port format)
@@ -65,6 +91,47 @@ func (q *RedisQueue) Enqueue(ctx context.Context, job *Job) error {
 	return nil
 }
 
+// EnqueueDelayed adds a job to the queue once delay has passed
+func (q *RedisQueue) EnqueueDelayed(ctx context.Context, job *Job, delay time.Duration) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	readyAt := time.Now().Add(delay).UnixMilli()
+	if err := q.client.ZAdd(ctx, delayedQueueKey, redis.Z{Score: float64(readyAt), Member: data}).Err(); err != nil {
+		return fmt.Errorf("failed to enqueue delayed job: %w", err)
+	}
+
+	log.Info().
+		Str("jobID", job.ID).
+		Str("type", string(job.Type)).
+		Str("deploymentID", job.DeploymentID).
+		Dur("delay", delay).
+		Msg("Job delayed")
+
+	return nil
+}
+
+// PromoteDelayedJobs moves delayed jobs that are due onto their queues and returns how many moved
+func (q *RedisQueue) PromoteDelayedJobs(ctx context.Context) (int, error) {
+	counts, err := promoteDelayedScript.Run(ctx, q.client, []string{delayedQueueKey},
+		time.Now().UnixMilli(), "queue:").Int64Slice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to promote delayed jobs: %w", err)
+	}
+
+	if len(counts) != 2 {
+		return 0, fmt.Errorf("unexpected delayed job promotion result: %v", counts)
+	}
+
+	if counts[1] > 0 {
+		log.Error().Int64("count", counts[1]).Msg("Dropped delayed jobs that cannot be decoded")
+	}
+
+	return int(counts[0]), nil
+}
+
 // Dequeue retrieves and removes a job from the queue (blocking)
 func (q *RedisQueue) Dequeue(ctx context.Context, jobType JobType, timeout time.Duration) (*Job, error) {
 	queueKey := fmt.Sprintf("queue:%s", jobType)
@@ -188,6 +255,67 @@ func (q *RedisQueue) GetQueueLength(ctx context.Context, jobType JobType) (int64
 	return length, nil
 }
 
+// GetCache retrieves a cached value, returning nil if the key does not exist
+func (q *RedisQueue) GetCache(ctx context.Context, key string) ([]byte, error) {
+	data, err := q.client.Get(ctx, fmt.Sprintf("cache:%s", key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cached value: %w", err)
+	}
+
+	return data, nil
+}
+
+// SetCache stores a value in the cache with the given TTL
+func (q *RedisQueue) SetCache(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if err := q.client.Set(ctx, fmt.Sprintf("cache:%s", key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cached value: %w", err)
+	}
+
+	return nil
+}
+
+// IncrementCounter increments a counter that expires after window and returns its new value
+// along with the time left before it resets
+func (q *RedisQueue) IncrementCounter(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	counterKey := fmt.Sprintf("counter:%s", key)
+
+	count, err := q.client.Incr(ctx, counterKey).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to increment counter: %w", err)
+	}
+
+	// The first increment of a window starts its expiry
+	if count == 1 {
+		if err := q.client.Expire(ctx, counterKey, window).Err(); err != nil {
+			return 0, 0, fmt.Errorf("failed to set counter expiry: %w", err)
+		}
+		return count, window, nil
+	}
+
+	ttl, err := q.client.TTL(ctx, counterKey).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get counter expiry: %w", err)
+	}
+
+	// Recover a counter left without an expiry by an interrupted first increment
+	if ttl < 0 {
+		if err := q.client.Expire(ctx, counterKey, window).Err(); err != nil {
+			return 0, 0, fmt.Errorf("failed to set counter expiry: %w", err)
+		}
+		ttl = window
+	}
+
+	return count, ttl, nil
+}
+
+// Client returns the underlying Redis client, for stores that share the connection
+func (q *RedisQueue) Client() *redis.Client {
+	return q.client
+}
+
 // Close closes the Redis connection
 func (q *RedisQueue) Close() error {
 	if err := q.client.Close(); err != nil {
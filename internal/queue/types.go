@@ -1,9 +1,38 @@
 package queue
 
 import (
+	"context"
+	"errors"
 	"time"
+
+	"github.com/alvesdmateus/app-deployer/internal/provisioner"
 )
 
+// ErrQueueLengthUnsupported is returned by queues that cannot count the jobs waiting in them
+var ErrQueueLengthUnsupported = errors.New("queue length is not available for this queue backend")
+
+// Queue is a job queue the orchestrator enqueues jobs on and workers take them from
+type Queue interface {
+	// Enqueue adds a job to the queue
+	Enqueue(ctx context.Context, job *Job) error
+	// EnqueueDelayed adds a job that is not handed out until delay has passed
+	EnqueueDelayed(ctx context.Context, job *Job, delay time.Duration) error
+	// PromoteDelayedJobs makes delayed jobs that are due available, returning how many were
+	PromoteDelayedJobs(ctx context.Context) (int, error)
+	// Dequeue takes the next job of a type, waiting up to timeout; nil means none arrived
+	Dequeue(ctx context.Context, jobType JobType, timeout time.Duration) (*Job, error)
+	// MarkComplete records that a dequeued job succeeded
+	MarkComplete(ctx context.Context, jobID string) error
+	// MarkFailed records that a dequeued job failed and will not be retried
+	MarkFailed(ctx context.Context, jobID string, jobErr error) error
+	// GetQueueLength returns the number of jobs waiting in a queue
+	GetQueueLength(ctx context.Context, jobType JobType) (int64, error)
+	// Ping checks that the queue backend is reachable
+	Ping(ctx context.Context) error
+	// Close releases the queue's connections
+	Close() error
+}
+
 // JobType represents the type of job to be processed
 type JobType string
 
@@ -19,8 +48,45 @@ const (
 
 	// JobTypeRollback represents a rollback job
 	JobTypeRollback JobType = "rollback"
+
+	// JobTypeReconcile represents an infrastructure drift detection job
+	JobTypeReconcile JobType = "reconcile"
+
+	// JobTypeUpdateInfra represents an in-place infrastructure update job
+	JobTypeUpdateInfra JobType = "update_infra"
+
+	// JobTypeMigrateBackend represents a Pulumi state backend migration job
+	JobTypeMigrateBackend JobType = "migrate_backend"
+
+	// JobTypeDestroyStack represents a job destroying a single orphaned Pulumi stack
+	JobTypeDestroyStack JobType = "destroy_stack"
+
+	// JobTypeNotify represents a webhook notification delivery job
+	JobTypeNotify JobType = "notify"
+
+	// JobTypeBuild represents a job building a pushed commit and deploying the image
+	JobTypeBuild JobType = "build"
+
+	// JobTypeRestart represents a rolling restart of a deployment's pods
+	JobTypeRestart JobType = "restart"
 )
 
+// JobTypes lists every job type. Queues create a topic for each and workers take jobs from
+// them in this order, so a new type must be added here.
+var JobTypes = []JobType{
+	JobTypeProvision,
+	JobTypeDeploy,
+	JobTypeDestroy,
+	JobTypeRollback,
+	JobTypeReconcile,
+	JobTypeUpdateInfra,
+	JobTypeMigrateBackend,
+	JobTypeDestroyStack,
+	JobTypeNotify,
+	JobTypeBuild,
+	JobTypeRestart,
+}
+
 // Job represents a work item in the queue
 type Job struct {
 	ID           string                 `json:"id"`
@@ -30,6 +96,9 @@ type Job struct {
 	CreatedAt    time.Time              `json:"created_at"`
 	Attempts     int                    `json:"attempts"`
 	MaxAttempts  int                    `json:"max_attempts"`
+
+	// Times the job was delayed waiting for the deployment's dependencies
+	DependencyWaits int `json:"dependency_waits,omitempty"`
 }
 
 // ProvisionPayload contains data for a provision job
@@ -46,6 +115,19 @@ type ProvisionPayload struct {
 	NodeCount   int    `json:"node_count,omitempty"`   // Default: 2
 	MachineType string `json:"machine_type,omitempty"` // Default: e2-small
 	Replicas    int    `json:"replicas,omitempty"`     // Default: 2
+
+	// Optional node pool autoscaling; NodeCount is the initial size when set
+	Autoscaling *provisioner.AutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// Optional primary IP range of the cluster subnet
+	SubnetCIDRBlock string `json:"subnet_cidr_block,omitempty"`
+
+	// Optional tainted node pool reserved for the app, DedicatedWorkload names its taint value
+	DedicatedNodePool bool   `json:"dedicated_node_pool,omitempty"`
+	DedicatedWorkload string `json:"dedicated_workload,omitempty"`
+
+	// Optional managed services to provision with the cluster
+	Addons []provisioner.AddonConfig `json:"addons,omitempty"`
 }
 
 // DeployPayload contains data for a deploy job
@@ -61,6 +143,7 @@ type DeployPayload struct {
 type DestroyPayload struct {
 	DeploymentID     string `json:"deployment_id"`
 	InfrastructureID string `json:"infrastructure_id"`
+	DeleteVolumes    bool   `json:"delete_volumes,omitempty"` // Also delete persistent volume claims
 }
 
 // RollbackPayload contains data for a rollback job
@@ -68,4 +151,60 @@ type RollbackPayload struct {
 	DeploymentID  string `json:"deployment_id"`
 	TargetVersion string `json:"target_version"`
 	TargetTag     string `json:"target_tag"`
+	Revision      int    `json:"revision,omitempty"` // Helm revision; 0 for the previous one
+}
+
+// ReconcilePayload contains data for a reconcile job
+type ReconcilePayload struct {
+	DeploymentID     string `json:"deployment_id"`
+	InfrastructureID string `json:"infrastructure_id"`
+}
+
+// UpdateInfraPayload contains data for an infrastructure update job
+type UpdateInfraPayload struct {
+	DeploymentID     string `json:"deployment_id"`
+	InfrastructureID string `json:"infrastructure_id"`
+	NodeCount        int    `json:"node_count,omitempty"`   // Zero keeps the current node count
+	MachineType      string `json:"machine_type,omitempty"` // Empty keeps the current machine type
+}
+
+// MigrateBackendPayload contains data for a state backend migration job
+type MigrateBackendPayload struct {
+	DeploymentID     string `json:"deployment_id"`
+	InfrastructureID string `json:"infrastructure_id"`
+	StackName        string `json:"stack_name"`
+	FromBackend      string `json:"from_backend,omitempty"` // Empty for the stack's current backend
+	ToBackend        string `json:"to_backend"`
+}
+
+// DestroyStackPayload contains data for an orphaned stack destroy job
+type DestroyStackPayload struct {
+	DeploymentID     string `json:"deployment_id"`
+	InfrastructureID string `json:"infrastructure_id,omitempty"` // Empty when no record owns the stack
+	StackName        string `json:"stack_name"`
+}
+
+// NotifyPayload contains data for a webhook notification job
+type NotifyPayload struct {
+	EventType    string            `json:"event_type"` // e.g. approval_requested
+	DeploymentID string            `json:"deployment_id"`
+	Message      string            `json:"message"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+// RestartPayload contains data for a rolling restart job
+type RestartPayload struct {
+	DeploymentID     string `json:"deployment_id"`
+	InfrastructureID string `json:"infrastructure_id"`
+}
+
+// BuildPayload contains data for a build job
+type BuildPayload struct {
+	DeploymentID  string `json:"deployment_id"`
+	RepoURL       string `json:"repo_url"`
+	CommitSHA     string `json:"commit_sha"`
+	Ref           string `json:"ref,omitempty"`            // e.g. refs/heads/main
+	Provider      string `json:"provider,omitempty"`       // Git provider the push came from, e.g. gitlab
+	SubPath       string `json:"sub_path,omitempty"`       // Service directory to build in a monorepo
+	BuildStrategy string `json:"build_strategy,omitempty"` // Overrides the detected strategy: existing, generated or kaniko
 }
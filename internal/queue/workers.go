@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// workerKeyPrefix prefixes the key each running worker keeps its status under. The key
+// expires unless the worker's heartbeat refreshes it, so only live workers are listed.
+const workerKeyPrefix = "deployer:workers:"
+
+// WorkerStatus is the status a running worker publishes with each heartbeat
+type WorkerStatus struct {
+	Hostname    string    `json:"hostname"`
+	PID         int       `json:"pid"`
+	Concurrency int       `json:"concurrency"` // Jobs the worker may currently run at once
+	ActiveJobs  int       `json:"active_jobs"`
+	StartedAt   time.Time `json:"started_at"`
+	Version     string    `json:"version"`
+}
+
+// SetWorkerStatus records a worker's status, expiring after ttl unless set again
+func (q *RedisQueue) SetWorkerStatus(ctx context.Context, status *WorkerStatus, ttl time.Duration) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker status: %w", err)
+	}
+
+	if err := q.client.Set(ctx, workerKeyPrefix+status.Hostname, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set worker status: %w", err)
+	}
+
+	return nil
+}
+
+// ListWorkers returns the status of every live worker, sorted by hostname
+func (q *RedisQueue) ListWorkers(ctx context.Context) ([]WorkerStatus, error) {
+	var keys []string
+	iter := q.client.Scan(ctx, 0, workerKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan worker statuses: %w", err)
+	}
+
+	workers := make([]WorkerStatus, 0, len(keys))
+	if len(keys) == 0 {
+		return workers, nil
+	}
+
+	values, err := q.client.MGet(ctx, keys...).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get worker statuses: %w", err)
+	}
+
+	// Keys that expired between the scan and the read come back nil
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var status WorkerStatus
+		if err := json.Unmarshal([]byte(data), &status); err != nil {
+			continue
+		}
+		workers = append(workers, status)
+	}
+
+	sort.Slice(workers, func(i, j int) bool {
+		return workers[i].Hostname < workers[j].Hostname
+	})
+
+	return workers, nil
+}
@@ -0,0 +1,223 @@
+package recommendations
+
+import (
+	"math"
+
+	"github.com/alvesdmateus/app-deployer/internal/analyzer"
+	"github.com/alvesdmateus/app-deployer/internal/deployer"
+	"github.com/alvesdmateus/app-deployer/internal/state"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Confidence levels of a recommendation
+const (
+	ConfidenceHeuristic  = "heuristic"   // From the language and framework profile alone
+	ConfidenceDataDriven = "data-driven" // Sized from VPA recommendations of the app's other deployments
+)
+
+// minReplicas keeps a rolling update or a node drain from taking the app down
+const minReplicas = 2
+
+// profile is the typical footprint of one replica of an app, and the requests per second it
+// serves comfortably within its CPU limit
+type profile struct {
+	cpuRequest    int64 // millicores
+	cpuLimit      int64 // millicores
+	memoryRequest int64 // MiB
+	memoryLimit   int64 // MiB
+	rpsPerReplica int
+}
+
+// defaultProfile sizes apps whose language is unknown
+var defaultProfile = profile{cpuRequest: 250, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 512, rpsPerReplica: 300}
+
+// languageProfiles sizes apps by runtime. Compiled languages need little memory; the JVM and
+// interpreted runtimes start with larger heaps and serve fewer requests per core.
+var languageProfiles = map[analyzer.Language]profile{
+	analyzer.LanguageGo:     {cpuRequest: 100, cpuLimit: 500, memoryRequest: 64, memoryLimit: 256, rpsPerReplica: 1000},
+	analyzer.LanguageRust:   {cpuRequest: 100, cpuLimit: 500, memoryRequest: 32, memoryLimit: 128, rpsPerReplica: 1500},
+	analyzer.LanguageNodeJS: {cpuRequest: 200, cpuLimit: 1000, memoryRequest: 128, memoryLimit: 512, rpsPerReplica: 500},
+	analyzer.LanguagePython: {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 512, rpsPerReplica: 200},
+	analyzer.LanguageJava:   {cpuRequest: 500, cpuLimit: 2000, memoryRequest: 512, memoryLimit: 1024, rpsPerReplica: 800},
+	analyzer.LanguageRuby:   {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 512, rpsPerReplica: 150},
+	analyzer.LanguagePHP:    {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 128, memoryLimit: 512, rpsPerReplica: 200},
+	analyzer.LanguageDotNet: {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 512, rpsPerReplica: 800},
+	analyzer.LanguageDeno:   {cpuRequest: 200, cpuLimit: 1000, memoryRequest: 128, memoryLimit: 512, rpsPerReplica: 500},
+	analyzer.LanguageElixir: {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 128, memoryLimit: 512, rpsPerReplica: 800},
+}
+
+// frameworkProfiles refines the language profile for frameworks whose footprint differs from
+// their runtime's typical one
+var frameworkProfiles = map[analyzer.Framework]profile{
+	analyzer.FrameworkFiber:      {cpuRequest: 100, cpuLimit: 500, memoryRequest: 64, memoryLimit: 256, rpsPerReplica: 1500},
+	analyzer.FrameworkFastify:    {cpuRequest: 200, cpuLimit: 1000, memoryRequest: 128, memoryLimit: 512, rpsPerReplica: 800},
+	analyzer.FrameworkNestJS:     {cpuRequest: 200, cpuLimit: 1000, memoryRequest: 192, memoryLimit: 512, rpsPerReplica: 400},
+	analyzer.FrameworkNextJS:     {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 1024, rpsPerReplica: 200},
+	analyzer.FrameworkFastAPI:    {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 128, memoryLimit: 512, rpsPerReplica: 400},
+	analyzer.FrameworkFlask:      {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 128, memoryLimit: 512, rpsPerReplica: 150},
+	analyzer.FrameworkDjango:     {cpuRequest: 300, cpuLimit: 1000, memoryRequest: 384, memoryLimit: 768, rpsPerReplica: 100},
+	analyzer.FrameworkSpringBoot: {cpuRequest: 500, cpuLimit: 2000, memoryRequest: 768, memoryLimit: 1536, rpsPerReplica: 600},
+	analyzer.FrameworkQuarkus:    {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 512, rpsPerReplica: 1000},
+	analyzer.FrameworkLaravel:    {cpuRequest: 300, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 512, rpsPerReplica: 100},
+	analyzer.FrameworkSymfony:    {cpuRequest: 300, cpuLimit: 1000, memoryRequest: 256, memoryLimit: 512, rpsPerReplica: 150},
+	analyzer.FrameworkBlazor:     {cpuRequest: 250, cpuLimit: 1000, memoryRequest: 512, memoryLimit: 1024, rpsPerReplica: 300},
+}
+
+// Request describes the app to size, as detected by the analyzer
+type Request struct {
+	Language    analyzer.Language
+	Framework   analyzer.Framework
+	ExpectedRPS int // Zero sizes for the minimum replica count
+}
+
+// Recommendation is the suggested container resources and replica count of a new deployment
+type Recommendation struct {
+	CPURequest      string
+	CPULimit        string
+	MemoryRequest   string
+	MemoryLimit     string
+	Replicas        int
+	ConfidenceLevel string
+	Samples         int // VPA recommendations the resources were sized from
+}
+
+// Recommender suggests resources for new deployments, kept within the resource policy
+type Recommender struct {
+	policy deployer.ResourceLimitPolicy
+}
+
+// NewRecommender creates a new recommender bounded by policy
+func NewRecommender(policy deployer.ResourceLimitPolicy) *Recommender {
+	return &Recommender{
+		policy: policy,
+	}
+}
+
+// Recommend sizes a deployment from the profile of its framework, or of its language. When
+// history holds VPA recommendations of the app's other deployments, requests are taken from the
+// largest of them instead, and limits from theirs or by the profile's limit to request ratio.
+// The replica count always follows from ExpectedRPS, since VPA sizes single pods.
+func (r *Recommender) Recommend(req Request, history []state.VPARecommendation) *Recommendation {
+	p, ok := frameworkProfiles[req.Framework]
+	if !ok {
+		p, ok = languageProfiles[req.Language]
+		if !ok {
+			p = defaultProfile
+		}
+	}
+
+	cpuRequest := float64(p.cpuRequest)
+	cpuLimit := float64(p.cpuLimit)
+	memoryRequest := float64(p.memoryRequest)
+	memoryLimit := float64(p.memoryLimit)
+	confidence := ConfidenceHeuristic
+
+	observed := observe(history)
+	if observed.samples > 0 {
+		confidence = ConfidenceDataDriven
+		cpuRequest = observed.cpuRequest
+		memoryRequest = observed.memoryRequest
+
+		cpuLimit = observed.cpuLimit
+		if cpuLimit < cpuRequest {
+			cpuLimit = cpuRequest * float64(p.cpuLimit) / float64(p.cpuRequest)
+		}
+		memoryLimit = observed.memoryLimit
+		if memoryLimit < memoryRequest {
+			memoryLimit = memoryRequest * float64(p.memoryLimit) / float64(p.memoryRequest)
+		}
+	}
+
+	replicas := minReplicas
+	if req.ExpectedRPS > 0 {
+		replicas = int(math.Ceil(float64(req.ExpectedRPS) / float64(p.rpsPerReplica)))
+		if replicas < minReplicas {
+			replicas = minReplicas
+		}
+	}
+
+	// Requests may not exceed limits, so both are lowered to a policy maximum
+	if max, ok := policyMax(r.policy.MaxCPULimit); ok {
+		cpuLimit = math.Min(cpuLimit, float64(max.MilliValue()))
+		cpuRequest = math.Min(cpuRequest, cpuLimit)
+	}
+	if max, ok := policyMax(r.policy.MaxMemoryLimit); ok {
+		memoryLimit = math.Min(memoryLimit, float64(max.Value())/(1<<20))
+		memoryRequest = math.Min(memoryRequest, memoryLimit)
+	}
+	if r.policy.MaxReplicas > 0 && replicas > r.policy.MaxReplicas {
+		replicas = r.policy.MaxReplicas
+	}
+
+	return &Recommendation{
+		CPURequest:      formatCPU(cpuRequest),
+		CPULimit:        formatCPU(cpuLimit),
+		MemoryRequest:   formatMemory(memoryRequest),
+		MemoryLimit:     formatMemory(memoryLimit),
+		Replicas:        replicas,
+		ConfidenceLevel: confidence,
+		Samples:         observed.samples,
+	}
+}
+
+// observation is the largest resources across VPA recommendations, in millicores and MiB
+type observation struct {
+	cpuRequest, cpuLimit       float64
+	memoryRequest, memoryLimit float64
+	samples                    int
+}
+
+// observe takes the largest requests and limits across history. Recommendations whose
+// requests do not parse are skipped; missing limits stay zero.
+func observe(history []state.VPARecommendation) observation {
+	var o observation
+
+	for _, rec := range history {
+		cpu, err := resource.ParseQuantity(rec.CPURequest)
+		if err != nil {
+			continue
+		}
+		memory, err := resource.ParseQuantity(rec.MemoryRequest)
+		if err != nil {
+			continue
+		}
+
+		o.samples++
+		o.cpuRequest = math.Max(o.cpuRequest, float64(cpu.MilliValue()))
+		o.memoryRequest = math.Max(o.memoryRequest, float64(memory.Value())/(1<<20))
+
+		if limit, err := resource.ParseQuantity(rec.CPULimit); err == nil {
+			o.cpuLimit = math.Max(o.cpuLimit, float64(limit.MilliValue()))
+		}
+		if limit, err := resource.ParseQuantity(rec.MemoryLimit); err == nil {
+			o.memoryLimit = math.Max(o.memoryLimit, float64(limit.Value())/(1<<20))
+		}
+	}
+
+	return o
+}
+
+// policyMax parses a policy maximum, reporting false when the policy sets none or it does not parse
+func policyMax(maximum string) (resource.Quantity, bool) {
+	if maximum == "" {
+		return resource.Quantity{}, false
+	}
+
+	q, err := resource.ParseQuantity(maximum)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+
+	return q, true
+}
+
+// formatCPU rounds millicores up to the next 10m, e.g. 250m or 1
+func formatCPU(millicores float64) string {
+	m := int64(math.Ceil(millicores/10) * 10)
+	return resource.NewMilliQuantity(m, resource.DecimalSI).String()
+}
+
+// formatMemory rounds MiB up to a whole MiB, e.g. 256Mi or 1Gi
+func formatMemory(mib float64) string {
+	return resource.NewQuantity(int64(math.Ceil(mib))<<20, resource.BinarySI).String()
+}
@@ -0,0 +1,186 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	binaryauthorization "google.golang.org/api/binaryauthorization/v1"
+	containeranalysis "google.golang.org/api/containeranalysis/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// cosignKMSPrefix is how cosign refers to Cloud KMS keys
+const cosignKMSPrefix = "gcpkms://"
+
+// CreateAttestorPolicy replaces the project's Binary Authorization policy with policyYAML, in
+// the format exported by gcloud container binauthz policy export. Clusters with enforcement
+// enabled only run images the policy admits, e.g. those carrying an attestor's attestation.
+func CreateAttestorPolicy(ctx context.Context, project, policyYAML string) error {
+	if project == "" {
+		return fmt.Errorf("GCP project is required for Binary Authorization")
+	}
+
+	policy, err := parsePolicy(policyYAML)
+	if err != nil {
+		return err
+	}
+
+	service, err := binaryauthorization.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Binary Authorization client: %w", err)
+	}
+
+	name := fmt.Sprintf("projects/%s/policy", project)
+	policy.Name = name
+	if _, err := service.Projects.UpdatePolicy(name, policy).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to update Binary Authorization policy: %w", err)
+	}
+
+	log.Info().
+		Str("project", project).
+		Msg("Binary Authorization policy updated")
+
+	return nil
+}
+
+// parsePolicy converts a YAML policy to its API representation, whose JSON field names the
+// YAML keys match
+func parsePolicy(policyYAML string) (*binaryauthorization.Policy, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal([]byte(policyYAML), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse Binary Authorization policy: %w", err)
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Binary Authorization policy: %w", err)
+	}
+
+	var policy binaryauthorization.Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse Binary Authorization policy: %w", err)
+	}
+
+	if policy.DefaultAdmissionRule == nil {
+		return nil, fmt.Errorf("policy has no defaultAdmissionRule")
+	}
+
+	return &policy, nil
+}
+
+// attestationPayload is the simple signing payload Binary Authorization verifies signatures over
+type attestationPayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// CreateAttestation records that attestor vouches for an image, so clusters enforcing a policy
+// that requires the attestor admit it. attestor is projects/<project>/attestors/<name>, and
+// imageDigest must be a digest reference. The attestation is signed with cosign using keyRef,
+// which must be a Cloud KMS key registered as one of the attestor's public keys.
+func CreateAttestation(ctx context.Context, attestor, imageDigest, keyRef string) error {
+	repo, digest, ok := strings.Cut(imageDigest, "@")
+	if !ok || !strings.HasPrefix(digest, "sha256:") {
+		return fmt.Errorf("image must be referenced by digest: %s", imageDigest)
+	}
+
+	if !strings.HasPrefix(keyRef, cosignKMSPrefix) {
+		return fmt.Errorf("attestations must be signed with a Cloud KMS key, got %s", keyRef)
+	}
+
+	project, _, ok := strings.Cut(strings.TrimPrefix(attestor, "projects/"), "/attestors/")
+	if !ok || project == "" {
+		return fmt.Errorf("attestor must be projects/<project>/attestors/<name>: %s", attestor)
+	}
+
+	authz, err := binaryauthorization.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Binary Authorization client: %w", err)
+	}
+
+	// Attestations are occurrences of the note the attestor reads
+	a, err := authz.Projects.Attestors.Get(attestor).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get attestor %s: %w", attestor, err)
+	}
+	if a.UserOwnedGrafeasNote == nil || a.UserOwnedGrafeasNote.NoteReference == "" {
+		return fmt.Errorf("attestor %s has no note", attestor)
+	}
+
+	var payload attestationPayload
+	payload.Critical.Identity.DockerReference = repo
+	payload.Critical.Image.DockerManifestDigest = digest
+	payload.Critical.Type = "Google cloud binauthz container signature"
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode attestation payload: %w", err)
+	}
+
+	signature, err := signBlob(ctx, payloadJSON, keyRef)
+	if err != nil {
+		return err
+	}
+
+	analysis, err := containeranalysis.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Container Analysis client: %w", err)
+	}
+
+	occurrence := &containeranalysis.Occurrence{
+		ResourceUri: "https://" + imageDigest,
+		NoteName:    a.UserOwnedGrafeasNote.NoteReference,
+		Attestation: &containeranalysis.AttestationOccurrence{
+			SerializedPayload: base64.StdEncoding.EncodeToString(payloadJSON),
+			Signatures: []*containeranalysis.Signature{{
+				Signature:   signature,
+				PublicKeyId: "//cloudkms.googleapis.com/v1/" + strings.TrimPrefix(keyRef, cosignKMSPrefix),
+			}},
+		},
+	}
+
+	if _, err := analysis.Projects.Occurrences.Create("projects/"+project, occurrence).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to create attestation: %w", err)
+	}
+
+	log.Info().
+		Str("image", imageDigest).
+		Str("attestor", attestor).
+		Msg("Binary Authorization attestation created")
+
+	return nil
+}
+
+// signBlob signs data with cosign, returning the base64-encoded signature
+func signBlob(ctx context.Context, data []byte, keyRef string) (string, error) {
+	cmd := exec.CommandContext(ctx, "cosign", "sign-blob", "--key", keyRef, "--yes", "-")
+	cmd.Stdin = bytes.NewReader(data)
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("cosign sign-blob failed: %w, output: %s", err, string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("cosign sign-blob failed: %w", err)
+	}
+
+	signature := strings.TrimSpace(string(output))
+	if _, err := base64.StdEncoding.DecodeString(signature); err != nil {
+		return "", fmt.Errorf("cosign sign-blob returned an invalid signature: %w", err)
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,333 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// Rate limit rules take priorities in this range, after the lower numbered rules security
+	// teams write by hand and before the policy's default rule
+	rateLimitPriorityBase  = 1000000
+	rateLimitPriorityRange = 1000000
+
+	// rateLimitIntervalSec is the window requests are counted over
+	rateLimitIntervalSec = 60
+)
+
+// RateLimitRule throttles each client IP reaching a Cloud Armor policy's backends
+type RateLimitRule struct {
+	Name                   string   // Stored as the rule's description; the rule's priority is derived from it
+	Region                 string   // Region of a regional policy, empty for a global one
+	RequestsPerMinutePerIP int      // Requests over the limit are denied with 429
+	EnforceOnURIPaths      []string // Path prefixes throttled; every path when empty
+}
+
+// AttachSecurityPolicy sets the Cloud Armor policy of a backend service and waits for the change
+// to apply. backendServiceName is the name of a global backend service, or a regional one
+// qualified as regions/<region>/backendServices/<name>; the policy must exist in the same scope.
+// Application Default Credentials are used.
+func AttachSecurityPolicy(ctx context.Context, project, backendServiceName, policyName string) error {
+	if project == "" {
+		return fmt.Errorf("GCP project is required for Cloud Armor")
+	}
+
+	service, err := compute.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Compute client: %w", err)
+	}
+
+	region, name := splitBackendService(backendServiceName)
+
+	var op *compute.Operation
+	if region == "" {
+		policy := fmt.Sprintf("projects/%s/global/securityPolicies/%s", project, policyName)
+		op, err = service.BackendServices.SetSecurityPolicy(project, name,
+			&compute.SecurityPolicyReference{SecurityPolicy: policy}).Context(ctx).Do()
+	} else {
+		policy := fmt.Sprintf("projects/%s/regions/%s/securityPolicies/%s", project, region, policyName)
+		op, err = service.RegionBackendServices.SetSecurityPolicy(project, region, name,
+			&compute.SecurityPolicyReference{SecurityPolicy: policy}).Context(ctx).Do()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set security policy of %s: %w", backendServiceName, err)
+	}
+
+	if err := waitForOperation(ctx, service, project, region, op); err != nil {
+		return fmt.Errorf("failed to set security policy of %s: %w", backendServiceName, err)
+	}
+
+	log.Info().
+		Str("project", project).
+		Str("backendService", backendServiceName).
+		Str("securityPolicy", policyName).
+		Msg("Cloud Armor security policy attached")
+
+	return nil
+}
+
+// splitBackendService returns the region and name of a backend service; the region is empty
+// for global backend services
+func splitBackendService(backendServiceName string) (region, name string) {
+	parts := strings.Split(backendServiceName, "/")
+	if len(parts) == 4 && parts[0] == "regions" && parts[2] == "backendServices" {
+		return parts[1], parts[3]
+	}
+	return "", backendServiceName
+}
+
+// BackendServiceRegion returns the region of a backend service name as AttachSecurityPolicy
+// takes it, empty for a global backend service
+func BackendServiceRegion(backendServiceName string) string {
+	region, _ := splitBackendService(backendServiceName)
+	return region
+}
+
+// waitForOperation waits for a global or regional Compute operation and returns its error.
+// Wait returns once the operation is done or after about two minutes, so it is polled until done.
+func waitForOperation(ctx context.Context, service *compute.Service, project, region string, op *compute.Operation) error {
+	var err error
+	for op.Status != "DONE" {
+		if region == "" {
+			op, err = service.GlobalOperations.Wait(project, op.Name).Context(ctx).Do()
+		} else {
+			op, err = service.RegionOperations.Wait(project, region, op.Name).Context(ctx).Do()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to wait for operation: %w", err)
+		}
+	}
+
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return errors.New(op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
+
+// RateLimitRuleName names the rate limit rule of a deployment. Deployments may share a policy,
+// so each has its own rule.
+func RateLimitRuleName(deploymentID string) string {
+	return "deployer-ratelimit-" + deploymentID
+}
+
+// RateLimitRulePriority returns the policy priority of the rate limit rule named name. Rules
+// are identified by priority, so it is derived from the name to find the rule again later.
+func RateLimitRulePriority(name string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return rateLimitPriorityBase + int64(h.Sum32()%rateLimitPriorityRange)
+}
+
+// AddRateLimitRule adds a throttling rule to a Cloud Armor policy and waits for it to apply.
+// A rule of the same name already in the policy is updated instead, so retried deploys do
+// not fail.
+func AddRateLimitRule(ctx context.Context, project, policyName string, config RateLimitRule) error {
+	if project == "" {
+		return fmt.Errorf("GCP project is required for Cloud Armor")
+	}
+
+	service, err := compute.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Compute client: %w", err)
+	}
+
+	rule := rateLimitRule(config)
+
+	var policy *compute.SecurityPolicy
+	if config.Region == "" {
+		policy, err = service.SecurityPolicies.Get(project, policyName).Context(ctx).Do()
+	} else {
+		policy, err = service.RegionSecurityPolicies.Get(project, config.Region, policyName).Context(ctx).Do()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get security policy %s: %w", policyName, err)
+	}
+
+	for _, existing := range policy.Rules {
+		if existing.Priority != rule.Priority {
+			continue
+		}
+		if existing.Description != config.Name {
+			return fmt.Errorf("priority %d of security policy %s is taken by another rule", rule.Priority, policyName)
+		}
+		return patchRateLimitRule(ctx, service, project, policyName, config.Region, rule)
+	}
+
+	var op *compute.Operation
+	if config.Region == "" {
+		op, err = service.SecurityPolicies.AddRule(project, policyName, rule).Context(ctx).Do()
+	} else {
+		op, err = service.RegionSecurityPolicies.AddRule(project, config.Region, policyName, rule).Context(ctx).Do()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add rate limit rule to %s: %w", policyName, err)
+	}
+
+	if err := waitForOperation(ctx, service, project, config.Region, op); err != nil {
+		return fmt.Errorf("failed to add rate limit rule to %s: %w", policyName, err)
+	}
+
+	log.Info().
+		Str("project", project).
+		Str("securityPolicy", policyName).
+		Str("rule", config.Name).
+		Int64("priority", rule.Priority).
+		Msg("Cloud Armor rate limit rule added")
+
+	return nil
+}
+
+// UpdateRateLimitRule patches an existing throttling rule of a Cloud Armor policy
+func UpdateRateLimitRule(ctx context.Context, project, policyName string, config RateLimitRule) error {
+	if project == "" {
+		return fmt.Errorf("GCP project is required for Cloud Armor")
+	}
+
+	service, err := compute.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Compute client: %w", err)
+	}
+
+	return patchRateLimitRule(ctx, service, project, policyName, config.Region, rateLimitRule(config))
+}
+
+// DeleteRateLimitRule removes the throttling rule named name from a Cloud Armor policy. A rule
+// that is already gone is not an error.
+func DeleteRateLimitRule(ctx context.Context, project, policyName, region, name string) error {
+	if project == "" {
+		return fmt.Errorf("GCP project is required for Cloud Armor")
+	}
+
+	service, err := compute.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Compute client: %w", err)
+	}
+
+	priority := RateLimitRulePriority(name)
+
+	var op *compute.Operation
+	if region == "" {
+		op, err = service.SecurityPolicies.RemoveRule(project, policyName).Priority(priority).Context(ctx).Do()
+	} else {
+		op, err = service.RegionSecurityPolicies.RemoveRule(project, region, policyName).Priority(priority).Context(ctx).Do()
+	}
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove rate limit rule from %s: %w", policyName, err)
+	}
+
+	if err := waitForOperation(ctx, service, project, region, op); err != nil {
+		return fmt.Errorf("failed to remove rate limit rule from %s: %w", policyName, err)
+	}
+
+	log.Info().
+		Str("project", project).
+		Str("securityPolicy", policyName).
+		Str("rule", name).
+		Msg("Cloud Armor rate limit rule removed")
+
+	return nil
+}
+
+// ApplyRateLimitRule brings a policy's throttling rule in line with config: the rule named
+// current is updated, or a rule named config.Name added when there is none, and a nil config
+// removes it. It returns the name of the rule left in the policy, empty once removed.
+func ApplyRateLimitRule(ctx context.Context, project, policyName, region, current string, config *RateLimitRule) (string, error) {
+	switch {
+	case config == nil && current == "":
+		return "", nil
+	case config == nil:
+		if err := DeleteRateLimitRule(ctx, project, policyName, region, current); err != nil {
+			return current, err
+		}
+		return "", nil
+	case current != "":
+		config.Name = current
+		config.Region = region
+		return current, UpdateRateLimitRule(ctx, project, policyName, *config)
+	default:
+		config.Region = region
+		if err := AddRateLimitRule(ctx, project, policyName, *config); err != nil {
+			return "", err
+		}
+		return config.Name, nil
+	}
+}
+
+// patchRateLimitRule replaces the rule at rule's priority and waits for it to apply
+func patchRateLimitRule(ctx context.Context, service *compute.Service, project, policyName, region string, rule *compute.SecurityPolicyRule) error {
+	var (
+		op  *compute.Operation
+		err error
+	)
+	if region == "" {
+		op, err = service.SecurityPolicies.PatchRule(project, policyName, rule).Priority(rule.Priority).Context(ctx).Do()
+	} else {
+		op, err = service.RegionSecurityPolicies.PatchRule(project, region, policyName, rule).Priority(rule.Priority).Context(ctx).Do()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update rate limit rule of %s: %w", policyName, err)
+	}
+
+	if err := waitForOperation(ctx, service, project, region, op); err != nil {
+		return fmt.Errorf("failed to update rate limit rule of %s: %w", policyName, err)
+	}
+
+	log.Info().
+		Str("project", project).
+		Str("securityPolicy", policyName).
+		Str("rule", rule.Description).
+		Msg("Cloud Armor rate limit rule updated")
+
+	return nil
+}
+
+// rateLimitRule builds the throttle rule for config. Without paths every request matches.
+func rateLimitRule(config RateLimitRule) *compute.SecurityPolicyRule {
+	match := &compute.SecurityPolicyRuleMatcher{
+		VersionedExpr: "SRC_IPS_V1",
+		Config:        &compute.SecurityPolicyRuleMatcherConfig{SrcIpRanges: []string{"*"}},
+	}
+	if len(config.EnforceOnURIPaths) > 0 {
+		conditions := make([]string, len(config.EnforceOnURIPaths))
+		for i, path := range config.EnforceOnURIPaths {
+			conditions[i] = fmt.Sprintf("request.path.startsWith('%s')", path)
+		}
+		match = &compute.SecurityPolicyRuleMatcher{
+			Expr: &compute.Expr{Expression: strings.Join(conditions, " || ")},
+		}
+	}
+
+	return &compute.SecurityPolicyRule{
+		Description: config.Name,
+		Priority:    RateLimitRulePriority(config.Name),
+		Action:      "throttle",
+		Match:       match,
+		RateLimitOptions: &compute.SecurityPolicyRuleRateLimitOptions{
+			RateLimitThreshold: &compute.SecurityPolicyRuleRateLimitOptionsThreshold{
+				Count:       int64(config.RequestsPerMinutePerIP),
+				IntervalSec: rateLimitIntervalSec,
+			},
+			ConformAction: "allow",
+			ExceedAction:  "deny(429)",
+			EnforceOnKey:  "IP",
+		},
+	}
+}
+
+// isNotFound reports whether a Compute API call failed because its resource does not exist
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,113 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	accesscontextmanager "google.golang.org/api/accesscontextmanager/v1"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v3"
+)
+
+// perimeterUpdateTimeout bounds the wait for a service perimeter change to be applied
+const perimeterUpdateTimeout = 5 * time.Minute
+
+// AddToPerimeter puts a cluster's service account inside a VPC Service Controls perimeter, so
+// the Google APIs the perimeter restricts cannot move data to projects outside it. Perimeters
+// enforce by project, so the service account's project is added as a perimeter resource.
+// perimeter is accessPolicies/<policy>/servicePerimeters/<name>.
+func AddToPerimeter(ctx context.Context, project, perimeter, serviceAccount string) error {
+	if project == "" {
+		return fmt.Errorf("GCP project is required for VPC Service Controls")
+	}
+
+	if !strings.HasPrefix(perimeter, "accessPolicies/") || !strings.Contains(perimeter, "/servicePerimeters/") {
+		return fmt.Errorf("perimeter must be accessPolicies/<policy>/servicePerimeters/<name>: %s", perimeter)
+	}
+
+	// A service account acts inside the perimeter only when its own project is there
+	if !strings.HasSuffix(serviceAccount, "@"+project+".iam.gserviceaccount.com") {
+		return fmt.Errorf("service account %s does not belong to project %s", serviceAccount, project)
+	}
+
+	projects, err := cloudresourcemanager.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Resource Manager client: %w", err)
+	}
+
+	// Perimeter resources are project numbers, e.g. projects/123456789
+	p, err := projects.Projects.Get("projects/" + project).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get project %s: %w", project, err)
+	}
+	resource := p.Name
+
+	service, err := accesscontextmanager.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Access Context Manager client: %w", err)
+	}
+
+	current, err := service.AccessPolicies.ServicePerimeters.Get(perimeter).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get service perimeter %s: %w", perimeter, err)
+	}
+
+	status := current.Status
+	if status == nil {
+		status = &accesscontextmanager.ServicePerimeterConfig{}
+	}
+
+	for _, r := range status.Resources {
+		if r == resource {
+			log.Info().
+				Str("perimeter", perimeter).
+				Str("serviceAccount", serviceAccount).
+				Msg("Project already inside the service perimeter")
+			return nil
+		}
+	}
+
+	update := &accesscontextmanager.ServicePerimeter{
+		Status: &accesscontextmanager.ServicePerimeterConfig{
+			Resources: append(status.Resources, resource),
+		},
+	}
+
+	op, err := service.AccessPolicies.ServicePerimeters.Patch(perimeter, update).
+		UpdateMask("status.resources").Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to update service perimeter %s: %w", perimeter, err)
+	}
+
+	deadline := time.Now().Add(perimeterUpdateTimeout)
+	for !op.Done {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout waiting for service perimeter %s to update after %v", perimeter, perimeterUpdateTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+
+		op, err = service.Operations.Get(op.Name).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get service perimeter update: %w", err)
+		}
+	}
+
+	if op.Error != nil {
+		return fmt.Errorf("failed to update service perimeter %s: %s", perimeter, op.Error.Message)
+	}
+
+	log.Info().
+		Str("perimeter", perimeter).
+		Str("resource", resource).
+		Str("serviceAccount", serviceAccount).
+		Msg("Project added to service perimeter")
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// deploymentCacheTTL bounds how stale a cached deployment can get. Writes to the deployment
+// drop it sooner, but writes to its infrastructure or builds only show once it expires.
+const deploymentCacheTTL = 10 * time.Second
+
+// CacheStats counts GetDeployment lookups served from and missing the cache
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// CacheStats returns the deployment cache's hit and miss counts since the repository was created
+func (r *Repository) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   r.cacheHits.Load(),
+		Misses: r.cacheMisses.Load(),
+	}
+}
+
+// deploymentCacheKey returns the cache key of a deployment
+func deploymentCacheKey(id uuid.UUID) string {
+	return "deployer:deployment:" + id.String()
+}
+
+// cachedDeployment returns the cached copy of a deployment, or nil when there is none.
+// Cache errors are treated as misses so lookups fall back to the database.
+func (r *Repository) cachedDeployment(ctx context.Context, id uuid.UUID) *Deployment {
+	if r.cache == nil {
+		return nil
+	}
+
+	data, err := r.cache.Get(ctx, deploymentCacheKey(id)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Warn().Err(err).Str("deployment_id", id.String()).Msg("Failed to read cached deployment")
+		}
+		r.cacheMisses.Add(1)
+		return nil
+	}
+
+	var deployment Deployment
+	if err := json.Unmarshal(data, &deployment); err != nil {
+		log.Warn().Err(err).Str("deployment_id", id.String()).Msg("Failed to decode cached deployment")
+		r.cacheMisses.Add(1)
+		return nil
+	}
+
+	r.cacheHits.Add(1)
+	return &deployment
+}
+
+// cacheDeployment stores a copy of a deployment in the cache
+func (r *Repository) cacheDeployment(ctx context.Context, deployment *Deployment) {
+	if r.cache == nil {
+		return
+	}
+
+	data, err := json.Marshal(deployment)
+	if err != nil {
+		log.Warn().Err(err).Str("deployment_id", deployment.ID.String()).Msg("Failed to encode deployment for cache")
+		return
+	}
+
+	if err := r.cache.Set(ctx, deploymentCacheKey(deployment.ID), data, deploymentCacheTTL).Err(); err != nil {
+		log.Warn().Err(err).Str("deployment_id", deployment.ID.String()).Msg("Failed to cache deployment")
+	}
+}
+
+// invalidateDeployment drops the cached copy of a deployment after it is written
+func (r *Repository) invalidateDeployment(ctx context.Context, id uuid.UUID) {
+	if r.cache == nil {
+		return
+	}
+
+	if err := r.cache.Del(ctx, deploymentCacheKey(id)).Err(); err != nil {
+		log.Warn().Err(err).Str("deployment_id", id.String()).Msg("Failed to invalidate cached deployment")
+	}
+}
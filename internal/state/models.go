@@ -1,6 +1,7 @@
 package state
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,7 +14,7 @@ type Deployment struct {
 	Name             string     `gorm:"not null;index"`
 	AppName          string     `gorm:"not null"`
 	Version          string     `gorm:"not null"`
-	Status           string     `gorm:"not null;index"` // PENDING, BUILDING, PROVISIONING, DEPLOYING, EXPOSED, FAILED
+	Status           string     `gorm:"not null;index"` // PENDING, BUILDING, PROVISIONING, DEPLOYING, EXPOSED, HEALTHY, UNHEALTHY, DRIFTED, RESTARTING, FAILED
 	Cloud            string     `gorm:"not null"`       // gcp, aws, azure
 	Region           string     `gorm:"not null"`
 	Port             int        `gorm:"default:8080"`   // Application port
@@ -21,6 +22,107 @@ type Deployment struct {
 	ExternalIP       string
 	ExternalURL      string
 	Error            string     `gorm:"type:text"` // Last error message
+	ImageTag         string     // Image currently being rolled out, used to resume stuck pipelines
+	LastDeployFingerprint string // Hash of the image and settings of the last rollout started
+	DeployerType     string     `gorm:"default:helm"` // helm, kustomize
+	RepoURL          string     // Source repository, required for kustomize deployments
+	KustomizePath    string     // Directory containing kustomization.yaml, searched for when empty
+	CPULimit         string     // Container CPU limit, chart default when empty
+	MemoryLimit      string     // Container memory limit, chart default when empty
+	CPURequest       string     // Container CPU request, chart default when empty
+	MemoryRequest    string     // Container memory request, chart default when empty
+	DeploymentType   string     `gorm:"default:service"` // service, cronjob, job
+
+	// Organizational key-value metadata, applied as labels to the app's cloud and Kubernetes resources
+	Tags map[string]string `gorm:"type:jsonb;serializer:json;index:,type:gin"`
+
+	// Monthly infrastructure budget, none when zero. BudgetAlertPercent is the highest budget
+	// threshold alerted on this month, 0 until projected spend first passes one.
+	MonthlyBudgetUSD   float64
+	BudgetAlertPercent int
+	BudgetAlertSentAt  *time.Time
+
+	// Daily external egress in GB above which an egress alert is sent; zero uses the platform default
+	EgressAlertGB float64
+
+	// Resources last recommended by the cluster's Vertical Pod Autoscaler, nil until recorded
+	VPALastRecommendation *VPARecommendation `gorm:"type:jsonb;serializer:json"`
+
+	// Cronjob scheduling, used when DeploymentType is cronjob
+	Schedule                string // Cron expression
+	ConcurrencyPolicy       string // Allow, Forbid, Replace
+	StartingDeadlineSeconds int
+
+	// Persistent storage, used when DeploymentType is statefulset
+	StorageClass     string
+	StorageSize      string // e.g. 10Gi, no volumes when empty
+	StorageMountPath string
+
+	// JSON-encoded pre- and post-deploy hooks, empty when none are configured
+	Hooks string `gorm:"type:text"`
+
+	// JSON-encoded node labels and taint tolerations placing the app's pods, empty when unconstrained
+	NodeAffinity string `gorm:"type:text"`
+
+	// JSON-encoded sidecar containers run in the app's pods, empty when none are configured
+	Sidecars string `gorm:"type:text"`
+
+	// JSON-encoded init containers run before the app starts in its pods, empty when none are configured
+	InitContainers string `gorm:"type:text"`
+
+	// JSON-encoded payload of the last provision job started, used to resume stuck pipelines
+	ProvisionPayload string `gorm:"type:text"`
+
+	// JSON-encoded smoke tests run once the app is exposed, and the outcome of the last run
+	SmokeTests      string          `gorm:"type:text"`
+	SmokeTestResult json.RawMessage `gorm:"type:jsonb;serializer:json"`
+
+	// Workload identity; a GCP service account is created for the app when the email is empty
+	WorkloadIdentity       bool
+	GCPServiceAccountEmail string
+
+	// Cloud Armor policy attached to the app's load balancer; "default" is the platform's policy
+	CloudArmorEnabled bool
+	CloudArmorPolicy  string
+
+	// Cloud Armor throttling of each client IP, on the given path prefixes or every path when
+	// none are set; zero requests leaves clients unthrottled
+	RateLimitRequestsPerMinute int
+	RateLimitPaths             []string `gorm:"type:jsonb;serializer:json"`
+
+	// PodDisruptionBudget for the app's pods, setting at most one bound; one pod is kept
+	// available when both are zero
+	PDBEnabled        bool `gorm:"default:false"`
+	PDBMinAvailable   int
+	PDBMaxUnavailable int
+
+	// Deployment whose in-mesh traffic is mirrored to this one, nil when it is not a shadow
+	MirrorFrom *uuid.UUID `gorm:"type:uuid;index"`
+
+	// Paused deployments keep their queued jobs on hold until resumed
+	Paused   bool `gorm:"default:false"`
+	PausedAt *time.Time
+
+	// GitOps mode: reconcile jobs redeploy when the live Helm release drifts from what was deployed
+	ReconciliationMode bool `gorm:"default:false"`
+
+	// Scheduled rollouts stay PENDING until ScheduledAt, when the JSON-encoded provision
+	// payload in ScheduledJob is enqueued
+	ScheduledAt  *time.Time `gorm:"index"`
+	ScheduledJob string     `gorm:"type:text"`
+
+	// Production gate: rollouts wait for one of Approvers, or an admin, to approve them.
+	// Approvers are client identities as recorded in audit logs, e.g. key:abcd1234.
+	RequiresApproval bool     `gorm:"default:false"`
+	Approvers        []string `gorm:"type:jsonb;serializer:json"`
+
+	// Deployment this one was cloned from, nil for deployments created directly
+	ClonedFromID *uuid.UUID `gorm:"type:uuid;index"`
+
+	// User ID of the deployment's owner, empty until an admin transfers it to one
+	UserID string `gorm:"index"`
+
+	LastProgressAt   time.Time  `gorm:"index"` // Last status change or progress log entry
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	DeployedAt       *time.Time
@@ -63,12 +165,72 @@ type Infrastructure struct {
 	NodePoolName        string
 	NodeCount           int    `gorm:"default:2"`
 	ServiceAccountEmail string
+	AppServiceAccountEmail string // GCP service account bound to the app's Kubernetes ServiceAccount
+	VPCServicePerimeter string // VPC Service Controls perimeter the node service account was added to
+	DedicatedWorkload   string // Workload the tainted dedicated node pool is reserved for, empty without one
+
+	// Clusters imported by endpoint and CA cert instead of provisioned. They have no Pulumi
+	// stack and are left running when the deployment is destroyed.
+	ImportedExternally bool   `gorm:"default:false"`
+	GCPProject         string // Project an imported cluster runs in
 
 	// Kubernetes deployment details (from deployer phase)
 	KubeNamespace   string // K8s namespace
 	HelmReleaseName string // Helm release name
 	ExternalIP      string // LoadBalancer external IP
 
+	// Cloud Armor policy attached to the LoadBalancer's backend service, and the last
+	// attachment error; both empty when the app has no WAF
+	WAFPolicyName     string
+	WAFBackendService string // e.g. regions/us-central1/backendServices/k8s2-...
+	WAFError          string `gorm:"type:text"`
+	RateLimitRuleName string // Rate limit rule added to the policy, empty when there is none
+
+	// Releases installed outside the deployer and adopted into it; their namespace predates
+	// the deployment and is kept when it is destroyed
+	AdoptedExternally bool `gorm:"default:false"`
+
+	// Kustomize deployments keep the applied and previous manifests for rollback
+	KustomizeManifest     string `gorm:"type:text"`
+	LastKustomizeManifest string `gorm:"type:text"`
+
+	// Persistent volume claims created by statefulset deployments
+	PVCNames []string `gorm:"type:jsonb;serializer:json"`
+
+	// Horizontal Pod Autoscaler settings, nil when the app is not autoscaled
+	HPAConfig *HPAConfig `gorm:"type:jsonb;serializer:json"`
+
+	// Chart version of each platform addon installed on the cluster, e.g. {"istio": "1.24.2"}
+	AddonVersions map[string]string `gorm:"type:jsonb;serializer:json"`
+
+	// Cloud SQL addon (empty when not provisioned)
+	DatabaseConnectionName string
+	DatabaseHost           string
+	DatabasePort           int
+	DatabaseName           string
+	DatabaseUser           string // IAM database user bound to the node service account
+
+	// Memorystore addon (empty when not provisioned)
+	RedisHost string
+	RedisPort int
+
+	// Drift detection (from reconcile jobs)
+	DriftDetected    bool
+	DriftDetails     *string `gorm:"type:jsonb"` // Expected vs actual values per drifted field; NULL without drift
+	LastReconciledAt *time.Time
+
+	// GitOps mode: values of the last Helm deploy, and the outcome of the last release check
+	HelmValues          string `gorm:"type:text"`
+	LastReconcileStatus string // in_sync, redeploying, skipped, error
+
+	// Monthly cost projected from the billing export by the daily cost job
+	EstimatedMonthlyCostUSD float64
+	CostUpdatedAt           *time.Time
+
+	// External egress of the cluster's nodes over the 24 hours before the nightly egress check
+	LastEgressGB      float64
+	LastEgressCheckAt *time.Time
+
 	// Error tracking
 	LastError    string `gorm:"type:text"` // Last error message
 	ProvisionLog string `gorm:"type:text"` // Provision operation logs
@@ -85,9 +247,326 @@ type Build struct {
 	ImageTag     string    `gorm:"not null"`
 	Status       string    `gorm:"not null"` // PENDING, BUILDING, SUCCESS, FAILED
 	BuildLog     string    `gorm:"type:text"`
+	CacheKey     string    // Build cache entry used or produced, empty when caching is disabled
+	SBOMPath     string    // gs:// path of the image SBOM, empty when none was generated
+	SBOMFormat   string    // spdx-json or cyclonedx-json
+	SignatureRef string    // Cosign signature reference, empty when the image is unsigned
+	ScanStatus   string    // PENDING, PASSED, FAILED, SKIPPED
 	StartedAt    time.Time
 	CompletedAt  *time.Time
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	DeletedAt    gorm.DeletedAt `gorm:"index"`
 }
+
+// DeploymentLog is a user-visible message recorded during a deployment phase
+type DeploymentLog struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DeploymentID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Phase        string    `gorm:"not null;index"` // Deployment status the entry was recorded in, e.g. DEPLOYING
+	Level        string    `gorm:"not null"`       // INFO, WARNING, ERROR
+	Source       string    // Component that produced the entry, e.g. helm-lint
+	Message      string    `gorm:"type:text"`
+	CreatedAt    time.Time
+
+	// Highlight is the message excerpt matching a search, filled only by searches
+	Highlight string `gorm:"->;-:migration"`
+}
+
+// FederatedDeployment groups deployments of the same app across clusters in different regions
+type FederatedDeployment struct {
+	ID            uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	Name          string      `gorm:"not null;index"`
+	AppName       string      `gorm:"not null"`
+	Version       string      `gorm:"not null"`
+	DeploymentIDs []uuid.UUID `gorm:"type:jsonb;serializer:json"` // Member deployments, one per target
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+// DeploymentDependency records that a deployment may only be provisioned once another is live
+type DeploymentDependency struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DeploymentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_deployment_dependency"`
+	DependsOnID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_deployment_dependency;index"`
+	CreatedAt    time.Time
+}
+
+// DeploymentEnvVar is an environment variable applied to every deploy of a deployment
+type DeploymentEnvVar struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DeploymentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_deployment_env_var"`
+	Key          string    `gorm:"not null;uniqueIndex:idx_deployment_env_var"`
+	Value        string    `gorm:"type:text"` // AES-256-GCM ciphertext when IsSecret is set
+	IsSecret     bool      `gorm:"default:false"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// DeploymentConfigMap is a set of files mounted into a deployment's pods from a ConfigMap
+type DeploymentConfigMap struct {
+	ID           uuid.UUID         `gorm:"type:uuid;primaryKey"`
+	DeploymentID uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_deployment_config_map"`
+	Name         string            `gorm:"not null;uniqueIndex:idx_deployment_config_map"`
+	MountPath    string            // Default: /etc/config/<name>
+	Data         map[string]string `gorm:"type:jsonb;serializer:json"` // File name to content
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ServiceRoute sends a share of the in-mesh traffic to a deployment's service to another
+// deployment on the same cluster
+type ServiceRoute struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SourceDeploymentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_service_route"`
+	TargetDeploymentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_service_route;index"`
+	Prefix             string    `gorm:"not null;uniqueIndex:idx_service_route"` // URI prefix routed, rewritten to / for the target
+	WeightPercent      int       `gorm:"not null"`                               // Share of the prefix's traffic sent to the target
+	CreatedAt          time.Time
+}
+
+// A/B test statuses
+const (
+	ABTestStatusRunning   = "RUNNING"   // Matching requests are routed to the variant
+	ABTestStatusPromoting = "PROMOTING" // The variant won; all traffic goes to it until the baseline is destroyed
+	ABTestStatusConcluded = "CONCLUDED"
+	ABTestStatusCancelled = "CANCELLED" // A deployment was destroyed before the test concluded
+)
+
+// ABTest routes the requests to a baseline deployment carrying a header to a variant deployment
+// on the same cluster. The request counts of each are stored once it concludes.
+type ABTest struct {
+	ID                   uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	BaselineDeploymentID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	VariantDeploymentID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	HeaderName           string     `gorm:"not null"` // Lowercase, as Istio matches it
+	HeaderValue          string     `gorm:"not null"`
+	RoutingWeight        int        `gorm:"not null"` // Share of matching requests sent to the variant
+	Status               string     `gorm:"not null;index"`
+	WinnerDeploymentID   *uuid.UUID `gorm:"type:uuid"`
+	BaselineRequests     int64
+	BaselineErrors       int64
+	VariantRequests      int64
+	VariantErrors        int64
+	CreatedAt            time.Time
+	ConcludedAt          *time.Time
+}
+
+// HPAConfig holds the Horizontal Pod Autoscaler settings of a deployment.
+// A zero target percentage leaves that metric out.
+type HPAConfig struct {
+	MinReplicas         int `json:"min_replicas"`
+	MaxReplicas         int `json:"max_replicas"`
+	TargetCPUPercent    int `json:"target_cpu_percent,omitempty"`
+	TargetMemoryPercent int `json:"target_memory_percent,omitempty"`
+}
+
+// VPARecommendation holds the container resources a Vertical Pod Autoscaler recommended for a
+// deployment, and when they were read
+type VPARecommendation struct {
+	Container     string    `json:"container"`
+	CPURequest    string    `json:"cpu_request"`
+	MemoryRequest string    `json:"memory_request"`
+	CPULimit      string    `json:"cpu_limit,omitempty"`
+	MemoryLimit   string    `json:"memory_limit,omitempty"`
+	RecordedAt    time.Time `json:"recorded_at"`
+}
+
+// AuditLog records a privileged operation and who performed it
+type AuditLog struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Action       string    `gorm:"not null;index"` // e.g. pod.exec
+	DeploymentID uuid.UUID `gorm:"type:uuid;index"`
+	Actor        string    // Client the request came from, e.g. key:abcd1234 or ip:10.0.0.1
+	Details      string    `gorm:"type:jsonb"`
+	CreatedAt    time.Time
+}
+
+// Deployment event types
+const (
+	EventTypeStatusChange = "status_change"
+	EventTypeLog          = "log"
+	EventTypeSmokeTest    = "smoke_test"
+	EventTypeHealthCheck  = "health_check"
+	EventTypeHelmRevision = "helm_revision"
+	EventTypeHPAScaling   = "hpa_scaling"
+)
+
+// DeploymentEvent is an entry of a deployment's event feed
+type DeploymentEvent struct {
+	ID           uuid.UUID              `gorm:"type:uuid;primaryKey"`
+	DeploymentID uuid.UUID              `gorm:"type:uuid;not null;index"`
+	Type         string                 `gorm:"not null;index"`
+	Message      string                 `gorm:"type:text"`
+	Metadata     map[string]interface{} `gorm:"type:jsonb;serializer:json"`
+	CreatedAt    time.Time              `gorm:"index"`
+}
+
+// ResourcePolicy is the admin-set limit on the resources of a single deployment. There is at
+// most one row; when present it replaces the deployer's configured policy.
+type ResourcePolicy struct {
+	ID             uint   `gorm:"primaryKey"`
+	MaxCPULimit    string // Kubernetes quantity, empty for no limit
+	MaxMemoryLimit string // Kubernetes quantity, empty for no limit
+	MaxReplicas    int    // Zero for no limit
+	UpdatedBy      string // Client that last set the policy
+	UpdatedAt      time.Time
+}
+
+// Deployment approval statuses
+const (
+	ApprovalStatusPending  = "PENDING"
+	ApprovalStatusApproved = "APPROVED"
+	ApprovalStatusRejected = "REJECTED"
+	ApprovalStatusExpired  = "EXPIRED"
+)
+
+// DeploymentApproval is a rollout of a deployment that requires approval, held until an
+// approver decides on it or it expires
+type DeploymentApproval struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DeploymentID uuid.UUID `gorm:"type:uuid;not null;index"`
+	RequestedBy  string    // Client that started the rollout
+	Approvers    []string  `gorm:"type:jsonb;serializer:json"` // Copied from the deployment when requested
+	Status       string    `gorm:"not null;index"`             // PENDING, APPROVED, REJECTED, EXPIRED
+	Rollout      string    `gorm:"type:text"`                  // JSON-encoded rollout started once approved
+	DecidedBy    string
+	DecidedAt    *time.Time
+	ExpiresAt    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// DeploymentOwnershipHistory records a deployment passing from one owner to another
+type DeploymentOwnershipHistory struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DeploymentID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	PreviousOwner string    // Empty when the deployment had no owner
+	NewOwner      string    `gorm:"not null;index"`
+	TransferredBy string    // Client that requested the transfer
+	CreatedAt     time.Time
+}
+
+// Git providers a GitHook receives push events from
+const (
+	GitProviderGitHub    = "github"
+	GitProviderGitLab    = "gitlab"
+	GitProviderBitbucket = "bitbucket"
+)
+
+// GitHook builds and deploys a deployment when a branch of its repository is pushed to
+type GitHook struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DeploymentID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Provider        string    `gorm:"not null;index:idx_git_hook_repo"` // github, gitlab, bitbucket
+	RepoURL         string    `gorm:"not null;index:idx_git_hook_repo"` // Clone URL push events are matched against
+	Branch          string    `gorm:"not null;default:main"`
+	SubPath         string    // Service directory built in a monorepo, empty for the root
+	Secret          string    `gorm:"type:text"` // AES-256-GCM ciphertext of the provider's hook secret
+	LastCommitSHA   string    // Commit of the last push that started a build
+	LastTriggeredAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Build scan statuses
+const (
+	ScanStatusPending = "PENDING"
+	ScanStatusPassed  = "PASSED"
+	ScanStatusFailed  = "FAILED"
+	ScanStatusSkipped = "SKIPPED"
+)
+
+// VulnerabilityScan is the result of scanning a build's image for known vulnerabilities
+type VulnerabilityScan struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey"`
+	BuildID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	DeploymentID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	ImageTag      string
+	ScanTime      time.Time `gorm:"index"`
+	Passed        bool      // No vulnerability at or above the scanner's fail-on severity
+	CriticalCount int
+	HighCount     int
+	MediumCount   int
+	LowCount      int
+	RawResult     json.RawMessage `gorm:"type:jsonb;serializer:json"` // The scanner's report
+	// Vulnerabilities found but not counted because their risk was accepted
+	SuppressedCount int
+	SuppressedVulns []string `gorm:"type:jsonb;serializer:json"`
+	CreatedAt       time.Time
+}
+
+// CVESuppression accepts the risk of a vulnerability, e.g. one without a fix, so scans
+// neither count it nor fail on it
+type CVESuppression struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	CVEID        string     `gorm:"column:cve_id;not null;index"` // As reported by the scanner, e.g. CVE-2024-12345
+	UserID       string     // Admin client that accepted the risk
+	Reason       string     `gorm:"type:text;not null"`
+	DeploymentID *uuid.UUID `gorm:"type:uuid;index"` // Deployment the suppression applies to, nil for all
+	ExpiresAt    *time.Time `gorm:"index"`           // The suppression stops applying after this, nil for never
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// HelmChart is a version of the deployer's chart published to the OCI chart registry
+type HelmChart struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name        string    `gorm:"not null;uniqueIndex:idx_helm_charts_registry_name_version"`
+	Version     string    `gorm:"not null;uniqueIndex:idx_helm_charts_registry_name_version"`
+	RegistryURL string    `gorm:"not null;uniqueIndex:idx_helm_charts_registry_name_version"` // e.g. oci://us-central1-docker.pkg.dev/project/helm-charts
+	Digest      string    // Manifest digest reported by helm push
+	PublishedBy string    // Admin client that published it
+	CreatedAt   time.Time
+}
+
+// RegistryCredential is a login to a private container registry images are pushed to
+type RegistryCredential struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	RegistryURL string     `gorm:"not null;index"`     // Registry host, e.g. registry.example.com
+	Username    string     `gorm:"not null"`           // _json_key for a GCP service account key
+	Secret      string     `gorm:"type:text;not null"` // Password, token or service account key, encrypted
+	ExpiresAt   *time.Time `gorm:"index"`              // When the registry stops accepting it, nil for never
+	UserID      string     // Client that stored it
+	RotatedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Pipeline stage types, in the order a deployment moves through them
+const (
+	StageTypeBuild     = "BUILD"
+	StageTypeProvision = "PROVISION"
+	StageTypeDeploy    = "DEPLOY"
+)
+
+// Pipeline stage statuses
+const (
+	StageStatusPending   = "PENDING"
+	StageStatusQueued    = "QUEUED"
+	StageStatusRunning   = "RUNNING"
+	StageStatusCompleted = "COMPLETED"
+	StageStatusFailed    = "FAILED"
+)
+
+// Pipeline is the latest run of a deployment through its stages. It is replaced when a new
+// run starts.
+type Pipeline struct {
+	ID                uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	DeploymentID      uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex"`
+	Stages            []PipelineStage `gorm:"type:jsonb;serializer:json"`
+	CurrentStageIndex int             // Stage running or last reached
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// PipelineStage is one step of a pipeline, run by a single queued job
+type PipelineStage struct {
+	Type        string     `json:"type"`   // BUILD, PROVISION, DEPLOY
+	Status      string     `json:"status"` // PENDING, QUEUED, RUNNING, COMPLETED, FAILED
+	JobID       string     `json:"job_id,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
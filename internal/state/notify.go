@@ -0,0 +1,41 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Postgres channels status changes are announced on with NOTIFY
+const (
+	ChannelDeploymentUpdates = "deployment_updates"
+	ChannelBuildUpdates      = "build_updates"
+)
+
+// StatusNotification is the payload sent when a deployment or build changes status
+type StatusNotification struct {
+	ID           uuid.UUID  `json:"id"`
+	DeploymentID *uuid.UUID `json:"deployment_id,omitempty"` // Set for builds
+	Status       string     `json:"status"`
+}
+
+// notify sends payload on channel within tx, so listeners hear of the change only once it
+// is committed. Other databases have no NOTIFY and are left alone.
+func notify(tx *gorm.DB, channel string, payload StatusNotification) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	if err := tx.Exec("SELECT pg_notify(?, ?)", channel, string(data)).Error; err != nil {
+		return fmt.Errorf("failed to notify %s: %w", channel, err)
+	}
+
+	return nil
+}
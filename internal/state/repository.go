@@ -2,21 +2,34 @@ package state
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
 // Repository provides database operations for deployments
 type Repository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	readDB *gorm.DB      // Read replica for lookups that tolerate replication lag
+	cache  *redis.Client // Short-lived cache of single deployments; nil disables it
+
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
 }
 
-// NewRepository creates a new state repository
-func NewRepository(db *gorm.DB) *Repository {
-	return &Repository{db: db}
+// NewRepository creates a new state repository. Writes go to db, and the lookups behind
+// listings and status pages go to readDB, or to db as well when readDB is nil. When cache is
+// not nil, GetDeployment results are cached in it and dropped when the deployment is written.
+func NewRepository(db, readDB *gorm.DB, cache *redis.Client) *Repository {
+	if readDB == nil {
+		readDB = db
+	}
+	return &Repository{db: db, readDB: readDB, cache: cache}
 }
 
 // CreateDeployment creates a new deployment record
@@ -25,6 +38,10 @@ func (r *Repository) CreateDeployment(ctx context.Context, deployment *Deploymen
 		deployment.ID = uuid.New()
 	}
 
+	if deployment.LastProgressAt.IsZero() {
+		deployment.LastProgressAt = time.Now()
+	}
+
 	if err := r.db.WithContext(ctx).Create(deployment).Error; err != nil {
 		return fmt.Errorf("failed to create deployment: %w", err)
 	}
@@ -32,11 +49,83 @@ func (r *Repository) CreateDeployment(ctx context.Context, deployment *Deploymen
 	return nil
 }
 
-// GetDeployment retrieves a deployment by ID
+// CloneDeployment creates clone and copies the environment variables and ConfigMaps of the
+// deployment it was cloned from. Secret values are copied as stored, still encrypted.
+func (r *Repository) CloneDeployment(ctx context.Context, clone *Deployment, sourceID uuid.UUID) error {
+	if clone.ID == uuid.Nil {
+		clone.ID = uuid.New()
+	}
+
+	if clone.LastProgressAt.IsZero() {
+		clone.LastProgressAt = time.Now()
+	}
+
+	clone.ClonedFromID = &sourceID
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(clone).Error; err != nil {
+			return fmt.Errorf("failed to create deployment: %w", err)
+		}
+
+		var envVars []DeploymentEnvVar
+		if err := tx.Where("deployment_id = ?", sourceID).Find(&envVars).Error; err != nil {
+			return fmt.Errorf("failed to list env vars: %w", err)
+		}
+		for i := range envVars {
+			envVars[i].ID = uuid.New()
+			envVars[i].DeploymentID = clone.ID
+			envVars[i].CreatedAt = time.Time{}
+			envVars[i].UpdatedAt = time.Time{}
+			if err := tx.Create(&envVars[i]).Error; err != nil {
+				return fmt.Errorf("failed to copy env var: %w", err)
+			}
+		}
+
+		var configMaps []DeploymentConfigMap
+		if err := tx.Where("deployment_id = ?", sourceID).Find(&configMaps).Error; err != nil {
+			return fmt.Errorf("failed to list configmaps: %w", err)
+		}
+		for i := range configMaps {
+			configMaps[i].ID = uuid.New()
+			configMaps[i].DeploymentID = clone.ID
+			configMaps[i].CreatedAt = time.Time{}
+			configMaps[i].UpdatedAt = time.Time{}
+			if err := tx.Create(&configMaps[i]).Error; err != nil {
+				return fmt.Errorf("failed to copy configmap: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetDeployment retrieves a deployment by ID from the cache or the read replica, either of
+// which may lag behind
 func (r *Repository) GetDeployment(ctx context.Context, id uuid.UUID) (*Deployment, error) {
+	if deployment := r.cachedDeployment(ctx, id); deployment != nil {
+		return deployment, nil
+	}
+
+	deployment, err := getDeployment(r.readDB.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cacheDeployment(ctx, deployment)
+	return deployment, nil
+}
+
+// GetDeploymentConsistent retrieves a deployment by ID from the primary, bypassing the cache,
+// for decisions that must see the latest status
+func (r *Repository) GetDeploymentConsistent(ctx context.Context, id uuid.UUID) (*Deployment, error) {
+	return getDeployment(r.db.WithContext(ctx), id)
+}
+
+// getDeployment retrieves a deployment with its infrastructure and builds from db
+func getDeployment(db *gorm.DB, id uuid.UUID) (*Deployment, error) {
 	var deployment Deployment
 
-	if err := r.db.WithContext(ctx).
+	if err := db.
 		Preload("Infrastructure").
 		Preload("Builds").
 		First(&deployment, "id = ?", id).Error; err != nil {
@@ -49,15 +138,58 @@ func (r *Repository) GetDeployment(ctx context.Context, id uuid.UUID) (*Deployme
 	return &deployment, nil
 }
 
+// DeploymentFilter narrows a deployment listing; empty fields match everything
+type DeploymentFilter struct {
+	Status     string
+	Cloud      string
+	Region     string
+	Scheduled  bool              // Only PENDING deployments with a scheduled rollout
+	ClonedFrom *uuid.UUID        // Only clones of this deployment
+	Tags       map[string]string // Only deployments carrying all of these tags
+	UserID     string            // Only deployments currently owned by this client
+}
+
 // ListDeployments retrieves all deployments with optional filters
 func (r *Repository) ListDeployments(ctx context.Context, limit, offset int) ([]Deployment, error) {
+	return r.ListDeploymentsFiltered(ctx, DeploymentFilter{}, limit, offset)
+}
+
+// ListDeploymentsFiltered retrieves deployments matching the filter
+func (r *Repository) ListDeploymentsFiltered(ctx context.Context, filter DeploymentFilter, limit, offset int) ([]Deployment, error) {
 	var deployments []Deployment
 
-	query := r.db.WithContext(ctx).
+	query := r.readDB.WithContext(ctx).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset)
 
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Cloud != "" {
+		query = query.Where("cloud = ?", filter.Cloud)
+	}
+	if filter.Region != "" {
+		query = query.Where("region = ?", filter.Region)
+	}
+	if filter.Scheduled {
+		query = query.Where("scheduled_at IS NOT NULL AND status = ?", "PENDING")
+	}
+	if filter.ClonedFrom != nil {
+		query = query.Where("cloned_from_id = ?", *filter.ClonedFrom)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if len(filter.Tags) > 0 {
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tag filter: %w", err)
+		}
+		// JSONB containment, served by the GIN index on tags
+		query = query.Where("tags @> ?::jsonb", string(tags))
+	}
+
 	if err := query.Find(&deployments).Error; err != nil {
 		return nil, fmt.Errorf("failed to list deployments: %w", err)
 	}
@@ -65,27 +197,324 @@ func (r *Repository) ListDeployments(ctx context.Context, limit, offset int) ([]
 	return deployments, nil
 }
 
-// UpdateDeployment updates a deployment record
+// UpdateDeployment updates a deployment record, announcing its status on
+// ChannelDeploymentUpdates when it changes. Every column is written, so the deployment must
+// have been loaded with GetDeploymentConsistent; a cached or replica copy would revert changes
+// made since.
 func (r *Repository) UpdateDeployment(ctx context.Context, deployment *Deployment) error {
-	if err := r.db.WithContext(ctx).Save(deployment).Error; err != nil {
-		return fmt.Errorf("failed to update deployment: %w", err)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous []string
+		if err := tx.Model(&Deployment{}).
+			Where("id = ?", deployment.ID).
+			Pluck("status", &previous).Error; err != nil {
+			return fmt.Errorf("failed to get deployment status: %w", err)
+		}
+
+		if err := tx.Save(deployment).Error; err != nil {
+			return fmt.Errorf("failed to update deployment: %w", err)
+		}
+
+		if len(previous) > 0 && previous[0] == deployment.Status {
+			return nil
+		}
+		return notify(tx, ChannelDeploymentUpdates, StatusNotification{ID: deployment.ID, Status: deployment.Status})
+	})
+	if err != nil {
+		return err
 	}
 
+	r.invalidateDeployment(ctx, deployment.ID)
 	return nil
 }
 
+// MergeDeploymentTags sets the given tags on a deployment and removes those listed in remove,
+// leaving its other tags untouched, and returns the resulting tags
+func (r *Repository) MergeDeploymentTags(ctx context.Context, id uuid.UUID, set map[string]string, remove []string) (map[string]string, error) {
+	var tags map[string]string
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deployment Deployment
+		if err := tx.Select("id", "tags").
+			First(&deployment, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to get deployment: %w", err)
+		}
+
+		tags = deployment.Tags
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		for k, v := range set {
+			tags[k] = v
+		}
+		for _, k := range remove {
+			delete(tags, k)
+		}
+
+		if err := tx.Model(&Deployment{ID: id}).
+			Select("Tags").
+			Updates(&Deployment{Tags: tags}).Error; err != nil {
+			return fmt.Errorf("failed to update deployment tags: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidateDeployment(ctx, id)
+	return tags, nil
+}
+
 // UpdateDeploymentStatus updates only the status of a deployment
 func (r *Repository) UpdateDeploymentStatus(ctx context.Context, id uuid.UUID, status string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&Deployment{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"status":           status,
+				"last_progress_at": time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update deployment status: %w", err)
+		}
+
+		return notify(tx, ChannelDeploymentUpdates, StatusNotification{ID: id, Status: status})
+	})
+	if err != nil {
+		return err
+	}
+
+	r.invalidateDeployment(ctx, id)
+	return nil
+}
+
+// ClaimScheduledDeployment moves a PENDING deployment to QUEUED. It reports false when the
+// deployment is no longer PENDING, such as when another scheduler already claimed it.
+func (r *Repository) ClaimScheduledDeployment(ctx context.Context, id uuid.UUID) (bool, error) {
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&Deployment{}).
+			Where("id = ? AND status = ?", id, "PENDING").
+			Updates(map[string]interface{}{
+				"status":           "QUEUED",
+				"last_progress_at": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to claim scheduled deployment: %w", result.Error)
+		}
+
+		if result.RowsAffected != 1 {
+			return nil
+		}
+
+		claimed = true
+		return notify(tx, ChannelDeploymentUpdates, StatusNotification{ID: id, Status: "QUEUED"})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if claimed {
+		r.invalidateDeployment(ctx, id)
+	}
+	return claimed, nil
+}
+
+// TouchDeploymentProgress records that a deployment is still making progress
+func (r *Repository) TouchDeploymentProgress(ctx context.Context, id uuid.UUID) error {
 	if err := r.db.WithContext(ctx).
 		Model(&Deployment{}).
 		Where("id = ?", id).
-		Update("status", status).Error; err != nil {
-		return fmt.Errorf("failed to update deployment status: %w", err)
+		Update("last_progress_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to update deployment progress: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimStuckDeployment resets the progress clock of a deployment found stuck, provided it
+// still has the status and updated_at it was found with. It reports false when the deployment
+// changed since, such as when another watchdog already claimed it.
+func (r *Repository) ClaimStuckDeployment(ctx context.Context, id uuid.UUID, status string, updatedAt time.Time) (bool, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("id = ? AND status = ? AND updated_at = ?", id, status, updatedAt).
+		Updates(map[string]interface{}{
+			"last_progress_at": now,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim stuck deployment: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// SetDeploymentPaused pauses or resumes a deployment
+func (r *Repository) SetDeploymentPaused(ctx context.Context, id uuid.UUID, paused bool) error {
+	var pausedAt *time.Time
+	if paused {
+		now := time.Now()
+		pausedAt = &now
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"paused":    paused,
+			"paused_at": pausedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to set deployment paused: %w", err)
+	}
+
+	r.invalidateDeployment(ctx, id)
+	return nil
+}
+
+// SetDeploymentBudget sets the monthly budget of a deployment, zero removing it. Budget
+// alerts are re-armed so spend is checked against the new budget from scratch.
+func (r *Repository) SetDeploymentBudget(ctx context.Context, id uuid.UUID, monthlyBudgetUSD float64) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"monthly_budget_usd":   monthlyBudgetUSD,
+			"budget_alert_percent": 0,
+			"budget_alert_sent_at": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to set deployment budget: %w", err)
+	}
+
+	r.invalidateDeployment(ctx, id)
+	return nil
+}
+
+// ListBudgetedDeployments retrieves every deployment with a monthly budget
+func (r *Repository) ListBudgetedDeployments(ctx context.Context) ([]Deployment, error) {
+	var deployments []Deployment
+
+	if err := r.readDB.WithContext(ctx).
+		Where("monthly_budget_usd > 0").
+		Order("created_at DESC").
+		Find(&deployments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list budgeted deployments: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// ListAppVPARecommendations retrieves the VPA recommendations recorded for an app's
+// deployments, newest first
+func (r *Repository) ListAppVPARecommendations(ctx context.Context, appName string) ([]VPARecommendation, error) {
+	var deployments []Deployment
+
+	if err := r.readDB.WithContext(ctx).
+		Select("id", "vpa_last_recommendation").
+		Where("app_name = ? AND vpa_last_recommendation IS NOT NULL", appName).
+		Order("updated_at DESC").
+		Find(&deployments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list VPA recommendations: %w", err)
+	}
+
+	recommendations := make([]VPARecommendation, 0, len(deployments))
+	for _, d := range deployments {
+		if d.VPALastRecommendation != nil {
+			recommendations = append(recommendations, *d.VPALastRecommendation)
+		}
+	}
+
+	return recommendations, nil
+}
+
+// RecordBudgetAlert records the budget threshold last alerted on for a deployment and when;
+// a zero percent with a nil time clears it
+func (r *Repository) RecordBudgetAlert(ctx context.Context, id uuid.UUID, percent int, sentAt *time.Time) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"budget_alert_percent": percent,
+			"budget_alert_sent_at": sentAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to record budget alert: %w", err)
+	}
+
+	r.invalidateDeployment(ctx, id)
+	return nil
+}
+
+// UpdateVPARecommendation records the resources last recommended for a deployment
+func (r *Repository) UpdateVPARecommendation(ctx context.Context, id uuid.UUID, recommendation *VPARecommendation) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{ID: id}).
+		Select("vpa_last_recommendation").
+		Updates(&Deployment{VPALastRecommendation: recommendation}).Error; err != nil {
+		return fmt.Errorf("failed to update VPA recommendation: %w", err)
+	}
+
+	r.invalidateDeployment(ctx, id)
+	return nil
+}
+
+// SetDeploymentReconciliationMode turns GitOps reconciliation of a deployment on or off
+func (r *Repository) SetDeploymentReconciliationMode(ctx context.Context, id uuid.UUID, enabled bool) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("id = ?", id).
+		Update("reconciliation_mode", enabled).Error; err != nil {
+		return fmt.Errorf("failed to set deployment reconciliation mode: %w", err)
+	}
+
+	r.invalidateDeployment(ctx, id)
+	return nil
+}
+
+// SetDeploymentInfrastructure links a deployment to its infrastructure record
+func (r *Repository) SetDeploymentInfrastructure(ctx context.Context, id, infraID uuid.UUID) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("id = ?", id).
+		Update("infrastructure_id", infraID).Error; err != nil {
+		return fmt.Errorf("failed to set deployment infrastructure: %w", err)
 	}
 
+	r.invalidateDeployment(ctx, id)
 	return nil
 }
 
+// GetDueScheduledDeployments retrieves PENDING deployments whose scheduled rollout is due,
+// skipping paused ones
+func (r *Repository) GetDueScheduledDeployments(ctx context.Context, now time.Time) ([]Deployment, error) {
+	var deployments []Deployment
+
+	if err := r.db.WithContext(ctx).
+		Where("scheduled_at IS NOT NULL AND scheduled_at <= ? AND status = ? AND paused = ?", now, "PENDING", false).
+		Order("scheduled_at ASC").
+		Find(&deployments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get scheduled deployments: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// GetStuckDeployments retrieves deployments in a status that have made no progress since the given time
+func (r *Repository) GetStuckDeployments(ctx context.Context, status string, since time.Time) ([]Deployment, error) {
+	var deployments []Deployment
+
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND last_progress_at < ? AND updated_at < ?", status, since, since).
+		Order("last_progress_at ASC").
+		Find(&deployments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get stuck deployments: %w", err)
+	}
+
+	return deployments, nil
+}
+
 // DeleteDeployment deletes a deployment and related records
 func (r *Repository) DeleteDeployment(ctx context.Context, id uuid.UUID) error {
 	// Delete related infrastructure and builds (cascade)
@@ -101,11 +530,64 @@ func (r *Repository) DeleteDeployment(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("failed to delete builds: %w", err)
 	}
 
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", id).
+		Delete(&DeploymentEnvVar{}).Error; err != nil {
+		return fmt.Errorf("failed to delete env vars: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", id).
+		Delete(&DeploymentConfigMap{}).Error; err != nil {
+		return fmt.Errorf("failed to delete configmaps: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", id).
+		Delete(&GitHook{}).Error; err != nil {
+		return fmt.Errorf("failed to delete git hooks: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", id).
+		Delete(&VulnerabilityScan{}).Error; err != nil {
+		return fmt.Errorf("failed to delete vulnerability scans: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", id).
+		Delete(&CVESuppression{}).Error; err != nil {
+		return fmt.Errorf("failed to delete CVE suppressions: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", id).
+		Delete(&Pipeline{}).Error; err != nil {
+		return fmt.Errorf("failed to delete pipeline: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("source_deployment_id = ? OR target_deployment_id = ?", id, id).
+		Delete(&ServiceRoute{}).Error; err != nil {
+		return fmt.Errorf("failed to delete service routes: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("baseline_deployment_id = ? OR variant_deployment_id = ?", id, id).
+		Delete(&ABTest{}).Error; err != nil {
+		return fmt.Errorf("failed to delete A/B tests: %w", err)
+	}
+
+	if err := r.ClearDeploymentMirrors(ctx, id); err != nil {
+		return err
+	}
+
 	// Delete deployment
 	if err := r.db.WithContext(ctx).Delete(&Deployment{}, "id = ?", id).Error; err != nil {
 		return fmt.Errorf("failed to delete deployment: %w", err)
 	}
 
+	r.invalidateDeployment(ctx, id)
 	return nil
 }
 
@@ -122,11 +604,27 @@ func (r *Repository) CreateInfrastructure(ctx context.Context, infra *Infrastruc
 	return nil
 }
 
+// GetInfrastructureByRelease retrieves infrastructure by the Kubernetes namespace and release deployed on it
+func (r *Repository) GetInfrastructureByRelease(ctx context.Context, namespace, releaseName string) (*Infrastructure, error) {
+	var infra Infrastructure
+
+	if err := r.db.WithContext(ctx).
+		Where("kube_namespace = ? AND helm_release_name = ?", namespace, releaseName).
+		First(&infra).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("infrastructure not found for release: %s/%s", namespace, releaseName)
+		}
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	return &infra, nil
+}
+
 // GetInfrastructure retrieves infrastructure by deployment ID
 func (r *Repository) GetInfrastructure(ctx context.Context, deploymentID uuid.UUID) (*Infrastructure, error) {
 	var infra Infrastructure
 
-	if err := r.db.WithContext(ctx).
+	if err := r.readDB.WithContext(ctx).
 		First(&infra, "deployment_id = ?", deploymentID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("infrastructure not found for deployment: %s", deploymentID)
@@ -146,6 +644,19 @@ func (r *Repository) UpdateInfrastructure(ctx context.Context, infra *Infrastruc
 	return nil
 }
 
+// UpdateInfrastructureAddonVersions records the chart versions of the platform addons
+// installed on the infrastructure's cluster
+func (r *Repository) UpdateInfrastructureAddonVersions(ctx context.Context, id uuid.UUID, versions map[string]string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Infrastructure{ID: id}).
+		Select("addon_versions").
+		Updates(&Infrastructure{AddonVersions: versions}).Error; err != nil {
+		return fmt.Errorf("failed to update addon versions: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateInfrastructureStatus updates only the status of infrastructure
 func (r *Repository) UpdateInfrastructureStatus(ctx context.Context, id uuid.UUID, status string) error {
 	if err := r.db.WithContext(ctx).
@@ -192,7 +703,8 @@ func (r *Repository) AppendProvisionLog(ctx context.Context, id uuid.UUID, logEn
 		return fmt.Errorf("failed to append provision log: %w", err)
 	}
 
-	return nil
+	// Log output means the pipeline is alive
+	return r.TouchDeploymentProgress(ctx, infra.DeploymentID)
 }
 
 // GetInfrastructureByStackName retrieves infrastructure by Pulumi stack name (for idempotency)
@@ -224,27 +736,105 @@ func (r *Repository) ListInfrastructureByStatus(ctx context.Context, status stri
 	return infrastructures, nil
 }
 
-// MarkInfrastructureReady marks infrastructure as ready with cluster endpoint and CA cert
-func (r *Repository) MarkInfrastructureReady(ctx context.Context, id uuid.UUID, endpoint, caCert string) error {
+// ListInfrastructureByStackNames retrieves every infrastructure record that uses one of the
+// given Pulumi stacks
+func (r *Repository) ListInfrastructureByStackNames(ctx context.Context, stackNames []string) ([]*Infrastructure, error) {
+	var infrastructures []*Infrastructure
+
+	if len(stackNames) == 0 {
+		return infrastructures, nil
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("pulumi_stack_name IN ?", stackNames).
+		Order("created_at DESC").
+		Find(&infrastructures).Error; err != nil {
+		return nil, fmt.Errorf("failed to list infrastructure by stack names: %w", err)
+	}
+
+	return infrastructures, nil
+}
+
+// UpdateInfrastructureCost records the projected monthly cost of infrastructure
+func (r *Repository) UpdateInfrastructureCost(ctx context.Context, id uuid.UUID, monthlyCostUSD float64) error {
 	if err := r.db.WithContext(ctx).
 		Model(&Infrastructure{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"status":           "READY",
-			"cluster_endpoint": endpoint,
-			"cluster_ca_cert":  caCert,
+			"estimated_monthly_cost_usd": monthlyCostUSD,
+			"cost_updated_at":            time.Now(),
 		}).Error; err != nil {
-		return fmt.Errorf("failed to mark infrastructure as ready: %w", err)
+		return fmt.Errorf("failed to update infrastructure cost: %w", err)
 	}
 
 	return nil
 }
 
-// CreateBuild creates a build record
-func (r *Repository) CreateBuild(ctx context.Context, build *Build) error {
-	if build.ID == uuid.Nil {
-		build.ID = uuid.New()
-	}
+// UpdateInfrastructureEgress records the external egress measured for infrastructure's cluster
+func (r *Repository) UpdateInfrastructureEgress(ctx context.Context, id uuid.UUID, egressGB float64, checkedAt time.Time) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Infrastructure{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_egress_gb":       egressGB,
+			"last_egress_check_at": checkedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update infrastructure egress: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateInfrastructureWAF records the Cloud Armor policy attached to infrastructure's load
+// balancer, or why attaching it failed
+func (r *Repository) UpdateInfrastructureWAF(ctx context.Context, id uuid.UUID, policyName, backendService, wafError string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Infrastructure{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"waf_policy_name":     policyName,
+			"waf_backend_service": backendService,
+			"waf_error":           wafError,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update infrastructure WAF: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateInfrastructureRateLimitRule records the Cloud Armor rate limit rule of an infrastructure
+func (r *Repository) UpdateInfrastructureRateLimitRule(ctx context.Context, id uuid.UUID, ruleName string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Infrastructure{}).
+		Where("id = ?", id).
+		Update("rate_limit_rule_name", ruleName).Error; err != nil {
+		return fmt.Errorf("failed to update infrastructure rate limit rule: %w", err)
+	}
+
+	return nil
+}
+
+// MarkInfrastructureReady marks infrastructure as ready with cluster endpoint and CA cert
+func (r *Repository) MarkInfrastructureReady(ctx context.Context, id uuid.UUID, endpoint, caCert string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&Infrastructure{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":           "READY",
+			"cluster_endpoint": endpoint,
+			"cluster_ca_cert":  caCert,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark infrastructure as ready: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBuild creates a build record
+func (r *Repository) CreateBuild(ctx context.Context, build *Build) error {
+	if build.ID == uuid.Nil {
+		build.ID = uuid.New()
+	}
 
 	if err := r.db.WithContext(ctx).Create(build).Error; err != nil {
 		return fmt.Errorf("failed to create build: %w", err)
@@ -253,45 +843,691 @@ func (r *Repository) CreateBuild(ctx context.Context, build *Build) error {
 	return nil
 }
 
-// UpdateBuild updates a build record
-func (r *Repository) UpdateBuild(ctx context.Context, build *Build) error {
-	if err := r.db.WithContext(ctx).Save(build).Error; err != nil {
-		return fmt.Errorf("failed to update build: %w", err)
+// UpdateBuild updates a build record
+func (r *Repository) UpdateBuild(ctx context.Context, build *Build) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(build).Error; err != nil {
+			return fmt.Errorf("failed to update build: %w", err)
+		}
+
+		return notify(tx, ChannelBuildUpdates, StatusNotification{
+			ID:           build.ID,
+			DeploymentID: &build.DeploymentID,
+			Status:       build.Status,
+		})
+	})
+}
+
+// GetBuildByID retrieves a build by its ID
+func (r *Repository) GetBuildByID(ctx context.Context, buildID uuid.UUID) (*Build, error) {
+	var build Build
+
+	if err := r.readDB.WithContext(ctx).
+		First(&build, "id = ?", buildID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil // Return nil, nil for not found instead of error
+		}
+		return nil, fmt.Errorf("failed to get build: %w", err)
+	}
+
+	return &build, nil
+}
+
+// GetLatestBuild retrieves the most recent build for a deployment
+func (r *Repository) GetLatestBuild(ctx context.Context, deploymentID uuid.UUID) (*Build, error) {
+	var build Build
+
+	if err := r.readDB.WithContext(ctx).
+		Where("deployment_id = ?", deploymentID).
+		Order("started_at DESC").
+		First(&build).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("no builds found for deployment: %s", deploymentID)
+		}
+		return nil, fmt.Errorf("failed to get latest build: %w", err)
+	}
+
+	return &build, nil
+}
+
+// CreateDeploymentLog records a deployment log entry. Warnings and errors are also added to
+// the deployment's event feed.
+func (r *Repository) CreateDeploymentLog(ctx context.Context, entry *DeploymentLog) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to create deployment log: %w", err)
+		}
+
+		if entry.Level != "WARNING" && entry.Level != "WARN" && entry.Level != "ERROR" {
+			return nil
+		}
+
+		event := &DeploymentEvent{
+			ID:           uuid.New(),
+			DeploymentID: entry.DeploymentID,
+			Type:         EventTypeLog,
+			Message:      entry.Message,
+			Metadata: map[string]interface{}{
+				"level":  entry.Level,
+				"phase":  entry.Phase,
+				"source": entry.Source,
+			},
+			CreatedAt: entry.CreatedAt,
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to create deployment event: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ListDeploymentLogs retrieves a deployment's log entries oldest first, optionally limited to one
+// phase. A non-empty search keeps only entries whose message matches it as a full-text query,
+// each with the matching excerpt in Highlight.
+func (r *Repository) ListDeploymentLogs(ctx context.Context, deploymentID uuid.UUID, phase, search string) ([]DeploymentLog, error) {
+	var logs []DeploymentLog
+
+	query := r.readDB.WithContext(ctx).Where("deployment_id = ?", deploymentID)
+	if phase != "" {
+		query = query.Where("phase = ?", phase)
+	}
+
+	if search != "" {
+		query = query.
+			Select("*, ts_headline('english', message, plainto_tsquery('english', ?), 'MaxWords=10') AS highlight", search).
+			Where("message_tsv @@ plainto_tsquery('english', ?)", search)
+	}
+
+	if err := query.Order("created_at ASC").Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deployment logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// CleanupOldLogs deletes deployment log entries older than retentionDays and returns how many
+// were deleted. A retentionDays below 1 keeps logs forever.
+func (r *Repository) CleanupOldLogs(ctx context.Context, retentionDays int) (int64, error) {
+	if retentionDays < 1 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	result := r.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&DeploymentLog{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to clean up deployment logs: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// CreateDeploymentEvent records an entry of a deployment's event feed
+func (r *Repository) CreateDeploymentEvent(ctx context.Context, event *DeploymentEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create deployment event: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeploymentEvents retrieves a deployment's events oldest first. A non-zero since only
+// returns events recorded after it.
+func (r *Repository) ListDeploymentEvents(ctx context.Context, deploymentID uuid.UUID, since time.Time, limit, offset int) ([]DeploymentEvent, error) {
+	var events []DeploymentEvent
+
+	query := r.readDB.WithContext(ctx).
+		Where("deployment_id = ?", deploymentID).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset)
+
+	if !since.IsZero() {
+		query = query.Where("created_at > ?", since)
+	}
+
+	if err := query.Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deployment events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetLatestDeploymentEvent retrieves a deployment's most recent event of one type
+func (r *Repository) GetLatestDeploymentEvent(ctx context.Context, deploymentID uuid.UUID, eventType string) (*DeploymentEvent, error) {
+	var event DeploymentEvent
+
+	err := r.db.WithContext(ctx).
+		Where("deployment_id = ? AND type = ?", deploymentID, eventType).
+		Order("created_at DESC").
+		First(&event).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("no %s events found for deployment: %s", eventType, deploymentID)
+		}
+		return nil, fmt.Errorf("failed to get latest deployment event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// CreateDeploymentDependencies records the deployments a deployment depends on
+func (r *Repository) CreateDeploymentDependencies(ctx context.Context, deploymentID uuid.UUID, dependsOn []uuid.UUID) error {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+
+	dependencies := make([]DeploymentDependency, len(dependsOn))
+	for i, id := range dependsOn {
+		dependencies[i] = DeploymentDependency{
+			ID:           uuid.New(),
+			DeploymentID: deploymentID,
+			DependsOnID:  id,
+		}
+	}
+
+	if err := r.db.WithContext(ctx).Create(&dependencies).Error; err != nil {
+		return fmt.Errorf("failed to create deployment dependencies: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeploymentDependencies retrieves the IDs of the deployments a deployment depends on
+func (r *Repository) ListDeploymentDependencies(ctx context.Context, deploymentID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	if err := r.db.WithContext(ctx).
+		Model(&DeploymentDependency{}).
+		Where("deployment_id = ?", deploymentID).
+		Order("created_at ASC").
+		Pluck("depends_on_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deployment dependencies: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetBlockingDependencies retrieves the IDs of a deployment's dependencies that are not
+// EXPOSED or HEALTHY. Dependencies that have been deleted are blocking too.
+func (r *Repository) GetBlockingDependencies(ctx context.Context, deploymentID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := r.ListDeploymentDependencies(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	dependencies, err := r.GetDeploymentsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := make(map[uuid.UUID]bool, len(dependencies))
+	for _, d := range dependencies {
+		ready[d.ID] = d.Status == "EXPOSED" || d.Status == "HEALTHY"
+	}
+
+	var blocking []uuid.UUID
+	for _, id := range ids {
+		if !ready[id] {
+			blocking = append(blocking, id)
+		}
+	}
+
+	return blocking, nil
+}
+
+// CreateServiceRoute records a service route between two deployments
+func (r *Repository) CreateServiceRoute(ctx context.Context, route *ServiceRoute) error {
+	if route.ID == uuid.Nil {
+		route.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(route).Error; err != nil {
+		return fmt.Errorf("failed to create service route: %w", err)
+	}
+
+	return nil
+}
+
+// ListServiceRoutes retrieves the service routes sending a deployment's traffic elsewhere,
+// oldest first
+func (r *Repository) ListServiceRoutes(ctx context.Context, sourceDeploymentID uuid.UUID) ([]ServiceRoute, error) {
+	var routes []ServiceRoute
+
+	if err := r.db.WithContext(ctx).
+		Where("source_deployment_id = ?", sourceDeploymentID).
+		Order("created_at ASC").
+		Find(&routes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list service routes: %w", err)
+	}
+
+	return routes, nil
+}
+
+// DeleteServiceRoute deletes a service route
+func (r *Repository) DeleteServiceRoute(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&ServiceRoute{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete service route: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteServiceRoutesForDeployment deletes the service routes from or to a deployment and
+// returns the deleted routes
+func (r *Repository) DeleteServiceRoutesForDeployment(ctx context.Context, deploymentID uuid.UUID) ([]ServiceRoute, error) {
+	var routes []ServiceRoute
+
+	if err := r.db.WithContext(ctx).
+		Where("source_deployment_id = ? OR target_deployment_id = ?", deploymentID, deploymentID).
+		Find(&routes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list service routes: %w", err)
+	}
+	if len(routes) == 0 {
+		return nil, nil
+	}
+
+	if err := r.db.WithContext(ctx).
+		Where("source_deployment_id = ? OR target_deployment_id = ?", deploymentID, deploymentID).
+		Delete(&ServiceRoute{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete service routes: %w", err)
+	}
+
+	return routes, nil
+}
+
+// CreateABTest records an A/B test between two deployments
+func (r *Repository) CreateABTest(ctx context.Context, test *ABTest) error {
+	if test.ID == uuid.Nil {
+		test.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(test).Error; err != nil {
+		return fmt.Errorf("failed to create A/B test: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateABTest updates an A/B test
+func (r *Repository) UpdateABTest(ctx context.Context, test *ABTest) error {
+	if err := r.db.WithContext(ctx).Save(test).Error; err != nil {
+		return fmt.Errorf("failed to update A/B test: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteABTest deletes an A/B test
+func (r *Repository) DeleteABTest(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Delete(&ABTest{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete A/B test: %w", err)
+	}
+
+	return nil
+}
+
+// GetVariantABTest retrieves the latest A/B test of a variant deployment, or nil when it has
+// none
+func (r *Repository) GetVariantABTest(ctx context.Context, variantDeploymentID uuid.UUID) (*ABTest, error) {
+	var test ABTest
+
+	if err := r.db.WithContext(ctx).
+		Where("variant_deployment_id = ?", variantDeploymentID).
+		Order("created_at DESC").
+		First(&test).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get A/B test: %w", err)
+	}
+
+	return &test, nil
+}
+
+// GetRoutingABTest retrieves the running or promoting A/B test routing a baseline deployment's
+// traffic, or nil when none does
+func (r *Repository) GetRoutingABTest(ctx context.Context, baselineDeploymentID uuid.UUID) (*ABTest, error) {
+	var test ABTest
+
+	if err := r.db.WithContext(ctx).
+		Where("baseline_deployment_id = ? AND status IN ?", baselineDeploymentID,
+			[]string{ABTestStatusRunning, ABTestStatusPromoting}).
+		Order("created_at DESC").
+		First(&test).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get A/B test: %w", err)
+	}
+
+	return &test, nil
+}
+
+// EndABTests ends the A/B tests a destroyed deployment takes part in. Running tests are
+// cancelled and promoting ones concluded. It returns the tests it ended.
+func (r *Repository) EndABTests(ctx context.Context, deploymentID uuid.UUID) ([]ABTest, error) {
+	var tests []ABTest
+
+	if err := r.db.WithContext(ctx).
+		Where("(baseline_deployment_id = ? OR variant_deployment_id = ?) AND status IN ?", deploymentID, deploymentID,
+			[]string{ABTestStatusRunning, ABTestStatusPromoting}).
+		Find(&tests).Error; err != nil {
+		return nil, fmt.Errorf("failed to list A/B tests: %w", err)
+	}
+
+	now := time.Now()
+	for i := range tests {
+		if tests[i].Status == ABTestStatusRunning {
+			tests[i].Status = ABTestStatusCancelled
+		} else {
+			tests[i].Status = ABTestStatusConcluded
+		}
+		if tests[i].ConcludedAt == nil {
+			tests[i].ConcludedAt = &now
+		}
+		if err := r.db.WithContext(ctx).Save(&tests[i]).Error; err != nil {
+			return nil, fmt.Errorf("failed to end A/B test: %w", err)
+		}
+	}
+
+	return tests, nil
+}
+
+// GetMirroringDeployment retrieves the deployment mirroring a deployment's traffic, or nil when
+// none does
+func (r *Repository) GetMirroringDeployment(ctx context.Context, sourceDeploymentID uuid.UUID) (*Deployment, error) {
+	var deployment Deployment
+
+	if err := r.db.WithContext(ctx).
+		Where("mirror_from = ?", sourceDeploymentID).
+		Order("created_at DESC").
+		First(&deployment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get mirroring deployment: %w", err)
+	}
+
+	return &deployment, nil
+}
+
+// ClearDeploymentMirrors stops every deployment mirroring a deployment's traffic
+func (r *Repository) ClearDeploymentMirrors(ctx context.Context, sourceDeploymentID uuid.UUID) error {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("mirror_from = ?", sourceDeploymentID).
+		Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("failed to list mirroring deployments: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&Deployment{}).
+		Where("id IN ?", ids).
+		Update("mirror_from", nil).Error; err != nil {
+		return fmt.Errorf("failed to clear deployment mirrors: %w", err)
+	}
+
+	for _, id := range ids {
+		r.invalidateDeployment(ctx, id)
+	}
+	return nil
+}
+
+// SetDeploymentEnvVar creates or replaces an environment variable of a deployment
+func (r *Repository) SetDeploymentEnvVar(ctx context.Context, envVar *DeploymentEnvVar) error {
+	var existing DeploymentEnvVar
+	err := r.db.WithContext(ctx).
+		Where("deployment_id = ? AND key = ?", envVar.DeploymentID, envVar.Key).
+		First(&existing).Error
+
+	switch {
+	case err == nil:
+		envVar.ID = existing.ID
+		envVar.CreatedAt = existing.CreatedAt
+		if err := r.db.WithContext(ctx).Save(envVar).Error; err != nil {
+			return fmt.Errorf("failed to update env var: %w", err)
+		}
+	case err == gorm.ErrRecordNotFound:
+		envVar.ID = uuid.New()
+		if err := r.db.WithContext(ctx).Create(envVar).Error; err != nil {
+			return fmt.Errorf("failed to create env var: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to get env var: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeploymentEnvVars retrieves a deployment's environment variables ordered by key
+func (r *Repository) ListDeploymentEnvVars(ctx context.Context, deploymentID uuid.UUID) ([]DeploymentEnvVar, error) {
+	var envVars []DeploymentEnvVar
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", deploymentID).
+		Order("key ASC").
+		Find(&envVars).Error; err != nil {
+		return nil, fmt.Errorf("failed to list env vars: %w", err)
+	}
+
+	return envVars, nil
+}
+
+// DeleteDeploymentEnvVar removes an environment variable of a deployment
+func (r *Repository) DeleteDeploymentEnvVar(ctx context.Context, deploymentID uuid.UUID, key string) error {
+	result := r.db.WithContext(ctx).
+		Where("deployment_id = ? AND key = ?", deploymentID, key).
+		Delete(&DeploymentEnvVar{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete env var: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("env var not found: %s", key)
+	}
+
+	return nil
+}
+
+// SetDeploymentConfigMap creates or replaces a ConfigMap of a deployment
+func (r *Repository) SetDeploymentConfigMap(ctx context.Context, configMap *DeploymentConfigMap) error {
+	var existing DeploymentConfigMap
+	err := r.db.WithContext(ctx).
+		Where("deployment_id = ? AND name = ?", configMap.DeploymentID, configMap.Name).
+		First(&existing).Error
+
+	switch {
+	case err == nil:
+		configMap.ID = existing.ID
+		configMap.CreatedAt = existing.CreatedAt
+		if err := r.db.WithContext(ctx).Save(configMap).Error; err != nil {
+			return fmt.Errorf("failed to update configmap: %w", err)
+		}
+	case err == gorm.ErrRecordNotFound:
+		configMap.ID = uuid.New()
+		if err := r.db.WithContext(ctx).Create(configMap).Error; err != nil {
+			return fmt.Errorf("failed to create configmap: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to get configmap: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeploymentConfigMaps retrieves a deployment's ConfigMaps ordered by name
+func (r *Repository) ListDeploymentConfigMaps(ctx context.Context, deploymentID uuid.UUID) ([]DeploymentConfigMap, error) {
+	var configMaps []DeploymentConfigMap
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id = ?", deploymentID).
+		Order("name ASC").
+		Find(&configMaps).Error; err != nil {
+		return nil, fmt.Errorf("failed to list configmaps: %w", err)
+	}
+
+	return configMaps, nil
+}
+
+// DeleteDeploymentConfigMap removes a ConfigMap of a deployment
+func (r *Repository) DeleteDeploymentConfigMap(ctx context.Context, deploymentID uuid.UUID, name string) error {
+	result := r.db.WithContext(ctx).
+		Where("deployment_id = ? AND name = ?", deploymentID, name).
+		Delete(&DeploymentConfigMap{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete configmap: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("configmap not found: %s", name)
+	}
+
+	return nil
+}
+
+// CreateAuditLog records a privileged operation
+func (r *Repository) CreateAuditLog(ctx context.Context, entry *AuditLog) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
 	}
 
 	return nil
 }
 
-// GetBuildByID retrieves a build by its ID
-func (r *Repository) GetBuildByID(ctx context.Context, buildID uuid.UUID) (*Build, error) {
-	var build Build
+// resourcePolicyID is the primary key of the single resource policy row
+const resourcePolicyID = 1
+
+// GetResourcePolicy retrieves the admin-set resource policy, or nil when none has been set
+func (r *Repository) GetResourcePolicy(ctx context.Context) (*ResourcePolicy, error) {
+	var policy ResourcePolicy
 
 	if err := r.db.WithContext(ctx).
-		First(&build, "id = ?", buildID).Error; err != nil {
+		First(&policy, "id = ?", resourcePolicyID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, nil // Return nil, nil for not found instead of error
+			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to get build: %w", err)
+		return nil, fmt.Errorf("failed to get resource policy: %w", err)
 	}
 
-	return &build, nil
+	return &policy, nil
 }
 
-// GetLatestBuild retrieves the most recent build for a deployment
-func (r *Repository) GetLatestBuild(ctx context.Context, deploymentID uuid.UUID) (*Build, error) {
-	var build Build
+// SaveResourcePolicy creates or replaces the admin-set resource policy
+func (r *Repository) SaveResourcePolicy(ctx context.Context, policy *ResourcePolicy) error {
+	policy.ID = resourcePolicyID
 
-	if err := r.db.WithContext(ctx).
-		Where("deployment_id = ?", deploymentID).
-		Order("started_at DESC").
-		First(&build).Error; err != nil {
+	if err := r.db.WithContext(ctx).Save(policy).Error; err != nil {
+		return fmt.Errorf("failed to save resource policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetPipeline retrieves the latest pipeline of a deployment
+func (r *Repository) GetPipeline(ctx context.Context, deploymentID uuid.UUID) (*Pipeline, error) {
+	var pipeline Pipeline
+
+	if err := r.readDB.WithContext(ctx).Where("deployment_id = ?", deploymentID).First(&pipeline).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("no builds found for deployment: %s", deploymentID)
+			return nil, fmt.Errorf("pipeline not found for deployment: %s", deploymentID)
 		}
-		return nil, fmt.Errorf("failed to get latest build: %w", err)
+		return nil, fmt.Errorf("failed to get pipeline: %w", err)
 	}
 
-	return &build, nil
+	return &pipeline, nil
+}
+
+// StartPipeline records a new pipeline for a deployment, replacing its previous one
+func (r *Repository) StartPipeline(ctx context.Context, pipeline *Pipeline) error {
+	if pipeline.ID == uuid.Nil {
+		pipeline.ID = uuid.New()
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("deployment_id = ?", pipeline.DeploymentID).Delete(&Pipeline{}).Error; err != nil {
+			return fmt.Errorf("failed to delete previous pipeline: %w", err)
+		}
+
+		if err := tx.Create(pipeline).Error; err != nil {
+			return fmt.Errorf("failed to create pipeline: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdatePipeline saves the stages of a pipeline
+func (r *Repository) UpdatePipeline(ctx context.Context, pipeline *Pipeline) error {
+	if err := r.db.WithContext(ctx).Save(pipeline).Error; err != nil {
+		return fmt.Errorf("failed to update pipeline: %w", err)
+	}
+
+	return nil
+}
+
+// CreateFederatedDeployment creates a federated deployment record
+func (r *Repository) CreateFederatedDeployment(ctx context.Context, federated *FederatedDeployment) error {
+	if federated.ID == uuid.Nil {
+		federated.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(federated).Error; err != nil {
+		return fmt.Errorf("failed to create federated deployment: %w", err)
+	}
+
+	return nil
+}
+
+// GetFederatedDeployment retrieves a federated deployment by ID
+func (r *Repository) GetFederatedDeployment(ctx context.Context, id uuid.UUID) (*FederatedDeployment, error) {
+	var federated FederatedDeployment
+
+	if err := r.db.WithContext(ctx).First(&federated, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("federated deployment not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get federated deployment: %w", err)
+	}
+
+	return &federated, nil
+}
+
+// GetDeploymentsByIDs retrieves the deployments with the given IDs; deleted deployments are skipped
+func (r *Repository) GetDeploymentsByIDs(ctx context.Context, ids []uuid.UUID) ([]Deployment, error) {
+	var deployments []Deployment
+
+	if len(ids) == 0 {
+		return deployments, nil
+	}
+
+	if err := r.readDB.WithContext(ctx).
+		Where("id IN ?", ids).
+		Order("created_at ASC").
+		Find(&deployments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get deployments: %w", err)
+	}
+
+	return deployments, nil
 }
 
 // GetDeploymentsByStatus retrieves deployments by status
@@ -338,14 +1574,450 @@ func (r *Repository) MarkDeploymentAsDeployed(ctx context.Context, id uuid.UUID,
 		return fmt.Errorf("failed to mark deployment as deployed: %w", err)
 	}
 
+	r.invalidateDeployment(ctx, id)
+	return nil
+}
+
+// CreateDeploymentApproval records a rollout awaiting approval
+func (r *Repository) CreateDeploymentApproval(ctx context.Context, approval *DeploymentApproval) error {
+	if approval.ID == uuid.Nil {
+		approval.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(approval).Error; err != nil {
+		return fmt.Errorf("failed to create deployment approval: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeploymentApproval retrieves a deployment approval by ID
+func (r *Repository) GetDeploymentApproval(ctx context.Context, id uuid.UUID) (*DeploymentApproval, error) {
+	var approval DeploymentApproval
+
+	if err := r.db.WithContext(ctx).First(&approval, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get deployment approval: %w", err)
+	}
+
+	return &approval, nil
+}
+
+// DecideDeploymentApproval moves a pending approval to status and reports whether it did.
+// It returns false when the approval was decided or expired concurrently.
+func (r *Repository) DecideDeploymentApproval(ctx context.Context, approval *DeploymentApproval, status, decidedBy string) (bool, error) {
+	now := time.Now()
+
+	result := r.db.WithContext(ctx).
+		Model(&DeploymentApproval{}).
+		Where("id = ? AND status = ?", approval.ID, ApprovalStatusPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"decided_by": decidedBy,
+			"decided_at": now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update deployment approval: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	approval.Status = status
+	approval.DecidedBy = decidedBy
+	approval.DecidedAt = &now
+
+	return true, nil
+}
+
+// TransferDeploymentOwnership makes newOwner the owner of a deployment and records the
+// transfer in its ownership history
+func (r *Repository) TransferDeploymentOwnership(ctx context.Context, deployment *Deployment, newOwner, transferredBy string) (*DeploymentOwnershipHistory, error) {
+	entry := &DeploymentOwnershipHistory{
+		ID:            uuid.New(),
+		DeploymentID:  deployment.ID,
+		PreviousOwner: deployment.UserID,
+		NewOwner:      newOwner,
+		TransferredBy: transferredBy,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Deployment{}).
+			Where("id = ?", deployment.ID).
+			Update("user_id", newOwner).Error; err != nil {
+			return fmt.Errorf("failed to update deployment owner: %w", err)
+		}
+
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to record ownership transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deployment.UserID = newOwner
+	r.invalidateDeployment(ctx, deployment.ID)
+	return entry, nil
+}
+
+// TransferAllDeployments makes newOwner the owner of every deployment owned by owner,
+// recording each transfer, and returns the IDs of the deployments transferred
+func (r *Repository) TransferAllDeployments(ctx context.Context, owner, newOwner, transferredBy string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Deployment{}).
+			Where("user_id = ?", owner).
+			Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to list owned deployments: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&Deployment{}).
+			Where("id IN ?", ids).
+			Update("user_id", newOwner).Error; err != nil {
+			return fmt.Errorf("failed to update deployment owners: %w", err)
+		}
+
+		entries := make([]DeploymentOwnershipHistory, len(ids))
+		for i, id := range ids {
+			entries[i] = DeploymentOwnershipHistory{
+				ID:            uuid.New(),
+				DeploymentID:  id,
+				PreviousOwner: owner,
+				NewOwner:      newOwner,
+				TransferredBy: transferredBy,
+			}
+		}
+		if err := tx.Create(&entries).Error; err != nil {
+			return fmt.Errorf("failed to record ownership transfers: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		r.invalidateDeployment(ctx, id)
+	}
+	return ids, nil
+}
+
+// ListDeploymentOwnershipHistory retrieves the ownership transfers of a deployment, oldest first
+func (r *Repository) ListDeploymentOwnershipHistory(ctx context.Context, deploymentID uuid.UUID) ([]DeploymentOwnershipHistory, error) {
+	var history []DeploymentOwnershipHistory
+
+	if err := r.readDB.WithContext(ctx).
+		Where("deployment_id = ?", deploymentID).
+		Order("created_at ASC").
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ownership history: %w", err)
+	}
+
+	return history, nil
+}
+
+// CreateGitHook records a git hook of a deployment
+func (r *Repository) CreateGitHook(ctx context.Context, hook *GitHook) error {
+	if hook.ID == uuid.Nil {
+		hook.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(hook).Error; err != nil {
+		return fmt.Errorf("failed to create git hook: %w", err)
+	}
+
+	return nil
+}
+
+// ListGitHooksByRepo retrieves a provider's git hooks registered for any of the given clone URLs
+func (r *Repository) ListGitHooksByRepo(ctx context.Context, provider string, repoURLs []string) ([]GitHook, error) {
+	var hooks []GitHook
+
+	if err := r.db.WithContext(ctx).
+		Where("provider = ? AND repo_url IN ?", provider, repoURLs).
+		Order("created_at ASC").
+		Find(&hooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list git hooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+// MarkGitHookTriggered records the commit a git hook last started a build for
+func (r *Repository) MarkGitHookTriggered(ctx context.Context, id uuid.UUID, commitSHA string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&GitHook{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_commit_sha":   commitSHA,
+			"last_triggered_at": time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update git hook: %w", err)
+	}
+
+	return nil
+}
+
+// CreateVulnerabilityScan records the scan of a build's image
+func (r *Repository) CreateVulnerabilityScan(ctx context.Context, scan *VulnerabilityScan) error {
+	if scan.ID == uuid.Nil {
+		scan.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(scan).Error; err != nil {
+		return fmt.Errorf("failed to create vulnerability scan: %w", err)
+	}
+
+	return nil
+}
+
+// GetVulnerabilityScanByBuild retrieves the latest scan of a build's image, nil when it was
+// not scanned
+func (r *Repository) GetVulnerabilityScanByBuild(ctx context.Context, buildID uuid.UUID) (*VulnerabilityScan, error) {
+	var scan VulnerabilityScan
+
+	if err := r.db.WithContext(ctx).
+		Where("build_id = ?", buildID).
+		Order("scan_time DESC").
+		First(&scan).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get vulnerability scan: %w", err)
+	}
+
+	return &scan, nil
+}
+
+// ListUnresolvedVulnerabilityScans retrieves, for each deployment, its latest scan when that
+// scan still found vulnerabilities of the given severity: CRITICAL, HIGH, MEDIUM or LOW.
+// Vulnerabilities fixed by a later build are resolved.
+func (r *Repository) ListUnresolvedVulnerabilityScans(ctx context.Context, severity string) ([]VulnerabilityScan, error) {
+	column, ok := map[string]string{
+		"CRITICAL": "critical_count",
+		"HIGH":     "high_count",
+		"MEDIUM":   "medium_count",
+		"LOW":      "low_count",
+	}[severity]
+	if !ok {
+		return nil, fmt.Errorf("unknown severity: %s", severity)
+	}
+
+	var scans []VulnerabilityScan
+
+	if err := r.db.WithContext(ctx).
+		Omit("raw_result").
+		Where(column + " > 0").
+		Where("scan_time = (SELECT MAX(latest.scan_time) FROM vulnerability_scans latest WHERE latest.deployment_id = vulnerability_scans.deployment_id)").
+		Order(column + " DESC").
+		Find(&scans).Error; err != nil {
+		return nil, fmt.Errorf("failed to list vulnerability scans: %w", err)
+	}
+
+	return scans, nil
+}
+
+// CreateCVESuppression records an accepted vulnerability risk
+func (r *Repository) CreateCVESuppression(ctx context.Context, suppression *CVESuppression) error {
+	if suppression.ID == uuid.Nil {
+		suppression.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(suppression).Error; err != nil {
+		return fmt.Errorf("failed to create CVE suppression: %w", err)
+	}
+
+	return nil
+}
+
+// ListCVESuppressions retrieves all CVE suppressions, including expired ones, newest first
+func (r *Repository) ListCVESuppressions(ctx context.Context) ([]CVESuppression, error) {
+	var suppressions []CVESuppression
+
+	if err := r.db.WithContext(ctx).
+		Order("created_at DESC").
+		Find(&suppressions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list CVE suppressions: %w", err)
+	}
+
+	return suppressions, nil
+}
+
+// ListActiveCVESuppressions retrieves the unexpired suppressions that apply to a deployment:
+// its own and the global ones
+func (r *Repository) ListActiveCVESuppressions(ctx context.Context, deploymentID uuid.UUID) ([]CVESuppression, error) {
+	var suppressions []CVESuppression
+
+	if err := r.db.WithContext(ctx).
+		Where("deployment_id IS NULL OR deployment_id = ?", deploymentID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Find(&suppressions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list active CVE suppressions: %w", err)
+	}
+
+	return suppressions, nil
+}
+
+// ListExpiringCVESuppressions retrieves the suppressions that expire before the given time
+// and have not expired yet, soonest first
+func (r *Repository) ListExpiringCVESuppressions(ctx context.Context, before time.Time) ([]CVESuppression, error) {
+	var suppressions []CVESuppression
+
+	if err := r.db.WithContext(ctx).
+		Where("expires_at > ? AND expires_at <= ?", time.Now(), before).
+		Order("expires_at ASC").
+		Find(&suppressions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list expiring CVE suppressions: %w", err)
+	}
+
+	return suppressions, nil
+}
+
+// CreateHelmChart records a chart version published to the chart registry
+func (r *Repository) CreateHelmChart(ctx context.Context, chart *HelmChart) error {
+	if chart.ID == uuid.Nil {
+		chart.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(chart).Error; err != nil {
+		return fmt.Errorf("failed to create helm chart: %w", err)
+	}
+
+	return nil
+}
+
+// GetHelmChart retrieves a published chart version, nil when it has not been published to
+// the registry
+func (r *Repository) GetHelmChart(ctx context.Context, registryURL, name, version string) (*HelmChart, error) {
+	var chart HelmChart
+
+	if err := r.db.WithContext(ctx).
+		Where("registry_url = ? AND name = ? AND version = ?", registryURL, name, version).
+		First(&chart).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get helm chart: %w", err)
+	}
+
+	return &chart, nil
+}
+
+// GetLatestHelmChart retrieves the most recently published version of a chart, nil when none
+// has been published to the registry
+func (r *Repository) GetLatestHelmChart(ctx context.Context, registryURL, name string) (*HelmChart, error) {
+	var chart HelmChart
+
+	if err := r.db.WithContext(ctx).
+		Where("registry_url = ? AND name = ?", registryURL, name).
+		Order("created_at DESC").
+		First(&chart).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest helm chart: %w", err)
+	}
+
+	return &chart, nil
+}
+
+// CreateRegistryCredential stores a container registry login
+func (r *Repository) CreateRegistryCredential(ctx context.Context, credential *RegistryCredential) error {
+	if credential.ID == uuid.Nil {
+		credential.ID = uuid.New()
+	}
+
+	if err := r.db.WithContext(ctx).Create(credential).Error; err != nil {
+		return fmt.Errorf("failed to create registry credential: %w", err)
+	}
+
+	return nil
+}
+
+// GetRegistryCredential retrieves a registry credential by ID
+func (r *Repository) GetRegistryCredential(ctx context.Context, id uuid.UUID) (*RegistryCredential, error) {
+	var credential RegistryCredential
+
+	if err := r.db.WithContext(ctx).First(&credential, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("registry credential not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get registry credential: %w", err)
+	}
+
+	return &credential, nil
+}
+
+// GetActiveRegistryCredential retrieves the newest unexpired credential for a registry, nil when
+// it has none
+func (r *Repository) GetActiveRegistryCredential(ctx context.Context, registryURL string) (*RegistryCredential, error) {
+	var credential RegistryCredential
+
+	if err := r.db.WithContext(ctx).
+		Where("registry_url = ?", registryURL).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		First(&credential).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get registry credential: %w", err)
+	}
+
+	return &credential, nil
+}
+
+// UpdateRegistryCredential saves a registry credential, e.g. after its secret was rotated
+func (r *Repository) UpdateRegistryCredential(ctx context.Context, credential *RegistryCredential) error {
+	if err := r.db.WithContext(ctx).Save(credential).Error; err != nil {
+		return fmt.Errorf("failed to update registry credential: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRegistryCredential deletes a registry credential
+func (r *Repository) DeleteRegistryCredential(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&RegistryCredential{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete registry credential: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("registry credential not found: %s", id)
+	}
+
 	return nil
 }
 
+// ListExpiringRegistryCredentials retrieves the registry credentials that expire before the
+// given time and have not expired yet, soonest first
+func (r *Repository) ListExpiringRegistryCredentials(ctx context.Context, before time.Time) ([]RegistryCredential, error) {
+	var credentials []RegistryCredential
+
+	if err := r.db.WithContext(ctx).
+		Where("expires_at > ? AND expires_at <= ?", time.Now(), before).
+		Order("expires_at ASC").
+		Find(&credentials).Error; err != nil {
+		return nil, fmt.Errorf("failed to list expiring registry credentials: %w", err)
+	}
+
+	return credentials, nil
+}
+
 // GetRecentDeployments retrieves the most recent N deployments
 func (r *Repository) GetRecentDeployments(ctx context.Context, limit int) ([]Deployment, error) {
 	var deployments []Deployment
 
-	if err := r.db.WithContext(ctx).
+	if err := r.readDB.WithContext(ctx).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&deployments).Error; err != nil {
@@ -355,7 +2027,9 @@ func (r *Repository) GetRecentDeployments(ctx context.Context, limit int) ([]Dep
 	return deployments, nil
 }
 
-// GetDeploymentByID is an alias for GetDeployment (for API consistency)
+// GetDeploymentByID retrieves a deployment by ID from the primary, bypassing the cache. The
+// worker reads through it since its jobs act on infrastructure and builds it has just written,
+// which the cached copy may predate.
 func (r *Repository) GetDeploymentByID(ctx context.Context, id uuid.UUID) (*Deployment, error) {
-	return r.GetDeployment(ctx, id)
+	return r.GetDeploymentConsistent(ctx, id)
 }
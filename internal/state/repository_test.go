@@ -18,7 +18,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err, "failed to create test database")
 
 	// Run migrations
-	err = db.AutoMigrate(&Deployment{}, &Infrastructure{}, &Build{})
+	err = db.AutoMigrate(&Deployment{}, &Infrastructure{}, &Build{}, &DeploymentLog{}, &FederatedDeployment{}, &DeploymentDependency{}, &DeploymentEnvVar{}, &DeploymentConfigMap{}, &AuditLog{}, &DeploymentEvent{}, &ResourcePolicy{}, &DeploymentApproval{}, &GitHook{}, &VulnerabilityScan{}, &CVESuppression{}, &Pipeline{}, &ServiceRoute{}, &ABTest{}, &HelmChart{}, &RegistryCredential{}, &DeploymentOwnershipHistory{})
 	require.NoError(t, err, "failed to run migrations")
 
 	return db
@@ -27,7 +27,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 func TestCreateDeployment(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	deployment := &Deployment{
@@ -47,7 +47,7 @@ func TestCreateDeployment(t *testing.T) {
 func TestGetDeployment(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create deployment
@@ -75,7 +75,7 @@ func TestGetDeployment(t *testing.T) {
 func TestGetDeploymentNotFound(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	_, err := repo.GetDeployment(ctx, uuid.New())
@@ -86,7 +86,7 @@ func TestGetDeploymentNotFound(t *testing.T) {
 func TestListDeployments(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create multiple deployments
@@ -109,10 +109,43 @@ func TestListDeployments(t *testing.T) {
 	assert.Len(t, deployments, 5)
 }
 
+func TestListDeploymentsFiltered(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	// Create deployments across regions and statuses
+	for _, tc := range []struct{ status, region string }{
+		{"EXPOSED", "us-central1"},
+		{"EXPOSED", "europe-west1"},
+		{"FAILED", "us-central1"},
+	} {
+		deployment := &Deployment{
+			Name:    "test-deployment",
+			AppName: "test-app",
+			Version: "v1.0.0",
+			Status:  tc.status,
+			Cloud:   "gcp",
+			Region:  tc.region,
+		}
+		err := repo.CreateDeployment(ctx, deployment)
+		require.NoError(t, err)
+	}
+
+	deployments, err := repo.ListDeploymentsFiltered(ctx, DeploymentFilter{Status: "EXPOSED"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 2)
+
+	deployments, err = repo.ListDeploymentsFiltered(ctx, DeploymentFilter{Status: "EXPOSED", Region: "us-central1"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+}
+
 func TestUpdateDeploymentStatus(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create deployment
@@ -137,10 +170,389 @@ func TestUpdateDeploymentStatus(t *testing.T) {
 	assert.Equal(t, "BUILDING", updated.Status)
 }
 
+func TestSetDeploymentPaused(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{
+		Name:    "test-deployment",
+		AppName: "test-app",
+		Version: "v1.0.0",
+		Status:  "QUEUED",
+		Cloud:   "gcp",
+		Region:  "us-central1",
+	}
+	err := repo.CreateDeployment(ctx, deployment)
+	require.NoError(t, err)
+
+	// Pause
+	err = repo.SetDeploymentPaused(ctx, deployment.ID, true)
+	assert.NoError(t, err)
+
+	paused, err := repo.GetDeployment(ctx, deployment.ID)
+	assert.NoError(t, err)
+	assert.True(t, paused.Paused)
+	assert.NotNil(t, paused.PausedAt)
+
+	// Resume
+	err = repo.SetDeploymentPaused(ctx, deployment.ID, false)
+	assert.NoError(t, err)
+
+	resumed, err := repo.GetDeployment(ctx, deployment.ID)
+	assert.NoError(t, err)
+	assert.False(t, resumed.Paused)
+	assert.Nil(t, resumed.PausedAt)
+}
+
+func TestDeploymentBudget(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	budgeted := &Deployment{Name: "budgeted", AppName: "app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	unbudgeted := &Deployment{Name: "unbudgeted", AppName: "app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, budgeted))
+	require.NoError(t, repo.CreateDeployment(ctx, unbudgeted))
+
+	require.NoError(t, repo.SetDeploymentBudget(ctx, budgeted.ID, 500))
+
+	deployments, err := repo.ListBudgetedDeployments(ctx)
+	require.NoError(t, err)
+	require.Len(t, deployments, 1)
+	assert.Equal(t, budgeted.ID, deployments[0].ID)
+	assert.Equal(t, 500.0, deployments[0].MonthlyBudgetUSD)
+
+	now := time.Now()
+	require.NoError(t, repo.RecordBudgetAlert(ctx, budgeted.ID, 80, &now))
+
+	alerted, err := repo.GetDeployment(ctx, budgeted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 80, alerted.BudgetAlertPercent)
+	assert.NotNil(t, alerted.BudgetAlertSentAt)
+
+	// A new budget re-arms alerts
+	require.NoError(t, repo.SetDeploymentBudget(ctx, budgeted.ID, 1000))
+
+	rearmed, err := repo.GetDeployment(ctx, budgeted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, rearmed.MonthlyBudgetUSD)
+	assert.Equal(t, 0, rearmed.BudgetAlertPercent)
+	assert.Nil(t, rearmed.BudgetAlertSentAt)
+}
+
+func TestUpdateVPARecommendation(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "vpa", AppName: "app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	recommendation := &VPARecommendation{
+		Container:     "base-app",
+		CPURequest:    "250m",
+		MemoryRequest: "256Mi",
+		CPULimit:      "1000m",
+		RecordedAt:    time.Now(),
+	}
+	require.NoError(t, repo.UpdateVPARecommendation(ctx, deployment.ID, recommendation))
+
+	updated, err := repo.GetDeployment(ctx, deployment.ID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.VPALastRecommendation)
+	assert.Equal(t, "250m", updated.VPALastRecommendation.CPURequest)
+	assert.Equal(t, "256Mi", updated.VPALastRecommendation.MemoryRequest)
+	assert.Equal(t, "HEALTHY", updated.Status)
+}
+
+func TestListAppVPARecommendations(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	recommended := &Deployment{Name: "staging", AppName: "app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	unrecommended := &Deployment{Name: "dev", AppName: "app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	other := &Deployment{Name: "other", AppName: "other-app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	for _, d := range []*Deployment{recommended, unrecommended, other} {
+		require.NoError(t, repo.CreateDeployment(ctx, d))
+	}
+
+	for _, d := range []*Deployment{recommended, other} {
+		require.NoError(t, repo.UpdateVPARecommendation(ctx, d.ID, &VPARecommendation{
+			Container:     "base-app",
+			CPURequest:    "300m",
+			MemoryRequest: "200Mi",
+			RecordedAt:    time.Now(),
+		}))
+	}
+
+	recommendations, err := repo.ListAppVPARecommendations(ctx, "app")
+	require.NoError(t, err)
+	require.Len(t, recommendations, 1)
+	assert.Equal(t, "300m", recommendations[0].CPURequest)
+	assert.Equal(t, "200Mi", recommendations[0].MemoryRequest)
+}
+
+func TestGetDueScheduledDeployments(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	due := &Deployment{Name: "due", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "us-central1", ScheduledAt: &past}
+	later := &Deployment{Name: "later", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "us-central1", ScheduledAt: &future}
+	unscheduled := &Deployment{Name: "unscheduled", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "us-central1"}
+	for _, d := range []*Deployment{due, later, unscheduled} {
+		require.NoError(t, repo.CreateDeployment(ctx, d))
+	}
+
+	deployments, err := repo.GetDueScheduledDeployments(ctx, time.Now())
+	assert.NoError(t, err)
+	require.Len(t, deployments, 1)
+	assert.Equal(t, due.ID, deployments[0].ID)
+
+	// Scheduled filter lists every pending scheduled deployment
+	scheduled, err := repo.ListDeploymentsFiltered(ctx, DeploymentFilter{Scheduled: true}, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, scheduled, 2)
+}
+
+func TestGetBlockingDependencies(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	live := &Deployment{Name: "db", AppName: "db", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	building := &Deployment{Name: "cache", AppName: "cache", Version: "v1", Status: "PROVISIONING", Cloud: "gcp", Region: "us-central1"}
+	app := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "us-central1"}
+	for _, d := range []*Deployment{live, building, app} {
+		require.NoError(t, repo.CreateDeployment(ctx, d))
+	}
+
+	require.NoError(t, repo.CreateDeploymentDependencies(ctx, app.ID, []uuid.UUID{live.ID, building.ID}))
+
+	ids, err := repo.ListDeploymentDependencies(ctx, app.ID)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 2)
+
+	blocking, err := repo.GetBlockingDependencies(ctx, app.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{building.ID}, blocking)
+}
+
+func TestServiceRoutes(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	frontend := &Deployment{Name: "frontend", AppName: "frontend", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	api := &Deployment{Name: "api", AppName: "api", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	search := &Deployment{Name: "search", AppName: "search", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	for _, d := range []*Deployment{frontend, api, search} {
+		require.NoError(t, repo.CreateDeployment(ctx, d))
+	}
+
+	require.NoError(t, repo.CreateServiceRoute(ctx, &ServiceRoute{SourceDeploymentID: frontend.ID, TargetDeploymentID: api.ID, Prefix: "/api", WeightPercent: 100}))
+	require.NoError(t, repo.CreateServiceRoute(ctx, &ServiceRoute{SourceDeploymentID: frontend.ID, TargetDeploymentID: search.ID, Prefix: "/search", WeightPercent: 50}))
+
+	routes, err := repo.ListServiceRoutes(ctx, frontend.ID)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 2)
+
+	// Destroying a target removes only the routes to it
+	deleted, err := repo.DeleteServiceRoutesForDeployment(ctx, api.ID)
+	assert.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, frontend.ID, deleted[0].SourceDeploymentID)
+
+	routes, err = repo.ListServiceRoutes(ctx, frontend.ID)
+	assert.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.Equal(t, search.ID, routes[0].TargetDeploymentID)
+}
+
+func TestDeploymentMirrors(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	live := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, live))
+
+	mirror, err := repo.GetMirroringDeployment(ctx, live.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, mirror)
+
+	shadow := &Deployment{Name: "app-v2", AppName: "app", Version: "v2", Status: "PENDING", Cloud: "gcp", Region: "us-central1", MirrorFrom: &live.ID}
+	require.NoError(t, repo.CreateDeployment(ctx, shadow))
+
+	mirror, err = repo.GetMirroringDeployment(ctx, live.ID)
+	assert.NoError(t, err)
+	require.NotNil(t, mirror)
+	assert.Equal(t, shadow.ID, mirror.ID)
+
+	require.NoError(t, repo.ClearDeploymentMirrors(ctx, live.ID))
+
+	updated, err := repo.GetDeployment(ctx, shadow.ID)
+	require.NoError(t, err)
+	assert.Nil(t, updated.MirrorFrom)
+}
+
+func TestABTests(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	baseline := &Deployment{Name: "checkout", AppName: "checkout", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	variant := &Deployment{Name: "checkout-b", AppName: "checkout", Version: "v2", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	for _, d := range []*Deployment{baseline, variant} {
+		require.NoError(t, repo.CreateDeployment(ctx, d))
+	}
+
+	test := &ABTest{
+		BaselineDeploymentID: baseline.ID,
+		VariantDeploymentID:  variant.ID,
+		HeaderName:           "x-variant",
+		HeaderValue:          "b",
+		RoutingWeight:        100,
+		Status:               ABTestStatusRunning,
+	}
+	require.NoError(t, repo.CreateABTest(ctx, test))
+
+	routing, err := repo.GetRoutingABTest(ctx, baseline.ID)
+	assert.NoError(t, err)
+	require.NotNil(t, routing)
+	assert.Equal(t, test.ID, routing.ID)
+
+	latest, err := repo.GetVariantABTest(ctx, variant.ID)
+	assert.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, test.ID, latest.ID)
+
+	// Destroying either deployment cancels a running test
+	ended, err := repo.EndABTests(ctx, variant.ID)
+	assert.NoError(t, err)
+	require.Len(t, ended, 1)
+	assert.Equal(t, ABTestStatusCancelled, ended[0].Status)
+	assert.NotNil(t, ended[0].ConcludedAt)
+
+	routing, err = repo.GetRoutingABTest(ctx, baseline.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, routing)
+}
+
+func TestDeploymentEnvVars(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	require.NoError(t, repo.SetDeploymentEnvVar(ctx, &DeploymentEnvVar{DeploymentID: deployment.ID, Key: "LOG_LEVEL", Value: "info"}))
+	require.NoError(t, repo.SetDeploymentEnvVar(ctx, &DeploymentEnvVar{DeploymentID: deployment.ID, Key: "LOG_LEVEL", Value: "debug"}))
+
+	envVars, err := repo.ListDeploymentEnvVars(ctx, deployment.ID)
+	assert.NoError(t, err)
+	require.Len(t, envVars, 1)
+	assert.Equal(t, "debug", envVars[0].Value)
+
+	assert.NoError(t, repo.DeleteDeploymentEnvVar(ctx, deployment.ID, "LOG_LEVEL"))
+	assert.Error(t, repo.DeleteDeploymentEnvVar(ctx, deployment.ID, "LOG_LEVEL"))
+}
+
+func TestDeploymentConfigMaps(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	configMap := &DeploymentConfigMap{
+		DeploymentID: deployment.ID,
+		Name:         "nginx",
+		Data:         map[string]string{"nginx.conf": "server { listen 8080; }"},
+	}
+	require.NoError(t, repo.SetDeploymentConfigMap(ctx, configMap))
+
+	configMaps, err := repo.ListDeploymentConfigMaps(ctx, deployment.ID)
+	assert.NoError(t, err)
+	require.Len(t, configMaps, 1)
+	assert.Equal(t, configMap.Data, configMaps[0].Data)
+
+	assert.NoError(t, repo.DeleteDeploymentConfigMap(ctx, deployment.ID, "nginx"))
+	assert.Error(t, repo.DeleteDeploymentConfigMap(ctx, deployment.ID, "nginx"))
+}
+
+func TestCloneDeployment(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	source := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, source))
+	require.NoError(t, repo.SetDeploymentEnvVar(ctx, &DeploymentEnvVar{DeploymentID: source.ID, Key: "LOG_LEVEL", Value: "info"}))
+	require.NoError(t, repo.SetDeploymentConfigMap(ctx, &DeploymentConfigMap{DeploymentID: source.ID, Name: "nginx", Data: map[string]string{"nginx.conf": "server {}"}}))
+
+	clone := &Deployment{Name: "app-clone", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "europe-west1"}
+	require.NoError(t, repo.CloneDeployment(ctx, clone, source.ID))
+	require.NotNil(t, clone.ClonedFromID)
+	assert.Equal(t, source.ID, *clone.ClonedFromID)
+
+	envVars, err := repo.ListDeploymentEnvVars(ctx, clone.ID)
+	assert.NoError(t, err)
+	require.Len(t, envVars, 1)
+	assert.Equal(t, "info", envVars[0].Value)
+
+	configMaps, err := repo.ListDeploymentConfigMaps(ctx, clone.ID)
+	assert.NoError(t, err)
+	assert.Len(t, configMaps, 1)
+
+	clones, err := repo.ListDeploymentsFiltered(ctx, DeploymentFilter{ClonedFrom: &source.ID}, 10, 0)
+	assert.NoError(t, err)
+	require.Len(t, clones, 1)
+	assert.Equal(t, clone.ID, clones[0].ID)
+}
+
+func TestMergeDeploymentTags(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "PENDING", Cloud: "gcp", Region: "us-central1",
+		Tags: map[string]string{"env": "staging", "team": "backend"}}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	tags, err := repo.MergeDeploymentTags(ctx, deployment.ID, map[string]string{"env": "production", "tier": "gold"}, []string{"team"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "production", "tier": "gold"}, tags)
+
+	retrieved, err := repo.GetDeployment(ctx, deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tags, retrieved.Tags)
+}
+
 func TestCreateInfrastructure(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create deployment first
@@ -170,10 +582,223 @@ func TestCreateInfrastructure(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, infra.ID)
 }
 
+func TestListInfrastructureByStackNames(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "FAILED", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	infra := &Infrastructure{DeploymentID: deployment.ID, PulumiStackName: "deployer-" + deployment.ID.String(), Status: "FAILED",
+		Config: `{"type":"kubernetes"}`}
+	require.NoError(t, repo.CreateInfrastructure(ctx, infra))
+
+	infrastructures, err := repo.ListInfrastructureByStackNames(ctx, []string{infra.PulumiStackName, "deployer-unknown"})
+	require.NoError(t, err)
+	require.Len(t, infrastructures, 1)
+	assert.Equal(t, infra.ID, infrastructures[0].ID)
+
+	infrastructures, err = repo.ListInfrastructureByStackNames(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, infrastructures)
+}
+
+func TestUpdateInfrastructureEgress(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "egress", AppName: "app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	infra := &Infrastructure{DeploymentID: deployment.ID, ClusterName: "egress-cluster", Status: "READY", Config: `{"type":"kubernetes"}`}
+	require.NoError(t, repo.CreateInfrastructure(ctx, infra))
+
+	checkedAt := time.Now().UTC()
+	require.NoError(t, repo.UpdateInfrastructureEgress(ctx, infra.ID, 12.5, checkedAt))
+
+	updated, err := repo.GetInfrastructureByID(ctx, infra.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, updated.LastEgressGB)
+	require.NotNil(t, updated.LastEgressCheckAt)
+	assert.WithinDuration(t, checkedAt, *updated.LastEgressCheckAt, time.Second)
+	assert.Equal(t, "READY", updated.Status)
+}
+
+func TestUpdateInfrastructureAddonVersions(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "mesh", AppName: "app", Version: "v1", Status: "HEALTHY", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	infra := &Infrastructure{DeploymentID: deployment.ID, ClusterName: "mesh-cluster", Status: "READY", Config: `{"type":"kubernetes"}`}
+	require.NoError(t, repo.CreateInfrastructure(ctx, infra))
+
+	require.NoError(t, repo.UpdateInfrastructureAddonVersions(ctx, infra.ID, map[string]string{"istio": "1.24.2"}))
+
+	updated, err := repo.GetInfrastructureByID(ctx, infra.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"istio": "1.24.2"}, updated.AddonVersions)
+	assert.Equal(t, "READY", updated.Status)
+}
+
+func TestUpdateInfrastructureRateLimitRule(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "throttled", AppName: "app", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1",
+		CloudArmorEnabled: true, CloudArmorPolicy: "default", RateLimitRequestsPerMinute: 600, RateLimitPaths: []string{"/api"}}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	infra := &Infrastructure{DeploymentID: deployment.ID, ClusterName: "throttled-cluster", Status: "READY", Config: `{"type":"kubernetes"}`}
+	require.NoError(t, repo.CreateInfrastructure(ctx, infra))
+
+	require.NoError(t, repo.UpdateInfrastructureRateLimitRule(ctx, infra.ID, "deployer-ratelimit-"+deployment.ID.String()))
+
+	updated, err := repo.GetInfrastructureByID(ctx, infra.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "deployer-ratelimit-"+deployment.ID.String(), updated.RateLimitRuleName)
+
+	retrieved, err := repo.GetDeployment(ctx, deployment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/api"}, retrieved.RateLimitPaths)
+}
+
+func TestDecideDeploymentApproval(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1",
+		RequiresApproval: true, Approvers: []string{"key:approver"}}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	approval := &DeploymentApproval{DeploymentID: deployment.ID, RequestedBy: "key:requester", Approvers: deployment.Approvers,
+		Status: ApprovalStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.CreateDeploymentApproval(ctx, approval))
+
+	decided, err := repo.DecideDeploymentApproval(ctx, approval, ApprovalStatusApproved, "key:approver")
+	require.NoError(t, err)
+	assert.True(t, decided)
+
+	// A second decision loses to the first
+	decided, err = repo.DecideDeploymentApproval(ctx, approval, ApprovalStatusRejected, "key:approver")
+	require.NoError(t, err)
+	assert.False(t, decided)
+
+	retrieved, err := repo.GetDeploymentApproval(ctx, approval.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalStatusApproved, retrieved.Status)
+	assert.Equal(t, []string{"key:approver"}, retrieved.Approvers)
+}
+
+func TestListGitHooksByRepo(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	hook := &GitHook{DeploymentID: deployment.ID, Provider: GitProviderGitLab, RepoURL: "git@gitlab.com:group/app.git",
+		Branch: "main", Secret: "encrypted"}
+	require.NoError(t, repo.CreateGitHook(ctx, hook))
+
+	hooks, err := repo.ListGitHooksByRepo(ctx, GitProviderGitLab, []string{"git@gitlab.com:group/app.git", "https://gitlab.com/group/app.git"})
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	assert.Equal(t, hook.ID, hooks[0].ID)
+
+	// Hooks are scoped to their provider
+	hooks, err = repo.ListGitHooksByRepo(ctx, GitProviderGitHub, []string{"git@gitlab.com:group/app.git"})
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	require.NoError(t, repo.MarkGitHookTriggered(ctx, hook.ID, "da15608"))
+	hooks, err = repo.ListGitHooksByRepo(ctx, GitProviderGitLab, []string{"git@gitlab.com:group/app.git"})
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	assert.Equal(t, "da15608", hooks[0].LastCommitSHA)
+	assert.NotNil(t, hooks[0].LastTriggeredAt)
+}
+
+func TestListUnresolvedVulnerabilityScans(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	first := &VulnerabilityScan{BuildID: uuid.New(), DeploymentID: deployment.ID, ScanTime: time.Now().Add(-time.Hour), CriticalCount: 2}
+	require.NoError(t, repo.CreateVulnerabilityScan(ctx, first))
+
+	scans, err := repo.ListUnresolvedVulnerabilityScans(ctx, "CRITICAL")
+	require.NoError(t, err)
+	require.Len(t, scans, 1)
+	assert.Equal(t, first.ID, scans[0].ID)
+
+	// A later build without critical vulnerabilities resolves them
+	second := &VulnerabilityScan{BuildID: uuid.New(), DeploymentID: deployment.ID, ScanTime: time.Now(), HighCount: 1, Passed: true}
+	require.NoError(t, repo.CreateVulnerabilityScan(ctx, second))
+
+	scans, err = repo.ListUnresolvedVulnerabilityScans(ctx, "CRITICAL")
+	require.NoError(t, err)
+	assert.Empty(t, scans)
+
+	scan, err := repo.GetVulnerabilityScanByBuild(ctx, second.BuildID)
+	require.NoError(t, err)
+	require.NotNil(t, scan)
+	assert.Equal(t, 1, scan.HighCount)
+}
+
+func TestListActiveCVESuppressions(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deployment := &Deployment{Name: "app", AppName: "app", Version: "v1", Status: "EXPOSED", Cloud: "gcp", Region: "us-central1"}
+	require.NoError(t, repo.CreateDeployment(ctx, deployment))
+
+	other := uuid.New()
+	expired := time.Now().Add(-time.Hour)
+	expiring := time.Now().Add(48 * time.Hour)
+
+	require.NoError(t, repo.CreateCVESuppression(ctx, &CVESuppression{CVEID: "CVE-2024-0001", Reason: "global"}))
+	require.NoError(t, repo.CreateCVESuppression(ctx, &CVESuppression{CVEID: "CVE-2024-0002", Reason: "own", DeploymentID: &deployment.ID, ExpiresAt: &expiring}))
+	require.NoError(t, repo.CreateCVESuppression(ctx, &CVESuppression{CVEID: "CVE-2024-0003", Reason: "other", DeploymentID: &other}))
+	require.NoError(t, repo.CreateCVESuppression(ctx, &CVESuppression{CVEID: "CVE-2024-0004", Reason: "expired", ExpiresAt: &expired}))
+
+	suppressions, err := repo.ListActiveCVESuppressions(ctx, deployment.ID)
+	require.NoError(t, err)
+	var ids []string
+	for _, s := range suppressions {
+		ids = append(ids, s.CVEID)
+	}
+	assert.ElementsMatch(t, []string{"CVE-2024-0001", "CVE-2024-0002"}, ids)
+
+	suppressions, err = repo.ListExpiringCVESuppressions(ctx, time.Now().Add(7*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, suppressions, 1)
+	assert.Equal(t, "CVE-2024-0002", suppressions[0].CVEID)
+}
+
 func TestCreateBuild(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create deployment first
@@ -202,10 +827,179 @@ func TestCreateBuild(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, build.ID)
 }
 
+func TestListDeploymentLogs(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deploymentID := uuid.New()
+	entries := []*DeploymentLog{
+		{DeploymentID: deploymentID, Phase: "BUILDING", Level: "INFO", Message: "Image built"},
+		{DeploymentID: deploymentID, Phase: "DEPLOYING", Level: "ERROR", Source: "helm-lint", Message: "templates/: parse error"},
+	}
+	for _, entry := range entries {
+		require.NoError(t, repo.CreateDeploymentLog(ctx, entry))
+		assert.NotEqual(t, uuid.Nil, entry.ID)
+	}
+
+	logs, err := repo.ListDeploymentLogs(ctx, deploymentID, "", "")
+	require.NoError(t, err)
+	assert.Len(t, logs, 2)
+
+	logs, err = repo.ListDeploymentLogs(ctx, deploymentID, "DEPLOYING", "")
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "helm-lint", logs[0].Source)
+}
+
+func TestCleanupOldLogs(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deploymentID := uuid.New()
+	entries := []*DeploymentLog{
+		{DeploymentID: deploymentID, Phase: "BUILDING", Level: "INFO", Message: "Image built", CreatedAt: time.Now().AddDate(0, 0, -45)},
+		{DeploymentID: deploymentID, Phase: "DEPLOYING", Level: "INFO", Message: "Release installed", CreatedAt: time.Now()},
+	}
+	for _, entry := range entries {
+		require.NoError(t, repo.CreateDeploymentLog(ctx, entry))
+	}
+
+	// Keeping logs forever deletes nothing
+	deleted, err := repo.CleanupOldLogs(ctx, -1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	deleted, err = repo.CleanupOldLogs(ctx, 30)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	logs, err := repo.ListDeploymentLogs(ctx, deploymentID, "", "")
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "Release installed", logs[0].Message)
+}
+
+func TestPipeline(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deploymentID := uuid.New()
+	first := &Pipeline{
+		DeploymentID: deploymentID,
+		Stages: []PipelineStage{
+			{Type: StageTypeProvision, Status: StageStatusQueued, JobID: "job-1"},
+			{Type: StageTypeDeploy, Status: StageStatusPending},
+		},
+	}
+	require.NoError(t, repo.StartPipeline(ctx, first))
+
+	first.Stages[0].Status = StageStatusCompleted
+	first.CurrentStageIndex = 1
+	require.NoError(t, repo.UpdatePipeline(ctx, first))
+
+	retrieved, err := repo.GetPipeline(ctx, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, retrieved.CurrentStageIndex)
+	assert.Equal(t, StageStatusCompleted, retrieved.Stages[0].Status)
+
+	// A new run replaces the previous pipeline
+	second := &Pipeline{
+		DeploymentID: deploymentID,
+		Stages:       []PipelineStage{{Type: StageTypeDeploy, Status: StageStatusQueued, JobID: "job-2"}},
+	}
+	require.NoError(t, repo.StartPipeline(ctx, second))
+
+	retrieved, err = repo.GetPipeline(ctx, deploymentID)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, retrieved.ID)
+	assert.Len(t, retrieved.Stages, 1)
+}
+
+func TestDeploymentEvents(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	deploymentID := uuid.New()
+	require.NoError(t, repo.CreateDeploymentEvent(ctx, &DeploymentEvent{
+		DeploymentID: deploymentID,
+		Type:         EventTypeStatusChange,
+		Message:      "Deployment is EXPOSED",
+		Metadata:     map[string]interface{}{"status": "EXPOSED"},
+	}))
+
+	// Warnings and errors logged for the deployment join its event feed
+	require.NoError(t, repo.CreateDeploymentLog(ctx, &DeploymentLog{
+		DeploymentID: deploymentID, Phase: "DEPLOYING", Level: "INFO", Message: "Hook passed",
+	}))
+	require.NoError(t, repo.CreateDeploymentLog(ctx, &DeploymentLog{
+		DeploymentID: deploymentID, Phase: "DEPLOYING", Level: "ERROR", Source: "smoke-test", Message: "GET /healthz failed",
+	}))
+
+	events, err := repo.ListDeploymentEvents(ctx, deploymentID, time.Time{}, 100, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventTypeStatusChange, events[0].Type)
+	assert.Equal(t, EventTypeLog, events[1].Type)
+	assert.Equal(t, "smoke-test", events[1].Metadata["source"])
+
+	events, err = repo.ListDeploymentEvents(ctx, deploymentID, events[0].CreatedAt, 100, 0)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	latest, err := repo.GetLatestDeploymentEvent(ctx, deploymentID, EventTypeLog)
+	require.NoError(t, err)
+	assert.Equal(t, "GET /healthz failed", latest.Message)
+}
+
+func TestFederatedDeployment(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	var memberIDs []uuid.UUID
+	for _, region := range []string{"us-central1", "europe-west1"} {
+		deployment := &Deployment{
+			Name:    "test-app-" + region,
+			AppName: "test-app",
+			Version: "v1.0.0",
+			Status:  "PENDING",
+			Cloud:   "gcp",
+			Region:  region,
+		}
+		require.NoError(t, repo.CreateDeployment(ctx, deployment))
+		memberIDs = append(memberIDs, deployment.ID)
+	}
+
+	federated := &FederatedDeployment{
+		Name:          "test-app",
+		AppName:       "test-app",
+		Version:       "v1.0.0",
+		DeploymentIDs: memberIDs,
+	}
+	require.NoError(t, repo.CreateFederatedDeployment(ctx, federated))
+
+	retrieved, err := repo.GetFederatedDeployment(ctx, federated.ID)
+	require.NoError(t, err)
+	assert.Equal(t, memberIDs, retrieved.DeploymentIDs)
+
+	members, err := repo.GetDeploymentsByIDs(ctx, retrieved.DeploymentIDs)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+}
+
 func TestMarkDeploymentAsDeployed(t *testing.T) {
 	t.Skip("Skipping test - requires CGO for SQLite")
 	db := setupTestDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, nil, nil)
 	ctx := context.Background()
 
 	// Create deployment
@@ -232,3 +1026,122 @@ func TestMarkDeploymentAsDeployed(t *testing.T) {
 	assert.Equal(t, "http://example.com", updated.ExternalURL)
 	assert.NotNil(t, updated.DeployedAt)
 }
+
+func TestGetLatestHelmChart(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	registry := "oci://us-central1-docker.pkg.dev/project/helm-charts"
+
+	latest, err := repo.GetLatestHelmChart(ctx, registry, "base-app")
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	for i, version := range []string{"1.0.0", "1.1.0"} {
+		chart := &HelmChart{
+			Name:        "base-app",
+			Version:     version,
+			RegistryURL: registry,
+			CreatedAt:   time.Now().Add(time.Duration(i) * time.Minute),
+		}
+		require.NoError(t, repo.CreateHelmChart(ctx, chart))
+	}
+
+	latest, err = repo.GetLatestHelmChart(ctx, registry, "base-app")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, "1.1.0", latest.Version)
+
+	published, err := repo.GetHelmChart(ctx, registry, "base-app", "1.0.0")
+	require.NoError(t, err)
+	assert.NotNil(t, published)
+
+	missing, err := repo.GetHelmChart(ctx, "oci://example.com/charts", "base-app", "1.0.0")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	expired := time.Now().Add(-time.Hour)
+	expiring := time.Now().Add(12 * time.Hour)
+
+	old := &RegistryCredential{
+		RegistryURL: "registry.example.com",
+		Username:    "ci",
+		Secret:      "encrypted-old",
+		ExpiresAt:   &expired,
+	}
+	require.NoError(t, repo.CreateRegistryCredential(ctx, old))
+
+	current := &RegistryCredential{
+		RegistryURL: "registry.example.com",
+		Username:    "ci",
+		Secret:      "encrypted-current",
+		ExpiresAt:   &expiring,
+	}
+	require.NoError(t, repo.CreateRegistryCredential(ctx, current))
+
+	active, err := repo.GetActiveRegistryCredential(ctx, "registry.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, current.ID, active.ID)
+
+	soon, err := repo.ListExpiringRegistryCredentials(ctx, time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, soon, 1)
+	assert.Equal(t, current.ID, soon[0].ID)
+
+	require.NoError(t, repo.DeleteRegistryCredential(ctx, current.ID))
+	assert.Error(t, repo.DeleteRegistryCredential(ctx, current.ID))
+
+	active, err = repo.GetActiveRegistryCredential(ctx, "registry.example.com")
+	require.NoError(t, err)
+	assert.Nil(t, active)
+}
+
+func TestTransferDeploymentOwnership(t *testing.T) {
+	t.Skip("Skipping test - requires CGO for SQLite")
+	db := setupTestDB(t)
+	repo := NewRepository(db, nil, nil)
+	ctx := context.Background()
+
+	owned := make([]*Deployment, 2)
+	for i, name := range []string{"alice-api", "alice-worker"} {
+		owned[i] = &Deployment{
+			Name:    name,
+			AppName: "app",
+			Version: "v1.0.0",
+			Status:  "PENDING",
+			Cloud:   "gcp",
+			Region:  "us-central1",
+			UserID:  "key:alice123",
+		}
+		require.NoError(t, repo.CreateDeployment(ctx, owned[i]))
+	}
+
+	entry, err := repo.TransferDeploymentOwnership(ctx, owned[0], "key:bob45678", "key:alice123")
+	require.NoError(t, err)
+	assert.Equal(t, "key:alice123", entry.PreviousOwner)
+	assert.Equal(t, "key:bob45678", owned[0].UserID)
+
+	ids, err := repo.TransferAllDeployments(ctx, "key:alice123", "key:carol789", "key:admin000")
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{owned[1].ID}, ids)
+
+	carols, err := repo.ListDeploymentsFiltered(ctx, DeploymentFilter{UserID: "key:carol789"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, carols, 1)
+	assert.Equal(t, owned[1].ID, carols[0].ID)
+
+	history, err := repo.ListDeploymentOwnershipHistory(ctx, owned[0].ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "key:bob45678", history[0].NewOwner)
+}
@@ -10,22 +10,45 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server      ServerConfig
-	Database    DatabaseConfig
-	Redis       RedisConfig
-	Platform    PlatformConfig
-	Registry    RegistryConfig
-	Provisioner ProvisionerConfig
-	Deployer    DeployerConfig
-	Worker      WorkerConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Redis         RedisConfig
+	Queue         QueueConfig
+	Platform      PlatformConfig
+	Registry      RegistryConfig
+	Builder       BuilderConfig
+	Provisioner   ProvisionerConfig
+	Deployer      DeployerConfig
+	Worker        WorkerConfig
+	Security      SecurityConfig
+	Secrets       SecretsConfig
+	Billing       BillingConfig
+	Approval      ApprovalConfig
+	Notifications NotificationsConfig
+	LogRetention  LogRetentionConfig
+	Policy        PolicyConfig
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	LogLevel     string
+	Port           string
+	GRPCPort       string
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	LogLevel       string
+	RateLimits     RateLimitConfig
+	TrustedProxies []string // CIDRs of reverse proxies whose X-Forwarded-For and X-Real-IP headers are honored
+	ExecEnabled    bool     // Allow interactive exec into deployment pods
+	ExecOrigins    []string // Browser origins allowed to open exec sessions; clients that send no Origin are not affected
+	AdminToken     string   // Bearer token for /api/v1/admin; admin endpoints are disabled when empty
+}
+
+// RateLimitConfig holds per-client API request limits, counted per minute
+type RateLimitConfig struct {
+	Enabled           bool
+	ReadPerMinute     int // GET requests
+	MutationPerMinute int // POST, PUT, PATCH and DELETE requests
+	AdminPerMinute    int // Requests bearing the admin token, of any method
 }
 
 // DatabaseConfig holds PostgreSQL configuration
@@ -39,6 +62,9 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	ReplicaHost     string // Read replica the API server reads from; empty reads from the primary
+	ReplicaPort     int
+	MigrationsPath  string // Directory of the numbered SQL migrations applied at startup
 }
 
 // RedisConfig holds Redis configuration
@@ -48,6 +74,13 @@ type RedisConfig struct {
 	DB       int
 }
 
+// QueueConfig holds job queue configuration
+type QueueConfig struct {
+	Backend           string // redis or pubsub
+	PubSubProject     string // Project the Pub/Sub topics live in; empty uses provisioner.gcp_project
+	PubSubTopicPrefix string // Topics are named <prefix>-<job type>
+}
+
 // PlatformConfig holds platform-wide settings
 type PlatformConfig struct {
 	DefaultCloud  string
@@ -62,13 +95,33 @@ type RegistryConfig struct {
 	URL      string
 }
 
+// BuilderConfig holds container image build configuration
+type BuilderConfig struct {
+	CacheBucket     string // GCS bucket for the persistent build cache, empty disables it
+	CacheMaxAgeDays int
+	RegistryCache   bool   // Push builder stages to the registry's cache repository and build from them
+	SBOMBucket      string // GCS bucket for image SBOMs, empty disables SBOM generation
+	SBOMFormat      string // spdx-json or cyclonedx-json
+	SBOMSigner      string // Service account email used to sign SBOM download URLs
+	UseKaniko       bool   // Build with Kaniko when the worker runs in Kubernetes
+	KanikoNamespace string // Namespace Kaniko build pods run in, empty for the worker's own
+	KanikoAccount   string // Service account Kaniko build pods push with
+}
+
 // ProvisionerConfig holds infrastructure provisioner configuration
 type ProvisionerConfig struct {
-	GCPProject      string
-	GCPRegion       string
-	PulumiBackend   string
-	DefaultNodeType string
-	DefaultNodes    int
+	Provider         string // Cloud provider backing provisioning and Cloud Run deploys
+	GCPProject       string
+	GCPRegion        string
+	PulumiBackend    string
+	DefaultNodeType  string
+	DefaultNodes     int
+	ProvisionTimeout time.Duration
+	EnableVPA        bool // Vertical Pod Autoscaling in recommendation mode on provisioned clusters
+
+	// Label keys every provisioned resource must carry; those the deployer does not set come
+	// from deployment tags
+	RequiredCostLabels []string
 }
 
 // DeployerConfig holds Kubernetes deployer configuration
@@ -77,12 +130,91 @@ type DeployerConfig struct {
 	DefaultPort     int
 	HelmTimeout     time.Duration
 	PodTimeout      time.Duration
+
+	EnableNetworkPolicies bool // Isolate deployment namespaces, requires an Istio ingress gateway
+
+	// Per-deployment resource maximums; empty or zero is unlimited. Admins can replace
+	// them at runtime through /api/v1/admin/resource-policy.
+	MaxCPULimit    string
+	MaxMemoryLimit string
+	MaxReplicas    int
+
+	// Chart version each platform addon is upgraded to by addon name, from
+	// deployer.platform_addons.<name>.chart_version; unpinned addons move to their newest release
+	AddonChartVersions map[string]string
+
+	// OCI registry the deployer's chart is published to and installed from, e.g.
+	// oci://us-central1-docker.pkg.dev/project/helm-charts; empty installs the local chart
+	ChartRegistryURL string
 }
 
 // WorkerConfig holds orchestrator worker configuration
 type WorkerConfig struct {
-	Concurrency  int
-	PollInterval time.Duration
+	Concurrency       int   // Concurrency to start with
+	MinConcurrency    int   // Lowest concurrency auto-tuning goes down to
+	MaxConcurrency    int   // Highest concurrency auto-tuning goes up to; at or below MinConcurrency disables tuning
+	HighWaterMark     int64 // Total queue depth above which concurrency is raised
+	LowWaterMark      int64 // Total queue depth below which concurrency is lowered
+	PollInterval      time.Duration
+	ReconcileInterval time.Duration // 0 disables periodic drift detection
+	WatchdogInterval  time.Duration // 0 disables stuck deployment recovery
+	DrainTimeout      time.Duration // How long in-flight jobs may finish after SIGTERM
+	HealthPort        string        // Port serving /healthz/drain and /metrics; empty disables it
+}
+
+// SecurityConfig holds image supply chain settings
+type SecurityConfig struct {
+	CosignKeyRef        string // Key built images are signed with, empty disables signing
+	EnforceSignedImages bool   // Refuse to deploy images without a valid signature
+	ScanImages          bool   // Scan built images for vulnerabilities with trivy
+	ScanFailOn          string // Lowest severity that fails a scan: CRITICAL, HIGH, MEDIUM or LOW
+
+	// Existing Cloud Armor policy attached when a deployment asks for the "default" policy
+	CloudArmorDefaultPolicy string
+
+	// Binary Authorization: provisioned clusters enforce the project policy, and signed images
+	// are attested for the attestor with the cosign key
+	BinaryAuthorization  bool
+	BinaryAuthPolicyFile string // Policy YAML applied to the project before provisioning, empty keeps the current policy
+	BinaryAuthAttestor   string // e.g. projects/p/attestors/built-by-app-deployer, empty disables attestations
+
+	// VPC Service Controls perimeter provisioned clusters' service accounts are added to
+	ServicePerimeter string // accessPolicies/<policy>/servicePerimeters/<name>, empty disables it
+}
+
+// SecretsConfig holds encryption settings for secret values stored in the database
+type SecretsConfig struct {
+	EncryptionKey string // Base64-encoded 32-byte AES-256 key, empty disables secret env vars
+}
+
+// BillingConfig locates the Cloud Billing export incurred costs are read from
+type BillingConfig struct {
+	BigQueryProject string  // Project that runs the queries and holds the dataset
+	BigQueryDataset string  // Dataset of the billing export, empty disables actual cost tracking
+	BigQueryTable   string  // Detailed (resource-level) export table, gcp_billing_export_resource_v1_<account>
+	PauseOverBudget bool    // Pause deployments once their projected spend exceeds their monthly budget
+	EgressAlertGB   float64 // Daily cluster egress in GB that triggers an egress alert, for deployments without their own; zero disables
+}
+
+// ApprovalConfig holds settings for deployments that require approval before rolling out
+type ApprovalConfig struct {
+	ExpiryHours int // Hours a pending approval can be acted on
+}
+
+// NotificationsConfig holds the webhook platform events are delivered to
+type NotificationsConfig struct {
+	WebhookURL    string // Events are POSTed here as JSON, empty disables notifications
+	WebhookSecret string // Signs each body as HMAC-SHA256 in X-Deployer-Signature, unsigned when empty
+}
+
+// LogRetentionConfig holds how long deployment logs are kept
+type LogRetentionConfig struct {
+	RetentionDays int // Logs older than this are deleted daily, -1 keeps them forever
+}
+
+// PolicyConfig holds the OPA policies deployment requests are admitted by
+type PolicyConfig struct {
+	PolicyDir string // Directory of .rego files evaluated with the opa CLI, empty disables admission policies
 }
 
 // Load loads configuration from environment variables and config files
@@ -109,9 +241,20 @@ func Load() (*Config, error) {
 	config := &Config{
 		Server: ServerConfig{
 			Port:         viper.GetString("server.port"),
+			GRPCPort:     viper.GetString("server.grpc_port"),
 			ReadTimeout:  viper.GetDuration("server.read_timeout"),
 			WriteTimeout: viper.GetDuration("server.write_timeout"),
 			LogLevel:     viper.GetString("server.log_level"),
+			RateLimits: RateLimitConfig{
+				Enabled:           viper.GetBool("server.rate_limits.enabled"),
+				ReadPerMinute:     viper.GetInt("server.rate_limits.read_per_minute"),
+				MutationPerMinute: viper.GetInt("server.rate_limits.mutation_per_minute"),
+				AdminPerMinute:    viper.GetInt("server.rate_limits.admin_per_minute"),
+			},
+			TrustedProxies: viper.GetStringSlice("server.trusted_proxies"),
+			ExecEnabled:    viper.GetBool("server.exec_enabled"),
+			ExecOrigins:    viper.GetStringSlice("server.exec_allowed_origins"),
+			AdminToken:     viper.GetString("server.admin_token"),
 		},
 		Database: DatabaseConfig{
 			Host:            viper.GetString("database.host"),
@@ -123,12 +266,20 @@ func Load() (*Config, error) {
 			MaxOpenConns:    viper.GetInt("database.max_open_conns"),
 			MaxIdleConns:    viper.GetInt("database.max_idle_conns"),
 			ConnMaxLifetime: viper.GetDuration("database.conn_max_lifetime"),
+			ReplicaHost:     viper.GetString("database.replica_host"),
+			ReplicaPort:     viper.GetInt("database.replica_port"),
+			MigrationsPath:  viper.GetString("database.migrations_path"),
 		},
 		Redis: RedisConfig{
 			URL:      viper.GetString("redis.url"),
 			Password: viper.GetString("redis.password"),
 			DB:       viper.GetInt("redis.db"),
 		},
+		Queue: QueueConfig{
+			Backend:           viper.GetString("queue.backend"),
+			PubSubProject:     viper.GetString("queue.pubsub_project"),
+			PubSubTopicPrefix: viper.GetString("queue.pubsub_topic_prefix"),
+		},
 		Platform: PlatformConfig{
 			DefaultCloud:  viper.GetString("platform.default_cloud"),
 			DefaultRegion: viper.GetString("platform.default_region"),
@@ -139,22 +290,93 @@ func Load() (*Config, error) {
 			Location: viper.GetString("registry.location"),
 			URL:      viper.GetString("registry.url"),
 		},
+		Builder: BuilderConfig{
+			CacheBucket:     viper.GetString("builder.cache_bucket"),
+			CacheMaxAgeDays: viper.GetInt("builder.cache_max_age_days"),
+			RegistryCache:   viper.GetBool("builder.registry_cache"),
+			SBOMBucket:      viper.GetString("builder.sbom_bucket"),
+			SBOMFormat:      viper.GetString("builder.sbom_format"),
+			SBOMSigner:      viper.GetString("builder.sbom_signer"),
+			UseKaniko:       viper.GetBool("builder.use_kaniko"),
+			KanikoNamespace: viper.GetString("builder.kaniko_namespace"),
+			KanikoAccount:   viper.GetString("builder.kaniko_service_account"),
+		},
 		Provisioner: ProvisionerConfig{
-			GCPProject:      viper.GetString("provisioner.gcp_project"),
-			GCPRegion:       viper.GetString("provisioner.gcp_region"),
-			PulumiBackend:   viper.GetString("provisioner.pulumi_backend"),
-			DefaultNodeType: viper.GetString("provisioner.default_node_type"),
-			DefaultNodes:    viper.GetInt("provisioner.default_nodes"),
+			Provider:         viper.GetString("provisioner.provider"),
+			GCPProject:       viper.GetString("provisioner.gcp_project"),
+			GCPRegion:        viper.GetString("provisioner.gcp_region"),
+			PulumiBackend:    viper.GetString("provisioner.pulumi_backend"),
+			DefaultNodeType:  viper.GetString("provisioner.default_node_type"),
+			DefaultNodes:     viper.GetInt("provisioner.default_nodes"),
+			ProvisionTimeout: viper.GetDuration("provisioner.provision_timeout"),
+			EnableVPA:        viper.GetBool("provisioner.enable_vpa"),
+
+			RequiredCostLabels: viper.GetStringSlice("provisioner.cost_allocation.required_labels"),
 		},
 		Deployer: DeployerConfig{
 			DefaultReplicas: viper.GetInt("deployer.default_replicas"),
 			DefaultPort:     viper.GetInt("deployer.default_port"),
 			HelmTimeout:     viper.GetDuration("deployer.helm_timeout"),
 			PodTimeout:      viper.GetDuration("deployer.pod_timeout"),
+
+			EnableNetworkPolicies: viper.GetBool("deployer.enable_network_policies"),
+
+			MaxCPULimit:    viper.GetString("deployer.max_cpu_limit"),
+			MaxMemoryLimit: viper.GetString("deployer.max_memory_limit"),
+			MaxReplicas:    viper.GetInt("deployer.max_replicas"),
+
+			AddonChartVersions: addonChartVersions(),
+
+			ChartRegistryURL: viper.GetString("deployer.chart_registry_url"),
 		},
 		Worker: WorkerConfig{
-			Concurrency:  viper.GetInt("worker.concurrency"),
-			PollInterval: viper.GetDuration("worker.poll_interval"),
+			Concurrency:       viper.GetInt("worker.concurrency"),
+			MinConcurrency:    viper.GetInt("worker.min_concurrency"),
+			MaxConcurrency:    viper.GetInt("worker.max_concurrency"),
+			HighWaterMark:     viper.GetInt64("worker.high_water_mark"),
+			LowWaterMark:      viper.GetInt64("worker.low_water_mark"),
+			PollInterval:      viper.GetDuration("worker.poll_interval"),
+			ReconcileInterval: viper.GetDuration("worker.reconcile_interval"),
+			WatchdogInterval:  viper.GetDuration("worker.watchdog_interval"),
+			DrainTimeout:      viper.GetDuration("worker.drain_timeout"),
+			HealthPort:        viper.GetString("worker.health_port"),
+		},
+		Security: SecurityConfig{
+			CosignKeyRef:        viper.GetString("security.cosign_key_ref"),
+			EnforceSignedImages: viper.GetBool("security.enforce_signed_images"),
+			ScanImages:          viper.GetBool("security.scan_images"),
+			ScanFailOn:          viper.GetString("security.scan_fail_on"),
+
+			CloudArmorDefaultPolicy: viper.GetString("security.cloud_armor_default_policy"),
+
+			BinaryAuthorization:  viper.GetBool("security.binary_authorization"),
+			BinaryAuthPolicyFile: viper.GetString("security.binauthz_policy_file"),
+			BinaryAuthAttestor:   viper.GetString("security.binauthz_attestor"),
+
+			ServicePerimeter: viper.GetString("security.vpc_service_perimeter"),
+		},
+		Secrets: SecretsConfig{
+			EncryptionKey: viper.GetString("secrets.encryption_key"),
+		},
+		Billing: BillingConfig{
+			BigQueryProject: viper.GetString("billing.bigquery_project"),
+			BigQueryDataset: viper.GetString("billing.bigquery_dataset"),
+			BigQueryTable:   viper.GetString("billing.bigquery_table"),
+			PauseOverBudget: viper.GetBool("billing.pause_over_budget"),
+			EgressAlertGB:   viper.GetFloat64("billing.egress_alert_gb"),
+		},
+		Approval: ApprovalConfig{
+			ExpiryHours: viper.GetInt("approval.expiry_hours"),
+		},
+		Notifications: NotificationsConfig{
+			WebhookURL:    viper.GetString("notifications.webhook_url"),
+			WebhookSecret: viper.GetString("notifications.webhook_secret"),
+		},
+		LogRetention: LogRetentionConfig{
+			RetentionDays: viper.GetInt("log_retention.retention_days"),
+		},
+		Policy: PolicyConfig{
+			PolicyDir: viper.GetString("policy.policy_dir"),
 		},
 	}
 
@@ -167,13 +389,34 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// addonChartVersions reads the chart versions pinned under deployer.platform_addons, skipping
+// addons without one
+func addonChartVersions() map[string]string {
+	versions := make(map[string]string)
+	for name := range viper.GetStringMap("deployer.platform_addons") {
+		if v := viper.GetString("deployer.platform_addons." + name + ".chart_version"); v != "" {
+			versions[name] = v
+		}
+	}
+	return versions
+}
+
 // setDefaults sets default configuration values
 func setDefaults() {
 	// Server defaults
 	viper.SetDefault("server.port", "3000")
+	viper.SetDefault("server.grpc_port", "50051")
 	viper.SetDefault("server.read_timeout", 10*time.Second)
 	viper.SetDefault("server.write_timeout", 10*time.Second)
 	viper.SetDefault("server.log_level", "info")
+	viper.SetDefault("server.rate_limits.enabled", true)
+	viper.SetDefault("server.rate_limits.read_per_minute", 100)
+	viper.SetDefault("server.rate_limits.mutation_per_minute", 20)
+	viper.SetDefault("server.rate_limits.admin_per_minute", 1000)
+	viper.SetDefault("server.trusted_proxies", []string{})
+	viper.SetDefault("server.exec_enabled", false)
+	viper.SetDefault("server.exec_allowed_origins", []string{})
+	viper.SetDefault("server.admin_token", "")
 
 	// Database defaults
 	viper.SetDefault("database.host", "localhost")
@@ -185,12 +428,20 @@ func setDefaults() {
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 5)
 	viper.SetDefault("database.conn_max_lifetime", 5*time.Minute)
+	viper.SetDefault("database.replica_host", "")
+	viper.SetDefault("database.replica_port", 5432)
+	viper.SetDefault("database.migrations_path", "db/migrations")
 
 	// Redis defaults
 	viper.SetDefault("redis.url", "localhost:6379")
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
 
+	// Queue defaults
+	viper.SetDefault("queue.backend", "redis")
+	viper.SetDefault("queue.pubsub_project", "")
+	viper.SetDefault("queue.pubsub_topic_prefix", "deployer")
+
 	// Platform defaults
 	viper.SetDefault("platform.default_cloud", "gcp")
 	viper.SetDefault("platform.default_region", "us-central1")
@@ -201,22 +452,85 @@ func setDefaults() {
 	viper.SetDefault("registry.location", "us-central1")
 	viper.SetDefault("registry.url", "")
 
+	// Builder defaults
+	viper.SetDefault("builder.cache_bucket", "")
+	viper.SetDefault("builder.cache_max_age_days", 7)
+	viper.SetDefault("builder.registry_cache", false)
+	viper.SetDefault("builder.sbom_bucket", "")
+	viper.SetDefault("builder.sbom_format", "spdx-json")
+	viper.SetDefault("builder.sbom_signer", "")
+	viper.SetDefault("builder.use_kaniko", false)
+	viper.SetDefault("builder.kaniko_namespace", "")
+	viper.SetDefault("builder.kaniko_service_account", "")
+
 	// Provisioner defaults
+	viper.SetDefault("provisioner.provider", "gcp")
 	viper.SetDefault("provisioner.gcp_project", "")
 	viper.SetDefault("provisioner.gcp_region", "us-central1")
 	viper.SetDefault("provisioner.pulumi_backend", "")
 	viper.SetDefault("provisioner.default_node_type", "e2-small")
 	viper.SetDefault("provisioner.default_nodes", 2)
+	viper.SetDefault("provisioner.provision_timeout", 30*time.Minute)
+	viper.SetDefault("provisioner.enable_vpa", false)
+	viper.SetDefault("provisioner.cost_allocation.required_labels", []string{"environment", "team", "cost-center", "deployment-id", "managed-by"})
 
 	// Deployer defaults
 	viper.SetDefault("deployer.default_replicas", 2)
 	viper.SetDefault("deployer.default_port", 8080)
 	viper.SetDefault("deployer.helm_timeout", 5*time.Minute)
 	viper.SetDefault("deployer.pod_timeout", 5*time.Minute)
+	viper.SetDefault("deployer.enable_network_policies", false)
+	viper.SetDefault("deployer.max_cpu_limit", "")
+	viper.SetDefault("deployer.max_memory_limit", "")
+	viper.SetDefault("deployer.max_replicas", 0)
+	viper.SetDefault("deployer.platform_addons", map[string]interface{}{})
+	viper.SetDefault("deployer.chart_registry_url", "")
 
 	// Worker defaults
 	viper.SetDefault("worker.concurrency", 3)
+	viper.SetDefault("worker.min_concurrency", 1)
+	viper.SetDefault("worker.max_concurrency", 10)
+	viper.SetDefault("worker.high_water_mark", 50)
+	viper.SetDefault("worker.low_water_mark", 5)
 	viper.SetDefault("worker.poll_interval", 5*time.Second)
+	viper.SetDefault("worker.reconcile_interval", 30*time.Minute)
+	viper.SetDefault("worker.watchdog_interval", 5*time.Minute)
+	viper.SetDefault("worker.drain_timeout", 30*time.Second)
+	viper.SetDefault("worker.health_port", "8081")
+
+	// Security defaults
+	viper.SetDefault("security.cosign_key_ref", "")
+	viper.SetDefault("security.enforce_signed_images", false)
+	viper.SetDefault("security.scan_images", false)
+	viper.SetDefault("security.scan_fail_on", "CRITICAL")
+	viper.SetDefault("security.cloud_armor_default_policy", "")
+	viper.SetDefault("security.binary_authorization", false)
+	viper.SetDefault("security.binauthz_policy_file", "")
+	viper.SetDefault("security.binauthz_attestor", "")
+	viper.SetDefault("security.vpc_service_perimeter", "")
+
+	// Secrets defaults
+	viper.SetDefault("secrets.encryption_key", "")
+
+	// Billing defaults
+	viper.SetDefault("billing.bigquery_project", "")
+	viper.SetDefault("billing.bigquery_dataset", "")
+	viper.SetDefault("billing.bigquery_table", "")
+	viper.SetDefault("billing.pause_over_budget", false)
+	viper.SetDefault("billing.egress_alert_gb", 0)
+
+	// Approval defaults
+	viper.SetDefault("approval.expiry_hours", 24)
+
+	// Notification defaults
+	viper.SetDefault("notifications.webhook_url", "")
+	viper.SetDefault("notifications.webhook_secret", "")
+
+	// Log retention defaults
+	viper.SetDefault("log_retention.retention_days", 30)
+
+	// Policy defaults
+	viper.SetDefault("policy.policy_dir", "")
 }
 
 // GetDatabaseDSN returns the PostgreSQL connection string
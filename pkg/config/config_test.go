@@ -29,6 +29,10 @@ func TestLoad(t *testing.T) {
 	if cfg.Database.Port != 5432 {
 		t.Errorf("Expected default database port 5432, got %d", cfg.Database.Port)
 	}
+
+	if cfg.Server.RateLimits.MutationPerMinute != 20 {
+		t.Errorf("Expected default mutation rate limit 20, got %d", cfg.Server.RateLimits.MutationPerMinute)
+	}
 }
 
 func TestLoadWithEnvOverride(t *testing.T) {
@@ -23,8 +23,54 @@ type Config struct {
 	ConnMaxLifetime time.Duration
 }
 
+// ReplicaConfig holds read replica configuration
+type ReplicaConfig struct {
+	Host            string
+	Port            int
+	User            string
+	Password        string
+	DBName          string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
 // New creates a new database connection
 func New(config Config) (*gorm.DB, error) {
+	db, err := open(config)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("host", config.Host).
+		Int("port", config.Port).
+		Str("database", config.DBName).
+		Msg("Database connected successfully")
+
+	return db, nil
+}
+
+// NewReadReplica creates a connection to a read replica. Nothing is written through it, so it
+// is not migrated.
+func NewReadReplica(cfg ReplicaConfig) (*gorm.DB, error) {
+	db, err := open(Config(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+
+	log.Info().
+		Str("host", cfg.Host).
+		Int("port", cfg.Port).
+		Str("database", cfg.DBName).
+		Msg("Read replica connected successfully")
+
+	return db, nil
+}
+
+// open connects to a database and configures its pool
+func open(config Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		config.Host,
@@ -64,12 +110,6 @@ func New(config Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Info().
-		Str("host", config.Host).
-		Int("port", config.Port).
-		Str("database", config.DBName).
-		Msg("Database connected successfully")
-
 	return db, nil
 }
 
@@ -2,12 +2,271 @@ package database
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
-// Migrate runs database migrations for all models
+// migrationLockID is the advisory lock that keeps processes starting together from running
+// migrations at the same time
+const migrationLockID = 72_516_804
+
+// migrationFilePattern matches migration files: {version}_{name}.up.sql and .down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a numbered pair of SQL files in a migrations directory
+type Migration struct {
+	Version  uint
+	Name     string
+	UpPath   string
+	DownPath string // Empty when the migration cannot be rolled back
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// schemaMigration is a row of the schema_migrations table, one per applied migration
+type schemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// TableName pins the table applied migrations are recorded in
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// LoadMigrations reads the migrations in dir, ordered by version
+func LoadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has files named %s and %s", version, m.Name, match[2])
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if match[3] == "up" {
+			m.UpPath = path
+		} else {
+			m.DownPath = path
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpPath == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// MigrateUp applies the migrations in migrationsPath that have not been applied yet. They run
+// in one transaction, so a failing migration leaves the schema as it was.
+func MigrateUp(db *gorm.DB, migrationsPath string) error {
+	migrations, err := LoadMigrations(migrationsPath)
+	if err != nil {
+		return err
+	}
+
+	applied := 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		done, err := lockMigrations(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+
+			if err := runMigrationFile(tx, m.UpPath); err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			if err := tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error; err != nil {
+				return fmt.Errorf("failed to record migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			log.Info().Uint("version", m.Version).Str("name", m.Name).Msg("Applied migration")
+			applied++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Int("applied", applied).Msg("Database migrations completed successfully")
+	return nil
+}
+
+// MigrateDown rolls back the last steps applied migrations, newest first, in one transaction
+func MigrateDown(db *gorm.DB, migrationsPath string, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1")
+	}
+
+	migrations, err := LoadMigrations(migrationsPath)
+	if err != nil {
+		return err
+	}
+
+	byVersion := make(map[uint]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockMigrations(tx); err != nil {
+			return err
+		}
+
+		var applied []schemaMigration
+		if err := tx.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+			return fmt.Errorf("failed to list applied migrations: %w", err)
+		}
+
+		for _, a := range applied {
+			m, ok := byVersion[a.Version]
+			if !ok || m.DownPath == "" {
+				return fmt.Errorf("migration %d_%s has no down file", a.Version, a.Name)
+			}
+
+			if err := runMigrationFile(tx, m.DownPath); err != nil {
+				return fmt.Errorf("failed to roll back migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			if err := tx.Delete(&schemaMigration{}, "version = ?", m.Version).Error; err != nil {
+				return fmt.Errorf("failed to unrecord migration %d_%s: %w", m.Version, m.Name, err)
+			}
+
+			log.Info().Uint("version", m.Version).Str("name", m.Name).Msg("Rolled back migration")
+		}
+
+		return nil
+	})
+}
+
+// GetMigrationStatus reports which of the migrations in migrationsPath have been applied
+func GetMigrationStatus(db *gorm.DB, migrationsPath string) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(migrationsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	done, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := done[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &a.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// MigrationVersion returns the version of the newest applied migration, or 0 when none is
+func MigrationVersion(db *gorm.DB) (uint, error) {
+	done, err := appliedMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+
+	var version uint
+	for v := range done {
+		version = max(version, v)
+	}
+
+	return version, nil
+}
+
+// lockMigrations takes the migration lock for the rest of tx and returns the applied migrations
+func lockMigrations(tx *gorm.DB) (map[uint]schemaMigration, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+
+	return appliedMigrations(tx)
+}
+
+// appliedMigrations returns the applied migrations by version, creating their table if needed
+func appliedMigrations(db *gorm.DB) (map[uint]schemaMigration, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+
+	done := make(map[uint]schemaMigration, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row
+	}
+
+	return done, nil
+}
+
+// runMigrationFile executes the statements in a migration file
+func runMigrationFile(tx *gorm.DB, path string) error {
+	sql, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return tx.Exec(string(sql)).Error
+}
+
+// Migrate creates or updates the tables of models with GORM's AutoMigrate. It cannot be rolled
+// back; servers and workers apply the SQL migrations with MigrateUp instead.
 func Migrate(db *gorm.DB, models ...interface{}) error {
 	log.Info().Msg("Running database migrations...")
 
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadMigrations tests that migration files are paired and ordered by version
+func TestLoadMigrations(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"000002_add_builds.up.sql",
+		"000001_initial_schema.up.sql",
+		"000001_initial_schema.down.sql",
+		"README.md",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		t.Fatalf("LoadMigrations failed: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+
+	if migrations[0].Version != 1 || migrations[0].Name != "initial_schema" {
+		t.Errorf("Expected migration 1 initial_schema first, got %d %s", migrations[0].Version, migrations[0].Name)
+	}
+
+	if migrations[0].DownPath == "" {
+		t.Error("Expected migration 1 to have a down file")
+	}
+
+	if migrations[1].Version != 2 || migrations[1].DownPath != "" {
+		t.Errorf("Expected migration 2 without a down file, got %+v", migrations[1])
+	}
+}
+
+// TestLoadMigrationsWithoutUp tests that a down file without its up file is rejected
+func TestLoadMigrationsWithoutUp(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "000003_drop_logs.down.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatalf("Failed to write migration: %v", err)
+	}
+
+	if _, err := LoadMigrations(dir); err == nil {
+		t.Error("Expected an error for a migration without an up file")
+	}
+}
+
+// TestRepositoryMigrations tests that the repository's migrations directory loads
+func TestRepositoryMigrations(t *testing.T) {
+	migrations, err := LoadMigrations(filepath.Join("..", "..", "db", "migrations"))
+	if err != nil {
+		t.Fatalf("LoadMigrations failed: %v", err)
+	}
+
+	for _, m := range migrations {
+		if m.DownPath == "" {
+			t.Errorf("Migration %d_%s has no down file", m.Version, m.Name)
+		}
+	}
+}
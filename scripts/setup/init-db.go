@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/alvesdmateus/app-deployer/internal/state"
 	"github.com/alvesdmateus/app-deployer/pkg/config"
 	"github.com/alvesdmateus/app-deployer/pkg/database"
 
@@ -44,13 +43,7 @@ func main() {
 	}
 
 	// Run migrations
-	models := []interface{}{
-		&state.Deployment{},
-		&state.Infrastructure{},
-		&state.Build{},
-	}
-
-	if err := database.Migrate(db, models...); err != nil {
+	if err := database.MigrateUp(db, cfg.Database.MigrationsPath); err != nil {
 		log.Fatal().Err(err).Msg("Failed to run migrations")
 	}
 
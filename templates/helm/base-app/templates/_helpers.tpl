@@ -21,6 +21,28 @@ Create a default fully qualified app name.
 {{- end }}
 {{- end }}
 
+{{/*
+Volume mounts for the ConfigMaps in extraConfigMaps
+*/}}
+{{- define "base-app.configMapVolumeMounts" -}}
+{{- range .Values.extraConfigMaps }}
+- name: configmap-{{ .name }}
+  mountPath: {{ .mountPath | default (printf "/etc/config/%s" .name) }}
+  readOnly: true
+{{- end }}
+{{- end }}
+
+{{/*
+Volumes for the ConfigMaps in extraConfigMaps
+*/}}
+{{- define "base-app.configMapVolumes" -}}
+{{- range .Values.extraConfigMaps }}
+- name: configmap-{{ .name }}
+  configMap:
+    name: {{ $.Release.Name }}-{{ .name }}
+{{- end }}
+{{- end }}
+
 {{/*
 Create chart name and version as used by the chart label.
 */}}